@@ -0,0 +1,159 @@
+// Package client is a Go client for the accounting API. The types and operation methods
+// in client_gen.go are generated from the OpenAPI document by "go run . openapi generate";
+// this file holds the transport they share.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client calls the API at BaseURL, e.g. "http://localhost:8080"
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// UserID is sent as the User-ID header that posting endpoints require
+	UserID int
+}
+
+func New(baseURL string, userID int) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), HTTPClient: http.DefaultClient, UserID: userID}
+}
+
+// RequestOption changes a single request, e.g. to add a header
+type RequestOption func(*http.Request)
+
+// WithIdempotencyKey makes a create safe to retry: repeating a request with the same key
+// returns the first response instead of posting twice
+func WithIdempotencyKey(key string) RequestOption {
+	return WithHeader("Idempotency-Key", key)
+}
+
+func WithHeader(name, value string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(name, value)
+	}
+}
+
+func ifMatch(version int) RequestOption {
+	return WithHeader("If-Match", strconv.Quote(strconv.Itoa(version)))
+}
+
+// APIError is an error response. Code is stable and safe to branch on, e.g.
+// "validation_failed", "not_found" or "version_conflict".
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Fields     []FieldError
+	Details    map[string]interface{}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// multipartFile is the body of upload operations
+type multipartFile struct {
+	field string
+	name  string
+	r     io.Reader
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, out interface{}, opts []RequestOption) error {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case multipartFile:
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		part, err := writer.CreateFormFile(b.field, b.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, b.r); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		reader, contentType = &buf, writer.FormDataContentType()
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader, contentType = bytes.NewReader(data), "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.UserID != 0 {
+		req.Header.Set("User-ID", strconv.Itoa(c.UserID))
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return decodeError(resp.StatusCode, data)
+	}
+
+	if raw, ok := out.(*[]byte); ok {
+		*raw = data
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// decodeError reads the error envelope, declared in client_gen.go with the other types
+func decodeError(status int, data []byte) error {
+	var envelope Envelope
+	apiErr := &APIError{StatusCode: status}
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Error.Code == "" {
+		apiErr.Code = "http_error"
+		apiErr.Message = strings.TrimSpace(string(data))
+		return apiErr
+	}
+	apiErr.Code = envelope.Error.Code
+	apiErr.Message = envelope.Error.Message
+	apiErr.Fields = envelope.Error.Fields
+	apiErr.Details = envelope.Error.Details
+	return apiErr
+}
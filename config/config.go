@@ -0,0 +1,814 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/mysecodgit/go_accounting/dialect"
+)
+
+// EnvPrefix is the prefix of every environment variable read by the config subsystem
+const EnvPrefix = "ACCOUNTING_"
+
+// DefaultConfigFile is read when no -config flag or ACCOUNTING_CONFIG variable is given
+const DefaultConfigFile = "config.json"
+
+type DatabaseConfig struct {
+	Driver          string `json:"driver"` // mysql, postgres or sqlite
+	DSN             string `json:"dsn"`
+	MaxOpenConns    int    `json:"max_open_conns"`
+	MaxIdleConns    int    `json:"max_idle_conns"`
+	ConnMaxLifetime string `json:"conn_max_lifetime"` // Go duration, e.g. "5m"
+	AutoMigrate     bool   `json:"auto_migrate"`      // Apply pending schema migrations on startup
+	ConnectTimeout  string `json:"connect_timeout"`   // How long to retry the database on startup, e.g. "1m"
+	// Statements taking at least this long are logged, e.g. "200ms"; "0" turns it off
+	SlowQueryThreshold string `json:"slow_query_threshold"`
+}
+
+type ServerConfig struct {
+	Address     string `json:"address"`
+	TLSCertFile string `json:"tls_cert_file"`
+	TLSKeyFile  string `json:"tls_key_file"`
+	// ShutdownTimeout bounds how long in-flight requests may take to finish on SIGTERM
+	ShutdownTimeout string `json:"shutdown_timeout"`
+}
+
+type CORSConfig struct {
+	AllowOrigins []string `json:"allow_origins"`
+}
+
+type UploadConfig struct {
+	Root string `json:"root"`
+}
+
+type LogConfig struct {
+	Level string `json:"level"` // debug, info, warn or error
+}
+
+type WebhookConfig struct {
+	Enabled          bool   `json:"enabled"`           // Run the outbox dispatcher in this process
+	DispatchInterval string `json:"dispatch_interval"` // How often the outbox is polled, e.g. "5s"
+	RequestTimeout   string `json:"request_timeout"`   // Per delivery attempt, e.g. "10s"
+	MaxAttempts      int    `json:"max_attempts"`      // Attempts before a delivery is dead-lettered
+}
+
+type SchedulerConfig struct {
+	Enabled      bool   `json:"enabled"`       // Run scheduled jobs in this process
+	PollInterval string `json:"poll_interval"` // How often due jobs and queued runs are looked for, e.g. "15s"
+	LockTimeout  string `json:"lock_timeout"`  // How long a job stays locked after its runner stops renewing it, e.g. "2m"
+}
+
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"` // Empty sends without authentication
+	Password string `json:"password"`
+	From     string `json:"from"` // Sender address, e.g. "Accounts <accounts@example.com>"
+}
+
+type SMSGatewayConfig struct {
+	URL     string `json:"url"`     // Messages are POSTed here as JSON {from, to, message}
+	Token   string `json:"token"`   // Sent as a bearer token when set
+	Sender  string `json:"sender"`  // Sender id or number
+	Timeout string `json:"timeout"` // Per request, e.g. "10s"
+}
+
+type NotificationConfig struct {
+	Enabled          bool   `json:"enabled"`           // Run the notification sender in this process
+	DispatchInterval string `json:"dispatch_interval"` // How often pending notifications are looked for, e.g. "10s"
+	MaxAttempts      int    `json:"max_attempts"`      // Attempts before a notification is marked failed
+	// EmailTransport is smtp, file or console and SMSTransport is http, file or console;
+	// file and console are stand-ins for local testing
+	EmailTransport string           `json:"email_transport"`
+	SMSTransport   string           `json:"sms_transport"`
+	OutputDir      string           `json:"output_dir"` // Where the file transport writes messages
+	SMTP           SMTPConfig       `json:"smtp"`
+	SMSGateway     SMSGatewayConfig `json:"sms_gateway"`
+}
+
+// PortalConfig is the tenant portal, a separate read-only API on its own address where
+// tenants sign in with a code sent to their phone
+type PortalConfig struct {
+	Enabled        bool     `json:"enabled"`         // Serve the portal from this process
+	Address        string   `json:"address"`         // Must differ from server.address, e.g. ":8084"
+	AllowOrigins   []string `json:"allow_origins"`   // CORS origins of the portal front end
+	CodeTTL        string   `json:"code_ttl"`        // How long a sign-in code can be used, e.g. "10m"
+	CodeAttempts   int      `json:"code_attempts"`   // Sign-in attempts, right or wrong, before a code stops working
+	ResendInterval string   `json:"resend_interval"` // Minimum wait between codes for a phone number, e.g. "1m"
+	SessionTTL     string   `json:"session_ttl"`     // How long a tenant stays signed in, e.g. "720h"
+}
+
+// PaymentGatewayConfig is online payment of invoices through a payment gateway. The provider
+// posts payment results to /api/payment-gateways/<provider>/webhook, signed with the secret.
+type PaymentGatewayConfig struct {
+	Provider           string `json:"provider"`            // fake, or empty when online payment is off; fake is for local testing
+	WebhookSecret      string `json:"webhook_secret"`      // Shared with the provider to sign webhooks
+	SignatureTolerance string `json:"signature_tolerance"` // How old a signed webhook may be, e.g. "5m"
+}
+
+// Config is the effective application configuration: defaults, overridden by the
+// config file, overridden by environment variables
+type Config struct {
+	Database       DatabaseConfig       `json:"database"`
+	Server         ServerConfig         `json:"server"`
+	CORS           CORSConfig           `json:"cors"`
+	Uploads        UploadConfig         `json:"uploads"`
+	Log            LogConfig            `json:"log"`
+	Webhooks       WebhookConfig        `json:"webhooks"`
+	Scheduler      SchedulerConfig      `json:"scheduler"`
+	Notifications  NotificationConfig   `json:"notifications"`
+	Portal         PortalConfig         `json:"portal"`
+	PaymentGateway PaymentGatewayConfig `json:"payment_gateway"`
+	Features       map[string]bool      `json:"features"`
+}
+
+// App holds the configuration loaded at startup
+var App *Config
+
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+var featureNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Default returns the configuration used when nothing is overridden
+func Default() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			Driver:             "mysql",
+			DSN:                "root:@tcp(127.0.0.1:3306)/go_accounting",
+			MaxOpenConns:       25,
+			MaxIdleConns:       5,
+			ConnMaxLifetime:    "5m",
+			ConnectTimeout:     "1m",
+			SlowQueryThreshold: "200ms",
+		},
+		Server: ServerConfig{
+			Address:         ":8083",
+			ShutdownTimeout: "30s",
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{"*"},
+		},
+		Uploads: UploadConfig{
+			Root: "uploads",
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Webhooks: WebhookConfig{
+			Enabled:          true,
+			DispatchInterval: "5s",
+			RequestTimeout:   "10s",
+			MaxAttempts:      10,
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:      true,
+			PollInterval: "15s",
+			LockTimeout:  "2m",
+		},
+		Notifications: NotificationConfig{
+			Enabled:          true,
+			DispatchInterval: "10s",
+			MaxAttempts:      5,
+			EmailTransport:   "console",
+			SMSTransport:     "console",
+			OutputDir:        "notifications",
+			SMTP: SMTPConfig{
+				Port: 587,
+			},
+			SMSGateway: SMSGatewayConfig{
+				Timeout: "10s",
+			},
+		},
+		Portal: PortalConfig{
+			Address:        ":8084",
+			AllowOrigins:   []string{"*"},
+			CodeTTL:        "10m",
+			CodeAttempts:   5,
+			ResendInterval: "1m",
+			SessionTTL:     "720h",
+		},
+		PaymentGateway: PaymentGatewayConfig{
+			SignatureTolerance: "5m",
+		},
+		Features: map[string]bool{},
+	}
+}
+
+// Load builds the configuration from defaults, the given JSON file (optional when empty or
+// missing and it is the default file) and environment variables, then validates it
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if !(errors.Is(err, os.ErrNotExist) && path == DefaultConfigFile) {
+				return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+			}
+		} else {
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(cfg); err != nil {
+				return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+			}
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadConfig loads the configuration into App, printing the effective redacted config.
+// The file comes from the -config flag, then ACCOUNTING_CONFIG, then config.json.
+func LoadConfig() {
+	path := DefaultConfigFile
+	if envPath, ok := os.LookupEnv(EnvPrefix + "CONFIG"); ok && envPath != "" {
+		path = envPath
+	}
+	configFlag := flag.String("config", path, "path to the JSON configuration file")
+	flag.Parse()
+
+	cfg, err := Load(*configFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	App = cfg
+
+	effective, err := json.MarshalIndent(cfg.Redacted(), "", "  ")
+	if err == nil {
+		fmt.Printf("Effective configuration:\n%s\n", effective)
+	}
+}
+
+// applyEnv overrides configuration values from ACCOUNTING_* environment variables
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	stringVars := map[string]*string{
+		"DB_DRIVER":                      &c.Database.Driver,
+		"DB_DSN":                         &c.Database.DSN,
+		"DB_CONN_MAX_LIFETIME":           &c.Database.ConnMaxLifetime,
+		"DB_CONNECT_TIMEOUT":             &c.Database.ConnectTimeout,
+		"DB_SLOW_QUERY_THRESHOLD":        &c.Database.SlowQueryThreshold,
+		"LISTEN_ADDR":                    &c.Server.Address,
+		"TLS_CERT_FILE":                  &c.Server.TLSCertFile,
+		"TLS_KEY_FILE":                   &c.Server.TLSKeyFile,
+		"SHUTDOWN_TIMEOUT":               &c.Server.ShutdownTimeout,
+		"UPLOAD_ROOT":                    &c.Uploads.Root,
+		"LOG_LEVEL":                      &c.Log.Level,
+		"WEBHOOK_DISPATCH_INTERVAL":      &c.Webhooks.DispatchInterval,
+		"WEBHOOK_REQUEST_TIMEOUT":        &c.Webhooks.RequestTimeout,
+		"SCHEDULER_POLL_INTERVAL":        &c.Scheduler.PollInterval,
+		"SCHEDULER_LOCK_TIMEOUT":         &c.Scheduler.LockTimeout,
+		"NOTIFICATION_DISPATCH_INTERVAL": &c.Notifications.DispatchInterval,
+		"NOTIFICATION_EMAIL_TRANSPORT":   &c.Notifications.EmailTransport,
+		"NOTIFICATION_SMS_TRANSPORT":     &c.Notifications.SMSTransport,
+		"NOTIFICATION_OUTPUT_DIR":        &c.Notifications.OutputDir,
+		"SMTP_HOST":                      &c.Notifications.SMTP.Host,
+		"SMTP_USERNAME":                  &c.Notifications.SMTP.Username,
+		"SMTP_PASSWORD":                  &c.Notifications.SMTP.Password,
+		"SMTP_FROM":                      &c.Notifications.SMTP.From,
+		"SMS_GATEWAY_URL":                &c.Notifications.SMSGateway.URL,
+		"SMS_GATEWAY_TOKEN":              &c.Notifications.SMSGateway.Token,
+		"SMS_GATEWAY_SENDER":             &c.Notifications.SMSGateway.Sender,
+		"SMS_GATEWAY_TIMEOUT":            &c.Notifications.SMSGateway.Timeout,
+		"PORTAL_LISTEN_ADDR":             &c.Portal.Address,
+		"PORTAL_CODE_TTL":                &c.Portal.CodeTTL,
+		"PORTAL_RESEND_INTERVAL":         &c.Portal.ResendInterval,
+		"PORTAL_SESSION_TTL":             &c.Portal.SessionTTL,
+		"PAYMENT_PROVIDER":               &c.PaymentGateway.Provider,
+		"PAYMENT_WEBHOOK_SECRET":         &c.PaymentGateway.WebhookSecret,
+		"PAYMENT_SIGNATURE_TOLERANCE":    &c.PaymentGateway.SignatureTolerance,
+	}
+	for name, target := range stringVars {
+		if value, ok := lookup(EnvPrefix + name); ok {
+			*target = value
+		}
+	}
+
+	intVars := map[string]*int{
+		"DB_MAX_OPEN_CONNS":         &c.Database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS":         &c.Database.MaxIdleConns,
+		"WEBHOOK_MAX_ATTEMPTS":      &c.Webhooks.MaxAttempts,
+		"NOTIFICATION_MAX_ATTEMPTS": &c.Notifications.MaxAttempts,
+		"SMTP_PORT":                 &c.Notifications.SMTP.Port,
+		"PORTAL_CODE_ATTEMPTS":      &c.Portal.CodeAttempts,
+	}
+	for name, target := range intVars {
+		if value, ok := lookup(EnvPrefix + name); ok {
+			parsed, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("%s%s must be an integer", EnvPrefix, name)
+			}
+			*target = parsed
+		}
+	}
+
+	boolVars := map[string]*bool{
+		"DB_AUTO_MIGRATE":       &c.Database.AutoMigrate,
+		"WEBHOOKS_ENABLED":      &c.Webhooks.Enabled,
+		"SCHEDULER_ENABLED":     &c.Scheduler.Enabled,
+		"NOTIFICATIONS_ENABLED": &c.Notifications.Enabled,
+		"PORTAL_ENABLED":        &c.Portal.Enabled,
+	}
+	for name, target := range boolVars {
+		if value, ok := lookup(EnvPrefix + name); ok {
+			parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("%s%s must be true or false", EnvPrefix, name)
+			}
+			*target = parsed
+		}
+	}
+
+	// Comma separated list, e.g. "https://app.example.com,https://admin.example.com"
+	if value, ok := lookup(EnvPrefix + "CORS_ORIGINS"); ok {
+		c.CORS.AllowOrigins = splitList(value)
+	}
+	if value, ok := lookup(EnvPrefix + "PORTAL_CORS_ORIGINS"); ok {
+		c.Portal.AllowOrigins = splitList(value)
+	}
+
+	// Comma separated flags, "name" or "name=true" enables and "name=false" disables
+	if value, ok := lookup(EnvPrefix + "FEATURES"); ok {
+		if c.Features == nil {
+			c.Features = map[string]bool{}
+		}
+		for _, item := range splitList(value) {
+			name, rawValue, hasValue := strings.Cut(item, "=")
+			enabled := true
+			if hasValue {
+				parsed, err := strconv.ParseBool(strings.TrimSpace(rawValue))
+				if err != nil {
+					return fmt.Errorf("%sFEATURES: invalid value for feature '%s'", EnvPrefix, name)
+				}
+				enabled = parsed
+			}
+			c.Features[strings.TrimSpace(name)] = enabled
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the configuration and returns all problems at once
+func (c *Config) Validate() error {
+	problems := []string{}
+
+	driver, err := dialect.Parse(c.Database.Driver)
+	if err != nil {
+		problems = append(problems, "database.driver: "+err.Error())
+	}
+	if strings.TrimSpace(c.Database.DSN) == "" {
+		problems = append(problems, "database.dsn is required")
+	} else if err == nil {
+		if err := validateDSN(driver, c.Database.DSN); err != nil {
+			problems = append(problems, fmt.Sprintf("database.dsn is invalid: %v", err))
+		}
+	}
+	if c.Database.MaxOpenConns < 0 {
+		problems = append(problems, "database.max_open_conns must not be negative")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		problems = append(problems, "database.max_idle_conns must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "database.max_idle_conns must not exceed database.max_open_conns")
+	}
+	if c.Database.ConnMaxLifetime != "" {
+		if d, err := time.ParseDuration(c.Database.ConnMaxLifetime); err != nil || d < 0 {
+			problems = append(problems, "database.conn_max_lifetime must be a duration such as 5m")
+		}
+	}
+	if c.Database.ConnectTimeout != "" {
+		if d, err := time.ParseDuration(c.Database.ConnectTimeout); err != nil || d < 0 {
+			problems = append(problems, "database.connect_timeout must be a duration such as 1m")
+		}
+	}
+	if c.Database.SlowQueryThreshold != "" {
+		if d, err := time.ParseDuration(c.Database.SlowQueryThreshold); err != nil || d < 0 {
+			problems = append(problems, "database.slow_query_threshold must be a duration such as 200ms")
+		}
+	}
+
+	if strings.TrimSpace(c.Server.Address) == "" {
+		problems = append(problems, "server.address is required")
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		problems = append(problems, "server.tls_cert_file and server.tls_key_file must be set together")
+	}
+	if c.Server.ShutdownTimeout != "" {
+		if d, err := time.ParseDuration(c.Server.ShutdownTimeout); err != nil || d < 0 {
+			problems = append(problems, "server.shutdown_timeout must be a duration such as 30s")
+		}
+	}
+	for _, file := range []string{c.Server.TLSCertFile, c.Server.TLSKeyFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			problems = append(problems, fmt.Sprintf("TLS file %s is not readable: %v", file, err))
+		}
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		problems = append(problems, "cors.allow_origins must contain at least one origin")
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		if origin == "*" {
+			if len(c.CORS.AllowOrigins) > 1 {
+				problems = append(problems, "cors.allow_origins cannot combine * with other origins")
+			}
+			continue
+		}
+		if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			problems = append(problems, fmt.Sprintf("cors origin '%s' must start with http:// or https://", origin))
+		}
+	}
+
+	if strings.TrimSpace(c.Uploads.Root) == "" {
+		problems = append(problems, "uploads.root is required")
+	}
+
+	validLevel := false
+	for _, level := range logLevels {
+		if strings.ToLower(c.Log.Level) == level {
+			validLevel = true
+			break
+		}
+	}
+	if !validLevel {
+		problems = append(problems, fmt.Sprintf("log.level must be one of %s", strings.Join(logLevels, ", ")))
+	}
+
+	if d, err := time.ParseDuration(c.Webhooks.DispatchInterval); err != nil || d <= 0 {
+		problems = append(problems, "webhooks.dispatch_interval must be a positive duration such as 5s")
+	}
+	if d, err := time.ParseDuration(c.Webhooks.RequestTimeout); err != nil || d <= 0 {
+		problems = append(problems, "webhooks.request_timeout must be a positive duration such as 10s")
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		problems = append(problems, "webhooks.max_attempts must be at least 1")
+	}
+
+	if d, err := time.ParseDuration(c.Scheduler.PollInterval); err != nil || d <= 0 {
+		problems = append(problems, "scheduler.poll_interval must be a positive duration such as 15s")
+	}
+	// Runners renew their locks every third of the timeout; shorter than that risks a job
+	// being taken over while it still runs
+	if d, err := time.ParseDuration(c.Scheduler.LockTimeout); err != nil || d < 30*time.Second {
+		problems = append(problems, "scheduler.lock_timeout must be a duration of at least 30s")
+	}
+
+	problems = append(problems, c.Notifications.validate()...)
+	problems = append(problems, c.Portal.validate(c.Server.Address)...)
+	problems = append(problems, c.PaymentGateway.validate()...)
+
+	for name := range c.Features {
+		if !featureNamePattern.MatchString(name) {
+			problems = append(problems, fmt.Sprintf("feature name '%s' must be lower_snake_case", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ConnMaxLifetimeDuration returns the parsed connection lifetime (0 means unlimited)
+func (d DatabaseConfig) ConnMaxLifetimeDuration() time.Duration {
+	lifetime, err := time.ParseDuration(d.ConnMaxLifetime)
+	if err != nil {
+		return 0
+	}
+	return lifetime
+}
+
+// ConnectTimeoutDuration returns how long to keep retrying the database on startup
+// (0 means a single attempt)
+func (d DatabaseConfig) ConnectTimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(d.ConnectTimeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+// SlowQueryThresholdDuration returns the duration from which statements are logged
+// (0 means never)
+func (d DatabaseConfig) SlowQueryThresholdDuration() time.Duration {
+	threshold, err := time.ParseDuration(d.SlowQueryThreshold)
+	if err != nil {
+		return 0
+	}
+	return threshold
+}
+
+// ShutdownTimeoutDuration returns how long to wait for in-flight requests on shutdown
+func (s ServerConfig) ShutdownTimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(s.ShutdownTimeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+// DispatchIntervalDuration returns how often the webhook dispatcher polls the outbox
+func (w WebhookConfig) DispatchIntervalDuration() time.Duration {
+	interval, err := time.ParseDuration(w.DispatchInterval)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// RequestTimeoutDuration returns the timeout of a single webhook delivery attempt
+func (w WebhookConfig) RequestTimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(w.RequestTimeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+// DispatchIntervalDuration returns how often the notification sender looks for work
+func (n NotificationConfig) DispatchIntervalDuration() time.Duration {
+	interval, err := time.ParseDuration(n.DispatchInterval)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// TimeoutDuration returns the timeout of a single SMS gateway request
+func (s SMSGatewayConfig) TimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(s.Timeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+// validate checks the notification settings, including those of the selected transports only
+func (n NotificationConfig) validate() []string {
+	problems := []string{}
+
+	if d, err := time.ParseDuration(n.DispatchInterval); err != nil || d <= 0 {
+		problems = append(problems, "notifications.dispatch_interval must be a positive duration such as 10s")
+	}
+	if n.MaxAttempts < 1 {
+		problems = append(problems, "notifications.max_attempts must be at least 1")
+	}
+
+	switch n.EmailTransport {
+	case "smtp":
+		if strings.TrimSpace(n.SMTP.Host) == "" {
+			problems = append(problems, "notifications.smtp.host is required for the smtp transport")
+		}
+		if n.SMTP.Port < 1 || n.SMTP.Port > 65535 {
+			problems = append(problems, "notifications.smtp.port must be between 1 and 65535")
+		}
+		if strings.TrimSpace(n.SMTP.From) == "" {
+			problems = append(problems, "notifications.smtp.from is required for the smtp transport")
+		}
+	case "file", "console":
+	default:
+		problems = append(problems, "notifications.email_transport must be smtp, file or console")
+	}
+
+	switch n.SMSTransport {
+	case "http":
+		if parsed, err := url.Parse(n.SMSGateway.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			problems = append(problems, "notifications.sms_gateway.url must be an absolute http or https URL for the http transport")
+		}
+		if d, err := time.ParseDuration(n.SMSGateway.Timeout); err != nil || d <= 0 {
+			problems = append(problems, "notifications.sms_gateway.timeout must be a positive duration such as 10s")
+		}
+	case "file", "console":
+	default:
+		problems = append(problems, "notifications.sms_transport must be http, file or console")
+	}
+
+	if (n.EmailTransport == "file" || n.SMSTransport == "file") && strings.TrimSpace(n.OutputDir) == "" {
+		problems = append(problems, "notifications.output_dir is required for the file transport")
+	}
+
+	return problems
+}
+
+func (p PortalConfig) validate(serverAddress string) []string {
+	problems := []string{}
+
+	if p.Enabled {
+		if strings.TrimSpace(p.Address) == "" {
+			problems = append(problems, "portal.address is required")
+		} else if p.Address == serverAddress {
+			problems = append(problems, "portal.address must differ from server.address")
+		}
+	}
+	if d, err := time.ParseDuration(p.CodeTTL); err != nil || d <= 0 {
+		problems = append(problems, "portal.code_ttl must be a positive duration such as 10m")
+	}
+	if p.CodeAttempts < 1 {
+		problems = append(problems, "portal.code_attempts must be at least 1")
+	}
+	if d, err := time.ParseDuration(p.ResendInterval); err != nil || d < 0 {
+		problems = append(problems, "portal.resend_interval must be a duration such as 1m")
+	}
+	if d, err := time.ParseDuration(p.SessionTTL); err != nil || d <= 0 {
+		problems = append(problems, "portal.session_ttl must be a positive duration such as 720h")
+	}
+
+	return problems
+}
+
+func (p PaymentGatewayConfig) validate() []string {
+	problems := []string{}
+
+	switch p.Provider {
+	case "":
+	case "fake":
+		if strings.TrimSpace(p.WebhookSecret) == "" {
+			problems = append(problems, "payment_gateway.webhook_secret is required when a provider is set")
+		}
+	default:
+		problems = append(problems, "payment_gateway.provider must be fake or empty")
+	}
+	if d, err := time.ParseDuration(p.SignatureTolerance); err != nil || d <= 0 {
+		problems = append(problems, "payment_gateway.signature_tolerance must be a positive duration such as 5m")
+	}
+
+	return problems
+}
+
+// TLSEnabled reports whether the server should listen with TLS
+func (s ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != "" && s.TLSKeyFile != ""
+}
+
+// Redacted returns a copy that is safe to print, with the database password, the
+// notification credentials and the payment gateway secret masked
+func (c *Config) Redacted() Config {
+	redacted := *c
+	redacted.Database.DSN = redactDSN(c.Database.Driver, c.Database.DSN)
+	if redacted.Notifications.SMTP.Password != "" {
+		redacted.Notifications.SMTP.Password = "*****"
+	}
+	if redacted.Notifications.SMSGateway.Token != "" {
+		redacted.Notifications.SMSGateway.Token = "*****"
+	}
+	if redacted.PaymentGateway.WebhookSecret != "" {
+		redacted.PaymentGateway.WebhookSecret = "*****"
+	}
+	redacted.CORS.AllowOrigins = append([]string{}, c.CORS.AllowOrigins...)
+	redacted.Portal.AllowOrigins = append([]string{}, c.Portal.AllowOrigins...)
+	redacted.Features = make(map[string]bool, len(c.Features))
+	for name, enabled := range c.Features {
+		redacted.Features[name] = enabled
+	}
+	return redacted
+}
+
+func validateDSN(driver dialect.Name, dsn string) error {
+	switch driver {
+	case dialect.Postgres:
+		_, err := pgx.ParseConfig(dsn)
+		return err
+	case dialect.SQLite:
+		return nil
+	default:
+		_, err := mysql.ParseDSN(dsn)
+		return err
+	}
+}
+
+var postgresPasswordPattern = regexp.MustCompile(`password=\S+`)
+
+func redactDSN(driverName string, dsn string) string {
+	driver, err := dialect.Parse(driverName)
+	if err != nil {
+		return "<invalid>"
+	}
+
+	switch driver {
+	case dialect.Postgres:
+		if parsed, err := url.Parse(dsn); err == nil && parsed.Scheme != "" {
+			if _, hasPassword := parsed.User.Password(); hasPassword {
+				parsed.User = url.UserPassword(parsed.User.Username(), "*****")
+			}
+			return parsed.String()
+		}
+		return postgresPasswordPattern.ReplaceAllString(dsn, "password=*****")
+	case dialect.SQLite:
+		return dsn
+	default:
+		parsed, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return "<invalid>"
+		}
+		if parsed.Passwd != "" {
+			parsed.Passwd = "*****"
+		}
+		return parsed.FormatDSN()
+	}
+}
+
+// FeatureEnabled reports whether a feature flag is switched on
+func FeatureEnabled(name string) bool {
+	if App == nil {
+		return false
+	}
+	return App.Features[name]
+}
+
+// UploadRoot returns the configured root directory for uploaded files
+func UploadRoot() string {
+	if App == nil || App.Uploads.Root == "" {
+		return Default().Uploads.Root
+	}
+	return filepath.Clean(App.Uploads.Root)
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// PollIntervalDuration returns how often the job scheduler looks for work
+func (s SchedulerConfig) PollIntervalDuration() time.Duration {
+	interval, err := time.ParseDuration(s.PollInterval)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// LockTimeoutDuration returns how long a running job's lock lasts without renewal
+func (s SchedulerConfig) LockTimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(s.LockTimeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+// CodeTTLDuration returns how long a portal sign-in code can be used
+func (p PortalConfig) CodeTTLDuration() time.Duration {
+	ttl, err := time.ParseDuration(p.CodeTTL)
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+// ResendIntervalDuration returns the minimum wait between sign-in codes for a phone number
+func (p PortalConfig) ResendIntervalDuration() time.Duration {
+	interval, err := time.ParseDuration(p.ResendInterval)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// SessionTTLDuration returns how long a portal session lasts
+func (p PortalConfig) SessionTTLDuration() time.Duration {
+	ttl, err := time.ParseDuration(p.SessionTTL)
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+// SignatureToleranceDuration returns how old a signed payment gateway webhook may be
+func (p PaymentGatewayConfig) SignatureToleranceDuration() time.Duration {
+	tolerance, err := time.ParseDuration(p.SignatureTolerance)
+	if err != nil {
+		return 0
+	}
+	return tolerance
+}
@@ -1,27 +1,77 @@
 package config
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"log"
+	"strings"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/mysecodgit/go_accounting/dialect"
+	"github.com/mysecodgit/go_accounting/src/logging"
 )
 
 var DB *sql.DB
 
 func ConnectDatabase() {
-	dsn := "root:@tcp(127.0.0.1:3306)/go_accounting"
-	var err error
-	DB, err = sql.Open("mysql", dsn)
+	if App == nil {
+		App = Default()
+	}
+
+	driver, err := dialect.Parse(App.Database.Driver)
 	if err != nil {
-		log.Fatal("Error connecting to database: ", err)
+		log.Fatal(err)
 	}
+	dialect.Current = driver
+	dialect.SlowQueryThreshold = App.Database.SlowQueryThresholdDuration()
+	dialect.SlowQuery = logSlowQuery
 
-	err = DB.Ping()
+	dsn := driver.PrepareDSN(App.Database.DSN)
+	DB, err = sql.Open(driver.DriverName(), dsn)
 	if err != nil {
+		log.Fatal("Error connecting to database: ", err)
+	}
+
+	DB.SetMaxOpenConns(App.Database.MaxOpenConns)
+	DB.SetMaxIdleConns(App.Database.MaxIdleConns)
+	DB.SetConnMaxLifetime(App.Database.ConnMaxLifetimeDuration())
+
+	if err := waitForDatabase(App.Database.ConnectTimeoutDuration()); err != nil {
 		log.Fatal("Cannot reach database: ", err)
 	}
 
-	fmt.Println("Database connected")
+	log.Println("Database connected")
+}
+
+// waitForDatabase pings the database until it answers or the timeout passes, backing off
+// between attempts so the server can start before the database during a deploy
+func waitForDatabase(timeout time.Duration) error {
+	const maxBackoff = 10 * time.Second
+
+	deadline := time.Now().Add(timeout)
+	backoff := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := DB.Ping()
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return err
+		}
+		log.Printf("Database not reachable (attempt %d), retrying in %s: %v", attempt, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// logSlowQuery logs a statement that took longer than the configured threshold, with the
+// request's logger when the statement was given its context
+func logSlowQuery(ctx context.Context, query string, elapsed time.Duration) {
+	logging.FromContext(ctx).Warn("slow query",
+		"query", strings.Join(strings.Fields(query), " "),
+		"duration_ms", float64(elapsed.Microseconds())/1000,
+	)
 }
@@ -0,0 +1,188 @@
+// Package dialect lets the MySQL-flavoured SQL used by the repositories run on PostgreSQL
+// and SQLite.
+//
+// Repositories keep writing `?` placeholders, backtick identifiers, `<=>`, NOW() and
+// Result.LastInsertId. The drivers registered here rewrite each statement for their
+// database and normalise date values to the strings the MySQL driver returns. MySQL
+// statements go through the same wrapper unchanged, so slow ones are reported on every
+// database alike.
+package dialect
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Name string
+
+const (
+	MySQL    Name = "mysql"
+	Postgres Name = "postgres"
+	SQLite   Name = "sqlite"
+)
+
+// Current is the dialect of the configured database, set when the database is connected
+var Current = MySQL
+
+// Parse validates a configured driver name
+func Parse(name string) (Name, error) {
+	switch Name(strings.ToLower(strings.TrimSpace(name))) {
+	case "", MySQL:
+		return MySQL, nil
+	case Postgres, "postgresql", "pgx":
+		return Postgres, nil
+	case SQLite, "sqlite3":
+		return SQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported database driver '%s' (mysql, postgres or sqlite)", name)
+	}
+}
+
+// DriverName returns the database/sql driver registered for a dialect
+func (d Name) DriverName() string {
+	switch d {
+	case Postgres:
+		return postgresDriverName
+	case SQLite:
+		return sqliteDriverName
+	default:
+		return mysqlDriverName
+	}
+}
+
+// DateOf returns an expression for the calendar date of a date or datetime column.
+// DATE() exists in all three databases; this keeps call sites explicit about intent.
+func (d Name) DateOf(column string) string {
+	return "DATE(" + column + ")"
+}
+
+// Upsert returns an INSERT that updates updateColumns when a row with the same
+// conflictColumns already exists
+func (d Name) Upsert(table string, columns []string, conflictColumns []string, updateColumns []string) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), placeholders)
+
+	assignments := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		if d == MySQL {
+			assignments = append(assignments, fmt.Sprintf("%s = VALUES(%s)", column, column))
+		} else {
+			assignments = append(assignments, fmt.Sprintf("%s = excluded.%s", column, column))
+		}
+	}
+
+	if d == MySQL {
+		if len(assignments) == 0 {
+			return strings.Replace(query, "INSERT INTO", "INSERT IGNORE INTO", 1)
+		}
+		return query + " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+	}
+
+	conflict := " ON CONFLICT (" + strings.Join(conflictColumns, ", ") + ")"
+	if len(assignments) == 0 {
+		return query + conflict + " DO NOTHING"
+	}
+	return query + conflict + " DO UPDATE SET " + strings.Join(assignments, ", ")
+}
+
+// Rewrite translates a MySQL-flavoured statement for the dialect, leaving string
+// literals and comments untouched
+func (d Name) Rewrite(query string) string {
+	if d == MySQL {
+		return query
+	}
+
+	var out strings.Builder
+	out.Grow(len(query) + 16)
+	placeholder := 0
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := skipQuoted(query, i, c)
+			out.WriteString(query[i:end])
+			i = end - 1
+		case c == '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				out.WriteString(query[i:])
+				i = len(query)
+				continue
+			}
+			identifier := query[i+1 : i+1+end]
+			if d == Postgres {
+				// Unquoted identifiers fold to lower case in PostgreSQL and the schema is lower case
+				out.WriteString(`"` + strings.ToLower(identifier) + `"`)
+			} else {
+				out.WriteString(`"` + identifier + `"`)
+			}
+			i += end + 1
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			out.WriteString(query[i : i+end])
+			i += end - 1
+		case c == '?':
+			placeholder++
+			if d == Postgres {
+				fmt.Fprintf(&out, "$%d", placeholder)
+			} else {
+				out.WriteByte('?')
+			}
+		case c == '<' && strings.HasPrefix(query[i:], "<=>"):
+			if d == Postgres {
+				out.WriteString("IS NOT DISTINCT FROM")
+			} else {
+				out.WriteString("IS")
+			}
+			i += 2
+		case d == SQLite && (c == 'N' || c == 'n') && hasWordPrefixFold(query, i, "NOW()"):
+			out.WriteString("CURRENT_TIMESTAMP")
+			i += len("NOW()") - 1
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	return out.String()
+}
+
+// skipQuoted returns the index just past the literal starting at start, honouring
+// doubled quotes and backslash escapes
+func skipQuoted(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+func hasWordPrefixFold(query string, i int, word string) bool {
+	if i > 0 {
+		prev := query[i-1]
+		if prev == '_' || (prev >= 'a' && prev <= 'z') || (prev >= 'A' && prev <= 'Z') || (prev >= '0' && prev <= '9') {
+			return false
+		}
+	}
+	return len(query)-i >= len(word) && strings.EqualFold(query[i:i+len(word)], word)
+}
+
+// isInsert reports whether a statement is an INSERT without its own RETURNING clause
+func isInsert(query string) bool {
+	trimmed := strings.TrimSpace(query)
+	if len(trimmed) < 6 || !strings.EqualFold(trimmed[:6], "INSERT") {
+		return false
+	}
+	return !strings.Contains(strings.ToUpper(trimmed), " RETURNING ")
+}
@@ -0,0 +1,357 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/stdlib"
+	"modernc.org/sqlite"
+)
+
+const (
+	mysqlDriverName    = "accounting-mysql"
+	postgresDriverName = "accounting-postgres"
+	sqliteDriverName   = "accounting-sqlite"
+)
+
+func init() {
+	sql.Register(mysqlDriverName, &wrappedDriver{inner: &mysql.MySQLDriver{}, dialect: MySQL})
+	sql.Register(postgresDriverName, &wrappedDriver{inner: stdlib.GetDefaultDriver(), dialect: Postgres})
+	sql.Register(sqliteDriverName, &wrappedDriver{inner: &sqlite.Driver{}, dialect: SQLite})
+}
+
+// PrepareDSN adds the connection settings the application relies on
+func (d Name) PrepareDSN(dsn string) string {
+	switch d {
+	case Postgres:
+		// The simple protocol lets PostgreSQL coerce untyped arguments like MySQL does,
+		// e.g. an int status bound to a varchar column
+		if !strings.Contains(dsn, "default_query_exec_mode") {
+			dsn = appendParam(dsn, "default_query_exec_mode", "simple_protocol")
+		}
+	case SQLite:
+		if !strings.Contains(dsn, "foreign_keys") {
+			dsn = appendParam(dsn, "_pragma", "foreign_keys(1)")
+		}
+		if !strings.Contains(dsn, "busy_timeout") {
+			dsn = appendParam(dsn, "_pragma", "busy_timeout(5000)")
+		}
+	}
+	return dsn
+}
+
+func appendParam(dsn string, key string, value string) string {
+	// Key/value style PostgreSQL DSNs ("host=... dbname=...") take space separated settings
+	if !strings.Contains(dsn, "://") && strings.Contains(dsn, "=") && !strings.Contains(dsn, "?") && !strings.HasPrefix(dsn, "file:") {
+		return dsn + " " + key + "=" + value
+	}
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	return dsn + separator + key + "=" + value
+}
+
+type wrappedDriver struct {
+	inner   driver.Driver
+	dialect Name
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	inner, err := d.inner.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{inner: inner, dialect: d.dialect}, nil
+}
+
+type conn struct {
+	inner   driver.Conn
+	dialect Name
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	rewritten, returning := c.rewrite(query)
+	var inner driver.Stmt
+	var err error
+	if preparer, ok := c.inner.(driver.ConnPrepareContext); ok {
+		inner, err = preparer.PrepareContext(ctx, rewritten)
+	} else {
+		inner, err = c.inner.Prepare(rewritten)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{inner: inner, query: query, returning: returning}, nil
+}
+
+func (c *conn) Close() error {
+	return c.inner.Close()
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.inner.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.inner.Begin()
+}
+
+// rewrite translates the statement and, on PostgreSQL, makes INSERTs return their row so
+// LastInsertId keeps working
+func (c *conn) rewrite(query string) (string, bool) {
+	rewritten := c.dialect.Rewrite(query)
+	if c.dialect == Postgres && isInsert(rewritten) {
+		return strings.TrimRight(strings.TrimSpace(rewritten), ";") + " RETURNING *", true
+	}
+	return rewritten, false
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	defer observe(ctx, query, time.Now())
+	rewritten, returning := c.rewrite(query)
+	if returning {
+		queryer, ok := c.inner.(driver.QueryerContext)
+		if !ok {
+			return nil, driver.ErrSkip
+		}
+		rows, err := queryer.QueryContext(ctx, rewritten, args)
+		if err != nil {
+			return nil, err
+		}
+		return collectInsertResult(rows)
+	}
+
+	execer, ok := c.inner.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return execer.ExecContext(ctx, rewritten, args)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	defer observe(ctx, query, time.Now())
+	queryer, ok := c.inner.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	rewritten := c.dialect.Rewrite(query)
+	rows, err := queryer.QueryContext(ctx, rewritten, args)
+	if err != nil {
+		return nil, err
+	}
+	return &normalizedRows{inner: rows}, nil
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.inner.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.inner.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if validator, ok := c.inner.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *conn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.inner.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+type stmt struct {
+	inner     driver.Stmt
+	query     string
+	returning bool
+}
+
+func (s *stmt) Close() error {
+	return s.inner.Close()
+}
+
+func (s *stmt) NumInput() int {
+	return s.inner.NumInput()
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	defer observe(ctx, s.query, time.Now())
+	if s.returning {
+		rows, err := s.queryInner(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return collectInsertResult(rows)
+	}
+	if execer, ok := s.inner.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	return s.inner.Exec(plainValues(args))
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	defer observe(ctx, s.query, time.Now())
+	rows, err := s.queryInner(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return &normalizedRows{inner: rows}, nil
+}
+
+func (s *stmt) queryInner(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := s.inner.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+	return s.inner.Query(plainValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+func plainValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+type insertResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r insertResult) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r insertResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// collectInsertResult reads the rows of an INSERT ... RETURNING * into a Result.
+// Like MySQL, LastInsertId is the id of the first inserted row.
+func collectInsertResult(rows driver.Rows) (driver.Result, error) {
+	defer rows.Close()
+
+	idIndex := -1
+	for i, column := range rows.Columns() {
+		if strings.EqualFold(column, "id") {
+			idIndex = i
+			break
+		}
+	}
+
+	result := insertResult{}
+	values := make([]driver.Value, len(rows.Columns()))
+	for {
+		err := rows.Next(values)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if result.rowsAffected == 0 && idIndex >= 0 {
+			result.lastInsertID = toInt64(values[idIndex])
+		}
+		result.rowsAffected++
+	}
+	return result, nil
+}
+
+func toInt64(value driver.Value) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case []byte:
+		parsed, _ := strconv.ParseInt(string(v), 10, 64)
+		return parsed
+	case string:
+		parsed, _ := strconv.ParseInt(v, 10, 64)
+		return parsed
+	}
+	return 0
+}
+
+// normalizedRows returns DATE values as "2006-01-02" and other time values as
+// "2006-01-02 15:04:05", which is what the MySQL driver hands back without parseTime
+type normalizedRows struct {
+	inner driver.Rows
+}
+
+func (r *normalizedRows) Columns() []string {
+	return r.inner.Columns()
+}
+
+func (r *normalizedRows) Close() error {
+	return r.inner.Close()
+}
+
+func (r *normalizedRows) Next(dest []driver.Value) error {
+	if err := r.inner.Next(dest); err != nil {
+		return err
+	}
+	for i, value := range dest {
+		t, ok := value.(time.Time)
+		if !ok {
+			continue
+		}
+		if r.isDateColumn(i) {
+			dest[i] = t.Format("2006-01-02")
+		} else {
+			dest[i] = t.Format("2006-01-02 15:04:05")
+		}
+	}
+	return nil
+}
+
+func (r *normalizedRows) isDateColumn(index int) bool {
+	typed, ok := r.inner.(driver.RowsColumnTypeDatabaseTypeName)
+	if !ok {
+		return false
+	}
+	return strings.EqualFold(typed.ColumnTypeDatabaseTypeName(index), "DATE")
+}
@@ -0,0 +1,25 @@
+package dialect
+
+import (
+	"context"
+	"time"
+)
+
+// SlowQueryThreshold is the duration from which a statement is reported to SlowQuery;
+// zero turns reporting off. Both are set when the database is connected.
+var SlowQueryThreshold time.Duration
+
+// SlowQuery receives statements that took at least SlowQueryThreshold, as written by
+// the repository before rewriting. ctx is the statement's context, which carries the
+// request logger when the caller passed one.
+var SlowQuery func(ctx context.Context, query string, elapsed time.Duration)
+
+// observe reports the statement started at start if it was slow
+func observe(ctx context.Context, query string, start time.Time) {
+	if SlowQueryThreshold <= 0 || SlowQuery == nil {
+		return
+	}
+	if elapsed := time.Since(start); elapsed >= SlowQueryThreshold {
+		SlowQuery(ctx, query, elapsed)
+	}
+}
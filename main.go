@@ -1,29 +1,241 @@
+//go:generate go run . openapi generate
+
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/mysecodgit/go_accounting/config"
+	"github.com/mysecodgit/go_accounting/migrations"
 	"github.com/mysecodgit/go_accounting/routes"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/idempotency"
+	"github.com/mysecodgit/go_accounting/src/jobs"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/webhooks"
+	"github.com/mysecodgit/go_accounting/utils"
 )
 
 func main() {
-	r := gin.Default()
+	config.LoadConfig()
+
+	// JSON logs; the standard log package writes through the same handler
+	logger := logging.New(os.Stdout, config.App.Log.Level)
+	slog.SetDefault(logger)
+
+	// Admin command: go_accounting [-config file] migrate <up|down|status|verify|baseline|force>
+	if flag.Arg(0) == "migrate" {
+		config.ConnectDatabase()
+		if err := migrations.RunCommand(config.DB, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	// Admin command: go_accounting openapi <generate|check>. Routes are only registered,
+	// so no database is needed.
+	if flag.Arg(0) == "openapi" {
+		gin.SetMode(gin.ReleaseMode)
+		r := gin.New()
+		portal := gin.New()
+		routes.SetupRoutes(r, portal, logger, jobs.NewRegistry())
+		// The portal document is served by the portal itself; only check it is complete
+		if _, undocumented := openapi.Build(portal.Routes(), routes.PortalOpenAPI...); len(undocumented) > 0 {
+			log.Fatalf("portal routes without an OpenAPI description (add them to the portal's OpenAPI handlers):\n  %s", strings.Join(undocumented, "\n  "))
+		}
+		if err := openapi.RunCommand(r, routes.OpenAPI, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}
+
+	if strings.ToLower(config.App.Log.Level) != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	r := newEngine()
+
+	// Validation errors report fields by their json names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		utils.UseJSONFieldNames(v)
+	}
 
 	// CORS middleware
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"}, // Change to your frontend URL, or use "*" to allow all
+		AllowOrigins:     config.App.CORS.AllowOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "User-ID"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "User-ID", idempotency.HeaderKey, "If-Match", logging.HeaderRequestID},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", idempotency.HeaderReplayed, "ETag", logging.HeaderRequestID},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
 	config.ConnectDatabase()
-	routes.SetupRoutes(r)
+	metrics.RegisterDB(config.DB, config.App.Database.Driver)
+
+	migrator, err := migrations.NewMigrator(config.DB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if config.App.Database.AutoMigrate {
+		if _, err := migrator.Up(); err != nil {
+			log.Fatal("Schema migration failed: ", err)
+		}
+	} else if err := migrator.Verify(); err != nil {
+		log.Fatal("Schema check failed: ", err)
+	}
+
+	// The tenant portal has its own listener and CORS origins, so it can be exposed to
+	// tenants without exposing the staff API
+	var portal *gin.Engine
+	if config.App.Portal.Enabled {
+		portal = newEngine()
+		portal.Use(cors.New(cors.Config{
+			AllowOrigins:     config.App.Portal.AllowOrigins,
+			AllowMethods:     []string{"GET", "POST", "OPTIONS"},
+			AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", logging.HeaderRequestID},
+			ExposeHeaders:    []string{"Content-Length", "Content-Disposition", logging.HeaderRequestID},
+			AllowCredentials: true,
+			MaxAge:           12 * time.Hour,
+		}))
+	}
+
+	jobRegistry := jobs.NewRegistry()
+	routes.SetupRoutes(r, portal, logger, jobRegistry)
+
+	// The webhook dispatcher delivers outbox events until shutdown begins
+	dispatchCtx, stopDispatcher := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	if config.App.Webhooks.Enabled {
+		dispatcher := webhooks.NewDispatcher(
+			webhooks.NewWebhookRepository(config.DB), outbox.NewOutboxRepository(config.DB), logger.With("component", "webhooks"),
+			config.App.Webhooks.DispatchIntervalDuration(), config.App.Webhooks.RequestTimeoutDuration(), config.App.Webhooks.MaxAttempts,
+		)
+		go func() {
+			defer close(dispatcherDone)
+			dispatcher.Run(dispatchCtx)
+		}()
+	} else {
+		close(dispatcherDone)
+	}
+
+	// The job scheduler runs due jobs until shutdown begins; runs in progress are then
+	// cancelled and recorded before the database is closed
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	if config.App.Scheduler.Enabled {
+		scheduler := jobs.NewScheduler(
+			jobs.NewJobRepository(config.DB), jobRegistry, logger.With("component", "scheduler"),
+			config.App.Scheduler.PollIntervalDuration(), config.App.Scheduler.LockTimeoutDuration(),
+		)
+		go func() {
+			defer close(schedulerDone)
+			scheduler.Run(schedulerCtx)
+		}()
+	} else {
+		close(schedulerDone)
+	}
+
+	// The notification sender delivers queued emails and SMS until shutdown begins
+	senderCtx, stopSender := context.WithCancel(context.Background())
+	senderDone := make(chan struct{})
+	if config.App.Notifications.Enabled {
+		notificationLogger := logger.With("component", "notifications")
+		sender := notifications.NewSender(
+			notifications.NewNotificationRepository(config.DB), notifications.NewTransports(config.App.Notifications, notificationLogger), notificationLogger,
+			config.App.Notifications.DispatchIntervalDuration(), config.App.Notifications.MaxAttempts,
+		)
+		go func() {
+			defer close(senderDone)
+			sender.Run(senderCtx)
+		}()
+	} else {
+		close(senderDone)
+	}
+
+	server := config.App.Server
+	srv := &http.Server{Addr: server.Address, Handler: r}
+	go serve(srv, server)
+	log.Printf("Listening on %s", server.Address)
+
+	// The portal shares the server's TLS certificate
+	var portalSrv *http.Server
+	if portal != nil {
+		portalSrv = &http.Server{Addr: config.App.Portal.Address, Handler: portal}
+		go serve(portalSrv, server)
+		log.Printf("Tenant portal listening on %s", config.App.Portal.Address)
+	}
+
+	// On SIGTERM stop accepting connections and let in-flight requests finish, so a
+	// posting that has begun its database transaction commits instead of being cut off
+	stop, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	<-stop.Done()
+
+	log.Printf("Shutting down, waiting up to %s for %d in-flight request(s)", server.ShutdownTimeoutDuration(), metrics.InFlight())
+	ctx, cancelShutdown := context.WithTimeout(context.Background(), server.ShutdownTimeoutDuration())
+	defer cancelShutdown()
+	if portalSrv != nil {
+		if err := portalSrv.Shutdown(ctx); err != nil {
+			log.Printf("Tenant portal shutdown incomplete: %v", err)
+		}
+	}
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Shutdown incomplete, %d request(s) still in flight: %v", metrics.InFlight(), err)
+	}
+	// Events committed by the drained requests stay in the outbox for the next start
+	stopDispatcher()
+	stopScheduler()
+	stopSender()
+	<-dispatcherDone
+	<-schedulerDone
+	<-senderDone
+	if err := config.DB.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+	log.Println("Server stopped")
+}
+
+// newEngine returns an engine with request logging, panic recovery and metrics
+func newEngine() *gin.Engine {
+	r := gin.New()
+	r.Use(logging.Middleware())
+	// Panics are reported in the same error envelope as every other failure
+	r.Use(gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		apperrors.Abort(c, apperrors.Internal(fmt.Errorf("panic: %v", recovered)))
+	}))
+	r.Use(metrics.Middleware())
+	return r
+}
 
-	r.Run(":8083")
+// serve runs srv until it is shut down, with TLS when the server config has a certificate
+func serve(srv *http.Server, server config.ServerConfig) {
+	var err error
+	if server.TLSEnabled() {
+		err = srv.ListenAndServeTLS(server.TLSCertFile, server.TLSKeyFile)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal("Server stopped: ", err)
+	}
 }
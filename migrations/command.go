@@ -0,0 +1,96 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// Usage describes the migrate admin command
+const Usage = `usage: migrate <command>
+
+commands:
+  up            apply all pending migrations
+  down [n]      revert the last n migrations (default 1)
+  status        list migrations and whether they are applied
+  verify        check applied migrations against their checksums and that none are pending
+  baseline      record the baseline as applied for a database created from the old dumps
+  force <v>     mark migration v as cleanly applied after fixing a failed run by hand`
+
+// RunCommand runs the migrate admin command with its arguments
+func RunCommand(db *sql.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", Usage)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration(s) applied\n", count)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid number of steps: %s", args[1])
+			}
+		}
+		count, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d migration(s) reverted\n", count)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt
+			}
+			if status.Dirty {
+				state += " (dirty)"
+			}
+			if status.ChecksumMismatch {
+				state += " (checksum mismatch)"
+			}
+			fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, state)
+		}
+	case "verify":
+		if err := migrator.Verify(); err != nil {
+			return err
+		}
+		fmt.Println("All migrations are applied and match")
+	case "baseline":
+		if err := migrator.Baseline(); err != nil {
+			return err
+		}
+		fmt.Printf("Migration %d recorded as applied\n", BaselineVersion)
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf("force needs a version")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version: %s", args[1])
+		}
+		if err := migrator.Force(version); err != nil {
+			return err
+		}
+		fmt.Printf("Migration %d marked as applied\n", version)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], Usage)
+	}
+
+	return nil
+}
@@ -0,0 +1,509 @@
+// Package migrations applies the versioned SQL files embedded from migrations/sql/<dialect>.
+//
+// Files are named <version>_<name>.up.sql and <version>_<name>.down.sql, with the same
+// versions in every dialect directory. Applied versions
+// are recorded in schema_migrations together with the checksum of their up file, so editing
+// a migration after it has run is detected instead of silently diverging.
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mysecodgit/go_accounting/dialect"
+)
+
+//go:embed sql/*/*.sql
+var embedded embed.FS
+
+// BaselineVersion is the migration that describes the schema of databases created before
+// migrations existed
+const BaselineVersion = 1
+
+const versionTable = "schema_migrations"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string // sha256 of the up file
+}
+
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	Dirty     bool
+	AppliedAt string
+}
+
+type MigrationStatus struct {
+	Version          int
+	Name             string
+	Applied          bool
+	Dirty            bool
+	AppliedAt        string
+	ChecksumMismatch bool
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect.Name
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the embedded migrations of the current dialect
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load(embedded, dialect.Current)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect.Current, migrations: migrations}, nil
+}
+
+// Load reads and validates every migration under sql/<dialect> in the given file system
+func Load(fsys fs.FS, name dialect.Name) ([]Migration, error) {
+	dir := path.Join("sql", string(name))
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %v", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", entry.Name(), err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.UpSQL = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		if migration.DownSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrations returns the known migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+func (m *Migrator) ensureVersionTable() error {
+	query := `CREATE TABLE IF NOT EXISTS ` + versionTable + ` (
+		version integer NOT NULL,
+		name varchar(255) NOT NULL,
+		checksum char(64) NOT NULL,
+		dirty smallint NOT NULL DEFAULT 0,
+		applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (version)
+	)`
+	if m.dialect == dialect.MySQL {
+		query += ` ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci`
+	}
+	_, err := m.db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create %s table: %v", versionTable, err)
+	}
+	return nil
+}
+
+// Applied returns the migrations recorded in the version table, oldest first
+func (m *Migrator) Applied() ([]AppliedMigration, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(`SELECT version, name, checksum, dirty, applied_at FROM ` + versionTable + ` ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := []AppliedMigration{}
+	for rows.Next() {
+		var item AppliedMigration
+		if err := rows.Scan(&item.Version, &item.Name, &item.Checksum, &item.Dirty, &item.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %v", err)
+		}
+		applied = append(applied, item)
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// Verify checks that every applied migration is known, clean and unchanged, and that none
+// of this build's migrations is still pending
+func (m *Migrator) Verify() error {
+	applied, err := m.Applied()
+	if err != nil {
+		return err
+	}
+	if err := m.verifyApplied(applied); err != nil {
+		return err
+	}
+
+	appliedVersions := make(map[int]bool, len(applied))
+	for _, item := range applied {
+		appliedVersions[item.Version] = true
+	}
+	pending := []string{}
+	for _, migration := range m.migrations {
+		if !appliedVersions[migration.Version] {
+			pending = append(pending, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database is missing migration(s) %s; run 'migrate up' or enable database.auto_migrate", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func (m *Migrator) verifyApplied(applied []AppliedMigration) error {
+	for _, item := range applied {
+		if item.Dirty {
+			return fmt.Errorf("migration %d_%s failed part way; fix the schema by hand and run 'migrate force %d'", item.Version, item.Name, item.Version)
+		}
+		migration := m.find(item.Version)
+		if migration == nil {
+			return fmt.Errorf("database has migration %d_%s which this build does not know about", item.Version, item.Name)
+		}
+		if migration.Checksum != item.Checksum {
+			return fmt.Errorf("checksum mismatch for migration %d_%s: the file was changed after it was applied", item.Version, item.Name)
+		}
+	}
+	return nil
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.Applied()
+	if err != nil {
+		return nil, err
+	}
+	appliedByVersion := make(map[int]AppliedMigration)
+	for _, item := range applied {
+		appliedByVersion[item.Version] = item
+	}
+
+	statuses := []MigrationStatus{}
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if item, ok := appliedByVersion[migration.Version]; ok {
+			status.Applied = true
+			status.Dirty = item.Dirty
+			status.AppliedAt = item.AppliedAt
+			status.ChecksumMismatch = item.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration in order and returns how many were applied.
+// A database that already has the application tables but no version history is adopted
+// by recording the baseline as applied instead of running it.
+func (m *Migrator) Up() (int, error) {
+	applied, err := m.Applied()
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verifyApplied(applied); err != nil {
+		return 0, err
+	}
+
+	if len(applied) == 0 {
+		existing, err := m.hasLegacySchema()
+		if err != nil {
+			return 0, err
+		}
+		if existing {
+			if err := m.Baseline(); err != nil {
+				return 0, err
+			}
+			log.Printf("Existing schema found, recorded migration %d as applied", BaselineVersion)
+			applied, err = m.Applied()
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	done := make(map[int]bool)
+	for _, item := range applied {
+		done[item.Version] = true
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if done[migration.Version] {
+			continue
+		}
+		if err := m.apply(migration); err != nil {
+			return count, err
+		}
+		log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+		count++
+	}
+	return count, nil
+}
+
+// Down reverts the given number of most recently applied migrations
+func (m *Migrator) Down(steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("steps must be greater than 0")
+	}
+
+	applied, err := m.Applied()
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verifyApplied(applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(applied) - 1; i >= 0 && count < steps; i-- {
+		migration := m.find(applied[i].Version)
+		if err := m.revert(*migration); err != nil {
+			return count, err
+		}
+		log.Printf("Reverted migration %d_%s", migration.Version, migration.Name)
+		count++
+	}
+	return count, nil
+}
+
+// Baseline records the baseline migration as applied without running it, for databases
+// that were created from the old schema dumps
+func (m *Migrator) Baseline() error {
+	if err := m.ensureVersionTable(); err != nil {
+		return err
+	}
+	migration := m.find(BaselineVersion)
+	if migration == nil {
+		return fmt.Errorf("baseline migration %d not found", BaselineVersion)
+	}
+	_, err := m.db.Exec(`INSERT INTO `+versionTable+` (version, name, checksum, dirty) VALUES (?, ?, ?, 0)`,
+		migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		return fmt.Errorf("failed to record baseline: %v", err)
+	}
+	return nil
+}
+
+// Force marks a migration as cleanly applied, after a failed migration was fixed by hand
+func (m *Migrator) Force(version int) error {
+	if err := m.ensureVersionTable(); err != nil {
+		return err
+	}
+	migration := m.find(version)
+	if migration == nil {
+		return fmt.Errorf("migration %d not found", version)
+	}
+	query := m.dialect.Upsert(versionTable, []string{"version", "name", "checksum", "dirty"}, []string{"version"}, []string{"name", "checksum", "dirty"})
+	_, err := m.db.Exec(query, migration.Version, migration.Name, migration.Checksum, 0)
+	if err != nil {
+		return fmt.Errorf("failed to force migration %d: %v", version, err)
+	}
+	return nil
+}
+
+// hasLegacySchema reports whether the application tables exist without any version history
+func (m *Migrator) hasLegacySchema() (bool, error) {
+	query := `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'transactions'`
+	switch m.dialect {
+	case dialect.Postgres:
+		query = `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'transactions'`
+	case dialect.SQLite:
+		query = `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'transactions'`
+	}
+
+	var count int
+	err := m.db.QueryRow(query).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect schema: %v", err)
+	}
+	return count > 0, nil
+}
+
+// apply runs an up migration. MySQL commits DDL implicitly, so the version row is written
+// as dirty first and only marked clean once every statement succeeded. PostgreSQL and
+// SQLite could roll back, but share the same bookkeeping.
+func (m *Migrator) apply(migration Migration) error {
+	_, err := m.db.Exec(`INSERT INTO `+versionTable+` (version, name, checksum, dirty) VALUES (?, ?, ?, 1)`,
+		migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+	}
+
+	if err := m.execScript(migration.UpSQL); err != nil {
+		return fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+	}
+
+	_, err = m.db.Exec(`UPDATE `+versionTable+` SET dirty = 0 WHERE version = ?`, migration.Version)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) revert(migration Migration) error {
+	_, err := m.db.Exec(`UPDATE `+versionTable+` SET dirty = 1 WHERE version = ?`, migration.Version)
+	if err != nil {
+		return fmt.Errorf("failed to mark migration %d: %v", migration.Version, err)
+	}
+
+	if err := m.execScript(migration.DownSQL); err != nil {
+		return fmt.Errorf("reverting migration %d_%s failed: %v", migration.Version, migration.Name, err)
+	}
+
+	_, err = m.db.Exec(`DELETE FROM `+versionTable+` WHERE version = ?`, migration.Version)
+	if err != nil {
+		return fmt.Errorf("failed to remove migration %d: %v", migration.Version, err)
+	}
+	return nil
+}
+
+// execScript runs each statement of a script on a single connection, so session settings
+// such as FOREIGN_KEY_CHECKS apply to the statements that follow them
+func (m *Migrator) execScript(script string) error {
+	conn, err := m.db.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, statement := range SplitStatements(script) {
+		if _, err := conn.ExecContext(context.Background(), statement); err != nil {
+			return fmt.Errorf("%v\nin statement: %s", err, firstLine(statement))
+		}
+	}
+	return nil
+}
+
+// SplitStatements splits a SQL script on semicolons outside of quotes and comments.
+// Comments are dropped from the result.
+func SplitStatements(script string) []string {
+	statements := []string{}
+	var current strings.Builder
+	var quote byte
+
+	flush := func() {
+		statement := strings.TrimSpace(current.String())
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		if quote != 0 {
+			current.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(script) {
+				i++
+				current.WriteByte(script[i])
+				continue
+			}
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			current.WriteByte(c)
+		case c == '-' && strings.HasPrefix(script[i:], "--"), c == '#':
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end
+				current.WriteByte('\n')
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 3
+			}
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
+
+func firstLine(statement string) string {
+	line, _, _ := strings.Cut(statement, "\n")
+	return line
+}
@@ -0,0 +1,659 @@
+-- Baseline schema generated from config/multiple_account_report.sql
+-- (structure only: tables, indexes, auto increments and foreign keys).
+
+CREATE TABLE `accounts` (
+  `id` int(11) NOT NULL,
+  `account_number` int(11) NOT NULL,
+  `account_name` varchar(50) NOT NULL,
+  `account_type` int(11) NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `isDefault` tinyint(1) NOT NULL,
+  `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `account_types` (
+  `id` int(11) NOT NULL,
+  `typeName` varchar(250) NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `sub_type` varchar(20) NOT NULL,
+  `typeStatus` varchar(10) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `buildings` (
+  `id` int(11) NOT NULL,
+  `name` varchar(250) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `checks` (
+  `id` int(11) NOT NULL,
+  `transaction_id` int(11) NOT NULL,
+  `check_date` date NOT NULL,
+  `reference_number` varchar(50) DEFAULT NULL,
+  `payment_account_id` int(11) NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `memo` text DEFAULT NULL,
+  `total_amount` decimal(10,2) DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `credit_memo` (
+  `id` int(11) NOT NULL,
+  `transaction_id` int(11) NOT NULL,
+  `reference` varchar(255) NOT NULL,
+  `date` date NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `deposit_to` int(11) NOT NULL,
+  `liability_account` int(11) NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `unit_id` int(11) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `description` text NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `expense_lines` (
+  `id` int(11) NOT NULL,
+  `check_id` int(11) NOT NULL,
+  `account_id` int(11) NOT NULL,
+  `unit_id` int(11) DEFAULT NULL,
+  `people_id` int(11) DEFAULT NULL,
+  `description` text DEFAULT NULL,
+  `amount` decimal(10,2) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `invoices` (
+  `id` int(11) NOT NULL,
+  `invoice_no` varchar(255) NOT NULL,
+  `transaction_id` int(11) NOT NULL,
+  `sales_date` date NOT NULL,
+  `due_date` date NOT NULL,
+  `ar_account_id` int(11) NOT NULL,
+  `unit_id` int(11) DEFAULT NULL,
+  `people_id` int(11) DEFAULT NULL,
+  `user_id` int(11) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `description` text NOT NULL,
+  `cancel_reason` text DEFAULT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `building_id` int(11) NOT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updatedAt` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `invoice_applied_credits` (
+  `id` int(11) NOT NULL,
+  `invoice_id` int(11) NOT NULL,
+  `credit_memo_id` int(11) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `description` text NOT NULL,
+  `date` date NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `invoice_applied_discounts` (
+  `id` int(11) NOT NULL,
+  `reference` varchar(255) NOT NULL,
+  `invoice_id` int(11) NOT NULL,
+  `transaction_id` int(11) NOT NULL,
+  `ar_account` int(11) NOT NULL,
+  `income_account` int(11) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `description` text NOT NULL,
+  `date` date NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `invoice_items` (
+  `id` int(11) NOT NULL,
+  `invoice_id` int(11) NOT NULL,
+  `item_id` int(11) NOT NULL,
+  `item_name` varchar(250) NOT NULL,
+  `previous_value` decimal(10,3) DEFAULT NULL,
+  `current_value` decimal(10,3) DEFAULT NULL,
+  `qty` decimal(10,3) DEFAULT NULL,
+  `rate` varchar(100) DEFAULT NULL,
+  `total` decimal(10,2) NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `invoice_payments` (
+  `id` int(11) NOT NULL,
+  `transaction_id` int(11) NOT NULL,
+  `reference` varchar(255) NOT NULL,
+  `date` date NOT NULL,
+  `invoice_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `account_id` int(11) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updatedAt` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `items` (
+  `id` int(11) NOT NULL,
+  `name` varchar(250) NOT NULL,
+  `type` enum('inventory','non inventory','service','discount','payment') NOT NULL,
+  `description` text NOT NULL,
+  `asset_account` int(11) DEFAULT NULL,
+  `income_account` int(11) DEFAULT NULL,
+  `cogs_account` int(11) DEFAULT NULL,
+  `expense_account` int(11) DEFAULT NULL,
+  `on_hand` decimal(10,2) NOT NULL,
+  `avg_cost` decimal(10,2) NOT NULL,
+  `date` date NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `journal` (
+  `id` int(11) NOT NULL,
+  `transaction_id` int(11) NOT NULL,
+  `reference` varchar(255) NOT NULL,
+  `journal_date` date NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `memo` text DEFAULT NULL,
+  `total_amount` decimal(10,2) DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `journal_lines` (
+  `id` int(11) NOT NULL,
+  `journal_id` int(11) NOT NULL,
+  `account_id` int(11) NOT NULL,
+  `unit_id` int(11) DEFAULT NULL,
+  `people_id` int(11) DEFAULT NULL,
+  `description` text DEFAULT NULL,
+  `debit` decimal(10,2) DEFAULT 0.00,
+  `credit` decimal(10,2) DEFAULT 0.00
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `leases` (
+  `id` int(11) NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `unit_id` int(11) NOT NULL,
+  `start_date` date NOT NULL,
+  `end_date` date DEFAULT NULL,
+  `rent_amount` decimal(10,2) NOT NULL,
+  `deposit_amount` decimal(10,2) NOT NULL,
+  `service_amount` decimal(10,2) NOT NULL,
+  `lease_terms` text NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `lease_files` (
+  `id` int(11) NOT NULL,
+  `lease_id` int(11) NOT NULL,
+  `filename` varchar(255) NOT NULL,
+  `original_name` varchar(255) NOT NULL,
+  `file_path` varchar(500) NOT NULL,
+  `file_type` varchar(100) NOT NULL,
+  `file_size` int(11) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `people` (
+  `id` int(11) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `phone` varchar(20) NOT NULL,
+  `type_id` int(11) NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `people_types` (
+  `id` int(11) NOT NULL,
+  `title` varchar(50) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `periods` (
+  `id` int(11) NOT NULL,
+  `period_name` varchar(50) NOT NULL,
+  `start` date NOT NULL,
+  `end` date NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `is_closed` int(2) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `readings` (
+  `id` int(11) NOT NULL,
+  `item_id` int(11) NOT NULL,
+  `unit_id` int(11) NOT NULL,
+  `lease_id` int(11) DEFAULT NULL,
+  `reading_month` varchar(10) DEFAULT NULL,
+  `reading_year` varchar(5) DEFAULT NULL,
+  `reading_date` date NOT NULL,
+  `previous_value` decimal(10,3) DEFAULT NULL,
+  `current_value` decimal(10,3) DEFAULT NULL,
+  `unit_price` decimal(10,2) DEFAULT NULL,
+  `total_amount` decimal(10,2) DEFAULT NULL,
+  `notes` text DEFAULT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `receipt_items` (
+  `id` int(11) NOT NULL,
+  `receipt_id` int(11) NOT NULL,
+  `item_id` int(11) NOT NULL,
+  `item_name` varchar(250) NOT NULL,
+  `previous_value` decimal(10,3) DEFAULT NULL,
+  `current_value` decimal(10,3) DEFAULT NULL,
+  `qty` decimal(10,2) DEFAULT NULL,
+  `rate` varchar(100) DEFAULT NULL,
+  `total` decimal(10,2) NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `sales_receipt` (
+  `id` int(11) NOT NULL,
+  `receipt_no` int(11) NOT NULL,
+  `transaction_id` int(11) NOT NULL,
+  `receipt_date` date NOT NULL,
+  `unit_id` int(11) DEFAULT NULL,
+  `people_id` int(11) DEFAULT NULL,
+  `user_id` int(11) NOT NULL,
+  `account_id` int(11) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `description` text DEFAULT NULL,
+  `cancel_reason` text DEFAULT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `building_id` int(11) NOT NULL,
+  `createdAt` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updatedAt` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `splits` (
+  `id` int(11) NOT NULL,
+  `transaction_id` int(11) NOT NULL,
+  `account_id` int(11) NOT NULL,
+  `people_id` int(11) DEFAULT NULL,
+  `unit_id` int(11) DEFAULT NULL,
+  `debit` decimal(10,2) DEFAULT NULL,
+  `credit` decimal(10,2) DEFAULT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `transactions` (
+  `id` int(11) NOT NULL,
+  `type` enum('invoice','payment','check','deposit','bill','credit memo','sales receipt','journal','bill credit','bill payment','credit applied') NOT NULL,
+  `transaction_date` date NOT NULL,
+  `transaction_number` varchar(255) NOT NULL,
+  `memo` text NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `created_at` datetime NOT NULL DEFAULT current_timestamp(),
+  `updated_at` datetime NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  `building_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `unit_id` int(11) DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `units` (
+  `id` int(11) NOT NULL,
+  `name` varchar(20) NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE `users` (
+  `id` int(11) NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `username` varchar(20) NOT NULL,
+  `phone` varchar(20) NOT NULL,
+  `password` varchar(10) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+ALTER TABLE `accounts`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `acc_acc_type_fk` (`account_type`),
+  ADD KEY `accounts_building_fk` (`building_id`);
+
+ALTER TABLE `account_types`
+  ADD PRIMARY KEY (`id`);
+
+ALTER TABLE `buildings`
+  ADD PRIMARY KEY (`id`),
+  ADD UNIQUE KEY `name` (`name`);
+
+ALTER TABLE `checks`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `transaction_id` (`transaction_id`),
+  ADD KEY `payment_account_id` (`payment_account_id`),
+  ADD KEY `building_id` (`building_id`);
+
+ALTER TABLE `credit_memo`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `cm_transaction_id` (`transaction_id`),
+  ADD KEY `cm_user_id` (`user_id`),
+  ADD KEY `cm_deposit_to` (`deposit_to`),
+  ADD KEY `cm_liability_account` (`liability_account`),
+  ADD KEY `cm_people_id` (`people_id`),
+  ADD KEY `cm_building_id` (`building_id`),
+  ADD KEY `cm_unit_id` (`unit_id`);
+
+ALTER TABLE `expense_lines`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `check_id` (`check_id`),
+  ADD KEY `account_id` (`account_id`),
+  ADD KEY `unit_id` (`unit_id`),
+  ADD KEY `people_id` (`people_id`);
+
+ALTER TABLE `invoices`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `invoice_unit_id` (`unit_id`),
+  ADD KEY `invoice_people_id` (`people_id`),
+  ADD KEY `invoice_user_id` (`user_id`),
+  ADD KEY `invoice_building_id` (`building_id`),
+  ADD KEY `fk_invoice_account_id` (`ar_account_id`);
+
+ALTER TABLE `invoice_applied_credits`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `iac_invoice_id` (`invoice_id`),
+  ADD KEY `iac_credit_memo_id` (`credit_memo_id`);
+
+ALTER TABLE `invoice_applied_discounts`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `iad_invoice_id` (`invoice_id`),
+  ADD KEY `iad_transaction_id` (`transaction_id`),
+  ADD KEY `iad_ar_account` (`ar_account`),
+  ADD KEY `iad_income_account` (`income_account`);
+
+ALTER TABLE `invoice_items`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `invoice_items_inv_id` (`invoice_id`),
+  ADD KEY `invoice_items_item_id` (`item_id`);
+
+ALTER TABLE `invoice_payments`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `ip_transaction_id` (`transaction_id`),
+  ADD KEY `ip_invoice_id` (`invoice_id`),
+  ADD KEY `ip_user_id` (`user_id`),
+  ADD KEY `ip_account_id` (`account_id`);
+
+ALTER TABLE `items`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `item_asset_account_pk` (`asset_account`),
+  ADD KEY `item_income_account_pk` (`income_account`),
+  ADD KEY `item_cogs_account_pk` (`cogs_account`),
+  ADD KEY `item_expense_account_pk` (`expense_account`),
+  ADD KEY `item_building_fk` (`building_id`);
+
+ALTER TABLE `journal`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `transaction_id` (`transaction_id`),
+  ADD KEY `building_id` (`building_id`);
+
+ALTER TABLE `journal_lines`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `journal_id` (`journal_id`),
+  ADD KEY `account_id` (`account_id`),
+  ADD KEY `unit_id` (`unit_id`),
+  ADD KEY `people_id` (`people_id`);
+
+ALTER TABLE `leases`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `idx_people_id` (`people_id`),
+  ADD KEY `idx_building_id` (`building_id`),
+  ADD KEY `idx_unit_id` (`unit_id`);
+
+ALTER TABLE `lease_files`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `idx_lease_id` (`lease_id`);
+
+ALTER TABLE `people`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `people_type_id_fk` (`type_id`),
+  ADD KEY `people_building_id_fk` (`building_id`);
+
+ALTER TABLE `people_types`
+  ADD PRIMARY KEY (`id`);
+
+ALTER TABLE `periods`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `period_building_id` (`building_id`);
+
+ALTER TABLE `readings`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `idx_item_id` (`item_id`),
+  ADD KEY `idx_unit_id` (`unit_id`),
+  ADD KEY `idx_lease_id` (`lease_id`);
+
+ALTER TABLE `receipt_items`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `sri_receipt_id` (`receipt_id`),
+  ADD KEY `sri_item_id` (`item_id`);
+
+ALTER TABLE `sales_receipt`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `sr_unit_id` (`unit_id`),
+  ADD KEY `sr_people_id` (`people_id`),
+  ADD KEY `sr_user_id` (`user_id`),
+  ADD KEY `sr_building_id` (`building_id`),
+  ADD KEY `sr_account_id` (`account_id`);
+
+ALTER TABLE `splits`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `td_transaction_id` (`transaction_id`),
+  ADD KEY `td_account_id` (`account_id`),
+  ADD KEY `td_people_id` (`people_id`),
+  ADD KEY `fk_splits_unit` (`unit_id`);
+
+ALTER TABLE `transactions`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `transaction_type_fk` (`type`),
+  ADD KEY `transaction_building_fk` (`building_id`),
+  ADD KEY `transaction_user_fk` (`user_id`),
+  ADD KEY `transaction_unit_fk` (`unit_id`);
+
+ALTER TABLE `units`
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `unit_building_id_fk` (`building_id`);
+
+ALTER TABLE `users`
+  ADD PRIMARY KEY (`id`);
+
+ALTER TABLE `accounts`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `account_types`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `buildings`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `checks`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `credit_memo`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `expense_lines`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `invoices`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `invoice_applied_credits`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `invoice_applied_discounts`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `invoice_items`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `invoice_payments`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `items`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `journal`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `journal_lines`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `leases`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `lease_files`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `people`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `people_types`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `periods`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `readings`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `receipt_items`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `sales_receipt`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `splits`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `transactions`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `units`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `users`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+ALTER TABLE `accounts`
+  ADD CONSTRAINT `acc_acc_type_fk` FOREIGN KEY (`account_type`) REFERENCES `account_types` (`id`),
+  ADD CONSTRAINT `accounts_building_fk` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`);
+
+ALTER TABLE `checks`
+  ADD CONSTRAINT `checks_ibfk_1` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `checks_ibfk_2` FOREIGN KEY (`payment_account_id`) REFERENCES `accounts` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `checks_ibfk_3` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE `credit_memo`
+  ADD CONSTRAINT `fk_cm_building` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_cm_deposit_to` FOREIGN KEY (`deposit_to`) REFERENCES `accounts` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_cm_liability_account` FOREIGN KEY (`liability_account`) REFERENCES `accounts` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_cm_people` FOREIGN KEY (`people_id`) REFERENCES `people` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_cm_transaction` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_cm_unit` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_cm_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE CASCADE;
+
+ALTER TABLE `expense_lines`
+  ADD CONSTRAINT `expense_lines_ibfk_1` FOREIGN KEY (`check_id`) REFERENCES `checks` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `expense_lines_ibfk_2` FOREIGN KEY (`account_id`) REFERENCES `accounts` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `expense_lines_ibfk_3` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+  ADD CONSTRAINT `expense_lines_ibfk_4` FOREIGN KEY (`people_id`) REFERENCES `people` (`id`) ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE `invoices`
+  ADD CONSTRAINT `fk_invoice_account_id` FOREIGN KEY (`ar_account_id`) REFERENCES `accounts` (`id`),
+  ADD CONSTRAINT `fk_invoice_building` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_invoice_people` FOREIGN KEY (`people_id`) REFERENCES `people` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_invoice_unit` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE `invoice_applied_credits`
+  ADD CONSTRAINT `fk_iac_credit_memo` FOREIGN KEY (`credit_memo_id`) REFERENCES `credit_memo` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_iac_invoice` FOREIGN KEY (`invoice_id`) REFERENCES `invoices` (`id`) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE `invoice_applied_discounts`
+  ADD CONSTRAINT `fk_iad_ar_account` FOREIGN KEY (`ar_account`) REFERENCES `accounts` (`id`),
+  ADD CONSTRAINT `fk_iad_income_account` FOREIGN KEY (`income_account`) REFERENCES `accounts` (`id`),
+  ADD CONSTRAINT `fk_iad_invoice` FOREIGN KEY (`invoice_id`) REFERENCES `invoices` (`id`),
+  ADD CONSTRAINT `fk_iad_transaction` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`);
+
+ALTER TABLE `invoice_payments`
+  ADD CONSTRAINT `fk_ip_account` FOREIGN KEY (`account_id`) REFERENCES `accounts` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_ip_invoice` FOREIGN KEY (`invoice_id`) REFERENCES `invoices` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_ip_transaction` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_ip_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE CASCADE;
+
+ALTER TABLE `items`
+  ADD CONSTRAINT `fk_items_building` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE `journal`
+  ADD CONSTRAINT `journal_ibfk_1` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `journal_ibfk_2` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE `journal_lines`
+  ADD CONSTRAINT `journal_lines_ibfk_1` FOREIGN KEY (`journal_id`) REFERENCES `journal` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `journal_lines_ibfk_2` FOREIGN KEY (`account_id`) REFERENCES `accounts` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `journal_lines_ibfk_3` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+  ADD CONSTRAINT `journal_lines_ibfk_4` FOREIGN KEY (`people_id`) REFERENCES `people` (`id`) ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE `leases`
+  ADD CONSTRAINT `fk_leases_buildings` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_leases_people` FOREIGN KEY (`people_id`) REFERENCES `people` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_leases_units` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON UPDATE CASCADE;
+
+ALTER TABLE `lease_files`
+  ADD CONSTRAINT `fk_lease_files_lease_id` FOREIGN KEY (`lease_id`) REFERENCES `leases` (`id`) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE `people`
+  ADD CONSTRAINT `people_building_id_fk` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`),
+  ADD CONSTRAINT `people_type_id_fk` FOREIGN KEY (`type_id`) REFERENCES `people_types` (`id`);
+
+ALTER TABLE `periods`
+  ADD CONSTRAINT `period_building_id` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`);
+
+ALTER TABLE `readings`
+  ADD CONSTRAINT `fk_readings_item` FOREIGN KEY (`item_id`) REFERENCES `items` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_readings_lease` FOREIGN KEY (`lease_id`) REFERENCES `leases` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_readings_unit` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON UPDATE CASCADE;
+
+ALTER TABLE `receipt_items`
+  ADD CONSTRAINT `fk_sri_item` FOREIGN KEY (`item_id`) REFERENCES `items` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_sri_receipt` FOREIGN KEY (`receipt_id`) REFERENCES `sales_receipt` (`id`) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE `sales_receipt`
+  ADD CONSTRAINT `fk_sr_account` FOREIGN KEY (`account_id`) REFERENCES `accounts` (`id`) ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_sr_building` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_sr_people` FOREIGN KEY (`people_id`) REFERENCES `people` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_sr_unit` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE `splits`
+  ADD CONSTRAINT `fk_splits_account` FOREIGN KEY (`account_id`) REFERENCES `accounts` (`id`),
+  ADD CONSTRAINT `fk_splits_people` FOREIGN KEY (`people_id`) REFERENCES `people` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_splits_unit` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`);
+
+ALTER TABLE `transactions`
+  ADD CONSTRAINT `fk_transactions_building` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_transactions_unit` FOREIGN KEY (`unit_id`) REFERENCES `units` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  ADD CONSTRAINT `fk_transactions_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE `units`
+  ADD CONSTRAINT `unit_building_id_fk` FOREIGN KEY (`building_id`) REFERENCES `buildings` (`id`);
@@ -0,0 +1,4 @@
+-- Intentionally leaves the reference data in place. The up script skips rows
+-- that already exist, so on databases adopted through a baseline these rows
+-- predate this migration, and the accounts and people that reference them
+-- would be left pointing at missing types.
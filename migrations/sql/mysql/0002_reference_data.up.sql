@@ -0,0 +1,20 @@
+-- Reference data the application looks up by name (people types by title,
+-- account types by typeName/type/typeStatus).
+
+INSERT IGNORE INTO `people_types` (`id`, `title`) VALUES
+(1, 'customer'),
+(2, 'vendor'),
+(3, 'employee'),
+(6, 'others');
+
+INSERT IGNORE INTO `account_types` (`id`, `typeName`, `type`, `sub_type`, `typeStatus`) VALUES
+(1, 'Bank', 'Asset', 'current asset', 'debit'),
+(2, 'Account Receivable', 'Asset', 'current asset', 'debit'),
+(3, 'Other current asset', 'Asset', 'current asset', 'debit'),
+(4, 'Cost of goods sold', 'Expense', '', 'debit'),
+(5, 'Expense', 'Expense', '', 'debit'),
+(6, 'Account Payable', 'Liability', '', 'credit'),
+(7, 'Other Liability', 'Liability', '', 'credit'),
+(8, 'Equity', 'Equity', '', 'credit'),
+(9, 'Income', 'Income', '', 'credit'),
+(10, 'Fixed Asset', 'Asset', 'fixed asset', 'debit');
@@ -0,0 +1,17 @@
+-- One row per account, optional unit, year and month.
+-- unit_id NULL means the amount is budgeted for the whole building.
+
+CREATE TABLE IF NOT EXISTS `budgets` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `account_id` int(11) NOT NULL,
+  `unit_id` int(11) DEFAULT NULL,
+  `year` int(4) NOT NULL,
+  `month` int(2) NOT NULL,
+  `amount` decimal(15,2) NOT NULL DEFAULT 0.00,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  KEY `idx_budgets_building_year` (`building_id`, `year`),
+  KEY `idx_budgets_account` (`account_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
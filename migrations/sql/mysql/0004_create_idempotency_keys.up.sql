@@ -0,0 +1,21 @@
+-- Responses of POST requests sent with an Idempotency-Key header, one row per user and key.
+-- locked_until and expires_at are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) NOT NULL,
+  `idempotency_key` varchar(255) NOT NULL,
+  `method` varchar(10) NOT NULL,
+  `path` varchar(255) NOT NULL,
+  `request_hash` char(64) NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'processing',
+  `response_status` int(11) DEFAULT NULL,
+  `response_content_type` varchar(255) DEFAULT NULL,
+  `response_body` longtext DEFAULT NULL,
+  `locked_until` datetime NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_idempotency_keys_user_key` (`user_id`, `idempotency_key`),
+  KEY `idx_idempotency_keys_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
@@ -0,0 +1,50 @@
+-- Domain events are written to outbox_events in the same transaction as the change they
+-- describe. The dispatcher copies each event into webhook_deliveries, one row per matching
+-- subscription, and sets dispatched_at. Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS `outbox_events` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `event_type` varchar(100) NOT NULL,
+  `aggregate_type` varchar(50) NOT NULL,
+  `aggregate_id` int(11) NOT NULL,
+  `payload` longtext NOT NULL,
+  `created_at` datetime NOT NULL,
+  `dispatched_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_outbox_events_dispatched_at` (`dispatched_at`),
+  KEY `idx_outbox_events_building` (`building_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- event_types is a comma separated list; empty means every event of the building
+CREATE TABLE IF NOT EXISTS `webhook_subscriptions` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `url` varchar(2048) NOT NULL,
+  `secret` varchar(255) NOT NULL,
+  `event_types` text NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'active',
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  KEY `idx_webhook_subscriptions_building` (`building_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- status is pending until delivered, or dead once the attempts are used up
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `event_id` int(11) NOT NULL,
+  `subscription_id` int(11) NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'pending',
+  `attempts` int(11) NOT NULL DEFAULT 0,
+  `next_attempt_at` datetime NOT NULL,
+  `last_status_code` int(11) DEFAULT NULL,
+  `last_error` text DEFAULT NULL,
+  `delivered_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_webhook_deliveries_event_subscription` (`event_id`, `subscription_id`),
+  KEY `idx_webhook_deliveries_due` (`status`, `next_attempt_at`),
+  KEY `idx_webhook_deliveries_subscription` (`subscription_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
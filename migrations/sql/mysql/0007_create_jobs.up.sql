@@ -0,0 +1,44 @@
+-- Scheduled jobs run in-process. A job is claimed by one application instance at a time
+-- through locked_by/locked_until, and every execution is recorded in job_runs. Times are
+-- UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS `scheduled_jobs` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `job_type` varchar(50) NOT NULL,
+  `schedule` varchar(100) NOT NULL,
+  `timezone` varchar(64) NOT NULL DEFAULT 'UTC',
+  `params` text NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'active',
+  `next_run_at` datetime DEFAULT NULL,
+  `last_run_at` datetime DEFAULT NULL,
+  `last_run_status` varchar(20) DEFAULT NULL,
+  `locked_by` varchar(100) DEFAULT NULL,
+  `locked_until` datetime DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  KEY `idx_scheduled_jobs_due` (`status`, `next_run_at`),
+  KEY `idx_scheduled_jobs_building` (`building_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+-- status moves queued -> running -> succeeded, failed or cancelled
+CREATE TABLE IF NOT EXISTS `job_runs` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `job_id` int(11) NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `triggered_by` varchar(20) NOT NULL,
+  `user_id` int(11) DEFAULT NULL,
+  `status` varchar(20) NOT NULL,
+  `cancel_requested` tinyint(1) NOT NULL DEFAULT 0,
+  `runner` varchar(100) DEFAULT NULL,
+  `output` longtext DEFAULT NULL,
+  `error` text DEFAULT NULL,
+  `queued_at` datetime NOT NULL,
+  `started_at` datetime DEFAULT NULL,
+  `finished_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_job_runs_job` (`job_id`, `id`),
+  KEY `idx_job_runs_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
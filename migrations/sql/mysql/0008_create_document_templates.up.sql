@@ -0,0 +1,20 @@
+-- Per-building print settings of each document type (invoice, sales_receipt, payment,
+-- credit_memo). A type without a row prints with the defaults. logo_path is relative to
+-- the upload root.
+
+CREATE TABLE IF NOT EXISTS `document_templates` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `document_type` varchar(30) NOT NULL,
+  `title` varchar(100) NOT NULL,
+  `business_name` varchar(255) NOT NULL DEFAULT '',
+  `address` text NOT NULL,
+  `footer_text` text NOT NULL,
+  `number_prefix` varchar(20) NOT NULL DEFAULT '',
+  `number_padding` int(11) NOT NULL DEFAULT 0,
+  `logo_path` varchar(255) DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_document_templates_building_type` (`building_id`, `document_type`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `notifications`;
+DROP TABLE IF EXISTS `notification_preferences`;
+DROP TABLE IF EXISTS `notification_templates`;
+ALTER TABLE `people` DROP COLUMN `email`;
@@ -0,0 +1,59 @@
+-- Email and SMS notifications. people.email is optional; a person without an address or phone
+-- is skipped on that channel.
+--
+-- notification_templates override the built-in subject and body of a category on a channel.
+-- notification_preferences hold the channels a person wants per category as a comma separated
+-- list; a category without a row goes to every channel the person has contact details for.
+-- notifications is the delivery log: status is pending until sent, or failed once the attempts
+-- are used up. Times are UTC and written by the application.
+
+ALTER TABLE `people` ADD COLUMN `email` varchar(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS `notification_templates` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `category` varchar(30) NOT NULL,
+  `channel` varchar(10) NOT NULL,
+  `subject` varchar(255) NOT NULL DEFAULT '',
+  `body` text NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_notification_templates_building_category_channel` (`building_id`, `category`, `channel`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `notification_preferences` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `category` varchar(30) NOT NULL,
+  `channels` varchar(30) NOT NULL DEFAULT '',
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_notification_preferences_people_category` (`people_id`, `category`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `notifications` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `category` varchar(30) NOT NULL,
+  `channel` varchar(10) NOT NULL,
+  `recipient` varchar(255) NOT NULL,
+  `subject` varchar(255) NOT NULL DEFAULT '',
+  `body` text NOT NULL,
+  `reference_type` varchar(30) DEFAULT NULL,
+  `reference_id` int(11) DEFAULT NULL,
+  `attachment_name` varchar(255) DEFAULT NULL,
+  `attachment_path` varchar(255) DEFAULT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'pending',
+  `attempts` int(11) NOT NULL DEFAULT 0,
+  `next_attempt_at` datetime NOT NULL,
+  `last_error` text DEFAULT NULL,
+  `sent_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_notifications_due` (`status`, `next_attempt_at`),
+  KEY `idx_notifications_building` (`building_id`, `id`),
+  KEY `idx_notifications_reference` (`reference_type`, `reference_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `dunning_reminders`;
+DROP TABLE IF EXISTS `dunning_exclusions`;
+DROP TABLE IF EXISTS `dunning_steps`;
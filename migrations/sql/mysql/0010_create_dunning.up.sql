@@ -0,0 +1,52 @@
+-- Dunning: reminders of overdue invoices sent in steps, e.g. a friendly reminder 3 days
+-- after the due date, a firm notice at 15 days and a final notice at 30.
+--
+-- dunning_steps is a building's sequence; each step has its own channel and template.
+-- dunning_exclusions keeps customers out of dunning, with the reason.
+-- dunning_reminders records every reminder of an invoice: sent by a dunning step, sent by
+-- hand (step_id NULL), or skipped when the customer could not be reached on the step's
+-- channel. Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS `dunning_steps` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `days_overdue` int(11) NOT NULL,
+  `channel` varchar(10) NOT NULL,
+  `subject` varchar(255) NOT NULL DEFAULT '',
+  `body` text NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_dunning_steps_building_days` (`building_id`, `days_overdue`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `dunning_exclusions` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `reason` varchar(255) NOT NULL,
+  `user_id` int(11) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_dunning_exclusions_people` (`people_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `dunning_reminders` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `invoice_id` int(11) NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `step_id` int(11) DEFAULT NULL,
+  `step_name` varchar(100) NOT NULL,
+  `days_overdue` int(11) NOT NULL,
+  `balance` decimal(10,2) NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `note` varchar(255) DEFAULT NULL,
+  `notification_ids` varchar(100) NOT NULL DEFAULT '',
+  `user_id` int(11) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_dunning_reminders_invoice_step` (`invoice_id`, `step_id`),
+  KEY `idx_dunning_reminders_building` (`building_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
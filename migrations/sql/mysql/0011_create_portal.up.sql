@@ -0,0 +1,29 @@
+-- Tenant portal sign-in. A tenant asks for a code by phone number; portal_codes keeps a
+-- hash of each code sent with its wrong guesses. A correct code opens a session for one
+-- people record; portal_sessions keeps a hash of the bearer token, never the token itself.
+-- Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS `portal_codes` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `phone` varchar(30) NOT NULL,
+  `code_hash` varchar(64) NOT NULL,
+  `attempts` int(11) NOT NULL DEFAULT 0,
+  `expires_at` datetime NOT NULL,
+  `used_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_portal_codes_phone` (`phone`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `portal_sessions` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `token_hash` varchar(64) NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `building_id` int(11) NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `revoked_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_portal_sessions_token` (`token_hash`),
+  KEY `idx_portal_sessions_people` (`people_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
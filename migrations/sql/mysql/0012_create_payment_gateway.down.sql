@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `payment_gateway_events`;
+DROP TABLE IF EXISTS `payment_intents`;
+DROP TABLE IF EXISTS `payment_gateway_settings`;
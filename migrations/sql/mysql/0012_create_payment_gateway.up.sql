@@ -0,0 +1,60 @@
+-- Online payments through a payment gateway, e.g. mobile money or cards.
+--
+-- payment_gateway_settings holds, per building, the clearing account gateway payments are
+-- received into and the user they are recorded by.
+-- payment_intents are pending payments of an invoice handed to the gateway; paid_amount is
+-- what the gateway reported paid, less reversals.
+-- payment_gateway_events records every webhook event once per provider event id, so that
+-- redelivered events are recognised, with the invoice payment it created. Times are UTC and
+-- written by the application.
+
+CREATE TABLE IF NOT EXISTS `payment_gateway_settings` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `clearing_account_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'active',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_payment_gateway_settings_building` (`building_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `payment_intents` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `building_id` int(11) NOT NULL,
+  `invoice_id` int(11) NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `provider` varchar(30) NOT NULL,
+  `provider_reference` varchar(100) NOT NULL,
+  `checkout_url` varchar(500) NOT NULL DEFAULT '',
+  `amount` decimal(10,2) NOT NULL,
+  `paid_amount` decimal(10,2) NOT NULL DEFAULT 0,
+  `status` varchar(20) NOT NULL,
+  `source` varchar(20) NOT NULL,
+  `user_id` int(11) DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_payment_intents_reference` (`provider`, `provider_reference`),
+  KEY `idx_payment_intents_invoice` (`invoice_id`),
+  KEY `idx_payment_intents_building` (`building_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+CREATE TABLE IF NOT EXISTS `payment_gateway_events` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `provider` varchar(30) NOT NULL,
+  `event_id` varchar(100) NOT NULL,
+  `intent_id` int(11) DEFAULT NULL,
+  `type` varchar(20) NOT NULL,
+  `amount` decimal(10,2) NOT NULL DEFAULT 0,
+  `status` varchar(20) NOT NULL,
+  `note` varchar(255) DEFAULT NULL,
+  `payment_id` int(11) DEFAULT NULL,
+  `payload` text NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_payment_gateway_events_event` (`provider`, `event_id`),
+  KEY `idx_payment_gateway_events_intent` (`intent_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
@@ -0,0 +1,32 @@
+-- Customer payments: one receipt of money from a customer, allocated across several open
+-- invoices by hand or oldest first, posted as one transaction with one deposit split.
+--
+-- Every allocation is an invoice_payments row pointing back at its customer payment through
+-- customer_payment_id, so invoice balances count it like any other payment. What is not
+-- allocated stays on the customer's receivable account as unapplied credit, and is applied to
+-- invoices later in a transaction of its own. Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS `customer_payments` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `transaction_id` int(11) NOT NULL,
+  `reference` varchar(255) NOT NULL,
+  `date` date NOT NULL,
+  `people_id` int(11) NOT NULL,
+  `unit_id` int(11) DEFAULT NULL,
+  `building_id` int(11) NOT NULL,
+  `account_id` int(11) NOT NULL,
+  `ar_account_id` int(11) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `memo` varchar(255) NOT NULL DEFAULT '',
+  `user_id` int(11) NOT NULL,
+  `status` enum('0','1') NOT NULL DEFAULT '1',
+  `version` int(11) NOT NULL DEFAULT 1,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_customer_payments_building` (`building_id`, `date`),
+  KEY `idx_customer_payments_people` (`people_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci;
+
+ALTER TABLE `invoice_payments` ADD COLUMN `customer_payment_id` int(11) DEFAULT NULL;
+CREATE INDEX `idx_invoice_payments_customer_payment` ON `invoice_payments` (`customer_payment_id`);
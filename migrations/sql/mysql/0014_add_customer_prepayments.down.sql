@@ -0,0 +1,6 @@
+-- Prepayments keep their liability account as ar_account_id so the column can be required again
+
+UPDATE `customer_payments` SET `ar_account_id` = `liability_account_id` WHERE `ar_account_id` IS NULL;
+ALTER TABLE `customer_payments`
+  DROP COLUMN `liability_account_id`,
+  MODIFY `ar_account_id` int(11) NOT NULL;
@@ -0,0 +1,8 @@
+-- Customer prepayments: money a customer pays before they are invoiced. A prepayment is a
+-- customer payment held on a customer deposits liability account (liability_account_id)
+-- instead of a receivable, so it has no ar_account_id. It is applied to invoices later like
+-- any unapplied credit, moving it from the liability to the invoice's receivable.
+
+ALTER TABLE `customer_payments`
+  MODIFY `ar_account_id` int(11) DEFAULT NULL,
+  ADD COLUMN `liability_account_id` int(11) DEFAULT NULL AFTER `ar_account_id`;
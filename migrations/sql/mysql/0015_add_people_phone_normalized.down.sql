@@ -0,0 +1,2 @@
+DROP INDEX `idx_people_phone_normalized` ON `people`;
+ALTER TABLE `people` DROP COLUMN `phone_normalized`;
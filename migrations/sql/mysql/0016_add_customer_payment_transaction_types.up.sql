@@ -0,0 +1,5 @@
+-- Customer payments post transactions of their own types: the payment, and each later
+-- application of its unapplied credit.
+
+ALTER TABLE `transactions`
+  MODIFY `type` enum('invoice','payment','check','deposit','bill','credit memo','sales receipt','journal','bill credit','bill payment','credit applied','customer payment','customer payment application') NOT NULL;
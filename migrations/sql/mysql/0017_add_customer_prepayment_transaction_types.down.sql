@@ -0,0 +1,2 @@
+ALTER TABLE `transactions`
+  MODIFY `type` enum('invoice','payment','check','deposit','bill','credit memo','sales receipt','journal','bill credit','bill payment','credit applied','customer payment','customer payment application') NOT NULL;
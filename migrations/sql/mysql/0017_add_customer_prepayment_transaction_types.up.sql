@@ -0,0 +1,5 @@
+-- Customer prepayments post transactions of their own types: the prepayment, and each
+-- application of it to an invoice.
+
+ALTER TABLE `transactions`
+  MODIFY `type` enum('invoice','payment','check','deposit','bill','credit memo','sales receipt','journal','bill credit','bill payment','credit applied','customer payment','customer payment application','customer prepayment','customer prepayment application') NOT NULL;
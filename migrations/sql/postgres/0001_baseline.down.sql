@@ -0,0 +1,26 @@
+DROP TABLE IF EXISTS "users" CASCADE;
+DROP TABLE IF EXISTS "units" CASCADE;
+DROP TABLE IF EXISTS "transactions" CASCADE;
+DROP TABLE IF EXISTS "splits" CASCADE;
+DROP TABLE IF EXISTS "sales_receipt" CASCADE;
+DROP TABLE IF EXISTS "receipt_items" CASCADE;
+DROP TABLE IF EXISTS "readings" CASCADE;
+DROP TABLE IF EXISTS "periods" CASCADE;
+DROP TABLE IF EXISTS "people_types" CASCADE;
+DROP TABLE IF EXISTS "people" CASCADE;
+DROP TABLE IF EXISTS "lease_files" CASCADE;
+DROP TABLE IF EXISTS "leases" CASCADE;
+DROP TABLE IF EXISTS "journal_lines" CASCADE;
+DROP TABLE IF EXISTS "journal" CASCADE;
+DROP TABLE IF EXISTS "items" CASCADE;
+DROP TABLE IF EXISTS "invoice_payments" CASCADE;
+DROP TABLE IF EXISTS "invoice_items" CASCADE;
+DROP TABLE IF EXISTS "invoice_applied_discounts" CASCADE;
+DROP TABLE IF EXISTS "invoice_applied_credits" CASCADE;
+DROP TABLE IF EXISTS "invoices" CASCADE;
+DROP TABLE IF EXISTS "expense_lines" CASCADE;
+DROP TABLE IF EXISTS "credit_memo" CASCADE;
+DROP TABLE IF EXISTS "checks" CASCADE;
+DROP TABLE IF EXISTS "buildings" CASCADE;
+DROP TABLE IF EXISTS "account_types" CASCADE;
+DROP TABLE IF EXISTS "accounts" CASCADE;
@@ -0,0 +1,678 @@
+-- Baseline schema converted from the MySQL baseline for PostgreSQL.
+-- Identifiers are lower case so unquoted mixed-case names in queries (typeName) resolve.
+
+CREATE TABLE "accounts" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "account_number" integer NOT NULL,
+  "account_name" varchar(50) NOT NULL,
+  "account_type" integer NOT NULL,
+  "building_id" integer NOT NULL,
+  "isdefault" smallint NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "accounts_acc_acc_type_fk" ON "accounts" ("account_type");
+
+CREATE INDEX "accounts_accounts_building_fk" ON "accounts" ("building_id");
+
+CREATE TABLE "account_types" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "typename" varchar(250) NOT NULL,
+  "type" varchar(20) NOT NULL,
+  "sub_type" varchar(20) NOT NULL,
+  "typestatus" varchar(10) NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE "buildings" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "name" varchar(250) NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "buildings_name" ON "buildings" ("name");
+
+CREATE TABLE "checks" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "transaction_id" integer NOT NULL,
+  "check_date" date NOT NULL,
+  "reference_number" varchar(50) DEFAULT NULL,
+  "payment_account_id" integer NOT NULL,
+  "building_id" integer NOT NULL,
+  "memo" text DEFAULT NULL,
+  "total_amount" numeric(10,2) DEFAULT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "checks_transaction_id" ON "checks" ("transaction_id");
+
+CREATE INDEX "checks_payment_account_id" ON "checks" ("payment_account_id");
+
+CREATE INDEX "checks_building_id" ON "checks" ("building_id");
+
+CREATE TABLE "credit_memo" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "transaction_id" integer NOT NULL,
+  "reference" varchar(255) NOT NULL,
+  "date" date NOT NULL,
+  "user_id" integer NOT NULL,
+  "deposit_to" integer NOT NULL,
+  "liability_account" integer NOT NULL,
+  "people_id" integer NOT NULL,
+  "building_id" integer NOT NULL,
+  "unit_id" integer NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "description" text NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "credit_memo_cm_transaction_id" ON "credit_memo" ("transaction_id");
+
+CREATE INDEX "credit_memo_cm_user_id" ON "credit_memo" ("user_id");
+
+CREATE INDEX "credit_memo_cm_deposit_to" ON "credit_memo" ("deposit_to");
+
+CREATE INDEX "credit_memo_cm_liability_account" ON "credit_memo" ("liability_account");
+
+CREATE INDEX "credit_memo_cm_people_id" ON "credit_memo" ("people_id");
+
+CREATE INDEX "credit_memo_cm_building_id" ON "credit_memo" ("building_id");
+
+CREATE INDEX "credit_memo_cm_unit_id" ON "credit_memo" ("unit_id");
+
+CREATE TABLE "expense_lines" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "check_id" integer NOT NULL,
+  "account_id" integer NOT NULL,
+  "unit_id" integer DEFAULT NULL,
+  "people_id" integer DEFAULT NULL,
+  "description" text DEFAULT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "expense_lines_check_id" ON "expense_lines" ("check_id");
+
+CREATE INDEX "expense_lines_account_id" ON "expense_lines" ("account_id");
+
+CREATE INDEX "expense_lines_unit_id" ON "expense_lines" ("unit_id");
+
+CREATE INDEX "expense_lines_people_id" ON "expense_lines" ("people_id");
+
+CREATE TABLE "invoices" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "invoice_no" varchar(255) NOT NULL,
+  "transaction_id" integer NOT NULL,
+  "sales_date" date NOT NULL,
+  "due_date" date NOT NULL,
+  "ar_account_id" integer NOT NULL,
+  "unit_id" integer DEFAULT NULL,
+  "people_id" integer DEFAULT NULL,
+  "user_id" integer NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "description" text NOT NULL,
+  "cancel_reason" text DEFAULT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "building_id" integer NOT NULL,
+  "createdat" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedat" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "invoices_invoice_unit_id" ON "invoices" ("unit_id");
+
+CREATE INDEX "invoices_invoice_people_id" ON "invoices" ("people_id");
+
+CREATE INDEX "invoices_invoice_user_id" ON "invoices" ("user_id");
+
+CREATE INDEX "invoices_invoice_building_id" ON "invoices" ("building_id");
+
+CREATE INDEX "invoices_fk_invoice_account_id" ON "invoices" ("ar_account_id");
+
+CREATE TABLE "invoice_applied_credits" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "invoice_id" integer NOT NULL,
+  "credit_memo_id" integer NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "description" text NOT NULL,
+  "date" date NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "invoice_applied_credits_iac_invoice_id" ON "invoice_applied_credits" ("invoice_id");
+
+CREATE INDEX "invoice_applied_credits_iac_credit_memo_id" ON "invoice_applied_credits" ("credit_memo_id");
+
+CREATE TABLE "invoice_applied_discounts" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "reference" varchar(255) NOT NULL,
+  "invoice_id" integer NOT NULL,
+  "transaction_id" integer NOT NULL,
+  "ar_account" integer NOT NULL,
+  "income_account" integer NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "description" text NOT NULL,
+  "date" date NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "invoice_applied_discounts_iad_invoice_id" ON "invoice_applied_discounts" ("invoice_id");
+
+CREATE INDEX "invoice_applied_discounts_iad_transaction_id" ON "invoice_applied_discounts" ("transaction_id");
+
+CREATE INDEX "invoice_applied_discounts_iad_ar_account" ON "invoice_applied_discounts" ("ar_account");
+
+CREATE INDEX "invoice_applied_discounts_iad_income_account" ON "invoice_applied_discounts" ("income_account");
+
+CREATE TABLE "invoice_items" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "invoice_id" integer NOT NULL,
+  "item_id" integer NOT NULL,
+  "item_name" varchar(250) NOT NULL,
+  "previous_value" numeric(10,3) DEFAULT NULL,
+  "current_value" numeric(10,3) DEFAULT NULL,
+  "qty" numeric(10,3) DEFAULT NULL,
+  "rate" varchar(100) DEFAULT NULL,
+  "total" numeric(10,2) NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "invoice_items_invoice_items_inv_id" ON "invoice_items" ("invoice_id");
+
+CREATE INDEX "invoice_items_invoice_items_item_id" ON "invoice_items" ("item_id");
+
+CREATE TABLE "invoice_payments" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "transaction_id" integer NOT NULL,
+  "reference" varchar(255) NOT NULL,
+  "date" date NOT NULL,
+  "invoice_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "account_id" integer NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "createdat" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedat" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "invoice_payments_ip_transaction_id" ON "invoice_payments" ("transaction_id");
+
+CREATE INDEX "invoice_payments_ip_invoice_id" ON "invoice_payments" ("invoice_id");
+
+CREATE INDEX "invoice_payments_ip_user_id" ON "invoice_payments" ("user_id");
+
+CREATE INDEX "invoice_payments_ip_account_id" ON "invoice_payments" ("account_id");
+
+CREATE TABLE "items" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "name" varchar(250) NOT NULL,
+  "type" varchar(13) NOT NULL,
+  "description" text NOT NULL,
+  "asset_account" integer DEFAULT NULL,
+  "income_account" integer DEFAULT NULL,
+  "cogs_account" integer DEFAULT NULL,
+  "expense_account" integer DEFAULT NULL,
+  "on_hand" numeric(10,2) NOT NULL,
+  "avg_cost" numeric(10,2) NOT NULL,
+  "date" date NOT NULL,
+  "building_id" integer NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "items_item_asset_account_pk" ON "items" ("asset_account");
+
+CREATE INDEX "items_item_income_account_pk" ON "items" ("income_account");
+
+CREATE INDEX "items_item_cogs_account_pk" ON "items" ("cogs_account");
+
+CREATE INDEX "items_item_expense_account_pk" ON "items" ("expense_account");
+
+CREATE INDEX "items_item_building_fk" ON "items" ("building_id");
+
+CREATE TABLE "journal" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "transaction_id" integer NOT NULL,
+  "reference" varchar(255) NOT NULL,
+  "journal_date" date NOT NULL,
+  "building_id" integer NOT NULL,
+  "memo" text DEFAULT NULL,
+  "total_amount" numeric(10,2) DEFAULT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "journal_transaction_id" ON "journal" ("transaction_id");
+
+CREATE INDEX "journal_building_id" ON "journal" ("building_id");
+
+CREATE TABLE "journal_lines" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "journal_id" integer NOT NULL,
+  "account_id" integer NOT NULL,
+  "unit_id" integer DEFAULT NULL,
+  "people_id" integer DEFAULT NULL,
+  "description" text DEFAULT NULL,
+  "debit" numeric(10,2) DEFAULT 0.00,
+  "credit" numeric(10,2) DEFAULT 0.00,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "journal_lines_journal_id" ON "journal_lines" ("journal_id");
+
+CREATE INDEX "journal_lines_account_id" ON "journal_lines" ("account_id");
+
+CREATE INDEX "journal_lines_unit_id" ON "journal_lines" ("unit_id");
+
+CREATE INDEX "journal_lines_people_id" ON "journal_lines" ("people_id");
+
+CREATE TABLE "leases" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "people_id" integer NOT NULL,
+  "building_id" integer NOT NULL,
+  "unit_id" integer NOT NULL,
+  "start_date" date NOT NULL,
+  "end_date" date DEFAULT NULL,
+  "rent_amount" numeric(10,2) NOT NULL,
+  "deposit_amount" numeric(10,2) NOT NULL,
+  "service_amount" numeric(10,2) NOT NULL,
+  "lease_terms" text NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "leases_idx_people_id" ON "leases" ("people_id");
+
+CREATE INDEX "leases_idx_building_id" ON "leases" ("building_id");
+
+CREATE INDEX "leases_idx_unit_id" ON "leases" ("unit_id");
+
+CREATE TABLE "lease_files" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "lease_id" integer NOT NULL,
+  "filename" varchar(255) NOT NULL,
+  "original_name" varchar(255) NOT NULL,
+  "file_path" varchar(500) NOT NULL,
+  "file_type" varchar(100) NOT NULL,
+  "file_size" integer NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "lease_files_idx_lease_id" ON "lease_files" ("lease_id");
+
+CREATE TABLE "people" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "name" varchar(255) NOT NULL,
+  "phone" varchar(20) NOT NULL,
+  "type_id" integer NOT NULL,
+  "building_id" integer NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "people_people_type_id_fk" ON "people" ("type_id");
+
+CREATE INDEX "people_people_building_id_fk" ON "people" ("building_id");
+
+CREATE TABLE "people_types" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "title" varchar(50) NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE "periods" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "period_name" varchar(50) NOT NULL,
+  "start" date NOT NULL,
+  "end" date NOT NULL,
+  "building_id" integer NOT NULL,
+  "is_closed" integer NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "periods_period_building_id" ON "periods" ("building_id");
+
+CREATE TABLE "readings" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "item_id" integer NOT NULL,
+  "unit_id" integer NOT NULL,
+  "lease_id" integer DEFAULT NULL,
+  "reading_month" varchar(10) DEFAULT NULL,
+  "reading_year" varchar(5) DEFAULT NULL,
+  "reading_date" date NOT NULL,
+  "previous_value" numeric(10,3) DEFAULT NULL,
+  "current_value" numeric(10,3) DEFAULT NULL,
+  "unit_price" numeric(10,2) DEFAULT NULL,
+  "total_amount" numeric(10,2) DEFAULT NULL,
+  "notes" text DEFAULT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "readings_idx_item_id" ON "readings" ("item_id");
+
+CREATE INDEX "readings_idx_unit_id" ON "readings" ("unit_id");
+
+CREATE INDEX "readings_idx_lease_id" ON "readings" ("lease_id");
+
+CREATE TABLE "receipt_items" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "receipt_id" integer NOT NULL,
+  "item_id" integer NOT NULL,
+  "item_name" varchar(250) NOT NULL,
+  "previous_value" numeric(10,3) DEFAULT NULL,
+  "current_value" numeric(10,3) DEFAULT NULL,
+  "qty" numeric(10,2) DEFAULT NULL,
+  "rate" varchar(100) DEFAULT NULL,
+  "total" numeric(10,2) NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "receipt_items_sri_receipt_id" ON "receipt_items" ("receipt_id");
+
+CREATE INDEX "receipt_items_sri_item_id" ON "receipt_items" ("item_id");
+
+CREATE TABLE "sales_receipt" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "receipt_no" integer NOT NULL,
+  "transaction_id" integer NOT NULL,
+  "receipt_date" date NOT NULL,
+  "unit_id" integer DEFAULT NULL,
+  "people_id" integer DEFAULT NULL,
+  "user_id" integer NOT NULL,
+  "account_id" integer NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "description" text DEFAULT NULL,
+  "cancel_reason" text DEFAULT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "building_id" integer NOT NULL,
+  "createdat" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedat" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "sales_receipt_sr_unit_id" ON "sales_receipt" ("unit_id");
+
+CREATE INDEX "sales_receipt_sr_people_id" ON "sales_receipt" ("people_id");
+
+CREATE INDEX "sales_receipt_sr_user_id" ON "sales_receipt" ("user_id");
+
+CREATE INDEX "sales_receipt_sr_building_id" ON "sales_receipt" ("building_id");
+
+CREATE INDEX "sales_receipt_sr_account_id" ON "sales_receipt" ("account_id");
+
+CREATE TABLE "splits" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "transaction_id" integer NOT NULL,
+  "account_id" integer NOT NULL,
+  "people_id" integer DEFAULT NULL,
+  "unit_id" integer DEFAULT NULL,
+  "debit" numeric(10,2) DEFAULT NULL,
+  "credit" numeric(10,2) DEFAULT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "splits_td_transaction_id" ON "splits" ("transaction_id");
+
+CREATE INDEX "splits_td_account_id" ON "splits" ("account_id");
+
+CREATE INDEX "splits_td_people_id" ON "splits" ("people_id");
+
+CREATE INDEX "splits_fk_splits_unit" ON "splits" ("unit_id");
+
+CREATE TABLE "transactions" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "type" varchar(14) NOT NULL,
+  "transaction_date" date NOT NULL,
+  "transaction_number" varchar(255) NOT NULL,
+  "memo" text NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "building_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "unit_id" integer DEFAULT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "transactions_transaction_type_fk" ON "transactions" ("type");
+
+CREATE INDEX "transactions_transaction_building_fk" ON "transactions" ("building_id");
+
+CREATE INDEX "transactions_transaction_user_fk" ON "transactions" ("user_id");
+
+CREATE INDEX "transactions_transaction_unit_fk" ON "transactions" ("unit_id");
+
+CREATE TABLE "units" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "name" varchar(20) NOT NULL,
+  "building_id" integer NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "units_unit_building_id_fk" ON "units" ("building_id");
+
+CREATE TABLE "users" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "name" varchar(50) NOT NULL,
+  "username" varchar(20) NOT NULL,
+  "phone" varchar(20) NOT NULL,
+  "password" varchar(10) NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+ALTER TABLE "accounts"
+  ADD CONSTRAINT "acc_acc_type_fk" FOREIGN KEY ("account_type") REFERENCES "account_types" ("id");
+
+ALTER TABLE "accounts"
+  ADD CONSTRAINT "accounts_building_fk" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id");
+
+ALTER TABLE "checks"
+  ADD CONSTRAINT "checks_ibfk_1" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "checks"
+  ADD CONSTRAINT "checks_ibfk_2" FOREIGN KEY ("payment_account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "checks"
+  ADD CONSTRAINT "checks_ibfk_3" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "credit_memo"
+  ADD CONSTRAINT "fk_cm_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "credit_memo"
+  ADD CONSTRAINT "fk_cm_deposit_to" FOREIGN KEY ("deposit_to") REFERENCES "accounts" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "credit_memo"
+  ADD CONSTRAINT "fk_cm_liability_account" FOREIGN KEY ("liability_account") REFERENCES "accounts" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "credit_memo"
+  ADD CONSTRAINT "fk_cm_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "credit_memo"
+  ADD CONSTRAINT "fk_cm_transaction" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "credit_memo"
+  ADD CONSTRAINT "fk_cm_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "credit_memo"
+  ADD CONSTRAINT "fk_cm_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "expense_lines"
+  ADD CONSTRAINT "expense_lines_ibfk_1" FOREIGN KEY ("check_id") REFERENCES "checks" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "expense_lines"
+  ADD CONSTRAINT "expense_lines_ibfk_2" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "expense_lines"
+  ADD CONSTRAINT "expense_lines_ibfk_3" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "expense_lines"
+  ADD CONSTRAINT "expense_lines_ibfk_4" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "invoices"
+  ADD CONSTRAINT "fk_invoice_account_id" FOREIGN KEY ("ar_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "invoices"
+  ADD CONSTRAINT "fk_invoice_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "invoices"
+  ADD CONSTRAINT "fk_invoice_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "invoices"
+  ADD CONSTRAINT "fk_invoice_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "invoice_applied_credits"
+  ADD CONSTRAINT "fk_iac_credit_memo" FOREIGN KEY ("credit_memo_id") REFERENCES "credit_memo" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "invoice_applied_credits"
+  ADD CONSTRAINT "fk_iac_invoice" FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "invoice_applied_discounts"
+  ADD CONSTRAINT "fk_iad_ar_account" FOREIGN KEY ("ar_account") REFERENCES "accounts" ("id");
+
+ALTER TABLE "invoice_applied_discounts"
+  ADD CONSTRAINT "fk_iad_income_account" FOREIGN KEY ("income_account") REFERENCES "accounts" ("id");
+
+ALTER TABLE "invoice_applied_discounts"
+  ADD CONSTRAINT "fk_iad_invoice" FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id");
+
+ALTER TABLE "invoice_applied_discounts"
+  ADD CONSTRAINT "fk_iad_transaction" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+
+ALTER TABLE "invoice_payments"
+  ADD CONSTRAINT "fk_ip_account" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "invoice_payments"
+  ADD CONSTRAINT "fk_ip_invoice" FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "invoice_payments"
+  ADD CONSTRAINT "fk_ip_transaction" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "invoice_payments"
+  ADD CONSTRAINT "fk_ip_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "items"
+  ADD CONSTRAINT "fk_items_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "journal"
+  ADD CONSTRAINT "journal_ibfk_1" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "journal"
+  ADD CONSTRAINT "journal_ibfk_2" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "journal_lines"
+  ADD CONSTRAINT "journal_lines_ibfk_1" FOREIGN KEY ("journal_id") REFERENCES "journal" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "journal_lines"
+  ADD CONSTRAINT "journal_lines_ibfk_2" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "journal_lines"
+  ADD CONSTRAINT "journal_lines_ibfk_3" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "journal_lines"
+  ADD CONSTRAINT "journal_lines_ibfk_4" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "leases"
+  ADD CONSTRAINT "fk_leases_buildings" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "leases"
+  ADD CONSTRAINT "fk_leases_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "leases"
+  ADD CONSTRAINT "fk_leases_units" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "lease_files"
+  ADD CONSTRAINT "fk_lease_files_lease_id" FOREIGN KEY ("lease_id") REFERENCES "leases" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "people"
+  ADD CONSTRAINT "people_building_id_fk" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id");
+
+ALTER TABLE "people"
+  ADD CONSTRAINT "people_type_id_fk" FOREIGN KEY ("type_id") REFERENCES "people_types" ("id");
+
+ALTER TABLE "periods"
+  ADD CONSTRAINT "period_building_id" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id");
+
+ALTER TABLE "readings"
+  ADD CONSTRAINT "fk_readings_item" FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "readings"
+  ADD CONSTRAINT "fk_readings_lease" FOREIGN KEY ("lease_id") REFERENCES "leases" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "readings"
+  ADD CONSTRAINT "fk_readings_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "receipt_items"
+  ADD CONSTRAINT "fk_sri_item" FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "receipt_items"
+  ADD CONSTRAINT "fk_sri_receipt" FOREIGN KEY ("receipt_id") REFERENCES "sales_receipt" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "sales_receipt"
+  ADD CONSTRAINT "fk_sr_account" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE;
+
+ALTER TABLE "sales_receipt"
+  ADD CONSTRAINT "fk_sr_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "sales_receipt"
+  ADD CONSTRAINT "fk_sr_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "sales_receipt"
+  ADD CONSTRAINT "fk_sr_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "splits"
+  ADD CONSTRAINT "fk_splits_account" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "splits"
+  ADD CONSTRAINT "fk_splits_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE "splits"
+  ADD CONSTRAINT "fk_splits_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id");
+
+ALTER TABLE "transactions"
+  ADD CONSTRAINT "fk_transactions_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "transactions"
+  ADD CONSTRAINT "fk_transactions_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "transactions"
+  ADD CONSTRAINT "fk_transactions_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE "units"
+  ADD CONSTRAINT "unit_building_id_fk" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id");
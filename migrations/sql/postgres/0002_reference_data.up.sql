@@ -0,0 +1,27 @@
+-- Reference data the application looks up by name (people types by title,
+-- account types by typeName/type/typeStatus).
+
+INSERT INTO "people_types" ("id", "title") VALUES
+(1, 'customer'),
+(2, 'vendor'),
+(3, 'employee'),
+(6, 'others')
+ON CONFLICT ("id") DO NOTHING;
+
+INSERT INTO "account_types" ("id", "typename", "type", "sub_type", "typestatus") VALUES
+(1, 'Bank', 'Asset', 'current asset', 'debit'),
+(2, 'Account Receivable', 'Asset', 'current asset', 'debit'),
+(3, 'Other current asset', 'Asset', 'current asset', 'debit'),
+(4, 'Cost of goods sold', 'Expense', '', 'debit'),
+(5, 'Expense', 'Expense', '', 'debit'),
+(6, 'Account Payable', 'Liability', '', 'credit'),
+(7, 'Other Liability', 'Liability', '', 'credit'),
+(8, 'Equity', 'Equity', '', 'credit'),
+(9, 'Income', 'Income', '', 'credit'),
+(10, 'Fixed Asset', 'Asset', 'fixed asset', 'debit')
+ON CONFLICT ("id") DO NOTHING;
+
+-- Explicit ids do not advance identity sequences
+SELECT setval(pg_get_serial_sequence('people_types', 'id'), (SELECT MAX("id") FROM "people_types"));
+
+SELECT setval(pg_get_serial_sequence('account_types', 'id'), (SELECT MAX("id") FROM "account_types"));
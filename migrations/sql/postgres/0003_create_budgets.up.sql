@@ -0,0 +1,19 @@
+-- One row per account, optional unit, year and month.
+-- unit_id NULL means the amount is budgeted for the whole building.
+
+CREATE TABLE IF NOT EXISTS "budgets" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "account_id" integer NOT NULL,
+  "unit_id" integer DEFAULT NULL,
+  "year" integer NOT NULL,
+  "month" integer NOT NULL,
+  "amount" numeric(15,2) NOT NULL DEFAULT 0.00,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_budgets_building_year" ON "budgets" ("building_id", "year");
+
+CREATE INDEX IF NOT EXISTS "idx_budgets_account" ON "budgets" ("account_id");
@@ -0,0 +1,23 @@
+-- Responses of POST requests sent with an Idempotency-Key header, one row per user and key.
+-- locked_until and expires_at are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "user_id" integer NOT NULL,
+  "idempotency_key" varchar(255) NOT NULL,
+  "method" varchar(10) NOT NULL,
+  "path" varchar(255) NOT NULL,
+  "request_hash" char(64) NOT NULL,
+  "status" varchar(20) NOT NULL DEFAULT 'processing',
+  "response_status" integer DEFAULT NULL,
+  "response_content_type" varchar(255) DEFAULT NULL,
+  "response_body" text DEFAULT NULL,
+  "locked_until" timestamp NOT NULL,
+  "expires_at" timestamp NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_idempotency_keys_user_key" ON "idempotency_keys" ("user_id", "idempotency_key");
+
+CREATE INDEX IF NOT EXISTS "idx_idempotency_keys_expires_at" ON "idempotency_keys" ("expires_at");
@@ -0,0 +1,11 @@
+-- Row versions for optimistic concurrency. Every update of a document must name the version
+-- it read (If-Match) and increments it.
+
+ALTER TABLE "invoices" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "sales_receipt" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "invoice_payments" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "checks" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "credit_memo" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "journal" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "leases" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+ALTER TABLE "readings" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
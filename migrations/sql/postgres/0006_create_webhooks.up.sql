@@ -0,0 +1,56 @@
+-- Domain events are written to outbox_events in the same transaction as the change they
+-- describe. The dispatcher copies each event into webhook_deliveries, one row per matching
+-- subscription, and sets dispatched_at. Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "outbox_events" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "event_type" varchar(100) NOT NULL,
+  "aggregate_type" varchar(50) NOT NULL,
+  "aggregate_id" integer NOT NULL,
+  "payload" text NOT NULL,
+  "created_at" timestamp NOT NULL,
+  "dispatched_at" timestamp DEFAULT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_dispatched_at" ON "outbox_events" ("dispatched_at");
+
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_building" ON "outbox_events" ("building_id", "id");
+
+-- event_types is a comma separated list; empty means every event of the building
+CREATE TABLE IF NOT EXISTS "webhook_subscriptions" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "url" varchar(2048) NOT NULL,
+  "secret" varchar(255) NOT NULL,
+  "event_types" text NOT NULL,
+  "status" varchar(20) NOT NULL DEFAULT 'active',
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_webhook_subscriptions_building" ON "webhook_subscriptions" ("building_id");
+
+-- status is pending until delivered, or dead once the attempts are used up
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "event_id" integer NOT NULL,
+  "subscription_id" integer NOT NULL,
+  "status" varchar(20) NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamp NOT NULL,
+  "last_status_code" integer DEFAULT NULL,
+  "last_error" text DEFAULT NULL,
+  "delivered_at" timestamp DEFAULT NULL,
+  "created_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_webhook_deliveries_event_subscription" ON "webhook_deliveries" ("event_id", "subscription_id");
+
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_due" ON "webhook_deliveries" ("status", "next_attempt_at");
+
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_subscription" ON "webhook_deliveries" ("subscription_id");
@@ -0,0 +1,48 @@
+-- Scheduled jobs run in-process. A job is claimed by one application instance at a time
+-- through locked_by/locked_until, and every execution is recorded in job_runs. Times are
+-- UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "scheduled_jobs" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "name" varchar(100) NOT NULL,
+  "job_type" varchar(50) NOT NULL,
+  "schedule" varchar(100) NOT NULL,
+  "timezone" varchar(64) NOT NULL DEFAULT 'UTC',
+  "params" text NOT NULL,
+  "status" varchar(20) NOT NULL DEFAULT 'active',
+  "next_run_at" timestamp DEFAULT NULL,
+  "last_run_at" timestamp DEFAULT NULL,
+  "last_run_status" varchar(20) DEFAULT NULL,
+  "locked_by" varchar(100) DEFAULT NULL,
+  "locked_until" timestamp DEFAULT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_scheduled_jobs_due" ON "scheduled_jobs" ("status", "next_run_at");
+
+CREATE INDEX IF NOT EXISTS "idx_scheduled_jobs_building" ON "scheduled_jobs" ("building_id");
+
+-- status moves queued -> running -> succeeded, failed or cancelled
+CREATE TABLE IF NOT EXISTS "job_runs" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "job_id" integer NOT NULL,
+  "building_id" integer NOT NULL,
+  "triggered_by" varchar(20) NOT NULL,
+  "user_id" integer DEFAULT NULL,
+  "status" varchar(20) NOT NULL,
+  "cancel_requested" smallint NOT NULL DEFAULT 0,
+  "runner" varchar(100) DEFAULT NULL,
+  "output" text DEFAULT NULL,
+  "error" text DEFAULT NULL,
+  "queued_at" timestamp NOT NULL,
+  "started_at" timestamp DEFAULT NULL,
+  "finished_at" timestamp DEFAULT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_job_runs_job" ON "job_runs" ("job_id", "id");
+
+CREATE INDEX IF NOT EXISTS "idx_job_runs_status" ON "job_runs" ("status");
@@ -0,0 +1,21 @@
+-- Per-building print settings of each document type (invoice, sales_receipt, payment,
+-- credit_memo). A type without a row prints with the defaults. logo_path is relative to
+-- the upload root.
+
+CREATE TABLE IF NOT EXISTS "document_templates" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "document_type" varchar(30) NOT NULL,
+  "title" varchar(100) NOT NULL,
+  "business_name" varchar(255) NOT NULL DEFAULT '',
+  "address" text NOT NULL,
+  "footer_text" text NOT NULL,
+  "number_prefix" varchar(20) NOT NULL DEFAULT '',
+  "number_padding" integer NOT NULL DEFAULT 0,
+  "logo_path" varchar(255) DEFAULT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_document_templates_building_type" ON "document_templates" ("building_id", "document_type");
@@ -0,0 +1,64 @@
+-- Email and SMS notifications. people.email is optional; a person without an address or phone
+-- is skipped on that channel.
+--
+-- notification_templates override the built-in subject and body of a category on a channel.
+-- notification_preferences hold the channels a person wants per category as a comma separated
+-- list; a category without a row goes to every channel the person has contact details for.
+-- notifications is the delivery log: status is pending until sent, or failed once the attempts
+-- are used up. Times are UTC and written by the application.
+
+ALTER TABLE "people" ADD COLUMN "email" varchar(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS "notification_templates" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "category" varchar(30) NOT NULL,
+  "channel" varchar(10) NOT NULL,
+  "subject" varchar(255) NOT NULL DEFAULT '',
+  "body" text NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_notification_templates_building_category_channel" ON "notification_templates" ("building_id", "category", "channel");
+
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "people_id" integer NOT NULL,
+  "category" varchar(30) NOT NULL,
+  "channels" varchar(30) NOT NULL DEFAULT '',
+  "updated_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_notification_preferences_people_category" ON "notification_preferences" ("people_id", "category");
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "people_id" integer NOT NULL,
+  "category" varchar(30) NOT NULL,
+  "channel" varchar(10) NOT NULL,
+  "recipient" varchar(255) NOT NULL,
+  "subject" varchar(255) NOT NULL DEFAULT '',
+  "body" text NOT NULL,
+  "reference_type" varchar(30) DEFAULT NULL,
+  "reference_id" integer DEFAULT NULL,
+  "attachment_name" varchar(255) DEFAULT NULL,
+  "attachment_path" varchar(255) DEFAULT NULL,
+  "status" varchar(20) NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamp NOT NULL,
+  "last_error" text DEFAULT NULL,
+  "sent_at" timestamp DEFAULT NULL,
+  "created_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_due" ON "notifications" ("status", "next_attempt_at");
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_building" ON "notifications" ("building_id", "id");
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_reference" ON "notifications" ("reference_type", "reference_id");
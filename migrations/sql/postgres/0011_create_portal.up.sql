@@ -0,0 +1,32 @@
+-- Tenant portal sign-in. A tenant asks for a code by phone number; portal_codes keeps a
+-- hash of each code sent with its wrong guesses. A correct code opens a session for one
+-- people record; portal_sessions keeps a hash of the bearer token, never the token itself.
+-- Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "portal_codes" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "phone" varchar(30) NOT NULL,
+  "code_hash" varchar(64) NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "expires_at" timestamp NOT NULL,
+  "used_at" timestamp DEFAULT NULL,
+  "created_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_portal_codes_phone" ON "portal_codes" ("phone", "id");
+
+CREATE TABLE IF NOT EXISTS "portal_sessions" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "token_hash" varchar(64) NOT NULL,
+  "people_id" integer NOT NULL,
+  "building_id" integer NOT NULL,
+  "expires_at" timestamp NOT NULL,
+  "revoked_at" timestamp DEFAULT NULL,
+  "created_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_portal_sessions_token" ON "portal_sessions" ("token_hash");
+
+CREATE INDEX IF NOT EXISTS "idx_portal_sessions_people" ON "portal_sessions" ("people_id");
@@ -0,0 +1,66 @@
+-- Online payments through a payment gateway, e.g. mobile money or cards.
+--
+-- payment_gateway_settings holds, per building, the clearing account gateway payments are
+-- received into and the user they are recorded by.
+-- payment_intents are pending payments of an invoice handed to the gateway; paid_amount is
+-- what the gateway reported paid, less reversals.
+-- payment_gateway_events records every webhook event once per provider event id, so that
+-- redelivered events are recognised, with the invoice payment it created. Times are UTC and
+-- written by the application.
+
+CREATE TABLE IF NOT EXISTS "payment_gateway_settings" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "clearing_account_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "status" varchar(20) NOT NULL DEFAULT 'active',
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_payment_gateway_settings_building" ON "payment_gateway_settings" ("building_id");
+
+CREATE TABLE IF NOT EXISTS "payment_intents" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "building_id" integer NOT NULL,
+  "invoice_id" integer NOT NULL,
+  "people_id" integer NOT NULL,
+  "provider" varchar(30) NOT NULL,
+  "provider_reference" varchar(100) NOT NULL,
+  "checkout_url" varchar(500) NOT NULL DEFAULT '',
+  "amount" numeric(10,2) NOT NULL,
+  "paid_amount" numeric(10,2) NOT NULL DEFAULT 0,
+  "status" varchar(20) NOT NULL,
+  "source" varchar(20) NOT NULL,
+  "user_id" integer DEFAULT NULL,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_payment_intents_reference" ON "payment_intents" ("provider", "provider_reference");
+
+CREATE INDEX IF NOT EXISTS "idx_payment_intents_invoice" ON "payment_intents" ("invoice_id");
+
+CREATE INDEX IF NOT EXISTS "idx_payment_intents_building" ON "payment_intents" ("building_id", "id");
+
+CREATE TABLE IF NOT EXISTS "payment_gateway_events" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "provider" varchar(30) NOT NULL,
+  "event_id" varchar(100) NOT NULL,
+  "intent_id" integer DEFAULT NULL,
+  "type" varchar(20) NOT NULL,
+  "amount" numeric(10,2) NOT NULL DEFAULT 0,
+  "status" varchar(20) NOT NULL,
+  "note" varchar(255) DEFAULT NULL,
+  "payment_id" integer DEFAULT NULL,
+  "payload" text NOT NULL,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_payment_gateway_events_event" ON "payment_gateway_events" ("provider", "event_id");
+
+CREATE INDEX IF NOT EXISTS "idx_payment_gateway_events_intent" ON "payment_gateway_events" ("intent_id");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_invoice_payments_customer_payment";
+ALTER TABLE "invoice_payments" DROP COLUMN "customer_payment_id";
+DROP TABLE IF EXISTS "customer_payments";
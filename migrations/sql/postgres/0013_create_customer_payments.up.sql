@@ -0,0 +1,33 @@
+-- Customer payments: one receipt of money from a customer, allocated across several open
+-- invoices by hand or oldest first, posted as one transaction with one deposit split.
+--
+-- Every allocation is an invoice_payments row pointing back at its customer payment through
+-- customer_payment_id, so invoice balances count it like any other payment. What is not
+-- allocated stays on the customer's receivable account as unapplied credit, and is applied to
+-- invoices later in a transaction of its own. Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "customer_payments" (
+  "id" integer GENERATED BY DEFAULT AS IDENTITY,
+  "transaction_id" integer NOT NULL,
+  "reference" varchar(255) NOT NULL,
+  "date" date NOT NULL,
+  "people_id" integer NOT NULL,
+  "unit_id" integer DEFAULT NULL,
+  "building_id" integer NOT NULL,
+  "account_id" integer NOT NULL,
+  "ar_account_id" integer NOT NULL,
+  "amount" numeric(10,2) NOT NULL,
+  "memo" varchar(255) NOT NULL DEFAULT '',
+  "user_id" integer NOT NULL,
+  "status" varchar(1) NOT NULL DEFAULT '1',
+  "version" integer NOT NULL DEFAULT 1,
+  "created_at" timestamp NOT NULL,
+  "updated_at" timestamp NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_customer_payments_building" ON "customer_payments" ("building_id", "date");
+CREATE INDEX IF NOT EXISTS "idx_customer_payments_people" ON "customer_payments" ("people_id");
+
+ALTER TABLE "invoice_payments" ADD COLUMN "customer_payment_id" integer DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS "idx_invoice_payments_customer_payment" ON "invoice_payments" ("customer_payment_id");
@@ -0,0 +1,5 @@
+-- Prepayments keep their liability account as ar_account_id so the column can be required again
+
+UPDATE "customer_payments" SET "ar_account_id" = "liability_account_id" WHERE "ar_account_id" IS NULL;
+ALTER TABLE "customer_payments" DROP COLUMN "liability_account_id";
+ALTER TABLE "customer_payments" ALTER COLUMN "ar_account_id" SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Customer prepayments: money a customer pays before they are invoiced. A prepayment is a
+-- customer payment held on a customer deposits liability account (liability_account_id)
+-- instead of a receivable, so it has no ar_account_id. It is applied to invoices later like
+-- any unapplied credit, moving it from the liability to the invoice's receivable.
+
+ALTER TABLE "customer_payments" ALTER COLUMN "ar_account_id" DROP NOT NULL;
+ALTER TABLE "customer_payments" ADD COLUMN "liability_account_id" integer DEFAULT NULL;
@@ -0,0 +1,9 @@
+-- people.phone_normalized holds the digits of people.phone, which the tenant portal looks a
+-- phone number up by, so signing in doesn't scan every person. The application writes it with
+-- the phone; existing numbers are filled in here by dropping the usual separators and a
+-- leading 00. A number with other characters is normalized when the person is next saved.
+
+ALTER TABLE "people" ADD COLUMN "phone_normalized" varchar(30) NOT NULL DEFAULT '';
+UPDATE "people" SET "phone_normalized" = REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE("phone", ' ', ''), '-', ''), '+', ''), '(', ''), ')', ''), '.', ''), '/', '');
+UPDATE "people" SET "phone_normalized" = SUBSTR("phone_normalized", 3) WHERE "phone_normalized" LIKE '00%';
+CREATE INDEX IF NOT EXISTS "idx_people_phone_normalized" ON "people" ("phone_normalized");
@@ -0,0 +1 @@
+ALTER TABLE "transactions" ALTER COLUMN "type" TYPE varchar(14);
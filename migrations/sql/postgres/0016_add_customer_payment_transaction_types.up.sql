@@ -0,0 +1,4 @@
+-- Customer payments post transactions of their own types: the payment, and each later
+-- application of its unapplied credit. Their names don't fit the baseline's varchar(14).
+
+ALTER TABLE "transactions" ALTER COLUMN "type" TYPE varchar(40);
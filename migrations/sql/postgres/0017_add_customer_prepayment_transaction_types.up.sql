@@ -0,0 +1,3 @@
+-- Customer prepayments post transactions of their own types: the prepayment, and each
+-- application of it to an invoice. 0016 widened transactions.type to varchar(40), which
+-- holds them; this migration keeps the version numbers in step with MySQL.
@@ -0,0 +1,531 @@
+-- Baseline schema converted from the MySQL baseline for SQLite.
+
+CREATE TABLE "accounts" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "account_number" INTEGER NOT NULL,
+  "account_name" VARCHAR(50) NOT NULL,
+  "account_type" INTEGER NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "isDefault" INTEGER NOT NULL,
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "acc_acc_type_fk" FOREIGN KEY ("account_type") REFERENCES "account_types" ("id"),
+  CONSTRAINT "accounts_building_fk" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id")
+);
+
+CREATE INDEX "accounts_acc_acc_type_fk" ON "accounts" ("account_type");
+
+CREATE INDEX "accounts_accounts_building_fk" ON "accounts" ("building_id");
+
+CREATE TABLE "account_types" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "typeName" VARCHAR(250) NOT NULL,
+  "type" VARCHAR(20) NOT NULL,
+  "sub_type" VARCHAR(20) NOT NULL,
+  "typeStatus" VARCHAR(10) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "buildings" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(250) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX "buildings_name" ON "buildings" ("name");
+
+CREATE TABLE "checks" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "transaction_id" INTEGER NOT NULL,
+  "check_date" DATE NOT NULL,
+  "reference_number" VARCHAR(50) DEFAULT NULL,
+  "payment_account_id" INTEGER NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "memo" TEXT DEFAULT NULL,
+  "total_amount" DECIMAL(10,2) DEFAULT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "checks_ibfk_1" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "checks_ibfk_2" FOREIGN KEY ("payment_account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "checks_ibfk_3" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX "checks_transaction_id" ON "checks" ("transaction_id");
+
+CREATE INDEX "checks_payment_account_id" ON "checks" ("payment_account_id");
+
+CREATE INDEX "checks_building_id" ON "checks" ("building_id");
+
+CREATE TABLE "credit_memo" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "transaction_id" INTEGER NOT NULL,
+  "reference" VARCHAR(255) NOT NULL,
+  "date" DATE NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "deposit_to" INTEGER NOT NULL,
+  "liability_account" INTEGER NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "unit_id" INTEGER NOT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  "description" TEXT NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_cm_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_cm_deposit_to" FOREIGN KEY ("deposit_to") REFERENCES "accounts" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_cm_liability_account" FOREIGN KEY ("liability_account") REFERENCES "accounts" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_cm_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_cm_transaction" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_cm_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_cm_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE CASCADE
+);
+
+CREATE INDEX "credit_memo_cm_transaction_id" ON "credit_memo" ("transaction_id");
+
+CREATE INDEX "credit_memo_cm_user_id" ON "credit_memo" ("user_id");
+
+CREATE INDEX "credit_memo_cm_deposit_to" ON "credit_memo" ("deposit_to");
+
+CREATE INDEX "credit_memo_cm_liability_account" ON "credit_memo" ("liability_account");
+
+CREATE INDEX "credit_memo_cm_people_id" ON "credit_memo" ("people_id");
+
+CREATE INDEX "credit_memo_cm_building_id" ON "credit_memo" ("building_id");
+
+CREATE INDEX "credit_memo_cm_unit_id" ON "credit_memo" ("unit_id");
+
+CREATE TABLE "expense_lines" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "check_id" INTEGER NOT NULL,
+  "account_id" INTEGER NOT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  "people_id" INTEGER DEFAULT NULL,
+  "description" TEXT DEFAULT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  CONSTRAINT "expense_lines_ibfk_1" FOREIGN KEY ("check_id") REFERENCES "checks" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "expense_lines_ibfk_2" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "expense_lines_ibfk_3" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE SET NULL ON UPDATE CASCADE,
+  CONSTRAINT "expense_lines_ibfk_4" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX "expense_lines_check_id" ON "expense_lines" ("check_id");
+
+CREATE INDEX "expense_lines_account_id" ON "expense_lines" ("account_id");
+
+CREATE INDEX "expense_lines_unit_id" ON "expense_lines" ("unit_id");
+
+CREATE INDEX "expense_lines_people_id" ON "expense_lines" ("people_id");
+
+CREATE TABLE "invoices" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "invoice_no" VARCHAR(255) NOT NULL,
+  "transaction_id" INTEGER NOT NULL,
+  "sales_date" DATE NOT NULL,
+  "due_date" DATE NOT NULL,
+  "ar_account_id" INTEGER NOT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  "people_id" INTEGER DEFAULT NULL,
+  "user_id" INTEGER NOT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  "description" TEXT NOT NULL,
+  "cancel_reason" TEXT DEFAULT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "building_id" INTEGER NOT NULL,
+  "createdAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_invoice_account_id" FOREIGN KEY ("ar_account_id") REFERENCES "accounts" ("id"),
+  CONSTRAINT "fk_invoice_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_invoice_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE,
+  CONSTRAINT "fk_invoice_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX "invoices_invoice_unit_id" ON "invoices" ("unit_id");
+
+CREATE INDEX "invoices_invoice_people_id" ON "invoices" ("people_id");
+
+CREATE INDEX "invoices_invoice_user_id" ON "invoices" ("user_id");
+
+CREATE INDEX "invoices_invoice_building_id" ON "invoices" ("building_id");
+
+CREATE INDEX "invoices_fk_invoice_account_id" ON "invoices" ("ar_account_id");
+
+CREATE TABLE "invoice_applied_credits" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "invoice_id" INTEGER NOT NULL,
+  "credit_memo_id" INTEGER NOT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  "description" TEXT NOT NULL,
+  "date" DATE NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_iac_credit_memo" FOREIGN KEY ("credit_memo_id") REFERENCES "credit_memo" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_iac_invoice" FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX "invoice_applied_credits_iac_invoice_id" ON "invoice_applied_credits" ("invoice_id");
+
+CREATE INDEX "invoice_applied_credits_iac_credit_memo_id" ON "invoice_applied_credits" ("credit_memo_id");
+
+CREATE TABLE "invoice_applied_discounts" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "reference" VARCHAR(255) NOT NULL,
+  "invoice_id" INTEGER NOT NULL,
+  "transaction_id" INTEGER NOT NULL,
+  "ar_account" INTEGER NOT NULL,
+  "income_account" INTEGER NOT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  "description" TEXT NOT NULL,
+  "date" DATE NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_iad_ar_account" FOREIGN KEY ("ar_account") REFERENCES "accounts" ("id"),
+  CONSTRAINT "fk_iad_income_account" FOREIGN KEY ("income_account") REFERENCES "accounts" ("id"),
+  CONSTRAINT "fk_iad_invoice" FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id"),
+  CONSTRAINT "fk_iad_transaction" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id")
+);
+
+CREATE INDEX "invoice_applied_discounts_iad_invoice_id" ON "invoice_applied_discounts" ("invoice_id");
+
+CREATE INDEX "invoice_applied_discounts_iad_transaction_id" ON "invoice_applied_discounts" ("transaction_id");
+
+CREATE INDEX "invoice_applied_discounts_iad_ar_account" ON "invoice_applied_discounts" ("ar_account");
+
+CREATE INDEX "invoice_applied_discounts_iad_income_account" ON "invoice_applied_discounts" ("income_account");
+
+CREATE TABLE "invoice_items" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "invoice_id" INTEGER NOT NULL,
+  "item_id" INTEGER NOT NULL,
+  "item_name" VARCHAR(250) NOT NULL,
+  "previous_value" DECIMAL(10,3) DEFAULT NULL,
+  "current_value" DECIMAL(10,3) DEFAULT NULL,
+  "qty" DECIMAL(10,3) DEFAULT NULL,
+  "rate" VARCHAR(100) DEFAULT NULL,
+  "total" DECIMAL(10,2) NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "invoice_items_invoice_items_inv_id" ON "invoice_items" ("invoice_id");
+
+CREATE INDEX "invoice_items_invoice_items_item_id" ON "invoice_items" ("item_id");
+
+CREATE TABLE "invoice_payments" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "transaction_id" INTEGER NOT NULL,
+  "reference" VARCHAR(255) NOT NULL,
+  "date" DATE NOT NULL,
+  "invoice_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "account_id" INTEGER NOT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "createdAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_ip_account" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_ip_invoice" FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_ip_transaction" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_ip_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON UPDATE CASCADE
+);
+
+CREATE INDEX "invoice_payments_ip_transaction_id" ON "invoice_payments" ("transaction_id");
+
+CREATE INDEX "invoice_payments_ip_invoice_id" ON "invoice_payments" ("invoice_id");
+
+CREATE INDEX "invoice_payments_ip_user_id" ON "invoice_payments" ("user_id");
+
+CREATE INDEX "invoice_payments_ip_account_id" ON "invoice_payments" ("account_id");
+
+CREATE TABLE "items" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(250) NOT NULL,
+  "type" TEXT NOT NULL,
+  "description" TEXT NOT NULL,
+  "asset_account" INTEGER DEFAULT NULL,
+  "income_account" INTEGER DEFAULT NULL,
+  "cogs_account" INTEGER DEFAULT NULL,
+  "expense_account" INTEGER DEFAULT NULL,
+  "on_hand" DECIMAL(10,2) NOT NULL,
+  "avg_cost" DECIMAL(10,2) NOT NULL,
+  "date" DATE NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_items_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX "items_item_asset_account_pk" ON "items" ("asset_account");
+
+CREATE INDEX "items_item_income_account_pk" ON "items" ("income_account");
+
+CREATE INDEX "items_item_cogs_account_pk" ON "items" ("cogs_account");
+
+CREATE INDEX "items_item_expense_account_pk" ON "items" ("expense_account");
+
+CREATE INDEX "items_item_building_fk" ON "items" ("building_id");
+
+CREATE TABLE "journal" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "transaction_id" INTEGER NOT NULL,
+  "reference" VARCHAR(255) NOT NULL,
+  "journal_date" DATE NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "memo" TEXT DEFAULT NULL,
+  "total_amount" DECIMAL(10,2) DEFAULT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "journal_ibfk_1" FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "journal_ibfk_2" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX "journal_transaction_id" ON "journal" ("transaction_id");
+
+CREATE INDEX "journal_building_id" ON "journal" ("building_id");
+
+CREATE TABLE "journal_lines" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "journal_id" INTEGER NOT NULL,
+  "account_id" INTEGER NOT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  "people_id" INTEGER DEFAULT NULL,
+  "description" TEXT DEFAULT NULL,
+  "debit" DECIMAL(10,2) DEFAULT 0.00,
+  "credit" DECIMAL(10,2) DEFAULT 0.00,
+  CONSTRAINT "journal_lines_ibfk_1" FOREIGN KEY ("journal_id") REFERENCES "journal" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "journal_lines_ibfk_2" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "journal_lines_ibfk_3" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE SET NULL ON UPDATE CASCADE,
+  CONSTRAINT "journal_lines_ibfk_4" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX "journal_lines_journal_id" ON "journal_lines" ("journal_id");
+
+CREATE INDEX "journal_lines_account_id" ON "journal_lines" ("account_id");
+
+CREATE INDEX "journal_lines_unit_id" ON "journal_lines" ("unit_id");
+
+CREATE INDEX "journal_lines_people_id" ON "journal_lines" ("people_id");
+
+CREATE TABLE "leases" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "people_id" INTEGER NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "unit_id" INTEGER NOT NULL,
+  "start_date" DATE NOT NULL,
+  "end_date" DATE DEFAULT NULL,
+  "rent_amount" DECIMAL(10,2) NOT NULL,
+  "deposit_amount" DECIMAL(10,2) NOT NULL,
+  "service_amount" DECIMAL(10,2) NOT NULL,
+  "lease_terms" TEXT NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  CONSTRAINT "fk_leases_buildings" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_leases_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_leases_units" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON UPDATE CASCADE
+);
+
+CREATE INDEX "leases_idx_people_id" ON "leases" ("people_id");
+
+CREATE INDEX "leases_idx_building_id" ON "leases" ("building_id");
+
+CREATE INDEX "leases_idx_unit_id" ON "leases" ("unit_id");
+
+CREATE TABLE "lease_files" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "lease_id" INTEGER NOT NULL,
+  "filename" VARCHAR(255) NOT NULL,
+  "original_name" VARCHAR(255) NOT NULL,
+  "file_path" VARCHAR(500) NOT NULL,
+  "file_type" VARCHAR(100) NOT NULL,
+  "file_size" INTEGER NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_lease_files_lease_id" FOREIGN KEY ("lease_id") REFERENCES "leases" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX "lease_files_idx_lease_id" ON "lease_files" ("lease_id");
+
+CREATE TABLE "people" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(255) NOT NULL,
+  "phone" VARCHAR(20) NOT NULL,
+  "type_id" INTEGER NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "people_building_id_fk" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id"),
+  CONSTRAINT "people_type_id_fk" FOREIGN KEY ("type_id") REFERENCES "people_types" ("id")
+);
+
+CREATE INDEX "people_people_type_id_fk" ON "people" ("type_id");
+
+CREATE INDEX "people_people_building_id_fk" ON "people" ("building_id");
+
+CREATE TABLE "people_types" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "title" VARCHAR(50) NOT NULL
+);
+
+CREATE TABLE "periods" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "period_name" VARCHAR(50) NOT NULL,
+  "start" DATE NOT NULL,
+  "end" DATE NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "is_closed" INTEGER NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "period_building_id" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id")
+);
+
+CREATE INDEX "periods_period_building_id" ON "periods" ("building_id");
+
+CREATE TABLE "readings" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "item_id" INTEGER NOT NULL,
+  "unit_id" INTEGER NOT NULL,
+  "lease_id" INTEGER DEFAULT NULL,
+  "reading_month" VARCHAR(10) DEFAULT NULL,
+  "reading_year" VARCHAR(5) DEFAULT NULL,
+  "reading_date" DATE NOT NULL,
+  "previous_value" DECIMAL(10,3) DEFAULT NULL,
+  "current_value" DECIMAL(10,3) DEFAULT NULL,
+  "unit_price" DECIMAL(10,2) DEFAULT NULL,
+  "total_amount" DECIMAL(10,2) DEFAULT NULL,
+  "notes" TEXT DEFAULT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_readings_item" FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_readings_lease" FOREIGN KEY ("lease_id") REFERENCES "leases" ("id") ON DELETE SET NULL ON UPDATE CASCADE,
+  CONSTRAINT "fk_readings_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON UPDATE CASCADE
+);
+
+CREATE INDEX "readings_idx_item_id" ON "readings" ("item_id");
+
+CREATE INDEX "readings_idx_unit_id" ON "readings" ("unit_id");
+
+CREATE INDEX "readings_idx_lease_id" ON "readings" ("lease_id");
+
+CREATE TABLE "receipt_items" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "receipt_id" INTEGER NOT NULL,
+  "item_id" INTEGER NOT NULL,
+  "item_name" VARCHAR(250) NOT NULL,
+  "previous_value" DECIMAL(10,3) DEFAULT NULL,
+  "current_value" DECIMAL(10,3) DEFAULT NULL,
+  "qty" DECIMAL(10,2) DEFAULT NULL,
+  "rate" VARCHAR(100) DEFAULT NULL,
+  "total" DECIMAL(10,2) NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_sri_item" FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_sri_receipt" FOREIGN KEY ("receipt_id") REFERENCES "sales_receipt" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX "receipt_items_sri_receipt_id" ON "receipt_items" ("receipt_id");
+
+CREATE INDEX "receipt_items_sri_item_id" ON "receipt_items" ("item_id");
+
+CREATE TABLE "sales_receipt" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "receipt_no" INTEGER NOT NULL,
+  "transaction_id" INTEGER NOT NULL,
+  "receipt_date" DATE NOT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  "people_id" INTEGER DEFAULT NULL,
+  "user_id" INTEGER NOT NULL,
+  "account_id" INTEGER NOT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  "description" TEXT DEFAULT NULL,
+  "cancel_reason" TEXT DEFAULT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "building_id" INTEGER NOT NULL,
+  "createdAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updatedAt" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_sr_account" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON UPDATE CASCADE,
+  CONSTRAINT "fk_sr_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_sr_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE,
+  CONSTRAINT "fk_sr_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX "sales_receipt_sr_unit_id" ON "sales_receipt" ("unit_id");
+
+CREATE INDEX "sales_receipt_sr_people_id" ON "sales_receipt" ("people_id");
+
+CREATE INDEX "sales_receipt_sr_user_id" ON "sales_receipt" ("user_id");
+
+CREATE INDEX "sales_receipt_sr_building_id" ON "sales_receipt" ("building_id");
+
+CREATE INDEX "sales_receipt_sr_account_id" ON "sales_receipt" ("account_id");
+
+CREATE TABLE "splits" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "transaction_id" INTEGER NOT NULL,
+  "account_id" INTEGER NOT NULL,
+  "people_id" INTEGER DEFAULT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  "debit" DECIMAL(10,2) DEFAULT NULL,
+  "credit" DECIMAL(10,2) DEFAULT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "fk_splits_account" FOREIGN KEY ("account_id") REFERENCES "accounts" ("id"),
+  CONSTRAINT "fk_splits_people" FOREIGN KEY ("people_id") REFERENCES "people" ("id") ON DELETE SET NULL ON UPDATE CASCADE,
+  CONSTRAINT "fk_splits_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id")
+);
+
+CREATE INDEX "splits_td_transaction_id" ON "splits" ("transaction_id");
+
+CREATE INDEX "splits_td_account_id" ON "splits" ("account_id");
+
+CREATE INDEX "splits_td_people_id" ON "splits" ("people_id");
+
+CREATE INDEX "splits_fk_splits_unit" ON "splits" ("unit_id");
+
+CREATE TABLE "transactions" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "type" TEXT NOT NULL,
+  "transaction_date" DATE NOT NULL,
+  "transaction_number" VARCHAR(255) NOT NULL,
+  "memo" TEXT NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "building_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  CONSTRAINT "fk_transactions_building" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_transactions_unit" FOREIGN KEY ("unit_id") REFERENCES "units" ("id") ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT "fk_transactions_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX "transactions_transaction_type_fk" ON "transactions" ("type");
+
+CREATE INDEX "transactions_transaction_building_fk" ON "transactions" ("building_id");
+
+CREATE INDEX "transactions_transaction_user_fk" ON "transactions" ("user_id");
+
+CREATE INDEX "transactions_transaction_unit_fk" ON "transactions" ("unit_id");
+
+CREATE TABLE "units" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(20) NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT "unit_building_id_fk" FOREIGN KEY ("building_id") REFERENCES "buildings" ("id")
+);
+
+CREATE INDEX "units_unit_building_id_fk" ON "units" ("building_id");
+
+CREATE TABLE "users" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" VARCHAR(50) NOT NULL,
+  "username" VARCHAR(20) NOT NULL,
+  "phone" VARCHAR(20) NOT NULL,
+  "password" VARCHAR(10) NOT NULL
+);
@@ -0,0 +1,18 @@
+-- One row per account, optional unit, year and month.
+-- unit_id NULL means the amount is budgeted for the whole building.
+
+CREATE TABLE IF NOT EXISTS "budgets" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "account_id" INTEGER NOT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  "year" INTEGER NOT NULL,
+  "month" INTEGER NOT NULL,
+  "amount" DECIMAL(15,2) NOT NULL DEFAULT 0.00,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_budgets_building_year" ON "budgets" ("building_id", "year");
+
+CREATE INDEX IF NOT EXISTS "idx_budgets_account" ON "budgets" ("account_id");
@@ -0,0 +1,22 @@
+-- Responses of POST requests sent with an Idempotency-Key header, one row per user and key.
+-- locked_until and expires_at are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "user_id" INTEGER NOT NULL,
+  "idempotency_key" VARCHAR(255) NOT NULL,
+  "method" VARCHAR(10) NOT NULL,
+  "path" VARCHAR(255) NOT NULL,
+  "request_hash" CHAR(64) NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'processing',
+  "response_status" INTEGER DEFAULT NULL,
+  "response_content_type" VARCHAR(255) DEFAULT NULL,
+  "response_body" TEXT DEFAULT NULL,
+  "locked_until" DATETIME NOT NULL,
+  "expires_at" DATETIME NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_idempotency_keys_user_key" ON "idempotency_keys" ("user_id", "idempotency_key");
+
+CREATE INDEX IF NOT EXISTS "idx_idempotency_keys_expires_at" ON "idempotency_keys" ("expires_at");
@@ -0,0 +1,8 @@
+ALTER TABLE "invoices" DROP COLUMN "version";
+ALTER TABLE "sales_receipt" DROP COLUMN "version";
+ALTER TABLE "invoice_payments" DROP COLUMN "version";
+ALTER TABLE "checks" DROP COLUMN "version";
+ALTER TABLE "credit_memo" DROP COLUMN "version";
+ALTER TABLE "journal" DROP COLUMN "version";
+ALTER TABLE "leases" DROP COLUMN "version";
+ALTER TABLE "readings" DROP COLUMN "version";
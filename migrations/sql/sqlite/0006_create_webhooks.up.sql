@@ -0,0 +1,53 @@
+-- Domain events are written to outbox_events in the same transaction as the change they
+-- describe. The dispatcher copies each event into webhook_deliveries, one row per matching
+-- subscription, and sets dispatched_at. Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "outbox_events" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "event_type" VARCHAR(100) NOT NULL,
+  "aggregate_type" VARCHAR(50) NOT NULL,
+  "aggregate_id" INTEGER NOT NULL,
+  "payload" TEXT NOT NULL,
+  "created_at" DATETIME NOT NULL,
+  "dispatched_at" DATETIME DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_dispatched_at" ON "outbox_events" ("dispatched_at");
+
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_building" ON "outbox_events" ("building_id", "id");
+
+-- event_types is a comma separated list; empty means every event of the building
+CREATE TABLE IF NOT EXISTS "webhook_subscriptions" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "url" VARCHAR(2048) NOT NULL,
+  "secret" VARCHAR(255) NOT NULL,
+  "event_types" TEXT NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'active',
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_webhook_subscriptions_building" ON "webhook_subscriptions" ("building_id");
+
+-- status is pending until delivered, or dead once the attempts are used up
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "event_id" INTEGER NOT NULL,
+  "subscription_id" INTEGER NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "next_attempt_at" DATETIME NOT NULL,
+  "last_status_code" INTEGER DEFAULT NULL,
+  "last_error" TEXT DEFAULT NULL,
+  "delivered_at" DATETIME DEFAULT NULL,
+  "created_at" DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_webhook_deliveries_event_subscription" ON "webhook_deliveries" ("event_id", "subscription_id");
+
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_due" ON "webhook_deliveries" ("status", "next_attempt_at");
+
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_subscription" ON "webhook_deliveries" ("subscription_id");
@@ -0,0 +1,46 @@
+-- Scheduled jobs run in-process. A job is claimed by one application instance at a time
+-- through locked_by/locked_until, and every execution is recorded in job_runs. Times are
+-- UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "scheduled_jobs" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "name" VARCHAR(100) NOT NULL,
+  "job_type" VARCHAR(50) NOT NULL,
+  "schedule" VARCHAR(100) NOT NULL,
+  "timezone" VARCHAR(64) NOT NULL DEFAULT 'UTC',
+  "params" TEXT NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'active',
+  "next_run_at" DATETIME DEFAULT NULL,
+  "last_run_at" DATETIME DEFAULT NULL,
+  "last_run_status" VARCHAR(20) DEFAULT NULL,
+  "locked_by" VARCHAR(100) DEFAULT NULL,
+  "locked_until" DATETIME DEFAULT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_scheduled_jobs_due" ON "scheduled_jobs" ("status", "next_run_at");
+
+CREATE INDEX IF NOT EXISTS "idx_scheduled_jobs_building" ON "scheduled_jobs" ("building_id");
+
+-- status moves queued -> running -> succeeded, failed or cancelled
+CREATE TABLE IF NOT EXISTS "job_runs" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "job_id" INTEGER NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "triggered_by" VARCHAR(20) NOT NULL,
+  "user_id" INTEGER DEFAULT NULL,
+  "status" VARCHAR(20) NOT NULL,
+  "cancel_requested" INTEGER NOT NULL DEFAULT 0,
+  "runner" VARCHAR(100) DEFAULT NULL,
+  "output" TEXT DEFAULT NULL,
+  "error" TEXT DEFAULT NULL,
+  "queued_at" DATETIME NOT NULL,
+  "started_at" DATETIME DEFAULT NULL,
+  "finished_at" DATETIME DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_job_runs_job" ON "job_runs" ("job_id", "id");
+
+CREATE INDEX IF NOT EXISTS "idx_job_runs_status" ON "job_runs" ("status");
@@ -0,0 +1,20 @@
+-- Per-building print settings of each document type (invoice, sales_receipt, payment,
+-- credit_memo). A type without a row prints with the defaults. logo_path is relative to
+-- the upload root.
+
+CREATE TABLE IF NOT EXISTS "document_templates" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "document_type" VARCHAR(30) NOT NULL,
+  "title" VARCHAR(100) NOT NULL,
+  "business_name" VARCHAR(255) NOT NULL DEFAULT '',
+  "address" TEXT NOT NULL,
+  "footer_text" TEXT NOT NULL,
+  "number_prefix" VARCHAR(20) NOT NULL DEFAULT '',
+  "number_padding" INTEGER NOT NULL DEFAULT 0,
+  "logo_path" VARCHAR(255) DEFAULT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_document_templates_building_type" ON "document_templates" ("building_id", "document_type");
@@ -0,0 +1,61 @@
+-- Email and SMS notifications. people.email is optional; a person without an address or phone
+-- is skipped on that channel.
+--
+-- notification_templates override the built-in subject and body of a category on a channel.
+-- notification_preferences hold the channels a person wants per category as a comma separated
+-- list; a category without a row goes to every channel the person has contact details for.
+-- notifications is the delivery log: status is pending until sent, or failed once the attempts
+-- are used up. Times are UTC and written by the application.
+
+ALTER TABLE "people" ADD COLUMN "email" VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS "notification_templates" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "category" VARCHAR(30) NOT NULL,
+  "channel" VARCHAR(10) NOT NULL,
+  "subject" VARCHAR(255) NOT NULL DEFAULT '',
+  "body" TEXT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_notification_templates_building_category_channel" ON "notification_templates" ("building_id", "category", "channel");
+
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "category" VARCHAR(30) NOT NULL,
+  "channels" VARCHAR(30) NOT NULL DEFAULT '',
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_notification_preferences_people_category" ON "notification_preferences" ("people_id", "category");
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "category" VARCHAR(30) NOT NULL,
+  "channel" VARCHAR(10) NOT NULL,
+  "recipient" VARCHAR(255) NOT NULL,
+  "subject" VARCHAR(255) NOT NULL DEFAULT '',
+  "body" TEXT NOT NULL,
+  "reference_type" VARCHAR(30) DEFAULT NULL,
+  "reference_id" INTEGER DEFAULT NULL,
+  "attachment_name" VARCHAR(255) DEFAULT NULL,
+  "attachment_path" VARCHAR(255) DEFAULT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "next_attempt_at" DATETIME NOT NULL,
+  "last_error" TEXT DEFAULT NULL,
+  "sent_at" DATETIME DEFAULT NULL,
+  "created_at" DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_due" ON "notifications" ("status", "next_attempt_at");
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_building" ON "notifications" ("building_id", "id");
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_reference" ON "notifications" ("reference_type", "reference_id");
@@ -0,0 +1,53 @@
+-- Dunning: reminders of overdue invoices sent in steps, e.g. a friendly reminder 3 days
+-- after the due date, a firm notice at 15 days and a final notice at 30.
+--
+-- dunning_steps is a building's sequence; each step has its own channel and template.
+-- dunning_exclusions keeps customers out of dunning, with the reason.
+-- dunning_reminders records every reminder of an invoice: sent by a dunning step, sent by
+-- hand (step_id NULL), or skipped when the customer could not be reached on the step's
+-- channel. Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "dunning_steps" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "name" VARCHAR(100) NOT NULL,
+  "days_overdue" INTEGER NOT NULL,
+  "channel" VARCHAR(10) NOT NULL,
+  "subject" VARCHAR(255) NOT NULL DEFAULT '',
+  "body" TEXT NOT NULL,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_dunning_steps_building_days" ON "dunning_steps" ("building_id", "days_overdue");
+
+CREATE TABLE IF NOT EXISTS "dunning_exclusions" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "reason" VARCHAR(255) NOT NULL,
+  "user_id" INTEGER DEFAULT NULL,
+  "created_at" DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_dunning_exclusions_people" ON "dunning_exclusions" ("people_id");
+
+CREATE TABLE IF NOT EXISTS "dunning_reminders" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "invoice_id" INTEGER NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "step_id" INTEGER DEFAULT NULL,
+  "step_name" VARCHAR(100) NOT NULL,
+  "days_overdue" INTEGER NOT NULL,
+  "balance" DECIMAL(10,2) NOT NULL,
+  "status" VARCHAR(20) NOT NULL,
+  "note" VARCHAR(255) DEFAULT NULL,
+  "notification_ids" VARCHAR(100) NOT NULL DEFAULT '',
+  "user_id" INTEGER DEFAULT NULL,
+  "created_at" DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_dunning_reminders_invoice_step" ON "dunning_reminders" ("invoice_id", "step_id");
+
+CREATE INDEX IF NOT EXISTS "idx_dunning_reminders_building" ON "dunning_reminders" ("building_id", "id");
@@ -0,0 +1,30 @@
+-- Tenant portal sign-in. A tenant asks for a code by phone number; portal_codes keeps a
+-- hash of each code sent with its wrong guesses. A correct code opens a session for one
+-- people record; portal_sessions keeps a hash of the bearer token, never the token itself.
+-- Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "portal_codes" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "phone" VARCHAR(30) NOT NULL,
+  "code_hash" VARCHAR(64) NOT NULL,
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "expires_at" DATETIME NOT NULL,
+  "used_at" DATETIME DEFAULT NULL,
+  "created_at" DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_portal_codes_phone" ON "portal_codes" ("phone", "id");
+
+CREATE TABLE IF NOT EXISTS "portal_sessions" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "token_hash" VARCHAR(64) NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "building_id" INTEGER NOT NULL,
+  "expires_at" DATETIME NOT NULL,
+  "revoked_at" DATETIME DEFAULT NULL,
+  "created_at" DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_portal_sessions_token" ON "portal_sessions" ("token_hash");
+
+CREATE INDEX IF NOT EXISTS "idx_portal_sessions_people" ON "portal_sessions" ("people_id");
@@ -0,0 +1,63 @@
+-- Online payments through a payment gateway, e.g. mobile money or cards.
+--
+-- payment_gateway_settings holds, per building, the clearing account gateway payments are
+-- received into and the user they are recorded by.
+-- payment_intents are pending payments of an invoice handed to the gateway; paid_amount is
+-- what the gateway reported paid, less reversals.
+-- payment_gateway_events records every webhook event once per provider event id, so that
+-- redelivered events are recognised, with the invoice payment it created. Times are UTC and
+-- written by the application.
+
+CREATE TABLE IF NOT EXISTS "payment_gateway_settings" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "clearing_account_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'active',
+  "created_at" DATETIME NOT NULL,
+  "updated_at" DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_payment_gateway_settings_building" ON "payment_gateway_settings" ("building_id");
+
+CREATE TABLE IF NOT EXISTS "payment_intents" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "building_id" INTEGER NOT NULL,
+  "invoice_id" INTEGER NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "provider" VARCHAR(30) NOT NULL,
+  "provider_reference" VARCHAR(100) NOT NULL,
+  "checkout_url" VARCHAR(500) NOT NULL DEFAULT '',
+  "amount" DECIMAL(10,2) NOT NULL,
+  "paid_amount" DECIMAL(10,2) NOT NULL DEFAULT 0,
+  "status" VARCHAR(20) NOT NULL,
+  "source" VARCHAR(20) NOT NULL,
+  "user_id" INTEGER DEFAULT NULL,
+  "created_at" DATETIME NOT NULL,
+  "updated_at" DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_payment_intents_reference" ON "payment_intents" ("provider", "provider_reference");
+
+CREATE INDEX IF NOT EXISTS "idx_payment_intents_invoice" ON "payment_intents" ("invoice_id");
+
+CREATE INDEX IF NOT EXISTS "idx_payment_intents_building" ON "payment_intents" ("building_id", "id");
+
+CREATE TABLE IF NOT EXISTS "payment_gateway_events" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "provider" VARCHAR(30) NOT NULL,
+  "event_id" VARCHAR(100) NOT NULL,
+  "intent_id" INTEGER DEFAULT NULL,
+  "type" VARCHAR(20) NOT NULL,
+  "amount" DECIMAL(10,2) NOT NULL DEFAULT 0,
+  "status" VARCHAR(20) NOT NULL,
+  "note" VARCHAR(255) DEFAULT NULL,
+  "payment_id" INTEGER DEFAULT NULL,
+  "payload" TEXT NOT NULL,
+  "created_at" DATETIME NOT NULL,
+  "updated_at" DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "uniq_payment_gateway_events_event" ON "payment_gateway_events" ("provider", "event_id");
+
+CREATE INDEX IF NOT EXISTS "idx_payment_gateway_events_intent" ON "payment_gateway_events" ("intent_id");
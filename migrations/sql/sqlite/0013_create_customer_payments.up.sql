@@ -0,0 +1,32 @@
+-- Customer payments: one receipt of money from a customer, allocated across several open
+-- invoices by hand or oldest first, posted as one transaction with one deposit split.
+--
+-- Every allocation is an invoice_payments row pointing back at its customer payment through
+-- customer_payment_id, so invoice balances count it like any other payment. What is not
+-- allocated stays on the customer's receivable account as unapplied credit, and is applied to
+-- invoices later in a transaction of its own. Times are UTC and written by the application.
+
+CREATE TABLE IF NOT EXISTS "customer_payments" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "transaction_id" INTEGER NOT NULL,
+  "reference" VARCHAR(255) NOT NULL,
+  "date" DATE NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  "building_id" INTEGER NOT NULL,
+  "account_id" INTEGER NOT NULL,
+  "ar_account_id" INTEGER NOT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  "memo" VARCHAR(255) NOT NULL DEFAULT '',
+  "user_id" INTEGER NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "version" INTEGER NOT NULL DEFAULT 1,
+  "created_at" DATETIME NOT NULL,
+  "updated_at" DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_customer_payments_building" ON "customer_payments" ("building_id", "date");
+CREATE INDEX IF NOT EXISTS "idx_customer_payments_people" ON "customer_payments" ("people_id");
+
+ALTER TABLE "invoice_payments" ADD COLUMN "customer_payment_id" INTEGER DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS "idx_invoice_payments_customer_payment" ON "invoice_payments" ("customer_payment_id");
@@ -0,0 +1,34 @@
+-- Customer prepayments: money a customer pays before they are invoiced. A prepayment is a
+-- customer payment held on a customer deposits liability account (liability_account_id)
+-- instead of a receivable, so it has no ar_account_id. It is applied to invoices later like
+-- any unapplied credit, moving it from the liability to the invoice's receivable.
+--
+-- SQLite can't drop NOT NULL from a column, so the table is rebuilt.
+
+CREATE TABLE "customer_payments_new" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "transaction_id" INTEGER NOT NULL,
+  "reference" VARCHAR(255) NOT NULL,
+  "date" DATE NOT NULL,
+  "people_id" INTEGER NOT NULL,
+  "unit_id" INTEGER DEFAULT NULL,
+  "building_id" INTEGER NOT NULL,
+  "account_id" INTEGER NOT NULL,
+  "ar_account_id" INTEGER DEFAULT NULL,
+  "liability_account_id" INTEGER DEFAULT NULL,
+  "amount" DECIMAL(10,2) NOT NULL,
+  "memo" VARCHAR(255) NOT NULL DEFAULT '',
+  "user_id" INTEGER NOT NULL,
+  "status" TEXT NOT NULL DEFAULT '1',
+  "version" INTEGER NOT NULL DEFAULT 1,
+  "created_at" DATETIME NOT NULL,
+  "updated_at" DATETIME NOT NULL
+);
+
+INSERT INTO "customer_payments_new" ("id", "transaction_id", "reference", "date", "people_id", "unit_id", "building_id", "account_id", "ar_account_id", "amount", "memo", "user_id", "status", "version", "created_at", "updated_at")
+  SELECT "id", "transaction_id", "reference", "date", "people_id", "unit_id", "building_id", "account_id", "ar_account_id", "amount", "memo", "user_id", "status", "version", "created_at", "updated_at" FROM "customer_payments";
+DROP TABLE "customer_payments";
+ALTER TABLE "customer_payments_new" RENAME TO "customer_payments";
+
+CREATE INDEX IF NOT EXISTS "idx_customer_payments_building" ON "customer_payments" ("building_id", "date");
+CREATE INDEX IF NOT EXISTS "idx_customer_payments_people" ON "customer_payments" ("people_id");
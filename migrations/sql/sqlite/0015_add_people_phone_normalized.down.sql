@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_people_phone_normalized";
+ALTER TABLE "people" DROP COLUMN "phone_normalized";
@@ -0,0 +1,3 @@
+-- Customer payments post transactions of their own types: the payment, and each later
+-- application of its unapplied credit. SQLite's transactions.type is TEXT and accepts them
+-- already; this migration keeps the version numbers in step with MySQL and PostgreSQL.
@@ -0,0 +1,3 @@
+-- Customer prepayments post transactions of their own types: the prepayment, and each
+-- application of it to an invoice. SQLite's transactions.type is TEXT and accepts them
+-- already; this migration keeps the version numbers in step with MySQL and PostgreSQL.
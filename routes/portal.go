@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/config"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/documents"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/payment_gateway"
+	"github.com/mysecodgit/go_accounting/src/portal"
+	"github.com/mysecodgit/go_accounting/src/reports"
+)
+
+// PortalOpenAPI describes the tenant portal, which is served apart from the staff API
+var PortalOpenAPI = []openapi.Handlers{
+	portal.OpenAPI,
+}
+
+// setupPortalRoutes registers the tenant portal on its own engine. None of the staff routes
+// are reachable from it; every route but sign-in needs a portal session.
+func setupPortalRoutes(r *gin.Engine, logger *slog.Logger, reportsService *reports.ReportsService, documentService *documents.DocumentService, paymentGatewayService *payment_gateway.PaymentGatewayService) {
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) {
+		apperrors.Respond(c, apperrors.New(apperrors.CodeNotFound, "Route not found"))
+	})
+	r.NoMethod(func(c *gin.Context) {
+		apperrors.Respond(c, apperrors.New(apperrors.CodeMethodNotAllowed, "Method not allowed"))
+	})
+
+	openAPIHandler := openapi.NewOpenAPIHandler(r, PortalOpenAPI...)
+	r.GET("/openapi.json", openAPIHandler.GetSpec)
+	r.GET("/docs", openAPIHandler.GetDocs)
+
+	// Sign-in codes go out directly rather than through the notification queue, which
+	// may be disabled and would keep the code in its log
+	sms := notifications.NewTransports(config.App.Notifications, logger.With("component", "portal"))[notifications.ChannelSMS]
+	portalService := portal.NewPortalService(portal.NewPortalRepository(config.DB), reportsService, documentService, paymentGatewayService, sms, config.App.Portal, logger)
+	portalHandler := portal.NewPortalHandler(portalService)
+
+	portalRoutes := r.Group("/api/portal")
+	{
+		portalRoutes.POST("/auth/code", portalHandler.RequestCode)
+		portalRoutes.POST("/auth/verify", portalHandler.Verify)
+
+		signedIn := portalRoutes.Group("", portalHandler.RequireSession)
+		signedIn.POST("/auth/logout", portalHandler.SignOut)
+		signedIn.GET("/me", portalHandler.GetProfile)
+		signedIn.GET("/leases", portalHandler.GetLeases)
+		signedIn.GET("/leases/:leaseId/files", portalHandler.GetLeaseFiles)
+		signedIn.GET("/leases/:leaseId/files/:fileId", portalHandler.DownloadLeaseFile)
+		signedIn.GET("/invoices", portalHandler.GetInvoices)
+		signedIn.GET("/invoices/:invoiceId/pdf", portalHandler.DownloadInvoice)
+		signedIn.POST("/invoices/:invoiceId/pay", portalHandler.PayInvoice)
+		signedIn.GET("/payment-intents", portalHandler.GetPaymentIntents)
+		signedIn.GET("/payments", portalHandler.GetPayments)
+		signedIn.GET("/payments/:paymentId/pdf", portalHandler.DownloadPayment)
+		signedIn.GET("/statement", portalHandler.GetStatement)
+		signedIn.GET("/statement/pdf", portalHandler.DownloadStatement)
+		signedIn.GET("/readings", portalHandler.GetReadings)
+	}
+}
@@ -1,24 +1,39 @@
 package routes
 
 import (
+	"log/slog"
+
 	"github.com/gin-gonic/gin"
 	"github.com/mysecodgit/go_accounting/config"
 	_ "github.com/mysecodgit/go_accounting/handlers"
 	"github.com/mysecodgit/go_accounting/src/account_types"
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/budgets"
 	"github.com/mysecodgit/go_accounting/src/building"
 	"github.com/mysecodgit/go_accounting/src/checks"
 	"github.com/mysecodgit/go_accounting/src/credit_memo"
+	"github.com/mysecodgit/go_accounting/src/customer_payments"
+	"github.com/mysecodgit/go_accounting/src/documents"
+	"github.com/mysecodgit/go_accounting/src/dunning"
 	"github.com/mysecodgit/go_accounting/src/expense_lines"
+	"github.com/mysecodgit/go_accounting/src/health"
+	"github.com/mysecodgit/go_accounting/src/idempotency"
 	"github.com/mysecodgit/go_accounting/src/invoice_applied_credits"
 	"github.com/mysecodgit/go_accounting/src/invoice_applied_discounts"
 	"github.com/mysecodgit/go_accounting/src/invoice_items"
 	"github.com/mysecodgit/go_accounting/src/invoice_payments"
 	"github.com/mysecodgit/go_accounting/src/invoices"
 	"github.com/mysecodgit/go_accounting/src/items"
+	"github.com/mysecodgit/go_accounting/src/jobs"
 	"github.com/mysecodgit/go_accounting/src/journal"
 	"github.com/mysecodgit/go_accounting/src/journal_lines"
 	"github.com/mysecodgit/go_accounting/src/leases"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/payment_gateway"
 	"github.com/mysecodgit/go_accounting/src/people"
 	"github.com/mysecodgit/go_accounting/src/people_types"
 	"github.com/mysecodgit/go_accounting/src/period"
@@ -30,12 +45,68 @@ import (
 	"github.com/mysecodgit/go_accounting/src/transactions"
 	"github.com/mysecodgit/go_accounting/src/unit"
 	"github.com/mysecodgit/go_accounting/src/user"
+	"github.com/mysecodgit/go_accounting/src/webhooks"
 )
 
-func SetupRoutes(r *gin.Engine) {
+// OpenAPI collects the API description of every package's handlers
+var OpenAPI = []openapi.Handlers{
+	health.OpenAPI,
+	user.OpenAPI,
+	building.OpenAPI,
+	unit.OpenAPI,
+	people.OpenAPI,
+	people_types.OpenAPI,
+	period.OpenAPI,
+	account_types.OpenAPI,
+	accounts.OpenAPI,
+	items.OpenAPI,
+	invoices.OpenAPI,
+	invoice_payments.OpenAPI,
+	customer_payments.OpenAPI,
+	invoice_applied_credits.OpenAPI,
+	invoice_applied_discounts.OpenAPI,
+	sales_receipt.OpenAPI,
+	checks.OpenAPI,
+	credit_memo.OpenAPI,
+	journal.OpenAPI,
+	leases.OpenAPI,
+	readings.OpenAPI,
+	budgets.OpenAPI,
+	reports.OpenAPI,
+	webhooks.OpenAPI,
+	jobs.OpenAPI,
+	documents.OpenAPI,
+	notifications.OpenAPI,
+	dunning.OpenAPI,
+	payment_gateway.OpenAPI,
+}
+
+// SetupRoutes registers every route. logger is the base logger given to services; each
+// request replaces it with the request's logger. Job types are added to jobRegistry, which
+// the scheduler started by main runs from. The tenant portal is registered on portal, its own
+// engine, unless portal is nil.
+func SetupRoutes(r *gin.Engine, portal *gin.Engine, logger *slog.Logger, jobRegistry *jobs.Registry) {
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) {
+		apperrors.Respond(c, apperrors.New(apperrors.CodeNotFound, "Route not found"))
+	})
+	r.NoMethod(func(c *gin.Context) {
+		apperrors.Respond(c, apperrors.New(apperrors.CodeMethodNotAllowed, "Method not allowed"))
+	})
+
+	// API description generated from the routes below, and a page to browse it
+	openAPIHandler := openapi.NewOpenAPIHandler(r, OpenAPI...)
+	r.GET("/openapi.json", openAPIHandler.GetSpec)
+	r.GET("/docs", openAPIHandler.GetDocs)
+
+	// Probes and Prometheus metrics for the deployment
+	healthHandler := health.NewHealthHandler(config.DB)
+	r.GET("/healthz", healthHandler.Liveness)
+	r.GET("/readyz", healthHandler.Readiness)
+	r.GET("/metrics", metrics.Handler())
 
 	userRepo := user.NewUserRepository(config.DB)
-	userService := user.NewUserService(userRepo)
+	userService := user.NewUserService(userRepo, logger)
 	userHandler := user.NewUserHandler(userService)
 
 	userRoutes := r.Group("/api/users")
@@ -51,6 +122,9 @@ func SetupRoutes(r *gin.Engine) {
 	buildingService := building.NewBuildingService(buildingRepo)
 	buildingHandler := building.NewBuildingHandler(buildingService)
 
+	// Posting endpoints accept an Idempotency-Key header so retries don't duplicate transactions
+	idempotent := idempotency.Middleware(idempotency.NewIdempotencyRepository(config.DB))
+
 	// Initialize invoice dependencies (used in both building-scoped and legacy routes)
 	transactionRepo := transactions.NewTransactionRepository(config.DB)
 	splitRepo := splits.NewSplitRepository(config.DB)
@@ -58,7 +132,13 @@ func SetupRoutes(r *gin.Engine) {
 	itemRepoForInvoice := items.NewItemRepository(config.DB)
 	accountRepoForInvoice := accounts.NewAccountRepository(config.DB)
 	invoiceRepo := invoices.NewInvoiceRepository(config.DB)
-	invoiceService := invoices.NewInvoiceService(invoiceRepo, transactionRepo, splitRepo, invoiceItemRepo, itemRepoForInvoice, accountRepoForInvoice, config.DB)
+
+	// Initialize customer payment dependencies; new invoices apply the customer's prepayments
+	customerPaymentRepo := customer_payments.NewCustomerPaymentRepository(config.DB)
+	customerPaymentService := customer_payments.NewCustomerPaymentService(customerPaymentRepo, transactionRepo, splitRepo, accountRepoForInvoice, config.DB, logger)
+	customerPaymentHandler := customer_payments.NewCustomerPaymentHandler(customerPaymentService)
+
+	invoiceService := invoices.NewInvoiceService(invoiceRepo, transactionRepo, splitRepo, invoiceItemRepo, itemRepoForInvoice, accountRepoForInvoice, customerPaymentService, config.DB, logger)
 	invoiceHandler := invoices.NewInvoiceHandler(invoiceService)
 
 	// Initialize sales receipt dependencies
@@ -66,50 +146,84 @@ func SetupRoutes(r *gin.Engine) {
 	itemRepoForReceipt := items.NewItemRepository(config.DB)
 	accountRepoForReceipt := accounts.NewAccountRepository(config.DB)
 	receiptRepo := sales_receipt.NewSalesReceiptRepository(config.DB)
-	receiptService := sales_receipt.NewSalesReceiptService(receiptRepo, transactionRepo, splitRepo, receiptItemRepo, itemRepoForReceipt, accountRepoForReceipt, config.DB)
+	receiptService := sales_receipt.NewSalesReceiptService(receiptRepo, transactionRepo, splitRepo, receiptItemRepo, itemRepoForReceipt, accountRepoForReceipt, config.DB, logger)
 	receiptHandler := sales_receipt.NewSalesReceiptHandler(receiptService)
 
 	// Initialize invoice payment dependencies
 	paymentRepo := invoice_payments.NewInvoicePaymentRepository(config.DB)
-	paymentService := invoice_payments.NewInvoicePaymentService(paymentRepo, transactionRepo, splitRepo, invoiceRepo, accountRepoForInvoice, config.DB)
+	paymentService := invoice_payments.NewInvoicePaymentService(paymentRepo, transactionRepo, splitRepo, invoiceRepo, accountRepoForInvoice, config.DB, logger)
 	paymentHandler := invoice_payments.NewInvoicePaymentHandler(paymentService)
 
 	// Initialize checks dependencies
 	checkRepo := checks.NewCheckRepository(config.DB)
 	expenseLineRepo := expense_lines.NewExpenseLineRepository(config.DB)
 	accountTypeRepoForChecks := account_types.NewAccountTypeRepository(config.DB)
-	checkService := checks.NewCheckService(checkRepo, expenseLineRepo, transactionRepo, splitRepo, accountRepoForInvoice, accountTypeRepoForChecks, config.DB)
+	checkService := checks.NewCheckService(checkRepo, expenseLineRepo, transactionRepo, splitRepo, accountRepoForInvoice, accountTypeRepoForChecks, config.DB, logger)
 	checkHandler := checks.NewCheckHandler(checkService)
 
 	// Initialize credit memo dependencies
 	creditMemoRepo := credit_memo.NewCreditMemoRepository(config.DB)
 	peopleRepoForCreditMemo := people.NewPersonRepository(config.DB)
 	accountTypeRepoForCreditMemo := account_types.NewAccountTypeRepository(config.DB)
-	creditMemoService := credit_memo.NewCreditMemoService(creditMemoRepo, transactionRepo, splitRepo, accountRepoForInvoice, accountTypeRepoForCreditMemo, peopleRepoForCreditMemo, config.DB)
+	creditMemoService := credit_memo.NewCreditMemoService(creditMemoRepo, transactionRepo, splitRepo, accountRepoForInvoice, accountTypeRepoForCreditMemo, peopleRepoForCreditMemo, config.DB, logger)
 	creditMemoHandler := credit_memo.NewCreditMemoHandler(creditMemoService)
 
 	// Initialize invoice applied credits dependencies
 	appliedCreditRepo := invoice_applied_credits.NewInvoiceAppliedCreditRepository(config.DB)
-	appliedCreditService := invoice_applied_credits.NewInvoiceAppliedCreditService(appliedCreditRepo, invoiceRepo, creditMemoRepo, accountRepoForInvoice)
+	appliedCreditService := invoice_applied_credits.NewInvoiceAppliedCreditService(appliedCreditRepo, invoiceRepo, creditMemoRepo, customerPaymentRepo, accountRepoForInvoice, logger)
 	appliedCreditHandler := invoice_applied_credits.NewInvoiceAppliedCreditHandler(appliedCreditService)
 
 	appliedDiscountRepo := invoice_applied_discounts.NewInvoiceAppliedDiscountRepository(config.DB)
-	appliedDiscountService := invoice_applied_discounts.NewInvoiceAppliedDiscountService(appliedDiscountRepo, invoiceRepo, accountRepoForInvoice, transactionRepo, splitRepo, config.DB)
+	appliedDiscountService := invoice_applied_discounts.NewInvoiceAppliedDiscountService(appliedDiscountRepo, invoiceRepo, accountRepoForInvoice, transactionRepo, splitRepo, config.DB, logger)
 	appliedDiscountHandler := invoice_applied_discounts.NewInvoiceAppliedDiscountHandler(appliedDiscountService)
 
 	// Initialize journal dependencies
 	journalRepo := journal.NewJournalRepository(config.DB)
 	journalLineRepo := journal_lines.NewJournalLineRepository(config.DB)
 	accountTypeRepoForJournal := account_types.NewAccountTypeRepository(config.DB)
-	journalService := journal.NewJournalService(journalRepo, journalLineRepo, transactionRepo, splitRepo, accountRepoForInvoice, accountTypeRepoForJournal, config.DB)
+	journalService := journal.NewJournalService(journalRepo, journalLineRepo, transactionRepo, splitRepo, accountRepoForInvoice, accountTypeRepoForJournal, config.DB, logger)
 	journalHandler := journal.NewJournalHandler(journalService)
 
 	// Initialize reports dependencies
 	peopleRepo := people.NewPersonRepository(config.DB)
 	peopleTypeRepoForReports := people_types.NewPeopleTypeRepository(config.DB)
-	reportsService := reports.NewReportsService(accountRepoForInvoice, splitRepo, transactionRepo, invoiceRepo, paymentRepo, peopleRepo, peopleTypeRepoForReports, config.DB)
+	budgetRepo := budgets.NewBudgetRepository(config.DB)
+	reportsService := reports.NewReportsService(accountRepoForInvoice, splitRepo, transactionRepo, invoiceRepo, paymentRepo, peopleRepo, peopleTypeRepoForReports, budgetRepo, config.DB)
 	reportsHandler := reports.NewReportsHandler(reportsService)
 
+	// Initialize webhook dependencies; the dispatcher itself runs from main
+	webhookService := webhooks.NewWebhookService(webhooks.NewWebhookRepository(config.DB), outbox.NewOutboxRepository(config.DB), logger)
+	webhookHandler := webhooks.NewWebhookHandler(webhookService)
+
+	// Initialize scheduled job dependencies; the scheduler itself runs from main
+	jobRegistry.Register(reports.SnapshotJob(reportsService))
+	jobService := jobs.NewJobService(jobs.NewJobRepository(config.DB), jobRegistry, logger)
+	jobHandler := jobs.NewJobHandler(jobService)
+
+	// Initialize notification dependencies; the sender itself runs from main
+	notificationService := notifications.NewNotificationService(notifications.NewNotificationRepository(config.DB), peopleRepo, logger)
+	notificationHandler := notifications.NewNotificationHandler(notificationService)
+
+	// Initialize printable document dependencies
+	documentService := documents.NewDocumentService(
+		documents.NewTemplateRepository(config.DB), buildingRepo, invoiceRepo, invoiceItemRepo, receiptRepo, receiptItemRepo,
+		paymentRepo, creditMemoRepo, appliedCreditRepo, appliedDiscountRepo, peopleRepo, unit.NewUnitRepository(config.DB), accountRepoForInvoice,
+		reportsService, notificationService, logger,
+	)
+	documentHandler := documents.NewDocumentHandler(documentService)
+
+	// Initialize dunning dependencies; scheduled runs go through the dunning_run job
+	dunningService := dunning.NewDunningService(dunning.NewDunningRepository(config.DB), peopleRepo, invoiceRepo, documentService, logger)
+	dunningHandler := dunning.NewDunningHandler(dunningService)
+	jobRegistry.Register(dunning.RunJob(dunningService))
+
+	// Initialize online payment dependencies
+	paymentGatewayService := payment_gateway.NewPaymentGatewayService(
+		payment_gateway.NewPaymentGatewayRepository(config.DB), invoiceRepo, accountRepoForInvoice, paymentService,
+		payment_gateway.NewProvider(config.App.PaymentGateway), logger,
+	)
+	paymentGatewayHandler := payment_gateway.NewPaymentGatewayHandler(paymentGatewayService)
+
 	buildingRoutes := r.Group("/api/buildings")
 	{
 		buildingRoutes.GET("", buildingHandler.GetBuildings)
@@ -135,6 +249,8 @@ func SetupRoutes(r *gin.Engine) {
 		buildingRoutes.POST("/:id/people", personHandler.CreatePerson)
 		buildingRoutes.GET("/:id/people/:personId", personHandler.GetPerson)
 		buildingRoutes.PUT("/:id/people/:personId", personHandler.UpdatePerson)
+		buildingRoutes.GET("/:id/people/:personId/notification-preferences", notificationHandler.GetPreferences)
+		buildingRoutes.PUT("/:id/people/:personId/notification-preferences", notificationHandler.UpdatePreferences)
 
 		periodRepo := period.NewPeriodRepository(config.DB)
 		periodService := period.NewPeriodService(periodRepo)
@@ -165,30 +281,43 @@ func SetupRoutes(r *gin.Engine) {
 
 		// Invoice routes (building-scoped)
 		buildingRoutes.POST("/:id/invoices/preview", invoiceHandler.PreviewInvoice)
-		buildingRoutes.POST("/:id/invoices", invoiceHandler.CreateInvoice)
+		buildingRoutes.POST("/:id/invoices", idempotent, invoiceHandler.CreateInvoice)
 		buildingRoutes.GET("/:id/invoices", invoiceHandler.GetInvoices)
 		// Payments route must come before single invoice route to avoid conflict (more specific route first)
 		buildingRoutes.GET("/:id/invoices/:invoiceId/payments", paymentHandler.GetPaymentsByInvoice)
 		// Applied credits routes (must come before single invoice route)
 		buildingRoutes.GET("/:id/invoices/:invoiceId/available-credits", appliedCreditHandler.GetAvailableCredits)
 		buildingRoutes.POST("/:id/invoices/:invoiceId/preview-apply-credit", appliedCreditHandler.PreviewApplyCredit)
-		buildingRoutes.POST("/:id/invoices/:invoiceId/apply-credit", appliedCreditHandler.ApplyCreditToInvoice)
+		buildingRoutes.POST("/:id/invoices/:invoiceId/apply-credit", idempotent, appliedCreditHandler.ApplyCreditToInvoice)
 		buildingRoutes.GET("/:id/invoices/:invoiceId/applied-credits", appliedCreditHandler.GetAppliedCredits)
 		buildingRoutes.DELETE("/:id/invoice-applied-credits/:appliedCreditId", appliedCreditHandler.DeleteAppliedCredit)
 		// Applied discounts routes (must come before single invoice route)
 		buildingRoutes.POST("/:id/invoices/:invoiceId/preview-apply-discount", appliedDiscountHandler.PreviewApplyDiscount)
-		buildingRoutes.POST("/:id/invoices/:invoiceId/apply-discount", appliedDiscountHandler.ApplyDiscountToInvoice)
+		buildingRoutes.POST("/:id/invoices/:invoiceId/apply-discount", idempotent, appliedDiscountHandler.ApplyDiscountToInvoice)
 		buildingRoutes.GET("/:id/invoices/:invoiceId/applied-discounts", appliedDiscountHandler.GetAppliedDiscounts)
 		buildingRoutes.DELETE("/:id/invoice-applied-discounts/:appliedDiscountId", appliedDiscountHandler.DeleteAppliedDiscount)
 		buildingRoutes.PUT("/:id/invoices/:invoiceId", invoiceHandler.UpdateInvoice)
 		buildingRoutes.GET("/:id/invoices/:invoiceId", invoiceHandler.GetInvoice)
+		buildingRoutes.GET("/:id/invoices/:invoiceId/pdf", documentHandler.DownloadInvoice)
+		buildingRoutes.POST("/:id/invoices/:invoiceId/send", documentHandler.SendInvoice)
+		buildingRoutes.POST("/:id/invoices/:invoiceId/remind", dunningHandler.SendReminder)
+		buildingRoutes.GET("/:id/invoices/:invoiceId/reminders", dunningHandler.GetReminders)
 
 		// Invoice Payment routes (building-scoped)
 		buildingRoutes.POST("/:id/invoice-payments/preview", paymentHandler.PreviewInvoicePayment)
-		buildingRoutes.POST("/:id/invoice-payments", paymentHandler.CreateInvoicePayment)
+		buildingRoutes.POST("/:id/invoice-payments", idempotent, paymentHandler.CreateInvoicePayment)
 		buildingRoutes.GET("/:id/invoice-payments", paymentHandler.GetInvoicePayments)
 		buildingRoutes.GET("/:id/invoice-payments/:paymentId", paymentHandler.GetInvoicePayment)
 		buildingRoutes.PUT("/:id/invoice-payments/:paymentId", paymentHandler.UpdateInvoicePayment)
+		buildingRoutes.GET("/:id/invoice-payments/:paymentId/pdf", documentHandler.DownloadPayment)
+		buildingRoutes.POST("/:id/invoice-payments/:paymentId/send", documentHandler.SendPayment)
+		buildingRoutes.GET("/:id/customer-payments/open-invoices", customerPaymentHandler.GetOpenInvoices)
+		buildingRoutes.POST("/:id/customer-payments/preview", customerPaymentHandler.PreviewCustomerPayment)
+		buildingRoutes.POST("/:id/customer-payments", idempotent, customerPaymentHandler.CreateCustomerPayment)
+		buildingRoutes.GET("/:id/customer-payments", customerPaymentHandler.GetCustomerPayments)
+		buildingRoutes.GET("/:id/customer-payments/:paymentId", customerPaymentHandler.GetCustomerPayment)
+		buildingRoutes.POST("/:id/customer-payments/:paymentId/apply", idempotent, customerPaymentHandler.ApplyCredit)
+		buildingRoutes.POST("/:id/customer-payments/:paymentId/void", idempotent, customerPaymentHandler.VoidCustomerPayment)
 
 		// Reports routes (building-scoped)
 		buildingRoutes.GET("/:id/reports/balance-sheet", reportsHandler.GetBalanceSheet)
@@ -196,29 +325,48 @@ func SetupRoutes(r *gin.Engine) {
 		buildingRoutes.GET("/:id/reports/transaction-details-by-account", reportsHandler.GetTransactionDetailsByAccount)
 		buildingRoutes.GET("/:id/reports/customer-balance-summary", reportsHandler.GetCustomerBalanceSummary)
 		buildingRoutes.GET("/:id/reports/customer-balance-details", reportsHandler.GetCustomerBalanceDetails)
+		buildingRoutes.GET("/:id/reports/customer-statement", reportsHandler.GetCustomerStatement)
+		buildingRoutes.GET("/:id/reports/customer-statement/pdf", documentHandler.DownloadStatement)
+		buildingRoutes.POST("/:id/reports/customer-statement/send", documentHandler.SendStatement)
+		buildingRoutes.GET("/:id/reports/customer-statements", reportsHandler.GetCustomerStatements)
+		buildingRoutes.GET("/:id/reports/customer-statements/pdf", documentHandler.DownloadStatements)
 		buildingRoutes.GET("/:id/reports/profit-and-loss-standard", reportsHandler.GetProfitAndLossStandard)
 		buildingRoutes.GET("/:id/reports/profit-and-loss-by-unit", reportsHandler.GetProfitAndLossByUnit)
+		buildingRoutes.GET("/:id/reports/general-ledger", reportsHandler.GetGeneralLedger)
+		buildingRoutes.GET("/:id/reports/journal", reportsHandler.GetJournalReport)
+		buildingRoutes.GET("/:id/reports/budget-vs-actual", reportsHandler.GetBudgetVsActual)
+
+		// Budget routes (building-scoped)
+		budgetService := budgets.NewBudgetService(budgetRepo, accountRepoForInvoice, unitRepo, config.DB)
+		budgetHandler := budgets.NewBudgetHandler(budgetService)
+
+		buildingRoutes.GET("/:id/budgets", budgetHandler.GetBudget)
+		buildingRoutes.POST("/:id/budgets", budgetHandler.SaveBudget)
+		buildingRoutes.POST("/:id/budgets/copy", budgetHandler.CopyBudget)
+		buildingRoutes.DELETE("/:id/budgets/:year/accounts/:accountId", budgetHandler.DeleteBudgetLine)
 
 		// Sales Receipt routes (building-scoped)
 		buildingRoutes.POST("/:id/sales-receipts/preview", receiptHandler.PreviewSalesReceipt)
-		buildingRoutes.POST("/:id/sales-receipts", receiptHandler.CreateSalesReceipt)
+		buildingRoutes.POST("/:id/sales-receipts", idempotent, receiptHandler.CreateSalesReceipt)
 		buildingRoutes.GET("/:id/sales-receipts", receiptHandler.GetSalesReceipts)
 		buildingRoutes.PUT("/:id/sales-receipts/:receiptId", receiptHandler.UpdateSalesReceipt)
 		buildingRoutes.GET("/:id/sales-receipts/:receiptId", receiptHandler.GetSalesReceipt)
+		buildingRoutes.GET("/:id/sales-receipts/:receiptId/pdf", documentHandler.DownloadSalesReceipt)
 
 		// Check routes (building-scoped)
 		buildingRoutes.POST("/:id/checks/preview", checkHandler.PreviewCheck)
-		buildingRoutes.POST("/:id/checks", checkHandler.CreateCheck)
+		buildingRoutes.POST("/:id/checks", idempotent, checkHandler.CreateCheck)
 		buildingRoutes.GET("/:id/checks", checkHandler.GetChecks)
 		buildingRoutes.PUT("/:id/checks/:checkId", checkHandler.UpdateCheck)
 		buildingRoutes.GET("/:id/checks/:checkId", checkHandler.GetCheck)
 
 		// Credit Memo routes (building-scoped)
 		buildingRoutes.POST("/:id/credit-memos/preview", creditMemoHandler.PreviewCreditMemo)
-		buildingRoutes.POST("/:id/credit-memos", creditMemoHandler.CreateCreditMemo)
+		buildingRoutes.POST("/:id/credit-memos", idempotent, creditMemoHandler.CreateCreditMemo)
 		buildingRoutes.GET("/:id/credit-memos", creditMemoHandler.GetCreditMemosByBuildingID)
 		buildingRoutes.PUT("/:id/credit-memos/:creditMemoId", creditMemoHandler.UpdateCreditMemo)
 		buildingRoutes.GET("/:id/credit-memos/:creditMemoId", creditMemoHandler.GetCreditMemoByID)
+		buildingRoutes.GET("/:id/credit-memos/:creditMemoId/pdf", documentHandler.DownloadCreditMemo)
 
 		// Lease routes (building-scoped)
 		leaseRepo := leases.NewLeaseRepository(config.DB)
@@ -262,12 +410,68 @@ func SetupRoutes(r *gin.Engine) {
 
 		// Journal routes (building-scoped)
 		buildingRoutes.POST("/:id/journals/preview", journalHandler.PreviewJournal)
-		buildingRoutes.POST("/:id/journals", journalHandler.CreateJournal)
+		buildingRoutes.POST("/:id/journals", idempotent, journalHandler.CreateJournal)
 		buildingRoutes.GET("/:id/journals", journalHandler.GetJournals)
 		buildingRoutes.PUT("/:id/journals/:journalId", journalHandler.UpdateJournal)
 		buildingRoutes.GET("/:id/journals/:journalId", journalHandler.GetJournal)
+
+		// Webhook routes (building-scoped)
+		buildingRoutes.GET("/:id/webhooks/event-types", webhookHandler.GetEventTypes)
+		buildingRoutes.GET("/:id/webhooks", webhookHandler.GetSubscriptions)
+		buildingRoutes.POST("/:id/webhooks", webhookHandler.CreateSubscription)
+		buildingRoutes.GET("/:id/webhooks/:webhookId", webhookHandler.GetSubscription)
+		buildingRoutes.PUT("/:id/webhooks/:webhookId", webhookHandler.UpdateSubscription)
+		buildingRoutes.DELETE("/:id/webhooks/:webhookId", webhookHandler.DeleteSubscription)
+		buildingRoutes.GET("/:id/webhook-deliveries", webhookHandler.GetDeliveries)
+		buildingRoutes.POST("/:id/webhook-deliveries/:deliveryId/replay", webhookHandler.ReplayDelivery)
+		buildingRoutes.GET("/:id/events", webhookHandler.GetEvents)
+		buildingRoutes.POST("/:id/events/:eventId/replay", webhookHandler.ReplayEvent)
+		buildingRoutes.GET("/:id/jobs/types", jobHandler.GetJobTypes)
+		buildingRoutes.GET("/:id/jobs", jobHandler.GetJobs)
+		buildingRoutes.POST("/:id/jobs", jobHandler.CreateJob)
+		buildingRoutes.GET("/:id/jobs/:jobId", jobHandler.GetJob)
+		buildingRoutes.PUT("/:id/jobs/:jobId", jobHandler.UpdateJob)
+		buildingRoutes.DELETE("/:id/jobs/:jobId", jobHandler.DeleteJob)
+		buildingRoutes.POST("/:id/jobs/:jobId/run", jobHandler.TriggerJob)
+		buildingRoutes.GET("/:id/job-runs", jobHandler.GetRuns)
+		buildingRoutes.GET("/:id/job-runs/:runId", jobHandler.GetRun)
+		buildingRoutes.POST("/:id/job-runs/:runId/cancel", jobHandler.CancelRun)
+		buildingRoutes.GET("/:id/document-templates", documentHandler.GetTemplates)
+		buildingRoutes.GET("/:id/document-templates/:documentType", documentHandler.GetTemplate)
+		buildingRoutes.PUT("/:id/document-templates/:documentType", documentHandler.UpdateTemplate)
+		buildingRoutes.GET("/:id/document-templates/:documentType/logo", documentHandler.DownloadLogo)
+		buildingRoutes.POST("/:id/document-templates/:documentType/logo", documentHandler.UploadLogo)
+		buildingRoutes.DELETE("/:id/document-templates/:documentType/logo", documentHandler.DeleteLogo)
+		buildingRoutes.GET("/:id/notifications", notificationHandler.GetNotifications)
+		buildingRoutes.GET("/:id/notifications/:notificationId", notificationHandler.GetNotification)
+		buildingRoutes.POST("/:id/notifications/:notificationId/resend", notificationHandler.ResendNotification)
+		buildingRoutes.GET("/:id/notification-variables", notificationHandler.GetVariables)
+		buildingRoutes.GET("/:id/notification-templates", notificationHandler.GetTemplates)
+		buildingRoutes.GET("/:id/notification-templates/:category/:channel", notificationHandler.GetTemplate)
+		buildingRoutes.PUT("/:id/notification-templates/:category/:channel", notificationHandler.UpdateTemplate)
+		buildingRoutes.DELETE("/:id/notification-templates/:category/:channel", notificationHandler.ResetTemplate)
+		buildingRoutes.GET("/:id/dunning/steps", dunningHandler.GetSteps)
+		buildingRoutes.POST("/:id/dunning/steps", dunningHandler.CreateStep)
+		buildingRoutes.GET("/:id/dunning/steps/:stepId", dunningHandler.GetStep)
+		buildingRoutes.PUT("/:id/dunning/steps/:stepId", dunningHandler.UpdateStep)
+		buildingRoutes.DELETE("/:id/dunning/steps/:stepId", dunningHandler.DeleteStep)
+		buildingRoutes.GET("/:id/dunning/exclusions", dunningHandler.GetExclusions)
+		buildingRoutes.POST("/:id/dunning/exclusions", dunningHandler.CreateExclusion)
+		buildingRoutes.DELETE("/:id/dunning/exclusions/:personId", dunningHandler.DeleteExclusion)
+		buildingRoutes.POST("/:id/dunning/run", dunningHandler.Run)
+		buildingRoutes.GET("/:id/dunning/report", dunningHandler.GetReport)
+		buildingRoutes.GET("/:id/payment-gateway", paymentGatewayHandler.GetSettings)
+		buildingRoutes.PUT("/:id/payment-gateway", paymentGatewayHandler.SaveSettings)
+		buildingRoutes.GET("/:id/payment-intents", paymentGatewayHandler.GetIntents)
+		buildingRoutes.POST("/:id/payment-intents", paymentGatewayHandler.CreateIntent)
+		buildingRoutes.GET("/:id/payment-intents/:intentId", paymentGatewayHandler.GetIntent)
+		buildingRoutes.POST("/:id/payment-intents/:intentId/cancel", paymentGatewayHandler.CancelIntent)
+		buildingRoutes.POST("/:id/payment-intents/:intentId/simulate", paymentGatewayHandler.Simulate)
 	}
 
+	// Payment gateways post their events here; requests are verified by their signature
+	r.POST("/api/payment-gateways/:provider/webhook", paymentGatewayHandler.ReceiveWebhook)
+
 	// Legacy routes (keeping for backward compatibility)
 	unitRepo := unit.NewUnitRepository(config.DB)
 	unitService := unit.NewUnitService(unitRepo)
@@ -360,7 +564,7 @@ func SetupRoutes(r *gin.Engine) {
 	invoiceRoutes := r.Group("/api/invoices")
 	{
 		invoiceRoutes.POST("/preview", invoiceHandler.PreviewInvoice)
-		invoiceRoutes.POST("", invoiceHandler.CreateInvoice)
+		invoiceRoutes.POST("", idempotent, invoiceHandler.CreateInvoice)
 		// Payments route must come before :id route to avoid conflict
 		invoiceRoutes.GET("/:id/payments", paymentHandler.GetPaymentsByInvoice)
 		invoiceRoutes.PUT("/:id", invoiceHandler.UpdateInvoice)
@@ -371,7 +575,7 @@ func SetupRoutes(r *gin.Engine) {
 	receiptRoutes := r.Group("/api/sales-receipts")
 	{
 		receiptRoutes.POST("/preview", receiptHandler.PreviewSalesReceipt)
-		receiptRoutes.POST("", receiptHandler.CreateSalesReceipt)
+		receiptRoutes.POST("", idempotent, receiptHandler.CreateSalesReceipt)
 		receiptRoutes.PUT("/:id", receiptHandler.UpdateSalesReceipt)
 		receiptRoutes.GET("/:id", receiptHandler.GetSalesReceipt)
 	}
@@ -379,7 +583,11 @@ func SetupRoutes(r *gin.Engine) {
 	// Invoice Payment routes (legacy)
 	paymentRoutes := r.Group("/api/invoice-payments")
 	{
-		paymentRoutes.POST("", paymentHandler.CreateInvoicePayment)
+		paymentRoutes.POST("", idempotent, paymentHandler.CreateInvoicePayment)
 		paymentRoutes.GET("/:id", paymentHandler.GetInvoicePayment)
 	}
+
+	if portal != nil {
+		setupPortalRoutes(portal, logger, reportsService, documentService, paymentGatewayService)
+	}
 }
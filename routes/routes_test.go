@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/config"
+	"github.com/mysecodgit/go_accounting/src/jobs"
+	"github.com/mysecodgit/go_accounting/src/openapi"
+)
+
+// TestOpenAPIInSync fails when a route has no OpenAPI description or the committed spec and
+// client are not what `go run . openapi generate` writes
+func TestOpenAPIInSync(t *testing.T) {
+	// Routes are only registered, so the defaults do and no database is needed
+	config.App = config.Default()
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	portal := gin.New()
+	SetupRoutes(r, portal, slog.New(slog.NewTextHandler(io.Discard, nil)), jobs.NewRegistry())
+
+	if _, undocumented := openapi.Build(portal.Routes(), PortalOpenAPI...); len(undocumented) > 0 {
+		t.Errorf("portal routes without an OpenAPI description:\n  %s", strings.Join(undocumented, "\n  "))
+	}
+	doc, undocumented := openapi.Build(r.Routes(), OpenAPI...)
+	if len(undocumented) > 0 {
+		t.Fatalf("routes without an OpenAPI description:\n  %s", strings.Join(undocumented, "\n  "))
+	}
+
+	stale, err := openapi.Stale(doc, "..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) > 0 {
+		t.Errorf("%s out of date, run: go run . openapi generate", strings.Join(stale, " and "))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type AccountTypeHandler struct {
@@ -19,19 +20,19 @@ func NewAccountTypeHandler(service *AccountTypeService) *AccountTypeHandler {
 func (h *AccountTypeHandler) CreateAccountType(c *gin.Context) {
 	var accountType AccountType
 	if err := c.ShouldBindJSON(&accountType); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.CreateAccountType(accountType)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -42,7 +43,7 @@ func (h *AccountTypeHandler) CreateAccountType(c *gin.Context) {
 func (h *AccountTypeHandler) GetAccountTypes(c *gin.Context) {
 	accountTypes, err := h.service.GetAllAccountTypes()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -55,17 +56,17 @@ func (h *AccountTypeHandler) GetAccountType(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	accountType, err := h.service.GetAccountTypeByID(int(id))
 	if err != nil {
 		if err.Error() == "id does not exist" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			apperrors.Respond(c, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -78,25 +79,25 @@ func (h *AccountTypeHandler) UpdateAccountType(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	var accountType AccountType
 	if err := c.ShouldBindJSON(&accountType); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.UpdateAccountType(id, accountType)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -0,0 +1,10 @@
+package account_types
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"AccountTypeHandler.GetAccountTypes":   {Summary: "List account types", Response: []AccountTypeResponse{}},
+	"AccountTypeHandler.GetAccountType":    {Summary: "Get an account type", Response: AccountTypeResponse{}},
+	"AccountTypeHandler.CreateAccountType": {Summary: "Create an account type", Request: AccountType{}, Response: AccountType{}},
+	"AccountTypeHandler.UpdateAccountType": {Summary: "Update an account type", Request: AccountType{}, Response: AccountType{}},
+}
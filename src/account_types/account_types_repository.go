@@ -2,7 +2,8 @@ package account_types
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type AccountTypeRepository interface {
@@ -61,7 +62,7 @@ func (r *accountTypeRepo) GetByID(id int) (AccountType, error) {
 		Scan(&accountType.ID, &accountType.TypeName, &accountType.Type, &accountType.SubType, &accountType.TypeStatus, &accountType.CreatedAt, &accountType.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return accountType, fmt.Errorf("id does not exist")
+		return accountType, apperrors.NotFound("account type")
 	}
 
 	return accountType, err
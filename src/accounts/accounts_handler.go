@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type AccountHandler struct {
@@ -19,25 +20,19 @@ func NewAccountHandler(service *AccountService) *AccountHandler {
 func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	var account Account
 	if err := c.ShouldBindJSON(&account); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.CreateAccount(account)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		// Check if it's a duplicate error
-		if otherErrors.Error() == "building cannot have duplicate account number" || 
-		   otherErrors.Error() == "building cannot have duplicate account name" {
-			c.JSON(http.StatusConflict, gin.H{"error": otherErrors.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -48,7 +43,7 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 func (h *AccountHandler) GetAccounts(c *gin.Context) {
 	accounts, err := h.service.GetAllAccounts()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -61,13 +56,13 @@ func (h *AccountHandler) GetAccountsByBuilding(c *gin.Context) {
 	buildingID, err := strconv.Atoi(buildingIDStr)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
 		return
 	}
 
 	accounts, err := h.service.GetAccountsByBuildingID(buildingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -84,17 +79,17 @@ func (h *AccountHandler) GetAccount(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	account, err := h.service.GetAccountByID(int(id))
 	if err != nil {
 		if err.Error() == "id does not exist" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			apperrors.Respond(c, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -111,30 +106,25 @@ func (h *AccountHandler) UpdateAccount(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	var account Account
 	if err := c.ShouldBindJSON(&account); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.UpdateAccount(id, account)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		// Check if it's a duplicate error for account name (only account name validation in update)
-		if otherErrors.Error() == "building cannot have duplicate account name" {
-			c.JSON(http.StatusConflict, gin.H{"error": otherErrors.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
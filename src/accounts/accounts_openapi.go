@@ -0,0 +1,11 @@
+package accounts
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"AccountHandler.GetAccounts":           {Summary: "List accounts of all buildings", Response: []AccountResponse{}},
+	"AccountHandler.GetAccountsByBuilding": {Summary: "List a building's chart of accounts", Response: []AccountResponse{}},
+	"AccountHandler.GetAccount":            {Summary: "Get an account", Response: AccountResponse{}},
+	"AccountHandler.CreateAccount":         {Summary: "Create an account", Request: Account{}, Response: Account{}},
+	"AccountHandler.UpdateAccount":         {Summary: "Update an account", Request: Account{}, Response: Account{}},
+}
@@ -2,10 +2,10 @@ package accounts
 
 import (
 	"database/sql"
-	"fmt"
 	"strings"
 
 	"github.com/mysecodgit/go_accounting/src/account_types"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/building"
 )
 
@@ -36,7 +36,7 @@ func (r *accountRepo) Create(account Account) (Account, error) {
 		return account, err
 	}
 	if exists {
-		return account, fmt.Errorf("building cannot have duplicate account number")
+		return account, apperrors.Conflict("building cannot have duplicate account number")
 	}
 
 	// Check for duplicate account name before inserting
@@ -45,7 +45,7 @@ func (r *accountRepo) Create(account Account) (Account, error) {
 		return account, err
 	}
 	if exists {
-		return account, fmt.Errorf("building cannot have duplicate account name")
+		return account, apperrors.Conflict("building cannot have duplicate account name")
 	}
 
 	result, err := r.db.Exec("INSERT INTO accounts (account_number, account_name, account_type, building_id, isDefault) VALUES (?, ?, ?, ?, ?)",
@@ -55,12 +55,12 @@ func (r *accountRepo) Create(account Account) (Account, error) {
 		// Check if it's a duplicate key error
 		if strings.Contains(err.Error(), "Duplicate entry") || strings.Contains(err.Error(), "UNIQUE constraint") {
 			if strings.Contains(err.Error(), "account_number") {
-				return account, fmt.Errorf("building cannot have duplicate account number")
+				return account, apperrors.Conflict("building cannot have duplicate account number")
 			}
 			if strings.Contains(err.Error(), "account_name") {
-				return account, fmt.Errorf("building cannot have duplicate account name")
+				return account, apperrors.Conflict("building cannot have duplicate account name")
 			}
-			return account, fmt.Errorf("duplicate entry detected")
+			return account, apperrors.Conflict("duplicate entry detected")
 		}
 		return account, err
 	}
@@ -82,7 +82,7 @@ func (r *accountRepo) Update(account Account, id int) (Account, error) {
 		return account, err
 	}
 	if exists {
-		return account, fmt.Errorf("building cannot have duplicate account name")
+		return account, apperrors.Conflict("building cannot have duplicate account name")
 	}
 
 	_, err = r.db.Exec("UPDATE accounts SET account_number=?, account_name=?, account_type=?, building_id=?, isDefault=?, updated_at=NOW() WHERE id=?",
@@ -92,9 +92,9 @@ func (r *accountRepo) Update(account Account, id int) (Account, error) {
 		// Check if it's a duplicate key error for account_name
 		if strings.Contains(err.Error(), "Duplicate entry") || strings.Contains(err.Error(), "UNIQUE constraint") {
 			if strings.Contains(err.Error(), "account_name") {
-				return account, fmt.Errorf("building cannot have duplicate account name")
+				return account, apperrors.Conflict("building cannot have duplicate account name")
 			}
-			return account, fmt.Errorf("duplicate entry detected")
+			return account, apperrors.Conflict("duplicate entry detected")
 		}
 		return account, err
 	}
@@ -124,7 +124,7 @@ func (r *accountRepo) GetByID(id int) (Account, account_types.AccountType, build
 			&b.ID, &b.Name, &b.CreatedAt, &b.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return account, accountType, b, fmt.Errorf("id does not exist")
+		return account, accountType, b, apperrors.NotFound("account")
 	}
 
 	return account, accountType, b, err
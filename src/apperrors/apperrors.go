@@ -0,0 +1,197 @@
+package apperrors
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// Code is a stable, machine-readable error identifier. Clients branch on codes, so
+// existing values must not be renamed.
+type Code string
+
+const (
+	CodeBadRequest           Code = "bad_request"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeInvalidJSON          Code = "invalid_json"
+	CodeValidation           Code = "validation_failed"
+	CodeNotFound             Code = "not_found"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodeConflict             Code = "conflict"
+	CodeBusinessRule         Code = "business_rule_violation"
+	CodeVersionConflict      Code = "version_conflict"
+	CodePreconditionRequired Code = "precondition_required"
+	CodeIdempotencyMismatch  Code = "idempotency_key_reused"
+	CodeRequestInProgress    Code = "request_in_progress"
+	CodeInternal             Code = "internal_error"
+)
+
+var statuses = map[Code]int{
+	CodeBadRequest:           http.StatusBadRequest,
+	CodeUnauthorized:         http.StatusUnauthorized,
+	CodeInvalidJSON:          http.StatusBadRequest,
+	CodeValidation:           http.StatusBadRequest,
+	CodeNotFound:             http.StatusNotFound,
+	CodeMethodNotAllowed:     http.StatusMethodNotAllowed,
+	CodeConflict:             http.StatusConflict,
+	CodeBusinessRule:         http.StatusUnprocessableEntity,
+	CodeVersionConflict:      http.StatusPreconditionFailed,
+	CodePreconditionRequired: http.StatusPreconditionRequired,
+	CodeIdempotencyMismatch:  http.StatusUnprocessableEntity,
+	CodeRequestInProgress:    http.StatusConflict,
+	CodeInternal:             http.StatusInternalServerError,
+}
+
+// Field-level codes
+const (
+	FieldInvalid     = "invalid"
+	FieldInvalidType = "invalid_type"
+)
+
+// FieldError points at one invalid input. Field is a JSON path such as "items[0].qty".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error with a code that decides the HTTP status
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Details map[string]interface{}
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status returns the HTTP status for the error's code
+func (e *Error) Status() int {
+	if status, ok := statuses[e.Code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// WithDetail adds extra data for clients, e.g. the current version on a conflict
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+	e.Details[key] = value
+	return e
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap keeps the cause for logs and errors.Is while the code decides the response
+func Wrap(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func BadRequest(message string) *Error {
+	return New(CodeBadRequest, message)
+}
+
+func BadRequestf(format string, args ...interface{}) *Error {
+	return Newf(CodeBadRequest, format, args...)
+}
+
+// Rule reports a request that is well formed but breaks an accounting or business rule
+func Rule(message string) *Error {
+	return New(CodeBusinessRule, message)
+}
+
+func Rulef(format string, args ...interface{}) *Error {
+	return Newf(CodeBusinessRule, format, args...)
+}
+
+func Conflict(message string) *Error {
+	return New(CodeConflict, message)
+}
+
+func Conflictf(format string, args ...interface{}) *Error {
+	return Newf(CodeConflict, format, args...)
+}
+
+// NotFound builds "<resource> not found", e.g. NotFound("invoice")
+func NotFound(resource string) *Error {
+	return New(CodeNotFound, resource+" not found").WithDetail("resource", resource)
+}
+
+// Lookup classifies an error from loading a resource: a missing row becomes NotFound,
+// a domain error is kept, and anything else is an internal error.
+func Lookup(resource string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(resource)
+	}
+	return Wrap(CodeInternal, "failed to load "+resource, err)
+}
+
+func Internal(err error) *Error {
+	return Wrap(CodeInternal, "internal error", err)
+}
+
+// Validation builds a validation error from the field → message maps returned by Validate methods
+func Validation(fields map[string]string) *Error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]FieldError, 0, len(names))
+	for _, name := range names {
+		list = append(list, FieldError{Field: name, Code: FieldInvalid, Message: fields[name]})
+	}
+	return Fields(list...)
+}
+
+func Fields(fields ...FieldError) *Error {
+	return &Error{Code: CodeValidation, Message: "Validation failed", Fields: fields}
+}
+
+// As returns the domain error in err's chain, if any
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// CodeOf returns the code the error will be reported with
+func CodeOf(err error) Code {
+	if appErr, ok := As(err); ok {
+		return appErr.Code
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return CodeNotFound
+	}
+	return CodeInternal
+}
@@ -0,0 +1,121 @@
+package apperrors
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/utils"
+)
+
+// Envelope is the body of every error response
+type Envelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    Code                   `json:"code"`
+	Message string                 `json:"message"`
+	Fields  []FieldError           `json:"fields,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Response converts any error into its status and envelope. Errors without a domain
+// error in their chain are internal errors, except a missing row which is not found. Their
+// text may be a database message, so it is only logged, never sent.
+func Response(err error) (int, Envelope) {
+	appErr, ok := As(err)
+	if !ok {
+		appErr = &Error{Code: CodeOf(err), Message: "Internal server error"}
+		if appErr.Code == CodeNotFound {
+			appErr.Message = "not found"
+		}
+	}
+
+	message := appErr.Message
+	// Keep the context added by callers that wrapped the domain error, but never the
+	// cause of a wrapped error, which may be a database message
+	if ok && err != error(appErr) && appErr.Err == nil {
+		message = err.Error()
+	}
+
+	return appErr.Status(), Envelope{ErrorBody{
+		Code:    appErr.Code,
+		Message: message,
+		Fields:  appErr.Fields,
+		Details: appErr.Details,
+	}}
+}
+
+// Respond writes err as an error response
+func Respond(c *gin.Context, err error) {
+	status, body := Response(err)
+	if status >= 500 {
+		logging.FromGin(c).Error("request failed", "error", err.Error())
+	}
+	c.JSON(status, body)
+}
+
+// Abort writes err as an error response and stops the handler chain
+func Abort(c *gin.Context, err error) {
+	Respond(c, err)
+	c.Abort()
+}
+
+// FromBinding converts an error from ShouldBindJSON into a validation error with field paths
+func FromBinding(err error) *Error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		return Fields(FieldError{
+			Field:   field,
+			Code:    FieldInvalidType,
+			Message: "Must be a " + jsonTypeName(typeErr.Type),
+		})
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, FieldError{
+				Field:   utils.FieldPath(fe),
+				Code:    fe.Tag(),
+				Message: utils.MsgForFieldError(fe),
+			})
+		}
+		return Fields(fields...)
+	}
+
+	if errors.Is(err, io.EOF) {
+		return New(CodeInvalidJSON, "Request body is required")
+	}
+	return Wrap(CodeInvalidJSON, "Invalid JSON", err)
+}
+
+// jsonTypeName names a Go type the way it appears in a JSON body
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	}
+	return "object"
+}
@@ -0,0 +1,39 @@
+package budgets
+
+type Budget struct {
+	ID         int     `json:"id"`
+	BuildingID int     `json:"building_id"`
+	AccountID  int     `json:"account_id"`
+	UnitID     *int    `json:"unit_id"`
+	Year       int     `json:"year"`
+	Month      int     `json:"month"`
+	Amount     float64 `json:"amount"`
+	CreatedAt  string  `json:"created_at"`
+	UpdatedAt  string  `json:"updated_at"`
+}
+
+func (b *Budget) Validate() map[string]string {
+	errors := make(map[string]string)
+
+	if b.BuildingID <= 0 {
+		errors["building_id"] = "Building ID must be greater than 0"
+	}
+
+	if b.AccountID <= 0 {
+		errors["account_id"] = "Account ID must be greater than 0"
+	}
+
+	if b.Year < 1900 || b.Year > 9999 {
+		errors["year"] = "Year must be a valid four digit year"
+	}
+
+	if b.Month < 1 || b.Month > 12 {
+		errors["month"] = "Month must be between 1 and 12"
+	}
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return errors
+}
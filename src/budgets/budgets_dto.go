@@ -0,0 +1,38 @@
+package budgets
+
+type BudgetLineInput struct {
+	AccountID int         `json:"account_id"`
+	UnitID    *int        `json:"unit_id"` // Optional: budget for a specific unit
+	Months    [12]float64 `json:"months"`  // January..December amounts
+}
+
+type SaveBudgetRequest struct {
+	BuildingID int               `json:"building_id"`
+	Year       int               `json:"year"`
+	Lines      []BudgetLineInput `json:"lines"`
+}
+
+type CopyBudgetRequest struct {
+	BuildingID    int     `json:"building_id"`
+	FromYear      int     `json:"from_year"`
+	ToYear        int     `json:"to_year"`
+	UpliftPercent float64 `json:"uplift_percent"` // e.g. 5 adds 5% to every amount
+	Overwrite     bool    `json:"overwrite"`      // Replace existing lines in the target year
+}
+
+type BudgetLine struct {
+	AccountID     int         `json:"account_id"`
+	AccountNumber int         `json:"account_number"`
+	AccountName   string      `json:"account_name"`
+	AccountType   string      `json:"account_type"` // "income" or "expense"
+	UnitID        *int        `json:"unit_id"`
+	Months        [12]float64 `json:"months"`
+	Total         float64     `json:"total"`
+}
+
+type BudgetResponse struct {
+	BuildingID int          `json:"building_id"`
+	Year       int          `json:"year"`
+	Lines      []BudgetLine `json:"lines"`
+	Total      float64      `json:"total"`
+}
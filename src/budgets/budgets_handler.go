@@ -0,0 +1,142 @@
+package budgets
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+)
+
+type BudgetHandler struct {
+	service *BudgetService
+}
+
+func NewBudgetHandler(service *BudgetService) *BudgetHandler {
+	return &BudgetHandler{service: service}
+}
+
+// GET /buildings/:id/budgets?year=2025
+func (h *BudgetHandler) GetBudget(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Year is required"))
+		return
+	}
+
+	budget, err := h.service.GetBudget(buildingID, year)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// POST /buildings/:id/budgets
+func (h *BudgetHandler) SaveBudget(c *gin.Context) {
+	var req SaveBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	req.BuildingID = buildingID
+
+	response, validationErr, otherErrors := h.service.SaveBudget(req)
+
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+
+	if otherErrors != nil {
+		apperrors.Respond(c, otherErrors)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /buildings/:id/budgets/copy
+func (h *BudgetHandler) CopyBudget(c *gin.Context) {
+	var req CopyBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	req.BuildingID = buildingID
+
+	response, validationErr, otherErrors := h.service.CopyBudget(req)
+
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+
+	if otherErrors != nil {
+		apperrors.Respond(c, otherErrors)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /buildings/:id/budgets/:year/accounts/:accountId?unit_id=
+func (h *BudgetHandler) DeleteBudgetLine(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid year"))
+		return
+	}
+
+	accountID, err := strconv.Atoi(c.Param("accountId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Account ID"))
+		return
+	}
+
+	var unitID *int
+	if unitIDStr := c.Query("unit_id"); unitIDStr != "" {
+		id, err := strconv.Atoi(unitIDStr)
+		if err != nil {
+			apperrors.Respond(c, apperrors.BadRequest("Invalid Unit ID"))
+			return
+		}
+		unitID = &id
+	}
+
+	if err := h.service.DeleteBudgetLine(buildingID, year, accountID, unitID); err != nil {
+		if err.Error() == "budget line not found" {
+			apperrors.Respond(c, err)
+			return
+		}
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Budget line deleted successfully"})
+}
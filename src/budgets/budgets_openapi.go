@@ -0,0 +1,18 @@
+package budgets
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"BudgetHandler.GetBudget": {
+		Summary:  "Get a year's budget",
+		Query:    []openapi.Param{{Name: "year", Type: "integer", Format: "int32", Required: true}},
+		Response: BudgetResponse{},
+	},
+	"BudgetHandler.SaveBudget": {Summary: "Save budget lines", Request: SaveBudgetRequest{}, Response: BudgetResponse{}},
+	"BudgetHandler.CopyBudget": {Summary: "Copy a budget to another year", Request: CopyBudgetRequest{}, Response: BudgetResponse{}},
+	"BudgetHandler.DeleteBudgetLine": {
+		Summary:  "Delete an account's budget line",
+		Query:    []openapi.Param{{Name: "unit_id", Type: "integer", Format: "int32", Description: "Delete the unit's line instead of the building-wide line"}},
+		Response: openapi.Message{},
+	},
+}
@@ -0,0 +1,59 @@
+package budgets
+
+import (
+	"database/sql"
+)
+
+type BudgetRepository interface {
+	GetByBuildingAndYear(buildingID int, year int) ([]Budget, error)
+	GetByBuildingYearAndUnit(buildingID int, year int, unitID int) ([]Budget, error)
+	YearExists(buildingID int, year int) (bool, error)
+}
+
+type budgetRepo struct {
+	db *sql.DB
+}
+
+func NewBudgetRepository(db *sql.DB) BudgetRepository {
+	return &budgetRepo{db: db}
+}
+
+func (r *budgetRepo) GetByBuildingAndYear(buildingID int, year int) ([]Budget, error) {
+	rows, err := r.db.Query("SELECT id, building_id, account_id, unit_id, `year`, `month`, amount, created_at, updated_at FROM budgets WHERE building_id = ? AND `year` = ? ORDER BY account_id, unit_id, `month`", buildingID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanBudgets(rows)
+}
+
+// GetByBuildingYearAndUnit returns budget rows set for a specific unit
+func (r *budgetRepo) GetByBuildingYearAndUnit(buildingID int, year int, unitID int) ([]Budget, error) {
+	rows, err := r.db.Query("SELECT id, building_id, account_id, unit_id, `year`, `month`, amount, created_at, updated_at FROM budgets WHERE building_id = ? AND `year` = ? AND unit_id = ? ORDER BY account_id, `month`", buildingID, year, unitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanBudgets(rows)
+}
+
+func (r *budgetRepo) YearExists(buildingID int, year int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM budgets WHERE building_id = ? AND `year` = ?)", buildingID, year).Scan(&exists)
+	return exists, err
+}
+
+func scanBudgets(rows *sql.Rows) ([]Budget, error) {
+	budgets := []Budget{}
+	for rows.Next() {
+		var b Budget
+		err := rows.Scan(&b.ID, &b.BuildingID, &b.AccountID, &b.UnitID, &b.Year, &b.Month, &b.Amount, &b.CreatedAt, &b.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, nil
+}
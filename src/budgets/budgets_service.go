@@ -0,0 +1,297 @@
+package budgets
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mysecodgit/go_accounting/src/account_types"
+	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/unit"
+)
+
+type BudgetService struct {
+	budgetRepo  BudgetRepository
+	accountRepo accounts.AccountRepository
+	unitRepo    unit.UnitRepository
+	db          *sql.DB
+}
+
+func NewBudgetService(budgetRepo BudgetRepository, accountRepo accounts.AccountRepository, unitRepo unit.UnitRepository, db *sql.DB) *BudgetService {
+	return &BudgetService{
+		budgetRepo:  budgetRepo,
+		accountRepo: accountRepo,
+		unitRepo:    unitRepo,
+		db:          db,
+	}
+}
+
+// budgetLineKey identifies one budget line (account + optional unit)
+type budgetLineKey struct {
+	accountID int
+	unitID    int // 0 means building-wide (unit_id IS NULL)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// budgetAccountType returns "income" or "expense" for budgetable accounts, "" otherwise
+func budgetAccountType(accountType account_types.AccountType) string {
+	typeLower := strings.ToLower(accountType.Type)
+	if typeLower == "income" || typeLower == "expense" {
+		return typeLower
+	}
+	return ""
+}
+
+// SaveBudget replaces the monthly amounts of the given lines for a year
+// All operations are wrapped in a database transaction to ensure atomicity
+func (s *BudgetService) SaveBudget(req SaveBudgetRequest) (*BudgetResponse, map[string]string, error) {
+	if req.BuildingID <= 0 {
+		return nil, map[string]string{"building_id": "Building ID must be greater than 0"}, nil
+	}
+	if req.Year < 1900 || req.Year > 9999 {
+		return nil, map[string]string{"year": "Year must be a valid four digit year"}, nil
+	}
+	if len(req.Lines) == 0 {
+		return nil, map[string]string{"lines": "Budget must have at least one line"}, nil
+	}
+
+	// Validate accounts and units: must belong to the building, accounts must be income or expense
+	for i, line := range req.Lines {
+		account, accountType, _, err := s.accountRepo.GetByID(line.AccountID)
+		if err != nil {
+			return nil, map[string]string{fmt.Sprintf("lines[%d].account_id", i): "Account does not exist"}, nil
+		}
+		if account.BuildingID != req.BuildingID {
+			return nil, map[string]string{fmt.Sprintf("lines[%d].account_id", i): "Account does not belong to this building"}, nil
+		}
+		if budgetAccountType(accountType) == "" {
+			return nil, map[string]string{fmt.Sprintf("lines[%d].account_id", i): "Budgets can only be set for income and expense accounts"}, nil
+		}
+		if line.UnitID != nil {
+			lineUnit, _, err := s.unitRepo.GetByID(*line.UnitID)
+			if err != nil {
+				return nil, map[string]string{fmt.Sprintf("lines[%d].unit_id", i): "Unit does not exist"}, nil
+			}
+			if lineUnit.BuildingID != req.BuildingID {
+				return nil, map[string]string{fmt.Sprintf("lines[%d].unit_id", i): "Unit does not belong to this building"}, nil
+			}
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	for _, line := range req.Lines {
+		var unitID interface{}
+		if line.UnitID != nil {
+			unitID = *line.UnitID
+		} else {
+			unitID = nil
+		}
+
+		// Replace any existing amounts for this account/unit/year (<=> matches NULL unit_id)
+		_, err = tx.Exec("DELETE FROM budgets WHERE building_id = ? AND `year` = ? AND account_id = ? AND unit_id <=> ?",
+			req.BuildingID, req.Year, line.AccountID, unitID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to delete existing budget: %w", err)
+		}
+
+		for m, amount := range line.Months {
+			_, err = tx.Exec("INSERT INTO budgets (building_id, account_id, unit_id, `year`, `month`, amount) VALUES (?, ?, ?, ?, ?, ?)",
+				req.BuildingID, line.AccountID, unitID, req.Year, m+1, round2(amount))
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to create budget: %w", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	response, err := s.GetBudget(req.BuildingID, req.Year)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// CopyBudget copies all budget lines from one year to another, applying a percentage uplift
+func (s *BudgetService) CopyBudget(req CopyBudgetRequest) (*BudgetResponse, map[string]string, error) {
+	errs := make(map[string]string)
+	if req.BuildingID <= 0 {
+		errs["building_id"] = "Building ID must be greater than 0"
+	}
+	if req.FromYear < 1900 || req.FromYear > 9999 {
+		errs["from_year"] = "From year must be a valid four digit year"
+	}
+	if req.ToYear < 1900 || req.ToYear > 9999 {
+		errs["to_year"] = "To year must be a valid four digit year"
+	}
+	if req.FromYear == req.ToYear {
+		errs["to_year"] = "To year must be different from from year"
+	}
+	if req.UpliftPercent <= -100 {
+		errs["uplift_percent"] = "Uplift percent must be greater than -100"
+	}
+	if len(errs) > 0 {
+		return nil, errs, nil
+	}
+
+	source, err := s.budgetRepo.GetByBuildingAndYear(req.BuildingID, req.FromYear)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get budget for %d: %w", req.FromYear, err)
+	}
+	if len(source) == 0 {
+		return nil, nil, apperrors.Newf(apperrors.CodeNotFound, "no budget found for %d", req.FromYear)
+	}
+
+	targetExists, err := s.budgetRepo.YearExists(req.BuildingID, req.ToYear)
+	if err != nil {
+		return nil, nil, err
+	}
+	if targetExists && !req.Overwrite {
+		return nil, nil, apperrors.Conflictf("budget already exists for %d", req.ToYear)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if targetExists {
+		_, err = tx.Exec("DELETE FROM budgets WHERE building_id = ? AND `year` = ?", req.BuildingID, req.ToYear)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to delete existing budget: %w", err)
+		}
+	}
+
+	factor := 1 + req.UpliftPercent/100
+	for _, b := range source {
+		var unitID interface{}
+		if b.UnitID != nil {
+			unitID = *b.UnitID
+		} else {
+			unitID = nil
+		}
+
+		_, err = tx.Exec("INSERT INTO budgets (building_id, account_id, unit_id, `year`, `month`, amount) VALUES (?, ?, ?, ?, ?, ?)",
+			req.BuildingID, b.AccountID, unitID, req.ToYear, b.Month, round2(b.Amount*factor))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create budget: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	response, err := s.GetBudget(req.BuildingID, req.ToYear)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// DeleteBudgetLine removes all monthly amounts of one account/unit for a year
+func (s *BudgetService) DeleteBudgetLine(buildingID int, year int, accountID int, unitID *int) error {
+	var unitIDValue interface{}
+	if unitID != nil {
+		unitIDValue = *unitID
+	} else {
+		unitIDValue = nil
+	}
+
+	result, err := s.db.Exec("DELETE FROM budgets WHERE building_id = ? AND `year` = ? AND account_id = ? AND unit_id <=> ?",
+		buildingID, year, accountID, unitIDValue)
+	if err != nil {
+		return err
+	}
+
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return apperrors.NotFound("budget line")
+	}
+
+	return nil
+}
+
+// GetBudget returns the budget of a year grouped into one line per account/unit
+func (s *BudgetService) GetBudget(buildingID int, year int) (*BudgetResponse, error) {
+	rows, err := s.budgetRepo.GetByBuildingAndYear(buildingID, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	accountsList, accountTypesList, _, err := s.accountRepo.GetByBuildingID(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	// Group monthly rows into lines
+	linesByKey := make(map[budgetLineKey]*BudgetLine)
+	keysByAccount := make(map[int][]budgetLineKey)
+	for _, b := range rows {
+		key := budgetLineKey{accountID: b.AccountID}
+		if b.UnitID != nil {
+			key.unitID = *b.UnitID
+		}
+
+		line, exists := linesByKey[key]
+		if !exists {
+			line = &BudgetLine{AccountID: b.AccountID, UnitID: b.UnitID}
+			linesByKey[key] = line
+			keysByAccount[b.AccountID] = append(keysByAccount[b.AccountID], key)
+		}
+
+		line.Months[b.Month-1] = b.Amount
+		line.Total += b.Amount
+	}
+
+	// Order lines by account number (accounts are already sorted)
+	lines := []BudgetLine{}
+	total := 0.0
+	for i, account := range accountsList {
+		for _, key := range keysByAccount[account.ID] {
+			line := linesByKey[key]
+			line.AccountNumber = account.AccountNumber
+			line.AccountName = account.AccountName
+			line.AccountType = budgetAccountType(accountTypesList[i])
+			line.Total = round2(line.Total)
+			lines = append(lines, *line)
+			total += line.Total
+		}
+	}
+
+	return &BudgetResponse{
+		BuildingID: buildingID,
+		Year:       year,
+		Lines:      lines,
+		Total:      round2(total),
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type BuildingHandler struct {
@@ -19,19 +20,19 @@ func NewBuildingHandler(service *BuildingService) *BuildingHandler {
 func (h *BuildingHandler) CreateBuilding(c *gin.Context) {
 	var building Building
 	if err := c.ShouldBindJSON(&building); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.CreateBuilding(building)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -42,7 +43,7 @@ func (h *BuildingHandler) CreateBuilding(c *gin.Context) {
 func (h *BuildingHandler) GetBuildings(c *gin.Context) {
 	buildings, err := h.service.GetAllBuildings()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -55,13 +56,13 @@ func (h *BuildingHandler) GetBuilding(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	building, err := h.service.GetBuildingByID(int(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -74,25 +75,25 @@ func (h *BuildingHandler) UpdateBuilding(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	var building Building
 	if err := c.ShouldBindJSON(&building); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.UpdateBuilding(id, building)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -0,0 +1,10 @@
+package building
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"BuildingHandler.GetBuildings":   {Summary: "List buildings", Response: []Building{}},
+	"BuildingHandler.GetBuilding":    {Summary: "Get a building", Response: Building{}},
+	"BuildingHandler.CreateBuilding": {Summary: "Create a building", Request: Building{}, Response: Building{}},
+	"BuildingHandler.UpdateBuilding": {Summary: "Update a building", Request: Building{}, Response: Building{}},
+}
@@ -2,7 +2,8 @@ package building
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type BuildingRepository interface {
@@ -61,7 +62,7 @@ func (r *buildingRepo) GetByID(id int) (Building, error) {
 		Scan(&building.ID, &building.Name, &building.CreatedAt, &building.UpdatedAt)
 	
 	if err == sql.ErrNoRows {
-		return building, fmt.Errorf("id does not exist")
+		return building, apperrors.NotFound("building")
 	}
 	
 	return building, err
@@ -13,6 +13,7 @@ type Check struct {
 	BuildingID       int     `json:"building_id"`
 	Memo             *string `json:"memo"`
 	TotalAmount      float64 `json:"total_amount"`
+	Version          int     `json:"version"`
 	CreatedAt        string  `json:"created_at"`
 }
 
@@ -51,6 +51,9 @@ type UpdateCheckRequest struct {
 	Memo             *string            `json:"memo"`
 	TotalAmount      float64            `json:"total_amount"`
 	ExpenseLines     []ExpenseLineInput `json:"expense_lines"`
+
+	// Version from the If-Match header; the update fails if the document has moved on
+	ExpectedVersion int `json:"-"`
 }
 
 type CheckResponse struct {
@@ -5,6 +5,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type CheckHandler struct {
@@ -19,7 +22,7 @@ func NewCheckHandler(service *CheckService) *CheckHandler {
 func (h *CheckHandler) PreviewCheck(c *gin.Context) {
 	var req CreateCheckRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -38,19 +41,19 @@ func (h *CheckHandler) PreviewCheck(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
 	preview, err := h.service.PreviewCheck(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -61,7 +64,7 @@ func (h *CheckHandler) PreviewCheck(c *gin.Context) {
 func (h *CheckHandler) CreateCheck(c *gin.Context) {
 	var req CreateCheckRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -80,19 +83,19 @@ func (h *CheckHandler) CreateCheck(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.CreateCheck(req, userID)
+	response, err := h.service.WithLogger(logging.FromGin(c)).CreateCheck(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -101,16 +104,21 @@ func (h *CheckHandler) CreateCheck(c *gin.Context) {
 
 // PUT /checks/:id or /buildings/:id/checks/:checkId
 func (h *CheckHandler) UpdateCheck(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateCheckRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	checkIDStr := c.Param("checkId")
 	id, err := strconv.Atoi(checkIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Check ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Check ID"))
 		return
 	}
 	req.ID = id
@@ -128,22 +136,25 @@ func (h *CheckHandler) UpdateCheck(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.UpdateCheck(req, userID)
+	req.ExpectedVersion = version
+	response, err := h.service.WithLogger(logging.FromGin(c)).UpdateCheck(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Check.Version)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -154,19 +165,25 @@ func (h *CheckHandler) GetChecks(c *gin.Context) {
 		buildingIDStr = c.Query("building_id")
 	}
 	if buildingIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	params, validationErrors := checkListSpec.Parse(c.Request.URL.Query())
+	if validationErrors != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
 		return
 	}
 
-	checks, err := h.service.GetChecks(buildingID)
+	checks, err := h.service.GetChecks(buildingID, params)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -180,21 +197,22 @@ func (h *CheckHandler) GetCheck(c *gin.Context) {
 		checkIDStr = c.Param("id")
 	}
 	if checkIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Check ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Check ID is required"))
 		return
 	}
 
 	id, err := strconv.Atoi(checkIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Check ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Check ID"))
 		return
 	}
 
 	checkResponse, err := h.service.GetCheckDetails(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, checkResponse.Check.Version)
 	c.JSON(http.StatusOK, checkResponse)
 }
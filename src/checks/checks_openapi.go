@@ -0,0 +1,18 @@
+package checks
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"CheckHandler.PreviewCheck": {
+		Summary:     "Preview a check's postings",
+		Description: "Validates the check and returns the splits it would post without saving anything.",
+		UserID:      true, Request: CreateCheckRequest{}, Response: CheckPreviewResponse{},
+	},
+	"CheckHandler.CreateCheck": {Summary: "Create and post a check", UserID: true, Idempotent: true, Request: CreateCheckRequest{}, Response: CheckResponse{}},
+	"CheckHandler.GetChecks":   {Summary: "List checks", Response: pagination.Page[Check]{}, List: &checkListSpec},
+	"CheckHandler.GetCheck":    {Summary: "Get a check with its expense lines and postings", Response: CheckResponse{}},
+	"CheckHandler.UpdateCheck": {Summary: "Update a check and repost it", UserID: true, IfMatch: true, Request: UpdateCheckRequest{}, Response: CheckResponse{}},
+}
@@ -2,7 +2,9 @@ package checks
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 )
 
 type CheckRepository interface {
@@ -10,6 +12,7 @@ type CheckRepository interface {
 	Update(check Check) (Check, error)
 	GetByID(id int) (Check, error)
 	GetByBuildingID(buildingID int) ([]Check, error)
+	List(buildingID int, params pagination.Params) ([]Check, int, error)
 }
 
 type checkRepo struct {
@@ -45,8 +48,8 @@ func (r *checkRepo) Create(check Check) (Check, error) {
 	id, _ := result.LastInsertId()
 	check.ID = int(id)
 
-	err = r.db.QueryRow("SELECT id, transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount, created_at FROM checks WHERE id = ?", check.ID).
-		Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt)
+	err = r.db.QueryRow("SELECT id, transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount, created_at, version FROM checks WHERE id = ?", check.ID).
+		Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt, &check.Version)
 
 	return check, err
 }
@@ -73,26 +76,26 @@ func (r *checkRepo) Update(check Check) (Check, error) {
 		return check, err
 	}
 
-	err = r.db.QueryRow("SELECT id, transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount, created_at FROM checks WHERE id = ?", check.ID).
-		Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt)
+	err = r.db.QueryRow("SELECT id, transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount, created_at, version FROM checks WHERE id = ?", check.ID).
+		Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt, &check.Version)
 
 	return check, err
 }
 
 func (r *checkRepo) GetByID(id int) (Check, error) {
 	var check Check
-	err := r.db.QueryRow("SELECT id, transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount, created_at FROM checks WHERE id = ?", id).
-		Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt)
+	err := r.db.QueryRow("SELECT id, transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount, created_at, version FROM checks WHERE id = ?", id).
+		Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt, &check.Version)
 
 	if err == sql.ErrNoRows {
-		return check, fmt.Errorf("check not found")
+		return check, apperrors.NotFound("check")
 	}
 
 	return check, err
 }
 
 func (r *checkRepo) GetByBuildingID(buildingID int) ([]Check, error) {
-	rows, err := r.db.Query("SELECT id, transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount, created_at FROM checks WHERE building_id = ? ORDER BY created_at DESC", buildingID)
+	rows, err := r.db.Query("SELECT id, transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount, created_at, version FROM checks WHERE building_id = ? ORDER BY created_at DESC", buildingID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +104,7 @@ func (r *checkRepo) GetByBuildingID(buildingID int) ([]Check, error) {
 	checks := []Check{}
 	for rows.Next() {
 		var check Check
-		err := rows.Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt)
+		err := rows.Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt, &check.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -110,3 +113,72 @@ func (r *checkRepo) GetByBuildingID(buildingID int) ([]Check, error) {
 
 	return checks, nil
 }
+
+var checkListSpec = pagination.Spec{
+	IDColumn: "c.id",
+	Sorts: map[string]string{
+		"id":         "c.id",
+		"date":       "c.check_date",
+		"number":     "COALESCE(c.reference_number, '')",
+		"amount":     "COALESCE(c.total_amount, 0)",
+		"created_at": "c.created_at",
+	},
+	DefaultSort:  "-created_at",
+	DateColumn:   "c.check_date",
+	AmountColumn: "COALESCE(c.total_amount, 0)",
+	NumberColumn: "COALESCE(c.reference_number, '')",
+	StatusFilter: "EXISTS (SELECT 1 FROM transactions t WHERE t.id = c.transaction_id AND t.status = ?)",
+	PeopleFilter: "EXISTS (SELECT 1 FROM splits s WHERE s.transaction_id = c.transaction_id AND s.status = '1' AND s.people_id = ?)",
+	UnitFilter:   "EXISTS (SELECT 1 FROM splits s WHERE s.transaction_id = c.transaction_id AND s.status = '1' AND s.unit_id = ?)",
+}
+
+func checkSortKey(check Check, sort string) (interface{}, int) {
+	switch sort {
+	case "date":
+		return check.CheckDate, check.ID
+	case "number":
+		if check.ReferenceNumber == nil {
+			return "", check.ID
+		}
+		return *check.ReferenceNumber, check.ID
+	case "amount":
+		return check.TotalAmount, check.ID
+	case "created_at":
+		return check.CreatedAt, check.ID
+	}
+	return check.ID, check.ID
+}
+
+func (r *checkRepo) List(buildingID int, params pagination.Params) ([]Check, int, error) {
+	where, args := checkListSpec.Where(params)
+	where = " WHERE c.building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM checks c"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := checkListSpec.After(params)
+	order, orderArgs := checkListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	rows, err := r.db.Query("SELECT c.id, c.transaction_id, c.check_date, c.reference_number, c.payment_account_id, c.building_id, c.memo, c.total_amount, c.created_at, c.version FROM checks c"+where+after+order, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	checks := []Check{}
+	for rows.Next() {
+		var check Check
+		err := rows.Scan(&check.ID, &check.TransactionID, &check.CheckDate, &check.ReferenceNumber, &check.PaymentAccountID, &check.BuildingID, &check.Memo, &check.TotalAmount, &check.CreatedAt, &check.Version)
+		if err != nil {
+			return nil, 0, err
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, total, rows.Err()
+}
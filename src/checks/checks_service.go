@@ -3,14 +3,20 @@ package checks
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"math"
 	"strings"
 
 	"github.com/mysecodgit/go_accounting/src/account_types"
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/expense_lines"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type CheckService struct {
@@ -21,6 +27,7 @@ type CheckService struct {
 	accountRepo     accounts.AccountRepository
 	accountTypeRepo account_types.AccountTypeRepository
 	db              *sql.DB
+	logger          *slog.Logger
 }
 
 func NewCheckService(
@@ -31,6 +38,7 @@ func NewCheckService(
 	accountRepo accounts.AccountRepository,
 	accountTypeRepo account_types.AccountTypeRepository,
 	db *sql.DB,
+	logger *slog.Logger,
 ) *CheckService {
 	return &CheckService{
 		checkRepo:       checkRepo,
@@ -40,41 +48,49 @@ func NewCheckService(
 		accountRepo:     accountRepo,
 		accountTypeRepo: accountTypeRepo,
 		db:              db,
+		logger:          logger,
 	}
 }
 
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *CheckService) WithLogger(logger *slog.Logger) *CheckService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
 // CalculateSplitsForCheck calculates the double-entry accounting splits for a check
 // For checks: Debit expense accounts, Credit payment account
 func (s *CheckService) CalculateSplitsForCheck(req CreateCheckRequest, userID int) ([]SplitPreview, error) {
 	splits := []SplitPreview{}
 
 	if len(req.ExpenseLines) == 0 {
-		return nil, fmt.Errorf("check must have at least one expense line")
+		return nil, apperrors.Rule("check must have at least one expense line")
 	}
 
 	// Get payment account
 	paymentAccount, _, _, err := s.accountRepo.GetByID(req.PaymentAccountID)
 	if err != nil {
-		return nil, fmt.Errorf("payment account not found: %v", err)
+		return nil, apperrors.Lookup("payment account", err)
 	}
 
 	// Validate expense lines and check for A/R or A/P accounts requiring people_id
 	for _, expenseLine := range req.ExpenseLines {
 		account, _, _, err := s.accountRepo.GetByID(expenseLine.AccountID)
 		if err != nil {
-			return nil, fmt.Errorf("expense account %d not found: %v", expenseLine.AccountID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("expense account %d", expenseLine.AccountID), err)
 		}
 
 		// Get account type
 		accountType, err := s.accountTypeRepo.GetByID(account.AccountType)
 		if err != nil {
-			return nil, fmt.Errorf("account type not found: %v", err)
+			return nil, apperrors.Lookup("account type", err)
 		}
 
 		// Validate: if account type is "Account Receivable" or "Account Payable", people_id must be selected
 		typeLower := strings.ToLower(accountType.Type)
 		if (typeLower == "account receivable" || typeLower == "account payable") && expenseLine.PeopleID == nil {
-			return nil, fmt.Errorf("people_id is required when account type is %s", accountType.TypeName)
+			return nil, apperrors.Rulef("people_id is required when account type is %s", accountType.TypeName)
 		}
 
 		// Debit: Expense account
@@ -115,7 +131,7 @@ func (s *CheckService) CalculateSplitsForCheck(req CreateCheckRequest, userID in
 	}
 
 	if len(splits) < 2 {
-		return nil, fmt.Errorf("check must have at least 2 splits for double-entry accounting, got %d", len(splits))
+		return nil, apperrors.Rulef("check must have at least 2 splits for double-entry accounting, got %d", len(splits))
 	}
 
 	totalDebitCent := int64(math.Round(totalDebit * 100))
@@ -124,8 +140,8 @@ totalCreditCent  := int64(math.Round(totalCredit * 100))
 	// use this library later import "github.com/shopspring/decimal"
 
 	if totalDebitCent != totalCreditCent {
-		fmt.Println("splits are not balanced: total debit :", totalDebitCent, "!= total credit :" , totalCreditCent)
-		return nil, fmt.Errorf("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
+		s.logger.Debug("check splits are not balanced", "debit_cents", totalDebitCent, "credit_cents", totalCreditCent)
+		return nil, apperrors.Rulef("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
 	}
 
 	return splits, nil
@@ -134,11 +150,11 @@ totalCreditCent  := int64(math.Round(totalCredit * 100))
 // PreviewCheck calculates and returns the splits that will be created
 func (s *CheckService) PreviewCheck(req CreateCheckRequest, userID int) (*CheckPreviewResponse, error) {
 	if req.TotalAmount <= 0 {
-		return nil, fmt.Errorf("total amount must be greater than 0")
+		return nil, apperrors.Rule("total amount must be greater than 0")
 	}
 
 	if len(req.ExpenseLines) == 0 {
-		return nil, fmt.Errorf("check must have at least one expense line")
+		return nil, apperrors.Rule("check must have at least one expense line")
 	}
 
 	// Calculate splits
@@ -176,7 +192,7 @@ func (s *CheckService) CreateCheck(req CreateCheckRequest, userID int) (*CheckRe
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -202,12 +218,12 @@ func (s *CheckService) CreateCheck(req CreateCheckRequest, userID int) (*CheckRe
 	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		"check", req.CheckDate, transactionNumber, memo, transactionStatus, req.BuildingID, userID, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %v", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	transactionID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction ID: %v", err)
+		return nil, fmt.Errorf("failed to get transaction ID: %w", err)
 	}
 
 	// Create check
@@ -228,12 +244,12 @@ func (s *CheckService) CreateCheck(req CreateCheckRequest, userID int) (*CheckRe
 	result, err = tx.Exec("INSERT INTO checks (transaction_id, check_date, reference_number, payment_account_id, building_id, memo, total_amount) VALUES (?, ?, ?, ?, ?, ?, ?)",
 		transactionID, req.CheckDate, referenceNumber, req.PaymentAccountID, req.BuildingID, memoInterface, req.TotalAmount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create check: %v", err)
+		return nil, fmt.Errorf("failed to create check: %w", err)
 	}
 
 	checkID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get check ID: %v", err)
+		return nil, fmt.Errorf("failed to get check ID: %w", err)
 	}
 
 	// Create expense lines
@@ -262,14 +278,14 @@ func (s *CheckService) CreateCheck(req CreateCheckRequest, userID int) (*CheckRe
 		_, err = tx.Exec("INSERT INTO expense_lines (check_id, account_id, unit_id, people_id, description, amount) VALUES (?, ?, ?, ?, ?, ?)",
 			checkID, expenseLineInput.AccountID, unitID, peopleID, description, expenseLineInput.Amount)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create expense line: %v", err)
+			return nil, fmt.Errorf("failed to create expense line: %w", err)
 		}
 	}
 
 	// Calculate and create splits
 	splitPreviews, err := s.CalculateSplitsForCheck(req, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Create splits within transaction
@@ -306,35 +322,50 @@ func (s *CheckService) CreateCheck(req CreateCheckRequest, userID int) (*CheckRe
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
+	// Record the event in the same transaction so it exists exactly when the check does
+	err = outbox.Write(tx, req.BuildingID, outbox.CheckCreated, int(checkID), outbox.Document{
+		ID:            int(checkID),
+		TransactionID: int(transactionID),
+		Number:        transactionNumber,
+		Date:          req.CheckDate,
+		Amount:        req.TotalAmount,
+		Version:       1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("check", metrics.PostingCreated)
+	s.logger.Info("check created", "check_id", checkID, "transaction_id", transactionID)
 
 	// Fetch created records after successful commit
 	createdTransaction, err := s.transactionRepo.GetByID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	createdCheck, err := s.checkRepo.GetByID(int(checkID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch check: %v", err)
+		return nil, fmt.Errorf("failed to fetch check: %w", err)
 	}
 
 	createdExpenseLines, err := s.expenseLineRepo.GetByCheckID(int(checkID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch expense lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch expense lines: %w", err)
 	}
 
 	createdSplits, err := s.splitRepo.GetByTransactionID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	// Filter to only active splits
@@ -359,13 +390,13 @@ func (s *CheckService) UpdateCheck(req UpdateCheckRequest, userID int) (*CheckRe
 	// Get existing check
 	existingCheck, err := s.checkRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("check not found: %v", err)
+		return nil, apperrors.Lookup("check", err)
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -376,6 +407,11 @@ func (s *CheckService) UpdateCheck(req UpdateCheckRequest, userID int) (*CheckRe
 		}
 	}()
 
+	// Claim the next version first so a concurrent update fails instead of overwriting
+	if err := versioning.Bump(tx, "checks", req.ID, req.ExpectedVersion, "check"); err != nil {
+		return nil, err
+	}
+
 	// Update transaction record
 	memo := ""
 	if req.Memo != nil {
@@ -390,7 +426,7 @@ func (s *CheckService) UpdateCheck(req UpdateCheckRequest, userID int) (*CheckRe
 	_, err = tx.Exec("UPDATE transactions SET transaction_date = ?, transaction_number = ?, memo = ? WHERE id = ?",
 		req.CheckDate, transactionNumber, memo, existingCheck.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update transaction: %v", err)
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
 	}
 
 	// Update check
@@ -411,20 +447,20 @@ func (s *CheckService) UpdateCheck(req UpdateCheckRequest, userID int) (*CheckRe
 	_, err = tx.Exec("UPDATE checks SET check_date = ?, reference_number = ?, payment_account_id = ?, memo = ?, total_amount = ? WHERE id = ?",
 		req.CheckDate, referenceNumber, req.PaymentAccountID, memoInterface, req.TotalAmount, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update check: %v", err)
+		return nil, fmt.Errorf("failed to update check: %w", err)
 	}
 
 	// Soft delete existing expense_lines (set status='0' if there's a status column, otherwise delete)
 	// Since expense_lines doesn't have a status column, we'll delete and recreate
 	_, err = tx.Exec("DELETE FROM expense_lines WHERE check_id = ?", req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to delete expense lines: %v", err)
+		return nil, fmt.Errorf("failed to delete expense lines: %w", err)
 	}
 
 	// Soft delete existing splits (set status='0')
 	_, err = tx.Exec("UPDATE splits SET status = '0' WHERE transaction_id = ?", existingCheck.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to soft delete splits: %v", err)
+		return nil, fmt.Errorf("failed to soft delete splits: %w", err)
 	}
 
 	// Recreate expense lines
@@ -453,7 +489,7 @@ func (s *CheckService) UpdateCheck(req UpdateCheckRequest, userID int) (*CheckRe
 		_, err = tx.Exec("INSERT INTO expense_lines (check_id, account_id, unit_id, people_id, description, amount) VALUES (?, ?, ?, ?, ?, ?)",
 			req.ID, expenseLineInput.AccountID, unitID, peopleID, description, expenseLineInput.Amount)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create expense line: %v", err)
+			return nil, fmt.Errorf("failed to create expense line: %w", err)
 		}
 	}
 
@@ -469,7 +505,7 @@ func (s *CheckService) UpdateCheck(req UpdateCheckRequest, userID int) (*CheckRe
 	}
 	splitPreviews, err := s.CalculateSplitsForCheck(createReq, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Recreate splits
@@ -506,35 +542,49 @@ func (s *CheckService) UpdateCheck(req UpdateCheckRequest, userID int) (*CheckRe
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingCheck.TransactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
+	err = outbox.Write(tx, existingCheck.BuildingID, outbox.CheckUpdated, existingCheck.ID, outbox.Document{
+		ID:            existingCheck.ID,
+		TransactionID: existingCheck.TransactionID,
+		Number:        transactionNumber,
+		Date:          req.CheckDate,
+		Amount:        req.TotalAmount,
+		Version:       req.ExpectedVersion + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("check", metrics.PostingUpdated)
+	s.logger.Info("check updated", "check_id", existingCheck.ID, "transaction_id", existingCheck.TransactionID)
 
 	// Fetch updated records, filtering for active status
 	updatedTransaction, err := s.transactionRepo.GetByID(existingCheck.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	updatedCheck, err := s.checkRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch check: %v", err)
+		return nil, fmt.Errorf("failed to fetch check: %w", err)
 	}
 
 	updatedExpenseLines, err := s.expenseLineRepo.GetByCheckID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch expense lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch expense lines: %w", err)
 	}
 
 	updatedSplits, err := s.splitRepo.GetByTransactionID(existingCheck.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	// Filter to only active splits
@@ -562,17 +612,17 @@ func (s *CheckService) GetCheckDetails(id int) (*CheckResponse, error) {
 
 	expenseLines, err := s.expenseLineRepo.GetByCheckID(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch expense lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch expense lines: %w", err)
 	}
 
 	splitsList, err := s.splitRepo.GetByTransactionID(check.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	transaction, err := s.transactionRepo.GetByID(check.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	return &CheckResponse{
@@ -584,6 +634,10 @@ func (s *CheckService) GetCheckDetails(id int) (*CheckResponse, error) {
 }
 
 // GetChecks returns all checks for a building
-func (s *CheckService) GetChecks(buildingID int) ([]Check, error) {
-	return s.checkRepo.GetByBuildingID(buildingID)
+func (s *CheckService) GetChecks(buildingID int, params pagination.Params) (pagination.Page[Check], error) {
+	checks, total, err := s.checkRepo.List(buildingID, params)
+	if err != nil {
+		return pagination.Page[Check]{}, fmt.Errorf("failed to list checks: %w", err)
+	}
+	return pagination.NewPage(checks, total, params, checkSortKey), nil
 }
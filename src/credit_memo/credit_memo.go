@@ -18,6 +18,7 @@ type CreditMemo struct {
 	Amount           float64 `json:"amount"`
 	Description      string  `json:"description"`
 	Status           string  `json:"status"`
+	Version          int     `json:"version"`
 	CreatedAt        string  `json:"created_at"`
 	UpdatedAt        string  `json:"updated_at"`
 }
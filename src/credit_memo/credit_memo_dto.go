@@ -46,6 +46,9 @@ type UpdateCreditMemoRequest struct {
 	UnitID           int     `json:"unit_id"`
 	Amount           float64 `json:"amount"`
 	Description      string  `json:"description"`
+
+	// Version from the If-Match header; the update fails if the document has moved on
+	ExpectedVersion int `json:"-"`
 }
 
 type CreditMemoResponse struct {
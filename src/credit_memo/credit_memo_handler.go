@@ -5,6 +5,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type CreditMemoHandler struct {
@@ -19,7 +22,7 @@ func NewCreditMemoHandler(service *CreditMemoService) *CreditMemoHandler {
 func (h *CreditMemoHandler) PreviewCreditMemo(c *gin.Context) {
 	var req CreateCreditMemoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -38,19 +41,19 @@ func (h *CreditMemoHandler) PreviewCreditMemo(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
 	preview, err := h.service.PreviewCreditMemo(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -61,7 +64,7 @@ func (h *CreditMemoHandler) PreviewCreditMemo(c *gin.Context) {
 func (h *CreditMemoHandler) CreateCreditMemo(c *gin.Context) {
 	var req CreateCreditMemoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -80,19 +83,19 @@ func (h *CreditMemoHandler) CreateCreditMemo(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.CreateCreditMemo(req, userID)
+	response, err := h.service.WithLogger(logging.FromGin(c)).CreateCreditMemo(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -101,9 +104,14 @@ func (h *CreditMemoHandler) CreateCreditMemo(c *gin.Context) {
 
 // PUT /credit-memos/:id or /buildings/:id/credit-memos/:creditMemoId
 func (h *CreditMemoHandler) UpdateCreditMemo(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateCreditMemoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -113,13 +121,13 @@ func (h *CreditMemoHandler) UpdateCreditMemo(c *gin.Context) {
 		creditMemoIDStr = c.Param("id")
 	}
 	if creditMemoIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Credit memo ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Credit memo ID is required"))
 		return
 	}
 
 	creditMemoID, err := strconv.Atoi(creditMemoIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credit memo ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid credit memo ID"))
 		return
 	}
 	req.ID = creditMemoID
@@ -139,22 +147,25 @@ func (h *CreditMemoHandler) UpdateCreditMemo(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.UpdateCreditMemo(req, userID)
+	req.ExpectedVersion = version
+	response, err := h.service.WithLogger(logging.FromGin(c)).UpdateCreditMemo(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.CreditMemo.Version)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -166,22 +177,23 @@ func (h *CreditMemoHandler) GetCreditMemoByID(c *gin.Context) {
 		creditMemoIDStr = c.Param("id")
 	}
 	if creditMemoIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Credit memo ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Credit memo ID is required"))
 		return
 	}
 
 	creditMemoID, err := strconv.Atoi(creditMemoIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credit memo ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid credit memo ID"))
 		return
 	}
 
 	response, err := h.service.GetCreditMemoByID(creditMemoID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.CreditMemo.Version)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -193,19 +205,25 @@ func (h *CreditMemoHandler) GetCreditMemosByBuildingID(c *gin.Context) {
 		buildingIDStr = c.Query("building_id")
 	}
 	if buildingIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
+		return
+	}
+
+	params, validationErrors := creditMemoListSpec.Parse(c.Request.URL.Query())
+	if validationErrors != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
 		return
 	}
 
-	creditMemos, err := h.service.GetCreditMemosByBuildingID(buildingID)
+	creditMemos, err := h.service.GetCreditMemosByBuildingID(buildingID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -0,0 +1,18 @@
+package credit_memo
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"CreditMemoHandler.PreviewCreditMemo": {
+		Summary:     "Preview a credit memo's postings",
+		Description: "Validates the credit memo and returns the splits it would post without saving anything.",
+		UserID:      true, Request: CreateCreditMemoRequest{}, Response: CreditMemoPreviewResponse{},
+	},
+	"CreditMemoHandler.CreateCreditMemo":           {Summary: "Create and post a credit memo", UserID: true, Idempotent: true, Request: CreateCreditMemoRequest{}, Response: CreditMemoResponse{}},
+	"CreditMemoHandler.GetCreditMemosByBuildingID": {Summary: "List credit memos", Response: pagination.Page[CreditMemoListItem]{}, List: &creditMemoListSpec},
+	"CreditMemoHandler.GetCreditMemoByID":          {Summary: "Get a credit memo with its postings", Response: CreditMemoResponse{}},
+	"CreditMemoHandler.UpdateCreditMemo":           {Summary: "Update a credit memo and repost it", UserID: true, IfMatch: true, Request: UpdateCreditMemoRequest{}, Response: CreditMemoResponse{}},
+}
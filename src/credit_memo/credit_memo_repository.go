@@ -2,7 +2,9 @@ package credit_memo
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 )
 
 type CreditMemoRepository interface {
@@ -10,6 +12,7 @@ type CreditMemoRepository interface {
 	Update(creditMemo CreditMemo) (CreditMemo, error)
 	GetByID(id int) (CreditMemo, error)
 	GetByBuildingID(buildingID int) ([]CreditMemo, error)
+	List(buildingID int, params pagination.Params) ([]CreditMemo, int, error)
 }
 
 type creditMemoRepo struct {
@@ -31,8 +34,8 @@ func (r *creditMemoRepo) Create(creditMemo CreditMemo) (CreditMemo, error) {
 	id, _ := result.LastInsertId()
 	creditMemo.ID = int(id)
 
-	err = r.db.QueryRow("SELECT id, transaction_id, reference, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status, created_at, updated_at FROM credit_memo WHERE id = ?", creditMemo.ID).
-		Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Reference, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, transaction_id, reference, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status, created_at, updated_at, version FROM credit_memo WHERE id = ?", creditMemo.ID).
+		Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Reference, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt, &creditMemo.Version)
 
 	return creditMemo, err
 }
@@ -45,26 +48,26 @@ func (r *creditMemoRepo) Update(creditMemo CreditMemo) (CreditMemo, error) {
 		return creditMemo, err
 	}
 
-	err = r.db.QueryRow("SELECT id, transaction_id, reference, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status, created_at, updated_at FROM credit_memo WHERE id = ?", creditMemo.ID).
-		Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Reference, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, transaction_id, reference, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status, created_at, updated_at, version FROM credit_memo WHERE id = ?", creditMemo.ID).
+		Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Reference, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt, &creditMemo.Version)
 
 	return creditMemo, err
 }
 
 func (r *creditMemoRepo) GetByID(id int) (CreditMemo, error) {
 	var creditMemo CreditMemo
-	err := r.db.QueryRow("SELECT id, transaction_id, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status, created_at, updated_at FROM credit_memo WHERE id = ?", id).
-		Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt)
+	err := r.db.QueryRow("SELECT id, transaction_id, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status, created_at, updated_at, version FROM credit_memo WHERE id = ?", id).
+		Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt, &creditMemo.Version)
 
 	if err == sql.ErrNoRows {
-		return creditMemo, fmt.Errorf("credit memo not found")
+		return creditMemo, apperrors.NotFound("credit memo")
 	}
 
 	return creditMemo, err
 }
 
 func (r *creditMemoRepo) GetByBuildingID(buildingID int) ([]CreditMemo, error) {
-	rows, err := r.db.Query("SELECT id, transaction_id, reference, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status, created_at, updated_at FROM credit_memo WHERE building_id = ? ORDER BY created_at DESC", buildingID)
+	rows, err := r.db.Query("SELECT id, transaction_id, reference, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status, created_at, updated_at, version FROM credit_memo WHERE building_id = ? ORDER BY created_at DESC", buildingID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +76,7 @@ func (r *creditMemoRepo) GetByBuildingID(buildingID int) ([]CreditMemo, error) {
 	creditMemos := []CreditMemo{}
 	for rows.Next() {
 		var creditMemo CreditMemo
-		err := rows.Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Reference, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt)
+		err := rows.Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Reference, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt, &creditMemo.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -83,3 +86,70 @@ func (r *creditMemoRepo) GetByBuildingID(buildingID int) ([]CreditMemo, error) {
 	return creditMemos, nil
 }
 
+var creditMemoListSpec = pagination.Spec{
+	IDColumn: "cm.id",
+	Sorts: map[string]string{
+		"id":         "cm.id",
+		"date":       "cm.date",
+		"number":     "cm.reference",
+		"amount":     "cm.amount",
+		"created_at": "cm.created_at",
+	},
+	DefaultSort:  "-created_at",
+	DateColumn:   "cm.date",
+	AmountColumn: "cm.amount",
+	NumberColumn: "cm.reference",
+	StatusFilter: "cm.status = ?",
+	PeopleFilter: "cm.people_id = ?",
+	UnitFilter:   "cm.unit_id = ?",
+}
+
+func creditMemoSortKey(item CreditMemoListItem, sort string) (interface{}, int) {
+	creditMemo := item.CreditMemo
+	switch sort {
+	case "date":
+		return creditMemo.Date, creditMemo.ID
+	case "number":
+		return creditMemo.Reference, creditMemo.ID
+	case "amount":
+		return creditMemo.Amount, creditMemo.ID
+	case "created_at":
+		return creditMemo.CreatedAt, creditMemo.ID
+	}
+	return creditMemo.ID, creditMemo.ID
+}
+
+func (r *creditMemoRepo) List(buildingID int, params pagination.Params) ([]CreditMemo, int, error) {
+	where, args := creditMemoListSpec.Where(params)
+	where = " WHERE cm.building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM credit_memo cm"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := creditMemoListSpec.After(params)
+	order, orderArgs := creditMemoListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	rows, err := r.db.Query("SELECT cm.id, cm.transaction_id, cm.reference, cm.date, cm.user_id, cm.deposit_to, cm.liability_account, cm.people_id, cm.building_id, cm.unit_id, cm.amount, cm.description, cm.status, cm.created_at, cm.updated_at, cm.version FROM credit_memo cm"+where+after+order, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	creditMemos := []CreditMemo{}
+	for rows.Next() {
+		var creditMemo CreditMemo
+		err := rows.Scan(&creditMemo.ID, &creditMemo.TransactionID, &creditMemo.Reference, &creditMemo.Date, &creditMemo.UserID, &creditMemo.DepositTo, &creditMemo.LiabilityAccount, &creditMemo.PeopleID, &creditMemo.BuildingID, &creditMemo.UnitID, &creditMemo.Amount, &creditMemo.Description, &creditMemo.Status, &creditMemo.CreatedAt, &creditMemo.UpdatedAt, &creditMemo.Version)
+		if err != nil {
+			return nil, 0, err
+		}
+		creditMemos = append(creditMemos, creditMemo)
+	}
+
+	return creditMemos, total, rows.Err()
+}
+
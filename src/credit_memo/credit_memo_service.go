@@ -3,13 +3,18 @@ package credit_memo
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/mysecodgit/go_accounting/src/account_types"
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 	"github.com/mysecodgit/go_accounting/src/people"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type CreditMemoService struct {
@@ -20,6 +25,7 @@ type CreditMemoService struct {
 	accountTypeRepo account_types.AccountTypeRepository
 	peopleRepo      people.PersonRepository
 	db              *sql.DB
+	logger          *slog.Logger
 }
 
 func NewCreditMemoService(
@@ -30,6 +36,7 @@ func NewCreditMemoService(
 	accountTypeRepo account_types.AccountTypeRepository,
 	peopleRepo people.PersonRepository,
 	db *sql.DB,
+	logger *slog.Logger,
 ) *CreditMemoService {
 	return &CreditMemoService{
 		creditMemoRepo:  creditMemoRepo,
@@ -39,40 +46,48 @@ func NewCreditMemoService(
 		accountTypeRepo: accountTypeRepo,
 		peopleRepo:      peopleRepo,
 		db:              db,
+		logger:          logger,
 	}
 }
 
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *CreditMemoService) WithLogger(logger *slog.Logger) *CreditMemoService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
 // CalculateSplitsForCreditMemo calculates the double-entry accounting splits for a credit memo
 // For credit memos: Debit deposit_to account (asset increases), Credit liability account (liability decreases)
 func (s *CreditMemoService) CalculateSplitsForCreditMemo(req CreateCreditMemoRequest, userID int) ([]SplitPreview, error) {
 	splits := []SplitPreview{}
 
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	// Get liability account
 	liabilityAccount, _, _, err := s.accountRepo.GetByID(req.LiabilityAccount)
 	if err != nil {
-		return nil, fmt.Errorf("liability account not found: %v", err)
+		return nil, apperrors.Lookup("liability account", err)
 	}
 
 	// Get account type for liability account
 	liabilityAccountType, err := s.accountTypeRepo.GetByID(liabilityAccount.AccountType)
 	if err != nil {
-		return nil, fmt.Errorf("liability account type not found: %v", err)
+		return nil, apperrors.Lookup("liability account type", err)
 	}
 
 	// Validate: if liability account type is "Account Receivable" or "Account Payable", people_id must be selected
 	typeLower := strings.ToLower(liabilityAccountType.Type)
 	if (typeLower == "account receivable" || typeLower == "account payable") && req.PeopleID <= 0 {
-		return nil, fmt.Errorf("people_id is required when liability account type is %s", liabilityAccountType.TypeName)
+		return nil, apperrors.Rulef("people_id is required when liability account type is %s", liabilityAccountType.TypeName)
 	}
 
 	// Get deposit_to account
 	depositAccount, _, _, err := s.accountRepo.GetByID(req.DepositTo)
 	if err != nil {
-		return nil, fmt.Errorf("deposit to account not found: %v", err)
+		return nil, apperrors.Lookup("deposit to account", err)
 	}
 
 	// Debit: Deposit to account (asset increases)
@@ -114,11 +129,11 @@ func (s *CreditMemoService) CalculateSplitsForCreditMemo(req CreateCreditMemoReq
 	}
 
 	if len(splits) < 2 {
-		return nil, fmt.Errorf("credit memo must have at least 2 splits for double-entry accounting, got %d", len(splits))
+		return nil, apperrors.Rulef("credit memo must have at least 2 splits for double-entry accounting, got %d", len(splits))
 	}
 
 	if totalDebit != totalCredit {
-		return nil, fmt.Errorf("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
+		return nil, apperrors.Rulef("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
 	}
 
 	return splits, nil
@@ -127,7 +142,7 @@ func (s *CreditMemoService) CalculateSplitsForCreditMemo(req CreateCreditMemoReq
 // PreviewCreditMemo calculates and returns the splits that will be created
 func (s *CreditMemoService) PreviewCreditMemo(req CreateCreditMemoRequest, userID int) (*CreditMemoPreviewResponse, error) {
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	// Calculate splits
@@ -165,7 +180,7 @@ func (s *CreditMemoService) CreateCreditMemo(req CreateCreditMemoRequest, userID
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -181,12 +196,12 @@ func (s *CreditMemoService) CreateCreditMemo(req CreateCreditMemoRequest, userID
 	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		"credit memo", req.Date, req.Reference, req.Description, transactionStatus, req.BuildingID, userID, req.UnitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %v", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	transactionID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction ID: %v", err)
+		return nil, fmt.Errorf("failed to get transaction ID: %w", err)
 	}
 
 	// Create credit memo - always use status "1" (active) when creating
@@ -194,18 +209,18 @@ func (s *CreditMemoService) CreateCreditMemo(req CreateCreditMemoRequest, userID
 	result, err = tx.Exec("INSERT INTO credit_memo (transaction_id, reference, date, user_id, deposit_to, liability_account, people_id, building_id, unit_id, amount, description, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		transactionID, req.Reference, req.Date, userID, req.DepositTo, req.LiabilityAccount, req.PeopleID, req.BuildingID, req.UnitID, req.Amount, req.Description, creditMemoStatus)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create credit memo: %v", err)
+		return nil, fmt.Errorf("failed to create credit memo: %w", err)
 	}
 
 	creditMemoID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get credit memo ID: %v", err)
+		return nil, fmt.Errorf("failed to get credit memo ID: %w", err)
 	}
 
 	// Calculate and create splits
 	splitPreviews, err := s.CalculateSplitsForCreditMemo(req, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Create splits within transaction
@@ -242,30 +257,32 @@ func (s *CreditMemoService) CreateCreditMemo(req CreateCreditMemoRequest, userID
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("credit memo", metrics.PostingCreated)
+	s.logger.Info("credit memo created", "credit_memo_id", creditMemoID, "transaction_id", transactionID)
 
 	// Fetch created records after successful commit
 	createdTransaction, err := s.transactionRepo.GetByID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	createdCreditMemo, err := s.creditMemoRepo.GetByID(int(creditMemoID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch credit memo: %v", err)
+		return nil, fmt.Errorf("failed to fetch credit memo: %w", err)
 	}
 
 	createdSplits, err := s.splitRepo.GetByTransactionID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	// Filter to only active splits
@@ -289,13 +306,13 @@ func (s *CreditMemoService) UpdateCreditMemo(req UpdateCreditMemoRequest, userID
 	// Get existing credit memo
 	existingCreditMemo, err := s.creditMemoRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("credit memo not found: %v", err)
+		return nil, apperrors.Lookup("credit memo", err)
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -306,24 +323,29 @@ func (s *CreditMemoService) UpdateCreditMemo(req UpdateCreditMemoRequest, userID
 		}
 	}()
 
+	// Claim the next version first so a concurrent update fails instead of overwriting
+	if err := versioning.Bump(tx, "credit_memo", req.ID, req.ExpectedVersion, "credit memo"); err != nil {
+		return nil, err
+	}
+
 	// Update transaction
 	_, err = tx.Exec("UPDATE transactions SET transaction_date = ?, transaction_number = ?, memo = ?, unit_id = ? WHERE id = ?",
 		req.Date, req.Reference, req.Description, req.UnitID, existingCreditMemo.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update transaction: %v", err)
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
 	}
 
 	// Update credit memo
 	_, err = tx.Exec("UPDATE credit_memo SET reference = ?, date = ?, deposit_to = ?, liability_account = ?, people_id = ?, unit_id = ?, amount = ?, description = ? WHERE id = ?",
 		req.Reference, req.Date, req.DepositTo, req.LiabilityAccount, req.PeopleID, req.UnitID, req.Amount, req.Description, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update credit memo: %v", err)
+		return nil, fmt.Errorf("failed to update credit memo: %w", err)
 	}
 
 	// Soft delete existing splits (set status to '0')
 	_, err = tx.Exec("UPDATE splits SET status = '0' WHERE transaction_id = ?", existingCreditMemo.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to soft delete splits: %v", err)
+		return nil, fmt.Errorf("failed to soft delete splits: %w", err)
 	}
 
 	// Calculate and create new splits
@@ -339,7 +361,7 @@ func (s *CreditMemoService) UpdateCreditMemo(req UpdateCreditMemoRequest, userID
 	}
 	splitPreviews, err := s.CalculateSplitsForCreditMemo(createReq, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Create new splits within transaction
@@ -376,30 +398,32 @@ func (s *CreditMemoService) UpdateCreditMemo(req UpdateCreditMemoRequest, userID
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingCreditMemo.TransactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("credit memo", metrics.PostingUpdated)
+	s.logger.Info("credit memo updated", "credit_memo_id", existingCreditMemo.ID, "transaction_id", existingCreditMemo.TransactionID)
 
 	// Fetch updated records after successful commit
 	updatedTransaction, err := s.transactionRepo.GetByID(existingCreditMemo.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	updatedCreditMemo, err := s.creditMemoRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch credit memo: %v", err)
+		return nil, fmt.Errorf("failed to fetch credit memo: %w", err)
 	}
 
 	updatedSplits, err := s.splitRepo.GetByTransactionID(existingCreditMemo.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	// Filter to only active splits
@@ -421,17 +445,17 @@ func (s *CreditMemoService) UpdateCreditMemo(req UpdateCreditMemoRequest, userID
 func (s *CreditMemoService) GetCreditMemoByID(id int) (*CreditMemoResponse, error) {
 	creditMemo, err := s.creditMemoRepo.GetByID(id)
 	if err != nil {
-		return nil, fmt.Errorf("credit memo not found: %v", err)
+		return nil, apperrors.Lookup("credit memo", err)
 	}
 
 	transaction, err := s.transactionRepo.GetByID(creditMemo.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	allSplits, err := s.splitRepo.GetByTransactionID(creditMemo.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	return &CreditMemoResponse{
@@ -441,11 +465,11 @@ func (s *CreditMemoService) GetCreditMemoByID(id int) (*CreditMemoResponse, erro
 	}, nil
 }
 
-// GetCreditMemosByBuildingID retrieves all credit memos for a building with used credits and balance
-func (s *CreditMemoService) GetCreditMemosByBuildingID(buildingID int) ([]CreditMemoListItem, error) {
-	creditMemos, err := s.creditMemoRepo.GetByBuildingID(buildingID)
+// GetCreditMemosByBuildingID retrieves a page of a building's credit memos with used credits and balance
+func (s *CreditMemoService) GetCreditMemosByBuildingID(buildingID int, params pagination.Params) (pagination.Page[CreditMemoListItem], error) {
+	creditMemos, total, err := s.creditMemoRepo.List(buildingID, params)
 	if err != nil {
-		return nil, err
+		return pagination.Page[CreditMemoListItem]{}, fmt.Errorf("failed to list credit memos: %w", err)
 	}
 
 	result := make([]CreditMemoListItem, 0, len(creditMemos))
@@ -478,5 +502,5 @@ func (s *CreditMemoService) GetCreditMemosByBuildingID(buildingID int) ([]Credit
 		})
 	}
 
-	return result, nil
+	return pagination.NewPage(result, total, params, creditMemoSortKey), nil
 }
@@ -0,0 +1,99 @@
+// Package customer_payments records one payment from a customer that covers several invoices,
+// e.g. a tenant paying three months of rent in one transfer.
+//
+// A customer payment posts one transaction: one split debits the deposit account with the whole
+// amount, and one split per invoice credits that invoice's receivable account. The amount is
+// allocated to the customer's open invoices as listed in the request, or oldest due first.
+// Every allocation is an invoice payment of its invoice, so invoice balances, statements and
+// aging count it like any other payment.
+//
+// Whatever is not allocated is credited to the payment's receivable account as unapplied
+// credit, which lowers the customer's balance. It is applied to invoices later in a
+// transaction of its own, which moves it from the payment's receivable to the invoice's.
+//
+// A prepayment is money received before the customer is invoiced. It is not allocated when it
+// is received but held on a customer deposits liability account, and is applied to invoices
+// like any unapplied credit, by hand or to the next invoice of the customer and unit.
+package customer_payments
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Transaction types
+const (
+	TransactionPayment     = "customer payment"
+	TransactionApplication = "customer payment application" // Applies unapplied credit later
+
+	TransactionPrepayment            = "customer prepayment"
+	TransactionPrepaymentApplication = "customer prepayment application"
+)
+
+type CustomerPayment struct {
+	ID                 int     `json:"id"`
+	TransactionID      int     `json:"transaction_id"`
+	Reference          string  `json:"reference"`
+	Date               string  `json:"date"`
+	PeopleID           int     `json:"people_id"`
+	PeopleName         string  `json:"people_name"`
+	UnitID             *int    `json:"unit_id"`
+	BuildingID         int     `json:"building_id"`
+	AccountID          int     `json:"account_id"`           // Asset account the money was deposited to
+	ARAccountID        *int    `json:"ar_account_id"`        // Receivable account holding the unapplied credit; nil for a prepayment
+	LiabilityAccountID *int    `json:"liability_account_id"` // Customer deposits account holding a prepayment
+	Amount             float64 `json:"amount"`
+	AppliedAmount      float64 `json:"applied_amount"`   // Allocated to invoices, now or later
+	UnappliedAmount    float64 `json:"unapplied_amount"` // Credit left to apply; 0 once voided
+	Memo               string  `json:"memo"`
+	UserID             int     `json:"user_id"`
+	Status             string  `json:"status"`
+	Version            int     `json:"version"`
+	CreatedAt          string  `json:"created_at"`
+	UpdatedAt          string  `json:"updated_at"`
+}
+
+// IsPrepayment reports whether the payment is held on a liability account until it is applied
+func (p CustomerPayment) IsPrepayment() bool {
+	return p.LiabilityAccountID != nil
+}
+
+// creditAccountID is the account holding the payment's unapplied credit
+func (p CustomerPayment) creditAccountID() int {
+	if p.LiabilityAccountID != nil {
+		return *p.LiabilityAccountID
+	}
+	return *p.ARAccountID
+}
+
+// transactionTypes are the types of the transaction that records the payment and of the ones
+// that apply its credit later
+func (p CustomerPayment) transactionTypes() (string, string) {
+	if p.IsPrepayment() {
+		return TransactionPrepayment, TransactionPrepaymentApplication
+	}
+	return TransactionPayment, TransactionApplication
+}
+
+// Allocation is the part of a customer payment paid to one invoice, recorded as an invoice
+// payment
+type Allocation struct {
+	ID            int     `json:"id"`             // The invoice payment
+	TransactionID int     `json:"transaction_id"` // The payment's transaction, or the one that applied the credit later
+	InvoiceID     int     `json:"invoice_id"`
+	InvoiceNo     string  `json:"invoice_no"`
+	UnitID        *int    `json:"unit_id"` // The invoice's unit
+	Date          string  `json:"date"`
+	Amount        float64 `json:"amount"`
+	Status        int     `json:"status"`
+	Version       int     `json:"version"`
+}
+
+// OpenInvoice is an invoice of the customer with a balance left to pay
+type OpenInvoice struct {
+	ID          int     `json:"id"`
+	InvoiceNo   string  `json:"invoice_no"`
+	SalesDate   string  `json:"sales_date"`
+	DueDate     string  `json:"due_date"`
+	UnitID      *int    `json:"unit_id"`
+	ARAccountID int     `json:"ar_account_id"`
+	Amount      float64 `json:"amount"`
+	Balance     float64 `json:"balance"`
+}
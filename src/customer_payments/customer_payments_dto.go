@@ -0,0 +1,129 @@
+package customer_payments
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/splits"
+	"github.com/mysecodgit/go_accounting/src/transactions"
+)
+
+type AllocationRequest struct {
+	InvoiceID int     `json:"invoice_id" binding:"required"`
+	Amount    float64 `json:"amount" binding:"required"`
+}
+
+type CreateCustomerPaymentRequest struct {
+	Reference   string `json:"reference"`
+	Date        string `json:"date" binding:"required"`
+	PeopleID    int    `json:"people_id" binding:"required"`
+	UnitID      *int   `json:"unit_id"`                       // Pays only the unit's invoices; the unapplied credit is the unit's
+	AccountID   int    `json:"account_id" binding:"required"` // Asset account (cash/bank) the money is deposited to
+	ARAccountID *int   `json:"ar_account_id"`                 // Receivable account that holds the unapplied credit
+	// LiabilityAccountID makes the payment a prepayment held on this customer deposits account;
+	// it replaces ar_account_id and the payment is not allocated until it is applied
+	LiabilityAccountID *int    `json:"liability_account_id"`
+	Amount             float64 `json:"amount" binding:"required"`
+	Memo               string  `json:"memo"`
+	// AutoAllocate pays the open invoices oldest due first; otherwise Allocations lists them.
+	// With neither, the whole amount is unapplied credit.
+	AutoAllocate bool                `json:"auto_allocate"`
+	Allocations  []AllocationRequest `json:"allocations"`
+}
+
+// ApplyCreditRequest applies a payment's unapplied credit to open invoices
+type ApplyCreditRequest struct {
+	Date         string              `json:"date" binding:"required"`
+	AutoAllocate bool                `json:"auto_allocate"`
+	Allocations  []AllocationRequest `json:"allocations"`
+}
+
+type SplitPreview struct {
+	AccountID   int      `json:"account_id"`
+	AccountName string   `json:"account_name"`
+	PeopleID    *int     `json:"people_id"`
+	UnitID      *int     `json:"unit_id"`
+	Debit       *float64 `json:"debit"`
+	Credit      *float64 `json:"credit"`
+	Status      string   `json:"status"`
+}
+
+// AllocationPreview is an invoice a payment would pay
+type AllocationPreview struct {
+	InvoiceID int     `json:"invoice_id"`
+	InvoiceNo string  `json:"invoice_no"`
+	DueDate   string  `json:"due_date"`
+	Balance   float64 `json:"balance"` // Before the payment
+	Amount    float64 `json:"amount"`
+}
+
+type CustomerPaymentPreviewResponse struct {
+	Allocations     []AllocationPreview `json:"allocations"`
+	UnappliedAmount float64             `json:"unapplied_amount"`
+	Splits          []SplitPreview      `json:"splits"`
+	TotalDebit      float64             `json:"total_debit"`
+	TotalCredit     float64             `json:"total_credit"`
+	IsBalanced      bool                `json:"is_balanced"`
+}
+
+type CustomerPaymentResponse struct {
+	Payment     CustomerPayment          `json:"payment"`
+	Allocations []Allocation             `json:"allocations"`
+	Transaction transactions.Transaction `json:"transaction"`
+	Splits      []splits.Split           `json:"splits"` // Of the payment's transaction
+}
+
+func (r CreateCustomerPaymentRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+		errors["date"] = "Date must be in YYYY-MM-DD format"
+	}
+	if r.Amount <= 0 {
+		errors["amount"] = "Amount must be greater than zero"
+	}
+	switch {
+	case r.ARAccountID == nil && r.LiabilityAccountID == nil:
+		errors["ar_account_id"] = "A/R account is required, or a liability account for a prepayment"
+	case r.ARAccountID != nil && r.LiabilityAccountID != nil:
+		errors["liability_account_id"] = "A prepayment has no A/R account"
+	case r.LiabilityAccountID != nil && (r.AutoAllocate || len(r.Allocations) > 0):
+		errors["allocations"] = "A prepayment is not allocated when it is received; apply it to invoices later"
+	}
+	validateAllocations(r.AutoAllocate, r.Allocations, errors)
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func (r ApplyCreditRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+		errors["date"] = "Date must be in YYYY-MM-DD format"
+	}
+	if !r.AutoAllocate && len(r.Allocations) == 0 {
+		errors["allocations"] = "List the invoices to apply the credit to, or set auto_allocate"
+	}
+	validateAllocations(r.AutoAllocate, r.Allocations, errors)
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func validateAllocations(auto bool, allocations []AllocationRequest, errors map[string]string) {
+	if auto && len(allocations) > 0 {
+		errors["allocations"] = "Allocations must be empty when auto_allocate is set"
+		return
+	}
+	seen := make(map[int]bool)
+	for i, allocation := range allocations {
+		if allocation.Amount <= 0 {
+			errors[fmt.Sprintf("allocations[%d].amount", i)] = "Amount must be greater than zero"
+		}
+		if seen[allocation.InvoiceID] {
+			errors[fmt.Sprintf("allocations[%d].invoice_id", i)] = "Invoice is listed more than once"
+		}
+		seen[allocation.InvoiceID] = true
+	}
+}
@@ -0,0 +1,252 @@
+package customer_payments
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/versioning"
+)
+
+type CustomerPaymentHandler struct {
+	service *CustomerPaymentService
+}
+
+func NewCustomerPaymentHandler(service *CustomerPaymentService) *CustomerPaymentHandler {
+	return &CustomerPaymentHandler{service: service}
+}
+
+// GET /buildings/:id/customer-payments/open-invoices
+func (h *CustomerPaymentHandler) GetOpenInvoices(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	peopleID, err := strconv.Atoi(c.Query("people_id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("people_id is required"))
+		return
+	}
+	var unitID *int
+	if unitIDStr := c.Query("unit_id"); unitIDStr != "" {
+		id, err := strconv.Atoi(unitIDStr)
+		if err != nil {
+			apperrors.Respond(c, apperrors.BadRequest("Invalid Unit ID"))
+			return
+		}
+		unitID = &id
+	}
+
+	invoices, err := h.service.OpenInvoices(buildingID, peopleID, unitID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
+// POST /buildings/:id/customer-payments/preview
+func (h *CustomerPaymentHandler) PreviewCustomerPayment(c *gin.Context) {
+	var req CreateCustomerPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	preview, validationErr, err := h.service.PreviewCustomerPayment(buildingID, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// POST /buildings/:id/customer-payments
+func (h *CustomerPaymentHandler) CreateCustomerPayment(c *gin.Context) {
+	var req CreateCustomerPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	response, validationErr, err := h.service.WithLogger(logging.FromGin(c)).CreateCustomerPayment(buildingID, req, userID)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	versioning.SetETag(c, response.Payment.Version)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GET /buildings/:id/customer-payments
+func (h *CustomerPaymentHandler) GetCustomerPayments(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	filter := ListFilter{
+		StartDate: c.Query("start_date"),
+		EndDate:   c.Query("end_date"),
+		Status:    c.Query("status"),
+		Unapplied: c.Query("unapplied") == "true",
+	}
+	if peopleIDStr := c.Query("people_id"); peopleIDStr != "" {
+		peopleID, err := strconv.Atoi(peopleIDStr)
+		if err != nil {
+			apperrors.Respond(c, apperrors.BadRequest("Invalid People ID"))
+			return
+		}
+		filter.PeopleID = &peopleID
+	}
+	if prepaymentStr := c.Query("prepayment"); prepaymentStr != "" {
+		prepayment, err := strconv.ParseBool(prepaymentStr)
+		if err != nil {
+			apperrors.Respond(c, apperrors.BadRequest("Invalid prepayment filter"))
+			return
+		}
+		filter.Prepayment = &prepayment
+	}
+
+	payments, err := h.service.ListCustomerPayments(buildingID, filter)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// GET /buildings/:id/customer-payments/:paymentId
+func (h *CustomerPaymentHandler) GetCustomerPayment(c *gin.Context) {
+	buildingID, id, ok := paymentParams(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetCustomerPayment(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	versioning.SetETag(c, response.Payment.Version)
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /buildings/:id/customer-payments/:paymentId/apply
+func (h *CustomerPaymentHandler) ApplyCredit(c *gin.Context) {
+	var req ApplyCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, id, ok := paymentParams(c)
+	if !ok {
+		return
+	}
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	response, validationErr, err := h.service.WithLogger(logging.FromGin(c)).ApplyCredit(buildingID, id, req, userID)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	versioning.SetETag(c, response.Payment.Version)
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /buildings/:id/customer-payments/:paymentId/void
+func (h *CustomerPaymentHandler) VoidCustomerPayment(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+	buildingID, id, ok := paymentParams(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.WithLogger(logging.FromGin(c)).VoidCustomerPayment(buildingID, id, version)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	versioning.SetETag(c, response.Payment.Version)
+	c.JSON(http.StatusOK, response)
+}
+
+func paymentParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("paymentId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Customer Payment ID"))
+		return 0, 0, false
+	}
+	return buildingID, id, true
+}
+
+// userParam reads the User-ID header, or the user_id query parameter, responding when it is
+// missing or invalid
+func userParam(c *gin.Context) (int, bool) {
+	userIDStr := c.GetHeader("User-ID")
+	if userIDStr == "" {
+		userIDStr = c.Query("user_id")
+	}
+	if userIDStr == "" {
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
+		return 0, false
+	}
+
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,56 @@
+package customer_payments
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"CustomerPaymentHandler.GetOpenInvoices": {
+		Summary: "List a customer's invoices a payment can be allocated to, oldest due first",
+		Query: []openapi.Param{
+			{Name: "people_id", Type: "integer", Format: "int32", Required: true},
+			{Name: "unit_id", Type: "integer", Format: "int32"},
+		},
+		Response: []OpenInvoice{},
+	},
+	"CustomerPaymentHandler.PreviewCustomerPayment": {
+		Summary:     "Preview a customer payment's allocations and postings",
+		Description: "Validates the payment and returns how it would be allocated and the splits it would post without saving anything.",
+		Request:     CreateCustomerPaymentRequest{},
+		Response:    CustomerPaymentPreviewResponse{},
+	},
+	"CustomerPaymentHandler.CreateCustomerPayment": {
+		Summary:     "Receive a payment covering several invoices",
+		Description: "Allocates the payment to the listed invoices, or oldest due first with auto_allocate, in one transaction. What is not allocated is kept as unapplied credit on the A/R account.",
+		UserID:      true,
+		Idempotent:  true,
+		Request:     CreateCustomerPaymentRequest{},
+		Response:    CustomerPaymentResponse{},
+	},
+	"CustomerPaymentHandler.GetCustomerPayments": {
+		Summary: "List customer payments",
+		Query: []openapi.Param{
+			{Name: "start_date", Format: "date"},
+			{Name: "end_date", Format: "date"},
+			{Name: "people_id", Type: "integer", Format: "int32"},
+			{Name: "status", Description: "1 for active, 0 for voided"},
+			{Name: "unapplied", Type: "boolean", Description: "Only active payments with unapplied credit left"},
+			{Name: "prepayment", Type: "boolean", Description: "true for prepayments only, false for other payments only"},
+		},
+		Response: []CustomerPayment{},
+	},
+	"CustomerPaymentHandler.GetCustomerPayment": {Summary: "Get a customer payment with its allocations and postings", Response: CustomerPaymentResponse{}},
+	"CustomerPaymentHandler.ApplyCredit": {
+		Summary:     "Apply a customer payment's unapplied credit to invoices",
+		Description: "Posts a transaction of its own, dated when the credit is applied, that moves the credit to the invoices' receivables.",
+		UserID:      true,
+		Idempotent:  true,
+		Request:     ApplyCreditRequest{},
+		Response:    CustomerPaymentResponse{},
+	},
+	"CustomerPaymentHandler.VoidCustomerPayment": {
+		Summary:     "Void a customer payment",
+		Description: "Voids the payment, all of its allocations and every transaction it posted, including later applications of its credit.",
+		IfMatch:     true,
+		Idempotent:  true,
+		Response:    CustomerPaymentResponse{},
+	},
+}
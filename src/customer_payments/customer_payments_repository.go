@@ -0,0 +1,196 @@
+package customer_payments
+
+import (
+	"database/sql"
+)
+
+type CustomerPaymentRepository interface {
+	GetByID(buildingID int, id int) (CustomerPayment, error)
+	List(buildingID int, filter ListFilter) ([]CustomerPayment, error)
+	ListAllocations(paymentID int) ([]Allocation, error)
+	OpenInvoices(buildingID int, peopleID int, unitID *int) ([]OpenInvoice, error)
+	PersonName(buildingID int, peopleID int) (string, error)
+	UnitExists(buildingID int, unitID int) (bool, error)
+}
+
+// ListFilter narrows the list of customer payments; zero values don't filter
+type ListFilter struct {
+	PeopleID   *int
+	StartDate  string
+	EndDate    string
+	Status     string
+	Unapplied  bool  // Only active payments with credit left to apply
+	Prepayment *bool // Only prepayments, or only payments that are not
+}
+
+type customerPaymentRepo struct {
+	db *sql.DB
+}
+
+func NewCustomerPaymentRepository(db *sql.DB) CustomerPaymentRepository {
+	return &customerPaymentRepo{db: db}
+}
+
+// appliedAmount is the total of a payment's active allocations
+const appliedAmount = "COALESCE((SELECT SUM(ip.amount) FROM invoice_payments ip WHERE ip.customer_payment_id = cp.id AND ip.status = '1'), 0)"
+
+const paymentColumns = "cp.id, cp.transaction_id, cp.reference, DATE(cp.date), cp.people_id, p.name, cp.unit_id, cp.building_id, cp.account_id," +
+	" cp.ar_account_id, cp.liability_account_id, cp.amount, " + appliedAmount + ", cp.memo, cp.user_id, cp.status, cp.version, cp.created_at, cp.updated_at" +
+	" FROM customer_payments cp INNER JOIN people p ON p.id = cp.people_id"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPayment(row scanner) (CustomerPayment, error) {
+	var p CustomerPayment
+	err := row.Scan(&p.ID, &p.TransactionID, &p.Reference, &p.Date, &p.PeopleID, &p.PeopleName, &p.UnitID, &p.BuildingID, &p.AccountID,
+		&p.ARAccountID, &p.LiabilityAccountID, &p.Amount, &p.AppliedAmount, &p.Memo, &p.UserID, &p.Status, &p.Version, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return p, err
+	}
+	p.Date = dateOnly(p.Date)
+	p.AppliedAmount = round2(p.AppliedAmount)
+	if p.Status == "1" {
+		p.UnappliedAmount = round2(p.Amount - p.AppliedAmount)
+	}
+	return p, nil
+}
+
+func (r *customerPaymentRepo) GetByID(buildingID int, id int) (CustomerPayment, error) {
+	return scanPayment(r.db.QueryRow("SELECT "+paymentColumns+" WHERE cp.building_id = ? AND cp.id = ?", buildingID, id))
+}
+
+// List returns the building's customer payments, newest first
+func (r *customerPaymentRepo) List(buildingID int, filter ListFilter) ([]CustomerPayment, error) {
+	query := "SELECT " + paymentColumns + " WHERE cp.building_id = ?"
+	args := []interface{}{buildingID}
+	if filter.PeopleID != nil {
+		query += " AND cp.people_id = ?"
+		args = append(args, *filter.PeopleID)
+	}
+	if filter.StartDate != "" {
+		query += " AND DATE(cp.date) >= ?"
+		args = append(args, filter.StartDate)
+	}
+	if filter.EndDate != "" {
+		query += " AND DATE(cp.date) <= ?"
+		args = append(args, filter.EndDate)
+	}
+	if filter.Status != "" {
+		query += " AND cp.status = ?"
+		args = append(args, filter.Status)
+	}
+	if filter.Prepayment != nil {
+		if *filter.Prepayment {
+			query += " AND cp.liability_account_id IS NOT NULL"
+		} else {
+			query += " AND cp.liability_account_id IS NULL"
+		}
+	}
+	if filter.Unapplied {
+		query += " AND cp.status = '1' AND cp.amount - " + appliedAmount + " > 0.005"
+	}
+
+	rows, err := r.db.Query(query+" ORDER BY cp.date DESC, cp.id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []CustomerPayment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+// ListAllocations returns every allocation of the payment, voided ones too, in the order
+// they were made
+func (r *customerPaymentRepo) ListAllocations(paymentID int) ([]Allocation, error) {
+	rows, err := r.db.Query(`
+		SELECT ip.id, ip.transaction_id, ip.invoice_id, i.invoice_no, i.unit_id, DATE(ip.date), ip.amount, ip.status, ip.version
+		FROM invoice_payments ip
+		INNER JOIN invoices i ON i.id = ip.invoice_id
+		WHERE ip.customer_payment_id = ?
+		ORDER BY ip.id`, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	allocations := []Allocation{}
+	for rows.Next() {
+		var a Allocation
+		if err := rows.Scan(&a.ID, &a.TransactionID, &a.InvoiceID, &a.InvoiceNo, &a.UnitID, &a.Date, &a.Amount, &a.Status, &a.Version); err != nil {
+			return nil, err
+		}
+		a.Date = dateOnly(a.Date)
+		allocations = append(allocations, a)
+	}
+	return allocations, rows.Err()
+}
+
+// OpenInvoices returns the customer's active invoices with a balance left, oldest due first,
+// optionally of one unit
+func (r *customerPaymentRepo) OpenInvoices(buildingID int, peopleID int, unitID *int) ([]OpenInvoice, error) {
+	query := `
+		SELECT i.id, i.invoice_no, DATE(i.sales_date), DATE(i.due_date), i.unit_id, i.ar_account_id, i.amount,
+			i.amount
+			- COALESCE((SELECT SUM(ip.amount) FROM invoice_payments ip WHERE ip.invoice_id = i.id AND ip.status = '1'), 0)
+			- COALESCE((SELECT SUM(c.amount) FROM invoice_applied_credits c WHERE c.invoice_id = i.id AND c.status = '1'), 0)
+			- COALESCE((SELECT SUM(d.amount) FROM invoice_applied_discounts d WHERE d.invoice_id = i.id AND d.status = '1'), 0)
+		FROM invoices i
+		WHERE i.building_id = ? AND i.people_id = ? AND i.status = '1'`
+	args := []interface{}{buildingID, peopleID}
+	if unitID != nil {
+		query += " AND i.unit_id = ?"
+		args = append(args, *unitID)
+	}
+
+	rows, err := r.db.Query(query+" ORDER BY i.due_date, i.sales_date, i.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := []OpenInvoice{}
+	for rows.Next() {
+		var i OpenInvoice
+		if err := rows.Scan(&i.ID, &i.InvoiceNo, &i.SalesDate, &i.DueDate, &i.UnitID, &i.ARAccountID, &i.Amount, &i.Balance); err != nil {
+			return nil, err
+		}
+		i.Balance = round2(i.Balance)
+		if i.Balance <= 0 {
+			continue
+		}
+		i.SalesDate = dateOnly(i.SalesDate)
+		i.DueDate = dateOnly(i.DueDate)
+		invoices = append(invoices, i)
+	}
+	return invoices, rows.Err()
+}
+
+func (r *customerPaymentRepo) PersonName(buildingID int, peopleID int) (string, error) {
+	var name string
+	err := r.db.QueryRow("SELECT name FROM people WHERE id = ? AND building_id = ?", peopleID, buildingID).Scan(&name)
+	return name, err
+}
+
+func (r *customerPaymentRepo) UnitExists(buildingID int, unitID int) (bool, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM units WHERE id = ? AND building_id = ?", unitID, buildingID).Scan(&count)
+	return count > 0, err
+}
+
+// dateOnly drops the time some drivers return with DATE columns
+func dateOnly(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
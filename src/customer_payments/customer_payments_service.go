@@ -0,0 +1,663 @@
+package customer_payments
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/splits"
+	"github.com/mysecodgit/go_accounting/src/transactions"
+	"github.com/mysecodgit/go_accounting/src/versioning"
+)
+
+type CustomerPaymentService struct {
+	repo            CustomerPaymentRepository
+	transactionRepo transactions.TransactionRepository
+	splitRepo       splits.SplitRepository
+	accountRepo     accounts.AccountRepository
+	db              *sql.DB
+	logger          *slog.Logger
+}
+
+func NewCustomerPaymentService(
+	repo CustomerPaymentRepository,
+	transactionRepo transactions.TransactionRepository,
+	splitRepo splits.SplitRepository,
+	accountRepo accounts.AccountRepository,
+	db *sql.DB,
+	logger *slog.Logger,
+) *CustomerPaymentService {
+	return &CustomerPaymentService{
+		repo:            repo,
+		transactionRepo: transactionRepo,
+		splitRepo:       splitRepo,
+		accountRepo:     accountRepo,
+		db:              db,
+		logger:          logger,
+	}
+}
+
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *CustomerPaymentService) WithLogger(logger *slog.Logger) *CustomerPaymentService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
+// plannedAllocation is the amount of a payment to pay to one open invoice
+type plannedAllocation struct {
+	invoice OpenInvoice
+	amount  float64
+}
+
+// posting is a split before it is written
+type posting struct {
+	accountID int
+	unitID    *int
+	debit     float64
+	credit    float64
+}
+
+// OpenInvoices lists the customer's invoices a payment can be allocated to, oldest due first
+func (s *CustomerPaymentService) OpenInvoices(buildingID int, peopleID int, unitID *int) ([]OpenInvoice, error) {
+	invoices, err := s.repo.OpenInvoices(buildingID, peopleID, unitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load open invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// PreviewCustomerPayment returns how the payment would be allocated and the splits it would
+// post, without saving anything
+func (s *CustomerPaymentService) PreviewCustomerPayment(buildingID int, req CreateCustomerPaymentRequest) (*CustomerPaymentPreviewResponse, map[string]string, error) {
+	payment, planned, validationErrors, err := s.prepare(buildingID, req)
+	if validationErrors != nil || err != nil {
+		return nil, validationErrors, err
+	}
+
+	allocations := []AllocationPreview{}
+	for _, allocation := range planned {
+		allocations = append(allocations, AllocationPreview{
+			InvoiceID: allocation.invoice.ID,
+			InvoiceNo: allocation.invoice.InvoiceNo,
+			DueDate:   allocation.invoice.DueDate,
+			Balance:   allocation.invoice.Balance,
+			Amount:    allocation.amount,
+		})
+	}
+
+	preview := &CustomerPaymentPreviewResponse{
+		Allocations:     allocations,
+		UnappliedAmount: round2(payment.Amount - allocatedTotal(planned)),
+		Splits:          []SplitPreview{},
+	}
+	accountNames := make(map[int]string)
+	for _, p := range paymentPostings(payment, planned) {
+		if _, ok := accountNames[p.accountID]; !ok {
+			account, _, _, err := s.accountRepo.GetByID(p.accountID)
+			if err != nil {
+				return nil, nil, apperrors.Lookup("account", err)
+			}
+			accountNames[p.accountID] = account.AccountName
+		}
+		split := SplitPreview{AccountID: p.accountID, AccountName: accountNames[p.accountID], PeopleID: &payment.PeopleID, UnitID: p.unitID, Status: "1"}
+		if p.debit > 0 {
+			debit := p.debit
+			split.Debit = &debit
+			preview.TotalDebit += debit
+		} else {
+			credit := p.credit
+			split.Credit = &credit
+			preview.TotalCredit += credit
+		}
+		preview.Splits = append(preview.Splits, split)
+	}
+	preview.TotalDebit = round2(preview.TotalDebit)
+	preview.TotalCredit = round2(preview.TotalCredit)
+	preview.IsBalanced = preview.TotalDebit == preview.TotalCredit
+
+	return preview, nil, nil
+}
+
+// CreateCustomerPayment records the payment in one transaction: the deposit account is
+// debited with the whole amount, each allocated invoice's receivable is credited with its
+// allocation and the payment's receivable, or a prepayment's liability, with what is left
+// unapplied
+func (s *CustomerPaymentService) CreateCustomerPayment(buildingID int, req CreateCustomerPaymentRequest, userID int) (*CustomerPaymentResponse, map[string]string, error) {
+	payment, planned, validationErrors, err := s.prepare(buildingID, req)
+	if validationErrors != nil || err != nil {
+		return nil, validationErrors, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transactionType, _ := payment.transactionTypes()
+	memo := payment.Memo
+	if memo == "" && payment.IsPrepayment() {
+		memo = "Prepayment from " + payment.PeopleName
+	} else if memo == "" {
+		memo = "Payment from " + payment.PeopleName
+	}
+	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		transactionType, payment.Date, payment.Reference, memo, "1", buildingID, userID, payment.UnitID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	transactionID, err := result.LastInsertId()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transaction ID: %w", err)
+	}
+
+	now := time.Now().UTC().Format(timeLayout)
+	result, err = tx.Exec("INSERT INTO customer_payments (transaction_id, reference, date, people_id, unit_id, building_id, account_id, ar_account_id, liability_account_id, amount, memo, user_id, status, created_at, updated_at)"+
+		" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '1', ?, ?)",
+		transactionID, payment.Reference, payment.Date, payment.PeopleID, payment.UnitID, buildingID, payment.AccountID, payment.ARAccountID,
+		payment.LiabilityAccountID, payment.Amount, payment.Memo, userID, now, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create customer payment: %w", err)
+	}
+	paymentID, err := result.LastInsertId()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get customer payment ID: %w", err)
+	}
+	payment.ID = int(paymentID)
+
+	if err := writeSplits(tx, int(transactionID), payment.PeopleID, paymentPostings(payment, planned)); err != nil {
+		return nil, nil, err
+	}
+	if err := writeAllocations(tx, payment, int(transactionID), payment.Date, planned, userID); err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	metrics.RecordPosting(transactionType, metrics.PostingCreated)
+	s.logger.Info("customer payment created", "customer_payment_id", paymentID, "transaction_id", transactionID,
+		"prepayment", payment.IsPrepayment(), "allocations", len(planned), "unapplied", round2(payment.Amount-allocatedTotal(planned)))
+
+	response, err := s.GetCustomerPayment(buildingID, payment.ID)
+	return response, nil, err
+}
+
+// ApplyCredit applies the payment's unapplied credit to open invoices of the customer, in a
+// transaction of its own dated when the credit is applied
+func (s *CustomerPaymentService) ApplyCredit(buildingID int, id int, req ApplyCreditRequest, userID int) (*CustomerPaymentResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	payment, err := s.repo.GetByID(buildingID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, apperrors.NotFound("customer payment")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load customer payment: %w", err)
+	}
+	if payment.Status != "1" {
+		return nil, nil, apperrors.Rule("The customer payment is voided")
+	}
+	if payment.UnappliedAmount <= 0 {
+		return nil, nil, apperrors.Rule("The customer payment has no unapplied credit left")
+	}
+	if req.Date < payment.Date {
+		return nil, map[string]string{"date": "Date must not be before the payment's date " + payment.Date}, nil
+	}
+
+	open, err := s.repo.OpenInvoices(buildingID, payment.PeopleID, payment.UnitID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load open invoices: %w", err)
+	}
+	planned, validationErrors := allocate(payment.UnappliedAmount, open, req.AutoAllocate, req.Allocations)
+	if validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	if len(planned) == 0 {
+		return nil, nil, apperrors.Rule("The customer has no open invoices to apply the credit to")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Claim the payment so that two applications can't both spend the same credit
+	if err := versioning.Bump(tx, "customer_payments", payment.ID, payment.Version, "customer payment"); err != nil {
+		return nil, nil, err
+	}
+
+	_, applicationType := payment.transactionTypes()
+	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		applicationType, req.Date, payment.Reference, applicationMemo(payment), "1", buildingID, userID, payment.UnitID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	transactionID, err := result.LastInsertId()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transaction ID: %w", err)
+	}
+
+	if err := writeSplits(tx, int(transactionID), payment.PeopleID, applicationPostings(payment, planned)); err != nil {
+		return nil, nil, err
+	}
+	if err := writeAllocations(tx, payment, int(transactionID), req.Date, planned, userID); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec("UPDATE customer_payments SET updated_at = ? WHERE id = ?", time.Now().UTC().Format(timeLayout), payment.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to update customer payment: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	metrics.RecordPosting(applicationType, metrics.PostingCreated)
+	s.logger.Info("customer payment credit applied", "customer_payment_id", payment.ID, "transaction_id", transactionID,
+		"allocations", len(planned), "amount", allocatedTotal(planned))
+
+	response, err := s.GetCustomerPayment(buildingID, payment.ID)
+	return response, nil, err
+}
+
+// ApplyPrepayments applies the customer's prepayments to an invoice, oldest first, until the
+// invoice is paid or the prepayments are used up. Prepayments of another unit are left alone;
+// ones without a unit apply to any of the customer's invoices. Each one is applied in a
+// transaction of its own, dated the invoice's date or the prepayment's if that is later.
+// It returns the amount applied.
+func (s *CustomerPaymentService) ApplyPrepayments(buildingID int, peopleID int, invoiceID int, userID int) (float64, error) {
+	open, err := s.repo.OpenInvoices(buildingID, peopleID, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load open invoices: %w", err)
+	}
+	var invoice *OpenInvoice
+	for i := range open {
+		if open[i].ID == invoiceID {
+			invoice = &open[i]
+			break
+		}
+	}
+	if invoice == nil {
+		return 0, nil
+	}
+
+	prepayment := true
+	prepayments, err := s.repo.List(buildingID, ListFilter{PeopleID: &peopleID, Unapplied: true, Prepayment: &prepayment})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load prepayments: %w", err)
+	}
+
+	applied := 0.0
+	left := invoice.Balance
+	// The list is newest first
+	for i := len(prepayments) - 1; i >= 0 && left > 0; i-- {
+		p := prepayments[i]
+		if p.UnitID != nil && (invoice.UnitID == nil || *p.UnitID != *invoice.UnitID) {
+			continue
+		}
+		amount := math.Min(p.UnappliedAmount, left)
+		date := invoice.SalesDate
+		if p.Date > date {
+			date = p.Date
+		}
+		req := ApplyCreditRequest{Date: date, Allocations: []AllocationRequest{{InvoiceID: invoiceID, Amount: amount}}}
+		_, validationErrors, err := s.ApplyCredit(buildingID, p.ID, req, userID)
+		if err != nil {
+			return applied, err
+		}
+		if validationErrors != nil {
+			return applied, fmt.Errorf("failed to apply prepayment %d: %w", p.ID, apperrors.Validation(validationErrors))
+		}
+		applied = round2(applied + amount)
+		left = round2(left - amount)
+	}
+	return applied, nil
+}
+
+// VoidCustomerPayment voids the payment with every allocation and every transaction it
+// posted, including the ones that applied its credit later
+func (s *CustomerPaymentService) VoidCustomerPayment(buildingID int, id int, expectedVersion int) (*CustomerPaymentResponse, error) {
+	payment, err := s.repo.GetByID(buildingID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("customer payment")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load customer payment: %w", err)
+	}
+	if payment.Status != "1" {
+		return nil, apperrors.Conflict("customer payment is already voided")
+	}
+	allocations, err := s.repo.ListAllocations(payment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load allocations: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := versioning.Bump(tx, "customer_payments", payment.ID, expectedVersion, "customer payment"); err != nil {
+		return nil, err
+	}
+
+	ownTransactions := "(SELECT transaction_id FROM invoice_payments WHERE customer_payment_id = ?)"
+	if _, err := tx.Exec("UPDATE customer_payments SET status = '0', updated_at = ? WHERE id = ?", time.Now().UTC().Format(timeLayout), payment.ID); err != nil {
+		return nil, fmt.Errorf("failed to void customer payment: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE transactions SET status = '0' WHERE id = ? OR id IN "+ownTransactions, payment.TransactionID, payment.ID); err != nil {
+		return nil, fmt.Errorf("failed to void transactions: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE splits SET status = '0' WHERE transaction_id = ? OR transaction_id IN "+ownTransactions, payment.TransactionID, payment.ID); err != nil {
+		return nil, fmt.Errorf("failed to void splits: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE invoice_payments SET status = '0', version = version + 1 WHERE customer_payment_id = ? AND status = '1'", payment.ID); err != nil {
+		return nil, fmt.Errorf("failed to void allocations: %w", err)
+	}
+
+	// Subscribers know allocations as invoice payments, so each one is voided as such
+	for _, allocation := range allocations {
+		if allocation.Status != 1 {
+			continue
+		}
+		err := outbox.Write(tx, buildingID, outbox.PaymentVoided, allocation.ID, outbox.Document{
+			ID:            allocation.ID,
+			TransactionID: allocation.TransactionID,
+			Number:        payment.Reference,
+			Date:          allocation.Date,
+			Amount:        allocation.Amount,
+			PeopleID:      &payment.PeopleID,
+			UnitID:        allocation.UnitID,
+			InvoiceID:     &allocation.InvoiceID,
+			Version:       allocation.Version + 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	transactionType, _ := payment.transactionTypes()
+	metrics.RecordPosting(transactionType, metrics.PostingUpdated)
+	s.logger.Info("customer payment voided", "customer_payment_id", payment.ID, "transaction_id", payment.TransactionID)
+
+	return s.GetCustomerPayment(buildingID, payment.ID)
+}
+
+func (s *CustomerPaymentService) ListCustomerPayments(buildingID int, filter ListFilter) ([]CustomerPayment, error) {
+	payments, err := s.repo.List(buildingID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load customer payments: %w", err)
+	}
+	return payments, nil
+}
+
+// GetCustomerPayment returns the payment with its allocations and the splits of its transaction
+func (s *CustomerPaymentService) GetCustomerPayment(buildingID int, id int) (*CustomerPaymentResponse, error) {
+	payment, err := s.repo.GetByID(buildingID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, apperrors.NotFound("customer payment")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load customer payment: %w", err)
+	}
+
+	allocations, err := s.repo.ListAllocations(payment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load allocations: %w", err)
+	}
+	transaction, err := s.transactionRepo.GetByID(payment.TransactionID)
+	if err != nil {
+		return nil, apperrors.Lookup("transaction", err)
+	}
+	paymentSplits, err := s.splitRepo.GetByTransactionID(payment.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
+	}
+
+	return &CustomerPaymentResponse{
+		Payment:     payment,
+		Allocations: allocations,
+		Transaction: transaction,
+		Splits:      paymentSplits,
+	}, nil
+}
+
+// prepare checks a new payment and plans its allocations
+func (s *CustomerPaymentService) prepare(buildingID int, req CreateCustomerPaymentRequest) (CustomerPayment, []plannedAllocation, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return CustomerPayment{}, nil, validationErrors, nil
+	}
+
+	name, err := s.repo.PersonName(buildingID, req.PeopleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CustomerPayment{}, nil, map[string]string{"people_id": "Customer not found in this building"}, nil
+	}
+	if err != nil {
+		return CustomerPayment{}, nil, nil, fmt.Errorf("failed to load customer: %w", err)
+	}
+	if req.UnitID != nil {
+		exists, err := s.repo.UnitExists(buildingID, *req.UnitID)
+		if err != nil {
+			return CustomerPayment{}, nil, nil, fmt.Errorf("failed to load unit: %w", err)
+		}
+		if !exists {
+			return CustomerPayment{}, nil, map[string]string{"unit_id": "Unit not found in this building"}, nil
+		}
+	}
+
+	account, accountType, _, err := s.accountRepo.GetByID(req.AccountID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && account.BuildingID != buildingID) {
+		return CustomerPayment{}, nil, map[string]string{"account_id": "Deposit account not found in this building"}, nil
+	}
+	if err != nil {
+		return CustomerPayment{}, nil, nil, fmt.Errorf("failed to load deposit account: %w", err)
+	}
+	if !strings.EqualFold(accountType.Type, "asset") || isReceivable(accountType.TypeName) {
+		return CustomerPayment{}, nil, map[string]string{"account_id": "Deposit account must be a cash or bank account"}, nil
+	}
+
+	if req.ARAccountID != nil {
+		arAccount, arAccountType, _, err := s.accountRepo.GetByID(*req.ARAccountID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && arAccount.BuildingID != buildingID) {
+			return CustomerPayment{}, nil, map[string]string{"ar_account_id": "A/R account not found in this building"}, nil
+		}
+		if err != nil {
+			return CustomerPayment{}, nil, nil, fmt.Errorf("failed to load A/R account: %w", err)
+		}
+		if !isReceivable(arAccountType.TypeName) {
+			return CustomerPayment{}, nil, map[string]string{"ar_account_id": "A/R account must be an Account Receivable account"}, nil
+		}
+	}
+	if req.LiabilityAccountID != nil {
+		liabilityAccount, liabilityAccountType, _, err := s.accountRepo.GetByID(*req.LiabilityAccountID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && liabilityAccount.BuildingID != buildingID) {
+			return CustomerPayment{}, nil, map[string]string{"liability_account_id": "Liability account not found in this building"}, nil
+		}
+		if err != nil {
+			return CustomerPayment{}, nil, nil, fmt.Errorf("failed to load liability account: %w", err)
+		}
+		// Payables belong to vendors; customer deposits are held on another liability
+		if !strings.EqualFold(liabilityAccountType.Type, "liability") || strings.EqualFold(liabilityAccountType.TypeName, "Account Payable") {
+			return CustomerPayment{}, nil, map[string]string{"liability_account_id": "Liability account must be a customer deposits liability account"}, nil
+		}
+	}
+
+	payment := CustomerPayment{
+		Reference:          strings.TrimSpace(req.Reference),
+		Date:               req.Date,
+		PeopleID:           req.PeopleID,
+		PeopleName:         name,
+		UnitID:             req.UnitID,
+		BuildingID:         buildingID,
+		AccountID:          req.AccountID,
+		ARAccountID:        req.ARAccountID,
+		LiabilityAccountID: req.LiabilityAccountID,
+		Amount:             round2(req.Amount),
+		Memo:               strings.TrimSpace(req.Memo),
+		Status:             "1",
+	}
+
+	open, err := s.repo.OpenInvoices(buildingID, req.PeopleID, req.UnitID)
+	if err != nil {
+		return CustomerPayment{}, nil, nil, fmt.Errorf("failed to load open invoices: %w", err)
+	}
+	planned, validationErrors := allocate(payment.Amount, open, req.AutoAllocate, req.Allocations)
+	if validationErrors != nil {
+		return CustomerPayment{}, nil, validationErrors, nil
+	}
+	return payment, planned, nil, nil
+}
+
+// allocate spreads up to limit over the open invoices: to the requested invoices, or oldest
+// due first when auto
+func allocate(limit float64, open []OpenInvoice, auto bool, requested []AllocationRequest) ([]plannedAllocation, map[string]string) {
+	planned := []plannedAllocation{}
+	if auto {
+		left := limit
+		for _, invoice := range open {
+			if left <= 0 {
+				break
+			}
+			amount := math.Min(invoice.Balance, left)
+			planned = append(planned, plannedAllocation{invoice: invoice, amount: amount})
+			left = round2(left - amount)
+		}
+		return planned, nil
+	}
+
+	openByID := make(map[int]OpenInvoice, len(open))
+	for _, invoice := range open {
+		openByID[invoice.ID] = invoice
+	}
+	errors := make(map[string]string)
+	for i, allocation := range requested {
+		invoice, ok := openByID[allocation.InvoiceID]
+		if !ok {
+			errors[fmt.Sprintf("allocations[%d].invoice_id", i)] = "Invoice is not an open invoice of the customer"
+			continue
+		}
+		amount := round2(allocation.Amount)
+		if amount > invoice.Balance {
+			errors[fmt.Sprintf("allocations[%d].amount", i)] = fmt.Sprintf("Amount must not exceed the invoice's balance of %.2f", invoice.Balance)
+			continue
+		}
+		planned = append(planned, plannedAllocation{invoice: invoice, amount: amount})
+	}
+	if len(errors) > 0 {
+		return nil, errors
+	}
+	if total := allocatedTotal(planned); total > limit {
+		return nil, map[string]string{"allocations": fmt.Sprintf("Allocations total %.2f, more than the %.2f available", total, limit)}
+	}
+	return planned, nil
+}
+
+// paymentPostings debits the deposit account with the whole amount and credits each invoice's
+// receivable with its allocation and the account holding the payment's credit with the rest
+func paymentPostings(payment CustomerPayment, planned []plannedAllocation) []posting {
+	postings := []posting{{accountID: payment.AccountID, unitID: payment.UnitID, debit: payment.Amount}}
+	for _, allocation := range planned {
+		postings = append(postings, posting{accountID: allocation.invoice.ARAccountID, unitID: allocation.invoice.UnitID, credit: allocation.amount})
+	}
+	if unapplied := round2(payment.Amount - allocatedTotal(planned)); unapplied > 0 {
+		postings = append(postings, posting{accountID: payment.creditAccountID(), unitID: payment.UnitID, credit: unapplied})
+	}
+	return postings
+}
+
+// applicationPostings moves applied credit from the account holding it to each invoice's
+// receivable
+func applicationPostings(payment CustomerPayment, planned []plannedAllocation) []posting {
+	postings := []posting{{accountID: payment.creditAccountID(), unitID: payment.UnitID, debit: allocatedTotal(planned)}}
+	for _, allocation := range planned {
+		postings = append(postings, posting{accountID: allocation.invoice.ARAccountID, unitID: allocation.invoice.UnitID, credit: allocation.amount})
+	}
+	return postings
+}
+
+func applicationMemo(payment CustomerPayment) string {
+	if payment.IsPrepayment() {
+		return fmt.Sprintf("Prepayment %d applied", payment.ID)
+	}
+	return fmt.Sprintf("Credit of customer payment %d applied", payment.ID)
+}
+
+func writeSplits(tx *sql.Tx, transactionID int, peopleID int, postings []posting) error {
+	for _, p := range postings {
+		var debit, credit interface{}
+		if p.debit > 0 {
+			debit = p.debit
+		} else {
+			credit = p.credit
+		}
+		_, err := tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			transactionID, p.accountID, peopleID, p.unitID, debit, credit, "1")
+		if err != nil {
+			return fmt.Errorf("failed to create split: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeAllocations records each allocation as an invoice payment of the transaction, with
+// the event subscribers get for any invoice payment
+func writeAllocations(tx *sql.Tx, payment CustomerPayment, transactionID int, date string, planned []plannedAllocation, userID int) error {
+	for _, allocation := range planned {
+		result, err := tx.Exec("INSERT INTO invoice_payments (transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, customer_payment_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			transactionID, payment.Reference, date, allocation.invoice.ID, userID, payment.AccountID, allocation.amount, "1", payment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create invoice payment: %w", err)
+		}
+		invoicePaymentID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get invoice payment ID: %w", err)
+		}
+
+		err = outbox.Write(tx, payment.BuildingID, outbox.PaymentCreated, int(invoicePaymentID), outbox.Document{
+			ID:            int(invoicePaymentID),
+			TransactionID: transactionID,
+			Number:        payment.Reference,
+			Date:          date,
+			Amount:        allocation.amount,
+			PeopleID:      &payment.PeopleID,
+			UnitID:        allocation.invoice.UnitID,
+			InvoiceID:     &allocation.invoice.ID,
+			Version:       1,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func allocatedTotal(planned []plannedAllocation) float64 {
+	total := 0.0
+	for _, allocation := range planned {
+		total += allocation.amount
+	}
+	return round2(total)
+}
+
+func isReceivable(typeName string) bool {
+	return strings.EqualFold(typeName, "Account Receivable")
+}
+
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,234 @@
+package customer_payments
+
+import (
+	"database/sql"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mysecodgit/go_accounting/dialect"
+	"github.com/mysecodgit/go_accounting/migrations"
+	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/splits"
+	"github.com/mysecodgit/go_accounting/src/transactions"
+)
+
+// testFixture holds the ids of the rows seeded for a test
+type testFixture struct {
+	userID     int
+	buildingID int
+	peopleID   int
+	bankID     int
+	arID       int
+	depositsID int
+}
+
+// openMigratedDB opens an empty database with every migration applied. It is a SQLite file
+// unless ACCOUNTING_TEST_DB_DRIVER and ACCOUNTING_TEST_DB_DSN name an empty MySQL or
+// PostgreSQL database to run against instead.
+func openMigratedDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	driverName := os.Getenv("ACCOUNTING_TEST_DB_DRIVER")
+	dsn := os.Getenv("ACCOUNTING_TEST_DB_DSN")
+	if driverName == "" {
+		driverName = string(dialect.SQLite)
+		dsn = "file:" + filepath.Join(t.TempDir(), "test.db")
+	}
+	driver, err := dialect.Parse(driverName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := dialect.Current
+	dialect.Current = driver
+	t.Cleanup(func() { dialect.Current = previous })
+
+	db, err := sql.Open(driver.DriverName(), driver.PrepareDSN(dsn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+func insertRow(t *testing.T, db *sql.DB, query string, args ...interface{}) int {
+	t.Helper()
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(id)
+}
+
+func seed(t *testing.T, db *sql.DB) testFixture {
+	t.Helper()
+	var f testFixture
+	f.userID = insertRow(t, db, "INSERT INTO users (name, username, phone, password) VALUES ('Admin', 'admin', '1', '')")
+	f.buildingID = insertRow(t, db, "INSERT INTO buildings (name) VALUES ('B1')")
+	f.peopleID = insertRow(t, db, "INSERT INTO people (name, phone, type_id, building_id, email) VALUES ('Tenant', '1', 1, ?, '')", f.buildingID)
+	f.bankID = insertRow(t, db, "INSERT INTO accounts (account_number, account_name, account_type, building_id, isDefault) VALUES (1010, 'Bank', 1, ?, 0)", f.buildingID)
+	f.arID = insertRow(t, db, "INSERT INTO accounts (account_number, account_name, account_type, building_id, isDefault) VALUES (1200, 'A/R', 2, ?, 0)", f.buildingID)
+	f.depositsID = insertRow(t, db, "INSERT INTO accounts (account_number, account_name, account_type, building_id, isDefault) VALUES (2300, 'Customer deposits', 7, ?, 0)", f.buildingID)
+	return f
+}
+
+func seedInvoice(t *testing.T, db *sql.DB, f testFixture, number string, amount float64) int {
+	t.Helper()
+	transactionID := insertRow(t, db, "INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id) VALUES ('invoice', '2026-01-01', ?, '', '1', ?, ?)",
+		number, f.buildingID, f.userID)
+	return insertRow(t, db, "INSERT INTO invoices (invoice_no, transaction_id, sales_date, due_date, ar_account_id, people_id, user_id, amount, description, status, building_id) VALUES (?, ?, '2026-01-01', '2026-01-31', ?, ?, ?, ?, '', '1', ?)",
+		number, transactionID, f.arID, f.peopleID, f.userID, amount, f.buildingID)
+}
+
+func newTestService(db *sql.DB) *CustomerPaymentService {
+	return NewCustomerPaymentService(NewCustomerPaymentRepository(db), transactions.NewTransactionRepository(db), splits.NewSplitRepository(db),
+		accounts.NewAccountRepository(db), db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// transactionTypeColumn returns the values the migrated MySQL transactions.type enum allows
+// and the width of the PostgreSQL column
+func transactionTypeColumn(t *testing.T) ([]string, int) {
+	t.Helper()
+	fsys := os.DirFS(filepath.Join("..", "..", "migrations"))
+	enumPattern := regexp.MustCompile("`type` enum\\(([^)]*)\\)")
+	widthPattern := regexp.MustCompile(`"type" (?:TYPE )?varchar\((\d+)\)`)
+
+	var allowed []string
+	width := 0
+	for _, d := range []dialect.Name{dialect.MySQL, dialect.Postgres} {
+		loaded, err := migrations.Load(fsys, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, migration := range loaded {
+			for _, statement := range migrations.SplitStatements(migration.UpSQL) {
+				if !strings.HasPrefix(statement, "CREATE TABLE `transactions`") && !strings.HasPrefix(statement, "ALTER TABLE `transactions`") &&
+					!strings.HasPrefix(statement, `CREATE TABLE "transactions"`) && !strings.HasPrefix(statement, `ALTER TABLE "transactions"`) {
+					continue
+				}
+				if match := enumPattern.FindStringSubmatch(statement); match != nil {
+					allowed = nil
+					for _, value := range strings.Split(match[1], ",") {
+						allowed = append(allowed, strings.Trim(strings.TrimSpace(value), "'"))
+					}
+				}
+				if match := widthPattern.FindStringSubmatch(statement); match != nil {
+					width, _ = strconv.Atoi(match[1])
+				}
+			}
+		}
+	}
+	if len(allowed) == 0 || width == 0 {
+		t.Fatal("transactions.type not found in the MySQL and PostgreSQL migrations")
+	}
+	return allowed, width
+}
+
+// assertTransactionTypesFit fails for any posted transaction type that the MySQL or
+// PostgreSQL schema would reject, which SQLite accepts
+func assertTransactionTypesFit(t *testing.T, db *sql.DB) {
+	t.Helper()
+	allowed, width := transactionTypeColumn(t)
+
+	rows, err := db.Query("SELECT DISTINCT type FROM transactions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var transactionType string
+		if err := rows.Scan(&transactionType); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(allowed, transactionType) {
+			t.Errorf("transaction type %q is not in the MySQL transactions.type enum", transactionType)
+		}
+		if len(transactionType) > width {
+			t.Errorf("transaction type %q is longer than the PostgreSQL transactions.type varchar(%d)", transactionType, width)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCustomerPaymentOnMigratedSchema posts a customer payment and applies its unapplied
+// credit later, then checks every dialect's schema accepts the transactions they posted
+func TestCustomerPaymentOnMigratedSchema(t *testing.T) {
+	db := openMigratedDB(t)
+	f := seed(t, db)
+	seedInvoice(t, db, f, "INV-1", 100)
+	service := newTestService(db)
+
+	payment, validationErrors, err := service.CreateCustomerPayment(f.buildingID, CreateCustomerPaymentRequest{
+		Date: "2026-01-10", PeopleID: f.peopleID, AccountID: f.bankID, ARAccountID: &f.arID, Amount: 150, AutoAllocate: true,
+	}, f.userID)
+	if err != nil || validationErrors != nil {
+		t.Fatalf("failed to create customer payment: %v %v", err, validationErrors)
+	}
+	if payment.Payment.UnappliedAmount != 50 {
+		t.Fatalf("unapplied amount = %.2f, want 50", payment.Payment.UnappliedAmount)
+	}
+
+	seedInvoice(t, db, f, "INV-2", 30)
+	payment, validationErrors, err = service.ApplyCredit(f.buildingID, payment.Payment.ID, ApplyCreditRequest{Date: "2026-02-01", AutoAllocate: true}, f.userID)
+	if err != nil || validationErrors != nil {
+		t.Fatalf("failed to apply credit: %v %v", err, validationErrors)
+	}
+	if payment.Payment.UnappliedAmount != 20 {
+		t.Fatalf("unapplied amount = %.2f, want 20", payment.Payment.UnappliedAmount)
+	}
+
+	assertTransactionTypesFit(t, db)
+}
+
+// TestPrepaymentOnMigratedSchema posts a prepayment and applies it to the customer's next
+// invoice, then checks every dialect's schema accepts the transactions they posted
+func TestPrepaymentOnMigratedSchema(t *testing.T) {
+	db := openMigratedDB(t)
+	f := seed(t, db)
+	service := newTestService(db)
+
+	prepayment, validationErrors, err := service.CreateCustomerPayment(f.buildingID, CreateCustomerPaymentRequest{
+		Date: "2026-01-10", PeopleID: f.peopleID, AccountID: f.bankID, LiabilityAccountID: &f.depositsID, Amount: 80,
+	}, f.userID)
+	if err != nil || validationErrors != nil {
+		t.Fatalf("failed to create prepayment: %v %v", err, validationErrors)
+	}
+
+	invoiceID := seedInvoice(t, db, f, "INV-1", 50)
+	applied, err := service.ApplyPrepayments(f.buildingID, f.peopleID, invoiceID, f.userID)
+	if err != nil {
+		t.Fatalf("failed to apply prepayments: %v", err)
+	}
+	if applied != 50 {
+		t.Fatalf("applied = %.2f, want 50", applied)
+	}
+	response, err := service.GetCustomerPayment(f.buildingID, prepayment.Payment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Payment.UnappliedAmount != 30 {
+		t.Fatalf("unapplied amount = %.2f, want 30", response.Payment.UnappliedAmount)
+	}
+
+	assertTransactionTypesFit(t, db)
+}
@@ -0,0 +1,74 @@
+// Package documents renders invoices, sales receipts, invoice payments, credit memos and
+// customer statements as printable PDFs. Each building configures a template per document
+// type with its logo, address, footer text and number format.
+package documents
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Document types that can be printed
+const (
+	TypeInvoice      = "invoice"
+	TypeSalesReceipt = "sales_receipt"
+	TypePayment      = "payment"
+	TypeCreditMemo   = "credit_memo"
+	TypeStatement    = "statement"
+)
+
+var Types = []string{TypeInvoice, TypeSalesReceipt, TypePayment, TypeCreditMemo, TypeStatement}
+
+var defaultTitles = map[string]string{
+	TypeInvoice:      "Invoice",
+	TypeSalesReceipt: "Sales Receipt",
+	TypePayment:      "Payment Receipt",
+	TypeCreditMemo:   "Credit Memo",
+	TypeStatement:    "Statement",
+}
+
+// Template holds a building's print settings for one document type
+type Template struct {
+	ID            int     `json:"id"` // 0 while the building uses the defaults
+	BuildingID    int     `json:"building_id"`
+	DocumentType  string  `json:"document_type"`
+	Title         string  `json:"title"`
+	BusinessName  string  `json:"business_name"` // Printed above the address; the building name when empty
+	Address       string  `json:"address"`       // One line per line of text
+	FooterText    string  `json:"footer_text"`
+	NumberPrefix  string  `json:"number_prefix"`  // e.g. "INV-"
+	NumberPadding int     `json:"number_padding"` // Numeric document numbers are zero-padded to this width
+	LogoPath      *string `json:"-"`
+	HasLogo       bool    `json:"has_logo"`
+	CreatedAt     *string `json:"created_at"`
+	UpdatedAt     *string `json:"updated_at"`
+}
+
+// DefaultTemplate returns the template used for a document type the building has not configured
+func DefaultTemplate(buildingID int, documentType string) Template {
+	return Template{
+		BuildingID:   buildingID,
+		DocumentType: documentType,
+		Title:        defaultTitles[documentType],
+	}
+}
+
+// IsType reports whether documentType can be printed
+func IsType(documentType string) bool {
+	_, ok := defaultTitles[documentType]
+	return ok
+}
+
+// FormatNumber applies the template's numbering to a document number: numeric numbers are
+// zero-padded and the prefix is added unless the number already carries it
+func (t Template) FormatNumber(number string) string {
+	number = strings.TrimSpace(number)
+	if n, err := strconv.Atoi(number); err == nil && n >= 0 && t.NumberPadding > 0 {
+		number = fmt.Sprintf("%0*d", t.NumberPadding, n)
+	}
+	if t.NumberPrefix != "" && !strings.HasPrefix(number, t.NumberPrefix) {
+		number = t.NumberPrefix + number
+	}
+	return number
+}
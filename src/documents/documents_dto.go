@@ -0,0 +1,34 @@
+package documents
+
+import "strings"
+
+type UpdateTemplateRequest struct {
+	Title         string `json:"title" binding:"required"`
+	BusinessName  string `json:"business_name"`
+	Address       string `json:"address"`
+	FooterText    string `json:"footer_text"`
+	NumberPrefix  string `json:"number_prefix"`
+	NumberPadding int    `json:"number_padding"` // 0 prints numbers as entered
+}
+
+func (r UpdateTemplateRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if strings.TrimSpace(r.Title) == "" {
+		errors["title"] = "Title is required"
+	} else if len(r.Title) > 100 {
+		errors["title"] = "Title must be at most 100 characters"
+	}
+	if len(r.BusinessName) > 255 {
+		errors["business_name"] = "Business name must be at most 255 characters"
+	}
+	if len(r.NumberPrefix) > 20 {
+		errors["number_prefix"] = "Number prefix must be at most 20 characters"
+	}
+	if r.NumberPadding < 0 || r.NumberPadding > 12 {
+		errors["number_padding"] = "Number padding must be between 0 and 12"
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package documents
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/reports"
+)
+
+type DocumentHandler struct {
+	service *DocumentService
+}
+
+func NewDocumentHandler(service *DocumentService) *DocumentHandler {
+	return &DocumentHandler{service: service}
+}
+
+// GET /buildings/:id/document-templates
+func (h *DocumentHandler) GetTemplates(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	templates, err := h.service.ListTemplates(buildingID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GET /buildings/:id/document-templates/:documentType
+func (h *DocumentHandler) GetTemplate(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	template, err := h.service.GetTemplate(buildingID, c.Param("documentType"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// PUT /buildings/:id/document-templates/:documentType
+func (h *DocumentHandler) UpdateTemplate(c *gin.Context) {
+	var req UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	template, validationErr, err := h.service.WithLogger(logging.FromGin(c)).UpdateTemplate(buildingID, c.Param("documentType"), req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// POST /buildings/:id/document-templates/:documentType/logo
+func (h *DocumentHandler) UploadLogo(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("No file provided"))
+		return
+	}
+	src, err := file.Open()
+	if err != nil {
+		apperrors.Respond(c, apperrors.Wrap(apperrors.CodeInternal, "Failed to open file", err))
+		return
+	}
+	defer src.Close()
+
+	// One byte over the limit is enough for the service to reject the file
+	content, err := io.ReadAll(io.LimitReader(src, MaxLogoSize+1))
+	if err != nil {
+		apperrors.Respond(c, apperrors.Wrap(apperrors.CodeInternal, "Failed to read file", err))
+		return
+	}
+
+	template, err := h.service.WithLogger(logging.FromGin(c)).SaveLogo(buildingID, c.Param("documentType"), content)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// GET /buildings/:id/document-templates/:documentType/logo
+func (h *DocumentHandler) DownloadLogo(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	path, err := h.service.LogoFile(buildingID, c.Param("documentType"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.File(path)
+}
+
+// DELETE /buildings/:id/document-templates/:documentType/logo
+func (h *DocumentHandler) DeleteLogo(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	template, err := h.service.WithLogger(logging.FromGin(c)).DeleteLogo(buildingID, c.Param("documentType"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// GET /buildings/:id/invoices/:invoiceId/pdf
+func (h *DocumentHandler) DownloadInvoice(c *gin.Context) {
+	h.download(c, "invoiceId", "Invalid Invoice ID", h.service.InvoicePDF)
+}
+
+// GET /buildings/:id/sales-receipts/:receiptId/pdf
+func (h *DocumentHandler) DownloadSalesReceipt(c *gin.Context) {
+	h.download(c, "receiptId", "Invalid Receipt ID", h.service.SalesReceiptPDF)
+}
+
+// GET /buildings/:id/invoice-payments/:paymentId/pdf
+func (h *DocumentHandler) DownloadPayment(c *gin.Context) {
+	h.download(c, "paymentId", "Invalid Payment ID", h.service.PaymentPDF)
+}
+
+// GET /buildings/:id/credit-memos/:creditMemoId/pdf
+func (h *DocumentHandler) DownloadCreditMemo(c *gin.Context) {
+	h.download(c, "creditMemoId", "Invalid Credit Memo ID", h.service.CreditMemoPDF)
+}
+
+// GET /buildings/:id/reports/customer-statement/pdf
+func (h *DocumentHandler) DownloadStatement(c *gin.Context) {
+	req, err := reports.ParseCustomerStatementRequest(c)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	filename, content, err := h.service.StatementPDF(req)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	sendPDF(c, filename, content)
+}
+
+// GET /buildings/:id/reports/customer-statements/pdf
+func (h *DocumentHandler) DownloadStatements(c *gin.Context) {
+	req, err := reports.ParseCustomerStatementRequest(c)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	filename, content, err := h.service.WithLogger(logging.FromGin(c)).StatementsZip(req)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/zip", content)
+}
+
+// POST /buildings/:id/invoices/:invoiceId/send
+func (h *DocumentHandler) SendInvoice(c *gin.Context) {
+	h.send(c, "invoiceId", "Invalid Invoice ID", h.service.WithLogger(logging.FromGin(c)).SendInvoice)
+}
+
+// POST /buildings/:id/invoice-payments/:paymentId/send
+func (h *DocumentHandler) SendPayment(c *gin.Context) {
+	h.send(c, "paymentId", "Invalid Payment ID", h.service.WithLogger(logging.FromGin(c)).SendPayment)
+}
+
+// POST /buildings/:id/reports/customer-statement/send
+func (h *DocumentHandler) SendStatement(c *gin.Context) {
+	req, ok := bindSendRequest(c)
+	if !ok {
+		return
+	}
+	statementReq, err := reports.ParseCustomerStatementRequest(c)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	response, validationErr, err := h.service.WithLogger(logging.FromGin(c)).SendStatement(statementReq, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// send queues the notifications of a document from the send request in the body
+func (h *DocumentHandler) send(c *gin.Context, param string, invalidMessage string, send func(buildingID int, id int, req notifications.SendRequest) (*notifications.SendResponse, map[string]string, error)) {
+	req, ok := bindSendRequest(c)
+	if !ok {
+		return
+	}
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest(invalidMessage))
+		return
+	}
+
+	response, validationErr, err := send(buildingID, id, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// bindSendRequest reads the optional send request body; an empty body uses the person's
+// preferences
+func bindSendRequest(c *gin.Context) (notifications.SendRequest, bool) {
+	var req notifications.SendRequest
+	if c.Request.ContentLength == 0 {
+		return req, true
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return req, false
+	}
+	return req, true
+}
+
+// download renders a document and sends it as a PDF attachment, or inline with ?inline=true
+// so browsers can print it directly
+func (h *DocumentHandler) download(c *gin.Context, param string, invalidMessage string, render func(buildingID int, id int) (string, []byte, error)) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest(invalidMessage))
+		return
+	}
+
+	filename, content, err := render(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	sendPDF(c, filename, content)
+}
+
+// sendPDF sends a rendered document as an attachment, or inline with ?inline=true
+func sendPDF(c *gin.Context, filename string, content []byte) {
+	disposition := "attachment"
+	if c.Query("inline") == "true" {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, filename))
+	c.Data(http.StatusOK, "application/pdf", content)
+}
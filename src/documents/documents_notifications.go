@@ -0,0 +1,260 @@
+package documents
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/invoices"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/reports"
+)
+
+// SendInvoice notifies the invoice's customer, attaching the invoice PDF to the email
+func (s *DocumentService) SendInvoice(buildingID int, id int, req notifications.SendRequest) (*notifications.SendResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	invoice, balance, err := s.sendableInvoice(buildingID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	number, err := s.formatNumber(buildingID, TypeInvoice, invoice.InvoiceNo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.notify(notifications.NotifyRequest{
+		BuildingID:    buildingID,
+		PeopleID:      *invoice.PeopleID,
+		Category:      notifications.CategoryInvoice,
+		Channels:      req.Channels,
+		ReferenceType: TypeInvoice,
+		ReferenceID:   invoice.ID,
+		Variables: map[string]string{
+			"number":   number,
+			"date":     dateOnly(invoice.SalesDate),
+			"due_date": dateOnly(invoice.DueDate),
+			"amount":   formatAmount(invoice.Amount),
+			"balance":  formatAmount(balance),
+		},
+	}, func() (string, []byte, error) { return s.InvoicePDF(buildingID, id) })
+}
+
+// SendOverdueReminder reminds the customer of an invoice that is past due with a balance left,
+// attaching the invoice PDF to the email
+func (s *DocumentService) SendOverdueReminder(buildingID int, id int, req notifications.SendRequest) (*notifications.SendResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	return s.remind(buildingID, id, req.Channels, nil, time.Now())
+}
+
+// SendReminderTemplate reminds the customer of an overdue invoice with the given template
+// instead of the building's overdue reminder template, counting the days overdue as of now
+func (s *DocumentService) SendReminderTemplate(buildingID int, id int, template notifications.Template, now time.Time) (*notifications.SendResponse, error) {
+	sent, _, err := s.remind(buildingID, id, nil, &template, now)
+	return sent, err
+}
+
+func (s *DocumentService) remind(buildingID int, id int, channels []string, template *notifications.Template, now time.Time) (*notifications.SendResponse, map[string]string, error) {
+	invoice, balance, err := s.sendableInvoice(buildingID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	number, err := s.formatNumber(buildingID, TypeInvoice, invoice.InvoiceNo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if balance < 0.005 {
+		return nil, nil, apperrors.Rulef("Invoice %s has no balance due", number)
+	}
+	daysOverdue := DaysOverdue(invoice.DueDate, now)
+	if daysOverdue <= 0 {
+		return nil, nil, apperrors.Rulef("Invoice %s is not overdue until after %s", number, dateOnly(invoice.DueDate))
+	}
+
+	return s.notify(notifications.NotifyRequest{
+		BuildingID:    buildingID,
+		PeopleID:      *invoice.PeopleID,
+		Category:      notifications.CategoryOverdueReminder,
+		Channels:      channels,
+		ReferenceType: TypeInvoice,
+		ReferenceID:   invoice.ID,
+		Variables: map[string]string{
+			"number":       number,
+			"due_date":     dateOnly(invoice.DueDate),
+			"days_overdue": strconv.Itoa(daysOverdue),
+			"balance":      formatAmount(balance),
+		},
+		Template: template,
+	}, func() (string, []byte, error) { return s.InvoicePDF(buildingID, id) })
+}
+
+// SendPayment sends the customer a receipt for an invoice payment, attaching the receipt PDF
+// to the email
+func (s *DocumentService) SendPayment(buildingID int, id int, req notifications.SendRequest) (*notifications.SendResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	payment, err := s.paymentRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, apperrors.Lookup("invoice payment", err)
+	}
+	invoice, err := s.invoiceRepo.GetByID(payment.InvoiceID)
+	if err != nil {
+		return nil, nil, apperrors.Lookup("invoice", err)
+	}
+	if invoice.BuildingID != buildingID {
+		return nil, nil, apperrors.NotFound("invoice payment")
+	}
+	if payment.Status == 0 {
+		return nil, nil, apperrors.Rule("Voided payments cannot be sent")
+	}
+	if invoice.PeopleID == nil {
+		return nil, nil, apperrors.Rule("The payment's invoice has no customer to send it to")
+	}
+
+	number := payment.Reference
+	if strings.TrimSpace(number) == "" {
+		number = strconv.Itoa(payment.ID)
+	}
+	number, err = s.formatNumber(buildingID, TypePayment, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	invoiceNumber, err := s.formatNumber(buildingID, TypeInvoice, invoice.InvoiceNo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.notify(notifications.NotifyRequest{
+		BuildingID:    buildingID,
+		PeopleID:      *invoice.PeopleID,
+		Category:      notifications.CategoryPaymentReceipt,
+		Channels:      req.Channels,
+		ReferenceType: TypePayment,
+		ReferenceID:   payment.ID,
+		Variables: map[string]string{
+			"number":         number,
+			"date":           dateOnly(payment.Date),
+			"amount":         formatAmount(payment.Amount),
+			"invoice_number": invoiceNumber,
+		},
+	}, func() (string, []byte, error) { return s.PaymentPDF(buildingID, id) })
+}
+
+// SendStatement sends a customer their statement of account, attaching the statement PDF to
+// the email
+func (s *DocumentService) SendStatement(statementReq reports.CustomerStatementRequest, req notifications.SendRequest) (*notifications.SendResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	statement, err := s.reportsService.GetCustomerStatement(statementReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.notify(notifications.NotifyRequest{
+		BuildingID:    statementReq.BuildingID,
+		PeopleID:      statement.PeopleID,
+		Category:      notifications.CategoryStatement,
+		Channels:      req.Channels,
+		ReferenceType: TypeStatement,
+		ReferenceID:   statement.PeopleID,
+		Variables: map[string]string{
+			"start_date": statement.StartDate,
+			"end_date":   statement.EndDate,
+			"balance":    formatAmount(statement.ClosingBalance),
+		},
+	}, func() (string, []byte, error) {
+		doc, err := s.statementPrintable(statementReq.BuildingID, statement)
+		if err != nil {
+			return "", nil, err
+		}
+		return s.renderNamed(doc, statementFileName(statement))
+	})
+}
+
+// DaysOverdue returns how many days past its due date an invoice is on the given day; zero or
+// less when it is not yet overdue
+func DaysOverdue(dueDate string, now time.Time) int {
+	due, err := time.Parse("2006-01-02", dateOnly(dueDate))
+	if err != nil {
+		return 0
+	}
+	today, _ := time.Parse("2006-01-02", now.Format("2006-01-02"))
+	return int(math.Round(today.Sub(due).Hours() / 24))
+}
+
+// sendableInvoice loads an active invoice of the building that has a customer, with its
+// balance due
+func (s *DocumentService) sendableInvoice(buildingID int, id int) (invoices.Invoice, float64, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return invoices.Invoice{}, 0, apperrors.Lookup("invoice", err)
+	}
+	if invoice.BuildingID != buildingID {
+		return invoices.Invoice{}, 0, apperrors.NotFound("invoice")
+	}
+	if invoice.Status == 0 {
+		return invoices.Invoice{}, 0, apperrors.Rule("Voided invoices cannot be sent")
+	}
+	if invoice.PeopleID == nil {
+		return invoices.Invoice{}, 0, apperrors.Rule("The invoice has no customer to send it to")
+	}
+	paid, credited, discounts, err := s.invoiceSettlements(id)
+	if err != nil {
+		return invoices.Invoice{}, 0, err
+	}
+	return invoice, invoice.Amount - paid - credited - discounts, nil
+}
+
+// notify queues the notification, rendering the attachment only when an email will be sent
+func (s *DocumentService) notify(req notifications.NotifyRequest, attachment func() (string, []byte, error)) (*notifications.SendResponse, map[string]string, error) {
+	if wantsEmail(req.Channels) && (req.Template == nil || req.Template.Channel == notifications.ChannelEmail) {
+		name, content, err := attachment()
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Attachment = &notifications.Attachment{Name: name, ContentType: "application/pdf", Content: content}
+	}
+
+	sent, err := s.notifier.WithLogger(s.logger).Notify(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &notifications.SendResponse{Notifications: sent}, nil, nil
+}
+
+func (s *DocumentService) formatNumber(buildingID int, documentType string, number string) (string, error) {
+	template, err := s.GetTemplate(buildingID, documentType)
+	if err != nil {
+		return "", err
+	}
+	return template.FormatNumber(number), nil
+}
+
+// wantsEmail reports whether a send on the given channels may go by email; no channels means
+// the person's preferences decide
+func wantsEmail(channels []string) bool {
+	if len(channels) == 0 {
+		return true
+	}
+	for _, channel := range channels {
+		if channel == notifications.ChannelEmail {
+			return true
+		}
+	}
+	return false
+}
+
+// dateOnly strips a time of day the driver may have added to a date column
+func dateOnly(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package documents
+
+import (
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/reports"
+)
+
+var inlineParam = openapi.Param{Name: "inline", Type: "boolean", Description: "Send the PDF for display in the browser instead of as an attachment"}
+
+var OpenAPI = openapi.Handlers{
+	"DocumentHandler.GetTemplates": {
+		Summary:     "List the building's document templates",
+		Description: "Returns one template per document type: invoice, sales_receipt, payment, credit_memo and statement. Types that were never configured are returned with the defaults and id 0.",
+		Response:    []Template{},
+	},
+	"DocumentHandler.GetTemplate": {Summary: "Get the building's template of a document type", Response: Template{}},
+	"DocumentHandler.UpdateTemplate": {
+		Summary:     "Configure the building's template of a document type",
+		Description: "Numeric document numbers are printed zero-padded to number_padding digits, after number_prefix.",
+		Request:     UpdateTemplateRequest{},
+		Response:    Template{},
+	},
+	"DocumentHandler.UploadLogo":   {Summary: "Upload the logo printed on a document type", Description: "PNG, JPEG or GIF, at most 1 MB.", Upload: "file", Response: Template{}},
+	"DocumentHandler.DownloadLogo": {Summary: "Download the logo of a document type", Download: true},
+	"DocumentHandler.DeleteLogo":   {Summary: "Remove the logo of a document type", Response: Template{}},
+	"DocumentHandler.DownloadInvoice": {
+		Summary:     "Download an invoice as PDF",
+		Description: "Includes meter readings of the items and the balance due after payments, credits and discounts.",
+		Download:    true,
+		Query:       []openapi.Param{inlineParam},
+	},
+	"DocumentHandler.DownloadSalesReceipt": {Summary: "Download a sales receipt as PDF", Download: true, Query: []openapi.Param{inlineParam}},
+	"DocumentHandler.DownloadPayment":      {Summary: "Download a receipt for an invoice payment as PDF", Download: true, Query: []openapi.Param{inlineParam}},
+	"DocumentHandler.DownloadCreditMemo": {
+		Summary:     "Download a credit memo as PDF",
+		Description: "Includes the credit still available to apply to invoices.",
+		Download:    true,
+		Query:       []openapi.Param{inlineParam},
+	},
+	"DocumentHandler.DownloadStatement": {
+		Summary:  "Download a customer statement as PDF",
+		Download: true,
+		Query:    append([]openapi.Param{{Name: "people_id", Type: "integer", Format: "int32", Required: true}, inlineParam}, reports.StatementQuery...),
+	},
+	"DocumentHandler.DownloadStatements": {
+		Summary:     "Download the statements of all customers",
+		Description: "A zip archive with one PDF per customer with an opening balance, activity in the range or a closing balance.",
+		Download:    true,
+		Query:       reports.StatementQuery,
+	},
+	"DocumentHandler.SendInvoice": {
+		Summary:     "Send an invoice to its customer",
+		Description: "Queues an email with the invoice PDF attached and/or an SMS. Without channels in the body, the customer's notification preferences decide.",
+		Request:     notifications.SendRequest{},
+		Response:    notifications.SendResponse{},
+	},
+	"DocumentHandler.SendPayment": {
+		Summary:  "Send a payment receipt to the invoice's customer",
+		Request:  notifications.SendRequest{},
+		Response: notifications.SendResponse{},
+	},
+	"DocumentHandler.SendStatement": {
+		Summary:  "Send a customer their statement of account",
+		Request:  notifications.SendRequest{},
+		Response: notifications.SendResponse{},
+		Query:    append([]openapi.Param{{Name: "people_id", Type: "integer", Format: "int32", Required: true}}, reports.StatementQuery...),
+	},
+}
@@ -0,0 +1,271 @@
+package documents
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfMargin     = 15.0
+	pdfLineHeight = 6.0
+	// The logo is scaled to fit this box, keeping its proportions
+	logoMaxWidth  = 45.0
+	logoMaxHeight = 22.0
+)
+
+// Field is a labelled value, e.g. a date in the header or a line of the totals
+type Field struct {
+	Label string
+	Value string
+}
+
+// Column describes a column of the line table. Width is relative to the other columns.
+type Column struct {
+	Title string
+	Width float64
+	Align string // L or R
+}
+
+// Printable is a document ready to be rendered with its building's template
+type Printable struct {
+	Template     Template
+	LogoFile     string // Absolute path of the logo, empty for none
+	BusinessName string
+	Number       string // Already formatted with the template's numbering; not printed when empty
+	Void         bool
+	Fields       []Field
+	PartyLabel   string // e.g. "Bill to"
+	Party        []string
+	Columns      []Column
+	Rows         [][]string
+	Totals       []Field // The last one is printed in bold
+	Notes        string
+	// An optional table printed under the totals, e.g. the aging of a statement
+	SummaryTitle   string
+	SummaryColumns []Column
+	SummaryRows    [][]string
+}
+
+// WritePDF renders the document on A4 pages: the building's logo, name and address, the
+// title, number and dates, the customer, the line table, the totals and the footer text
+func WritePDF(w io.Writer, doc Printable) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, 25)
+	pdf.AliasNbPages("{nb}")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	footerLines := splitLines(doc.Template.FooterText)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-10 - float64(len(footerLines))*4)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(90, 90, 90)
+		for _, line := range footerLines {
+			pdf.CellFormat(0, 4, tr(line), "", 1, "C", false, 0, "")
+		}
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	pdf.AddPage()
+	pageWidth, _ := pdf.GetPageSize()
+	usableWidth := pageWidth - 2*pdfMargin
+	halfWidth := usableWidth / 2
+
+	// Left column: logo, business name and address
+	y := pdfMargin
+	if doc.LogoFile != "" {
+		if _, err := os.Stat(doc.LogoFile); err == nil {
+			info := pdf.RegisterImageOptions(doc.LogoFile, fpdf.ImageOptions{ReadDpi: true})
+			if info != nil {
+				width, height := info.Extent()
+				scale := min(logoMaxWidth/width, logoMaxHeight/height)
+				pdf.ImageOptions(doc.LogoFile, pdfMargin, y, width*scale, height*scale, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+				y += height*scale + 3
+			}
+		}
+	}
+	pdf.SetXY(pdfMargin, y)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(halfWidth, pdfLineHeight, tr(doc.BusinessName), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range splitLines(doc.Template.Address) {
+		pdf.CellFormat(halfWidth, 4.5, tr(line), "", 2, "L", false, 0, "")
+	}
+	leftBottom := pdf.GetY()
+
+	// Right column: title, number and dates
+	pdf.SetXY(pdfMargin+halfWidth, pdfMargin)
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(halfWidth, 10, tr(strings.ToUpper(doc.Template.Title)), "", 2, "R", false, 0, "")
+	if doc.Void {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.SetTextColor(200, 0, 0)
+		pdf.CellFormat(halfWidth, 7, "VOID", "", 2, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+	fields := doc.Fields
+	if doc.Number != "" {
+		fields = append([]Field{{Label: "No.", Value: doc.Number}}, fields...)
+	}
+	for _, field := range fields {
+		pdf.SetX(pdfMargin + halfWidth)
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(halfWidth*0.55, 5, tr(field.Label), "", 0, "R", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(halfWidth*0.45, 5, tr(field.Value), "", 1, "R", false, 0, "")
+	}
+	pdf.SetY(max(leftBottom, pdf.GetY()) + 6)
+
+	// Customer
+	if len(doc.Party) > 0 {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(0, 5, tr(strings.ToUpper(doc.PartyLabel)), "", 1, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		for i, line := range doc.Party {
+			style := ""
+			if i == 0 {
+				style = "B"
+			}
+			pdf.SetFont("Helvetica", style, 10)
+			pdf.CellFormat(0, 5, tr(line), "", 1, "L", false, 0, "")
+		}
+		pdf.Ln(5)
+	}
+
+	// Line table
+	writeTable := func(columns []Column, rows [][]string) {
+		totalWeight := 0.0
+		for _, column := range columns {
+			totalWeight += column.Width
+		}
+		widths := make([]float64, len(columns))
+		for i, column := range columns {
+			widths[i] = usableWidth * column.Width / totalWeight
+		}
+
+		writeHeader := func() {
+			pdf.SetFont("Helvetica", "B", 9)
+			pdf.SetFillColor(235, 235, 235)
+			for i, column := range columns {
+				pdf.CellFormat(widths[i], 7, tr(column.Title), "TB", 0, column.Align, true, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+		writeHeader()
+
+		pdf.SetFont("Helvetica", "", 9)
+		_, pageHeight := pdf.GetPageSize()
+		for _, row := range rows {
+			// Repeat the column header when a row starts a new page
+			if pdf.GetY()+pdfLineHeight > pageHeight-25 {
+				pdf.AddPage()
+				writeHeader()
+				pdf.SetFont("Helvetica", "", 9)
+			}
+			for i, column := range columns {
+				value := ""
+				if i < len(row) {
+					value = row[i]
+				}
+				pdf.CellFormat(widths[i], pdfLineHeight, tr(truncateForWidth(pdf, value, widths[i])), "B", 0, column.Align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+		pdf.Ln(3)
+	}
+	if len(doc.Columns) > 0 {
+		writeTable(doc.Columns, doc.Rows)
+	}
+
+	// Totals, right-aligned under the table
+	for i, total := range doc.Totals {
+		style := ""
+		border := ""
+		if i == len(doc.Totals)-1 {
+			style = "B"
+			border = "T"
+		}
+		pdf.SetX(pdfMargin + halfWidth)
+		pdf.SetFont("Helvetica", style, 10)
+		pdf.CellFormat(halfWidth*0.6, pdfLineHeight, tr(total.Label), border, 0, "R", false, 0, "")
+		pdf.CellFormat(halfWidth*0.4, pdfLineHeight, tr(total.Value), border, 1, "R", false, 0, "")
+	}
+
+	if len(doc.SummaryColumns) > 0 {
+		pdf.Ln(6)
+		if doc.SummaryTitle != "" {
+			pdf.SetFont("Helvetica", "B", 9)
+			pdf.CellFormat(0, 5, tr(doc.SummaryTitle), "", 1, "L", false, 0, "")
+		}
+		writeTable(doc.SummaryColumns, doc.SummaryRows)
+	}
+
+	if strings.TrimSpace(doc.Notes) != "" {
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(0, 5, "Notes", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(0, 4.5, tr(doc.Notes), "", "L", false)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+// splitLines returns the non-blank lines of a multi-line setting
+func splitLines(text string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	return lines
+}
+
+// truncateForWidth shortens a cell value so it fits into a column
+func truncateForWidth(pdf *fpdf.Fpdf, value string, width float64) string {
+	maxWidth := width - 2
+	if pdf.GetStringWidth(value) <= maxWidth {
+		return value
+	}
+	runes := []rune(value)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// formatAmount prints an amount with two decimals and thousands separators
+func formatAmount(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	whole, fraction, _ := strings.Cut(fmt.Sprintf("%.2f", amount), ".")
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + grouped.String() + "." + fraction
+}
+
+// formatQuantity prints a quantity or meter value without trailing zeros
+func formatQuantity(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.4f", *value), "0"), ".")
+}
@@ -0,0 +1,73 @@
+package documents
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/dialect"
+)
+
+type TemplateRepository interface {
+	Get(buildingID int, documentType string) (Template, error)
+	List(buildingID int) ([]Template, error)
+	Save(template Template) error
+}
+
+type templateRepo struct {
+	db *sql.DB
+}
+
+func NewTemplateRepository(db *sql.DB) TemplateRepository {
+	return &templateRepo{db: db}
+}
+
+const templateColumns = "id, building_id, document_type, title, business_name, address, footer_text, number_prefix, number_padding, logo_path, created_at, updated_at"
+
+func (r *templateRepo) Get(buildingID int, documentType string) (Template, error) {
+	return scanTemplate(r.db.QueryRow("SELECT "+templateColumns+" FROM document_templates WHERE building_id = ? AND document_type = ?", buildingID, documentType))
+}
+
+func (r *templateRepo) List(buildingID int) ([]Template, error) {
+	rows, err := r.db.Query("SELECT "+templateColumns+" FROM document_templates WHERE building_id = ? ORDER BY id", buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []Template{}
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// Save creates or replaces the building's template of the document type
+func (r *templateRepo) Save(template Template) error {
+	query := dialect.Current.Upsert(
+		"document_templates",
+		[]string{"building_id", "document_type", "title", "business_name", "address", "footer_text", "number_prefix", "number_padding", "logo_path", "updated_at"},
+		[]string{"building_id", "document_type"},
+		[]string{"title", "business_name", "address", "footer_text", "number_prefix", "number_padding", "logo_path", "updated_at"},
+	)
+	_, err := r.db.Exec(query,
+		template.BuildingID, template.DocumentType, template.Title, template.BusinessName, template.Address, template.FooterText,
+		template.NumberPrefix, template.NumberPadding, template.LogoPath, time.Now().UTC().Format("2006-01-02 15:04:05"),
+	)
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTemplate(row scanner) (Template, error) {
+	var template Template
+	err := row.Scan(&template.ID, &template.BuildingID, &template.DocumentType, &template.Title, &template.BusinessName, &template.Address,
+		&template.FooterText, &template.NumberPrefix, &template.NumberPadding, &template.LogoPath, &template.CreatedAt, &template.UpdatedAt)
+	template.HasLogo = template.LogoPath != nil
+	return template, err
+}
@@ -0,0 +1,752 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/config"
+	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/building"
+	"github.com/mysecodgit/go_accounting/src/credit_memo"
+	"github.com/mysecodgit/go_accounting/src/invoice_applied_credits"
+	"github.com/mysecodgit/go_accounting/src/invoice_applied_discounts"
+	"github.com/mysecodgit/go_accounting/src/invoice_items"
+	"github.com/mysecodgit/go_accounting/src/invoice_payments"
+	"github.com/mysecodgit/go_accounting/src/invoices"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/people"
+	"github.com/mysecodgit/go_accounting/src/receipt_items"
+	"github.com/mysecodgit/go_accounting/src/reports"
+	"github.com/mysecodgit/go_accounting/src/sales_receipt"
+	"github.com/mysecodgit/go_accounting/src/unit"
+)
+
+// MaxLogoSize is the largest logo accepted, in bytes
+const MaxLogoSize = 1 << 20
+
+// Logo formats the PDF renderer can embed, by detected content type
+var logoExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+type DocumentService struct {
+	templateRepo        TemplateRepository
+	buildingRepo        building.BuildingRepository
+	invoiceRepo         invoices.InvoiceRepository
+	invoiceItemRepo     invoice_items.InvoiceItemRepository
+	receiptRepo         sales_receipt.SalesReceiptRepository
+	receiptItemRepo     receipt_items.ReceiptItemRepository
+	paymentRepo         invoice_payments.InvoicePaymentRepository
+	creditMemoRepo      credit_memo.CreditMemoRepository
+	appliedCreditRepo   invoice_applied_credits.InvoiceAppliedCreditRepository
+	appliedDiscountRepo invoice_applied_discounts.InvoiceAppliedDiscountRepository
+	peopleRepo          people.PersonRepository
+	unitRepo            unit.UnitRepository
+	accountRepo         accounts.AccountRepository
+	reportsService      *reports.ReportsService
+	notifier            *notifications.NotificationService
+	logger              *slog.Logger
+}
+
+func NewDocumentService(
+	templateRepo TemplateRepository,
+	buildingRepo building.BuildingRepository,
+	invoiceRepo invoices.InvoiceRepository,
+	invoiceItemRepo invoice_items.InvoiceItemRepository,
+	receiptRepo sales_receipt.SalesReceiptRepository,
+	receiptItemRepo receipt_items.ReceiptItemRepository,
+	paymentRepo invoice_payments.InvoicePaymentRepository,
+	creditMemoRepo credit_memo.CreditMemoRepository,
+	appliedCreditRepo invoice_applied_credits.InvoiceAppliedCreditRepository,
+	appliedDiscountRepo invoice_applied_discounts.InvoiceAppliedDiscountRepository,
+	peopleRepo people.PersonRepository,
+	unitRepo unit.UnitRepository,
+	accountRepo accounts.AccountRepository,
+	reportsService *reports.ReportsService,
+	notifier *notifications.NotificationService,
+	logger *slog.Logger,
+) *DocumentService {
+	return &DocumentService{
+		templateRepo:        templateRepo,
+		buildingRepo:        buildingRepo,
+		invoiceRepo:         invoiceRepo,
+		invoiceItemRepo:     invoiceItemRepo,
+		receiptRepo:         receiptRepo,
+		receiptItemRepo:     receiptItemRepo,
+		paymentRepo:         paymentRepo,
+		creditMemoRepo:      creditMemoRepo,
+		appliedCreditRepo:   appliedCreditRepo,
+		appliedDiscountRepo: appliedDiscountRepo,
+		peopleRepo:          peopleRepo,
+		unitRepo:            unitRepo,
+		accountRepo:         accountRepo,
+		reportsService:      reportsService,
+		notifier:            notifier,
+		logger:              logger,
+	}
+}
+
+// WithLogger returns a copy of the service that logs to logger, e.g. the request's logger
+func (s *DocumentService) WithLogger(logger *slog.Logger) *DocumentService {
+	copy := *s
+	copy.logger = logger
+	return &copy
+}
+
+// ListTemplates returns the building's template of every document type, defaults included
+func (s *DocumentService) ListTemplates(buildingID int) ([]Template, error) {
+	saved, err := s.templateRepo.List(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document templates: %w", err)
+	}
+	byType := map[string]Template{}
+	for _, template := range saved {
+		byType[template.DocumentType] = template
+	}
+
+	templates := make([]Template, 0, len(Types))
+	for _, documentType := range Types {
+		template, ok := byType[documentType]
+		if !ok {
+			template = DefaultTemplate(buildingID, documentType)
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// GetTemplate returns the building's template of a document type, or the default
+func (s *DocumentService) GetTemplate(buildingID int, documentType string) (Template, error) {
+	if !IsType(documentType) {
+		return Template{}, apperrors.NotFound("document type")
+	}
+	template, err := s.templateRepo.Get(buildingID, documentType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultTemplate(buildingID, documentType), nil
+	}
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to load document template: %w", err)
+	}
+	return template, nil
+}
+
+func (s *DocumentService) UpdateTemplate(buildingID int, documentType string, req UpdateTemplateRequest) (*Template, map[string]string, error) {
+	template, err := s.GetTemplate(buildingID, documentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	template.Title = strings.TrimSpace(req.Title)
+	template.BusinessName = strings.TrimSpace(req.BusinessName)
+	template.Address = strings.TrimSpace(req.Address)
+	template.FooterText = strings.TrimSpace(req.FooterText)
+	template.NumberPrefix = req.NumberPrefix
+	template.NumberPadding = req.NumberPadding
+	if err := s.templateRepo.Save(template); err != nil {
+		return nil, nil, fmt.Errorf("failed to save document template: %w", err)
+	}
+
+	updated, err := s.GetTemplate(buildingID, documentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.logger.Info("document template updated", "document_type", documentType)
+	return &updated, nil, nil
+}
+
+// SaveLogo stores a PNG, JPEG or GIF logo for the template, replacing the previous one
+func (s *DocumentService) SaveLogo(buildingID int, documentType string, content []byte) (Template, error) {
+	template, err := s.GetTemplate(buildingID, documentType)
+	if err != nil {
+		return Template{}, err
+	}
+	if len(content) > MaxLogoSize {
+		return Template{}, apperrors.BadRequestf("The logo must be at most %d KB", MaxLogoSize/1024)
+	}
+	ext, ok := logoExtensions[http.DetectContentType(content)]
+	if !ok {
+		return Template{}, apperrors.BadRequest("The logo must be a PNG, JPEG or GIF image")
+	}
+
+	dir := filepath.Join("documents", fmt.Sprintf("building_%d", buildingID))
+	if err := os.MkdirAll(filepath.Join(config.UploadRoot(), dir), 0755); err != nil {
+		return Template{}, apperrors.Wrap(apperrors.CodeInternal, "Failed to create upload directory", err)
+	}
+	relative := filepath.Join(dir, fmt.Sprintf("%s_logo_%d%s", documentType, time.Now().UnixNano(), ext))
+	if err := os.WriteFile(filepath.Join(config.UploadRoot(), relative), content, 0644); err != nil {
+		return Template{}, apperrors.Wrap(apperrors.CodeInternal, "Failed to save logo", err)
+	}
+
+	previous := template.LogoPath
+	template.LogoPath = &relative
+	if err := s.templateRepo.Save(template); err != nil {
+		os.Remove(filepath.Join(config.UploadRoot(), relative))
+		return Template{}, fmt.Errorf("failed to save document template: %w", err)
+	}
+	s.removeLogoFile(previous)
+
+	s.logger.Info("document template logo uploaded", "document_type", documentType, "size", len(content))
+	return s.GetTemplate(buildingID, documentType)
+}
+
+func (s *DocumentService) DeleteLogo(buildingID int, documentType string) (Template, error) {
+	template, err := s.GetTemplate(buildingID, documentType)
+	if err != nil {
+		return Template{}, err
+	}
+	if template.LogoPath == nil {
+		return template, nil
+	}
+
+	previous := template.LogoPath
+	template.LogoPath = nil
+	if err := s.templateRepo.Save(template); err != nil {
+		return Template{}, fmt.Errorf("failed to save document template: %w", err)
+	}
+	s.removeLogoFile(previous)
+
+	s.logger.Info("document template logo removed", "document_type", documentType)
+	return s.GetTemplate(buildingID, documentType)
+}
+
+// LogoFile returns the path of the template's logo on disk
+func (s *DocumentService) LogoFile(buildingID int, documentType string) (string, error) {
+	template, err := s.GetTemplate(buildingID, documentType)
+	if err != nil {
+		return "", err
+	}
+	path := logoFile(template)
+	if path == "" {
+		return "", apperrors.NotFound("logo")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", apperrors.New(apperrors.CodeNotFound, "Logo not found on disk")
+	}
+	return path, nil
+}
+
+// InvoicePDF renders an invoice with its active items, meter readings and balance due
+func (s *DocumentService) InvoicePDF(buildingID int, id int) (string, []byte, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return "", nil, apperrors.Lookup("invoice", err)
+	}
+	if invoice.BuildingID != buildingID {
+		return "", nil, apperrors.NotFound("invoice")
+	}
+	items, err := s.invoiceItemRepo.GetByInvoiceID(id)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load invoice items: %w", err)
+	}
+	paid, credited, discounts, err := s.invoiceSettlements(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	doc, err := s.printable(buildingID, TypeInvoice, invoice.InvoiceNo)
+	if err != nil {
+		return "", nil, err
+	}
+	doc.Void = invoice.Status == 0
+	doc.Fields = []Field{{"Date", invoice.SalesDate}, {"Due date", invoice.DueDate}}
+	doc.PartyLabel = "Bill to"
+	doc.Party = s.party(invoice.PeopleID, invoice.UnitID)
+	doc.Notes = invoice.Description
+
+	active := []invoice_items.InvoiceItem{}
+	hasMeter := false
+	for _, item := range items {
+		if item.Status != "1" {
+			continue
+		}
+		active = append(active, item)
+		if item.PreviousValue != nil || item.CurrentValue != nil {
+			hasMeter = true
+		}
+	}
+	doc.Columns, doc.Rows = lineTable(hasMeter, len(active), func(i int) lineItem {
+		item := active[i]
+		return lineItem{item.ItemName, item.PreviousValue, item.CurrentValue, item.Qty, item.Rate, item.Total}
+	})
+
+	doc.Totals = []Field{{"Total", formatAmount(invoice.Amount)}}
+	if paid > 0 {
+		doc.Totals = append(doc.Totals, Field{"Payments", "-" + formatAmount(paid)})
+	}
+	if credited > 0 {
+		doc.Totals = append(doc.Totals, Field{"Credits applied", "-" + formatAmount(credited)})
+	}
+	if discounts > 0 {
+		doc.Totals = append(doc.Totals, Field{"Discounts", "-" + formatAmount(discounts)})
+	}
+	doc.Totals = append(doc.Totals, Field{"Balance due", formatAmount(invoice.Amount - paid - credited - discounts)})
+
+	return s.render(doc)
+}
+
+// invoiceSettlements returns the active payments, applied credits and applied discounts of an invoice
+func (s *DocumentService) invoiceSettlements(id int) (float64, float64, float64, error) {
+	payments, err := s.paymentRepo.GetByInvoiceID(id)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to load invoice payments: %w", err)
+	}
+	credits, err := s.appliedCreditRepo.GetByInvoiceID(id)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to load applied credits: %w", err)
+	}
+	discounts, err := s.appliedDiscountRepo.GetAppliedAmountByInvoiceID(id)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to load applied discounts: %w", err)
+	}
+
+	paid := 0.0
+	for _, payment := range payments {
+		if payment.Status == 1 {
+			paid += payment.Amount
+		}
+	}
+	credited := 0.0
+	for _, credit := range credits {
+		if credit.Status == "1" {
+			credited += credit.Amount
+		}
+	}
+	return paid, credited, discounts, nil
+}
+
+// SalesReceiptPDF renders a sales receipt with its active items
+func (s *DocumentService) SalesReceiptPDF(buildingID int, id int) (string, []byte, error) {
+	receipt, err := s.receiptRepo.GetByID(id)
+	if err != nil {
+		return "", nil, apperrors.Lookup("sales receipt", err)
+	}
+	if receipt.BuildingID != buildingID {
+		return "", nil, apperrors.NotFound("sales receipt")
+	}
+	items, err := s.receiptItemRepo.GetByReceiptID(id)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load receipt items: %w", err)
+	}
+
+	doc, err := s.printable(buildingID, TypeSalesReceipt, receipt.ReceiptNo)
+	if err != nil {
+		return "", nil, err
+	}
+	doc.Void = receipt.Status == 0
+	doc.Fields = []Field{{"Date", receipt.ReceiptDate}}
+	if account := s.accountName(receipt.AccountID); account != "" {
+		doc.Fields = append(doc.Fields, Field{"Deposited to", account})
+	}
+	doc.PartyLabel = "Received from"
+	doc.Party = s.party(receipt.PeopleID, receipt.UnitID)
+	doc.Notes = receipt.Description
+
+	active := []receipt_items.ReceiptItem{}
+	hasMeter := false
+	for _, item := range items {
+		if item.Status != "1" {
+			continue
+		}
+		active = append(active, item)
+		if item.PreviousValue != nil || item.CurrentValue != nil {
+			hasMeter = true
+		}
+	}
+	doc.Columns, doc.Rows = lineTable(hasMeter, len(active), func(i int) lineItem {
+		item := active[i]
+		return lineItem{item.ItemName, item.PreviousValue, item.CurrentValue, item.Qty, item.Rate, item.Total}
+	})
+	doc.Totals = []Field{{"Total received", formatAmount(receipt.Amount)}}
+
+	return s.render(doc)
+}
+
+// PaymentPDF renders a receipt for a payment against an invoice
+func (s *DocumentService) PaymentPDF(buildingID int, id int) (string, []byte, error) {
+	payment, err := s.paymentRepo.GetByID(id)
+	if err != nil {
+		return "", nil, apperrors.Lookup("invoice payment", err)
+	}
+	invoice, err := s.invoiceRepo.GetByID(payment.InvoiceID)
+	if err != nil {
+		return "", nil, apperrors.Lookup("invoice", err)
+	}
+	if invoice.BuildingID != buildingID {
+		return "", nil, apperrors.NotFound("invoice payment")
+	}
+	invoiceTemplate, err := s.GetTemplate(buildingID, TypeInvoice)
+	if err != nil {
+		return "", nil, err
+	}
+
+	number := payment.Reference
+	if strings.TrimSpace(number) == "" {
+		number = strconv.Itoa(payment.ID)
+	}
+	doc, err := s.printable(buildingID, TypePayment, number)
+	if err != nil {
+		return "", nil, err
+	}
+	doc.Void = payment.Status == 0
+	doc.Fields = []Field{{"Date", payment.Date}}
+	if account := s.accountName(payment.AccountID); account != "" {
+		doc.Fields = append(doc.Fields, Field{"Deposited to", account})
+	}
+	doc.PartyLabel = "Received from"
+	doc.Party = s.party(invoice.PeopleID, invoice.UnitID)
+
+	doc.Columns = []Column{{"Invoice", 3, "L"}, {"Invoice date", 2, "L"}, {"Due date", 2, "L"}, {"Invoice total", 2, "R"}, {"Payment", 2, "R"}}
+	doc.Rows = [][]string{{
+		invoiceTemplate.FormatNumber(invoice.InvoiceNo), invoice.SalesDate, invoice.DueDate,
+		formatAmount(invoice.Amount), formatAmount(payment.Amount),
+	}}
+	doc.Totals = []Field{{"Amount received", formatAmount(payment.Amount)}}
+
+	return s.render(doc)
+}
+
+// CreditMemoPDF renders a credit memo with the amount still available to apply
+func (s *DocumentService) CreditMemoPDF(buildingID int, id int) (string, []byte, error) {
+	memo, err := s.creditMemoRepo.GetByID(id)
+	if err != nil {
+		return "", nil, apperrors.Lookup("credit memo", err)
+	}
+	if memo.BuildingID != buildingID {
+		return "", nil, apperrors.NotFound("credit memo")
+	}
+	applied, err := s.appliedCreditRepo.GetAppliedAmountByCreditMemoID(id)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load applied credits: %w", err)
+	}
+
+	number := memo.Reference
+	if strings.TrimSpace(number) == "" {
+		number = strconv.Itoa(memo.ID)
+	}
+	doc, err := s.printable(buildingID, TypeCreditMemo, number)
+	if err != nil {
+		return "", nil, err
+	}
+	doc.Void = memo.Status == "0"
+	doc.Fields = []Field{{"Date", memo.Date}}
+	doc.PartyLabel = "Credit to"
+	doc.Party = s.party(&memo.PeopleID, &memo.UnitID)
+
+	doc.Columns = []Column{{"Description", 8, "L"}, {"Amount", 2, "R"}}
+	doc.Rows = [][]string{{memo.Description, formatAmount(memo.Amount)}}
+	doc.Totals = []Field{{"Credit amount", formatAmount(memo.Amount)}}
+	if applied > 0 {
+		doc.Totals = append(doc.Totals, Field{"Applied to invoices", "-" + formatAmount(applied)})
+	}
+	doc.Totals = append(doc.Totals, Field{"Remaining credit", formatAmount(memo.Amount - applied)})
+
+	return s.render(doc)
+}
+
+// StatementPDF renders a customer's statement of account with its aging
+func (s *DocumentService) StatementPDF(req reports.CustomerStatementRequest) (string, []byte, error) {
+	statement, err := s.reportsService.GetCustomerStatement(req)
+	if err != nil {
+		return "", nil, err
+	}
+	doc, err := s.statementPrintable(req.BuildingID, statement)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.renderNamed(doc, statementFileName(statement))
+}
+
+// StatementsZip renders the statement of every customer of the building with a balance or
+// activity in the range, one PDF per customer in a zip archive
+func (s *DocumentService) StatementsZip(req reports.CustomerStatementRequest) (string, []byte, error) {
+	statements, err := s.reportsService.GetCustomerStatements(req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for i := range statements.Statements {
+		statement := &statements.Statements[i]
+		doc, err := s.statementPrintable(req.BuildingID, statement)
+		if err != nil {
+			return "", nil, err
+		}
+		name, content, err := s.renderNamed(doc, statementFileName(statement))
+		if err != nil {
+			return "", nil, err
+		}
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to add %s to the archive: %w", name, err)
+		}
+		if _, err := file.Write(content); err != nil {
+			return "", nil, fmt.Errorf("failed to add %s to the archive: %w", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return "", nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+	s.logger.Info("customer statements generated", "building_id", req.BuildingID, "start_date", req.StartDate, "end_date", req.EndDate, "statements", len(statements.Statements))
+
+	return fmt.Sprintf("statements-%s-%s.zip", req.StartDate, req.EndDate), buf.Bytes(), nil
+}
+
+// Statement line types as printed
+var statementLineLabels = map[string]string{
+	reports.StatementLineInvoice:       "Invoice",
+	reports.StatementLinePayment:       "Payment",
+	reports.StatementLineDiscount:      "Discount",
+	reports.StatementLineCreditMemo:    "Credit memo",
+	reports.StatementLineAppliedCredit: "Credit applied",
+	reports.StatementLineSalesReceipt:  "Sales receipt",
+
+	reports.StatementLineCustomerPayment:    "Payment",
+	reports.StatementLineCustomerCreditUsed: "Credit applied",
+	reports.StatementLinePrepayment:         "Prepayment",
+	reports.StatementLinePrepaymentApplied:  "Prepayment applied",
+}
+
+// Document types whose numbering applies to the references of statement lines
+var statementLineDocuments = map[string]string{
+	reports.StatementLineInvoice:       TypeInvoice,
+	reports.StatementLinePayment:       TypePayment,
+	reports.StatementLineCreditMemo:    TypeCreditMemo,
+	reports.StatementLineAppliedCredit: TypeCreditMemo,
+	reports.StatementLineSalesReceipt:  TypeSalesReceipt,
+
+	reports.StatementLineCustomerPayment:    TypePayment,
+	reports.StatementLineCustomerCreditUsed: TypePayment,
+	reports.StatementLinePrepayment:         TypePayment,
+	reports.StatementLinePrepaymentApplied:  TypePayment,
+}
+
+func (s *DocumentService) statementPrintable(buildingID int, statement *reports.CustomerStatement) (Printable, error) {
+	doc, err := s.printable(buildingID, TypeStatement, "")
+	if err != nil {
+		return Printable{}, err
+	}
+	// Statements are not numbered, even when the template has a prefix
+	doc.Number = ""
+	doc.Fields = []Field{{"Date", statement.EndDate}, {"Period", statement.StartDate + " to " + statement.EndDate}}
+	doc.PartyLabel = "Statement for"
+	doc.Party = []string{statement.PeopleName}
+	if statement.Phone != "" {
+		doc.Party = append(doc.Party, statement.Phone)
+	}
+	if statement.UnitName != nil {
+		doc.Party = append(doc.Party, "Unit "+*statement.UnitName)
+	}
+
+	templates := map[string]Template{}
+	reference := func(lineType string, number string) string {
+		documentType, ok := statementLineDocuments[lineType]
+		if !ok || number == "" {
+			return number
+		}
+		template, ok := templates[documentType]
+		if !ok {
+			loaded, err := s.GetTemplate(buildingID, documentType)
+			if err != nil {
+				return number
+			}
+			template = loaded
+			templates[documentType] = template
+		}
+		return template.FormatNumber(number)
+	}
+
+	doc.Columns = []Column{
+		{"Date", 2.2, "L"}, {"Type", 2.4, "L"}, {"Reference", 2.4, "L"}, {"Description", 5, "L"},
+		{"Charges", 2.3, "R"}, {"Payments", 2.3, "R"}, {"Balance", 2.5, "R"},
+	}
+	doc.Rows = [][]string{{statement.StartDate, "", "", "Opening balance", "", "", formatAmount(statement.OpeningBalance)}}
+	for _, line := range statement.Lines {
+		label, ok := statementLineLabels[line.Type]
+		if !ok {
+			label = strings.ReplaceAll(line.Type, "_", " ")
+		}
+		description := line.Description
+		charges, payments := "", ""
+		if line.Charges != 0 {
+			charges = formatAmount(line.Charges)
+		}
+		if line.Payments != 0 {
+			payments = formatAmount(line.Payments)
+		}
+		if line.Type == reports.StatementLineAppliedCredit {
+			description = formatAmount(line.Amount) + " - " + description
+		}
+		doc.Rows = append(doc.Rows, []string{
+			line.Date, label, reference(line.Type, line.Reference), description, charges, payments, formatAmount(line.Balance),
+		})
+	}
+
+	doc.Totals = []Field{
+		{"Opening balance", formatAmount(statement.OpeningBalance)},
+		{"Charges", formatAmount(statement.TotalCharges)},
+		{"Payments and credits", "-" + formatAmount(statement.TotalPayments)},
+		{"Balance due", formatAmount(statement.ClosingBalance)},
+	}
+
+	aging := statement.Aging
+	doc.SummaryTitle = "Aging at " + statement.EndDate
+	doc.SummaryColumns = []Column{
+		{"Current", 1, "R"}, {"1-30 days", 1, "R"}, {"31-60 days", 1, "R"}, {"61-90 days", 1, "R"},
+		{"Over 90 days", 1, "R"}, {"Unapplied credit", 1.2, "R"}, {"Total", 1, "R"},
+	}
+	unapplied := formatAmount(0)
+	if aging.UnappliedCredit != 0 {
+		unapplied = "-" + formatAmount(aging.UnappliedCredit)
+	}
+	doc.SummaryRows = [][]string{{
+		formatAmount(aging.Current), formatAmount(aging.Days1To30), formatAmount(aging.Days31To60), formatAmount(aging.Days61To90),
+		formatAmount(aging.Over90), unapplied, formatAmount(aging.Total),
+	}}
+
+	return doc, nil
+}
+
+// statementFileName names a statement after the customer and the end date, e.g.
+// statement-12-John_Smith-2024-06-30.pdf
+func statementFileName(statement *reports.CustomerStatement) string {
+	name := unsafeFilenameChars.ReplaceAllString(statement.PeopleName, "_")
+	if statement.UnitName != nil {
+		name += "-" + unsafeFilenameChars.ReplaceAllString(*statement.UnitName, "_")
+	}
+	return fmt.Sprintf("statement-%d-%s-%s.pdf", statement.PeopleID, name, statement.EndDate)
+}
+
+// printable starts a document with the building's template and header
+func (s *DocumentService) printable(buildingID int, documentType string, number string) (Printable, error) {
+	template, err := s.GetTemplate(buildingID, documentType)
+	if err != nil {
+		return Printable{}, err
+	}
+	businessName := template.BusinessName
+	if businessName == "" {
+		b, err := s.buildingRepo.GetByID(buildingID)
+		if err != nil {
+			return Printable{}, apperrors.Lookup("building", err)
+		}
+		businessName = b.Name
+	}
+	return Printable{
+		Template:     template,
+		LogoFile:     logoFile(template),
+		BusinessName: businessName,
+		Number:       template.FormatNumber(number),
+	}, nil
+}
+
+// render writes the PDF and names the file after the document, e.g. invoice-INV-00042.pdf
+func (s *DocumentService) render(doc Printable) (string, []byte, error) {
+	name := strings.ReplaceAll(doc.Template.DocumentType, "_", "-") + "-" + unsafeFilenameChars.ReplaceAllString(doc.Number, "_") + ".pdf"
+	return s.renderNamed(doc, name)
+}
+
+func (s *DocumentService) renderNamed(doc Printable, name string) (string, []byte, error) {
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, doc); err != nil {
+		return "", nil, fmt.Errorf("failed to render %s: %w", doc.Template.DocumentType, err)
+	}
+	return name, buf.Bytes(), nil
+}
+
+// party returns the customer's name and phone and the unit, skipping what is unknown
+func (s *DocumentService) party(peopleID *int, unitID *int) []string {
+	lines := []string{}
+	if peopleID != nil && *peopleID > 0 {
+		if person, _, _, err := s.peopleRepo.GetByID(*peopleID); err == nil {
+			lines = append(lines, person.Name)
+			if person.Phone != "" {
+				lines = append(lines, person.Phone)
+			}
+		}
+	}
+	if unitID != nil && *unitID > 0 {
+		if u, _, err := s.unitRepo.GetByID(*unitID); err == nil {
+			lines = append(lines, "Unit "+u.Name)
+		}
+	}
+	return lines
+}
+
+func (s *DocumentService) accountName(accountID int) string {
+	account, _, _, err := s.accountRepo.GetByID(accountID)
+	if err != nil {
+		return ""
+	}
+	return account.AccountName
+}
+
+func (s *DocumentService) removeLogoFile(relative *string) {
+	if relative == nil {
+		return
+	}
+	if err := os.Remove(filepath.Join(config.UploadRoot(), *relative)); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("failed to remove replaced logo", "path", *relative, "error", err)
+	}
+}
+
+func logoFile(template Template) string {
+	if template.LogoPath == nil {
+		return ""
+	}
+	return filepath.Join(config.UploadRoot(), *template.LogoPath)
+}
+
+// lineItem is an invoice or receipt item as printed
+type lineItem struct {
+	name     string
+	previous *float64
+	current  *float64
+	qty      *float64
+	rate     *string
+	total    float64
+}
+
+// lineTable builds the item columns and rows; meter columns are only shown when an item
+// has a reading
+func lineTable(hasMeter bool, count int, item func(i int) lineItem) ([]Column, [][]string) {
+	columns := []Column{{"Item", 5, "L"}}
+	if hasMeter {
+		columns = append(columns, Column{"Previous", 2, "R"}, Column{"Current", 2, "R"})
+	}
+	columns = append(columns, Column{"Qty", 1.5, "R"}, Column{"Rate", 2, "R"}, Column{"Amount", 2.5, "R"})
+
+	rows := make([][]string, 0, count)
+	for i := 0; i < count; i++ {
+		line := item(i)
+		// Rates are printed as entered; they may carry more than two decimals
+		rate := ""
+		if line.rate != nil {
+			rate = *line.rate
+		}
+		row := []string{line.name}
+		if hasMeter {
+			row = append(row, formatQuantity(line.previous), formatQuantity(line.current))
+		}
+		rows = append(rows, append(row, formatQuantity(line.qty), rate, formatAmount(line.total)))
+	}
+	return columns, rows
+}
@@ -0,0 +1,103 @@
+// Package dunning reminds customers of their overdue invoices in steps.
+//
+// Each building configures a sequence of steps, e.g. a friendly reminder 3 days after the due
+// date, a firm notice at 15 days and a final notice at 30, each with its own channel and
+// template. A run (by hand or as the dunning_run job) sends every overdue invoice the latest
+// step it has reached and not yet had; an invoice that is already 20 days overdue when the
+// sequence starts gets the 15 day notice only, not the 3 day reminder as well. Every reminder
+// is recorded against its invoice, including reminders sent by hand and steps skipped because
+// the customer could not be reached on the step's channel. Customers can be excluded from
+// dunning with a reason.
+package dunning
+
+import "github.com/mysecodgit/go_accounting/src/notifications"
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Reminder statuses
+const (
+	StatusSent    = "sent"
+	StatusSkipped = "skipped" // The customer has no contact details for, or opted out of, the step's channel
+	StatusDue     = "due"     // Dry runs only; never stored
+)
+
+// ManualStepName is the step name of reminders sent by hand
+const ManualStepName = "Manual reminder"
+
+// Step is one reminder of a building's dunning sequence
+type Step struct {
+	ID          int    `json:"id"`
+	BuildingID  int    `json:"building_id"`
+	Name        string `json:"name"`
+	DaysOverdue int    `json:"days_overdue"` // Sent once the invoice is this many days past due
+	Channel     string `json:"channel"`
+	Subject     string `json:"subject"` // Not used for SMS
+	Body        string `json:"body"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// template returns the step's subject and body as an overdue reminder template
+func (s Step) template() notifications.Template {
+	return notifications.Template{
+		BuildingID: s.BuildingID,
+		Category:   notifications.CategoryOverdueReminder,
+		Channel:    s.Channel,
+		Subject:    s.Subject,
+		Body:       s.Body,
+	}
+}
+
+// Exclusion keeps a customer out of dunning
+type Exclusion struct {
+	ID         int    `json:"id"`
+	BuildingID int    `json:"building_id"`
+	PeopleID   int    `json:"people_id"`
+	PeopleName string `json:"people_name"`
+	Reason     string `json:"reason"`
+	UserID     *int   `json:"user_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// Reminder is one reminder of an overdue invoice
+type Reminder struct {
+	ID              int     `json:"id"`
+	BuildingID      int     `json:"building_id"`
+	InvoiceID       int     `json:"invoice_id"`
+	PeopleID        int     `json:"people_id"`
+	StepID          *int    `json:"step_id"` // Null for reminders sent by hand
+	StepName        string  `json:"step_name"`
+	DaysOverdue     int     `json:"days_overdue"`
+	Balance         float64 `json:"balance"`
+	Status          string  `json:"status"`
+	Note            *string `json:"note"` // Why the step was skipped
+	NotificationIDs []int   `json:"notification_ids"`
+	UserID          *int    `json:"user_id"` // Null for scheduled runs
+	CreatedAt       string  `json:"created_at"`
+}
+
+// OverdueInvoice is an active customer invoice past its due date with a balance left
+type OverdueInvoice struct {
+	InvoiceID  int     `json:"invoice_id"`
+	InvoiceNo  string  `json:"invoice_no"`
+	PeopleID   int     `json:"people_id"`
+	PeopleName string  `json:"people_name"`
+	DueDate    string  `json:"due_date"`
+	Balance    float64 `json:"balance"`
+}
+
+// dueStep returns the latest step the invoice has reached, or nil when there is none or the
+// invoice already had it or a later step. steps are ordered by days overdue.
+func dueStep(steps []Step, daysOverdue int, reminded map[int]bool) *Step {
+	var due *Step
+	for i := len(steps) - 1; i >= 0; i-- {
+		if reminded[steps[i].ID] {
+			return nil
+		}
+		if steps[i].DaysOverdue <= daysOverdue {
+			due = &steps[i]
+			break
+		}
+	}
+	return due
+}
@@ -0,0 +1,112 @@
+package dunning
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/notifications"
+)
+
+type StepRequest struct {
+	Name        string `json:"name" binding:"required"`
+	DaysOverdue int    `json:"days_overdue"`
+	Channel     string `json:"channel" binding:"required"` // email or sms
+	Subject     string `json:"subject"`                    // Required for email, ignored for SMS
+	Body        string `json:"body" binding:"required"`
+}
+
+type CreateExclusionRequest struct {
+	PeopleID int    `json:"people_id" binding:"required"`
+	Reason   string `json:"reason" binding:"required"`
+}
+
+// RunRequest runs the building's sequence as of a day
+type RunRequest struct {
+	Date   string `json:"date"`    // YYYY-MM-DD; defaults to today
+	DryRun bool   `json:"dry_run"` // List the reminders that are due without sending or recording them
+}
+
+// RunResponse lists the reminders of a run
+type RunResponse struct {
+	Date      string     `json:"date"`
+	DryRun    bool       `json:"dry_run"`
+	Sent      int        `json:"sent"`
+	Skipped   int        `json:"skipped"`
+	Excluded  int        `json:"excluded"` // Overdue invoices of excluded customers
+	Failed    int        `json:"failed"`   // Left for the next run
+	Reminders []Reminder `json:"reminders"`
+}
+
+// ReportResponse shows which stage of the sequence every overdue invoice is at
+type ReportResponse struct {
+	Date     string         `json:"date"`
+	Summary  []StageSummary `json:"summary"`
+	Invoices []ReportLine   `json:"invoices"`
+}
+
+// StageSummary totals the overdue invoices at one stage
+type StageSummary struct {
+	Stage    string  `json:"stage"`
+	Invoices int     `json:"invoices"`
+	Balance  float64 `json:"balance"`
+}
+
+type ReportLine struct {
+	OverdueInvoice
+	DaysOverdue    int     `json:"days_overdue"`
+	Stage          string  `json:"stage"` // The last reminder's step, or "Not reminded"
+	StageStatus    *string `json:"stage_status"`
+	StageDate      *string `json:"stage_date"`
+	NextStep       *string `json:"next_step"`
+	NextStepDate   *string `json:"next_step_date"` // Today when the step is already due
+	ExcludedReason *string `json:"excluded_reason"`
+}
+
+// NotRemindedStage is the stage of overdue invoices without reminders
+const NotRemindedStage = "Not reminded"
+
+func (r StepRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if strings.TrimSpace(r.Name) == "" {
+		errors["name"] = "Name is required"
+	} else if len(r.Name) > 100 {
+		errors["name"] = "Name must be at most 100 characters"
+	}
+	if r.DaysOverdue < 1 {
+		errors["days_overdue"] = "Days overdue must be at least 1"
+	} else if r.DaysOverdue > 3650 {
+		errors["days_overdue"] = "Days overdue must be at most 3650"
+	}
+	if !notifications.IsChannel(r.Channel) {
+		errors["channel"] = "Channel must be email or sms"
+	} else {
+		notifications.ValidateTemplate(notifications.CategoryOverdueReminder, r.Channel, r.Subject, r.Body, errors)
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func (r CreateExclusionRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if strings.TrimSpace(r.Reason) == "" {
+		errors["reason"] = "Reason is required"
+	} else if len(r.Reason) > 255 {
+		errors["reason"] = "Reason must be at most 255 characters"
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func (r RunRequest) Validate() map[string]string {
+	if r.Date == "" {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+		return map[string]string{"date": "Date must be in YYYY-MM-DD format"}
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package dunning
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+)
+
+type DunningHandler struct {
+	service *DunningService
+}
+
+func NewDunningHandler(service *DunningService) *DunningHandler {
+	return &DunningHandler{service: service}
+}
+
+// GET /buildings/:id/dunning/steps
+func (h *DunningHandler) GetSteps(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	steps, err := h.service.ListSteps(buildingID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, steps)
+}
+
+// GET /buildings/:id/dunning/steps/:stepId
+func (h *DunningHandler) GetStep(c *gin.Context) {
+	buildingID, id, ok := stepParams(c)
+	if !ok {
+		return
+	}
+
+	step, err := h.service.GetStep(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, step)
+}
+
+// POST /buildings/:id/dunning/steps
+func (h *DunningHandler) CreateStep(c *gin.Context) {
+	var req StepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	step, validationErr, err := h.service.WithLogger(logging.FromGin(c)).CreateStep(buildingID, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, step)
+}
+
+// PUT /buildings/:id/dunning/steps/:stepId
+func (h *DunningHandler) UpdateStep(c *gin.Context) {
+	var req StepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, id, ok := stepParams(c)
+	if !ok {
+		return
+	}
+
+	step, validationErr, err := h.service.WithLogger(logging.FromGin(c)).UpdateStep(buildingID, id, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, step)
+}
+
+// DELETE /buildings/:id/dunning/steps/:stepId
+func (h *DunningHandler) DeleteStep(c *gin.Context) {
+	buildingID, id, ok := stepParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.WithLogger(logging.FromGin(c)).DeleteStep(buildingID, id); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Dunning step deleted successfully"})
+}
+
+// GET /buildings/:id/dunning/exclusions
+func (h *DunningHandler) GetExclusions(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	exclusions, err := h.service.ListExclusions(buildingID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, exclusions)
+}
+
+// POST /buildings/:id/dunning/exclusions
+func (h *DunningHandler) CreateExclusion(c *gin.Context) {
+	var req CreateExclusionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	exclusion, validationErr, err := h.service.WithLogger(logging.FromGin(c)).CreateExclusion(buildingID, req, userID)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, exclusion)
+}
+
+// DELETE /buildings/:id/dunning/exclusions/:personId
+func (h *DunningHandler) DeleteExclusion(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	personID, err := strconv.Atoi(c.Param("personId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Person ID"))
+		return
+	}
+
+	if err := h.service.WithLogger(logging.FromGin(c)).DeleteExclusion(buildingID, personID); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Customer included in dunning again"})
+}
+
+// GET /buildings/:id/invoices/:invoiceId/reminders
+func (h *DunningHandler) GetReminders(c *gin.Context) {
+	buildingID, invoiceID, ok := invoiceParams(c)
+	if !ok {
+		return
+	}
+
+	reminders, err := h.service.ListReminders(buildingID, invoiceID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reminders)
+}
+
+// POST /buildings/:id/invoices/:invoiceId/remind
+func (h *DunningHandler) SendReminder(c *gin.Context) {
+	var req notifications.SendRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperrors.Respond(c, apperrors.FromBinding(err))
+			return
+		}
+	}
+
+	buildingID, invoiceID, ok := invoiceParams(c)
+	if !ok {
+		return
+	}
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	sent, validationErr, err := h.service.WithLogger(logging.FromGin(c)).SendReminder(buildingID, invoiceID, req, userID)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sent)
+}
+
+// POST /buildings/:id/dunning/run
+func (h *DunningHandler) Run(c *gin.Context) {
+	var req RunRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperrors.Respond(c, apperrors.FromBinding(err))
+			return
+		}
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	result, validationErr, err := h.service.WithLogger(logging.FromGin(c)).Run(buildingID, req, &userID)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GET /buildings/:id/dunning/report?date=2024-01-31
+func (h *DunningHandler) GetReport(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	date := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+	report, err := h.service.Report(buildingID, date)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// stepParams reads the building and step ids, responding when either is invalid
+func stepParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("stepId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Step ID"))
+		return 0, 0, false
+	}
+	return buildingID, id, true
+}
+
+// invoiceParams reads the building and invoice ids, responding when either is invalid
+func invoiceParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("invoiceId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Invoice ID"))
+		return 0, 0, false
+	}
+	return buildingID, id, true
+}
+
+// userParam reads the User-ID header, or the user_id query parameter, responding when it is
+// missing or invalid
+func userParam(c *gin.Context) (int, bool) {
+	userIDStr := c.GetHeader("User-ID")
+	if userIDStr == "" {
+		userIDStr = c.Query("user_id")
+	}
+	if userIDStr == "" {
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
+		return 0, false
+	}
+
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,60 @@
+package dunning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/jobs"
+)
+
+// RunJobParams are the params of a dunning_run job
+type RunJobParams struct {
+	DryRun bool `json:"dry_run"` // Keep the reminders that are due as the run's output without sending them
+}
+
+// RunJob is a job type that runs the dunning sequence of the job's building, usually daily.
+// Days overdue are counted in the job's timezone.
+func RunJob(service *DunningService) jobs.Type {
+	return jobs.Type{
+		Name:        "dunning_run",
+		Description: "Sends overdue invoices the reminder of the dunning step they have reached",
+		Validate: func(raw json.RawMessage) map[string]string {
+			_, validationErrors := parseRunParams(raw)
+			return validationErrors
+		},
+		Run: func(ctx context.Context, job jobs.Job) (string, error) {
+			params, validationErrors := parseRunParams(job.Params)
+			if validationErrors != nil {
+				return "", fmt.Errorf("invalid params: %v", validationErrors)
+			}
+			loc, err := time.LoadLocation(job.Timezone)
+			if err != nil {
+				return "", err
+			}
+
+			result, _, err := service.Run(job.BuildingID, RunRequest{Date: time.Now().In(loc).Format("2006-01-02"), DryRun: params.DryRun}, nil)
+			if err != nil {
+				return "", err
+			}
+
+			output, err := json.Marshal(result)
+			if err != nil {
+				return "", err
+			}
+			return string(output), nil
+		},
+	}
+}
+
+func parseRunParams(raw json.RawMessage) (RunJobParams, map[string]string) {
+	var params RunJobParams
+	if len(raw) == 0 || string(raw) == "null" {
+		return params, nil
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return params, map[string]string{"dry_run": "Params must be an object with dry_run"}
+	}
+	return params, nil
+}
@@ -0,0 +1,55 @@
+package dunning
+
+import (
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/openapi"
+)
+
+var OpenAPI = openapi.Handlers{
+	"DunningHandler.GetSteps": {Summary: "List the building's dunning sequence", Response: []Step{}},
+	"DunningHandler.GetStep":  {Summary: "Get a dunning step", Response: Step{}},
+	"DunningHandler.CreateStep": {
+		Summary:     "Add a step to the dunning sequence",
+		Description: "Subject and body are overdue reminder templates: Go text/template syntax with the overdue_reminder variables, e.g. {{.days_overdue}}. SMS steps have no subject. Each step of a building needs its own days overdue.",
+		Request:     StepRequest{},
+		Response:    Step{},
+	},
+	"DunningHandler.UpdateStep": {Summary: "Update a dunning step", Request: StepRequest{}, Response: Step{}},
+	"DunningHandler.DeleteStep": {
+		Summary:     "Remove a step from the dunning sequence",
+		Description: "Reminders the step sent stay on their invoices.",
+		Response:    openapi.Message{},
+	},
+	"DunningHandler.GetExclusions": {Summary: "List the customers excluded from dunning", Response: []Exclusion{}},
+	"DunningHandler.CreateExclusion": {
+		Summary:     "Exclude a customer from dunning",
+		Description: "Dunning runs skip the customer's invoices. They can still be reminded by hand.",
+		Request:     CreateExclusionRequest{},
+		Response:    Exclusion{},
+		UserID:      true,
+	},
+	"DunningHandler.DeleteExclusion": {Summary: "Include a customer in dunning again", Response: openapi.Message{}},
+	"DunningHandler.GetReminders":    {Summary: "List the reminders of an invoice", Response: []Reminder{}},
+	"DunningHandler.SendReminder": {
+		Summary:     "Remind the customer of an overdue invoice",
+		Description: "Only invoices past their due date with a balance due can be reminded of. The reminder is recorded against the invoice as a manual reminder.",
+		Request:     notifications.SendRequest{},
+		Response:    notifications.SendResponse{},
+		UserID:      true,
+	},
+	"DunningHandler.Run": {
+		Summary:     "Run the dunning sequence",
+		Description: "Sends each overdue invoice the latest step it has reached and not had yet, with the invoice PDF attached to emails. Customers who cannot be reached on the step's channel are recorded as skipped. Use dry_run to see what would be sent. Schedule the dunning_run job to run it daily.",
+		Request:     RunRequest{},
+		Response:    RunResponse{},
+		UserID:      true,
+	},
+	"DunningHandler.GetReport": {
+		Summary:     "Show which dunning stage each overdue invoice is at",
+		Description: "Lists every invoice overdue on the date with its last reminder and the next step, and totals the invoices per stage.",
+		Response:    ReportResponse{},
+		Query: []openapi.Param{
+			{Name: "date", Format: "date", Description: "Defaults to today"},
+		},
+	},
+}
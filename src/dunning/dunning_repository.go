@@ -0,0 +1,269 @@
+package dunning
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+type DunningRepository interface {
+	ListSteps(buildingID int) ([]Step, error)
+	GetStep(buildingID int, id int) (Step, error)
+	CreateStep(step Step) (Step, error)
+	UpdateStep(step Step) (Step, error)
+	DeleteStep(buildingID int, id int) error
+	StepDaysTaken(buildingID int, daysOverdue int, excludeID int) (bool, error)
+	ListExclusions(buildingID int) ([]Exclusion, error)
+	GetExclusion(buildingID int, peopleID int) (Exclusion, error)
+	CreateExclusion(exclusion Exclusion) (Exclusion, error)
+	DeleteExclusion(buildingID int, peopleID int) error
+	CreateReminder(reminder Reminder) (Reminder, error)
+	ListReminders(buildingID int, invoiceID int) ([]Reminder, error)
+	ListOverdueReminders(buildingID int, today string) ([]Reminder, error)
+	ListOverdue(buildingID int, today string) ([]OverdueInvoice, error)
+	GetOverdue(buildingID int, invoiceID int, today string) (OverdueInvoice, error)
+}
+
+type dunningRepo struct {
+	db *sql.DB
+}
+
+func NewDunningRepository(db *sql.DB) DunningRepository {
+	return &dunningRepo{db: db}
+}
+
+const stepColumns = "id, building_id, name, days_overdue, channel, subject, body, created_at, updated_at"
+
+const exclusionColumns = "e.id, e.building_id, e.people_id, p.name, e.reason, e.user_id, e.created_at"
+
+const reminderColumns = "r.id, r.building_id, r.invoice_id, r.people_id, r.step_id, r.step_name, r.days_overdue, r.balance, r.status, r.note, r.notification_ids, r.user_id, r.created_at"
+
+// overdueQuery selects the building's active customer invoices due before a day, with their
+// balance left; invoices that are paid off are filtered out by the caller
+const overdueQuery = `
+	SELECT i.id, i.invoice_no, i.people_id, p.name, DATE(i.due_date), i.amount
+		- COALESCE((SELECT SUM(ip.amount) FROM invoice_payments ip WHERE ip.invoice_id = i.id AND ip.status = '1'), 0)
+		- COALESCE((SELECT SUM(c.amount) FROM invoice_applied_credits c WHERE c.invoice_id = i.id AND c.status = '1'), 0)
+		- COALESCE((SELECT SUM(d.amount) FROM invoice_applied_discounts d WHERE d.invoice_id = i.id AND d.status = '1'), 0)
+	FROM invoices i
+	INNER JOIN people p ON p.id = i.people_id
+	WHERE i.building_id = ? AND i.status = '1' AND DATE(i.due_date) < ?`
+
+func (r *dunningRepo) ListSteps(buildingID int) ([]Step, error) {
+	rows, err := r.db.Query("SELECT "+stepColumns+" FROM dunning_steps WHERE building_id = ? ORDER BY days_overdue", buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := []Step{}
+	for rows.Next() {
+		step, err := scanStep(rows)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, rows.Err()
+}
+
+func (r *dunningRepo) GetStep(buildingID int, id int) (Step, error) {
+	return scanStep(r.db.QueryRow("SELECT "+stepColumns+" FROM dunning_steps WHERE id = ? AND building_id = ?", id, buildingID))
+}
+
+func (r *dunningRepo) CreateStep(step Step) (Step, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO dunning_steps (building_id, name, days_overdue, channel, subject, body) VALUES (?, ?, ?, ?, ?, ?)",
+		step.BuildingID, step.Name, step.DaysOverdue, step.Channel, step.Subject, step.Body,
+	)
+	if err != nil {
+		return Step{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Step{}, err
+	}
+	return r.GetStep(step.BuildingID, int(id))
+}
+
+func (r *dunningRepo) UpdateStep(step Step) (Step, error) {
+	_, err := r.db.Exec(
+		"UPDATE dunning_steps SET name = ?, days_overdue = ?, channel = ?, subject = ?, body = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND building_id = ?",
+		step.Name, step.DaysOverdue, step.Channel, step.Subject, step.Body, step.ID, step.BuildingID,
+	)
+	if err != nil {
+		return Step{}, err
+	}
+	return r.GetStep(step.BuildingID, step.ID)
+}
+
+func (r *dunningRepo) DeleteStep(buildingID int, id int) error {
+	_, err := r.db.Exec("DELETE FROM dunning_steps WHERE id = ? AND building_id = ?", id, buildingID)
+	return err
+}
+
+func (r *dunningRepo) StepDaysTaken(buildingID int, daysOverdue int, excludeID int) (bool, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM dunning_steps WHERE building_id = ? AND days_overdue = ? AND id != ?", buildingID, daysOverdue, excludeID).Scan(&count)
+	return count > 0, err
+}
+
+func (r *dunningRepo) ListExclusions(buildingID int) ([]Exclusion, error) {
+	rows, err := r.db.Query("SELECT "+exclusionColumns+" FROM dunning_exclusions e INNER JOIN people p ON p.id = e.people_id WHERE e.building_id = ? ORDER BY p.name, e.id", buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exclusions := []Exclusion{}
+	for rows.Next() {
+		exclusion, err := scanExclusion(rows)
+		if err != nil {
+			return nil, err
+		}
+		exclusions = append(exclusions, exclusion)
+	}
+	return exclusions, rows.Err()
+}
+
+func (r *dunningRepo) GetExclusion(buildingID int, peopleID int) (Exclusion, error) {
+	return scanExclusion(r.db.QueryRow("SELECT "+exclusionColumns+" FROM dunning_exclusions e INNER JOIN people p ON p.id = e.people_id WHERE e.people_id = ? AND e.building_id = ?", peopleID, buildingID))
+}
+
+func (r *dunningRepo) CreateExclusion(e Exclusion) (Exclusion, error) {
+	_, err := r.db.Exec(
+		"INSERT INTO dunning_exclusions (building_id, people_id, reason, user_id, created_at) VALUES (?, ?, ?, ?, ?)",
+		e.BuildingID, e.PeopleID, e.Reason, e.UserID, e.CreatedAt,
+	)
+	if err != nil {
+		return Exclusion{}, err
+	}
+	return r.GetExclusion(e.BuildingID, e.PeopleID)
+}
+
+func (r *dunningRepo) DeleteExclusion(buildingID int, peopleID int) error {
+	_, err := r.db.Exec("DELETE FROM dunning_exclusions WHERE people_id = ? AND building_id = ?", peopleID, buildingID)
+	return err
+}
+
+func (r *dunningRepo) CreateReminder(reminder Reminder) (Reminder, error) {
+	ids := make([]string, 0, len(reminder.NotificationIDs))
+	for _, id := range reminder.NotificationIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	result, err := r.db.Exec(
+		"INSERT INTO dunning_reminders (building_id, invoice_id, people_id, step_id, step_name, days_overdue, balance, status, note, notification_ids, user_id, created_at)"+
+			" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		reminder.BuildingID, reminder.InvoiceID, reminder.PeopleID, reminder.StepID, reminder.StepName, reminder.DaysOverdue, reminder.Balance,
+		reminder.Status, reminder.Note, strings.Join(ids, ","), reminder.UserID, reminder.CreatedAt,
+	)
+	if err != nil {
+		return Reminder{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Reminder{}, err
+	}
+	return scanReminder(r.db.QueryRow("SELECT "+reminderColumns+" FROM dunning_reminders r WHERE r.id = ?", id))
+}
+
+func (r *dunningRepo) ListReminders(buildingID int, invoiceID int) ([]Reminder, error) {
+	return r.queryReminders("SELECT "+reminderColumns+" FROM dunning_reminders r WHERE r.building_id = ? AND r.invoice_id = ? ORDER BY r.id", buildingID, invoiceID)
+}
+
+// ListOverdueReminders returns the reminders of the building's invoices that are overdue on
+// today, oldest first
+func (r *dunningRepo) ListOverdueReminders(buildingID int, today string) ([]Reminder, error) {
+	return r.queryReminders(
+		"SELECT "+reminderColumns+" FROM dunning_reminders r INNER JOIN invoices i ON i.id = r.invoice_id"+
+			" WHERE r.building_id = ? AND i.status = '1' AND DATE(i.due_date) < ? ORDER BY r.id",
+		buildingID, today,
+	)
+}
+
+func (r *dunningRepo) queryReminders(query string, args ...interface{}) ([]Reminder, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := []Reminder{}
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+	return reminders, rows.Err()
+}
+
+func (r *dunningRepo) ListOverdue(buildingID int, today string) ([]OverdueInvoice, error) {
+	rows, err := r.db.Query(overdueQuery+" ORDER BY i.due_date, i.id", buildingID, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := []OverdueInvoice{}
+	for rows.Next() {
+		invoice, err := scanOverdue(rows)
+		if err != nil {
+			return nil, err
+		}
+		if invoice.Balance < 0.005 {
+			continue
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, rows.Err()
+}
+
+func (r *dunningRepo) GetOverdue(buildingID int, invoiceID int, today string) (OverdueInvoice, error) {
+	invoice, err := scanOverdue(r.db.QueryRow(overdueQuery+" AND i.id = ?", buildingID, today, invoiceID))
+	if err == nil && invoice.Balance < 0.005 {
+		return OverdueInvoice{}, sql.ErrNoRows
+	}
+	return invoice, err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStep(row scanner) (Step, error) {
+	var step Step
+	err := row.Scan(&step.ID, &step.BuildingID, &step.Name, &step.DaysOverdue, &step.Channel, &step.Subject, &step.Body, &step.CreatedAt, &step.UpdatedAt)
+	return step, err
+}
+
+func scanExclusion(row scanner) (Exclusion, error) {
+	var e Exclusion
+	err := row.Scan(&e.ID, &e.BuildingID, &e.PeopleID, &e.PeopleName, &e.Reason, &e.UserID, &e.CreatedAt)
+	return e, err
+}
+
+func scanReminder(row scanner) (Reminder, error) {
+	var reminder Reminder
+	var notificationIDs string
+	err := row.Scan(&reminder.ID, &reminder.BuildingID, &reminder.InvoiceID, &reminder.PeopleID, &reminder.StepID, &reminder.StepName,
+		&reminder.DaysOverdue, &reminder.Balance, &reminder.Status, &reminder.Note, &notificationIDs, &reminder.UserID, &reminder.CreatedAt)
+	if err != nil {
+		return reminder, err
+	}
+	reminder.NotificationIDs = []int{}
+	for _, value := range strings.Split(notificationIDs, ",") {
+		if id, err := strconv.Atoi(value); err == nil {
+			reminder.NotificationIDs = append(reminder.NotificationIDs, id)
+		}
+	}
+	return reminder, nil
+}
+
+func scanOverdue(row scanner) (OverdueInvoice, error) {
+	var invoice OverdueInvoice
+	err := row.Scan(&invoice.InvoiceID, &invoice.InvoiceNo, &invoice.PeopleID, &invoice.PeopleName, &invoice.DueDate, &invoice.Balance)
+	return invoice, err
+}
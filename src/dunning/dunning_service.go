@@ -0,0 +1,469 @@
+package dunning
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/documents"
+	"github.com/mysecodgit/go_accounting/src/invoices"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/people"
+)
+
+type DunningService struct {
+	repo            DunningRepository
+	peopleRepo      people.PersonRepository
+	invoiceRepo     invoices.InvoiceRepository
+	documentService *documents.DocumentService
+	logger          *slog.Logger
+}
+
+func NewDunningService(repo DunningRepository, peopleRepo people.PersonRepository, invoiceRepo invoices.InvoiceRepository, documentService *documents.DocumentService, logger *slog.Logger) *DunningService {
+	return &DunningService{
+		repo:            repo,
+		peopleRepo:      peopleRepo,
+		invoiceRepo:     invoiceRepo,
+		documentService: documentService,
+		logger:          logger,
+	}
+}
+
+// WithLogger returns a copy of the service that logs to logger, e.g. the request's logger
+func (s *DunningService) WithLogger(logger *slog.Logger) *DunningService {
+	copy := *s
+	copy.logger = logger
+	return &copy
+}
+
+func (s *DunningService) ListSteps(buildingID int) ([]Step, error) {
+	steps, err := s.repo.ListSteps(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dunning steps: %w", err)
+	}
+	return steps, nil
+}
+
+func (s *DunningService) GetStep(buildingID int, id int) (Step, error) {
+	step, err := s.repo.GetStep(buildingID, id)
+	if err != nil {
+		return Step{}, apperrors.Lookup("dunning step", err)
+	}
+	return step, nil
+}
+
+func (s *DunningService) CreateStep(buildingID int, req StepRequest) (*Step, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	if err := s.checkStepDays(buildingID, req.DaysOverdue, 0); err != nil {
+		return nil, nil, err
+	}
+
+	step, err := s.repo.CreateStep(stepFromRequest(Step{BuildingID: buildingID}, req))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create dunning step: %w", err)
+	}
+
+	s.logger.Info("dunning step created", "step_id", step.ID, "days_overdue", step.DaysOverdue, "channel", step.Channel)
+	return &step, nil, nil
+}
+
+func (s *DunningService) UpdateStep(buildingID int, id int, req StepRequest) (*Step, map[string]string, error) {
+	step, err := s.GetStep(buildingID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	if err := s.checkStepDays(buildingID, req.DaysOverdue, id); err != nil {
+		return nil, nil, err
+	}
+
+	step, err = s.repo.UpdateStep(stepFromRequest(step, req))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update dunning step: %w", err)
+	}
+
+	s.logger.Info("dunning step updated", "step_id", step.ID, "days_overdue", step.DaysOverdue, "channel", step.Channel)
+	return &step, nil, nil
+}
+
+// DeleteStep removes a step from the sequence; the reminders it sent keep their step name
+func (s *DunningService) DeleteStep(buildingID int, id int) error {
+	if _, err := s.GetStep(buildingID, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteStep(buildingID, id); err != nil {
+		return fmt.Errorf("failed to delete dunning step: %w", err)
+	}
+
+	s.logger.Info("dunning step deleted", "step_id", id)
+	return nil
+}
+
+func (s *DunningService) ListExclusions(buildingID int) ([]Exclusion, error) {
+	exclusions, err := s.repo.ListExclusions(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dunning exclusions: %w", err)
+	}
+	return exclusions, nil
+}
+
+func (s *DunningService) CreateExclusion(buildingID int, req CreateExclusionRequest, userID int) (*Exclusion, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	person, _, _, err := s.peopleRepo.GetByID(req.PeopleID)
+	if err != nil {
+		return nil, nil, apperrors.Lookup("person", err)
+	}
+	if person.BuildingID != buildingID {
+		return nil, nil, apperrors.NotFound("person")
+	}
+	if _, err := s.repo.GetExclusion(buildingID, req.PeopleID); err == nil {
+		return nil, nil, apperrors.Conflictf("%s is already excluded from dunning", person.Name)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("failed to load dunning exclusion: %w", err)
+	}
+
+	exclusion, err := s.repo.CreateExclusion(Exclusion{
+		BuildingID: buildingID,
+		PeopleID:   req.PeopleID,
+		Reason:     req.Reason,
+		UserID:     &userID,
+		CreatedAt:  time.Now().UTC().Format(timeLayout),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create dunning exclusion: %w", err)
+	}
+
+	s.logger.Info("customer excluded from dunning", "people_id", req.PeopleID)
+	return &exclusion, nil, nil
+}
+
+// DeleteExclusion puts the customer back into dunning
+func (s *DunningService) DeleteExclusion(buildingID int, peopleID int) error {
+	if _, err := s.repo.GetExclusion(buildingID, peopleID); err != nil {
+		return apperrors.Lookup("dunning exclusion", err)
+	}
+	if err := s.repo.DeleteExclusion(buildingID, peopleID); err != nil {
+		return fmt.Errorf("failed to delete dunning exclusion: %w", err)
+	}
+
+	s.logger.Info("customer included in dunning again", "people_id", peopleID)
+	return nil
+}
+
+// ListReminders returns the reminders of an invoice, oldest first
+func (s *DunningService) ListReminders(buildingID int, invoiceID int) ([]Reminder, error) {
+	invoice, err := s.invoiceRepo.GetByID(invoiceID)
+	if err != nil {
+		return nil, apperrors.Lookup("invoice", err)
+	}
+	if invoice.BuildingID != buildingID {
+		return nil, apperrors.NotFound("invoice")
+	}
+
+	reminders, err := s.repo.ListReminders(buildingID, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dunning reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// SendReminder reminds the customer of an overdue invoice by hand and records the reminder
+// against the invoice. Excluded customers can still be reminded this way.
+func (s *DunningService) SendReminder(buildingID int, invoiceID int, req notifications.SendRequest, userID int) (*notifications.SendResponse, map[string]string, error) {
+	sent, validationErrors, err := s.documentService.WithLogger(s.logger).SendOverdueReminder(buildingID, invoiceID, req)
+	if validationErrors != nil || err != nil {
+		return nil, validationErrors, err
+	}
+
+	today := time.Now().Format("2006-01-02")
+	invoice, err := s.repo.GetOverdue(buildingID, invoiceID, today)
+	if err != nil {
+		// The reminder is queued already; failing the request would invite a second one
+		s.logger.Error("failed to record manual reminder", "invoice_id", invoiceID, "error", err.Error())
+		return sent, nil, nil
+	}
+
+	reminder := Reminder{
+		BuildingID:  buildingID,
+		InvoiceID:   invoiceID,
+		PeopleID:    invoice.PeopleID,
+		StepName:    ManualStepName,
+		DaysOverdue: documents.DaysOverdue(invoice.DueDate, time.Now()),
+		Balance:     invoice.Balance,
+		Status:      StatusSent,
+		UserID:      &userID,
+		CreatedAt:   time.Now().UTC().Format(timeLayout),
+	}
+	for _, notification := range sent.Notifications {
+		reminder.NotificationIDs = append(reminder.NotificationIDs, notification.ID)
+	}
+	if _, err := s.repo.CreateReminder(reminder); err != nil {
+		s.logger.Error("failed to record manual reminder", "invoice_id", invoiceID, "error", err.Error())
+	}
+	return sent, nil, nil
+}
+
+// Run sends every overdue invoice of the building the latest step of the sequence it has
+// reached as of the request's date and not had yet. Customers who cannot be reached on the
+// step's channel are recorded as skipped so the step is not tried again; other failures are
+// left for the next run. userID is nil for scheduled runs.
+func (s *DunningService) Run(buildingID int, req RunRequest, userID *int) (*RunResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	date := time.Now()
+	if req.Date != "" {
+		date, _ = time.Parse("2006-01-02", req.Date)
+	}
+	today := date.Format("2006-01-02")
+
+	steps, err := s.ListSteps(buildingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &RunResponse{Date: today, DryRun: req.DryRun, Reminders: []Reminder{}}
+	if len(steps) == 0 {
+		return result, nil, nil
+	}
+
+	overdue, excluded, reminded, err := s.overdue(buildingID, today)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, invoice := range overdue {
+		if _, ok := excluded[invoice.PeopleID]; ok {
+			result.Excluded++
+			continue
+		}
+		daysOverdue := documents.DaysOverdue(invoice.DueDate, date)
+		step := dueStep(steps, daysOverdue, reminded[invoice.InvoiceID])
+		if step == nil {
+			continue
+		}
+
+		reminder := Reminder{
+			BuildingID:      buildingID,
+			InvoiceID:       invoice.InvoiceID,
+			PeopleID:        invoice.PeopleID,
+			StepID:          &step.ID,
+			StepName:        step.Name,
+			DaysOverdue:     daysOverdue,
+			Balance:         invoice.Balance,
+			NotificationIDs: []int{},
+			UserID:          userID,
+		}
+		if req.DryRun {
+			reminder.Status = StatusDue
+			result.Reminders = append(result.Reminders, reminder)
+			continue
+		}
+
+		sent, err := s.documentService.WithLogger(s.logger).SendReminderTemplate(buildingID, invoice.InvoiceID, step.template(), date)
+		switch {
+		case err == nil:
+			reminder.Status = StatusSent
+			for _, notification := range sent.Notifications {
+				reminder.NotificationIDs = append(reminder.NotificationIDs, notification.ID)
+			}
+		case apperrors.CodeOf(err) == apperrors.CodeBusinessRule:
+			note := err.Error()
+			reminder.Status = StatusSkipped
+			reminder.Note = &note
+		default:
+			s.logger.Error("dunning reminder failed", "invoice_id", invoice.InvoiceID, "step_id", step.ID, "error", err.Error())
+			result.Failed++
+			continue
+		}
+
+		reminder.CreatedAt = time.Now().UTC().Format(timeLayout)
+		saved, err := s.repo.CreateReminder(reminder)
+		if err != nil {
+			s.logger.Error("failed to record dunning reminder", "invoice_id", invoice.InvoiceID, "step_id", step.ID, "error", err.Error())
+			result.Failed++
+			continue
+		}
+		if saved.Status == StatusSent {
+			result.Sent++
+		} else {
+			result.Skipped++
+		}
+		result.Reminders = append(result.Reminders, saved)
+	}
+
+	if !req.DryRun {
+		s.logger.Info("dunning run finished", "date", today, "sent", result.Sent, "skipped", result.Skipped, "failed", result.Failed)
+	}
+	return result, nil, nil
+}
+
+// Report shows which stage of the sequence every invoice overdue on date is at
+func (s *DunningService) Report(buildingID int, date string) (*ReportResponse, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, apperrors.BadRequest("Date must be in YYYY-MM-DD format")
+	}
+
+	steps, err := s.ListSteps(buildingID)
+	if err != nil {
+		return nil, err
+	}
+	overdue, excluded, reminded, err := s.overdue(buildingID, date)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.latestReminders(buildingID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReportResponse{Date: date, Summary: []StageSummary{}, Invoices: []ReportLine{}}
+	stages := map[string]int{}
+	for _, invoice := range overdue {
+		line := ReportLine{
+			OverdueInvoice: invoice,
+			DaysOverdue:    documents.DaysOverdue(invoice.DueDate, day),
+			Stage:          NotRemindedStage,
+		}
+		if reminder, ok := latest[invoice.InvoiceID]; ok {
+			line.Stage = reminder.StepName
+			line.StageStatus = &reminder.Status
+			line.StageDate = &reminder.CreatedAt
+		}
+		if exclusion, ok := excluded[invoice.PeopleID]; ok {
+			line.ExcludedReason = &exclusion.Reason
+		} else if step := nextStep(steps, line.DaysOverdue, reminded[invoice.InvoiceID]); step != nil {
+			next := day
+			if step.DaysOverdue > line.DaysOverdue {
+				next = day.AddDate(0, 0, step.DaysOverdue-line.DaysOverdue)
+			}
+			nextDate := next.Format("2006-01-02")
+			line.NextStep = &step.Name
+			line.NextStepDate = &nextDate
+		}
+		report.Invoices = append(report.Invoices, line)
+
+		index, ok := stages[line.Stage]
+		if !ok {
+			index = len(report.Summary)
+			stages[line.Stage] = index
+			report.Summary = append(report.Summary, StageSummary{Stage: line.Stage})
+		}
+		report.Summary[index].Invoices++
+		report.Summary[index].Balance += invoice.Balance
+	}
+
+	// Order the stages as the sequence does, with invoices not reminded yet first and stages of
+	// deleted steps last
+	rank := map[string]int{NotRemindedStage: -1}
+	for i, step := range steps {
+		rank[step.Name] = i
+	}
+	sort.SliceStable(report.Summary, func(i, j int) bool {
+		ri, ok := rank[report.Summary[i].Stage]
+		if !ok {
+			ri = len(steps)
+		}
+		rj, ok := rank[report.Summary[j].Stage]
+		if !ok {
+			rj = len(steps)
+		}
+		return ri < rj
+	})
+	return report, nil
+}
+
+// overdue loads the invoices overdue on today with the building's exclusions by person and the
+// steps each invoice has had
+func (s *DunningService) overdue(buildingID int, today string) ([]OverdueInvoice, map[int]Exclusion, map[int]map[int]bool, error) {
+	overdue, err := s.repo.ListOverdue(buildingID, today)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load overdue invoices: %w", err)
+	}
+	exclusions, err := s.ListExclusions(buildingID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	excluded := make(map[int]Exclusion, len(exclusions))
+	for _, exclusion := range exclusions {
+		excluded[exclusion.PeopleID] = exclusion
+	}
+	reminders, err := s.repo.ListOverdueReminders(buildingID, today)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load dunning reminders: %w", err)
+	}
+	reminded := make(map[int]map[int]bool)
+	for _, reminder := range reminders {
+		if reminder.StepID == nil {
+			continue
+		}
+		if reminded[reminder.InvoiceID] == nil {
+			reminded[reminder.InvoiceID] = make(map[int]bool)
+		}
+		reminded[reminder.InvoiceID][*reminder.StepID] = true
+	}
+	return overdue, excluded, reminded, nil
+}
+
+// latestReminders returns the last step reminder of each invoice overdue on today; reminders
+// sent by hand do not move an invoice along the sequence
+func (s *DunningService) latestReminders(buildingID int, today string) (map[int]Reminder, error) {
+	reminders, err := s.repo.ListOverdueReminders(buildingID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dunning reminders: %w", err)
+	}
+	latest := make(map[int]Reminder, len(reminders))
+	for _, reminder := range reminders {
+		if reminder.StepID != nil {
+			latest[reminder.InvoiceID] = reminder
+		}
+	}
+	return latest, nil
+}
+
+func (s *DunningService) checkStepDays(buildingID int, daysOverdue int, excludeID int) error {
+	taken, err := s.repo.StepDaysTaken(buildingID, daysOverdue, excludeID)
+	if err != nil {
+		return fmt.Errorf("failed to check dunning steps: %w", err)
+	}
+	if taken {
+		return apperrors.Conflictf("The sequence already has a step at %d days overdue", daysOverdue)
+	}
+	return nil
+}
+
+// nextStep returns the step a run would send the invoice next: the step due now, or else the
+// first step still to come. nil when the invoice had the last step.
+func nextStep(steps []Step, daysOverdue int, reminded map[int]bool) *Step {
+	if step := dueStep(steps, daysOverdue, reminded); step != nil {
+		return step
+	}
+	for i := range steps {
+		if steps[i].DaysOverdue > daysOverdue {
+			return &steps[i]
+		}
+	}
+	return nil
+}
+
+func stepFromRequest(step Step, req StepRequest) Step {
+	step.Name = req.Name
+	step.DaysOverdue = req.DaysOverdue
+	step.Channel = req.Channel
+	step.Subject = req.Subject
+	if req.Channel == notifications.ChannelSMS {
+		step.Subject = ""
+	}
+	step.Body = req.Body
+	return step
+}
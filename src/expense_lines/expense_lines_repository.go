@@ -2,7 +2,8 @@ package expense_lines
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type ExpenseLineRepository interface {
@@ -128,7 +129,7 @@ func (r *expenseLineRepo) GetByID(id int) (ExpenseLine, error) {
 		Scan(&expenseLine.ID, &expenseLine.CheckID, &expenseLine.AccountID, &expenseLine.UnitID, &expenseLine.PeopleID, &expenseLine.Description, &expenseLine.Amount)
 
 	if err == sql.ErrNoRows {
-		return expenseLine, fmt.Errorf("expense line not found")
+		return expenseLine, apperrors.NotFound("expense line")
 	}
 
 	return expenseLine, err
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+type RowKind int
+
+const (
+	RowNormal RowKind = iota
+	RowHeader         // Section or group header row
+	RowTotal          // Subtotal or total row
+)
+
+type Row struct {
+	Cells []string
+	Kind  RowKind
+}
+
+// Table is one titled block of rows sharing the same columns
+type Table struct {
+	Title   string
+	Columns []string
+	Rows    []Row
+}
+
+// Document is a report ready to be rendered to a downloadable file
+type Document struct {
+	Title        string
+	BuildingName string
+	Period       string // e.g. "As of 2025-12-31" or "2025-01-01 to 2025-12-31"
+	Tables       []Table
+}
+
+// NegotiateFormat picks the output format from the format query parameter, falling back to the Accept header
+func NegotiateFormat(format string, accept string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "" {
+		switch format {
+		case FormatJSON, FormatCSV, FormatXLSX, FormatPDF:
+			return format, nil
+		}
+		return "", apperrors.BadRequestf("unsupported format '%s': use json, csv, xlsx or pdf", format)
+	}
+
+	accept = strings.ToLower(accept)
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return FormatCSV, nil
+	case strings.Contains(accept, "spreadsheetml"):
+		return FormatXLSX, nil
+	case strings.Contains(accept, "application/pdf"):
+		return FormatPDF, nil
+	}
+
+	return FormatJSON, nil
+}
+
+// ContentType returns the MIME type of a file format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// FileName builds a download file name such as "balance-sheet.pdf"
+func FileName(baseName string, format string) string {
+	return baseName + "." + format
+}
+
+// Render writes the document in the given file format
+func Render(w io.Writer, format string, doc Document) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, doc)
+	case FormatXLSX:
+		return WriteXLSX(w, doc)
+	case FormatPDF:
+		return WritePDF(w, doc)
+	}
+	return apperrors.BadRequestf("unsupported export format '%s'", format)
+}
+
+// Amount formats a money value with 2 decimals
+func Amount(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+// OptionalAmount formats a nullable money value, empty when nil
+func OptionalAmount(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return Amount(*v)
+}
@@ -0,0 +1,38 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the document header lines followed by each table, separated by blank lines
+func WriteCSV(w io.Writer, doc Document) error {
+	writer := csv.NewWriter(w)
+
+	header := [][]string{{doc.Title}, {doc.BuildingName}, {doc.Period}}
+	if err := writer.WriteAll(header); err != nil {
+		return err
+	}
+
+	for _, table := range doc.Tables {
+		if err := writer.Write([]string{}); err != nil {
+			return err
+		}
+		if table.Title != "" {
+			if err := writer.Write([]string{table.Title}); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(table.Columns); err != nil {
+			return err
+		}
+		for _, row := range table.Rows {
+			if err := writer.Write(row.Cells); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,147 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfMargin     = 10.0
+	pdfLineHeight = 6.0
+)
+
+// WritePDF writes the document as a paginated PDF with the report header and page numbers on every page
+// Tables with many columns switch the page to landscape
+func WritePDF(w io.Writer, doc Document) error {
+	orientation := "P"
+	for _, table := range doc.Tables {
+		if len(table.Columns) > 6 {
+			orientation = "L"
+			break
+		}
+	}
+
+	pdf := fpdf.New(orientation, "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("{nb}")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(0, 8, tr(doc.Title), "", 1, "C", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 5, tr(doc.BuildingName), "", 1, "C", false, 0, "")
+		pdf.CellFormat(0, 5, tr(doc.Period), "", 1, "C", false, 0, "")
+		pdf.Ln(4)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pageWidth, _ := pdf.GetPageSize()
+	usableWidth := pageWidth - 2*pdfMargin
+
+	for _, table := range doc.Tables {
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		// First column holds labels and gets a larger share of the width
+		widths := make([]float64, len(table.Columns))
+		if len(table.Columns) == 1 {
+			widths[0] = usableWidth
+		} else {
+			first := usableWidth * 0.3
+			if len(table.Columns) <= 3 {
+				first = usableWidth * 0.5
+			}
+			rest := (usableWidth - first) / float64(len(table.Columns)-1)
+			widths[0] = first
+			for i := 1; i < len(widths); i++ {
+				widths[i] = rest
+			}
+		}
+
+		fontSize := 9.0
+		if len(table.Columns) > 10 {
+			fontSize = 7
+		}
+
+		writeHeader := func() {
+			pdf.SetFont("Helvetica", "B", fontSize)
+			pdf.SetFillColor(230, 230, 230)
+			for i, column := range table.Columns {
+				pdf.CellFormat(widths[i], pdfLineHeight, tr(column), "1", 0, "C", true, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		if table.Title != "" {
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.CellFormat(0, 8, tr(table.Title), "", 1, "L", false, 0, "")
+		}
+		writeHeader()
+
+		for _, row := range table.Rows {
+			// Repeat the column header when a row starts a new page
+			_, pageHeight := pdf.GetPageSize()
+			if pdf.GetY()+pdfLineHeight > pageHeight-15 {
+				pdf.AddPage()
+				writeHeader()
+			}
+
+			style := ""
+			if row.Kind != RowNormal {
+				style = "B"
+			}
+			pdf.SetFont("Helvetica", style, fontSize)
+
+			for i := range table.Columns {
+				value := ""
+				if i < len(row.Cells) {
+					value = row.Cells[i]
+				}
+				align := "L"
+				if _, err := strconv.ParseFloat(value, 64); err == nil && i > 0 {
+					align = "R"
+				}
+				border := "LR"
+				if row.Kind == RowTotal {
+					border = "1"
+				}
+				pdf.CellFormat(widths[i], pdfLineHeight, tr(truncateForWidth(pdf, value, widths[i])), border, 0, align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		// Close the table with a bottom border
+		pdf.CellFormat(usableWidth, 0, "", "T", 1, "", false, 0, "")
+		pdf.Ln(4)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+
+	return pdf.Output(w)
+}
+
+// truncateForWidth shortens a cell value so it fits into a column
+func truncateForWidth(pdf *fpdf.Fpdf, value string, width float64) string {
+	maxWidth := width - 2
+	if pdf.GetStringWidth(value) <= maxWidth {
+		return value
+	}
+	runes := []rune(value)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
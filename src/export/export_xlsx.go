@@ -0,0 +1,122 @@
+package export
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// WriteXLSX writes the document to a single worksheet, with numeric cells stored as numbers
+func WriteXLSX(w io.Writer, doc Document) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Report"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+
+	boldStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	titleStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return err
+	}
+	amountStyle, err := f.NewStyle(&excelize.Style{NumFmt: 4}) // #,##0.00
+	if err != nil {
+		return err
+	}
+	boldAmountStyle, err := f.NewStyle(&excelize.Style{NumFmt: 4, Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	rowIndex := 1
+	writeRow := func(cells []string, bold bool) error {
+		for col, value := range cells {
+			cell, err := excelize.CoordinatesToCellName(col+1, rowIndex)
+			if err != nil {
+				return err
+			}
+
+			// Store amounts as numbers so the spreadsheet can sum them (first column is a label)
+			if number, parseErr := strconv.ParseFloat(value, 64); parseErr == nil && col > 0 {
+				if err := f.SetCellFloat(sheet, cell, number, 2, 64); err != nil {
+					return err
+				}
+				style := amountStyle
+				if bold {
+					style = boldAmountStyle
+				}
+				if err := f.SetCellStyle(sheet, cell, cell, style); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := f.SetCellStr(sheet, cell, value); err != nil {
+				return err
+			}
+			if bold {
+				if err := f.SetCellStyle(sheet, cell, cell, boldStyle); err != nil {
+					return err
+				}
+			}
+		}
+		rowIndex++
+		return nil
+	}
+
+	if err := f.SetCellStr(sheet, "A1", doc.Title); err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A1", "A1", titleStyle); err != nil {
+		return err
+	}
+	rowIndex++
+	if err := writeRow([]string{doc.BuildingName}, false); err != nil {
+		return err
+	}
+	if err := writeRow([]string{doc.Period}, false); err != nil {
+		return err
+	}
+
+	maxColumns := 1
+	for _, table := range doc.Tables {
+		rowIndex++ // blank line between tables
+		if table.Title != "" {
+			if err := writeRow([]string{table.Title}, true); err != nil {
+				return err
+			}
+		}
+		if err := writeRow(table.Columns, true); err != nil {
+			return err
+		}
+		for _, row := range table.Rows {
+			if err := writeRow(row.Cells, row.Kind != RowNormal); err != nil {
+				return err
+			}
+		}
+		if len(table.Columns) > maxColumns {
+			maxColumns = len(table.Columns)
+		}
+	}
+
+	lastColumn, err := excelize.ColumnNumberToName(maxColumns)
+	if err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "A", "A", 40); err != nil {
+		return err
+	}
+	if maxColumns > 1 {
+		if err := f.SetColWidth(sheet, "B", lastColumn, 16); err != nil {
+			return err
+		}
+	}
+
+	return f.Write(w)
+}
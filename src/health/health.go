@@ -0,0 +1,18 @@
+package health
+
+import "time"
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+
+	// A readiness probe gives up on the database after this long
+	CheckTimeout = 2 * time.Second
+)
+
+// Status is the body of /healthz and /readyz. Checks maps each dependency to "ok" or
+// "unavailable"; the reason it failed is logged.
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/logging"
+)
+
+type HealthHandler struct {
+	db *sql.DB
+}
+
+func NewHealthHandler(db *sql.DB) *HealthHandler {
+	return &HealthHandler{db: db}
+}
+
+// Liveness handles GET /healthz. The process is alive as long as it can answer, so a
+// database outage is reported in the checks without failing the probe; restarting the
+// server would not bring the database back.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, Status{Status: StatusOK, Checks: h.checks(c.Request.Context())})
+}
+
+// Readiness handles GET /readyz: 503 while the database is unreachable, so load
+// balancers stop sending requests that could not be served
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	checks := h.checks(c.Request.Context())
+	if checks["database"] != StatusOK {
+		c.JSON(http.StatusServiceUnavailable, Status{Status: StatusUnavailable, Checks: checks})
+		return
+	}
+	c.JSON(http.StatusOK, Status{Status: StatusOK, Checks: checks})
+}
+
+func (h *HealthHandler) checks(ctx context.Context) map[string]string {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+
+	// The probes need no authentication, so the driver's error, which can name the
+	// database host and user, is only logged
+	database := StatusOK
+	if err := h.db.PingContext(ctx); err != nil {
+		logging.FromContext(ctx).Warn("database health check failed", "error", err)
+		database = StatusUnavailable
+	}
+	return map[string]string{"database": database}
+}
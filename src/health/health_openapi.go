@@ -0,0 +1,16 @@
+package health
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"HealthHandler.Liveness": {
+		Summary:     "Liveness probe",
+		Description: "Always 200 while the server is running; the database check is informational.",
+		Response:    Status{},
+	},
+	"HealthHandler.Readiness": {
+		Summary:     "Readiness probe",
+		Description: "503 while the database is unreachable.",
+		Response:    Status{},
+	},
+}
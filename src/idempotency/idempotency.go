@@ -0,0 +1,34 @@
+package idempotency
+
+import "time"
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+
+	MaxKeyLength = 255
+
+	// Stored responses are replayed for this long
+	RetentionPeriod = 24 * time.Hour
+	// A key whose request never finished (e.g. the server stopped) is released after this long
+	LockTimeout = 5 * time.Minute
+)
+
+type Record struct {
+	ID                  int     `json:"id"`
+	UserID              int     `json:"user_id"`
+	Key                 string  `json:"idempotency_key"`
+	Method              string  `json:"method"`
+	Path                string  `json:"path"`
+	RequestHash         string  `json:"request_hash"`
+	Status              string  `json:"status"`
+	ResponseStatus      *int    `json:"response_status"`
+	ResponseContentType *string `json:"response_content_type"`
+	ResponseBody        *string `json:"response_body"`
+	LockedUntil         string  `json:"locked_until"`
+	ExpiresAt           string  `json:"expires_at"`
+	CreatedAt           string  `json:"created_at"`
+}
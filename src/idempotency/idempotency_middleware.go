@@ -0,0 +1,162 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+)
+
+// Middleware makes a POST endpoint safe to retry. When a request carries an Idempotency-Key
+// header, the first response for that key and user is stored and later requests with the
+// same key get it back instead of running the handler again. Reusing a key for a different
+// request is rejected. Requests without the header are not affected.
+func Middleware(repo IdempotencyRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(HeaderKey))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > MaxKeyLength {
+			apperrors.Abort(c, apperrors.BadRequestf("%s must be at most %d characters", HeaderKey, MaxKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apperrors.Abort(c, apperrors.Wrap(apperrors.CodeBadRequest, "Failed to read request body", err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := Record{
+			UserID:      requestUserID(c),
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: requestHash(c.Request, body),
+		}
+
+		reserved, err := repo.Reserve(record, time.Now())
+		if err != nil {
+			apperrors.Abort(c, fmt.Errorf("failed to reserve idempotency key: %w", err))
+			return
+		}
+		stored, err := repo.GetByKey(record.UserID, key)
+		if err != nil {
+			apperrors.Abort(c, fmt.Errorf("failed to load idempotency key: %w", err))
+			return
+		}
+
+		if !reserved {
+			switch {
+			case stored.RequestHash != record.RequestHash:
+				apperrors.Abort(c, apperrors.New(apperrors.CodeIdempotencyMismatch, HeaderKey+" has already been used for a different request"))
+			case stored.Status != StatusCompleted:
+				apperrors.Abort(c, apperrors.New(apperrors.CodeRequestInProgress, "A request with this "+HeaderKey+" is still being processed"))
+			default:
+				replay(c, stored)
+			}
+			return
+		}
+
+		// Free the key if the handler panics so the client can retry
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if err := repo.Release(stored.ID); err != nil {
+					logging.FromGin(c).Error("failed to release idempotency key", "key_id", stored.ID, "error", err.Error())
+				}
+				panic(recovered)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Server errors are not stored; the request may succeed when retried
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := repo.Release(stored.ID); err != nil {
+				logging.FromGin(c).Error("failed to release idempotency key", "key_id", stored.ID, "error", err.Error())
+			}
+			return
+		}
+		if err := repo.Complete(stored.ID, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.String()); err != nil {
+			logging.FromGin(c).Error("failed to store idempotent response", "key_id", stored.ID, "error", err.Error())
+		}
+	}
+}
+
+func replay(c *gin.Context, record Record) {
+	status := http.StatusOK
+	if record.ResponseStatus != nil {
+		status = *record.ResponseStatus
+	}
+	contentType := "application/json; charset=utf-8"
+	if record.ResponseContentType != nil && *record.ResponseContentType != "" {
+		contentType = *record.ResponseContentType
+	}
+	body := ""
+	if record.ResponseBody != nil {
+		body = *record.ResponseBody
+	}
+
+	c.Header(HeaderReplayed, "true")
+	c.Data(status, contentType, []byte(body))
+	c.Abort()
+}
+
+// requestUserID scopes keys the same way handlers identify the caller
+func requestUserID(c *gin.Context) int {
+	userIDStr := c.GetHeader("User-ID")
+	if userIDStr == "" {
+		userIDStr = c.Query("user_id")
+	}
+	userID, _ := strconv.Atoi(userIDStr)
+	return userID
+}
+
+// requestHash fingerprints the method, path, query and body. JSON bodies are hashed in
+// canonical form so key order and whitespace do not count as a different payload.
+func requestHash(r *http.Request, body []byte) string {
+	payload := body
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err == nil {
+		if canonical, err := json.Marshal(decoded); err == nil {
+			payload = canonical
+		}
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	hash.Write(payload)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
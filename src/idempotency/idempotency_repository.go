@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/dialect"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+type IdempotencyRepository interface {
+	Reserve(record Record, now time.Time) (bool, error)
+	GetByKey(userID int, key string) (Record, error)
+	Complete(id int, responseStatus int, contentType string, body string) error
+	Release(id int) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyRepo struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) IdempotencyRepository {
+	return &idempotencyRepo{db: db}
+}
+
+// Reserve claims a key for a new request. It returns false when the key is already held
+// by a stored response or by a request still in flight.
+func (r *idempotencyRepo) Reserve(record Record, now time.Time) (bool, error) {
+	now = now.UTC()
+	nowStr := now.Format(timeLayout)
+
+	// Expired responses and abandoned requests no longer hold their key
+	_, err := r.db.Exec(
+		"DELETE FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ? AND (expires_at < ? OR (status = ? AND locked_until < ?))",
+		record.UserID, record.Key, nowStr, StatusProcessing, nowStr,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	query := dialect.Current.Upsert(
+		"idempotency_keys",
+		[]string{"user_id", "idempotency_key", "method", "path", "request_hash", "status", "locked_until", "expires_at"},
+		[]string{"user_id", "idempotency_key"},
+		nil,
+	)
+	result, err := r.db.Exec(query,
+		record.UserID, record.Key, record.Method, record.Path, record.RequestHash, StatusProcessing,
+		now.Add(LockTimeout).Format(timeLayout), now.Add(RetentionPeriod).Format(timeLayout),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *idempotencyRepo) GetByKey(userID int, key string) (Record, error) {
+	var record Record
+	err := r.db.QueryRow(
+		"SELECT id, user_id, idempotency_key, method, path, request_hash, status, response_status, response_content_type, response_body, locked_until, expires_at, created_at FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?",
+		userID, key,
+	).Scan(&record.ID, &record.UserID, &record.Key, &record.Method, &record.Path, &record.RequestHash, &record.Status,
+		&record.ResponseStatus, &record.ResponseContentType, &record.ResponseBody, &record.LockedUntil, &record.ExpiresAt, &record.CreatedAt)
+	return record, err
+}
+
+func (r *idempotencyRepo) Complete(id int, responseStatus int, contentType string, body string) error {
+	_, err := r.db.Exec(
+		"UPDATE idempotency_keys SET status = ?, response_status = ?, response_content_type = ?, response_body = ? WHERE id = ?",
+		StatusCompleted, responseStatus, contentType, body, id,
+	)
+	return err
+}
+
+// Release frees a key so the request can be retried with it
+func (r *idempotencyRepo) Release(id int) error {
+	_, err := r.db.Exec("DELETE FROM idempotency_keys WHERE id = ?", id)
+	return err
+}
+
+func (r *idempotencyRepo) DeleteExpired(now time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM idempotency_keys WHERE expires_at < ?", now.UTC().Format(timeLayout))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
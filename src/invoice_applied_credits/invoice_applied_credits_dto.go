@@ -19,8 +19,16 @@ type InvoiceAppliedCreditResponse struct {
 	Transaction          transactions.Transaction  `json:"transaction"` // Empty transaction since we don't create transactions
 }
 
+// Sources of available credit
+const (
+	CreditSourceCreditMemo      = "credit_memo"      // Applied through the invoice's applied credits
+	CreditSourceCustomerPayment = "customer_payment" // Unapplied credit of a customer payment, applied through the payment
+	CreditSourcePrepayment      = "prepayment"       // A customer payment held as a prepayment, applied through the payment
+)
+
 type AvailableCreditMemo struct {
-	ID              int     `json:"id"`
+	ID              int     `json:"id"`     // Of the credit memo or customer payment
+	Source          string  `json:"source"` // credit_memo, customer_payment or prepayment
 	Date            string  `json:"date"`
 	Amount          float64 `json:"amount"`
 	AppliedAmount   float64 `json:"applied_amount"`   // Amount already applied to other invoices
@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
 )
 
 type InvoiceAppliedCreditHandler struct {
@@ -22,19 +24,19 @@ func (h *InvoiceAppliedCreditHandler) GetAvailableCredits(c *gin.Context) {
 		invoiceIDStr = c.Param("id")
 	}
 	if invoiceIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Invoice ID is required"))
 		return
 	}
 
 	invoiceID, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid invoice ID"))
 		return
 	}
 
 	response, err := h.service.GetAvailableCreditsForInvoice(invoiceID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -45,7 +47,7 @@ func (h *InvoiceAppliedCreditHandler) GetAvailableCredits(c *gin.Context) {
 func (h *InvoiceAppliedCreditHandler) ApplyCreditToInvoice(c *gin.Context) {
 	var req CreateInvoiceAppliedCreditRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -55,13 +57,13 @@ func (h *InvoiceAppliedCreditHandler) ApplyCreditToInvoice(c *gin.Context) {
 		invoiceIDStr = c.Param("id")
 	}
 	if invoiceIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Invoice ID is required"))
 		return
 	}
 
 	invoiceID, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid invoice ID"))
 		return
 	}
 	req.InvoiceID = invoiceID
@@ -72,19 +74,19 @@ func (h *InvoiceAppliedCreditHandler) ApplyCreditToInvoice(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.ApplyCreditToInvoice(req, userID)
+	response, err := h.service.WithLogger(logging.FromGin(c)).ApplyCreditToInvoice(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -98,19 +100,19 @@ func (h *InvoiceAppliedCreditHandler) GetAppliedCredits(c *gin.Context) {
 		invoiceIDStr = c.Param("id")
 	}
 	if invoiceIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Invoice ID is required"))
 		return
 	}
 
 	invoiceID, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid invoice ID"))
 		return
 	}
 
 	appliedCredits, err := h.service.GetAppliedCreditsByInvoiceID(invoiceID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -121,7 +123,7 @@ func (h *InvoiceAppliedCreditHandler) GetAppliedCredits(c *gin.Context) {
 func (h *InvoiceAppliedCreditHandler) PreviewApplyCredit(c *gin.Context) {
 	var req CreateInvoiceAppliedCreditRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -131,20 +133,20 @@ func (h *InvoiceAppliedCreditHandler) PreviewApplyCredit(c *gin.Context) {
 		invoiceIDStr = c.Param("id")
 	}
 	if invoiceIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Invoice ID is required"))
 		return
 	}
 
 	invoiceID, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid invoice ID"))
 		return
 	}
 	req.InvoiceID = invoiceID
 
 	preview, err := h.service.PreviewApplyCredit(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -159,19 +161,19 @@ func (h *InvoiceAppliedCreditHandler) DeleteAppliedCredit(c *gin.Context) {
 		appliedCreditIDStr = c.Param("id")
 	}
 	if appliedCreditIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Applied Credit ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Applied Credit ID is required"))
 		return
 	}
 
 	appliedCreditID, err := strconv.Atoi(appliedCreditIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Applied Credit ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Applied Credit ID"))
 		return
 	}
 
-	err = h.service.DeleteAppliedCredit(appliedCreditID)
+	err = h.service.WithLogger(logging.FromGin(c)).DeleteAppliedCredit(appliedCreditID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
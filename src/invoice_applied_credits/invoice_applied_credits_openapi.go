@@ -0,0 +1,15 @@
+package invoice_applied_credits
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"InvoiceAppliedCreditHandler.GetAvailableCredits": {
+		Summary:     "List the customer's credits available to an invoice",
+		Description: "Lists credit memos and the unapplied credit of customer payments and prepayments. Credit memos are applied here; the others through the customer payment's apply endpoint.",
+		Response:    AvailableCreditsResponse{},
+	},
+	"InvoiceAppliedCreditHandler.PreviewApplyCredit":   {Summary: "Preview applying a credit to an invoice", Request: CreateInvoiceAppliedCreditRequest{}, Response: InvoiceAppliedCreditPreviewResponse{}},
+	"InvoiceAppliedCreditHandler.ApplyCreditToInvoice": {Summary: "Apply a credit to an invoice", UserID: true, Idempotent: true, Request: CreateInvoiceAppliedCreditRequest{}, Response: InvoiceAppliedCreditResponse{}},
+	"InvoiceAppliedCreditHandler.GetAppliedCredits":    {Summary: "List credits applied to an invoice", Response: []InvoiceAppliedCredit{}},
+	"InvoiceAppliedCreditHandler.DeleteAppliedCredit":  {Summary: "Remove an applied credit", Response: openapi.Message{}},
+}
@@ -2,7 +2,8 @@ package invoice_applied_credits
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type InvoiceAppliedCreditRepository interface {
@@ -48,7 +49,7 @@ func (r *invoiceAppliedCreditRepo) GetByID(id int) (InvoiceAppliedCredit, error)
 		Scan(&appliedCredit.ID, &appliedCredit.InvoiceID, &appliedCredit.CreditMemoID, &appliedCredit.Amount, &appliedCredit.Description, &appliedCredit.Date, &appliedCredit.Status, &appliedCredit.CreatedAt, &appliedCredit.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return appliedCredit, fmt.Errorf("invoice applied credit not found")
+		return appliedCredit, apperrors.NotFound("invoice applied credit")
 	}
 
 	return appliedCredit, err
@@ -2,9 +2,12 @@ package invoice_applied_credits
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/credit_memo"
+	"github.com/mysecodgit/go_accounting/src/customer_payments"
 	"github.com/mysecodgit/go_accounting/src/invoices"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
@@ -14,29 +17,43 @@ type InvoiceAppliedCreditService struct {
 	appliedCreditRepo InvoiceAppliedCreditRepository
 	invoiceRepo       invoices.InvoiceRepository
 	creditMemoRepo    credit_memo.CreditMemoRepository
+	paymentRepo       customer_payments.CustomerPaymentRepository
 	accountRepo       accounts.AccountRepository
+	logger            *slog.Logger
 }
 
 func NewInvoiceAppliedCreditService(
 	appliedCreditRepo InvoiceAppliedCreditRepository,
 	invoiceRepo invoices.InvoiceRepository,
 	creditMemoRepo credit_memo.CreditMemoRepository,
+	paymentRepo customer_payments.CustomerPaymentRepository,
 	accountRepo accounts.AccountRepository,
+	logger *slog.Logger,
 ) *InvoiceAppliedCreditService {
 	return &InvoiceAppliedCreditService{
 		appliedCreditRepo: appliedCreditRepo,
 		invoiceRepo:       invoiceRepo,
 		creditMemoRepo:    creditMemoRepo,
+		paymentRepo:       paymentRepo,
 		accountRepo:       accountRepo,
+		logger:            logger,
 	}
 }
 
-// GetAvailableCreditsForInvoice gets all available credit memos for an invoice (matching people_id)
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *InvoiceAppliedCreditService) WithLogger(logger *slog.Logger) *InvoiceAppliedCreditService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
+// GetAvailableCreditsForInvoice gets all available credit memos for an invoice (matching people_id),
+// and the unapplied credit of the customer's payments and prepayments that can pay it
 func (s *InvoiceAppliedCreditService) GetAvailableCreditsForInvoice(invoiceID int) (*AvailableCreditsResponse, error) {
 	// Get invoice to find people_id
 	invoice, err := s.invoiceRepo.GetByID(invoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	if invoice.PeopleID == nil {
@@ -52,7 +69,7 @@ func (s *InvoiceAppliedCreditService) GetAvailableCreditsForInvoice(invoiceID in
 	// Get all credit memos for this people_id
 	allCreditMemos, err := s.creditMemoRepo.GetByBuildingID(invoice.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch credit memos: %v", err)
+		return nil, fmt.Errorf("failed to fetch credit memos: %w", err)
 	}
 
 	// Filter credit memos by people_id and status = '1'
@@ -73,6 +90,7 @@ func (s *InvoiceAppliedCreditService) GetAvailableCreditsForInvoice(invoiceID in
 			if availableAmount > 0 {
 				availableCredits = append(availableCredits, AvailableCreditMemo{
 					ID:              creditMemo.ID,
+					Source:          CreditSourceCreditMemo,
 					Date:            creditMemo.Date,
 					Amount:          creditMemo.Amount,
 					AppliedAmount:   appliedAmount,
@@ -83,6 +101,34 @@ func (s *InvoiceAppliedCreditService) GetAvailableCreditsForInvoice(invoiceID in
 		}
 	}
 
+	// Customer payments and prepayments of another unit can't pay the invoice
+	payments, err := s.paymentRepo.List(invoice.BuildingID, customer_payments.ListFilter{PeopleID: &peopleID, Unapplied: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch customer payments: %w", err)
+	}
+	for _, payment := range payments {
+		if payment.UnitID != nil && (invoice.UnitID == nil || *payment.UnitID != *invoice.UnitID) {
+			continue
+		}
+		source := CreditSourceCustomerPayment
+		description := payment.Memo
+		if payment.IsPrepayment() {
+			source = CreditSourcePrepayment
+		}
+		if description == "" {
+			description = payment.Reference
+		}
+		availableCredits = append(availableCredits, AvailableCreditMemo{
+			ID:              payment.ID,
+			Source:          source,
+			Date:            payment.Date,
+			Amount:          payment.Amount,
+			AppliedAmount:   payment.AppliedAmount,
+			AvailableAmount: payment.UnappliedAmount,
+			Description:     description,
+		})
+	}
+
 	return &AvailableCreditsResponse{
 		InvoiceID: invoiceID,
 		PeopleID:  peopleID,
@@ -94,54 +140,54 @@ func (s *InvoiceAppliedCreditService) GetAvailableCreditsForInvoice(invoiceID in
 func (s *InvoiceAppliedCreditService) PreviewApplyCredit(req CreateInvoiceAppliedCreditRequest) (*InvoiceAppliedCreditPreviewResponse, error) {
 	// Validate amount
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	// Get invoice
 	invoice, err := s.invoiceRepo.GetByID(req.InvoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	if invoice.PeopleID == nil {
-		return nil, fmt.Errorf("invoice must have a people_id")
+		return nil, apperrors.Rule("invoice must have a people_id")
 	}
 
 	if invoice.ARAccountID == nil {
-		return nil, fmt.Errorf("invoice must have an A/R account")
+		return nil, apperrors.Rule("invoice must have an A/R account")
 	}
 
 	// Get credit memo
 	creditMemo, err := s.creditMemoRepo.GetByID(req.CreditMemoID)
 	if err != nil {
-		return nil, fmt.Errorf("credit memo not found: %v", err)
+		return nil, apperrors.Lookup("credit memo", err)
 	}
 
 	// Validate people_id matches
 	if creditMemo.PeopleID != *invoice.PeopleID {
-		return nil, fmt.Errorf("credit memo people_id does not match invoice people_id")
+		return nil, apperrors.Rule("credit memo people_id does not match invoice people_id")
 	}
 
 	// Check available amount
 	appliedAmount, err := s.appliedCreditRepo.GetAppliedAmountByCreditMemoID(req.CreditMemoID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get applied amount: %v", err)
+		return nil, fmt.Errorf("failed to get applied amount: %w", err)
 	}
 
 	availableAmount := creditMemo.Amount - appliedAmount
 	if req.Amount > availableAmount {
-		return nil, fmt.Errorf("amount exceeds available credit. Available: %.2f, Requested: %.2f", availableAmount, req.Amount)
+		return nil, apperrors.Rulef("amount exceeds available credit. Available: %.2f, Requested: %.2f", availableAmount, req.Amount)
 	}
 
 	// Get accounts for splits
 	arAccount, _, _, err := s.accountRepo.GetByID(*invoice.ARAccountID)
 	if err != nil {
-		return nil, fmt.Errorf("A/R account not found: %v", err)
+		return nil, apperrors.Lookup("A/R account", err)
 	}
 
 	liabilityAccount, _, _, err := s.accountRepo.GetByID(creditMemo.LiabilityAccount)
 	if err != nil {
-		return nil, fmt.Errorf("liability account not found: %v", err)
+		return nil, apperrors.Lookup("liability account", err)
 	}
 
 	// Create preview splits: Debit liability account, Credit A/R account
@@ -198,43 +244,43 @@ func (s *InvoiceAppliedCreditService) PreviewApplyCredit(req CreateInvoiceApplie
 func (s *InvoiceAppliedCreditService) ApplyCreditToInvoice(req CreateInvoiceAppliedCreditRequest, userID int) (*InvoiceAppliedCreditResponse, error) {
 	// Validate amount
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	// Get invoice
 	invoice, err := s.invoiceRepo.GetByID(req.InvoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	if invoice.PeopleID == nil {
-		return nil, fmt.Errorf("invoice must have a people_id")
+		return nil, apperrors.Rule("invoice must have a people_id")
 	}
 
 	if invoice.ARAccountID == nil {
-		return nil, fmt.Errorf("invoice must have an A/R account")
+		return nil, apperrors.Rule("invoice must have an A/R account")
 	}
 
 	// Get credit memo
 	creditMemo, err := s.creditMemoRepo.GetByID(req.CreditMemoID)
 	if err != nil {
-		return nil, fmt.Errorf("credit memo not found: %v", err)
+		return nil, apperrors.Lookup("credit memo", err)
 	}
 
 	// Validate people_id matches
 	if creditMemo.PeopleID != *invoice.PeopleID {
-		return nil, fmt.Errorf("credit memo people_id does not match invoice people_id")
+		return nil, apperrors.Rule("credit memo people_id does not match invoice people_id")
 	}
 
 	// Check available amount
 	appliedAmount, err := s.appliedCreditRepo.GetAppliedAmountByCreditMemoID(req.CreditMemoID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get applied amount: %v", err)
+		return nil, fmt.Errorf("failed to get applied amount: %w", err)
 	}
 
 	availableAmount := creditMemo.Amount - appliedAmount
 	if req.Amount > availableAmount {
-		return nil, fmt.Errorf("amount exceeds available credit. Available: %.2f, Requested: %.2f", availableAmount, req.Amount)
+		return nil, apperrors.Rulef("amount exceeds available credit. Available: %.2f, Requested: %.2f", availableAmount, req.Amount)
 	}
 
 	// Create invoice applied credit record (no transaction or splits needed)
@@ -251,8 +297,9 @@ func (s *InvoiceAppliedCreditService) ApplyCreditToInvoice(req CreateInvoiceAppl
 
 	createdAppliedCredit, err := s.appliedCreditRepo.Create(appliedCredit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create invoice applied credit: %v", err)
+		return nil, fmt.Errorf("failed to create invoice applied credit: %w", err)
 	}
+	s.logger.Info("credit applied", "applied_credit_id", createdAppliedCredit.ID, "invoice_id", req.InvoiceID, "credit_memo_id", req.CreditMemoID)
 
 	return &InvoiceAppliedCreditResponse{
 		InvoiceAppliedCredit: createdAppliedCredit,
@@ -271,20 +318,21 @@ func (s *InvoiceAppliedCreditService) DeleteAppliedCredit(appliedCreditID int) e
 	// Get the applied credit
 	appliedCredit, err := s.appliedCreditRepo.GetByID(appliedCreditID)
 	if err != nil {
-		return fmt.Errorf("applied credit not found: %v", err)
+		return apperrors.Lookup("applied credit", err)
 	}
 
 	// Check if already deleted
 	if appliedCredit.Status == "0" {
-		return fmt.Errorf("applied credit is already deleted")
+		return apperrors.Conflict("applied credit is already deleted")
 	}
 
 	// Soft delete the applied credit (no transaction or splits to delete)
 	appliedCredit.Status = "0"
 	_, err = s.appliedCreditRepo.Update(appliedCredit)
 	if err != nil {
-		return fmt.Errorf("failed to delete applied credit: %v", err)
+		return fmt.Errorf("failed to delete applied credit: %w", err)
 	}
+	s.logger.Info("applied credit deleted", "applied_credit_id", appliedCreditID)
 
 	return nil
 }
@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
 )
 
 type InvoiceAppliedDiscountHandler struct {
@@ -19,7 +21,7 @@ func NewInvoiceAppliedDiscountHandler(service *InvoiceAppliedDiscountService) *I
 func (h *InvoiceAppliedDiscountHandler) ApplyDiscountToInvoice(c *gin.Context) {
 	var req CreateInvoiceAppliedDiscountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -29,13 +31,13 @@ func (h *InvoiceAppliedDiscountHandler) ApplyDiscountToInvoice(c *gin.Context) {
 		invoiceIDStr = c.Param("id")
 	}
 	if invoiceIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Invoice ID is required"))
 		return
 	}
 
 	invoiceID, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid invoice ID"))
 		return
 	}
 	req.InvoiceID = invoiceID
@@ -46,19 +48,19 @@ func (h *InvoiceAppliedDiscountHandler) ApplyDiscountToInvoice(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.ApplyDiscountToInvoice(req, userID)
+	response, err := h.service.WithLogger(logging.FromGin(c)).ApplyDiscountToInvoice(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -72,19 +74,19 @@ func (h *InvoiceAppliedDiscountHandler) GetAppliedDiscounts(c *gin.Context) {
 		invoiceIDStr = c.Param("id")
 	}
 	if invoiceIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Invoice ID is required"))
 		return
 	}
 
 	invoiceID, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid invoice ID"))
 		return
 	}
 
 	appliedDiscounts, err := h.service.GetAppliedDiscountsByInvoiceID(invoiceID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -95,7 +97,7 @@ func (h *InvoiceAppliedDiscountHandler) GetAppliedDiscounts(c *gin.Context) {
 func (h *InvoiceAppliedDiscountHandler) PreviewApplyDiscount(c *gin.Context) {
 	var req CreateInvoiceAppliedDiscountRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -105,20 +107,20 @@ func (h *InvoiceAppliedDiscountHandler) PreviewApplyDiscount(c *gin.Context) {
 		invoiceIDStr = c.Param("id")
 	}
 	if invoiceIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invoice ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Invoice ID is required"))
 		return
 	}
 
 	invoiceID, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid invoice ID"))
 		return
 	}
 	req.InvoiceID = invoiceID
 
 	preview, err := h.service.PreviewApplyDiscount(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -133,19 +135,19 @@ func (h *InvoiceAppliedDiscountHandler) DeleteAppliedDiscount(c *gin.Context) {
 		appliedDiscountIDStr = c.Param("id")
 	}
 	if appliedDiscountIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Applied Discount ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Applied Discount ID is required"))
 		return
 	}
 
 	appliedDiscountID, err := strconv.Atoi(appliedDiscountIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Applied Discount ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Applied Discount ID"))
 		return
 	}
 
-	err = h.service.DeleteAppliedDiscount(appliedDiscountID)
+	err = h.service.WithLogger(logging.FromGin(c)).DeleteAppliedDiscount(appliedDiscountID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -0,0 +1,10 @@
+package invoice_applied_discounts
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"InvoiceAppliedDiscountHandler.PreviewApplyDiscount":   {Summary: "Preview applying a discount to an invoice", Request: CreateInvoiceAppliedDiscountRequest{}, Response: InvoiceAppliedDiscountPreviewResponse{}},
+	"InvoiceAppliedDiscountHandler.ApplyDiscountToInvoice": {Summary: "Apply a discount to an invoice", UserID: true, Idempotent: true, Request: CreateInvoiceAppliedDiscountRequest{}, Response: InvoiceAppliedDiscountResponse{}},
+	"InvoiceAppliedDiscountHandler.GetAppliedDiscounts":    {Summary: "List discounts applied to an invoice", Response: []InvoiceAppliedDiscount{}},
+	"InvoiceAppliedDiscountHandler.DeleteAppliedDiscount":  {Summary: "Remove an applied discount", Response: openapi.Message{}},
+}
@@ -2,7 +2,8 @@ package invoice_applied_discounts
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type InvoiceAppliedDiscountRepository interface {
@@ -45,7 +46,7 @@ func (r *invoiceAppliedDiscountRepo) GetByID(id int) (InvoiceAppliedDiscount, er
 		Scan(&appliedDiscount.ID, &appliedDiscount.Reference, &appliedDiscount.InvoiceID, &appliedDiscount.TransactionID, &appliedDiscount.ARAccount, &appliedDiscount.IncomeAccount, &appliedDiscount.Amount, &appliedDiscount.Description, &appliedDiscount.Date, &appliedDiscount.Status, &appliedDiscount.CreatedAt, &appliedDiscount.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return appliedDiscount, fmt.Errorf("invoice applied discount not found")
+		return appliedDiscount, apperrors.NotFound("invoice applied discount")
 	}
 
 	return appliedDiscount, err
@@ -3,9 +3,12 @@ package invoice_applied_discounts
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/invoices"
+	"github.com/mysecodgit/go_accounting/src/metrics"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
 )
@@ -17,6 +20,7 @@ type InvoiceAppliedDiscountService struct {
 	transactionRepo     transactions.TransactionRepository
 	splitRepo           splits.SplitRepository
 	db                  *sql.DB
+	logger              *slog.Logger
 }
 
 func NewInvoiceAppliedDiscountService(
@@ -26,6 +30,7 @@ func NewInvoiceAppliedDiscountService(
 	transactionRepo transactions.TransactionRepository,
 	splitRepo splits.SplitRepository,
 	db *sql.DB,
+	logger *slog.Logger,
 ) *InvoiceAppliedDiscountService {
 	return &InvoiceAppliedDiscountService{
 		appliedDiscountRepo: appliedDiscountRepo,
@@ -34,44 +39,52 @@ func NewInvoiceAppliedDiscountService(
 		transactionRepo:     transactionRepo,
 		splitRepo:           splitRepo,
 		db:                  db,
+		logger:              logger,
 	}
 }
 
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *InvoiceAppliedDiscountService) WithLogger(logger *slog.Logger) *InvoiceAppliedDiscountService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
 // PreviewApplyDiscount previews the splits that will be created when applying a discount
 func (s *InvoiceAppliedDiscountService) PreviewApplyDiscount(req CreateInvoiceAppliedDiscountRequest) (*InvoiceAppliedDiscountPreviewResponse, error) {
 	// Validate amount
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	// Get invoice
 	invoice, err := s.invoiceRepo.GetByID(req.InvoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	if invoice.PeopleID == nil {
-		return nil, fmt.Errorf("invoice must have a people_id")
+		return nil, apperrors.Rule("invoice must have a people_id")
 	}
 
 	if invoice.ARAccountID == nil {
-		return nil, fmt.Errorf("invoice must have an A/R account")
+		return nil, apperrors.Rule("invoice must have an A/R account")
 	}
 
 	// Validate A/R account matches
 	if *invoice.ARAccountID != req.ARAccount {
-		return nil, fmt.Errorf("A/R account does not match invoice A/R account")
+		return nil, apperrors.Rule("A/R account does not match invoice A/R account")
 	}
 
 	// Get accounts for splits
 	arAccount, _, _, err := s.accountRepo.GetByID(req.ARAccount)
 	if err != nil {
-		return nil, fmt.Errorf("A/R account not found: %v", err)
+		return nil, apperrors.Lookup("A/R account", err)
 	}
 
 	incomeAccount, _, _, err := s.accountRepo.GetByID(req.IncomeAccount)
 	if err != nil {
-		return nil, fmt.Errorf("income account not found: %v", err)
+		return nil, apperrors.Lookup("income account", err)
 	}
 
 	// Create preview splits: Debit Income Account, Credit A/R Account
@@ -131,43 +144,43 @@ func (s *InvoiceAppliedDiscountService) PreviewApplyDiscount(req CreateInvoiceAp
 func (s *InvoiceAppliedDiscountService) ApplyDiscountToInvoice(req CreateInvoiceAppliedDiscountRequest, userID int) (*InvoiceAppliedDiscountResponse, error) {
 	// Validate amount
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	// Get invoice
 	invoice, err := s.invoiceRepo.GetByID(req.InvoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	if invoice.PeopleID == nil {
-		return nil, fmt.Errorf("invoice must have a people_id")
+		return nil, apperrors.Rule("invoice must have a people_id")
 	}
 
 	if invoice.ARAccountID == nil {
-		return nil, fmt.Errorf("invoice must have an A/R account")
+		return nil, apperrors.Rule("invoice must have an A/R account")
 	}
 
 	// Validate A/R account matches
 	if *invoice.ARAccountID != req.ARAccount {
-		return nil, fmt.Errorf("A/R account does not match invoice A/R account")
+		return nil, apperrors.Rule("A/R account does not match invoice A/R account")
 	}
 
 	// Validate accounts exist
 	_, _, _, err = s.accountRepo.GetByID(req.ARAccount)
 	if err != nil {
-		return nil, fmt.Errorf("A/R account not found: %v", err)
+		return nil, apperrors.Lookup("A/R account", err)
 	}
 
 	_, _, _, err = s.accountRepo.GetByID(req.IncomeAccount)
 	if err != nil {
-		return nil, fmt.Errorf("income account not found: %v", err)
+		return nil, apperrors.Lookup("income account", err)
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
@@ -184,12 +197,12 @@ func (s *InvoiceAppliedDiscountService) ApplyDiscountToInvoice(req CreateInvoice
 	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		"payment", req.Date, "", transactionMemo, transactionStatus, invoice.BuildingID, userID, unitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %v", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	transactionID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction ID: %v", err)
+		return nil, fmt.Errorf("failed to get transaction ID: %w", err)
 	}
 
 	// Create splits for double-entry accounting
@@ -209,14 +222,14 @@ func (s *InvoiceAppliedDiscountService) ApplyDiscountToInvoice(req CreateInvoice
 	_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 		transactionID, req.IncomeAccount, peopleID, unitID, debitAmount, nil, "1")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create income debit split: %v", err)
+		return nil, fmt.Errorf("failed to create income debit split: %w", err)
 	}
 
 	// Credit A/R Account
 	_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 		transactionID, req.ARAccount, peopleID, unitID, nil, creditAmount, "1")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create A/R credit split: %v", err)
+		return nil, fmt.Errorf("failed to create A/R credit split: %w", err)
 	}
 
 	// Create invoice applied discount record
@@ -237,29 +250,31 @@ func (s *InvoiceAppliedDiscountService) ApplyDiscountToInvoice(req CreateInvoice
 	_, err = tx.Exec("INSERT INTO invoice_applied_discounts (invoice_id, transaction_id, ar_account, income_account, amount, description, date, status, reference) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		appliedDiscount.InvoiceID, appliedDiscount.TransactionID, appliedDiscount.ARAccount, appliedDiscount.IncomeAccount, appliedDiscount.Amount, appliedDiscount.Description, appliedDiscount.Date, appliedDiscount.Status, appliedDiscount.Reference)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create invoice applied discount: %v", err)
+		return nil, fmt.Errorf("failed to create invoice applied discount: %w", err)
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	metrics.RecordPosting("payment", metrics.PostingCreated)
+	s.logger.Info("discount applied", "invoice_id", req.InvoiceID, "transaction_id", transactionID)
 
 	// Fetch created records after successful commit
 	createdTransaction, err := s.transactionRepo.GetByID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	createdSplits, err := s.splitRepo.GetByTransactionID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	// Fetch the created applied discount
 	createdAppliedDiscount, err := s.appliedDiscountRepo.GetByTransactionID(int(transactionID))
 	if err != nil || len(createdAppliedDiscount) == 0 {
-		return nil, fmt.Errorf("failed to fetch invoice applied discount: %v", err)
+		return nil, fmt.Errorf("failed to fetch invoice applied discount: %w", err)
 	}
 
 	return &InvoiceAppliedDiscountResponse{
@@ -279,19 +294,19 @@ func (s *InvoiceAppliedDiscountService) DeleteAppliedDiscount(appliedDiscountID
 	// Get the applied discount
 	appliedDiscount, err := s.appliedDiscountRepo.GetByID(appliedDiscountID)
 	if err != nil {
-		return fmt.Errorf("applied discount not found: %v", err)
+		return apperrors.Lookup("applied discount", err)
 	}
 
 	// Check if already deleted
 	if appliedDiscount.Status == "0" {
-		return fmt.Errorf("applied discount is already deleted")
+		return apperrors.Conflict("applied discount is already deleted")
 	}
 
 	// Soft delete the applied discount
 	appliedDiscount.Status = "0"
 	_, err = s.appliedDiscountRepo.Update(appliedDiscount)
 	if err != nil {
-		return fmt.Errorf("failed to delete applied discount: %v", err)
+		return fmt.Errorf("failed to delete applied discount: %w", err)
 	}
 
 	// Also soft delete the transaction and splits
@@ -300,15 +315,18 @@ func (s *InvoiceAppliedDiscountService) DeleteAppliedDiscount(appliedDiscountID
 		// Update transaction status to '0'
 		_, err = s.db.Exec("UPDATE transactions SET status = '0' WHERE id = ?", transaction.ID)
 		if err != nil {
-			return fmt.Errorf("failed to delete transaction: %v", err)
+			return fmt.Errorf("failed to delete transaction: %w", err)
 		}
 
 		// Update splits status to '0'
 		_, err = s.db.Exec("UPDATE splits SET status = '0' WHERE transaction_id = ?", transaction.ID)
 		if err != nil {
-			return fmt.Errorf("failed to delete splits: %v", err)
+			return fmt.Errorf("failed to delete splits: %w", err)
 		}
+	} else {
+		s.logger.Warn("applied discount has no transaction to delete", "applied_discount_id", appliedDiscountID, "transaction_id", appliedDiscount.TransactionID, "error", err.Error())
 	}
+	s.logger.Info("applied discount deleted", "applied_discount_id", appliedDiscountID)
 
 	return nil
 }
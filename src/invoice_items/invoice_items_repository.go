@@ -2,7 +2,8 @@ package invoice_items
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type InvoiceItemRepository interface {
@@ -142,7 +143,7 @@ func (r *invoiceItemRepo) GetByID(id int) (InvoiceItem, error) {
 		Scan(&invoiceItem.ID, &invoiceItem.InvoiceID, &invoiceItem.ItemID, &invoiceItem.ItemName, &invoiceItem.PreviousValue, &invoiceItem.CurrentValue, &invoiceItem.Qty, &invoiceItem.Rate, &invoiceItem.Total, &invoiceItem.Status, &invoiceItem.CreatedAt, &invoiceItem.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return invoiceItem, fmt.Errorf("invoice item not found")
+		return invoiceItem, apperrors.NotFound("invoice item")
 	}
 
 	return invoiceItem, err
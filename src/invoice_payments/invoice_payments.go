@@ -14,8 +14,12 @@ type InvoicePayment struct {
 	AccountID     int     `json:"account_id"`
 	Amount        float64 `json:"amount"`
 	Status        int     `json:"status"`
+	Version       int     `json:"version"`
 	CreatedAt     string  `json:"created_at"`
 	UpdatedAt     string  `json:"updated_at"`
+
+	// Set when the payment is an allocation of a customer payment, which owns its postings
+	CustomerPaymentID *int `json:"customer_payment_id"`
 }
 
 func (ip *InvoicePayment) Validate() map[string]string {
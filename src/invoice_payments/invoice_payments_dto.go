@@ -24,6 +24,9 @@ type UpdateInvoicePaymentRequest struct {
 	Amount    float64 `json:"amount"`
 	Status    *int    `json:"status"`
 	BuildingID int    `json:"building_id"`
+
+	// Version from the If-Match header; the update fails if the document has moved on
+	ExpectedVersion int `json:"-"`
 }
 
 type SplitPreview struct {
@@ -5,6 +5,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type InvoicePaymentHandler struct {
@@ -19,7 +22,7 @@ func NewInvoicePaymentHandler(service *InvoicePaymentService) *InvoicePaymentHan
 func (h *InvoicePaymentHandler) CreateInvoicePayment(c *gin.Context) {
 	var req CreateInvoicePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -36,19 +39,19 @@ func (h *InvoicePaymentHandler) CreateInvoicePayment(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.CreateInvoicePayment(req, userID)
+	response, err := h.service.WithLogger(logging.FromGin(c)).CreateInvoicePayment(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -64,7 +67,7 @@ func (h *InvoicePaymentHandler) GetInvoicePayments(c *gin.Context) {
 
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
 		return
 	}
 
@@ -89,7 +92,7 @@ func (h *InvoicePaymentHandler) GetInvoicePayments(c *gin.Context) {
 
 	payments, err := h.service.GetPaymentRepo().GetByBuildingIDWithFilters(buildingID, startDate, endDate, peopleID, status)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -105,16 +108,17 @@ func (h *InvoicePaymentHandler) GetInvoicePayment(c *gin.Context) {
 	}
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Payment ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Payment ID"))
 		return
 	}
 
 	response, err := h.service.GetInvoicePaymentWithDetails(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Payment.Version)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -128,13 +132,13 @@ func (h *InvoicePaymentHandler) GetPaymentsByInvoice(c *gin.Context) {
 
 	invoiceID, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Invoice ID"))
 		return
 	}
 
 	payments, err := h.service.GetPaymentRepo().GetByInvoiceID(invoiceID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -145,7 +149,7 @@ func (h *InvoicePaymentHandler) GetPaymentsByInvoice(c *gin.Context) {
 func (h *InvoicePaymentHandler) PreviewInvoicePayment(c *gin.Context) {
 	var req CreateInvoicePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -159,7 +163,7 @@ func (h *InvoicePaymentHandler) PreviewInvoicePayment(c *gin.Context) {
 
 	preview, err := h.service.PreviewInvoicePayment(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -168,6 +172,11 @@ func (h *InvoicePaymentHandler) PreviewInvoicePayment(c *gin.Context) {
 
 // PUT /buildings/:id/invoice-payments/:paymentId
 func (h *InvoicePaymentHandler) UpdateInvoicePayment(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	paymentIDStr := c.Param("paymentId")
 	if paymentIDStr == "" {
 		paymentIDStr = c.Param("id")
@@ -175,13 +184,13 @@ func (h *InvoicePaymentHandler) UpdateInvoicePayment(c *gin.Context) {
 
 	paymentID, err := strconv.Atoi(paymentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Payment ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Payment ID"))
 		return
 	}
 
 	var req UpdateInvoicePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -198,21 +207,24 @@ func (h *InvoicePaymentHandler) UpdateInvoicePayment(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.UpdateInvoicePayment(paymentID, req, userID)
+	req.ExpectedVersion = version
+	response, err := h.service.WithLogger(logging.FromGin(c)).UpdateInvoicePayment(paymentID, req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Payment.Version)
+
 	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,25 @@
+package invoice_payments
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"InvoicePaymentHandler.PreviewInvoicePayment": {
+		Summary:     "Preview a payment's postings",
+		Description: "Validates the payment and returns the splits it would post without saving anything.",
+		Request:     CreateInvoicePaymentRequest{}, Response: InvoicePaymentPreviewResponse{},
+	},
+	"InvoicePaymentHandler.CreateInvoicePayment": {Summary: "Receive a payment against an invoice", UserID: true, Idempotent: true, Request: CreateInvoicePaymentRequest{}, Response: InvoicePaymentResponse{}},
+	"InvoicePaymentHandler.GetInvoicePayments": {
+		Summary: "List payments",
+		Query: []openapi.Param{
+			{Name: "start_date", Format: "date"},
+			{Name: "end_date", Format: "date"},
+			{Name: "people_id", Type: "integer", Format: "int32"},
+			{Name: "status"},
+		},
+		Response: []InvoicePayment{},
+	},
+	"InvoicePaymentHandler.GetPaymentsByInvoice": {Summary: "List an invoice's payments", Response: []InvoicePayment{}},
+	"InvoicePaymentHandler.GetInvoicePayment":    {Summary: "Get a payment with its postings", Response: InvoicePaymentResponse{}},
+	"InvoicePaymentHandler.UpdateInvoicePayment": {Summary: "Update a payment and repost it", UserID: true, IfMatch: true, Request: UpdateInvoicePaymentRequest{}, Response: InvoicePaymentResponse{}},
+}
@@ -2,7 +2,8 @@ package invoice_payments
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type InvoicePaymentRepository interface {
@@ -33,8 +34,8 @@ func (r *invoicePaymentRepo) Create(payment InvoicePayment) (InvoicePayment, err
 	id, _ := result.LastInsertId()
 	payment.ID = int(id)
 
-	err = r.db.QueryRow("SELECT id, transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, createdAt, updatedAt FROM invoice_payments WHERE id = ?", payment.ID).
-		Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, createdAt, updatedAt, version, customer_payment_id FROM invoice_payments WHERE id = ?", payment.ID).
+		Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt, &payment.Version, &payment.CustomerPaymentID)
 
 	return payment, err
 }
@@ -47,26 +48,26 @@ func (r *invoicePaymentRepo) Update(payment InvoicePayment) (InvoicePayment, err
 		return payment, err
 	}
 
-	err = r.db.QueryRow("SELECT id, transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, createdAt, updatedAt FROM invoice_payments WHERE id = ?", payment.ID).
-		Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, createdAt, updatedAt, version, customer_payment_id FROM invoice_payments WHERE id = ?", payment.ID).
+		Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt, &payment.Version, &payment.CustomerPaymentID)
 
 	return payment, err
 }
 
 func (r *invoicePaymentRepo) GetByID(id int) (InvoicePayment, error) {
 	var payment InvoicePayment
-	err := r.db.QueryRow("SELECT id, transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, createdAt, updatedAt FROM invoice_payments WHERE id = ?", id).
-		Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt)
+	err := r.db.QueryRow("SELECT id, transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, createdAt, updatedAt, version, customer_payment_id FROM invoice_payments WHERE id = ?", id).
+		Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt, &payment.Version, &payment.CustomerPaymentID)
 
 	if err == sql.ErrNoRows {
-		return payment, fmt.Errorf("invoice payment not found")
+		return payment, apperrors.NotFound("invoice payment")
 	}
 
 	return payment, err
 }
 
 func (r *invoicePaymentRepo) GetByInvoiceID(invoiceID int) ([]InvoicePayment, error) {
-	rows, err := r.db.Query("SELECT id, transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, createdAt, updatedAt FROM invoice_payments WHERE invoice_id = ? ORDER BY createdAt DESC", invoiceID)
+	rows, err := r.db.Query("SELECT id, transaction_id, reference, date, invoice_id, user_id, account_id, amount, status, createdAt, updatedAt, version, customer_payment_id FROM invoice_payments WHERE invoice_id = ? ORDER BY createdAt DESC", invoiceID)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +76,7 @@ func (r *invoicePaymentRepo) GetByInvoiceID(invoiceID int) ([]InvoicePayment, er
 	payments := []InvoicePayment{}
 	for rows.Next() {
 		var payment InvoicePayment
-		err := rows.Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt)
+		err := rows.Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt, &payment.Version, &payment.CustomerPaymentID)
 		if err != nil {
 			return nil, err
 		}
@@ -92,7 +93,7 @@ func (r *invoicePaymentRepo) GetByBuildingID(buildingID int) ([]InvoicePayment,
 func (r *invoicePaymentRepo) GetByBuildingIDWithFilters(buildingID int, startDate, endDate *string, peopleID *int, status *string) ([]InvoicePayment, error) {
 	// Join with invoices table to filter by building_id and other filters
 	query := `
-		SELECT ip.id, ip.transaction_id, ip.reference, ip.date, ip.invoice_id, ip.user_id, ip.account_id, ip.amount, ip.status, ip.createdAt, ip.updatedAt 
+		SELECT ip.id, ip.transaction_id, ip.reference, ip.date, ip.invoice_id, ip.user_id, ip.account_id, ip.amount, ip.status, ip.createdAt, ip.updatedAt, ip.version, ip.customer_payment_id
 		FROM invoice_payments ip
 		INNER JOIN invoices i ON ip.invoice_id = i.id
 		WHERE i.building_id = ?
@@ -132,7 +133,7 @@ func (r *invoicePaymentRepo) GetByBuildingIDWithFilters(buildingID int, startDat
 	payments := []InvoicePayment{}
 	for rows.Next() {
 		var payment InvoicePayment
-		err := rows.Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt)
+		err := rows.Scan(&payment.ID, &payment.TransactionID, &payment.Reference, &payment.Date, &payment.InvoiceID, &payment.UserID, &payment.AccountID, &payment.Amount, &payment.Status, &payment.CreatedAt, &payment.UpdatedAt, &payment.Version, &payment.CustomerPaymentID)
 		if err != nil {
 			return nil, err
 		}
@@ -3,11 +3,16 @@ package invoice_payments
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/invoices"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/outbox"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type InvoicePaymentService struct {
@@ -17,6 +22,7 @@ type InvoicePaymentService struct {
 	invoiceRepo     invoices.InvoiceRepository
 	accountRepo     accounts.AccountRepository
 	db              *sql.DB
+	logger          *slog.Logger
 }
 
 func (s *InvoicePaymentService) GetPaymentRepo() InvoicePaymentRepository {
@@ -30,6 +36,7 @@ func NewInvoicePaymentService(
 	invoiceRepo invoices.InvoiceRepository,
 	accountRepo accounts.AccountRepository,
 	db *sql.DB,
+	logger *slog.Logger,
 ) *InvoicePaymentService {
 	return &InvoicePaymentService{
 		paymentRepo:     paymentRepo,
@@ -38,9 +45,17 @@ func NewInvoicePaymentService(
 		invoiceRepo:     invoiceRepo,
 		accountRepo:     accountRepo,
 		db:              db,
+		logger:          logger,
 	}
 }
 
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *InvoicePaymentService) WithLogger(logger *slog.Logger) *InvoicePaymentService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
 // CreateInvoicePayment creates an invoice payment with transaction and splits
 // Double-entry accounting:
 // 1. Debit: Asset Account (cash/bank account where payment is received)
@@ -49,39 +64,39 @@ func (s *InvoicePaymentService) CreateInvoicePayment(req CreateInvoicePaymentReq
 	// Validate invoice exists
 	invoice, err := s.invoiceRepo.GetByID(req.InvoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	// Validate invoice belongs to the building
 	if invoice.BuildingID != req.BuildingID {
-		return nil, fmt.Errorf("invoice does not belong to the specified building")
+		return nil, apperrors.Rule("invoice does not belong to the specified building")
 	}
 
 	// Get AR account from invoice
 	if invoice.ARAccountID == nil {
-		return nil, fmt.Errorf("invoice does not have an A/R account configured")
+		return nil, apperrors.Rule("invoice does not have an A/R account configured")
 	}
 
 	arAccount, _, _, err := s.accountRepo.GetByID(*invoice.ARAccountID)
 	if err != nil {
-		return nil, fmt.Errorf("A/R account not found: %v", err)
+		return nil, apperrors.Lookup("A/R account", err)
 	}
 
 	// Get Asset Account from request
 	assetAccount, _, _, err := s.accountRepo.GetByID(req.AccountID)
 	if err != nil {
-		return nil, fmt.Errorf("asset account not found: %v", err)
+		return nil, apperrors.Lookup("asset account", err)
 	}
 
 	// Validate amount
 	if req.Amount == 0 {
-		return nil, fmt.Errorf("amount cannot be zero")
+		return nil, apperrors.Rule("amount cannot be zero")
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
@@ -97,12 +112,12 @@ func (s *InvoicePaymentService) CreateInvoicePayment(req CreateInvoicePaymentReq
 	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		"payment", req.Date, req.Reference, fmt.Sprintf("Payment for Invoice #%s", invoice.InvoiceNo), transactionStatus, req.BuildingID, userID, unitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %v", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	transactionID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction ID: %v", err)
+		return nil, fmt.Errorf("failed to get transaction ID: %w", err)
 	}
 
 	// Create invoice payment - always use status '1' (active)
@@ -110,12 +125,12 @@ func (s *InvoicePaymentService) CreateInvoicePayment(req CreateInvoicePaymentReq
 	result, err = tx.Exec("INSERT INTO invoice_payments (transaction_id, reference, date, invoice_id, user_id, account_id, amount, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		transactionID, req.Reference, req.Date, req.InvoiceID, userID, req.AccountID, req.Amount, paymentStatus)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create invoice payment: %v", err)
+		return nil, fmt.Errorf("failed to create invoice payment: %w", err)
 	}
 
 	paymentID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get payment ID: %v", err)
+		return nil, fmt.Errorf("failed to get payment ID: %w", err)
 	}
 
 	// Create splits for double-entry accounting
@@ -146,48 +161,66 @@ func (s *InvoicePaymentService) CreateInvoicePayment(req CreateInvoicePaymentReq
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, assetAccount.ID, peopleID, unitID, debitAmount, nil, "1") // unit_id + people_id from invoice
 		if err != nil {
-			return nil, fmt.Errorf("failed to create asset debit split: %v", err)
+			return nil, fmt.Errorf("failed to create asset debit split: %w", err)
 		}
 
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, arAccount.ID, peopleID, unitID, nil, creditAmount, "1") // AR account: people_id is set, unit_id from invoice
 		if err != nil {
-			return nil, fmt.Errorf("failed to create A/R credit split: %v", err)
+			return nil, fmt.Errorf("failed to create A/R credit split: %w", err)
 		}
 	} else {
 		// Refund/reversal: Credit Asset, Debit A/R
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, assetAccount.ID, peopleID, unitID, nil, creditAmount, "1") // unit_id + people_id from invoice
 		if err != nil {
-			return nil, fmt.Errorf("failed to create asset credit split: %v", err)
+			return nil, fmt.Errorf("failed to create asset credit split: %w", err)
 		}
 
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, arAccount.ID, peopleID, unitID, debitAmount, nil, "1") // AR account: people_id is set, unit_id from invoice
 		if err != nil {
-			return nil, fmt.Errorf("failed to create A/R debit split: %v", err)
+			return nil, fmt.Errorf("failed to create A/R debit split: %w", err)
 		}
 	}
 
+	// Record the event in the same transaction so it exists exactly when the payment does
+	err = outbox.Write(tx, req.BuildingID, outbox.PaymentCreated, int(paymentID), outbox.Document{
+		ID:            int(paymentID),
+		TransactionID: int(transactionID),
+		Number:        req.Reference,
+		Date:          req.Date,
+		Amount:        req.Amount,
+		PeopleID:      invoice.PeopleID,
+		UnitID:        invoice.UnitID,
+		InvoiceID:     &invoice.ID,
+		Version:       1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	metrics.RecordPosting("payment", metrics.PostingCreated)
+	s.logger.Info("payment created", "payment_id", paymentID, "transaction_id", transactionID)
 
 	// Fetch created records after successful commit
 	createdTransaction, err := s.transactionRepo.GetByID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	createdPayment, err := s.paymentRepo.GetByID(int(paymentID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch invoice payment: %v", err)
+		return nil, fmt.Errorf("failed to fetch invoice payment: %w", err)
 	}
 
 	createdSplits, err := s.splitRepo.GetByTransactionID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	return &InvoicePaymentResponse{
@@ -204,12 +237,12 @@ func (s *InvoicePaymentService) PreviewInvoicePayment(req CreateInvoicePaymentRe
 	// Validate invoice exists
 	invoice, err := s.invoiceRepo.GetByID(req.InvoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	// Validate invoice belongs to the building
 	if invoice.BuildingID != req.BuildingID {
-		return nil, fmt.Errorf("invoice does not belong to the specified building")
+		return nil, apperrors.Rule("invoice does not belong to the specified building")
 	}
 
 	// Debug: Log invoice unit_id
@@ -221,23 +254,23 @@ func (s *InvoicePaymentService) PreviewInvoicePayment(req CreateInvoicePaymentRe
 
 	// Get AR account from invoice
 	if invoice.ARAccountID == nil {
-		return nil, fmt.Errorf("invoice does not have an A/R account configured")
+		return nil, apperrors.Rule("invoice does not have an A/R account configured")
 	}
 
 	arAccount, _, _, err := s.accountRepo.GetByID(*invoice.ARAccountID)
 	if err != nil {
-		return nil, fmt.Errorf("A/R account not found: %v", err)
+		return nil, apperrors.Lookup("A/R account", err)
 	}
 
 	// Get Asset Account from request
 	assetAccount, _, _, err := s.accountRepo.GetByID(req.AccountID)
 	if err != nil {
-		return nil, fmt.Errorf("asset account not found: %v", err)
+		return nil, apperrors.Lookup("asset account", err)
 	}
 
 	// Validate amount
 	if req.Amount == 0 {
-		return nil, fmt.Errorf("amount cannot be zero")
+		return nil, apperrors.Rule("amount cannot be zero")
 	}
 
 	// Create preview splits
@@ -337,47 +370,57 @@ func (s *InvoicePaymentService) UpdateInvoicePayment(paymentID int, req UpdateIn
 	// Get existing payment
 	existingPayment, err := s.paymentRepo.GetByID(paymentID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice payment not found: %v", err)
+		return nil, apperrors.Lookup("invoice payment", err)
+	}
+
+	// Allocations share their customer payment's transaction, which reposting would overwrite
+	if existingPayment.CustomerPaymentID != nil {
+		return nil, apperrors.Rulef("payment is an allocation of customer payment %d, void or apply the customer payment instead", *existingPayment.CustomerPaymentID)
 	}
 
 	// Get invoice to validate building
 	invoice, err := s.invoiceRepo.GetByID(existingPayment.InvoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	if invoice.BuildingID != req.BuildingID {
-		return nil, fmt.Errorf("invoice does not belong to the specified building")
+		return nil, apperrors.Rule("invoice does not belong to the specified building")
 	}
 
 	// Get AR account from invoice
 	if invoice.ARAccountID == nil {
-		return nil, fmt.Errorf("invoice does not have an A/R account configured")
+		return nil, apperrors.Rule("invoice does not have an A/R account configured")
 	}
 
 	arAccount, _, _, err := s.accountRepo.GetByID(*invoice.ARAccountID)
 	if err != nil {
-		return nil, fmt.Errorf("A/R account not found: %v", err)
+		return nil, apperrors.Lookup("A/R account", err)
 	}
 
 	// Get Asset Account from request
 	assetAccount, _, _, err := s.accountRepo.GetByID(req.AccountID)
 	if err != nil {
-		return nil, fmt.Errorf("asset account not found: %v", err)
+		return nil, apperrors.Lookup("asset account", err)
 	}
 
 	// Validate amount
 	if req.Amount == 0 {
-		return nil, fmt.Errorf("amount cannot be zero")
+		return nil, apperrors.Rule("amount cannot be zero")
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	// Claim the next version first so a concurrent update fails instead of overwriting
+	if err := versioning.Bump(tx, "invoice_payments", paymentID, req.ExpectedVersion, "invoice payment"); err != nil {
+		return nil, err
+	}
+
 	// Update transaction memo, date, transaction_number, unit_id, and status (keep consistent with payment)
 	var unitID interface{}
 	if invoice.UnitID != nil {
@@ -398,7 +441,7 @@ func (s *InvoicePaymentService) UpdateInvoicePayment(paymentID int, req UpdateIn
 	_, err = tx.Exec("UPDATE transactions SET transaction_date = ?, transaction_number = ?, memo = ?, unit_id = ?, status = ? WHERE id = ?",
 		req.Date, req.Reference, fmt.Sprintf("Payment for Invoice #%s", invoice.InvoiceNo), unitID, paymentStatus, existingPayment.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update transaction: %v", err)
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
 	}
 
 	updatedPayment := existingPayment
@@ -416,13 +459,13 @@ func (s *InvoicePaymentService) UpdateInvoicePayment(paymentID int, req UpdateIn
 	_, err = tx.Exec("UPDATE invoice_payments SET reference = ?, date = ?, account_id = ?, amount = ?, status = ? WHERE id = ?",
 		req.Reference, updatedPayment.Date, updatedPayment.AccountID, updatedPayment.Amount, paymentStatus, updatedPayment.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update invoice payment: %v", err)
+		return nil, fmt.Errorf("failed to update invoice payment: %w", err)
 	}
 
 	// Soft delete existing splits
 	_, err = tx.Exec("UPDATE splits SET status = '0' WHERE transaction_id = ?", existingPayment.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to soft delete existing splits: %v", err)
+		return nil, fmt.Errorf("failed to soft delete existing splits: %w", err)
 	}
 
 	// Create new splits for double-entry accounting
@@ -460,48 +503,70 @@ func (s *InvoicePaymentService) UpdateInvoicePayment(paymentID int, req UpdateIn
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingPayment.TransactionID, assetAccount.ID, peopleID, splitUnitID, debitAmount, nil, splitStatus) // unit_id + people_id from invoice
 		if err != nil {
-			return nil, fmt.Errorf("failed to create asset debit split: %v", err)
+			return nil, fmt.Errorf("failed to create asset debit split: %w", err)
 		}
 
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingPayment.TransactionID, arAccount.ID, peopleID, splitUnitID, nil, creditAmount, splitStatus) // AR account: people_id is set, unit_id from invoice
 		if err != nil {
-			return nil, fmt.Errorf("failed to create A/R credit split: %v", err)
+			return nil, fmt.Errorf("failed to create A/R credit split: %w", err)
 		}
 	} else {
 		// Refund/reversal: Credit Asset, Debit A/R
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingPayment.TransactionID, assetAccount.ID, peopleID, splitUnitID, nil, creditAmount, splitStatus) // unit_id + people_id from invoice
 		if err != nil {
-			return nil, fmt.Errorf("failed to create asset credit split: %v", err)
+			return nil, fmt.Errorf("failed to create asset credit split: %w", err)
 		}
 
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingPayment.TransactionID, arAccount.ID, peopleID, splitUnitID, debitAmount, nil, splitStatus) // AR account: people_id is set, unit_id from invoice
 		if err != nil {
-			return nil, fmt.Errorf("failed to create A/R debit split: %v", err)
+			return nil, fmt.Errorf("failed to create A/R debit split: %w", err)
 		}
 	}
 
+	// Setting an active payment's status to 0 voids it
+	eventType := outbox.PaymentUpdated
+	if paymentStatus == "0" && existingPayment.Status != 0 {
+		eventType = outbox.PaymentVoided
+	}
+	err = outbox.Write(tx, invoice.BuildingID, eventType, existingPayment.ID, outbox.Document{
+		ID:            existingPayment.ID,
+		TransactionID: existingPayment.TransactionID,
+		Number:        req.Reference,
+		Date:          req.Date,
+		Amount:        req.Amount,
+		PeopleID:      invoice.PeopleID,
+		UnitID:        invoice.UnitID,
+		InvoiceID:     &invoice.ID,
+		Version:       req.ExpectedVersion + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	metrics.RecordPosting("payment", metrics.PostingUpdated)
+	s.logger.Info("payment updated", "payment_id", existingPayment.ID, "transaction_id", existingPayment.TransactionID)
 
 	// Fetch updated records
 	updatedTransaction, err := s.transactionRepo.GetByID(existingPayment.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	updatedPaymentRecord, err := s.paymentRepo.GetByID(paymentID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch invoice payment: %v", err)
+		return nil, fmt.Errorf("failed to fetch invoice payment: %w", err)
 	}
 
 	updatedSplits, err := s.splitRepo.GetByTransactionID(existingPayment.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	return &InvoicePaymentResponse{
@@ -518,13 +583,13 @@ func (s *InvoicePaymentService) GetInvoicePaymentWithDetails(paymentID int) (*In
 	// Get payment
 	payment, err := s.paymentRepo.GetByID(paymentID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice payment not found: %v", err)
+		return nil, apperrors.Lookup("invoice payment", err)
 	}
 
 	// Get invoice
 	invoice, err := s.invoiceRepo.GetByID(payment.InvoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	// Get AR account
@@ -539,13 +604,13 @@ func (s *InvoicePaymentService) GetInvoicePaymentWithDetails(paymentID int) (*In
 	// Get transaction
 	transaction, err := s.transactionRepo.GetByID(payment.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("transaction not found: %v", err)
+		return nil, apperrors.Lookup("transaction", err)
 	}
 
 	// Get splits (both active and inactive)
 	splits, err := s.splitRepo.GetByTransactionID(payment.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	return &InvoicePaymentResponse{
@@ -20,6 +20,7 @@ type Invoice struct {
 	CancelReason  *string `json:"cancel_reason"`
 	Status        int     `json:"status"`
 	BuildingID    int     `json:"building_id"`
+	Version       int     `json:"version"`
 	CreatedAt     string  `json:"created_at"`
 	UpdatedAt     string  `json:"updated_at"`
 }
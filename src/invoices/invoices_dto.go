@@ -1,6 +1,7 @@
 package invoices
 
 import (
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/invoice_items"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
@@ -60,6 +61,9 @@ type UpdateInvoiceRequest struct {
 	Status       *int               `json:"status"` // Use pointer to distinguish between not provided (nil) and explicitly set to 0
 	BuildingID   int                `json:"building_id"`
 	Items        []InvoiceItemInput `json:"items"`
+
+	// Version from the If-Match header; the update fails if the document has moved on
+	ExpectedVersion int `json:"-"`
 }
 
 type InvoiceResponse struct {
@@ -67,6 +71,9 @@ type InvoiceResponse struct {
 	Items       []invoice_items.InvoiceItem `json:"items"`
 	Splits      []splits.Split             `json:"splits"`
 	Transaction transactions.Transaction  `json:"transaction"`
+
+	PrepaymentsApplied float64              `json:"prepayments_applied,omitempty"` // Of the customer's prepayments, when the invoice is created
+	PrepaymentsError   *apperrors.ErrorBody `json:"prepayments_error,omitempty"`   // Why the rest of the prepayments were not applied; apply them by hand
 }
 
 type InvoiceListItem struct {
@@ -84,6 +91,7 @@ type InvoiceListItem struct {
 	CancelReason       *string `json:"cancel_reason"`
 	Status             int     `json:"status"`
 	BuildingID         int     `json:"building_id"`
+	Version            int     `json:"version"`
 	CreatedAt          string  `json:"created_at"`
 	UpdatedAt          string  `json:"updated_at"`
 	PaidAmount         float64 `json:"paid_amount"`
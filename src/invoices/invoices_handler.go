@@ -5,6 +5,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type InvoiceHandler struct {
@@ -19,7 +22,7 @@ func NewInvoiceHandler(service *InvoiceService) *InvoiceHandler {
 func (h *InvoiceHandler) PreviewInvoice(c *gin.Context) {
 	var req CreateInvoiceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -38,19 +41,19 @@ func (h *InvoiceHandler) PreviewInvoice(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
 	preview, err := h.service.PreviewInvoice(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -61,7 +64,7 @@ func (h *InvoiceHandler) PreviewInvoice(c *gin.Context) {
 func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
 	var req CreateInvoiceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -80,19 +83,19 @@ func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.CreateInvoice(req, userID)
+	response, err := h.service.WithLogger(logging.FromGin(c)).CreateInvoice(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -108,32 +111,19 @@ func (h *InvoiceHandler) GetInvoices(c *gin.Context) {
 
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
 		return
 	}
 
-	// Get filter parameters from query string
-	var startDate, endDate, status *string
-	var peopleID *int
-
-	if startDateStr := c.Query("start_date"); startDateStr != "" {
-		startDate = &startDateStr
-	}
-	if endDateStr := c.Query("end_date"); endDateStr != "" {
-		endDate = &endDateStr
-	}
-	if statusStr := c.Query("status"); statusStr != "" {
-		status = &statusStr
-	}
-	if peopleIDStr := c.Query("people_id"); peopleIDStr != "" {
-		if pid, err := strconv.Atoi(peopleIDStr); err == nil {
-			peopleID = &pid
-		}
+	params, validationErrors := invoiceListSpec.Parse(c.Request.URL.Query())
+	if validationErrors != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
+		return
 	}
 
-	invoices, err := h.service.GetInvoiceRepo().GetByBuildingIDWithTotalsAndFilters(buildingID, startDate, endDate, peopleID, status)
+	invoices, err := h.service.ListInvoices(buildingID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -150,13 +140,13 @@ func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	invoice, err := h.service.GetInvoiceRepo().GetByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -165,21 +155,25 @@ func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
 	splits, _ := h.service.GetSplitRepo().GetByTransactionID(invoice.TransactionID)
 	transaction, _ := h.service.GetTransactionRepo().GetByID(invoice.TransactionID)
 
-	response := gin.H{
-		"invoice":     invoice,
-		"items":       invoiceItems,
-		"splits":      splits,
-		"transaction": transaction,
-	}
-
-	c.JSON(http.StatusOK, response)
+	versioning.SetETag(c, invoice.Version)
+	c.JSON(http.StatusOK, InvoiceResponse{
+		Invoice:     invoice,
+		Items:       invoiceItems,
+		Splits:      splits,
+		Transaction: transaction,
+	})
 }
 
 // PUT /invoices/:id or /buildings/:id/invoices/:invoiceId
 func (h *InvoiceHandler) UpdateInvoice(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateInvoiceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -191,7 +185,7 @@ func (h *InvoiceHandler) UpdateInvoice(c *gin.Context) {
 
 	id, err := strconv.Atoi(invoiceIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Invoice ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Invoice ID"))
 		return
 	}
 	req.ID = id
@@ -222,21 +216,24 @@ func (h *InvoiceHandler) UpdateInvoice(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.UpdateInvoice(req, userID)
+	req.ExpectedVersion = version
+	response, err := h.service.WithLogger(logging.FromGin(c)).UpdateInvoice(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Invoice.Version)
+
 	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,18 @@
+package invoices
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"InvoiceHandler.PreviewInvoice": {
+		Summary:     "Preview an invoice's postings",
+		Description: "Validates the invoice and returns the splits it would post without saving anything.",
+		UserID:      true, Request: CreateInvoiceRequest{}, Response: InvoicePreviewResponse{},
+	},
+	"InvoiceHandler.CreateInvoice": {Summary: "Create and post an invoice", UserID: true, Idempotent: true, Request: CreateInvoiceRequest{}, Response: InvoiceResponse{}},
+	"InvoiceHandler.GetInvoices":   {Summary: "List invoices", Response: pagination.Page[InvoiceListItem]{}, List: &invoiceListSpec},
+	"InvoiceHandler.GetInvoice":    {Summary: "Get an invoice with its items and postings", Response: InvoiceResponse{}},
+	"InvoiceHandler.UpdateInvoice": {Summary: "Update an invoice and repost it", UserID: true, IfMatch: true, Request: UpdateInvoiceRequest{}, Response: InvoiceResponse{}},
+}
@@ -3,6 +3,9 @@ package invoices
 import (
 	"database/sql"
 	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 )
 
 type InvoiceRepository interface {
@@ -10,8 +13,7 @@ type InvoiceRepository interface {
 	Update(invoice Invoice) (Invoice, error)
 	GetByID(id int) (Invoice, error)
 	GetByBuildingID(buildingID int) ([]Invoice, error)
-	GetByBuildingIDWithTotals(buildingID int) ([]InvoiceListItem, error)
-	GetByBuildingIDWithTotalsAndFilters(buildingID int, startDate, endDate *string, peopleID *int, status *string) ([]InvoiceListItem, error)
+	List(buildingID int, params pagination.Params) ([]InvoiceListItem, int, error)
 	GetNextInvoiceNo(buildingID int) (string, error)
 	CheckDuplicateInvoiceNo(buildingID int, invoiceNo string, excludeID int) (bool, error)
 }
@@ -63,8 +65,8 @@ func (r *invoiceRepo) Create(invoice Invoice) (Invoice, error) {
 	id, _ := result.LastInsertId()
 	invoice.ID = int(id)
 
-	err = r.db.QueryRow("SELECT id, invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt FROM invoices WHERE id = ?", invoice.ID).
-		Scan(&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate, &invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount, &invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID, &invoice.CreatedAt, &invoice.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt, version FROM invoices WHERE id = ?", invoice.ID).
+		Scan(&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate, &invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount, &invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID, &invoice.CreatedAt, &invoice.UpdatedAt, &invoice.Version)
 
 	return invoice, err
 }
@@ -105,26 +107,26 @@ func (r *invoiceRepo) Update(invoice Invoice) (Invoice, error) {
 		return invoice, err
 	}
 
-	err = r.db.QueryRow("SELECT id, invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt FROM invoices WHERE id = ?", invoice.ID).
-		Scan(&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate, &invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount, &invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID, &invoice.CreatedAt, &invoice.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt, version FROM invoices WHERE id = ?", invoice.ID).
+		Scan(&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate, &invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount, &invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID, &invoice.CreatedAt, &invoice.UpdatedAt, &invoice.Version)
 
 	return invoice, err
 }
 
 func (r *invoiceRepo) GetByID(id int) (Invoice, error) {
 	var invoice Invoice
-	err := r.db.QueryRow("SELECT id, invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt FROM invoices WHERE id = ?", id).
-		Scan(&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate, &invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount, &invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID, &invoice.CreatedAt, &invoice.UpdatedAt)
+	err := r.db.QueryRow("SELECT id, invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt, version FROM invoices WHERE id = ?", id).
+		Scan(&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate, &invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount, &invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID, &invoice.CreatedAt, &invoice.UpdatedAt, &invoice.Version)
 
 	if err == sql.ErrNoRows {
-		return invoice, fmt.Errorf("invoice not found")
+		return invoice, apperrors.NotFound("invoice")
 	}
 
 	return invoice, err
 }
 
 func (r *invoiceRepo) GetByBuildingID(buildingID int) ([]Invoice, error) {
-	rows, err := r.db.Query("SELECT id, invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, refrence, cancel_reason, status, building_id, createdAt, updatedAt FROM invoices WHERE building_id = ? ORDER BY createdAt DESC", buildingID)
+	rows, err := r.db.Query("SELECT id, invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, refrence, cancel_reason, status, building_id, createdAt, updatedAt, version FROM invoices WHERE building_id = ? ORDER BY createdAt DESC", buildingID)
 	if err != nil {
 		return nil, err
 	}
@@ -133,7 +135,7 @@ func (r *invoiceRepo) GetByBuildingID(buildingID int) ([]Invoice, error) {
 	invoices := []Invoice{}
 	for rows.Next() {
 		var invoice Invoice
-		err := rows.Scan(&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate, &invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount, &invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID, &invoice.CreatedAt, &invoice.UpdatedAt)
+		err := rows.Scan(&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate, &invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount, &invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID, &invoice.CreatedAt, &invoice.UpdatedAt, &invoice.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -175,17 +177,62 @@ func (r *invoiceRepo) CheckDuplicateInvoiceNo(buildingID int, invoiceNo string,
 	return count > 0, nil
 }
 
-func (r *invoiceRepo) GetByBuildingIDWithTotals(buildingID int) ([]InvoiceListItem, error) {
-	return r.GetByBuildingIDWithTotalsAndFilters(buildingID, nil, nil, nil, nil)
+var invoiceListSpec = pagination.Spec{
+	IDColumn: "i.id",
+	Sorts: map[string]string{
+		"id":         "i.id",
+		"date":       "i.sales_date",
+		"due_date":   "i.due_date",
+		"number":     "i.invoice_no",
+		"amount":     "i.amount",
+		"created_at": "i.createdAt",
+	},
+	DefaultSort:  "-created_at",
+	DateColumn:   "i.sales_date",
+	AmountColumn: "i.amount",
+	NumberColumn: "i.invoice_no",
+	StatusFilter: "i.status = ?",
+	PeopleFilter: "i.people_id = ?",
+	UnitFilter:   "i.unit_id = ?",
+}
+
+func invoiceSortKey(invoice InvoiceListItem, sort string) (interface{}, int) {
+	switch sort {
+	case "date":
+		return invoice.SalesDate, invoice.ID
+	case "due_date":
+		return invoice.DueDate, invoice.ID
+	case "number":
+		return invoice.InvoiceNo, invoice.ID
+	case "amount":
+		return invoice.Amount, invoice.ID
+	case "created_at":
+		return invoice.CreatedAt, invoice.ID
+	}
+	return invoice.ID, invoice.ID
 }
 
-func (r *invoiceRepo) GetByBuildingIDWithTotalsAndFilters(buildingID int, startDate, endDate *string, peopleID *int, status *string) ([]InvoiceListItem, error) {
+// List returns one page of a building's invoices with paid and credited totals, plus the
+// number of invoices matching the filters
+func (r *invoiceRepo) List(buildingID int, params pagination.Params) ([]InvoiceListItem, int, error) {
+	where, args := invoiceListSpec.Where(params)
+	where = " WHERE i.building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM invoices i"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := invoiceListSpec.After(params)
+	order, orderArgs := invoiceListSpec.OrderAndLimit(params)
 	query := `
 		SELECT 
 			i.id, i.invoice_no, i.transaction_id, i.sales_date, i.due_date, 
 			i.ar_account_id, i.unit_id, i.people_id, i.user_id, i.amount, 
 			i.description, i.cancel_reason, i.status, i.building_id, 
-			i.createdAt, i.updatedAt,
+			i.createdAt, i.updatedAt, i.version,
 			COALESCE((
 				SELECT SUM(ip.amount) 
 				FROM invoice_payments ip 
@@ -196,38 +243,12 @@ func (r *invoiceRepo) GetByBuildingIDWithTotalsAndFilters(buildingID int, startD
 				FROM invoice_applied_credits iac 
 				WHERE iac.invoice_id = i.id AND iac.status = '1'
 			), 0) as applied_credits_total
-		FROM invoices i
-		WHERE i.building_id = ?
-	`
-	
-	args := []interface{}{buildingID}
-	
-	// Add filters
-	if startDate != nil && *startDate != "" {
-		query += " AND DATE(i.sales_date) >= ?"
-		args = append(args, *startDate)
-	}
-	
-	if endDate != nil && *endDate != "" {
-		query += " AND DATE(i.sales_date) <= ?"
-		args = append(args, *endDate)
-	}
-	
-	if peopleID != nil && *peopleID > 0 {
-		query += " AND i.people_id = ?"
-		args = append(args, *peopleID)
-	}
-	
-	if status != nil && *status != "" {
-		query += " AND i.status = ?"
-		args = append(args, *status)
-	}
-	
-	query += " ORDER BY i.createdAt DESC"
-	
+		FROM invoices i` + where + after + order
+	args = append(append(args, afterArgs...), orderArgs...)
+
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -238,14 +259,14 @@ func (r *invoiceRepo) GetByBuildingIDWithTotalsAndFilters(buildingID int, startD
 			&invoice.ID, &invoice.InvoiceNo, &invoice.TransactionID, &invoice.SalesDate, &invoice.DueDate,
 			&invoice.ARAccountID, &invoice.UnitID, &invoice.PeopleID, &invoice.UserID, &invoice.Amount,
 			&invoice.Description, &invoice.CancelReason, &invoice.Status, &invoice.BuildingID,
-			&invoice.CreatedAt, &invoice.UpdatedAt,
+			&invoice.CreatedAt, &invoice.UpdatedAt, &invoice.Version,
 			&invoice.PaidAmount, &invoice.AppliedCreditsTotal,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		invoices = append(invoices, invoice)
 	}
 
-	return invoices, nil
+	return invoices, total, rows.Err()
 }
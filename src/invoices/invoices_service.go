@@ -3,13 +3,20 @@ package invoices
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"math"
 
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/customer_payments"
 	"github.com/mysecodgit/go_accounting/src/invoice_items"
 	"github.com/mysecodgit/go_accounting/src/items"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type InvoiceService struct {
@@ -20,6 +27,10 @@ type InvoiceService struct {
 	itemRepo        items.ItemRepository
 	accountRepo     accounts.AccountRepository
 	db              *sql.DB
+	logger          *slog.Logger
+
+	// Applies the customer's prepayments to each new invoice
+	customerPayments *customer_payments.CustomerPaymentService
 }
 
 // Expose invoiceRepo for handler access
@@ -49,7 +60,9 @@ func NewInvoiceService(
 	invoiceItemRepo invoice_items.InvoiceItemRepository,
 	itemRepo items.ItemRepository,
 	accountRepo accounts.AccountRepository,
+	customerPayments *customer_payments.CustomerPaymentService,
 	db *sql.DB,
+	logger *slog.Logger,
 ) *InvoiceService {
 	return &InvoiceService{
 		invoiceRepo:     invoiceRepo,
@@ -59,9 +72,19 @@ func NewInvoiceService(
 		itemRepo:        itemRepo,
 		accountRepo:     accountRepo,
 		db:              db,
+		logger:          logger,
+
+		customerPayments: customerPayments,
 	}
 }
 
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *InvoiceService) WithLogger(logger *slog.Logger) *InvoiceService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
 // CalculateSplitsForInvoice calculates the double-entry accounting splits for an invoice
 func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, userID int) ([]SplitPreview, error) {
 	splits := []SplitPreview{}
@@ -73,7 +96,7 @@ func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, use
 	for _, itemInput := range req.Items {
 		item, _, assetAccount, incomeAccount, _, _, err := s.itemRepo.GetByID(itemInput.ItemID)
 		if err != nil {
-			return nil, fmt.Errorf("item %d not found: %v", itemInput.ItemID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("item %d", itemInput.ItemID), err)
 		}
 		itemMap[itemInput.ItemID] = &item
 		if incomeAccount != nil {
@@ -91,12 +114,12 @@ func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, use
 	// Get accounts for the building
 	accountsList, _, _, err := s.accountRepo.GetByBuildingID(req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get accounts: %v", err)
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
 	}
 
 	// Get Accounts Receivable account from request
 	if req.ARAccountID == nil {
-		return nil, fmt.Errorf("A/R account is required")
+		return nil, apperrors.Rule("A/R account is required")
 	}
 
 	var accountsReceivableAccount *accounts.Account
@@ -108,7 +131,7 @@ func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, use
 	}
 
 	if accountsReceivableAccount == nil {
-		return nil, fmt.Errorf("A/R account not found")
+		return nil, apperrors.NotFound("A/R account")
 	}
 
 	// Calculate totals by item type
@@ -183,7 +206,7 @@ func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, use
 				}
 			} else {
 				// Service items must have an income account for proper accounting
-				return nil, fmt.Errorf("service item '%s' (ID: %d) must have an income account configured", item.Name, item.ID)
+				return nil, apperrors.Rulef("service item '%s' (ID: %d) must have an income account configured", item.Name, item.ID)
 			}
 		}
 	}
@@ -251,13 +274,13 @@ func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, use
 	// 4. Credit: Service Income Accounts (positive rates)
 	// This should always have entries if we have service items (validated above)
 	if len(serviceIncomeByAccount) == 0 && len(serviceDebitByAccount) == 0 && serviceTotalAmount > 0 {
-		return nil, fmt.Errorf("no income account found for service items - service items must have an income account configured")
+		return nil, apperrors.Rule("no income account found for service items - service items must have an income account configured")
 	}
 
 	for accountID, amount := range serviceIncomeByAccount {
 		account, _, _, err := s.accountRepo.GetByID(accountID)
 		if err != nil {
-			return nil, fmt.Errorf("income account %d not found: %v", accountID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("income account %d", accountID), err)
 		}
 		creditAmount := amount
 		splits = append(splits, SplitPreview{
@@ -275,7 +298,7 @@ func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, use
 	for accountID, amount := range serviceDebitByAccount {
 		account, _, _, err := s.accountRepo.GetByID(accountID)
 		if err != nil {
-			return nil, fmt.Errorf("income account %d not found: %v", accountID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("income account %d", accountID), err)
 		}
 		debitAmount := amount
 		splits = append(splits, SplitPreview{
@@ -320,11 +343,11 @@ func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, use
 
 	// Validate: Must have at least 2 splits and be balanced for double-entry accounting
 	if len(splits) < 2 {
-		return nil, fmt.Errorf("invoice must have at least 2 splits for double-entry accounting, got %d", len(splits))
+		return nil, apperrors.Rulef("invoice must have at least 2 splits for double-entry accounting, got %d", len(splits))
 	}
 
 	if totalDebit != totalCredit {
-		return nil, fmt.Errorf("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
+		return nil, apperrors.Rulef("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
 	}
 
 	return splits, nil
@@ -334,11 +357,11 @@ func (s *InvoiceService) CalculateSplitsForInvoice(req CreateInvoiceRequest, use
 func (s *InvoiceService) PreviewInvoice(req CreateInvoiceRequest, userID int) (*InvoicePreviewResponse, error) {
 	// Validate request
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	if len(req.Items) == 0 {
-		return nil, fmt.Errorf("invoice must have at least one item")
+		return nil, apperrors.Rule("invoice must have at least one item")
 	}
 
 	// Calculate splits
@@ -379,13 +402,13 @@ func (s *InvoiceService) CreateInvoice(req CreateInvoiceRequest, userID int) (*I
 		return nil, err
 	}
 	if exists {
-		return nil, fmt.Errorf("invoice number already exists for this building")
+		return nil, apperrors.Conflict("invoice number already exists for this building")
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -409,12 +432,12 @@ func (s *InvoiceService) CreateInvoice(req CreateInvoiceRequest, userID int) (*I
 	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		"invoice", req.SalesDate, req.InvoiceNo, req.Description, transactionStatus, req.BuildingID, userID, unitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %v", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	transactionID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction ID: %v", err)
+		return nil, fmt.Errorf("failed to get transaction ID: %w", err)
 	}
 
 	// Create invoice
@@ -437,19 +460,19 @@ func (s *InvoiceService) CreateInvoice(req CreateInvoiceRequest, userID int) (*I
 	result, err = tx.Exec("INSERT INTO invoices (invoice_no, transaction_id, sales_date, due_date, ar_account_id, unit_id, people_id, user_id, amount, description, cancel_reason, status, building_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		req.InvoiceNo, transactionID, req.SalesDate, req.DueDate, arAccountID, unitID, peopleID, userID, req.Amount, req.Description, nil, invoiceStatus, req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create invoice: %v", err)
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
 	}
 
 	invoiceID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get invoice ID: %v", err)
+		return nil, fmt.Errorf("failed to get invoice ID: %w", err)
 	}
 
 	// Create invoice items
 	for _, itemInput := range req.Items {
 		item, _, _, _, _, _, err := s.itemRepo.GetByID(itemInput.ItemID)
 		if err != nil {
-			return nil, fmt.Errorf("item %d not found: %v", itemInput.ItemID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("item %d", itemInput.ItemID), err)
 		}
 
 		// Calculate total using rate from input
@@ -509,14 +532,14 @@ func (s *InvoiceService) CreateInvoice(req CreateInvoiceRequest, userID int) (*I
 		_, err = tx.Exec("INSERT INTO invoice_items (invoice_id, item_id, item_name, previous_value, current_value, qty, rate, total, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
 			invoiceID, itemInput.ItemID, item.Name, previousValue, currentValue, qty, rateStr, total, itemStatus)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create invoice item: %v", err)
+			return nil, fmt.Errorf("failed to create invoice item: %w", err)
 		}
 	}
 
 	// Calculate and create splits
 	splitPreviews, err := s.CalculateSplitsForInvoice(req, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Create splits within transaction
@@ -553,42 +576,75 @@ func (s *InvoiceService) CreateInvoice(req CreateInvoiceRequest, userID int) (*I
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
+	// Record the event in the same transaction so it exists exactly when the invoice does
+	err = outbox.Write(tx, req.BuildingID, outbox.InvoiceCreated, int(invoiceID), outbox.Document{
+		ID:            int(invoiceID),
+		TransactionID: int(transactionID),
+		Number:        req.InvoiceNo,
+		Date:          req.SalesDate,
+		Amount:        req.Amount,
+		PeopleID:      req.PeopleID,
+		UnitID:        req.UnitID,
+		Version:       1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("invoice", metrics.PostingCreated)
+	s.logger.Info("invoice created", "invoice_id", invoiceID, "transaction_id", transactionID)
+
+	// The invoice stands even if the customer's prepayments can't be applied. Each one is
+	// applied in a transaction of its own, so the response says how much was applied and why
+	// the rest wasn't; what is left stays available to apply by hand.
+	prepaymentsApplied := 0.0
+	var prepaymentsError *apperrors.ErrorBody
+	if req.PeopleID != nil {
+		prepaymentsApplied, err = s.customerPayments.WithLogger(s.logger).ApplyPrepayments(req.BuildingID, *req.PeopleID, int(invoiceID), userID)
+		if err != nil {
+			s.logger.Warn("failed to apply prepayments to invoice", "invoice_id", invoiceID, "applied", prepaymentsApplied, "error", err)
+			_, envelope := apperrors.Response(err)
+			prepaymentsError = &envelope.Error
+		}
+	}
 
 	// Fetch created records after successful commit
 	createdTransaction, err := s.transactionRepo.GetByID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	createdInvoice, err := s.invoiceRepo.GetByID(int(invoiceID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch invoice: %v", err)
+		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
 	}
 
 	createdSplits, err := s.splitRepo.GetByTransactionID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	createdInvoiceItems, err := s.invoiceItemRepo.GetByInvoiceID(int(invoiceID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch invoice items: %v", err)
+		return nil, fmt.Errorf("failed to fetch invoice items: %w", err)
 	}
 
 	return &InvoiceResponse{
-		Invoice:     createdInvoice,
-		Items:       createdInvoiceItems,
-		Splits:      createdSplits,
-		Transaction: createdTransaction,
+		Invoice:            createdInvoice,
+		Items:              createdInvoiceItems,
+		Splits:             createdSplits,
+		Transaction:        createdTransaction,
+		PrepaymentsApplied: prepaymentsApplied,
+		PrepaymentsError:   prepaymentsError,
 	}, nil
 }
 
@@ -599,12 +655,12 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 	// Validate invoice exists
 	existingInvoice, err := s.invoiceRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("invoice not found: %v", err)
+		return nil, apperrors.Lookup("invoice", err)
 	}
 
 	// Validate invoice belongs to the building
 	if existingInvoice.BuildingID != req.BuildingID {
-		return nil, fmt.Errorf("invoice does not belong to the specified building")
+		return nil, apperrors.Rule("invoice does not belong to the specified building")
 	}
 
 	// Check for duplicate invoice number (excluding current invoice)
@@ -613,22 +669,22 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 		return nil, err
 	}
 	if exists {
-		return nil, fmt.Errorf("invoice number already exists for this building")
+		return nil, apperrors.Conflict("invoice number already exists for this building")
 	}
 
 	// Validate request
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	if len(req.Items) == 0 {
-		return nil, fmt.Errorf("invoice must have at least one item")
+		return nil, apperrors.Rule("invoice must have at least one item")
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -639,6 +695,11 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 		}
 	}()
 
+	// Claim the next version first so a concurrent update fails instead of overwriting
+	if err := versioning.Bump(tx, "invoices", req.ID, req.ExpectedVersion, "invoice"); err != nil {
+		return nil, err
+	}
+
 	// Update transaction record
 	var unitID interface{}
 	if req.UnitID != nil {
@@ -650,7 +711,7 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 	_, err = tx.Exec("UPDATE transactions SET transaction_date = ?, transaction_number = ?, memo = ? WHERE id = ?",
 		req.SalesDate, req.InvoiceNo, req.Description, existingInvoice.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update transaction: %v", err)
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
 	}
 
 	// Update invoice
@@ -671,26 +732,26 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 	_, err = tx.Exec("UPDATE invoices SET invoice_no = ?, sales_date = ?, due_date = ?, ar_account_id = ?, unit_id = ?, people_id = ?, amount = ?, description = ? WHERE id = ?",
 		req.InvoiceNo, req.SalesDate, req.DueDate, arAccountID, unitID, peopleID, req.Amount, req.Description, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update invoice: %v", err)
+		return nil, fmt.Errorf("failed to update invoice: %w", err)
 	}
 
 	// Soft delete existing invoice_items (set status='0')
 	_, err = tx.Exec("UPDATE invoice_items SET status = '0' WHERE invoice_id = ?", req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to soft delete invoice items: %v", err)
+		return nil, fmt.Errorf("failed to soft delete invoice items: %w", err)
 	}
 
 	// Soft delete existing splits (set status='0')
 	_, err = tx.Exec("UPDATE splits SET status = '0' WHERE transaction_id = ?", existingInvoice.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to soft delete splits: %v", err)
+		return nil, fmt.Errorf("failed to soft delete splits: %w", err)
 	}
 
 	// Recreate invoice items
 	for _, itemInput := range req.Items {
 		item, _, _, _, _, _, err := s.itemRepo.GetByID(itemInput.ItemID)
 		if err != nil {
-			return nil, fmt.Errorf("item %d not found: %v", itemInput.ItemID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("item %d", itemInput.ItemID), err)
 		}
 
 		// Calculate total using rate from input
@@ -757,7 +818,7 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 		_, err = tx.Exec("INSERT INTO invoice_items (invoice_id, item_id, item_name, previous_value, current_value, qty, rate, total, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
 			req.ID, itemInput.ItemID, item.Name, previousValue, currentValue, qty, rateStr, total, itemStatus)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create invoice item: %v", err)
+			return nil, fmt.Errorf("failed to create invoice item: %w", err)
 		}
 	}
 
@@ -778,7 +839,7 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 
 	splitPreviews, err := s.CalculateSplitsForInvoice(createReq, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Recreate splits within transaction
@@ -815,31 +876,47 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingInvoice.TransactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
+	err = outbox.Write(tx, existingInvoice.BuildingID, outbox.InvoiceUpdated, existingInvoice.ID, outbox.Document{
+		ID:            existingInvoice.ID,
+		TransactionID: existingInvoice.TransactionID,
+		Number:        req.InvoiceNo,
+		Date:          req.SalesDate,
+		Amount:        req.Amount,
+		PeopleID:      req.PeopleID,
+		UnitID:        req.UnitID,
+		Version:       req.ExpectedVersion + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("invoice", metrics.PostingUpdated)
+	s.logger.Info("invoice updated", "invoice_id", existingInvoice.ID, "transaction_id", existingInvoice.TransactionID)
 
 	// Fetch updated records after successful commit
 	updatedTransaction, err := s.transactionRepo.GetByID(existingInvoice.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	updatedInvoice, err := s.invoiceRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch invoice: %v", err)
+		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
 	}
 
 	// Get only active splits (status='1')
 	updatedSplits, err := s.splitRepo.GetByTransactionID(existingInvoice.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 	// Filter to only active splits
 	activeSplits := []splits.Split{}
@@ -852,7 +929,7 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 	// Get only active invoice items (status='1')
 	updatedInvoiceItems, err := s.invoiceItemRepo.GetByInvoiceID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch invoice items: %v", err)
+		return nil, fmt.Errorf("failed to fetch invoice items: %w", err)
 	}
 	// Filter to only active items
 	activeItems := []invoice_items.InvoiceItem{}
@@ -869,3 +946,11 @@ func (s *InvoiceService) UpdateInvoice(req UpdateInvoiceRequest, userID int) (*I
 		Transaction: updatedTransaction,
 	}, nil
 }
+
+func (s *InvoiceService) ListInvoices(buildingID int, params pagination.Params) (pagination.Page[InvoiceListItem], error) {
+	invoices, total, err := s.invoiceRepo.List(buildingID, params)
+	if err != nil {
+		return pagination.Page[InvoiceListItem]{}, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	return pagination.NewPage(invoices, total, params, invoiceSortKey), nil
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type ItemHandler struct {
@@ -19,19 +20,19 @@ func NewItemHandler(service *ItemService) *ItemHandler {
 func (h *ItemHandler) CreateItem(c *gin.Context) {
 	var item Item
 	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.CreateItem(item)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -42,7 +43,7 @@ func (h *ItemHandler) CreateItem(c *gin.Context) {
 func (h *ItemHandler) GetItems(c *gin.Context) {
 	items, err := h.service.GetAllItems()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -55,13 +56,13 @@ func (h *ItemHandler) GetItemsByBuilding(c *gin.Context) {
 	buildingID, err := strconv.Atoi(buildingIDStr)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
 		return
 	}
 
 	items, err := h.service.GetItemsByBuildingID(buildingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -78,17 +79,17 @@ func (h *ItemHandler) GetItem(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	item, err := h.service.GetItemByID(int(id))
 	if err != nil {
 		if err.Error() == "id does not exist" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			apperrors.Respond(c, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -105,25 +106,25 @@ func (h *ItemHandler) UpdateItem(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	var item Item
 	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.UpdateItem(id, item)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
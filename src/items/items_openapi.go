@@ -0,0 +1,11 @@
+package items
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"ItemHandler.GetItems":           {Summary: "List items of all buildings", Response: []ItemResponse{}},
+	"ItemHandler.GetItemsByBuilding": {Summary: "List a building's items", Response: []ItemResponse{}},
+	"ItemHandler.GetItem":            {Summary: "Get an item", Response: ItemResponse{}},
+	"ItemHandler.CreateItem":         {Summary: "Create an item", Request: Item{}, Response: Item{}},
+	"ItemHandler.UpdateItem":         {Summary: "Update an item", Request: Item{}, Response: Item{}},
+}
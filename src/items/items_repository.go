@@ -2,10 +2,10 @@ package items
 
 import (
 	"database/sql"
-	"fmt"
 	"strings"
 
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/building"
 )
 
@@ -33,7 +33,7 @@ func (r *itemRepo) Create(item Item) (Item, error) {
 
 	if err != nil {
 		if strings.Contains(err.Error(), "foreign key constraint") {
-			return item, fmt.Errorf("invalid foreign key reference")
+			return item, apperrors.Rule("invalid foreign key reference")
 		}
 		return item, err
 	}
@@ -54,7 +54,7 @@ func (r *itemRepo) Update(item Item, id int) (Item, error) {
 
 	if err != nil {
 		if strings.Contains(err.Error(), "foreign key constraint") {
-			return item, fmt.Errorf("invalid foreign key reference")
+			return item, apperrors.Rule("invalid foreign key reference")
 		}
 		return item, err
 	}
@@ -83,7 +83,7 @@ func (r *itemRepo) GetByID(id int) (Item, building.Building, *accounts.Account,
 			&b.ID, &b.Name, &b.CreatedAt, &b.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return item, b, nil, nil, nil, nil, fmt.Errorf("id does not exist")
+		return item, b, nil, nil, nil, nil, apperrors.NotFound("item")
 	}
 	if err != nil {
 		return item, b, nil, nil, nil, nil, err
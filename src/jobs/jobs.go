@@ -0,0 +1,113 @@
+// Package jobs runs recurring per-building tasks on cron schedules inside the application.
+//
+// Jobs are rows in scheduled_jobs. Any number of application instances may run a
+// Scheduler: a job is claimed through its lock columns before it runs, so each firing
+// runs once. Every execution, scheduled or triggered by hand, is recorded in job_runs
+// with its status and output. What a job does is decided by its type; packages register
+// their types in a Registry.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Job statuses
+const (
+	StatusActive = "active"
+	StatusPaused = "paused"
+)
+
+// Run statuses
+const (
+	RunQueued    = "queued"
+	RunRunning   = "running"
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	RunCancelled = "cancelled"
+)
+
+// What started a run
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+type Job struct {
+	ID            int             `json:"id"`
+	BuildingID    int             `json:"building_id"`
+	Name          string          `json:"name"`
+	JobType       string          `json:"job_type"`
+	Schedule      string          `json:"schedule"` // Five-field cron expression or a macro such as @daily
+	Timezone      string          `json:"timezone"` // IANA zone the schedule is read in
+	Params        json.RawMessage `json:"params"`
+	Status        string          `json:"status"`
+	NextRunAt     *string         `json:"next_run_at"` // UTC
+	LastRunAt     *string         `json:"last_run_at"`
+	LastRunStatus *string         `json:"last_run_status"`
+	LockedBy      *string         `json:"locked_by"` // Instance running the job, if any
+	LockedUntil   *string         `json:"locked_until"`
+	CreatedAt     string          `json:"created_at"`
+	UpdatedAt     string          `json:"updated_at"`
+}
+
+type Run struct {
+	ID              int     `json:"id"`
+	JobID           int     `json:"job_id"`
+	BuildingID      int     `json:"building_id"`
+	TriggeredBy     string  `json:"triggered_by"`
+	UserID          *int    `json:"user_id"`
+	Status          string  `json:"status"`
+	CancelRequested bool    `json:"cancel_requested"`
+	Runner          *string `json:"runner"`
+	Output          *string `json:"output,omitempty"` // Only returned when a single run is fetched
+	Error           *string `json:"error"`
+	QueuedAt        string  `json:"queued_at"`
+	StartedAt       *string `json:"started_at"`
+	FinishedAt      *string `json:"finished_at"`
+}
+
+// Func performs a job. ctx is cancelled when the run is cancelled or the application
+// shuts down. The returned output is stored with the run.
+type Func func(ctx context.Context, job Job) (string, error)
+
+// Type is a kind of job, e.g. a report snapshot
+type Type struct {
+	Name        string
+	Description string
+	// Validate checks a job's params and returns errors keyed by field; nil accepts any params
+	Validate func(params json.RawMessage) map[string]string
+	Run      Func
+}
+
+// Registry holds the job types the application can run
+type Registry struct {
+	types map[string]Type
+}
+
+func NewRegistry() *Registry {
+	return &Registry{types: map[string]Type{}}
+}
+
+// Register adds a job type; registering a name twice replaces the earlier type
+func (r *Registry) Register(t Type) {
+	r.types[t.Name] = t
+}
+
+func (r *Registry) Get(name string) (Type, bool) {
+	t, ok := r.types[name]
+	return t, ok
+}
+
+// Types returns the registered types sorted by name
+func (r *Registry) Types() []Type {
+	types := make([]Type, 0, len(r.types))
+	for _, t := range r.types {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
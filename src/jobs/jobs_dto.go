@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type CreateJobRequest struct {
+	Name     string          `json:"name" binding:"required"`
+	JobType  string          `json:"job_type" binding:"required"`
+	Schedule string          `json:"schedule" binding:"required"` // e.g. "0 6 1 * *" or "@daily"
+	Timezone string          `json:"timezone"`                    // IANA zone, UTC when empty
+	Params   json.RawMessage `json:"params"`                      // Depends on the job type
+}
+
+type UpdateJobRequest struct {
+	Name     string          `json:"name" binding:"required"`
+	Schedule string          `json:"schedule" binding:"required"`
+	Timezone string          `json:"timezone"`
+	Params   json.RawMessage `json:"params"`
+	Status   string          `json:"status" binding:"required"` // active or paused
+}
+
+type JobTypeInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// normalizeParams returns params as a JSON object, "{}" when omitted
+func normalizeParams(params json.RawMessage, errors map[string]string) json.RawMessage {
+	trimmed := strings.TrimSpace(string(params))
+	if trimmed == "" || trimmed == "null" {
+		return json.RawMessage("{}")
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(params, &object); err != nil {
+		errors["params"] = "Params must be a JSON object"
+	}
+	return json.RawMessage(trimmed)
+}
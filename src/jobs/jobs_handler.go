@@ -0,0 +1,257 @@
+package jobs
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+)
+
+type JobHandler struct {
+	service *JobService
+}
+
+func NewJobHandler(service *JobService) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+// GET /buildings/:id/jobs/types
+func (h *JobHandler) GetJobTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.ListJobTypes())
+}
+
+// GET /buildings/:id/jobs
+func (h *JobHandler) GetJobs(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	jobs, err := h.service.ListJobs(buildingID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GET /buildings/:id/jobs/:jobId
+func (h *JobHandler) GetJob(c *gin.Context) {
+	buildingID, id, ok := jobParams(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// POST /buildings/:id/jobs
+func (h *JobHandler) CreateJob(c *gin.Context) {
+	var req CreateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	job, validationErr, err := h.service.WithLogger(logging.FromGin(c)).CreateJob(buildingID, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// PUT /buildings/:id/jobs/:jobId
+func (h *JobHandler) UpdateJob(c *gin.Context) {
+	var req UpdateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, id, ok := jobParams(c)
+	if !ok {
+		return
+	}
+
+	job, validationErr, err := h.service.WithLogger(logging.FromGin(c)).UpdateJob(buildingID, id, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// DELETE /buildings/:id/jobs/:jobId
+func (h *JobHandler) DeleteJob(c *gin.Context) {
+	buildingID, id, ok := jobParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.WithLogger(logging.FromGin(c)).DeleteJob(buildingID, id); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job deleted successfully"})
+}
+
+// POST /buildings/:id/jobs/:jobId/run
+func (h *JobHandler) TriggerJob(c *gin.Context) {
+	buildingID, id, ok := jobParams(c)
+	if !ok {
+		return
+	}
+
+	userIDStr := c.GetHeader("User-ID")
+	if userIDStr == "" {
+		userIDStr = c.Query("user_id")
+	}
+	if userIDStr == "" {
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
+		return
+	}
+
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
+		return
+	}
+
+	run, err := h.service.WithLogger(logging.FromGin(c)).TriggerJob(buildingID, id, userID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// GET /buildings/:id/job-runs?job_id=1&status=failed
+func (h *JobHandler) GetRuns(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	params, validationErrors := RunListSpec.Parse(c.Request.URL.Query())
+	if validationErrors == nil {
+		validationErrors = map[string]string{}
+	}
+	switch params.Filters.Status {
+	case "", RunQueued, RunRunning, RunSucceeded, RunFailed, RunCancelled:
+	default:
+		validationErrors["status"] = "Status must be queued, running, succeeded, failed or cancelled"
+	}
+	var jobID *int
+	if value := c.Query("job_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			validationErrors["job_id"] = "Job ID must be a positive integer"
+		} else {
+			jobID = &id
+		}
+	}
+	if len(validationErrors) > 0 {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
+		return
+	}
+
+	runs, err := h.service.ListRuns(buildingID, jobID, params)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// GET /buildings/:id/job-runs/:runId
+func (h *JobHandler) GetRun(c *gin.Context) {
+	buildingID, id, ok := runParams(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.service.GetRun(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// POST /buildings/:id/job-runs/:runId/cancel
+func (h *JobHandler) CancelRun(c *gin.Context) {
+	buildingID, id, ok := runParams(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.service.WithLogger(logging.FromGin(c)).CancelRun(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// jobParams reads the building and job ids, responding when either is invalid
+func jobParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("jobId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Job ID"))
+		return 0, 0, false
+	}
+	return buildingID, id, true
+}
+
+// runParams reads the building and run ids, responding when either is invalid
+func runParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("runId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Run ID"))
+		return 0, 0, false
+	}
+	return buildingID, id, true
+}
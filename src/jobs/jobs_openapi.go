@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"JobHandler.GetJobTypes": {Summary: "List the job types that can be scheduled", Response: []JobTypeInfo{}},
+	"JobHandler.GetJobs":     {Summary: "List scheduled jobs", Response: []Job{}},
+	"JobHandler.GetJob":      {Summary: "Get a scheduled job", Response: Job{}},
+	"JobHandler.CreateJob": {
+		Summary:     "Schedule a recurring job",
+		Description: "The schedule is a five-field cron expression (minute hour day-of-month month day-of-week) or a macro such as @daily, read in the job's timezone.",
+		Request:     CreateJobRequest{},
+		Response:    Job{},
+	},
+	"JobHandler.UpdateJob": {
+		Summary:     "Update, pause or resume a scheduled job",
+		Description: "Resuming a paused job schedules its next firing from now; firings missed while paused are not run.",
+		Request:     UpdateJobRequest{},
+		Response:    Job{},
+	},
+	"JobHandler.DeleteJob": {Summary: "Delete a scheduled job and its run history", Response: openapi.Message{}},
+	"JobHandler.TriggerJob": {
+		Summary:     "Queue a run of a job now",
+		Description: "The run starts on the scheduler's next poll, whether the job is active or paused.",
+		Response:    Run{},
+		UserID:      true,
+	},
+	"JobHandler.GetRuns": {
+		Summary:  "List job runs",
+		Response: pagination.Page[Run]{},
+		List:     &RunListSpec,
+		Query: []openapi.Param{
+			{Name: "job_id", Type: "integer", Format: "int32", Description: "Only runs of this job"},
+		},
+	},
+	"JobHandler.GetRun": {Summary: "Get a job run with its output", Response: Run{}},
+	"JobHandler.CancelRun": {
+		Summary:     "Cancel a job run",
+		Description: "A queued run is cancelled at once; a running run is stopped by the instance running it shortly after.",
+		Response:    Run{},
+	},
+}
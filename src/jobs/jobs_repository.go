@@ -0,0 +1,348 @@
+package jobs
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+type JobRepository interface {
+	Create(job Job) (Job, error)
+	GetByID(buildingID int, id int) (Job, error)
+	List(buildingID int) ([]Job, error)
+	Update(job Job) error
+	Delete(buildingID int, id int) error
+	Due(now time.Time, limit int) ([]Job, error)
+	ClaimScheduled(id int, runner string, now time.Time, lockUntil time.Time, nextRunAt time.Time) (bool, error)
+	Lock(id int, runner string, now time.Time, lockUntil time.Time) (bool, error)
+	ExtendLock(id int, runner string, lockUntil time.Time) error
+	Unlock(id int, runner string) error
+	RecordLastRun(id int, runner string, finishedAt time.Time, status string) error
+
+	CreateRun(run Run) (Run, error)
+	GetRun(buildingID int, id int) (Run, error)
+	ListRuns(buildingID int, jobID *int, params pagination.Params) ([]Run, int, error)
+	OpenRun(jobID int) (Run, error)
+	QueuedRuns(limit int) ([]Run, error)
+	StartRun(id int, runner string, now time.Time) (bool, error)
+	FinishRun(id int, status string, output string, errorMessage *string, now time.Time) error
+	CancelQueuedRun(id int, now time.Time) (bool, error)
+	RequestCancel(id int) error
+	CancelRequested(id int) (bool, error)
+	FailAbandonedRuns(now time.Time) (int64, error)
+}
+
+type jobRepo struct {
+	db *sql.DB
+}
+
+func NewJobRepository(db *sql.DB) JobRepository {
+	return &jobRepo{db: db}
+}
+
+const jobColumns = "id, building_id, name, job_type, schedule, timezone, params, status, next_run_at, last_run_at, last_run_status, locked_by, locked_until, created_at, updated_at"
+
+// Run lists leave out the output, which can be large
+const runListColumns = "id, job_id, building_id, triggered_by, user_id, status, cancel_requested, runner, NULL, error, queued_at, started_at, finished_at"
+
+const runColumns = "id, job_id, building_id, triggered_by, user_id, status, cancel_requested, runner, output, error, queued_at, started_at, finished_at"
+
+// RunListSpec describes the query string of the run history
+var RunListSpec = pagination.Spec{
+	IDColumn: "id",
+	Sorts: map[string]string{
+		"id":   "id",
+		"date": "queued_at",
+	},
+	DefaultSort:  "-id",
+	DateColumn:   "queued_at",
+	StatusFilter: "status = ?",
+}
+
+// RunSortKey returns the cursor values of a run for the list's sort
+func RunSortKey(run Run, sort string) (interface{}, int) {
+	if sort == "date" {
+		return run.QueuedAt, run.ID
+	}
+	return run.ID, run.ID
+}
+
+func (r *jobRepo) Create(job Job) (Job, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO scheduled_jobs (building_id, name, job_type, schedule, timezone, params, status, next_run_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		job.BuildingID, job.Name, job.JobType, job.Schedule, job.Timezone, string(job.Params), job.Status, job.NextRunAt,
+	)
+	if err != nil {
+		return Job{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Job{}, err
+	}
+	return r.GetByID(job.BuildingID, int(id))
+}
+
+func (r *jobRepo) GetByID(buildingID int, id int) (Job, error) {
+	return scanJob(r.db.QueryRow("SELECT "+jobColumns+" FROM scheduled_jobs WHERE id = ? AND building_id = ?", id, buildingID))
+}
+
+func (r *jobRepo) List(buildingID int) ([]Job, error) {
+	return r.queryJobs("SELECT "+jobColumns+" FROM scheduled_jobs WHERE building_id = ? ORDER BY id", buildingID)
+}
+
+func (r *jobRepo) Update(job Job) error {
+	_, err := r.db.Exec(
+		"UPDATE scheduled_jobs SET name = ?, schedule = ?, timezone = ?, params = ?, status = ?, next_run_at = ?, updated_at = ? WHERE id = ? AND building_id = ?",
+		job.Name, job.Schedule, job.Timezone, string(job.Params), job.Status, job.NextRunAt,
+		time.Now().UTC().Format(timeLayout), job.ID, job.BuildingID,
+	)
+	return err
+}
+
+// Delete removes a job and its run history
+func (r *jobRepo) Delete(buildingID int, id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := tx.Exec("DELETE FROM job_runs WHERE job_id = ? AND building_id = ?", id, buildingID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM scheduled_jobs WHERE id = ? AND building_id = ?", id, buildingID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// Due returns active jobs whose next run has come and that no instance holds
+func (r *jobRepo) Due(now time.Time, limit int) ([]Job, error) {
+	nowStr := now.UTC().Format(timeLayout)
+	return r.queryJobs(
+		"SELECT "+jobColumns+" FROM scheduled_jobs WHERE status = ? AND next_run_at <= ? AND (locked_until IS NULL OR locked_until < ?) ORDER BY next_run_at, id LIMIT ?",
+		StatusActive, nowStr, nowStr, limit,
+	)
+}
+
+// ClaimScheduled locks a due job for runner and moves it to its next run in one
+// statement, so only one instance runs each firing
+func (r *jobRepo) ClaimScheduled(id int, runner string, now time.Time, lockUntil time.Time, nextRunAt time.Time) (bool, error) {
+	nowStr := now.UTC().Format(timeLayout)
+	result, err := r.db.Exec(
+		"UPDATE scheduled_jobs SET locked_by = ?, locked_until = ?, next_run_at = ? WHERE id = ? AND status = ? AND next_run_at <= ? AND (locked_until IS NULL OR locked_until < ?)",
+		runner, lockUntil.UTC().Format(timeLayout), nextRunAt.UTC().Format(timeLayout), id, StatusActive, nowStr, nowStr,
+	)
+	return claimed(result, err)
+}
+
+// Lock takes a job's lock for runner without touching its schedule, for manual runs
+func (r *jobRepo) Lock(id int, runner string, now time.Time, lockUntil time.Time) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE scheduled_jobs SET locked_by = ?, locked_until = ? WHERE id = ? AND (locked_until IS NULL OR locked_until < ?)",
+		runner, lockUntil.UTC().Format(timeLayout), id, now.UTC().Format(timeLayout),
+	)
+	return claimed(result, err)
+}
+
+func (r *jobRepo) ExtendLock(id int, runner string, lockUntil time.Time) error {
+	_, err := r.db.Exec("UPDATE scheduled_jobs SET locked_until = ? WHERE id = ? AND locked_by = ?", lockUntil.UTC().Format(timeLayout), id, runner)
+	return err
+}
+
+func (r *jobRepo) Unlock(id int, runner string) error {
+	_, err := r.db.Exec("UPDATE scheduled_jobs SET locked_by = NULL, locked_until = NULL WHERE id = ? AND locked_by = ?", id, runner)
+	return err
+}
+
+// RecordLastRun releases the job and records how its run ended
+func (r *jobRepo) RecordLastRun(id int, runner string, finishedAt time.Time, status string) error {
+	_, err := r.db.Exec(
+		"UPDATE scheduled_jobs SET locked_by = NULL, locked_until = NULL, last_run_at = ?, last_run_status = ? WHERE id = ? AND locked_by = ?",
+		finishedAt.UTC().Format(timeLayout), status, id, runner,
+	)
+	return err
+}
+
+func (r *jobRepo) CreateRun(run Run) (Run, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO job_runs (job_id, building_id, triggered_by, user_id, status, runner, queued_at, started_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		run.JobID, run.BuildingID, run.TriggeredBy, run.UserID, run.Status, run.Runner, run.QueuedAt, run.StartedAt,
+	)
+	if err != nil {
+		return Run{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Run{}, err
+	}
+	return r.GetRun(run.BuildingID, int(id))
+}
+
+func (r *jobRepo) GetRun(buildingID int, id int) (Run, error) {
+	return scanRun(r.db.QueryRow("SELECT "+runColumns+" FROM job_runs WHERE id = ? AND building_id = ?", id, buildingID))
+}
+
+func (r *jobRepo) ListRuns(buildingID int, jobID *int, params pagination.Params) ([]Run, int, error) {
+	where, args := RunListSpec.Where(params)
+	where = " WHERE building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+	if jobID != nil {
+		where += " AND job_id = ?"
+		args = append(args, *jobID)
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM job_runs"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := RunListSpec.After(params)
+	order, orderArgs := RunListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	runs, err := r.queryRuns("SELECT "+runListColumns+" FROM job_runs"+where+after+order, args...)
+	return runs, total, err
+}
+
+// OpenRun returns the job's queued or running run, or sql.ErrNoRows
+func (r *jobRepo) OpenRun(jobID int) (Run, error) {
+	return scanRun(r.db.QueryRow(
+		"SELECT "+runListColumns+" FROM job_runs WHERE job_id = ? AND status IN (?, ?) ORDER BY id LIMIT 1",
+		jobID, RunQueued, RunRunning,
+	))
+}
+
+func (r *jobRepo) QueuedRuns(limit int) ([]Run, error) {
+	return r.queryRuns("SELECT "+runListColumns+" FROM job_runs WHERE status = ? ORDER BY id LIMIT ?", RunQueued, limit)
+}
+
+// StartRun moves a queued run to running; it returns false if the run left the queue meanwhile
+func (r *jobRepo) StartRun(id int, runner string, now time.Time) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE job_runs SET status = ?, runner = ?, started_at = ? WHERE id = ? AND status = ?",
+		RunRunning, runner, now.UTC().Format(timeLayout), id, RunQueued,
+	)
+	return claimed(result, err)
+}
+
+func (r *jobRepo) FinishRun(id int, status string, output string, errorMessage *string, now time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE job_runs SET status = ?, output = ?, error = ?, finished_at = ? WHERE id = ?",
+		status, output, errorMessage, now.UTC().Format(timeLayout), id,
+	)
+	return err
+}
+
+func (r *jobRepo) CancelQueuedRun(id int, now time.Time) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE job_runs SET status = ?, cancel_requested = 1, finished_at = ? WHERE id = ? AND status = ?",
+		RunCancelled, now.UTC().Format(timeLayout), id, RunQueued,
+	)
+	return claimed(result, err)
+}
+
+// RequestCancel asks the instance running a run to stop it
+func (r *jobRepo) RequestCancel(id int) error {
+	_, err := r.db.Exec("UPDATE job_runs SET cancel_requested = 1 WHERE id = ? AND status = ?", id, RunRunning)
+	return err
+}
+
+func (r *jobRepo) CancelRequested(id int) (bool, error) {
+	var requested bool
+	err := r.db.QueryRow("SELECT cancel_requested FROM job_runs WHERE id = ?", id).Scan(&requested)
+	return requested, err
+}
+
+// FailAbandonedRuns fails running runs whose runner no longer holds the job's lock, e.g.
+// because its instance stopped mid-run
+func (r *jobRepo) FailAbandonedRuns(now time.Time) (int64, error) {
+	nowStr := now.UTC().Format(timeLayout)
+	result, err := r.db.Exec(
+		"UPDATE job_runs SET status = ?, error = ?, finished_at = ? WHERE status = ? AND NOT EXISTS "+
+			"(SELECT 1 FROM scheduled_jobs j WHERE j.id = job_runs.job_id AND j.locked_by = job_runs.runner AND j.locked_until >= ?)",
+		RunFailed, "The instance running the job stopped before the run finished", nowStr, RunRunning, nowStr,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *jobRepo) queryJobs(query string, args ...interface{}) ([]Job, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *jobRepo) queryRuns(query string, args ...interface{}) ([]Run, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []Run{}
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func claimed(result sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row scanner) (Job, error) {
+	var job Job
+	var params string
+	err := row.Scan(&job.ID, &job.BuildingID, &job.Name, &job.JobType, &job.Schedule, &job.Timezone, &params, &job.Status,
+		&job.NextRunAt, &job.LastRunAt, &job.LastRunStatus, &job.LockedBy, &job.LockedUntil, &job.CreatedAt, &job.UpdatedAt)
+	job.Params = []byte(params)
+	return job, err
+}
+
+func scanRun(row scanner) (Run, error) {
+	var run Run
+	err := row.Scan(&run.ID, &run.JobID, &run.BuildingID, &run.TriggeredBy, &run.UserID, &run.Status, &run.CancelRequested,
+		&run.Runner, &run.Output, &run.Error, &run.QueuedAt, &run.StartedAt, &run.FinishedAt)
+	return run, err
+}
@@ -0,0 +1,288 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// batchSize bounds the jobs claimed and the queued runs started per poll
+const batchSize = 20
+
+// errShutdown is the cancellation cause of runs stopped because the application is shutting down
+var errShutdown = errors.New("application shut down")
+
+// errCancelled is the cancellation cause of runs cancelled through the API
+var errCancelled = errors.New("cancelled on request")
+
+// Scheduler runs due jobs and queued manual runs. Every application instance can run
+// one: a job is locked by the instance running it, and the lock is extended while the
+// run lasts, so a firing never runs twice and a crashed instance's lock expires.
+type Scheduler struct {
+	repo         JobRepository
+	registry     *Registry
+	logger       *slog.Logger
+	runner       string
+	pollInterval time.Duration
+	lockTimeout  time.Duration
+	wg           sync.WaitGroup
+}
+
+func NewScheduler(repo JobRepository, registry *Registry, logger *slog.Logger, pollInterval time.Duration, lockTimeout time.Duration) *Scheduler {
+	return &Scheduler{
+		repo:         repo,
+		registry:     registry,
+		logger:       logger,
+		runner:       runnerID(),
+		pollInterval: pollInterval,
+		lockTimeout:  lockTimeout,
+	}
+}
+
+// Run polls for work every poll interval until ctx is cancelled. Runs in progress are
+// then cancelled, and Run returns once they have been recorded.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.logger.Info("job scheduler started", "runner", s.runner)
+	for {
+		s.PollOnce(ctx)
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			s.logger.Info("job scheduler stopped", "runner", s.runner)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollOnce fails runs abandoned by stopped instances, then starts due jobs and queued runs
+func (s *Scheduler) PollOnce(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	if failed, err := s.repo.FailAbandonedRuns(now); err != nil {
+		s.logger.Error("failed to recover abandoned job runs", "error", err)
+	} else if failed > 0 {
+		s.logger.Warn("abandoned job runs marked failed", "count", failed)
+	}
+
+	s.startDueJobs(ctx, now)
+	s.startQueuedRuns(ctx, now)
+}
+
+func (s *Scheduler) startDueJobs(ctx context.Context, now time.Time) {
+	due, err := s.repo.Due(now, batchSize)
+	if err != nil {
+		s.logger.Error("failed to load due jobs", "error", err)
+		return
+	}
+
+	for _, job := range due {
+		// A schedule that no longer parses stops the job rather than retrying it every poll
+		next, err := NextRun(job.Schedule, job.Timezone, now)
+		if err != nil {
+			s.logger.Error("job has an invalid schedule", "job_id", job.ID, "error", err)
+			continue
+		}
+		claimed, err := s.repo.ClaimScheduled(job.ID, s.runner, now, now.Add(s.lockTimeout), next)
+		if err != nil {
+			s.logger.Error("failed to claim job", "job_id", job.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			// Another instance took it
+			continue
+		}
+
+		startedAt := now.UTC().Format(timeLayout)
+		run, err := s.repo.CreateRun(Run{
+			JobID:       job.ID,
+			BuildingID:  job.BuildingID,
+			TriggeredBy: TriggerSchedule,
+			Status:      RunRunning,
+			Runner:      &s.runner,
+			QueuedAt:    startedAt,
+			StartedAt:   &startedAt,
+		})
+		if err != nil {
+			s.logger.Error("failed to record job run", "job_id", job.ID, "error", err)
+			s.unlock(job.ID)
+			continue
+		}
+		s.start(ctx, job, run)
+	}
+}
+
+func (s *Scheduler) startQueuedRuns(ctx context.Context, now time.Time) {
+	queued, err := s.repo.QueuedRuns(batchSize)
+	if err != nil {
+		s.logger.Error("failed to load queued job runs", "error", err)
+		return
+	}
+
+	for _, run := range queued {
+		// The job's lock keeps a manual run from overlapping a scheduled one; a locked
+		// job's run waits for a later poll
+		locked, err := s.repo.Lock(run.JobID, s.runner, now, now.Add(s.lockTimeout))
+		if err != nil {
+			s.logger.Error("failed to lock job", "job_id", run.JobID, "error", err)
+			continue
+		}
+		if !locked {
+			continue
+		}
+
+		started, err := s.repo.StartRun(run.ID, s.runner, now)
+		if err != nil || !started {
+			// Cancelled or deleted meanwhile
+			if err != nil {
+				s.logger.Error("failed to start job run", "run_id", run.ID, "error", err)
+			}
+			s.unlock(run.JobID)
+			continue
+		}
+
+		job, err := s.repo.GetByID(run.BuildingID, run.JobID)
+		if err != nil {
+			s.logger.Error("failed to load job", "job_id", run.JobID, "error", err)
+			s.finish(job, run, RunFailed, "", fmt.Errorf("failed to load job: %w", err))
+			continue
+		}
+		s.start(ctx, job, run)
+	}
+}
+
+// start executes a claimed run in its own goroutine
+func (s *Scheduler) start(ctx context.Context, job Job, run Run) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(ctx, job, run)
+	}()
+}
+
+func (s *Scheduler) execute(ctx context.Context, job Job, run Run) {
+	logger := s.logger.With("job_id", job.ID, "run_id", run.ID, "job_type", job.JobType, "building_id", job.BuildingID)
+	jobType, ok := s.registry.Get(job.JobType)
+	if !ok {
+		s.finish(job, run, RunFailed, "", fmt.Errorf("unknown job type '%s'", job.JobType))
+		return
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	heartbeatDone := make(chan struct{})
+	defer close(heartbeatDone)
+	go s.heartbeat(runCtx, cancel, job.ID, run.ID, heartbeatDone, logger)
+
+	logger.Info("job run started", "triggered_by", run.TriggeredBy)
+	started := time.Now()
+	output, err := safeRun(runCtx, jobType.Run, job)
+
+	status := RunSucceeded
+	switch {
+	case runCtx.Err() != nil:
+		status = RunCancelled
+		err = context.Cause(runCtx)
+		if errors.Is(err, context.Canceled) {
+			err = errShutdown
+		}
+	case err != nil:
+		status = RunFailed
+	}
+
+	logger.Info("job run finished", "status", status, "duration_ms", time.Since(started).Milliseconds(), "error", errorString(err))
+	s.finish(job, run, status, output, err)
+}
+
+// heartbeat extends the job's lock while the run lasts and cancels the run when a
+// cancellation is requested
+func (s *Scheduler) heartbeat(ctx context.Context, cancel context.CancelCauseFunc, jobID int, runID int, done <-chan struct{}, logger *slog.Logger) {
+	ticker := time.NewTicker(s.lockTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.repo.ExtendLock(jobID, s.runner, time.Now().Add(s.lockTimeout)); err != nil {
+			logger.Error("failed to extend job lock", "error", err)
+		}
+		requested, err := s.repo.CancelRequested(runID)
+		if err != nil {
+			logger.Error("failed to check job run cancellation", "error", err)
+			continue
+		}
+		if requested {
+			cancel(errCancelled)
+			return
+		}
+	}
+}
+
+// finish records the run's outcome and releases the job. It does not use the scheduler's
+// context, so runs stopped by a shutdown are still recorded.
+func (s *Scheduler) finish(job Job, run Run, status string, output string, runErr error) {
+	now := time.Now()
+	var errorMessage *string
+	if runErr != nil {
+		message := runErr.Error()
+		errorMessage = &message
+	}
+	if err := s.repo.FinishRun(run.ID, status, output, errorMessage, now); err != nil {
+		s.logger.Error("failed to record job run result", "run_id", run.ID, "error", err)
+	}
+	if err := s.repo.RecordLastRun(run.JobID, s.runner, now, status); err != nil {
+		s.logger.Error("failed to release job", "job_id", run.JobID, "error", err)
+	}
+}
+
+func (s *Scheduler) unlock(jobID int) {
+	if err := s.repo.Unlock(jobID, s.runner); err != nil {
+		s.logger.Error("failed to release job", "job_id", jobID, "error", err)
+	}
+}
+
+// safeRun calls run, turning a panic into an error so one bad job cannot stop the scheduler
+func safeRun(ctx context.Context, run Func, job Job) (output string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return run(ctx, job)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// runnerID identifies this instance in job locks and runs
+func runnerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
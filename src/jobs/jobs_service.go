@@ -0,0 +1,293 @@
+package jobs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+type JobService struct {
+	repo     JobRepository
+	registry *Registry
+	logger   *slog.Logger
+}
+
+func NewJobService(repo JobRepository, registry *Registry, logger *slog.Logger) *JobService {
+	return &JobService{repo: repo, registry: registry, logger: logger}
+}
+
+// WithLogger returns a copy of the service that logs to logger, e.g. the request's logger
+func (s *JobService) WithLogger(logger *slog.Logger) *JobService {
+	copy := *s
+	copy.logger = logger
+	return &copy
+}
+
+func (s *JobService) ListJobTypes() []JobTypeInfo {
+	types := []JobTypeInfo{}
+	for _, t := range s.registry.Types() {
+		types = append(types, JobTypeInfo{Name: t.Name, Description: t.Description})
+	}
+	return types
+}
+
+func (s *JobService) ListJobs(buildingID int) ([]Job, error) {
+	jobs, err := s.repo.List(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (s *JobService) GetJob(buildingID int, id int) (Job, error) {
+	job, err := s.repo.GetByID(buildingID, id)
+	if err != nil {
+		return Job{}, apperrors.Lookup("job", err)
+	}
+	return job, nil
+}
+
+func (s *JobService) CreateJob(buildingID int, req CreateJobRequest) (*Job, map[string]string, error) {
+	validationErrors := map[string]string{}
+	if strings.TrimSpace(req.Name) == "" {
+		validationErrors["name"] = "Name is required"
+	}
+	jobType, ok := s.registry.Get(req.JobType)
+	if !ok {
+		validationErrors["job_type"] = "Unknown job type"
+	}
+	timezone := timezoneOrUTC(req.Timezone)
+	nextRunAt := validateSchedule(req.Schedule, timezone, validationErrors)
+	params := validateParams(jobType, ok, req.Params, validationErrors)
+	if len(validationErrors) > 0 {
+		return nil, validationErrors, nil
+	}
+
+	job, err := s.repo.Create(Job{
+		BuildingID: buildingID,
+		Name:       strings.TrimSpace(req.Name),
+		JobType:    req.JobType,
+		Schedule:   strings.TrimSpace(req.Schedule),
+		Timezone:   timezone,
+		Params:     params,
+		Status:     StatusActive,
+		NextRunAt:  nextRunAt,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	s.logger.Info("job created", "job_id", job.ID, "job_type", job.JobType, "next_run_at", *job.NextRunAt)
+	return &job, nil, nil
+}
+
+// UpdateJob changes a job's schedule or params, or pauses and resumes it. A paused job
+// has no next run; resuming schedules the next firing from now.
+func (s *JobService) UpdateJob(buildingID int, id int, req UpdateJobRequest) (*Job, map[string]string, error) {
+	job, err := s.GetJob(buildingID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validationErrors := map[string]string{}
+	if strings.TrimSpace(req.Name) == "" {
+		validationErrors["name"] = "Name is required"
+	}
+	if req.Status != StatusActive && req.Status != StatusPaused {
+		validationErrors["status"] = "Status must be active or paused"
+	}
+	jobType, ok := s.registry.Get(job.JobType)
+	if !ok {
+		validationErrors["job_type"] = "The job's type is no longer available"
+	}
+	timezone := timezoneOrUTC(req.Timezone)
+	nextRunAt := validateSchedule(req.Schedule, timezone, validationErrors)
+	params := validateParams(jobType, ok, req.Params, validationErrors)
+	if len(validationErrors) > 0 {
+		return nil, validationErrors, nil
+	}
+
+	job.Name = strings.TrimSpace(req.Name)
+	job.Schedule = strings.TrimSpace(req.Schedule)
+	job.Timezone = timezone
+	job.Params = params
+	job.Status = req.Status
+	job.NextRunAt = nextRunAt
+	if job.Status == StatusPaused {
+		job.NextRunAt = nil
+	}
+
+	if err := s.repo.Update(job); err != nil {
+		return nil, nil, fmt.Errorf("failed to update job: %w", err)
+	}
+
+	updated, err := s.GetJob(buildingID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.logger.Info("job updated", "job_id", id, "status", updated.Status)
+	return &updated, nil, nil
+}
+
+// DeleteJob removes a job and its run history. A job cannot be deleted while it runs.
+func (s *JobService) DeleteJob(buildingID int, id int) error {
+	if _, err := s.GetJob(buildingID, id); err != nil {
+		return err
+	}
+	open, err := s.openRun(id)
+	if err != nil {
+		return err
+	}
+	if open != nil && open.Status == RunRunning {
+		return apperrors.Conflictf("Run %d of this job is in progress; cancel it before deleting the job", open.ID)
+	}
+
+	if err := s.repo.Delete(buildingID, id); err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+
+	s.logger.Info("job deleted", "job_id", id)
+	return nil
+}
+
+// TriggerJob queues a run of the job now, regardless of its schedule or status. A
+// scheduler picks the run up on its next poll.
+func (s *JobService) TriggerJob(buildingID int, id int, userID int) (Run, error) {
+	job, err := s.GetJob(buildingID, id)
+	if err != nil {
+		return Run{}, err
+	}
+	if _, ok := s.registry.Get(job.JobType); !ok {
+		return Run{}, apperrors.Rulef("Job type '%s' is no longer available", job.JobType)
+	}
+	open, err := s.openRun(id)
+	if err != nil {
+		return Run{}, err
+	}
+	if open != nil {
+		return Run{}, apperrors.Conflictf("Run %d of this job is already %s", open.ID, open.Status)
+	}
+
+	run, err := s.repo.CreateRun(Run{
+		JobID:       job.ID,
+		BuildingID:  buildingID,
+		TriggeredBy: TriggerManual,
+		UserID:      &userID,
+		Status:      RunQueued,
+		QueuedAt:    time.Now().UTC().Format(timeLayout),
+	})
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to queue job run: %w", err)
+	}
+
+	s.logger.Info("job run queued", "job_id", id, "run_id", run.ID)
+	return run, nil
+}
+
+func (s *JobService) ListRuns(buildingID int, jobID *int, params pagination.Params) (pagination.Page[Run], error) {
+	runs, total, err := s.repo.ListRuns(buildingID, jobID, params)
+	if err != nil {
+		return pagination.Page[Run]{}, fmt.Errorf("failed to list job runs: %w", err)
+	}
+	return pagination.NewPage(runs, total, params, RunSortKey), nil
+}
+
+func (s *JobService) GetRun(buildingID int, id int) (Run, error) {
+	run, err := s.repo.GetRun(buildingID, id)
+	if err != nil {
+		return Run{}, apperrors.Lookup("job run", err)
+	}
+	return run, nil
+}
+
+// CancelRun cancels a queued run at once. A running run is asked to stop; the instance
+// running it stops it within a heartbeat and records it as cancelled.
+func (s *JobService) CancelRun(buildingID int, id int) (Run, error) {
+	run, err := s.GetRun(buildingID, id)
+	if err != nil {
+		return Run{}, err
+	}
+
+	switch run.Status {
+	case RunQueued:
+		cancelled, err := s.repo.CancelQueuedRun(id, time.Now())
+		if err != nil {
+			return Run{}, fmt.Errorf("failed to cancel job run: %w", err)
+		}
+		if !cancelled {
+			// A scheduler started it meanwhile
+			if err := s.repo.RequestCancel(id); err != nil {
+				return Run{}, fmt.Errorf("failed to cancel job run: %w", err)
+			}
+		}
+	case RunRunning:
+		if err := s.repo.RequestCancel(id); err != nil {
+			return Run{}, fmt.Errorf("failed to cancel job run: %w", err)
+		}
+	default:
+		return Run{}, apperrors.Rulef("Run %d has already finished as %s", id, run.Status)
+	}
+
+	s.logger.Info("job run cancellation requested", "run_id", id, "job_id", run.JobID)
+	return s.GetRun(buildingID, id)
+}
+
+// openRun returns the job's queued or running run, or nil
+func (s *JobService) openRun(jobID int) (*Run, error) {
+	run, err := s.repo.OpenRun(jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check job runs: %w", err)
+	}
+	return &run, nil
+}
+
+func timezoneOrUTC(timezone string) string {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		return "UTC"
+	}
+	return timezone
+}
+
+// validateSchedule returns the schedule's next firing after now
+func validateSchedule(schedule string, timezone string, validationErrors map[string]string) *string {
+	_, scheduleErr := ParseSchedule(schedule)
+	if scheduleErr != nil {
+		validationErrors["schedule"] = "Invalid schedule: " + scheduleErr.Error()
+	}
+	_, timezoneErr := time.LoadLocation(timezone)
+	if timezoneErr != nil {
+		validationErrors["timezone"] = fmt.Sprintf("Unknown timezone '%s'", timezone)
+	}
+	if scheduleErr != nil || timezoneErr != nil {
+		return nil
+	}
+	next, err := NextRun(schedule, timezone, time.Now())
+	if err != nil {
+		validationErrors["schedule"] = "Invalid schedule: " + err.Error()
+		return nil
+	}
+	nextStr := next.Format(timeLayout)
+	return &nextStr
+}
+
+func validateParams(jobType Type, known bool, params []byte, validationErrors map[string]string) []byte {
+	normalized := normalizeParams(params, validationErrors)
+	if !known || jobType.Validate == nil || validationErrors["params"] != "" {
+		return normalized
+	}
+	for field, message := range jobType.Validate(normalized) {
+		validationErrors["params."+field] = message
+	}
+	return normalized
+}
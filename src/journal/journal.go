@@ -12,6 +12,7 @@ type Journal struct {
 	BuildingID    int     `json:"building_id"`
 	Memo          *string `json:"memo"`
 	TotalAmount   float64 `json:"total_amount"`
+	Version       int     `json:"version"`
 	CreatedAt     string  `json:"created_at"`
 }
 
@@ -50,6 +50,9 @@ type UpdateJournalRequest struct {
 	Memo        *string            `json:"memo"`
 	TotalAmount float64            `json:"total_amount"`
 	Lines       []JournalLineInput `json:"lines"`
+
+	// Version from the If-Match header; the update fails if the document has moved on
+	ExpectedVersion int `json:"-"`
 }
 
 type JournalResponse struct {
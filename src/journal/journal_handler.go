@@ -5,6 +5,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type JournalHandler struct {
@@ -19,7 +22,7 @@ func NewJournalHandler(service *JournalService) *JournalHandler {
 func (h *JournalHandler) PreviewJournal(c *gin.Context) {
 	var req CreateJournalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -38,19 +41,19 @@ func (h *JournalHandler) PreviewJournal(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
 	preview, err := h.service.PreviewJournal(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -61,7 +64,7 @@ func (h *JournalHandler) PreviewJournal(c *gin.Context) {
 func (h *JournalHandler) CreateJournal(c *gin.Context) {
 	var req CreateJournalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -80,19 +83,19 @@ func (h *JournalHandler) CreateJournal(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.CreateJournal(req, userID)
+	response, err := h.service.WithLogger(logging.FromGin(c)).CreateJournal(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -101,16 +104,21 @@ func (h *JournalHandler) CreateJournal(c *gin.Context) {
 
 // PUT /journals/:id or /buildings/:id/journals/:journalId
 func (h *JournalHandler) UpdateJournal(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateJournalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	journalIDStr := c.Param("journalId")
 	id, err := strconv.Atoi(journalIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Journal ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Journal ID"))
 		return
 	}
 	req.ID = id
@@ -128,22 +136,25 @@ func (h *JournalHandler) UpdateJournal(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.UpdateJournal(req, userID)
+	req.ExpectedVersion = version
+	response, err := h.service.WithLogger(logging.FromGin(c)).UpdateJournal(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Journal.Version)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -154,19 +165,25 @@ func (h *JournalHandler) GetJournals(c *gin.Context) {
 		buildingIDStr = c.Query("building_id")
 	}
 	if buildingIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	params, validationErrors := journalListSpec.Parse(c.Request.URL.Query())
+	if validationErrors != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
 		return
 	}
 
-	journals, err := h.service.GetJournals(buildingID)
+	journals, err := h.service.GetJournals(buildingID, params)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -180,22 +197,23 @@ func (h *JournalHandler) GetJournal(c *gin.Context) {
 		journalIDStr = c.Param("id")
 	}
 	if journalIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Journal ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Journal ID is required"))
 		return
 	}
 
 	id, err := strconv.Atoi(journalIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Journal ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Journal ID"))
 		return
 	}
 
 	journalResponse, err := h.service.GetJournalDetails(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, journalResponse.Journal.Version)
 	c.JSON(http.StatusOK, journalResponse)
 }
 
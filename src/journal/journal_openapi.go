@@ -0,0 +1,18 @@
+package journal
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"JournalHandler.PreviewJournal": {
+		Summary:     "Preview a journal entry's postings",
+		Description: "Validates the journal entry and returns the splits it would post without saving anything.",
+		UserID:      true, Request: CreateJournalRequest{}, Response: JournalPreviewResponse{},
+	},
+	"JournalHandler.CreateJournal": {Summary: "Create and post a journal entry", UserID: true, Idempotent: true, Request: CreateJournalRequest{}, Response: JournalResponse{}},
+	"JournalHandler.GetJournals":   {Summary: "List journal entries", Response: pagination.Page[Journal]{}, List: &journalListSpec},
+	"JournalHandler.GetJournal":    {Summary: "Get a journal entry with its lines and postings", Response: JournalResponse{}},
+	"JournalHandler.UpdateJournal": {Summary: "Update a journal entry and repost it", UserID: true, IfMatch: true, Request: UpdateJournalRequest{}, Response: JournalResponse{}},
+}
@@ -2,7 +2,9 @@ package journal
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 )
 
 type JournalRepository interface {
@@ -10,6 +12,7 @@ type JournalRepository interface {
 	Update(journal Journal) (Journal, error)
 	GetByID(id int) (Journal, error)
 	GetByBuildingID(buildingID int) ([]Journal, error)
+	List(buildingID int, params pagination.Params) ([]Journal, int, error)
 }
 
 type journalRepo struct {
@@ -38,8 +41,8 @@ func (r *journalRepo) Create(journal Journal) (Journal, error) {
 	id, _ := result.LastInsertId()
 	journal.ID = int(id)
 
-	err = r.db.QueryRow("SELECT id, transaction_id, reference, journal_date, building_id, memo, total_amount, created_at FROM journal WHERE id = ?", journal.ID).
-		Scan(&journal.ID, &journal.TransactionID, &journal.Reference, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt)
+	err = r.db.QueryRow("SELECT id, transaction_id, reference, journal_date, building_id, memo, total_amount, created_at, version FROM journal WHERE id = ?", journal.ID).
+		Scan(&journal.ID, &journal.TransactionID, &journal.Reference, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt, &journal.Version)
 
 	return journal, err
 }
@@ -59,26 +62,26 @@ func (r *journalRepo) Update(journal Journal) (Journal, error) {
 		return journal, err
 	}
 
-	err = r.db.QueryRow("SELECT id, transaction_id, reference, journal_date, building_id, memo, total_amount, created_at FROM journal WHERE id = ?", journal.ID).
-		Scan(&journal.ID, &journal.TransactionID, &journal.Reference, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt)
+	err = r.db.QueryRow("SELECT id, transaction_id, reference, journal_date, building_id, memo, total_amount, created_at, version FROM journal WHERE id = ?", journal.ID).
+		Scan(&journal.ID, &journal.TransactionID, &journal.Reference, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt, &journal.Version)
 
 	return journal, err
 }
 
 func (r *journalRepo) GetByID(id int) (Journal, error) {
 	var journal Journal
-	err := r.db.QueryRow("SELECT id, transaction_id, journal_date, building_id, memo, total_amount, created_at FROM journal WHERE id = ?", id).
-		Scan(&journal.ID, &journal.TransactionID, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt)
+	err := r.db.QueryRow("SELECT id, transaction_id, journal_date, building_id, memo, total_amount, created_at, version FROM journal WHERE id = ?", id).
+		Scan(&journal.ID, &journal.TransactionID, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt, &journal.Version)
 
 	if err == sql.ErrNoRows {
-		return journal, fmt.Errorf("journal not found")
+		return journal, apperrors.NotFound("journal")
 	}
 
 	return journal, err
 }
 
 func (r *journalRepo) GetByBuildingID(buildingID int) ([]Journal, error) {
-	rows, err := r.db.Query("SELECT id, transaction_id, reference, journal_date, building_id, memo, total_amount, created_at FROM journal WHERE building_id = ? ORDER BY created_at DESC", buildingID)
+	rows, err := r.db.Query("SELECT id, transaction_id, reference, journal_date, building_id, memo, total_amount, created_at, version FROM journal WHERE building_id = ? ORDER BY created_at DESC", buildingID)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +90,7 @@ func (r *journalRepo) GetByBuildingID(buildingID int) ([]Journal, error) {
 	journals := []Journal{}
 	for rows.Next() {
 		var journal Journal
-		err := rows.Scan(&journal.ID, &journal.TransactionID, &journal.Reference, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt)
+		err := rows.Scan(&journal.ID, &journal.TransactionID, &journal.Reference, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt, &journal.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -97,3 +100,69 @@ func (r *journalRepo) GetByBuildingID(buildingID int) ([]Journal, error) {
 	return journals, nil
 }
 
+var journalListSpec = pagination.Spec{
+	IDColumn: "j.id",
+	Sorts: map[string]string{
+		"id":         "j.id",
+		"date":       "j.journal_date",
+		"number":     "j.reference",
+		"amount":     "COALESCE(j.total_amount, 0)",
+		"created_at": "j.created_at",
+	},
+	DefaultSort:  "-created_at",
+	DateColumn:   "j.journal_date",
+	AmountColumn: "COALESCE(j.total_amount, 0)",
+	NumberColumn: "j.reference",
+	StatusFilter: "EXISTS (SELECT 1 FROM transactions t WHERE t.id = j.transaction_id AND t.status = ?)",
+	PeopleFilter: "EXISTS (SELECT 1 FROM splits s WHERE s.transaction_id = j.transaction_id AND s.status = '1' AND s.people_id = ?)",
+	UnitFilter:   "EXISTS (SELECT 1 FROM splits s WHERE s.transaction_id = j.transaction_id AND s.status = '1' AND s.unit_id = ?)",
+}
+
+func journalSortKey(journal Journal, sort string) (interface{}, int) {
+	switch sort {
+	case "date":
+		return journal.JournalDate, journal.ID
+	case "number":
+		return journal.Reference, journal.ID
+	case "amount":
+		return journal.TotalAmount, journal.ID
+	case "created_at":
+		return journal.CreatedAt, journal.ID
+	}
+	return journal.ID, journal.ID
+}
+
+func (r *journalRepo) List(buildingID int, params pagination.Params) ([]Journal, int, error) {
+	where, args := journalListSpec.Where(params)
+	where = " WHERE j.building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM journal j"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := journalListSpec.After(params)
+	order, orderArgs := journalListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	rows, err := r.db.Query("SELECT j.id, j.transaction_id, j.reference, j.journal_date, j.building_id, j.memo, j.total_amount, j.created_at, j.version FROM journal j"+where+after+order, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	journals := []Journal{}
+	for rows.Next() {
+		var journal Journal
+		err := rows.Scan(&journal.ID, &journal.TransactionID, &journal.Reference, &journal.JournalDate, &journal.BuildingID, &journal.Memo, &journal.TotalAmount, &journal.CreatedAt, &journal.Version)
+		if err != nil {
+			return nil, 0, err
+		}
+		journals = append(journals, journal)
+	}
+
+	return journals, total, rows.Err()
+}
+
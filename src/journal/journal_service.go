@@ -3,13 +3,18 @@ package journal
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"strings"
 
-	"github.com/mysecodgit/go_accounting/src/accounts"
 	"github.com/mysecodgit/go_accounting/src/account_types"
+	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/journal_lines"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type JournalService struct {
@@ -20,6 +25,7 @@ type JournalService struct {
 	accountRepo     accounts.AccountRepository
 	accountTypeRepo account_types.AccountTypeRepository
 	db              *sql.DB
+	logger          *slog.Logger
 }
 
 func NewJournalService(
@@ -30,6 +36,7 @@ func NewJournalService(
 	accountRepo accounts.AccountRepository,
 	accountTypeRepo account_types.AccountTypeRepository,
 	db *sql.DB,
+	logger *slog.Logger,
 ) *JournalService {
 	return &JournalService{
 		journalRepo:     journalRepo,
@@ -39,44 +46,52 @@ func NewJournalService(
 		accountRepo:     accountRepo,
 		accountTypeRepo: accountTypeRepo,
 		db:              db,
+		logger:          logger,
 	}
 }
 
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *JournalService) WithLogger(logger *slog.Logger) *JournalService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
 // CalculateSplitsForJournal calculates the double-entry accounting splits for a journal
 // For journals: Use debit/credit directly from journal_lines
 func (s *JournalService) CalculateSplitsForJournal(req CreateJournalRequest, userID int) ([]SplitPreview, error) {
 	splits := []SplitPreview{}
 
 	if len(req.Lines) == 0 {
-		return nil, fmt.Errorf("journal must have at least one line")
+		return nil, apperrors.Rule("journal must have at least one line")
 	}
 
 	// Validate journal lines and check for A/R or A/P accounts requiring people_id
 	for _, line := range req.Lines {
 		account, _, _, err := s.accountRepo.GetByID(line.AccountID)
 		if err != nil {
-			return nil, fmt.Errorf("account %d not found: %v", line.AccountID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("account %d", line.AccountID), err)
 		}
 
 		// Get account type
 		accountType, err := s.accountTypeRepo.GetByID(account.AccountType)
 		if err != nil {
-			return nil, fmt.Errorf("account type not found: %v", err)
+			return nil, apperrors.Lookup("account type", err)
 		}
 
 		// Validate: if account type is "Account Receivable" or "Account Payable", people_id must be selected
 		typeLower := strings.ToLower(accountType.Type)
 		if (typeLower == "account receivable" || typeLower == "account payable") && line.PeopleID == nil {
-			return nil, fmt.Errorf("people_id is required when account type is %s", accountType.TypeName)
+			return nil, apperrors.Rulef("people_id is required when account type is %s", accountType.TypeName)
 		}
 
 		// Validate: must have either debit or credit, but not both
 		if (line.Debit == nil || *line.Debit == 0) && (line.Credit == nil || *line.Credit == 0) {
-			return nil, fmt.Errorf("each journal line must have either a debit or credit amount")
+			return nil, apperrors.Rule("each journal line must have either a debit or credit amount")
 		}
 
 		if line.Debit != nil && *line.Debit > 0 && line.Credit != nil && *line.Credit > 0 {
-			return nil, fmt.Errorf("journal line cannot have both debit and credit")
+			return nil, apperrors.Rule("journal line cannot have both debit and credit")
 		}
 
 		var debitAmount *float64
@@ -114,11 +129,11 @@ func (s *JournalService) CalculateSplitsForJournal(req CreateJournalRequest, use
 	}
 
 	if len(splits) < 2 {
-		return nil, fmt.Errorf("journal must have at least 2 splits for double-entry accounting, got %d", len(splits))
+		return nil, apperrors.Rulef("journal must have at least 2 splits for double-entry accounting, got %d", len(splits))
 	}
 
 	if totalDebit != totalCredit {
-		return nil, fmt.Errorf("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
+		return nil, apperrors.Rulef("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
 	}
 
 	return splits, nil
@@ -127,7 +142,7 @@ func (s *JournalService) CalculateSplitsForJournal(req CreateJournalRequest, use
 // PreviewJournal calculates and returns the splits that will be created
 func (s *JournalService) PreviewJournal(req CreateJournalRequest, userID int) (*JournalPreviewResponse, error) {
 	if len(req.Lines) == 0 {
-		return nil, fmt.Errorf("journal must have at least one line")
+		return nil, apperrors.Rule("journal must have at least one line")
 	}
 
 	// Calculate splits
@@ -165,7 +180,7 @@ func (s *JournalService) CreateJournal(req CreateJournalRequest, userID int) (*J
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -185,12 +200,12 @@ func (s *JournalService) CreateJournal(req CreateJournalRequest, userID int) (*J
 	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		"journal", req.JournalDate, req.Reference, memo, transactionStatus, req.BuildingID, userID, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %v", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	transactionID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction ID: %v", err)
+		return nil, fmt.Errorf("failed to get transaction ID: %w", err)
 	}
 
 	// Create journal
@@ -204,12 +219,12 @@ func (s *JournalService) CreateJournal(req CreateJournalRequest, userID int) (*J
 	result, err = tx.Exec("INSERT INTO journal (transaction_id, reference, journal_date, building_id, memo, total_amount) VALUES (?, ?, ?, ?, ?, ?)",
 		transactionID, req.Reference, req.JournalDate, req.BuildingID, memoInterface, req.TotalAmount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create journal: %v", err)
+		return nil, fmt.Errorf("failed to create journal: %w", err)
 	}
 
 	journalID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get journal ID: %v", err)
+		return nil, fmt.Errorf("failed to get journal ID: %w", err)
 	}
 
 	// Create journal lines
@@ -252,14 +267,14 @@ func (s *JournalService) CreateJournal(req CreateJournalRequest, userID int) (*J
 		_, err = tx.Exec("INSERT INTO journal_lines (journal_id, account_id, unit_id, people_id, description, debit, credit) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			journalID, lineInput.AccountID, unitID, peopleID, description, debit, credit)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create journal line: %v", err)
+			return nil, fmt.Errorf("failed to create journal line: %w", err)
 		}
 	}
 
 	// Calculate and create splits
 	splitPreviews, err := s.CalculateSplitsForJournal(req, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Create splits within transaction
@@ -296,35 +311,37 @@ func (s *JournalService) CreateJournal(req CreateJournalRequest, userID int) (*J
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("journal", metrics.PostingCreated)
+	s.logger.Info("journal created", "journal_id", journalID, "transaction_id", transactionID)
 
 	// Fetch created records after successful commit
 	createdTransaction, err := s.transactionRepo.GetByID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	createdJournal, err := s.journalRepo.GetByID(int(journalID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch journal: %v", err)
+		return nil, fmt.Errorf("failed to fetch journal: %w", err)
 	}
 
 	createdLines, err := s.journalLineRepo.GetByJournalID(int(journalID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch journal lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch journal lines: %w", err)
 	}
 
 	createdSplits, err := s.splitRepo.GetByTransactionID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	// Filter to only active splits
@@ -349,13 +366,13 @@ func (s *JournalService) UpdateJournal(req UpdateJournalRequest, userID int) (*J
 	// Get existing journal
 	existingJournal, err := s.journalRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("journal not found: %v", err)
+		return nil, apperrors.Lookup("journal", err)
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -366,6 +383,11 @@ func (s *JournalService) UpdateJournal(req UpdateJournalRequest, userID int) (*J
 		}
 	}()
 
+	// Claim the next version first so a concurrent update fails instead of overwriting
+	if err := versioning.Bump(tx, "journal", req.ID, req.ExpectedVersion, "journal"); err != nil {
+		return nil, err
+	}
+
 	// Update transaction record
 	memo := ""
 	if req.Memo != nil {
@@ -374,7 +396,7 @@ func (s *JournalService) UpdateJournal(req UpdateJournalRequest, userID int) (*J
 	_, err = tx.Exec("UPDATE transactions SET transaction_date = ?, transaction_number = ?, memo = ? WHERE id = ?",
 		req.JournalDate, req.Reference, memo, existingJournal.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update transaction: %v", err)
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
 	}
 
 	// Update journal
@@ -388,19 +410,19 @@ func (s *JournalService) UpdateJournal(req UpdateJournalRequest, userID int) (*J
 	_, err = tx.Exec("UPDATE journal SET reference = ?, journal_date = ?, memo = ?, total_amount = ? WHERE id = ?",
 		req.Reference, req.JournalDate, memoInterface, req.TotalAmount, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update journal: %v", err)
+		return nil, fmt.Errorf("failed to update journal: %w", err)
 	}
 
 	// Delete existing journal_lines (no status column)
 	_, err = tx.Exec("DELETE FROM journal_lines WHERE journal_id = ?", req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to delete journal lines: %v", err)
+		return nil, fmt.Errorf("failed to delete journal lines: %w", err)
 	}
 
 	// Soft delete existing splits (set status='0')
 	_, err = tx.Exec("UPDATE splits SET status = '0' WHERE transaction_id = ?", existingJournal.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to soft delete splits: %v", err)
+		return nil, fmt.Errorf("failed to soft delete splits: %w", err)
 	}
 
 	// Recreate journal lines
@@ -443,7 +465,7 @@ func (s *JournalService) UpdateJournal(req UpdateJournalRequest, userID int) (*J
 		_, err = tx.Exec("INSERT INTO journal_lines (journal_id, account_id, unit_id, people_id, description, debit, credit) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			req.ID, lineInput.AccountID, unitID, peopleID, description, debit, credit)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create journal line: %v", err)
+			return nil, fmt.Errorf("failed to create journal line: %w", err)
 		}
 	}
 
@@ -457,7 +479,7 @@ func (s *JournalService) UpdateJournal(req UpdateJournalRequest, userID int) (*J
 	}
 	splitPreviews, err := s.CalculateSplitsForJournal(createReq, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Recreate splits
@@ -494,35 +516,37 @@ func (s *JournalService) UpdateJournal(req UpdateJournalRequest, userID int) (*J
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingJournal.TransactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("journal", metrics.PostingUpdated)
+	s.logger.Info("journal updated", "journal_id", existingJournal.ID, "transaction_id", existingJournal.TransactionID)
 
 	// Fetch updated records, filtering for active status
 	updatedTransaction, err := s.transactionRepo.GetByID(existingJournal.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	updatedJournal, err := s.journalRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch journal: %v", err)
+		return nil, fmt.Errorf("failed to fetch journal: %w", err)
 	}
 
 	updatedLines, err := s.journalLineRepo.GetByJournalID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch journal lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch journal lines: %w", err)
 	}
 
 	updatedSplits, err := s.splitRepo.GetByTransactionID(existingJournal.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	// Filter to only active splits
@@ -550,17 +574,17 @@ func (s *JournalService) GetJournalDetails(id int) (*JournalResponse, error) {
 
 	lines, err := s.journalLineRepo.GetByJournalID(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch journal lines: %v", err)
+		return nil, fmt.Errorf("failed to fetch journal lines: %w", err)
 	}
 
 	splitsList, err := s.splitRepo.GetByTransactionID(journal.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	transaction, err := s.transactionRepo.GetByID(journal.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	return &JournalResponse{
@@ -572,7 +596,11 @@ func (s *JournalService) GetJournalDetails(id int) (*JournalResponse, error) {
 }
 
 // GetJournals returns all journals for a building
-func (s *JournalService) GetJournals(buildingID int) ([]Journal, error) {
-	return s.journalRepo.GetByBuildingID(buildingID)
+func (s *JournalService) GetJournals(buildingID int, params pagination.Params) (pagination.Page[Journal], error) {
+	journals, total, err := s.journalRepo.List(buildingID, params)
+	if err != nil {
+		return pagination.Page[Journal]{}, fmt.Errorf("failed to list journals: %w", err)
+	}
+	return pagination.NewPage(journals, total, params, journalSortKey), nil
 }
 
@@ -2,7 +2,8 @@ package journal_lines
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type JournalLineRepository interface {
@@ -166,7 +167,7 @@ func (r *journalLineRepo) GetByID(id int) (JournalLine, error) {
 		Scan(&journalLine.ID, &journalLine.JournalID, &journalLine.AccountID, &journalLine.UnitID, &journalLine.PeopleID, &journalLine.Description, &debit, &credit)
 
 	if err == sql.ErrNoRows {
-		return journalLine, fmt.Errorf("journal line not found")
+		return journalLine, apperrors.NotFound("journal line")
 	}
 
 	if debit.Valid {
@@ -2,7 +2,8 @@ package leases
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type LeaseFileRepository interface {
@@ -78,7 +79,7 @@ func (r *leaseFileRepo) GetByID(id int) (LeaseFile, error) {
 	)
 
 	if err == sql.ErrNoRows {
-		return leaseFile, fmt.Errorf("lease file not found")
+		return leaseFile, apperrors.NotFound("lease file")
 	}
 
 	return leaseFile, err
@@ -16,6 +16,7 @@ type Lease struct {
 	ServiceAmount float64 `json:"service_amount"`
 	LeaseTerms    string  `json:"lease_terms"`
 	Status        string  `json:"status"`
+	Version       int     `json:"version"`
 	CreatedAt     string  `json:"created_at"`
 	UpdatedAt     string  `json:"updated_at"`
 }
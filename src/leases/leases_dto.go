@@ -27,6 +27,9 @@ type UpdateLeaseRequest struct {
 	ServiceAmount float64 `json:"service_amount"`
 	LeaseTerms    string  `json:"lease_terms"`
 	Status        string  `json:"status"`
+
+	// Version from the If-Match header; the update fails if the document has moved on
+	ExpectedVersion int `json:"-"`
 }
 
 type LeaseResponse struct {
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type LeaseHandler struct {
@@ -26,13 +28,13 @@ func (h *LeaseHandler) GetCustomers(c *gin.Context) {
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
 		return
 	}
 
 	customers, err := h.service.GetCustomersByBuildingID(buildingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -44,13 +46,13 @@ func (h *LeaseHandler) GetCustomersWithLeaseUnits(c *gin.Context) {
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
 		return
 	}
 
 	customers, err := h.service.GetCustomersWithLeaseUnits(buildingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -62,7 +64,7 @@ func (h *LeaseHandler) GetAvailableUnits(c *gin.Context) {
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
 		return
 	}
 
@@ -78,7 +80,7 @@ func (h *LeaseHandler) GetAvailableUnits(c *gin.Context) {
 
 	units, err := h.service.GetAvailableUnits(buildingID, includeUnitID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -90,20 +92,20 @@ func (h *LeaseHandler) GetUnitsByPeopleID(c *gin.Context) {
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
 		return
 	}
 
 	peopleIDStr := c.Param("peopleId")
 	peopleID, err := strconv.Atoi(peopleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid people ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid people ID"))
 		return
 	}
 
 	units, err := h.service.GetUnitsByPeopleID(buildingID, peopleID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -115,13 +117,13 @@ func (h *LeaseHandler) GetLeasesByUnitID(c *gin.Context) {
 	unitIDStr := c.Param("unitId")
 	unitID, err := strconv.Atoi(unitIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unit ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid unit ID"))
 		return
 	}
 
 	leases, err := h.service.GetLeasesByUnitID(unitID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -132,14 +134,14 @@ func (h *LeaseHandler) GetLeasesByUnitID(c *gin.Context) {
 func (h *LeaseHandler) CreateLease(c *gin.Context) {
 	var req CreateLeaseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
 		return
 	}
 	req.BuildingID = buildingID
@@ -150,7 +152,7 @@ func (h *LeaseHandler) CreateLease(c *gin.Context) {
 
 	response, err := h.service.CreateLease(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -159,16 +161,21 @@ func (h *LeaseHandler) CreateLease(c *gin.Context) {
 
 // PUT /buildings/:id/leases/:leaseId
 func (h *LeaseHandler) UpdateLease(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateLeaseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	leaseIDStr := c.Param("leaseId")
 	leaseID, err := strconv.Atoi(leaseIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lease ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid lease ID"))
 		return
 	}
 	req.ID = leaseID
@@ -176,17 +183,20 @@ func (h *LeaseHandler) UpdateLease(c *gin.Context) {
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
 		return
 	}
 	req.BuildingID = buildingID
 
+	req.ExpectedVersion = version
 	response, err := h.service.UpdateLease(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Lease.Version)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -195,16 +205,17 @@ func (h *LeaseHandler) GetLeaseByID(c *gin.Context) {
 	leaseIDStr := c.Param("leaseId")
 	leaseID, err := strconv.Atoi(leaseIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lease ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid lease ID"))
 		return
 	}
 
 	response, err := h.service.GetLeaseByID(leaseID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Lease.Version)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -213,13 +224,19 @@ func (h *LeaseHandler) GetLeasesByBuildingID(c *gin.Context) {
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
+		return
+	}
+
+	params, validationErrors := leaseListSpec.Parse(c.Request.URL.Query())
+	if validationErrors != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
 		return
 	}
 
-	leases, err := h.service.GetLeasesByBuildingID(buildingID)
+	leases, err := h.service.GetLeasesByBuildingID(buildingID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -231,13 +248,13 @@ func (h *LeaseHandler) DeleteLease(c *gin.Context) {
 	leaseIDStr := c.Param("leaseId")
 	leaseID, err := strconv.Atoi(leaseIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lease ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid lease ID"))
 		return
 	}
 
 	err = h.service.DeleteLease(leaseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -249,27 +266,27 @@ func (h *LeaseHandler) UploadLeaseFile(c *gin.Context) {
 	leaseIDStr := c.Param("leaseId")
 	leaseID, err := strconv.Atoi(leaseIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lease ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid lease ID"))
 		return
 	}
 
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
 		return
 	}
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No file provided"})
+		apperrors.Respond(c, apperrors.BadRequest("No file provided"))
 		return
 	}
 
 	// Create upload directory
 	uploadPath := GetUploadPath(buildingID)
 	if err := os.MkdirAll(uploadPath, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+		apperrors.Respond(c, apperrors.Wrap(apperrors.CodeInternal, "Failed to create upload directory", err))
 		return
 	}
 
@@ -281,20 +298,20 @@ func (h *LeaseHandler) UploadLeaseFile(c *gin.Context) {
 	// Save file
 	src, err := file.Open()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open file"})
+		apperrors.Respond(c, apperrors.Wrap(apperrors.CodeInternal, "Failed to open file", err))
 		return
 	}
 	defer src.Close()
 
 	dst, err := os.Create(filePath)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create file"})
+		apperrors.Respond(c, apperrors.Wrap(apperrors.CodeInternal, "Failed to create file", err))
 		return
 	}
 	defer dst.Close()
 
 	if _, err := io.Copy(dst, src); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		apperrors.Respond(c, apperrors.Wrap(apperrors.CodeInternal, "Failed to save file", err))
 		return
 	}
 
@@ -311,7 +328,7 @@ func (h *LeaseHandler) UploadLeaseFile(c *gin.Context) {
 		if _, statErr := os.Stat(filePath); statErr == nil {
 			os.Remove(filePath)
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -323,19 +340,19 @@ func (h *LeaseHandler) DownloadLeaseFile(c *gin.Context) {
 	fileIDStr := c.Param("fileId")
 	fileID, err := strconv.Atoi(fileIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid file ID"))
 		return
 	}
 
 	leaseFile, err := h.service.GetLeaseFileByID(fileID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		apperrors.Respond(c, apperrors.New(apperrors.CodeNotFound, "File not found"))
 		return
 	}
 
 	// Check if file exists
 	if _, err := os.Stat(leaseFile.FilePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "File not found on disk"})
+		apperrors.Respond(c, apperrors.New(apperrors.CodeNotFound, "File not found on disk"))
 		return
 	}
 
@@ -347,13 +364,13 @@ func (h *LeaseHandler) DeleteLeaseFile(c *gin.Context) {
 	fileIDStr := c.Param("fileId")
 	fileID, err := strconv.Atoi(fileIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid file ID"))
 		return
 	}
 
 	err = h.service.DeleteLeaseFile(fileID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
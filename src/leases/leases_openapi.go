@@ -0,0 +1,27 @@
+package leases
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+	"github.com/mysecodgit/go_accounting/src/people"
+)
+
+var OpenAPI = openapi.Handlers{
+	"LeaseHandler.GetCustomers":               {Summary: "List the building's customers", Response: []people.Person{}},
+	"LeaseHandler.GetCustomersWithLeaseUnits": {Summary: "List customers with their leased units", Response: []map[string]interface{}{}},
+	"LeaseHandler.GetAvailableUnits": {
+		Summary:  "List units without an active lease",
+		Query:    []openapi.Param{{Name: "include_unit_id", Type: "integer", Format: "int32", Description: "Also return this unit, e.g. the one on the lease being edited"}},
+		Response: []interface{}{},
+	},
+	"LeaseHandler.GetUnitsByPeopleID":    {Summary: "List units leased by a customer", Response: []interface{}{}},
+	"LeaseHandler.GetLeasesByUnitID":     {Summary: "List a unit's leases", Response: []LeaseListItem{}},
+	"LeaseHandler.GetLeasesByBuildingID": {Summary: "List leases", Response: pagination.Page[LeaseListItem]{}, List: &leaseListSpec},
+	"LeaseHandler.GetLeaseByID":          {Summary: "Get a lease with its files", Response: LeaseResponse{}},
+	"LeaseHandler.CreateLease":           {Summary: "Create a lease", Request: CreateLeaseRequest{}, Response: LeaseResponse{}},
+	"LeaseHandler.UpdateLease":           {Summary: "Update a lease", IfMatch: true, Request: UpdateLeaseRequest{}, Response: LeaseResponse{}},
+	"LeaseHandler.DeleteLease":           {Summary: "Delete a lease", Response: openapi.Message{}},
+	"LeaseHandler.UploadLeaseFile":       {Summary: "Attach a file to a lease", Upload: "file", Response: LeaseFile{}},
+	"LeaseHandler.DownloadLeaseFile":     {Summary: "Download a lease file", Download: true},
+	"LeaseHandler.DeleteLeaseFile":       {Summary: "Delete a lease file", Response: openapi.Message{}},
+}
@@ -2,14 +2,20 @@ package leases
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type LeaseRepository interface {
 	Create(lease Lease) (Lease, error)
+	CreateWithTx(tx *sql.Tx, lease Lease) (Lease, error)
 	Update(lease Lease) (Lease, error)
+	UpdateWithTx(tx *sql.Tx, lease Lease) (Lease, error)
 	GetByID(id int) (Lease, error)
 	GetByBuildingID(buildingID int) ([]Lease, error)
+	List(buildingID int, params pagination.Params) ([]Lease, int, error)
 	GetByUnitID(unitID int) ([]Lease, error)
 	Delete(id int) error
 }
@@ -23,7 +29,16 @@ func NewLeaseRepository(db *sql.DB) LeaseRepository {
 }
 
 func (r *leaseRepo) Create(lease Lease) (Lease, error) {
-	result, err := r.db.Exec(
+	return createLease(r.db, lease)
+}
+
+// CreateWithTx creates the lease in the caller's transaction
+func (r *leaseRepo) CreateWithTx(tx *sql.Tx, lease Lease) (Lease, error) {
+	return createLease(tx, lease)
+}
+
+func createLease(db versioning.Executor, lease Lease) (Lease, error) {
+	result, err := db.Exec(
 		"INSERT INTO leases (people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		lease.PeopleID, lease.BuildingID, lease.UnitID, lease.StartDate, lease.EndDate, lease.RentAmount, lease.DepositAmount, lease.ServiceAmount, lease.LeaseTerms, lease.Status,
 	)
@@ -35,18 +50,27 @@ func (r *leaseRepo) Create(lease Lease) (Lease, error) {
 	id, _ := result.LastInsertId()
 	lease.ID = int(id)
 
-	err = r.db.QueryRow(
-		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status FROM leases WHERE id = ?",
+	err = db.QueryRow(
+		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status, version FROM leases WHERE id = ?",
 		lease.ID,
 	).Scan(
-		&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status,
+		&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status, &lease.Version,
 	)
 
 	return lease, err
 }
 
 func (r *leaseRepo) Update(lease Lease) (Lease, error) {
-	_, err := r.db.Exec(
+	return updateLease(r.db, lease)
+}
+
+// UpdateWithTx updates the lease in the caller's transaction
+func (r *leaseRepo) UpdateWithTx(tx *sql.Tx, lease Lease) (Lease, error) {
+	return updateLease(tx, lease)
+}
+
+func updateLease(db versioning.Executor, lease Lease) (Lease, error) {
+	_, err := db.Exec(
 		"UPDATE leases SET people_id = ?, building_id = ?, unit_id = ?, start_date = ?, end_date = ?, rent_amount = ?, deposit_amount = ?, service_amount = ?, lease_terms = ?, status = ? WHERE id = ?",
 		lease.PeopleID, lease.BuildingID, lease.UnitID, lease.StartDate, lease.EndDate, lease.RentAmount, lease.DepositAmount, lease.ServiceAmount, lease.LeaseTerms, lease.Status, lease.ID,
 	)
@@ -55,11 +79,11 @@ func (r *leaseRepo) Update(lease Lease) (Lease, error) {
 		return lease, err
 	}
 
-	err = r.db.QueryRow(
-		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status FROM leases WHERE id = ?",
+	err = db.QueryRow(
+		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status, version FROM leases WHERE id = ?",
 		lease.ID,
 	).Scan(
-		&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status,
+		&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status, &lease.Version,
 	)
 
 	return lease, err
@@ -68,14 +92,14 @@ func (r *leaseRepo) Update(lease Lease) (Lease, error) {
 func (r *leaseRepo) GetByID(id int) (Lease, error) {
 	var lease Lease
 	err := r.db.QueryRow(
-		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status FROM leases WHERE id = ?",
+		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status, version FROM leases WHERE id = ?",
 		id,
 	).Scan(
-		&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status,
+		&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status, &lease.Version,
 	)
 
 	if err == sql.ErrNoRows {
-		return lease, fmt.Errorf("lease not found")
+		return lease, apperrors.NotFound("lease")
 	}
 
 	return lease, err
@@ -83,7 +107,7 @@ func (r *leaseRepo) GetByID(id int) (Lease, error) {
 
 func (r *leaseRepo) GetByBuildingID(buildingID int) ([]Lease, error) {
 	rows, err := r.db.Query(
-		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status FROM leases WHERE building_id = ? ORDER BY id DESC",
+		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status, version FROM leases WHERE building_id = ? ORDER BY id DESC",
 		buildingID,
 	)
 	if err != nil {
@@ -95,7 +119,7 @@ func (r *leaseRepo) GetByBuildingID(buildingID int) ([]Lease, error) {
 	for rows.Next() {
 		var lease Lease
 		err := rows.Scan(
-			&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status,
+			&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status, &lease.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -106,9 +130,74 @@ func (r *leaseRepo) GetByBuildingID(buildingID int) ([]Lease, error) {
 	return leases, nil
 }
 
+var leaseListSpec = pagination.Spec{
+	IDColumn: "l.id",
+	Sorts: map[string]string{
+		"id":     "l.id",
+		"date":   "l.start_date",
+		"amount": "l.rent_amount",
+	},
+	DefaultSort:  "-id",
+	DateColumn:   "l.start_date",
+	AmountColumn: "l.rent_amount",
+	StatusFilter: "l.status = ?",
+	PeopleFilter: "l.people_id = ?",
+	UnitFilter:   "l.unit_id = ?",
+}
+
+func leaseSortKey(item LeaseListItem, sort string) (interface{}, int) {
+	lease := item.Lease
+	switch sort {
+	case "date":
+		return lease.StartDate, lease.ID
+	case "amount":
+		return lease.RentAmount, lease.ID
+	}
+	return lease.ID, lease.ID
+}
+
+func (r *leaseRepo) List(buildingID int, params pagination.Params) ([]Lease, int, error) {
+	where, args := leaseListSpec.Where(params)
+	where = " WHERE l.building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM leases l"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := leaseListSpec.After(params)
+	order, orderArgs := leaseListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	rows, err := r.db.Query(
+		"SELECT l.id, l.people_id, l.building_id, l.unit_id, l.start_date, l.end_date, l.rent_amount, l.deposit_amount, l.service_amount, l.lease_terms, l.status, l.version FROM leases l"+where+after+order,
+		args...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	leases := []Lease{}
+	for rows.Next() {
+		var lease Lease
+		err := rows.Scan(
+			&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status, &lease.Version,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		leases = append(leases, lease)
+	}
+
+	return leases, total, rows.Err()
+}
+
 func (r *leaseRepo) GetByUnitID(unitID int) ([]Lease, error) {
 	rows, err := r.db.Query(
-		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status FROM leases WHERE unit_id = ? AND status = '1' ORDER BY id DESC",
+		"SELECT id, people_id, building_id, unit_id, start_date, end_date, rent_amount, deposit_amount, service_amount, lease_terms, status, version FROM leases WHERE unit_id = ? AND status = '1' ORDER BY id DESC",
 		unitID,
 	)
 	if err != nil {
@@ -120,7 +209,7 @@ func (r *leaseRepo) GetByUnitID(unitID int) ([]Lease, error) {
 	for rows.Next() {
 		var lease Lease
 		err := rows.Scan(
-			&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status,
+			&lease.ID, &lease.PeopleID, &lease.BuildingID, &lease.UnitID, &lease.StartDate, &lease.EndDate, &lease.RentAmount, &lease.DepositAmount, &lease.ServiceAmount, &lease.LeaseTerms, &lease.Status, &lease.Version,
 		)
 		if err != nil {
 			return nil, err
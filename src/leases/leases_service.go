@@ -7,8 +7,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/mysecodgit/go_accounting/config"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 	"github.com/mysecodgit/go_accounting/src/people"
 	"github.com/mysecodgit/go_accounting/src/people_types"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type LeaseService struct {
@@ -105,7 +110,7 @@ func (s *LeaseService) GetCustomersWithLeaseUnits(buildingID int) ([]map[string]
 	`
 	rows, err := s.db.Query(query, buildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query leases: %v", err)
+		return nil, fmt.Errorf("failed to query leases: %w", err)
 	}
 	defer rows.Close()
 
@@ -115,7 +120,7 @@ func (s *LeaseService) GetCustomersWithLeaseUnits(buildingID int) ([]map[string]
 		var peopleID, unitID int
 		var unitName string
 		if err := rows.Scan(&peopleID, &unitID, &unitName); err != nil {
-			return nil, fmt.Errorf("failed to scan lease: %v", err)
+			return nil, fmt.Errorf("failed to scan lease: %w", err)
 		}
 		leaseUnits[peopleID] = map[string]interface{}{
 			"unit_id":   unitID,
@@ -178,7 +183,7 @@ func (s *LeaseService) GetAvailableUnits(buildingID int, includeUnitID *int) ([]
 
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query available units: %v", err)
+		return nil, fmt.Errorf("failed to query available units: %w", err)
 	}
 	defer rows.Close()
 
@@ -187,7 +192,7 @@ func (s *LeaseService) GetAvailableUnits(buildingID int, includeUnitID *int) ([]
 		var id, buildingID int
 		var name string
 		if err := rows.Scan(&id, &name, &buildingID); err != nil {
-			return nil, fmt.Errorf("failed to scan unit: %v", err)
+			return nil, fmt.Errorf("failed to scan unit: %w", err)
 		}
 		units = append(units, map[string]interface{}{
 			"id":          id,
@@ -211,7 +216,7 @@ func (s *LeaseService) GetUnitsByPeopleID(buildingID, peopleID int) ([]interface
 
 	rows, err := s.db.Query(query, buildingID, peopleID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query units for people: %v", err)
+		return nil, fmt.Errorf("failed to query units for people: %w", err)
 	}
 	defer rows.Close()
 
@@ -220,7 +225,7 @@ func (s *LeaseService) GetUnitsByPeopleID(buildingID, peopleID int) ([]interface
 		var id, buildingID int
 		var name string
 		if err := rows.Scan(&id, &name, &buildingID); err != nil {
-			return nil, fmt.Errorf("failed to scan unit: %v", err)
+			return nil, fmt.Errorf("failed to scan unit: %w", err)
 		}
 		units = append(units, map[string]interface{}{
 			"id":          id,
@@ -247,14 +252,36 @@ func (s *LeaseService) CreateLease(req CreateLeaseRequest) (*LeaseResponse, erro
 	}
 
 	if errors := lease.Validate(); errors != nil {
-		return nil, fmt.Errorf("validation failed: %v", errors)
+		return nil, apperrors.Validation(errors)
 	}
 
-	createdLease, err := s.leaseRepo.Create(lease)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	createdLease, err := s.leaseRepo.CreateWithTx(tx, lease)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create lease: %v", err)
+		return nil, fmt.Errorf("failed to create lease: %w", err)
+	}
+
+	// Record the event in the same transaction so it exists exactly when the lease does
+	if err := outbox.Write(tx, createdLease.BuildingID, outbox.LeaseCreated, createdLease.ID, leaseEvent(createdLease)); err != nil {
+		return nil, err
 	}
 
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
 	// Get lease files (empty initially)
 	leaseFiles, _ := s.leaseFileRepo.GetByLeaseID(createdLease.ID)
 
@@ -280,14 +307,49 @@ func (s *LeaseService) UpdateLease(req UpdateLeaseRequest) (*LeaseResponse, erro
 	}
 
 	if errors := lease.Validate(); errors != nil {
-		return nil, fmt.Errorf("validation failed: %v", errors)
+		return nil, apperrors.Validation(errors)
+	}
+
+	existingLease, err := s.leaseRepo.GetByID(req.ID)
+	if err != nil {
+		return nil, apperrors.Lookup("lease", err)
 	}
 
-	updatedLease, err := s.leaseRepo.Update(lease)
+	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to update lease: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err := versioning.Bump(tx, "leases", req.ID, req.ExpectedVersion, "lease"); err != nil {
+		return nil, err
+	}
+
+	updatedLease, err := s.leaseRepo.UpdateWithTx(tx, lease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update lease: %w", err)
+	}
+
+	// Setting an active lease's status to 0 voids it, as DeleteLease does
+	eventType := outbox.LeaseUpdated
+	if updatedLease.Status == "0" && existingLease.Status != "0" {
+		eventType = outbox.LeaseVoided
+	}
+	if err := outbox.Write(tx, updatedLease.BuildingID, eventType, updatedLease.ID, leaseEvent(updatedLease)); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
 	// Get lease files
 	leaseFiles, _ := s.leaseFileRepo.GetByLeaseID(updatedLease.ID)
 
@@ -311,10 +373,10 @@ func (s *LeaseService) GetLeaseByID(id int) (*LeaseResponse, error) {
 	}, nil
 }
 
-func (s *LeaseService) GetLeasesByBuildingID(buildingID int) ([]LeaseListItem, error) {
-	leases, err := s.leaseRepo.GetByBuildingID(buildingID)
+func (s *LeaseService) GetLeasesByBuildingID(buildingID int, params pagination.Params) (pagination.Page[LeaseListItem], error) {
+	leases, total, err := s.leaseRepo.List(buildingID, params)
 	if err != nil {
-		return nil, err
+		return pagination.Page[LeaseListItem]{}, fmt.Errorf("failed to list leases: %w", err)
 	}
 
 	result := []LeaseListItem{}
@@ -334,7 +396,7 @@ func (s *LeaseService) GetLeasesByBuildingID(buildingID int) ([]LeaseListItem, e
 		result = append(result, item)
 	}
 
-	return result, nil
+	return pagination.NewPage(result, total, params, leaseSortKey), nil
 }
 
 func (s *LeaseService) GetLeasesByUnitID(unitID int) ([]LeaseListItem, error) {
@@ -364,10 +426,15 @@ func (s *LeaseService) GetLeasesByUnitID(unitID int) ([]LeaseListItem, error) {
 }
 
 func (s *LeaseService) DeleteLease(id int) error {
+	lease, err := s.leaseRepo.GetByID(id)
+	if err != nil {
+		return apperrors.Lookup("lease", err)
+	}
+
 	// Start transaction to ensure atomicity
 	tx, err := s.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %v", err)
+		return fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	committed := false
@@ -387,7 +454,7 @@ func (s *LeaseService) DeleteLease(id int) error {
 			// Delete database record first (within transaction)
 			_, err = tx.Exec("DELETE FROM lease_files WHERE id = ?", file.ID)
 			if err != nil {
-				return fmt.Errorf("failed to delete file record %d: %v", file.ID, err)
+				return fmt.Errorf("failed to delete file record %d: %w", file.ID, err)
 			}
 			// Delete physical file (outside transaction, but if DB delete succeeds, we should delete file)
 			if _, statErr := os.Stat(file.FilePath); statErr == nil {
@@ -399,18 +466,37 @@ func (s *LeaseService) DeleteLease(id int) error {
 	// Delete lease within transaction
 	_, err = tx.Exec("UPDATE leases SET status = '0' WHERE id = ?", id)
 	if err != nil {
-		return fmt.Errorf("failed to delete lease: %v", err)
+		return fmt.Errorf("failed to delete lease: %w", err)
+	}
+
+	lease.Status = "0"
+	if err := outbox.Write(tx, lease.BuildingID, outbox.LeaseVoided, lease.ID, leaseEvent(lease)); err != nil {
+		return err
 	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %v", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
 
 	return nil
 }
 
+// leaseEvent builds the payload of a lease event
+func leaseEvent(lease Lease) outbox.Lease {
+	return outbox.Lease{
+		ID:         lease.ID,
+		PeopleID:   lease.PeopleID,
+		UnitID:     lease.UnitID,
+		StartDate:  lease.StartDate,
+		EndDate:    lease.EndDate,
+		RentAmount: lease.RentAmount,
+		Status:     lease.Status,
+		Version:    lease.Version,
+	}
+}
+
 func (s *LeaseService) UploadLeaseFile(leaseID int, filename, originalName, filePath, fileType string, fileSize int64) (*LeaseFile, error) {
 	// Verify lease exists before uploading file
 	_, err := s.leaseRepo.GetByID(leaseID)
@@ -419,7 +505,7 @@ func (s *LeaseService) UploadLeaseFile(leaseID int, filename, originalName, file
 		if _, statErr := os.Stat(filePath); statErr == nil {
 			os.Remove(filePath)
 		}
-		return nil, fmt.Errorf("lease not found: %v", err)
+		return nil, apperrors.Lookup("lease", err)
 	}
 
 	leaseFile := LeaseFile{
@@ -437,7 +523,7 @@ func (s *LeaseService) UploadLeaseFile(leaseID int, filename, originalName, file
 		if _, statErr := os.Stat(filePath); statErr == nil {
 			os.Remove(filePath)
 		}
-		return nil, fmt.Errorf("failed to save file record: %v", err)
+		return nil, fmt.Errorf("failed to save file record: %w", err)
 	}
 
 	return &createdFile, nil
@@ -460,7 +546,7 @@ func (s *LeaseService) DeleteLeaseFile(id int) error {
 	// Delete physical file
 	if _, err := os.Stat(file.FilePath); err == nil {
 		if err := os.Remove(file.FilePath); err != nil {
-			return fmt.Errorf("failed to delete file: %v", err)
+			return fmt.Errorf("failed to delete file: %w", err)
 		}
 	}
 
@@ -470,5 +556,5 @@ func (s *LeaseService) DeleteLeaseFile(id int) error {
 
 // GetUploadPath returns the path where lease files should be stored
 func GetUploadPath(buildingID int) string {
-	return filepath.Join("uploads", "leases", fmt.Sprintf("building_%d", buildingID))
+	return filepath.Join(config.UploadRoot(), "leases", fmt.Sprintf("building_%d", buildingID))
 }
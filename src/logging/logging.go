@@ -0,0 +1,49 @@
+// Package logging writes structured JSON logs. Each request gets a logger carrying its
+// request ID, route, user and building, which handlers pass on to services so one request
+// can be followed through every line it logs.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestID is read from the request when a proxy already assigned an ID, and is
+// always set on the response
+const HeaderRequestID = "X-Request-ID"
+
+type contextKey struct{}
+
+// New returns a JSON logger writing records at level ("debug", "info", "warn" or "error")
+// and above
+func New(w io.Writer, level string) *slog.Logger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(strings.ToLower(level))); err != nil {
+		lvl = slog.LevelInfo
+	}
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl}))
+}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored by WithLogger, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// FromGin returns the request's logger
+func FromGin(c *gin.Context) *slog.Logger {
+	return FromContext(c.Request.Context())
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// buildingScope prefixes routes whose :id parameter is the building
+const buildingScope = "/api/buildings/:id"
+
+// Request IDs from clients are only trusted when they are short and printable
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// Middleware assigns the request ID and the request logger, and logs one line per
+// request with its status and duration. It replaces gin's text logger.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(HeaderRequestID)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(HeaderRequestID, requestID)
+
+		route := c.FullPath()
+		attrs := []any{
+			slog.String("request_id", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+		}
+		if userID, err := strconv.Atoi(c.GetHeader("User-ID")); err == nil {
+			attrs = append(attrs, slog.Int("user_id", userID))
+		}
+		if strings.HasPrefix(route, buildingScope) {
+			if buildingID, err := strconv.Atoi(c.Param("id")); err == nil {
+				attrs = append(attrs, slog.Int("building_id", buildingID))
+			}
+		}
+		logger := slog.Default().With(attrs...)
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		logger.Log(c.Request.Context(), level, "request",
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
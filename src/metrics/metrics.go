@@ -0,0 +1,72 @@
+// Package metrics exposes Prometheus metrics on /metrics: request latency, database pool
+// statistics and the number of ledger postings by transaction type.
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "accounting"
+
+// Posting operations, the operation label of the postings counter
+const (
+	PostingCreated = "create"
+	PostingUpdated = "update"
+)
+
+// Registry holds the application's metrics. A dedicated registry keeps /metrics free of
+// anything a dependency registers on the global one.
+var Registry = prometheus.NewRegistry()
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	requestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	postings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ledger_postings_total",
+		Help:      "Ledger transactions committed, by transaction type and operation.",
+	}, []string{"type", "operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		requestDuration,
+		requestsInFlight,
+		postings,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDB exports the connection pool statistics of db, e.g. open and in-use
+// connections and time spent waiting for one
+func RegisterDB(db *sql.DB, name string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RecordPosting counts a ledger transaction once it has been committed. txType is the
+// transactions.type value, e.g. "invoice" or "sales receipt".
+func RecordPosting(txType, operation string) {
+	postings.WithLabelValues(txType, operation).Inc()
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
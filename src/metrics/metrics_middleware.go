@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inFlight mirrors the in-flight gauge so shutdown can report what it is waiting for
+var inFlight atomic.Int64
+
+// Middleware records the latency of every request. Requests are labelled by route pattern,
+// e.g. /api/buildings/:id/invoices, so IDs in the path don't create a series each.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestsInFlight.Inc()
+		inFlight.Add(1)
+		defer func() {
+			requestsInFlight.Dec()
+			inFlight.Add(-1)
+		}()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		requestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// InFlight returns the number of requests currently being served
+func InFlight() int64 {
+	return inFlight.Load()
+}
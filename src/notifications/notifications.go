@@ -0,0 +1,214 @@
+// Package notifications sends invoices, statements, payment receipts and overdue reminders
+// to people by email and SMS.
+//
+// Sending a notification queues one row per channel in the delivery log; the Sender then
+// hands each row to the channel's Transport and records the outcome, retrying failures with
+// exponential backoff until the configured number of attempts. Subjects and bodies come from
+// per-building templates (text/template syntax with the category's variables, e.g.
+// {{.name}}), falling back to built-in defaults. Each person chooses the channels they get
+// per category; without a preference every channel with contact details is used.
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Channels
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
+var Channels = []string{ChannelEmail, ChannelSMS}
+
+// Categories
+const (
+	CategoryInvoice         = "invoice"
+	CategoryStatement       = "statement"
+	CategoryPaymentReceipt  = "payment_receipt"
+	CategoryOverdueReminder = "overdue_reminder"
+)
+
+var Categories = []string{CategoryInvoice, CategoryStatement, CategoryPaymentReceipt, CategoryOverdueReminder}
+
+// Delivery statuses
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+// commonVariables are available to the templates of every category
+var commonVariables = []string{"name", "building"}
+
+// Variables lists the template variables of each category besides the common ones
+var Variables = map[string][]string{
+	CategoryInvoice:         {"number", "date", "due_date", "amount", "balance"},
+	CategoryStatement:       {"start_date", "end_date", "balance"},
+	CategoryPaymentReceipt:  {"number", "date", "amount", "invoice_number"},
+	CategoryOverdueReminder: {"number", "due_date", "days_overdue", "balance"},
+}
+
+type defaultTemplate struct {
+	subject string
+	body    string
+}
+
+var defaultTemplates = map[string]map[string]defaultTemplate{
+	CategoryInvoice: {
+		ChannelEmail: {
+			"Invoice {{.number}} from {{.building}}",
+			"Dear {{.name}},\n\nPlease find attached invoice {{.number}} dated {{.date}} for {{.amount}}, due on {{.due_date}}.\nThe balance due is {{.balance}}.\n\nThank you,\n{{.building}}\n",
+		},
+		ChannelSMS: {"", "{{.building}}: invoice {{.number}} for {{.amount}} is due on {{.due_date}}. Balance due {{.balance}}."},
+	},
+	CategoryStatement: {
+		ChannelEmail: {
+			"Statement of account from {{.building}}",
+			"Dear {{.name}},\n\nPlease find attached your statement of account from {{.start_date}} to {{.end_date}}.\nThe balance on {{.end_date}} is {{.balance}}.\n\nThank you,\n{{.building}}\n",
+		},
+		ChannelSMS: {"", "{{.building}}: your balance on {{.end_date}} is {{.balance}}."},
+	},
+	CategoryPaymentReceipt: {
+		ChannelEmail: {
+			"Payment receipt from {{.building}}",
+			"Dear {{.name}},\n\nThank you for your payment of {{.amount}} on {{.date}} against invoice {{.invoice_number}}.\nYour receipt is attached.\n\n{{.building}}\n",
+		},
+		ChannelSMS: {"", "{{.building}}: we received your payment of {{.amount}} on {{.date}} for invoice {{.invoice_number}}. Thank you."},
+	},
+	CategoryOverdueReminder: {
+		ChannelEmail: {
+			"Reminder: invoice {{.number}} is overdue",
+			"Dear {{.name}},\n\nInvoice {{.number}} was due on {{.due_date}} and is {{.days_overdue}} days overdue.\nThe balance due is {{.balance}}. Please arrange payment at your earliest convenience.\n\nThank you,\n{{.building}}\n",
+		},
+		ChannelSMS: {"", "{{.building}}: invoice {{.number}} is {{.days_overdue}} days overdue. Balance due {{.balance}}."},
+	},
+}
+
+// Notification is one message to one person on one channel, with its delivery status
+type Notification struct {
+	ID             int     `json:"id"`
+	BuildingID     int     `json:"building_id"`
+	PeopleID       int     `json:"people_id"`
+	Category       string  `json:"category"`
+	Channel        string  `json:"channel"`
+	Recipient      string  `json:"recipient"` // Email address or phone number
+	Subject        string  `json:"subject"`   // Empty for SMS
+	Body           string  `json:"body"`
+	ReferenceType  *string `json:"reference_type"` // e.g. "invoice"
+	ReferenceID    *int    `json:"reference_id"`
+	AttachmentName *string `json:"attachment_name"`
+	AttachmentPath *string `json:"-"` // Relative to the upload root
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	NextAttemptAt  string  `json:"next_attempt_at"`
+	LastError      *string `json:"last_error"`
+	SentAt         *string `json:"sent_at"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// Template is a building's subject and body for one category on one channel
+type Template struct {
+	ID         int     `json:"id"` // 0 while the building uses the default
+	BuildingID int     `json:"building_id"`
+	Category   string  `json:"category"`
+	Channel    string  `json:"channel"`
+	Subject    string  `json:"subject"` // Not used for SMS
+	Body       string  `json:"body"`
+	CreatedAt  *string `json:"created_at"`
+	UpdatedAt  *string `json:"updated_at"`
+}
+
+// Preference is the channels a person gets notifications of a category on
+type Preference struct {
+	Category string   `json:"category"`
+	Channels []string `json:"channels"` // Empty opts out of the category
+}
+
+// Message is what a Transport sends
+type Message struct {
+	Channel    string
+	To         string
+	Subject    string
+	Body       string
+	Attachment *Attachment
+}
+
+type Attachment struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// DefaultTemplate returns the template used for a category and channel the building has not
+// configured
+func DefaultTemplate(buildingID int, category string, channel string) Template {
+	template := defaultTemplates[category][channel]
+	return Template{
+		BuildingID: buildingID,
+		Category:   category,
+		Channel:    channel,
+		Subject:    template.subject,
+		Body:       template.body,
+	}
+}
+
+func IsChannel(channel string) bool {
+	for _, c := range Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func IsCategory(category string) bool {
+	_, ok := Variables[category]
+	return ok
+}
+
+// Render executes the template's subject and body with vars. A variable the category does not
+// define is an error.
+func (t Template) Render(vars map[string]string) (string, string, error) {
+	subject := ""
+	if t.Channel == ChannelEmail {
+		var err error
+		subject, err = execute("subject", t.Subject, vars)
+		if err != nil {
+			return "", "", err
+		}
+		// Header values must stay on one line
+		subject = strings.Join(strings.Fields(subject), " ")
+	}
+	body, err := execute("body", t.Body, vars)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+// sampleVariables returns a value for every variable of the category, for validating templates
+func sampleVariables(category string) map[string]string {
+	vars := map[string]string{}
+	for _, name := range append(append([]string{}, commonVariables...), Variables[category]...) {
+		vars[name] = name
+	}
+	return vars
+}
+
+func execute(name string, text string, vars map[string]string) (string, error) {
+	parsed, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, vars); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,125 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+)
+
+type UpdateTemplateRequest struct {
+	Subject string `json:"subject"` // Required for email, ignored for SMS
+	Body    string `json:"body" binding:"required"`
+}
+
+type UpdatePreferencesRequest struct {
+	Preferences []Preference `json:"preferences" binding:"required"`
+}
+
+// PreferencesResponse lists the person's channels for every category, defaults included
+type PreferencesResponse struct {
+	PeopleID    int          `json:"people_id"`
+	Email       string       `json:"email"`
+	Phone       string       `json:"phone"`
+	Preferences []Preference `json:"preferences"`
+}
+
+// SendRequest picks the channels of a send; empty uses the person's preferences
+type SendRequest struct {
+	Channels []string `json:"channels"`
+}
+
+// SendResponse lists the notifications queued by a send, one per channel
+type SendResponse struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+type VariablesResponse struct {
+	Category  string   `json:"category"`
+	Variables []string `json:"variables"`
+}
+
+func (r UpdateTemplateRequest) Validate(category string, channel string) map[string]string {
+	errors := make(map[string]string)
+	ValidateTemplate(category, channel, r.Subject, r.Body, errors)
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// ValidateTemplate checks the subject and body of a template of the category on the channel,
+// adding problems to errors under "subject" and "body"
+func ValidateTemplate(category string, channel string, subject string, body string, errors map[string]string) {
+	if channel == ChannelEmail {
+		if strings.TrimSpace(subject) == "" {
+			errors["subject"] = "Subject is required"
+		} else if len(subject) > 255 {
+			errors["subject"] = "Subject must be at most 255 characters"
+		}
+	}
+	if strings.TrimSpace(body) == "" {
+		errors["body"] = "Body is required"
+	} else if len(body) > 10000 {
+		errors["body"] = "Body must be at most 10000 characters"
+	}
+	if errors["subject"] != "" || errors["body"] != "" {
+		return
+	}
+
+	// Render with a value for every variable so that typos and unknown variables show up now
+	// rather than when the template is sent
+	vars := sampleVariables(category)
+	if channel == ChannelEmail {
+		if _, err := execute("subject", subject, vars); err != nil {
+			errors["subject"] = templateError(category, err)
+		}
+	}
+	if _, err := execute("body", body, vars); err != nil {
+		errors["body"] = templateError(category, err)
+	}
+}
+
+func (r UpdatePreferencesRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	seen := map[string]bool{}
+	for i, preference := range r.Preferences {
+		if !IsCategory(preference.Category) {
+			errors[fmt.Sprintf("preferences[%d].category", i)] = "Category must be one of: " + strings.Join(Categories, ", ")
+			continue
+		}
+		if seen[preference.Category] {
+			errors[fmt.Sprintf("preferences[%d].category", i)] = "Category is listed more than once"
+		}
+		seen[preference.Category] = true
+		validateChannels(preference.Channels, fmt.Sprintf("preferences[%d].channels", i), errors)
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func (r SendRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	validateChannels(r.Channels, "channels", errors)
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func validateChannels(channels []string, field string, errors map[string]string) {
+	seen := map[string]bool{}
+	for i, channel := range channels {
+		if !IsChannel(channel) {
+			errors[fmt.Sprintf("%s[%d]", field, i)] = "Channel must be one of: " + strings.Join(Channels, ", ")
+		} else if seen[channel] {
+			errors[fmt.Sprintf("%s[%d]", field, i)] = "Channel is listed more than once"
+		}
+		seen[channel] = true
+	}
+}
+
+func templateError(category string, err error) string {
+	variables := append(append([]string{}, commonVariables...), Variables[category]...)
+	return fmt.Sprintf("Template is invalid (%v); available variables: %s", err, strings.Join(variables, ", "))
+}
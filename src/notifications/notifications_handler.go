@@ -0,0 +1,244 @@
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+)
+
+type NotificationHandler struct {
+	service *NotificationService
+}
+
+func NewNotificationHandler(service *NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// GET /buildings/:id/notifications?status=failed&people_id=1
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	params, validationErrors := NotificationListSpec.Parse(c.Request.URL.Query())
+	if validationErrors == nil {
+		validationErrors = map[string]string{}
+	}
+	switch params.Filters.Status {
+	case "", StatusPending, StatusSent, StatusFailed:
+	default:
+		validationErrors["status"] = "Status must be pending, sent or failed"
+	}
+	if len(validationErrors) > 0 {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
+		return
+	}
+
+	notifications, err := h.service.List(buildingID, params)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// GET /buildings/:id/notifications/:notificationId
+func (h *NotificationHandler) GetNotification(c *gin.Context) {
+	buildingID, id, ok := notificationParams(c)
+	if !ok {
+		return
+	}
+
+	notification, err := h.service.Get(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// POST /buildings/:id/notifications/:notificationId/resend
+func (h *NotificationHandler) ResendNotification(c *gin.Context) {
+	buildingID, id, ok := notificationParams(c)
+	if !ok {
+		return
+	}
+
+	notification, err := h.service.WithLogger(logging.FromGin(c)).Resend(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// GET /buildings/:id/notification-templates
+func (h *NotificationHandler) GetTemplates(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	templates, err := h.service.ListTemplates(buildingID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GET /buildings/:id/notification-variables
+func (h *NotificationHandler) GetVariables(c *gin.Context) {
+	variables := make([]VariablesResponse, 0, len(Categories))
+	for _, category := range Categories {
+		variables = append(variables, VariablesResponse{
+			Category:  category,
+			Variables: append(append([]string{}, commonVariables...), Variables[category]...),
+		})
+	}
+
+	c.JSON(http.StatusOK, variables)
+}
+
+// GET /buildings/:id/notification-templates/:category/:channel
+func (h *NotificationHandler) GetTemplate(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	template, err := h.service.GetTemplate(buildingID, c.Param("category"), c.Param("channel"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// PUT /buildings/:id/notification-templates/:category/:channel
+func (h *NotificationHandler) UpdateTemplate(c *gin.Context) {
+	var req UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	template, validationErr, err := h.service.WithLogger(logging.FromGin(c)).UpdateTemplate(buildingID, c.Param("category"), c.Param("channel"), req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DELETE /buildings/:id/notification-templates/:category/:channel
+func (h *NotificationHandler) ResetTemplate(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	template, err := h.service.WithLogger(logging.FromGin(c)).ResetTemplate(buildingID, c.Param("category"), c.Param("channel"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// GET /buildings/:id/people/:personId/notification-preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	buildingID, personID, ok := personParams(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.service.GetPreferences(buildingID, personID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// PUT /buildings/:id/people/:personId/notification-preferences
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, personID, ok := personParams(c)
+	if !ok {
+		return
+	}
+
+	preferences, validationErr, err := h.service.WithLogger(logging.FromGin(c)).UpdatePreferences(buildingID, personID, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// notificationParams reads the building and notification ids, responding when either is invalid
+func notificationParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("notificationId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Notification ID"))
+		return 0, 0, false
+	}
+	return buildingID, id, true
+}
+
+// personParams reads the building and person ids, responding when either is invalid
+func personParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	personID, err := strconv.Atoi(c.Param("personId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Person ID"))
+		return 0, 0, false
+	}
+	return buildingID, personID, true
+}
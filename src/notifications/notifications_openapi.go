@@ -0,0 +1,38 @@
+package notifications
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"NotificationHandler.GetNotifications": {
+		Summary:     "List the notification delivery log",
+		Description: "Filter with status=failed for notifications that used up their attempts.",
+		Response:    pagination.Page[Notification]{},
+		List:        &NotificationListSpec,
+	},
+	"NotificationHandler.GetNotification":    {Summary: "Get a notification", Response: Notification{}},
+	"NotificationHandler.ResendNotification": {Summary: "Send a notification again from its first attempt", Response: Notification{}},
+	"NotificationHandler.GetTemplates": {
+		Summary:     "List the building's notification templates",
+		Description: "Returns one template per category and channel. Templates that were never configured are returned with the defaults and id 0.",
+		Response:    []Template{},
+	},
+	"NotificationHandler.GetVariables": {Summary: "List the variables available to the templates of each category", Response: []VariablesResponse{}},
+	"NotificationHandler.GetTemplate":  {Summary: "Get the building's template of a category and channel", Response: Template{}},
+	"NotificationHandler.UpdateTemplate": {
+		Summary:     "Configure the building's template of a category and channel",
+		Description: "Subject and body use Go text/template syntax, e.g. {{.name}}. Using a variable the category does not have is a validation error. SMS templates have no subject.",
+		Request:     UpdateTemplateRequest{},
+		Response:    Template{},
+	},
+	"NotificationHandler.ResetTemplate":  {Summary: "Go back to the default template of a category and channel", Response: Template{}},
+	"NotificationHandler.GetPreferences": {Summary: "Get the channels a person is notified on per category", Response: PreferencesResponse{}},
+	"NotificationHandler.UpdatePreferences": {
+		Summary:     "Set the channels a person is notified on per category",
+		Description: "Categories left out keep their channels. An empty channel list opts the person out of the category.",
+		Request:     UpdatePreferencesRequest{},
+		Response:    PreferencesResponse{},
+	},
+}
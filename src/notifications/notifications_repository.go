@@ -0,0 +1,318 @@
+package notifications
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/dialect"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+type NotificationRepository interface {
+	Create(notification Notification) (Notification, error)
+	GetByID(buildingID int, id int) (Notification, error)
+	List(buildingID int, params pagination.Params) ([]Notification, int, error)
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]Notification, error)
+	MarkSent(id int, attempts int, now time.Time) error
+	MarkFailed(id int, attempts int, message string, nextAttemptAt *time.Time) error
+	Reset(id int, now time.Time) error
+	ListTemplates(buildingID int) ([]Template, error)
+	GetTemplate(buildingID int, category string, channel string) (Template, error)
+	SaveTemplate(template Template) error
+	DeleteTemplate(buildingID int, category string, channel string) error
+	GetPreferences(peopleID int) ([]Preference, error)
+	SavePreferences(buildingID int, peopleID int, preferences []Preference) error
+}
+
+type notificationRepo struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+const notificationColumns = "id, building_id, people_id, category, channel, recipient, subject, body, reference_type, reference_id, attachment_name, attachment_path, status, attempts, next_attempt_at, last_error, sent_at, created_at"
+
+const templateColumns = "id, building_id, category, channel, subject, body, created_at, updated_at"
+
+// NotificationListSpec describes the query string of the delivery log
+var NotificationListSpec = pagination.Spec{
+	IDColumn: "id",
+	Sorts: map[string]string{
+		"id":   "id",
+		"date": "created_at",
+	},
+	DefaultSort:   "-id",
+	DateColumn:    "created_at",
+	SearchColumns: []string{"recipient", "subject"},
+	StatusFilter:  "status = ?",
+	PeopleFilter:  "people_id = ?",
+}
+
+// NotificationSortKey returns the cursor values of a notification for the list's sort
+func NotificationSortKey(notification Notification, sort string) (interface{}, int) {
+	if sort == "date" {
+		return notification.CreatedAt, notification.ID
+	}
+	return notification.ID, notification.ID
+}
+
+func (r *notificationRepo) Create(n Notification) (Notification, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO notifications (building_id, people_id, category, channel, recipient, subject, body, reference_type, reference_id, attachment_name, attachment_path, status, attempts, next_attempt_at, created_at)"+
+			" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)",
+		n.BuildingID, n.PeopleID, n.Category, n.Channel, n.Recipient, n.Subject, n.Body, n.ReferenceType, n.ReferenceID,
+		n.AttachmentName, n.AttachmentPath, StatusPending, n.NextAttemptAt, n.CreatedAt,
+	)
+	if err != nil {
+		return Notification{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Notification{}, err
+	}
+	return r.GetByID(n.BuildingID, int(id))
+}
+
+func (r *notificationRepo) GetByID(buildingID int, id int) (Notification, error) {
+	return scanNotification(r.db.QueryRow("SELECT "+notificationColumns+" FROM notifications WHERE id = ? AND building_id = ?", id, buildingID))
+}
+
+func (r *notificationRepo) List(buildingID int, params pagination.Params) ([]Notification, int, error) {
+	where, args := NotificationListSpec.Where(params)
+	where = " WHERE building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM notifications"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := NotificationListSpec.After(params)
+	order, orderArgs := NotificationListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	rows, err := r.db.Query("SELECT "+notificationColumns+" FROM notifications"+where+after+order, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, total, rows.Err()
+}
+
+// ClaimDue returns pending notifications whose next attempt is due, pushing each one's next
+// attempt out by lease so that no other sender sends it meanwhile. A sender that dies
+// mid-attempt leaves the notification to be retried after the lease.
+func (r *notificationRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]Notification, error) {
+	nowStr := now.UTC().Format(timeLayout)
+
+	rows, err := r.db.Query(
+		"SELECT "+notificationColumns+" FROM notifications WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?",
+		StatusPending, nowStr, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		candidates = append(candidates, notification)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	leaseUntil := now.Add(lease).UTC().Format(timeLayout)
+	claimed := []Notification{}
+	for _, notification := range candidates {
+		result, err := r.db.Exec(
+			"UPDATE notifications SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?",
+			leaseUntil, notification.ID, StatusPending, nowStr,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			claimed = append(claimed, notification)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *notificationRepo) MarkSent(id int, attempts int, now time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE notifications SET status = ?, attempts = ?, last_error = NULL, sent_at = ? WHERE id = ?",
+		StatusSent, attempts, now.UTC().Format(timeLayout), id,
+	)
+	return err
+}
+
+// MarkFailed records a failed attempt. A nil nextAttemptAt gives up on the notification.
+func (r *notificationRepo) MarkFailed(id int, attempts int, message string, nextAttemptAt *time.Time) error {
+	if nextAttemptAt == nil {
+		_, err := r.db.Exec(
+			"UPDATE notifications SET status = ?, attempts = ?, last_error = ? WHERE id = ?",
+			StatusFailed, attempts, message, id,
+		)
+		return err
+	}
+	_, err := r.db.Exec(
+		"UPDATE notifications SET attempts = ?, last_error = ?, next_attempt_at = ? WHERE id = ?",
+		attempts, message, nextAttemptAt.UTC().Format(timeLayout), id,
+	)
+	return err
+}
+
+// Reset queues a notification to be sent again from its first attempt
+func (r *notificationRepo) Reset(id int, now time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE notifications SET status = ?, attempts = 0, next_attempt_at = ?, last_error = NULL, sent_at = NULL WHERE id = ?",
+		StatusPending, now.UTC().Format(timeLayout), id,
+	)
+	return err
+}
+
+func (r *notificationRepo) ListTemplates(buildingID int) ([]Template, error) {
+	rows, err := r.db.Query("SELECT "+templateColumns+" FROM notification_templates WHERE building_id = ? ORDER BY id", buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []Template{}
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+func (r *notificationRepo) GetTemplate(buildingID int, category string, channel string) (Template, error) {
+	return scanTemplate(r.db.QueryRow(
+		"SELECT "+templateColumns+" FROM notification_templates WHERE building_id = ? AND category = ? AND channel = ?",
+		buildingID, category, channel,
+	))
+}
+
+// SaveTemplate creates or replaces the building's template of the category and channel
+func (r *notificationRepo) SaveTemplate(template Template) error {
+	query := dialect.Current.Upsert(
+		"notification_templates",
+		[]string{"building_id", "category", "channel", "subject", "body", "updated_at"},
+		[]string{"building_id", "category", "channel"},
+		[]string{"subject", "body", "updated_at"},
+	)
+	_, err := r.db.Exec(query,
+		template.BuildingID, template.Category, template.Channel, template.Subject, template.Body, time.Now().UTC().Format(timeLayout),
+	)
+	return err
+}
+
+func (r *notificationRepo) DeleteTemplate(buildingID int, category string, channel string) error {
+	_, err := r.db.Exec("DELETE FROM notification_templates WHERE building_id = ? AND category = ? AND channel = ?", buildingID, category, channel)
+	return err
+}
+
+// GetPreferences returns the person's saved preferences; categories without one are absent
+func (r *notificationRepo) GetPreferences(peopleID int) ([]Preference, error) {
+	rows, err := r.db.Query("SELECT category, channels FROM notification_preferences WHERE people_id = ? ORDER BY id", peopleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	preferences := []Preference{}
+	for rows.Next() {
+		var preference Preference
+		var channels string
+		if err := rows.Scan(&preference.Category, &channels); err != nil {
+			return nil, err
+		}
+		preference.Channels = splitChannels(channels)
+		preferences = append(preferences, preference)
+	}
+	return preferences, rows.Err()
+}
+
+// SavePreferences creates or replaces the person's preference of each given category
+func (r *notificationRepo) SavePreferences(buildingID int, peopleID int, preferences []Preference) error {
+	query := dialect.Current.Upsert(
+		"notification_preferences",
+		[]string{"building_id", "people_id", "category", "channels", "updated_at"},
+		[]string{"people_id", "category"},
+		[]string{"channels", "updated_at"},
+	)
+	nowStr := time.Now().UTC().Format(timeLayout)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	for _, preference := range preferences {
+		if _, err := tx.Exec(query, buildingID, peopleID, preference.Category, strings.Join(preference.Channels, ","), nowStr); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanNotification(row scanner) (Notification, error) {
+	var n Notification
+	err := row.Scan(&n.ID, &n.BuildingID, &n.PeopleID, &n.Category, &n.Channel, &n.Recipient, &n.Subject, &n.Body,
+		&n.ReferenceType, &n.ReferenceID, &n.AttachmentName, &n.AttachmentPath, &n.Status, &n.Attempts, &n.NextAttemptAt,
+		&n.LastError, &n.SentAt, &n.CreatedAt)
+	return n, err
+}
+
+func scanTemplate(row scanner) (Template, error) {
+	var template Template
+	err := row.Scan(&template.ID, &template.BuildingID, &template.Category, &template.Channel, &template.Subject, &template.Body,
+		&template.CreatedAt, &template.UpdatedAt)
+	return template, err
+}
+
+func splitChannels(value string) []string {
+	channels := []string{}
+	for _, channel := range strings.Split(value, ",") {
+		if channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
@@ -0,0 +1,147 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/config"
+)
+
+const (
+	// batchSize bounds the notifications sent per poll
+	batchSize = 100
+	// sendTimeout bounds a single attempt
+	sendTimeout = 2 * time.Minute
+	// firstRetryDelay doubles after every failed attempt, up to maxRetryDelay
+	firstRetryDelay = time.Minute
+	maxRetryDelay   = 6 * time.Hour
+	// maxErrorLength bounds the error kept in last_error
+	maxErrorLength = 1000
+)
+
+// Sender sends queued notifications through the transport of their channel. Several
+// application instances can run one each: notifications are claimed atomically.
+type Sender struct {
+	repo        NotificationRepository
+	transports  map[string]Transport
+	logger      *slog.Logger
+	interval    time.Duration
+	maxAttempts int
+}
+
+func NewSender(repo NotificationRepository, transports map[string]Transport, logger *slog.Logger, interval time.Duration, maxAttempts int) *Sender {
+	return &Sender{
+		repo:        repo,
+		transports:  transports,
+		logger:      logger,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run sends due notifications every interval until ctx is cancelled. A send in progress
+// finishes before Run returns.
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.SendOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendOnce sends the notifications that are due. Each one is claimed just before it is
+// sent, so its lease only has to outlast its own attempt, not the ones queued before it.
+func (s *Sender) SendOnce(ctx context.Context) {
+	for sent := 0; sent < batchSize && ctx.Err() == nil; sent++ {
+		notifications, err := s.repo.ClaimDue(time.Now(), sendTimeout+time.Minute, 1)
+		if err != nil {
+			s.logger.Error("failed to load due notifications", "error", err.Error())
+			return
+		}
+		if len(notifications) == 0 {
+			return
+		}
+		s.send(notifications[0])
+	}
+}
+
+func (s *Sender) send(notification Notification) {
+	logger := s.logger.With("notification_id", notification.ID, "channel", notification.Channel, "category", notification.Category)
+	attempts := notification.Attempts + 1
+
+	err := s.deliver(notification)
+	if err == nil {
+		if err := s.repo.MarkSent(notification.ID, attempts, time.Now()); err != nil {
+			logger.Error("failed to record notification delivery", "error", err.Error())
+		}
+		logger.Debug("notification sent", "attempts", attempts)
+		return
+	}
+
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+
+	var nextAttemptAt *time.Time
+	if attempts < s.maxAttempts {
+		next := time.Now().Add(retryDelay(attempts))
+		nextAttemptAt = &next
+		logger.Warn("notification delivery failed", "error", message, "attempts", attempts, "next_attempt_at", next.UTC().Format(timeLayout))
+	} else {
+		logger.Error("notification delivery gave up", "error", message, "attempts", attempts)
+	}
+
+	if err := s.repo.MarkFailed(notification.ID, attempts, message, nextAttemptAt); err != nil {
+		logger.Error("failed to record notification delivery", "error", err.Error())
+	}
+}
+
+// deliver hands one attempt to the channel's transport
+func (s *Sender) deliver(notification Notification) error {
+	transport, ok := s.transports[notification.Channel]
+	if !ok {
+		return fmt.Errorf("no transport for channel %s", notification.Channel)
+	}
+
+	message := Message{
+		Channel: notification.Channel,
+		To:      notification.Recipient,
+		Subject: notification.Subject,
+		Body:    notification.Body,
+	}
+	if notification.AttachmentPath != nil && notification.AttachmentName != nil {
+		content, err := os.ReadFile(filepath.Join(config.UploadRoot(), *notification.AttachmentPath))
+		if err != nil {
+			return fmt.Errorf("failed to read attachment: %w", err)
+		}
+		message.Attachment = &Attachment{Name: *notification.AttachmentName, ContentType: http.DetectContentType(content), Content: content}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	return transport.Send(ctx, message)
+}
+
+// retryDelay returns the wait after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
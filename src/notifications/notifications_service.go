@@ -0,0 +1,380 @@
+package notifications
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/config"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+	"github.com/mysecodgit/go_accounting/src/people"
+)
+
+var unsafeAttachmentChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// NotifyRequest asks for a person to be notified. The category's variables besides name and
+// building, which are filled in here, must all be set.
+type NotifyRequest struct {
+	BuildingID    int
+	PeopleID      int
+	Category      string
+	Channels      []string // Empty uses the person's preferences
+	ReferenceType string   // What the notification is about, e.g. "invoice"
+	ReferenceID   int
+	Variables     map[string]string
+	Attachment    *Attachment // Sent by email only
+	// Template replaces the building's template of the category and limits the send to the
+	// template's channel, e.g. for a dunning step
+	Template *Template
+}
+
+type NotificationService struct {
+	repo       NotificationRepository
+	peopleRepo people.PersonRepository
+	logger     *slog.Logger
+}
+
+func NewNotificationService(repo NotificationRepository, peopleRepo people.PersonRepository, logger *slog.Logger) *NotificationService {
+	return &NotificationService{repo: repo, peopleRepo: peopleRepo, logger: logger}
+}
+
+// WithLogger returns a copy of the service that logs to logger, e.g. the request's logger
+func (s *NotificationService) WithLogger(logger *slog.Logger) *NotificationService {
+	copy := *s
+	copy.logger = logger
+	return &copy
+}
+
+// Notify renders the category's templates and queues one notification per channel the person
+// has contact details for. It fails when no channel is left.
+func (s *NotificationService) Notify(req NotifyRequest) ([]Notification, error) {
+	person, _, building, err := s.peopleRepo.GetByID(req.PeopleID)
+	if err != nil {
+		return nil, apperrors.Lookup("person", err)
+	}
+	if person.BuildingID != req.BuildingID {
+		return nil, apperrors.NotFound("person")
+	}
+
+	channels := req.Channels
+	if len(channels) == 0 {
+		channels, err = s.preferredChannels(person.ID, req.Category)
+		if err != nil {
+			return nil, err
+		}
+		if len(channels) == 0 {
+			return nil, apperrors.Rulef("%s has opted out of %s notifications", person.Name, categoryLabel(req.Category))
+		}
+	}
+	if req.Template != nil {
+		wanted := false
+		for _, channel := range channels {
+			wanted = wanted || channel == req.Template.Channel
+		}
+		if !wanted {
+			return nil, apperrors.Rulef("%s has opted out of %s notifications by %s", person.Name, categoryLabel(req.Category), req.Template.Channel)
+		}
+		channels = []string{req.Template.Channel}
+	}
+
+	vars := map[string]string{"name": person.Name, "building": building.Name}
+	for name, value := range req.Variables {
+		vars[name] = value
+	}
+
+	type pending struct {
+		template  Template
+		recipient string
+	}
+	queue := []pending{}
+	for _, channel := range channels {
+		recipient := person.Email
+		if channel == ChannelSMS {
+			recipient = person.Phone
+		}
+		if recipient == "" {
+			continue
+		}
+		if req.Template != nil {
+			queue = append(queue, pending{*req.Template, recipient})
+			continue
+		}
+		template, err := s.GetTemplate(req.BuildingID, req.Category, channel)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, pending{template, recipient})
+	}
+	if len(queue) == 0 {
+		return nil, apperrors.Rulef("%s has no %s", person.Name, missingContact(channels))
+	}
+
+	var referenceType *string
+	var referenceID *int
+	if req.ReferenceType != "" {
+		referenceType = &req.ReferenceType
+		referenceID = &req.ReferenceID
+	}
+
+	now := time.Now().UTC().Format(timeLayout)
+	notifications := []Notification{}
+	for _, item := range queue {
+		subject, body, err := item.template.Render(vars)
+		if err != nil {
+			return nil, apperrors.Rulef("The %s %s template cannot be rendered: %v", categoryLabel(req.Category), item.template.Channel, err)
+		}
+
+		notification := Notification{
+			BuildingID:    req.BuildingID,
+			PeopleID:      person.ID,
+			Category:      req.Category,
+			Channel:       item.template.Channel,
+			Recipient:     item.recipient,
+			Subject:       subject,
+			Body:          body,
+			ReferenceType: referenceType,
+			ReferenceID:   referenceID,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if req.Attachment != nil && item.template.Channel == ChannelEmail {
+			path, err := saveAttachment(req.BuildingID, *req.Attachment)
+			if err != nil {
+				return nil, err
+			}
+			notification.AttachmentName = &req.Attachment.Name
+			notification.AttachmentPath = &path
+		}
+
+		created, err := s.repo.Create(notification)
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue notification: %w", err)
+		}
+		notifications = append(notifications, created)
+	}
+
+	s.logger.Info("notifications queued", "people_id", person.ID, "category", req.Category, "count", len(notifications),
+		"reference_type", req.ReferenceType, "reference_id", req.ReferenceID)
+	return notifications, nil
+}
+
+func (s *NotificationService) List(buildingID int, params pagination.Params) (pagination.Page[Notification], error) {
+	notifications, total, err := s.repo.List(buildingID, params)
+	if err != nil {
+		return pagination.Page[Notification]{}, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return pagination.NewPage(notifications, total, params, NotificationSortKey), nil
+}
+
+func (s *NotificationService) Get(buildingID int, id int) (Notification, error) {
+	notification, err := s.repo.GetByID(buildingID, id)
+	if err != nil {
+		return Notification{}, apperrors.Lookup("notification", err)
+	}
+	return notification, nil
+}
+
+// Resend queues a notification to be sent again from its first attempt, typically a failed one
+func (s *NotificationService) Resend(buildingID int, id int) (Notification, error) {
+	if _, err := s.Get(buildingID, id); err != nil {
+		return Notification{}, err
+	}
+	if err := s.repo.Reset(id, time.Now()); err != nil {
+		return Notification{}, fmt.Errorf("failed to resend notification: %w", err)
+	}
+
+	s.logger.Info("notification resent", "notification_id", id)
+	return s.Get(buildingID, id)
+}
+
+// ListTemplates returns the building's template of every category and channel, defaults included
+func (s *NotificationService) ListTemplates(buildingID int) ([]Template, error) {
+	saved, err := s.repo.ListTemplates(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification templates: %w", err)
+	}
+	byKey := map[string]Template{}
+	for _, template := range saved {
+		byKey[template.Category+"/"+template.Channel] = template
+	}
+
+	templates := make([]Template, 0, len(Categories)*len(Channels))
+	for _, category := range Categories {
+		for _, channel := range Channels {
+			template, ok := byKey[category+"/"+channel]
+			if !ok {
+				template = DefaultTemplate(buildingID, category, channel)
+			}
+			templates = append(templates, template)
+		}
+	}
+	return templates, nil
+}
+
+// GetTemplate returns the building's template of a category and channel, or the default
+func (s *NotificationService) GetTemplate(buildingID int, category string, channel string) (Template, error) {
+	if !IsCategory(category) {
+		return Template{}, apperrors.NotFound("notification category")
+	}
+	if !IsChannel(channel) {
+		return Template{}, apperrors.NotFound("notification channel")
+	}
+	template, err := s.repo.GetTemplate(buildingID, category, channel)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultTemplate(buildingID, category, channel), nil
+	}
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to load notification template: %w", err)
+	}
+	return template, nil
+}
+
+func (s *NotificationService) UpdateTemplate(buildingID int, category string, channel string, req UpdateTemplateRequest) (*Template, map[string]string, error) {
+	template, err := s.GetTemplate(buildingID, category, channel)
+	if err != nil {
+		return nil, nil, err
+	}
+	if validationErrors := req.Validate(category, channel); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	template.Subject = req.Subject
+	if channel == ChannelSMS {
+		template.Subject = ""
+	}
+	template.Body = req.Body
+	if err := s.repo.SaveTemplate(template); err != nil {
+		return nil, nil, fmt.Errorf("failed to save notification template: %w", err)
+	}
+
+	s.logger.Info("notification template updated", "category", category, "channel", channel)
+	saved, err := s.GetTemplate(buildingID, category, channel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &saved, nil, nil
+}
+
+// ResetTemplate drops the building's template so that the default is used again
+func (s *NotificationService) ResetTemplate(buildingID int, category string, channel string) (Template, error) {
+	if _, err := s.GetTemplate(buildingID, category, channel); err != nil {
+		return Template{}, err
+	}
+	if err := s.repo.DeleteTemplate(buildingID, category, channel); err != nil {
+		return Template{}, fmt.Errorf("failed to reset notification template: %w", err)
+	}
+
+	s.logger.Info("notification template reset", "category", category, "channel", channel)
+	return DefaultTemplate(buildingID, category, channel), nil
+}
+
+// GetPreferences returns the person's channels for every category
+func (s *NotificationService) GetPreferences(buildingID int, peopleID int) (*PreferencesResponse, error) {
+	person, err := s.person(buildingID, peopleID)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := s.repo.GetPreferences(peopleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+	byCategory := map[string][]string{}
+	for _, preference := range saved {
+		byCategory[preference.Category] = preference.Channels
+	}
+
+	response := &PreferencesResponse{PeopleID: person.ID, Email: person.Email, Phone: person.Phone, Preferences: []Preference{}}
+	for _, category := range Categories {
+		channels, ok := byCategory[category]
+		if !ok {
+			channels = Channels
+		}
+		response.Preferences = append(response.Preferences, Preference{Category: category, Channels: channels})
+	}
+	return response, nil
+}
+
+// UpdatePreferences saves the channels of the listed categories; other categories keep theirs
+func (s *NotificationService) UpdatePreferences(buildingID int, peopleID int, req UpdatePreferencesRequest) (*PreferencesResponse, map[string]string, error) {
+	if _, err := s.person(buildingID, peopleID); err != nil {
+		return nil, nil, err
+	}
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	if err := s.repo.SavePreferences(buildingID, peopleID, req.Preferences); err != nil {
+		return nil, nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+
+	s.logger.Info("notification preferences updated", "people_id", peopleID)
+	response, err := s.GetPreferences(buildingID, peopleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, nil, nil
+}
+
+func (s *NotificationService) person(buildingID int, peopleID int) (people.Person, error) {
+	person, _, _, err := s.peopleRepo.GetByID(peopleID)
+	if err != nil {
+		return people.Person{}, apperrors.Lookup("person", err)
+	}
+	if person.BuildingID != buildingID {
+		return people.Person{}, apperrors.NotFound("person")
+	}
+	return person, nil
+}
+
+// preferredChannels returns the channels the person wants for the category; all of them
+// unless they said otherwise
+func (s *NotificationService) preferredChannels(peopleID int, category string) ([]string, error) {
+	preferences, err := s.repo.GetPreferences(peopleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+	for _, preference := range preferences {
+		if preference.Category == category {
+			return preference.Channels, nil
+		}
+	}
+	return Channels, nil
+}
+
+// saveAttachment stores an attachment under the upload root until it is sent, returning its
+// path relative to the root
+func saveAttachment(buildingID int, attachment Attachment) (string, error) {
+	dir := filepath.Join("notifications", fmt.Sprintf("building_%d", buildingID))
+	if err := os.MkdirAll(filepath.Join(config.UploadRoot(), dir), 0755); err != nil {
+		return "", apperrors.Wrap(apperrors.CodeInternal, "Failed to create upload directory", err)
+	}
+	relative := filepath.Join(dir, fmt.Sprintf("%d_%s", time.Now().UnixNano(), unsafeAttachmentChars.ReplaceAllString(attachment.Name, "_")))
+	if err := os.WriteFile(filepath.Join(config.UploadRoot(), relative), attachment.Content, 0644); err != nil {
+		return "", apperrors.Wrap(apperrors.CodeInternal, "Failed to save attachment", err)
+	}
+	return relative, nil
+}
+
+func categoryLabel(category string) string {
+	return strings.ReplaceAll(category, "_", " ")
+}
+
+// missingContact names the contact details the channels need, for an error message
+func missingContact(channels []string) string {
+	details := []string{}
+	for _, channel := range channels {
+		if channel == ChannelEmail {
+			details = append(details, "email address")
+		} else {
+			details = append(details, "phone number")
+		}
+	}
+	return strings.Join(details, " or ")
+}
@@ -0,0 +1,242 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/config"
+)
+
+// Transport sends a message on one channel. Implementations must be safe for concurrent use.
+type Transport interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewTransports returns the configured transport of each channel
+func NewTransports(cfg config.NotificationConfig, logger *slog.Logger) map[string]Transport {
+	transports := map[string]Transport{}
+
+	switch cfg.EmailTransport {
+	case "smtp":
+		transports[ChannelEmail] = &SMTPTransport{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		}
+	case "file":
+		transports[ChannelEmail] = &FileTransport{Dir: cfg.OutputDir, From: cfg.SMTP.From}
+	default:
+		transports[ChannelEmail] = &ConsoleTransport{Logger: logger}
+	}
+
+	switch cfg.SMSTransport {
+	case "http":
+		transports[ChannelSMS] = &HTTPSMSTransport{
+			URL:    cfg.SMSGateway.URL,
+			Token:  cfg.SMSGateway.Token,
+			Sender: cfg.SMSGateway.Sender,
+			Client: &http.Client{Timeout: cfg.SMSGateway.TimeoutDuration()},
+		}
+	case "file":
+		transports[ChannelSMS] = &FileTransport{Dir: cfg.OutputDir}
+	default:
+		transports[ChannelSMS] = &ConsoleTransport{Logger: logger}
+	}
+
+	return transports
+}
+
+// SMTPTransport sends email through an SMTP server, upgrading to TLS with STARTTLS when the
+// server offers it
+type SMTPTransport struct {
+	Host     string
+	Port     int
+	Username string // Empty sends without authentication
+	Password string
+	From     string
+}
+
+func (t *SMTPTransport) Send(ctx context.Context, message Message) error {
+	from, err := mail.ParseAddress(t.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	content, err := buildEmail(t.From, message)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if t.Username != "" {
+		auth = smtp.PlainAuth("", t.Username, t.Password, t.Host)
+	}
+	address := net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(address, auth, from.Address, []string{message.To}, content)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HTTPSMSTransport posts each SMS as JSON {"from", "to", "message"} to a gateway URL. Any
+// 2xx response counts as accepted.
+type HTTPSMSTransport struct {
+	URL    string
+	Token  string // Sent as "Authorization: Bearer <token>" when set
+	Sender string
+	Client *http.Client
+}
+
+func (t *HTTPSMSTransport) Send(ctx context.Context, message Message) error {
+	body, err := json.Marshal(map[string]string{"from": t.Sender, "to": message.To, "message": message.Body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go_accounting-notifications")
+	if t.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.Token)
+	}
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, excerpt)
+	}
+	return nil
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._@+-]+`)
+
+// FileTransport writes each message to a file in Dir instead of sending it: emails as .eml
+// files that mail clients can open, SMS as .txt files. Meant for local testing.
+type FileTransport struct {
+	Dir  string
+	From string // Sender of the written emails; a placeholder when empty
+}
+
+func (t *FileTransport) Send(ctx context.Context, message Message) error {
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return err
+	}
+
+	var content []byte
+	ext := ".txt"
+	if message.Channel == ChannelEmail {
+		from := t.From
+		if from == "" {
+			from = "notifications@localhost"
+		}
+		var err error
+		content, err = buildEmail(from, message)
+		if err != nil {
+			return err
+		}
+		ext = ".eml"
+	} else {
+		content = []byte(fmt.Sprintf("To: %s\n\n%s\n", message.To, message.Body))
+	}
+
+	name := fmt.Sprintf("%s-%s-%s%s", time.Now().UTC().Format("20060102T150405.000000000"), message.Channel,
+		unsafeFilenameChars.ReplaceAllString(message.To, "_"), ext)
+	return os.WriteFile(filepath.Join(t.Dir, name), content, 0644)
+}
+
+// ConsoleTransport logs each message instead of sending it. Meant for local testing.
+type ConsoleTransport struct {
+	Logger *slog.Logger
+}
+
+func (t *ConsoleTransport) Send(ctx context.Context, message Message) error {
+	attrs := []any{"channel", message.Channel, "to", message.To, "body", message.Body}
+	if message.Subject != "" {
+		attrs = append(attrs, "subject", message.Subject)
+	}
+	if message.Attachment != nil {
+		attrs = append(attrs, "attachment", message.Attachment.Name, "attachment_size", len(message.Attachment.Content))
+	}
+	t.Logger.Info("notification written to console", attrs...)
+	return nil
+}
+
+// buildEmail returns a MIME message: plain text, or multipart/mixed when there is an attachment
+func buildEmail(from string, message Message) ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "From: %s\r\n", from)
+	fmt.Fprintf(&out, "To: %s\r\n", message.To)
+	fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&out, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	out.WriteString("MIME-Version: 1.0\r\n")
+
+	body := base64Lines([]byte(message.Body))
+	if message.Attachment == nil {
+		out.WriteString("Content-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: base64\r\n\r\n")
+		out.WriteString(body)
+		return out.Bytes(), nil
+	}
+
+	boundaryBytes := make([]byte, 16)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return nil, err
+	}
+	boundary := hex.EncodeToString(boundaryBytes)
+	contentType := message.Attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	fmt.Fprintf(&out, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", boundary)
+	fmt.Fprintf(&out, "--%s\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: base64\r\n\r\n%s", boundary, body)
+	fmt.Fprintf(&out, "--%s\r\nContent-Type: %s\r\nContent-Transfer-Encoding: base64\r\n", boundary, contentType)
+	fmt.Fprintf(&out, "Content-Disposition: %s\r\n\r\n", mime.FormatMediaType("attachment", map[string]string{"filename": message.Attachment.Name}))
+	out.WriteString(base64Lines(message.Attachment.Content))
+	fmt.Fprintf(&out, "--%s--\r\n", boundary)
+	return out.Bytes(), nil
+}
+
+// base64Lines encodes content in lines of 76 characters, as MIME requires
+func base64Lines(content []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	var out strings.Builder
+	for len(encoded) > 76 {
+		out.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	out.WriteString(encoded + "\r\n")
+	return out.String()
+}
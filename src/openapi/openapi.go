@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+const Version = "3.0.3"
+
+// Document is the subset of an OpenAPI 3 document this API needs
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+	Tags       []Tag                `json:"tags,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type Components struct {
+	Schemas    map[string]*Schema    `json:"schemas"`
+	Parameters map[string]*Parameter `json:"parameters,omitempty"`
+	Responses  map[string]*Response  `json:"responses,omitempty"`
+}
+
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	// Go field order and whether a referenced struct was a pointer, used by the
+	// client generator; not part of the document
+	order   []string
+	pointer bool
+}
+
+// Handlers describes the operations of one handler type, keyed by "HandlerType.Method",
+// e.g. "InvoiceHandler.CreateInvoice". Each package declares its own next to its DTOs.
+type Handlers map[string]Endpoint
+
+// Endpoint documents a handler. Request and Response are zero values of the body types.
+type Endpoint struct {
+	Summary     string
+	Description string
+	Request     interface{}
+	Response    interface{}
+	Query       []Param
+	// List adds limit, sort, cursor and the filters the list supports
+	List *pagination.Spec
+	// UserID marks handlers that need the User-ID header
+	UserID bool
+	// Bearer marks tenant portal handlers that need a session token in Authorization
+	Bearer bool
+	// IfMatch marks updates that require the document version in If-Match
+	IfMatch bool
+	// Idempotent marks creates that accept an Idempotency-Key header
+	Idempotent bool
+	// Export marks reports that can also be downloaded as CSV, XLSX or PDF
+	Export bool
+	// Upload is the multipart field name of an uploaded file
+	Upload string
+	// Download marks handlers that return a file
+	Download bool
+}
+
+type Param struct {
+	Name        string
+	Type        string // integer, number, string or boolean; string when empty
+	Format      string
+	Enum        []string
+	Description string
+	Required    bool
+	// Array marks parameters that may be repeated, e.g. ?account_id=1&account_id=2
+	Array bool
+}
+
+// OneOf documents a handler that returns one of several bodies
+type OneOf []interface{}
+
+// Message is the body of handlers that only confirm an action
+type Message struct {
+	Message string `json:"message"`
+}
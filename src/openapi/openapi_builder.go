@@ -0,0 +1,335 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/export"
+	"github.com/mysecodgit/go_accounting/src/idempotency"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+const (
+	Title      = "Go Accounting API"
+	APIVersion = "1.0.0"
+
+	// buildingScope prefixes the current routes; the same handlers mounted elsewhere are legacy
+	buildingScope = "/api/buildings/:id/"
+)
+
+// handlerName matches gin's name for a method value, e.g.
+// github.com/mysecodgit/go_accounting/src/invoices.(*InvoiceHandler).CreateInvoice-fm
+var handlerName = regexp.MustCompile(`/src/(\w+)\.\(\*(\w+)\)\.(\w+)-fm$`)
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+type route struct {
+	method  string
+	path    string
+	pkg     string
+	key     string
+	name    string
+	legacy  bool
+	handler Endpoint
+}
+
+// Build generates the document for the registered routes. Routes served by a handler
+// method without an Endpoint are returned as undocumented and left out of the document.
+func Build(routes gin.RoutesInfo, handlers ...Handlers) (*Document, []string) {
+	endpoints := make(Handlers)
+	for _, h := range handlers {
+		for key, endpoint := range h {
+			endpoints[key] = endpoint
+		}
+	}
+
+	var documented []route
+	var undocumented []string
+	uses := make(map[string]int)
+	for _, info := range routes {
+		match := handlerName.FindStringSubmatch(info.Handler)
+		// The document and docs page don't describe themselves
+		if match == nil || match[1] == "openapi" {
+			continue
+		}
+		key := match[2] + "." + match[3]
+		endpoint, ok := endpoints[key]
+		if !ok {
+			undocumented = append(undocumented, fmt.Sprintf("%s %s (%s)", info.Method, info.Path, key))
+			continue
+		}
+		documented = append(documented, route{method: info.Method, path: info.Path, pkg: match[1], key: key, name: match[3], handler: endpoint})
+		uses[key]++
+	}
+	sort.Slice(documented, func(i, j int) bool {
+		if documented[i].path != documented[j].path {
+			return documented[i].path < documented[j].path
+		}
+		return documented[i].method < documented[j].method
+	})
+	sort.Strings(undocumented)
+
+	// A handler mounted under a building and elsewhere is only current under the building
+	for i := range documented {
+		r := &documented[i]
+		if uses[r.key] > 1 && !strings.HasPrefix(r.path, buildingScope) {
+			r.legacy = true
+			r.name += "Legacy"
+		}
+	}
+	ids := make(map[string]int)
+	for i := range documented {
+		r := &documented[i]
+		ids[r.name]++
+		if ids[r.name] > 1 {
+			r.name = fmt.Sprintf("%s%d", r.name, ids[r.name])
+		}
+	}
+
+	s := newSchemas()
+	s.collect(reflect.TypeOf(apperrors.Envelope{}))
+	for _, r := range documented {
+		for _, body := range []interface{}{r.handler.Request, r.handler.Response} {
+			for _, value := range bodies(body) {
+				s.collect(reflect.TypeOf(value))
+			}
+		}
+	}
+	s.assignNames()
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   Title,
+			Version: APIVersion,
+			Description: "Errors use a single envelope: {\"error\": {\"code\", \"message\", \"fields\", \"details\"}}. " +
+				"Routes outside /api/buildings/{id} that duplicate building routes are deprecated.",
+		},
+		Paths: make(map[string]*PathItem),
+		Components: Components{
+			Schemas:    s.components,
+			Parameters: sharedParameters(),
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "Error",
+					Content:     jsonContent(s.schema(reflect.TypeOf(apperrors.Envelope{}))),
+				},
+			},
+		},
+	}
+
+	tags := make(map[string]bool)
+	for _, r := range documented {
+		path := pathParam.ReplaceAllString(r.path, "{$1}")
+		item := doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		tag := strings.ReplaceAll(r.pkg, "_", "-")
+		tags[tag] = true
+		op := s.operation(r, tag)
+		switch r.method {
+		case http.MethodGet:
+			item.Get = op
+		case http.MethodPost:
+			item.Post = op
+		case http.MethodPut:
+			item.Put = op
+		case http.MethodPatch:
+			item.Patch = op
+		case http.MethodDelete:
+			item.Delete = op
+		}
+	}
+	for _, tag := range sortedKeys(tags) {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+
+	return doc, undocumented
+}
+
+func (s *schemas) operation(r route, tag string) *Operation {
+	e := r.handler
+	op := &Operation{
+		OperationID: r.name,
+		Summary:     e.Summary,
+		Description: e.Description,
+		Tags:        []string{tag},
+		Deprecated:  r.legacy,
+		Responses:   map[string]*Response{"default": {Ref: "#/components/responses/Error"}},
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(r.path, -1) {
+		description := ""
+		if match[1] == "id" && strings.HasPrefix(r.path, buildingScope) {
+			description = "Building ID"
+		}
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name: match[1], In: "path", Required: true, Description: description,
+			Schema: &Schema{Type: "integer", Format: "int32"},
+		})
+	}
+	if e.UserID {
+		op.Parameters = append(op.Parameters, &Parameter{Ref: "#/components/parameters/UserID"})
+	}
+	if e.Bearer {
+		op.Parameters = append(op.Parameters, &Parameter{Ref: "#/components/parameters/Authorization"})
+		op.Responses["401"] = &Response{Ref: "#/components/responses/Error"}
+	}
+	if e.IfMatch {
+		op.Parameters = append(op.Parameters, &Parameter{Ref: "#/components/parameters/IfMatch"})
+		op.Responses["412"] = &Response{Ref: "#/components/responses/Error"}
+		op.Responses["428"] = &Response{Ref: "#/components/responses/Error"}
+	}
+	if e.Idempotent {
+		op.Parameters = append(op.Parameters, &Parameter{Ref: "#/components/parameters/IdempotencyKey"})
+	}
+	if e.List != nil {
+		op.Parameters = append(op.Parameters, listParameters(*e.List)...)
+	}
+	for _, p := range e.Query {
+		op.Parameters = append(op.Parameters, queryParameter(p))
+	}
+	if e.Export {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name: "format", In: "query",
+			Description: "Download the report as a file instead of JSON; the Accept header is used when omitted",
+			Schema:      &Schema{Type: "string", Enum: []string{export.FormatJSON, export.FormatCSV, export.FormatXLSX, export.FormatPDF}},
+		})
+	}
+
+	if e.Request != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(s.schema(reflect.TypeOf(e.Request)))}
+	}
+	if e.Upload != "" {
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{
+			"multipart/form-data": {Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{e.Upload: {Type: "string", Format: "binary"}},
+				order:      []string{e.Upload},
+			}},
+		}}
+	}
+
+	ok := &Response{Description: "OK"}
+	switch {
+	case e.Download:
+		ok.Content = map[string]*MediaType{"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}}}
+	case e.Response != nil:
+		ok.Content = jsonContent(s.body(e.Response))
+	}
+	if e.Export {
+		for _, format := range []string{export.FormatCSV, export.FormatXLSX, export.FormatPDF} {
+			ok.Content[export.ContentType(format)] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	}
+	if e.IfMatch {
+		ok.Headers = map[string]*Header{"ETag": {Description: "The new document version", Schema: &Schema{Type: "string"}}}
+	}
+	op.Responses["200"] = ok
+
+	return op
+}
+
+func (s *schemas) body(value interface{}) *Schema {
+	if oneOf, ok := value.(OneOf); ok {
+		schema := &Schema{}
+		for _, v := range oneOf {
+			schema.OneOf = append(schema.OneOf, s.schema(reflect.TypeOf(v)))
+		}
+		return schema
+	}
+	return s.schema(reflect.TypeOf(value))
+}
+
+func bodies(value interface{}) []interface{} {
+	if value == nil {
+		return nil
+	}
+	if oneOf, ok := value.(OneOf); ok {
+		return oneOf
+	}
+	return []interface{}{value}
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+func sharedParameters() map[string]*Parameter {
+	return map[string]*Parameter{
+		"UserID": {
+			Name: "User-ID", In: "header", Required: true,
+			Description: "ID of the user making the change",
+			Schema:      &Schema{Type: "integer", Format: "int32"},
+		},
+		"Authorization": {
+			Name: "Authorization", In: "header", Required: true,
+			Description: "Tenant portal session token from sign-in, as \"Bearer <token>\"",
+			Schema:      &Schema{Type: "string"},
+		},
+		"IfMatch": {
+			Name: "If-Match", In: "header", Required: true,
+			Description: "Version of the document being updated, from its ETag or version field, e.g. \"3\"",
+			Schema:      &Schema{Type: "string"},
+		},
+		"IdempotencyKey": {
+			Name: idempotency.HeaderKey, In: "header",
+			Description: "Retrying with the same key returns the first response instead of posting twice",
+			Schema:      &Schema{Type: "string"},
+		},
+	}
+}
+
+func queryParameter(p Param) *Parameter {
+	schema := &Schema{Type: p.Type, Format: p.Format, Enum: p.Enum}
+	if schema.Type == "" {
+		schema.Type = "string"
+	}
+	if p.Array {
+		schema = &Schema{Type: "array", Items: schema}
+	}
+	return &Parameter{Name: p.Name, In: "query", Description: p.Description, Required: p.Required, Schema: schema}
+}
+
+// listParameters documents the query string read by pagination.Spec.Parse
+func listParameters(spec pagination.Spec) []*Parameter {
+	sorts := []string{}
+	for _, name := range sortedKeys(spec.Sorts) {
+		sorts = append(sorts, name, "-"+name)
+	}
+	params := []*Parameter{
+		queryParameter(Param{Name: "limit", Type: "integer", Format: "int32", Description: fmt.Sprintf("Page size, 1 to %d (default %d)", pagination.MaxLimit, pagination.DefaultLimit)}),
+		{Name: "sort", In: "query", Description: "Sort field; prefix with - for descending (default " + spec.DefaultSort + ")", Schema: &Schema{Type: "string", Enum: sorts}},
+		queryParameter(Param{Name: "cursor", Description: "next_cursor from the previous page"}),
+	}
+
+	filters := []struct {
+		supported bool
+		param     Param
+	}{
+		{spec.DateColumn != "", Param{Name: "start_date", Format: "date"}},
+		{spec.DateColumn != "", Param{Name: "end_date", Format: "date"}},
+		{spec.PeopleFilter != "", Param{Name: "people_id", Type: "integer", Format: "int32"}},
+		{spec.UnitFilter != "", Param{Name: "unit_id", Type: "integer", Format: "int32"}},
+		{spec.TypeFilter != "", Param{Name: "type_id", Type: "integer", Format: "int32"}},
+		{spec.StatusFilter != "", Param{Name: "status"}},
+		{spec.AmountColumn != "", Param{Name: "min_amount", Type: "number", Format: "double"}},
+		{spec.AmountColumn != "", Param{Name: "max_amount", Type: "number", Format: "double"}},
+		{spec.NumberColumn != "", Param{Name: "number", Description: "Document number contains"}},
+		{len(spec.SearchColumns) > 0, Param{Name: "search", Description: "Case-insensitive text search"}},
+	}
+	for _, filter := range filters {
+		if filter.supported {
+			params = append(params, queryParameter(filter.param))
+		}
+	}
+	return params
+}
@@ -0,0 +1,372 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+)
+
+// initialisms are kept upper case in generated Go names, e.g. building_id → BuildingID
+var initialisms = map[string]bool{
+	"api": true, "csv": true, "http": true, "id": true, "ids": true, "json": true,
+	"pdf": true, "url": true, "uuid": true, "xlsx": true,
+}
+
+// GenerateClient writes a typed Go client for doc. The generated file only holds the
+// types and one method per operation; the transport lives in the package's client.go.
+func GenerateClient(doc *Document, pkg string) ([]byte, error) {
+	g := &generator{doc: doc, imports: map[string]bool{"context": true}}
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		g.structType(name, doc.Components.Schemas[name])
+	}
+	for _, path := range sortedKeys(doc.Paths) {
+		item := doc.Paths[path]
+		for _, op := range []struct {
+			method string
+			op     *Operation
+		}{{"GET", item.Get}, {"POST", item.Post}, {"PUT", item.Put}, {"PATCH", item.Patch}, {"DELETE", item.Delete}} {
+			if op.op != nil {
+				g.operation(op.method, path, op.op)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by \"go run . openapi generate\"; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", pkg)
+	for _, imp := range sortedKeys(g.imports) {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	fmt.Fprintf(&out, ")\n\n")
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated client: %w", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	doc     *Document
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) structType(name string, schema *Schema) {
+	if schema.Description != "" {
+		g.printf("// %s %s\n", name, schema.Description)
+	}
+	g.printf("type %s %s\n\n", name, g.structBody(schema))
+}
+
+func (g *generator) structBody(schema *Schema) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	used := make(map[string]int)
+	for _, field := range schema.order {
+		name := goName(field)
+		used[name]++
+		if used[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, used[name])
+		}
+		fmt.Fprintf(&b, "%s %s `json:%q`\n", name, g.goType(schema.Properties[field]), field)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (g *generator) goType(schema *Schema) string {
+	if schema.Ref != "" {
+		name := refName(schema.Ref)
+		if schema.pointer {
+			return "*" + name
+		}
+		return name
+	}
+	if len(schema.OneOf) > 0 {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+
+	var base string
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			g.imports["time"] = true
+			base = "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		default:
+			base = "string"
+		}
+	case "integer":
+		base = "int"
+		if schema.Format == "int64" {
+			base = "int64"
+		}
+	case "number":
+		base = "float64"
+		if schema.Format == "float" {
+			base = "float32"
+		}
+	case "boolean":
+		base = "bool"
+	case "array":
+		return "[]" + g.goType(schema.Items)
+	case "object":
+		if schema.AdditionalProperties != nil {
+			return "map[string]" + g.goType(schema.AdditionalProperties)
+		}
+		base = g.structBody(schema)
+	default:
+		return "interface{}"
+	}
+	if schema.Nullable {
+		return "*" + base
+	}
+	return base
+}
+
+// clientOperation is what the generator needs to know about one operation
+type clientOperation struct {
+	method     string
+	path       string
+	op         *Operation
+	pathParams []string
+	query      []*Parameter
+	ifMatch    bool
+	export     bool
+}
+
+func (g *generator) operation(method, path string, op *Operation) {
+	o := clientOperation{method: method, path: path, op: op}
+	for _, p := range op.Parameters {
+		switch {
+		case p.Ref == "#/components/parameters/IfMatch":
+			o.ifMatch = true
+		case p.Ref != "":
+			// User-ID comes from the client and Idempotency-Key from a request option
+		case p.In == "path":
+			o.pathParams = append(o.pathParams, p.Name)
+		case p.In == "query" && p.Name == "format":
+			o.export = true
+		case p.In == "query":
+			o.query = append(o.query, p)
+		}
+	}
+
+	if len(o.query) > 0 {
+		g.paramsType(op.OperationID+"Params", o.query)
+	}
+
+	ok := op.Responses["200"]
+	var jsonSchema *Schema
+	if ok != nil {
+		if media := ok.Content["application/json"]; media != nil {
+			jsonSchema = media.Schema
+		}
+	}
+	if jsonSchema != nil {
+		g.method(o, op.OperationID, g.resultType(jsonSchema), "")
+	} else {
+		g.method(o, op.OperationID, "[]byte", "")
+	}
+	if o.export {
+		g.method(o, op.OperationID+"File", "[]byte", "format")
+	}
+}
+
+// resultType returns structs by pointer and everything else by value
+func (g *generator) resultType(schema *Schema) string {
+	if schema.Ref != "" {
+		return "*" + refName(schema.Ref)
+	}
+	return g.goType(schema)
+}
+
+func (g *generator) method(o clientOperation, name, result, formatArg string) {
+	op := o.op
+	summary := op.Summary
+	if formatArg != "" {
+		summary += ", downloaded as a csv, xlsx or pdf file"
+	}
+	g.printf("// %s calls %s %s", name, o.method, o.path)
+	if summary != "" {
+		g.printf(": %s", strings.ToLower(summary[:1])+summary[1:])
+	}
+	g.printf(".\n")
+	if op.Deprecated {
+		g.printf("//\n// Deprecated: use the route under /api/buildings/{id} instead.\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range o.pathParams {
+		args = append(args, lowerGoName(p)+" int")
+	}
+	if o.ifMatch {
+		args = append(args, "version int")
+	}
+	if formatArg != "" {
+		args = append(args, formatArg+" string")
+	}
+
+	body := "nil"
+	if rb := op.RequestBody; rb != nil {
+		if media := rb.Content["application/json"]; media != nil {
+			args = append(args, "body "+g.goType(media.Schema))
+			body = "body"
+		} else if media := rb.Content["multipart/form-data"]; media != nil {
+			g.imports["io"] = true
+			args = append(args, "fileName string", "file io.Reader")
+			body = fmt.Sprintf("multipartFile{field: %q, name: fileName, r: file}", media.Schema.order[0])
+		}
+	}
+	if len(o.query) > 0 {
+		args = append(args, "params *"+op.OperationID+"Params")
+	}
+	args = append(args, "opts ...RequestOption")
+
+	g.printf("func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), result)
+
+	pathExpr := fmt.Sprintf("%q", o.path)
+	if len(o.pathParams) > 0 {
+		g.imports["fmt"] = true
+		format := o.path
+		var values []string
+		for _, p := range o.pathParams {
+			format = strings.Replace(format, "{"+p+"}", "%d", 1)
+			values = append(values, lowerGoName(p))
+		}
+		pathExpr = fmt.Sprintf("fmt.Sprintf(%q, %s)", format, strings.Join(values, ", "))
+	}
+
+	g.imports["net/url"] = true
+	g.printf("query := url.Values{}\n")
+	if len(o.query) > 0 {
+		g.printf("if params != nil {\nquery = params.values()\n}\n")
+	}
+	if formatArg != "" {
+		g.printf("query.Set(\"format\", %s)\n", formatArg)
+	}
+	if o.ifMatch {
+		g.printf("opts = append(opts, ifMatch(version))\n")
+	}
+
+	if strings.HasPrefix(result, "*") {
+		g.printf("out := new(%s)\n", result[1:])
+		g.printf("if err := c.do(ctx, %q, %s, query, %s, out, opts); err != nil {\nreturn nil, err\n}\n", o.method, pathExpr, body)
+		g.printf("return out, nil\n}\n\n")
+		return
+	}
+	g.printf("var out %s\n", result)
+	g.printf("err := c.do(ctx, %q, %s, query, %s, &out, opts)\n", o.method, pathExpr, body)
+	g.printf("return out, err\n}\n\n")
+}
+
+// paramsType declares the optional query parameters of an operation. Unset fields are
+// left out of the query string.
+func (g *generator) paramsType(name string, params []*Parameter) {
+	g.imports["net/url"] = true
+	g.printf("// %s holds the query parameters of %s.\n", name, strings.TrimSuffix(name, "Params"))
+	g.printf("type %s struct {\n", name)
+	for _, p := range params {
+		if p.Description != "" {
+			g.printf("// %s\n", p.Description)
+		}
+		g.printf("%s %s\n", goName(p.Name), queryGoType(p.Schema))
+	}
+	g.printf("}\n\n")
+
+	g.printf("func (p *%s) values() url.Values {\nquery := url.Values{}\n", name)
+	for _, p := range params {
+		field := "p." + goName(p.Name)
+		switch t := queryGoType(p.Schema); {
+		case strings.HasPrefix(t, "[]"):
+			g.imports["fmt"] = true
+			g.printf("for _, v := range %s {\nquery.Add(%q, fmt.Sprint(v))\n}\n", field, p.Name)
+		case t == "string":
+			g.printf("if %s != \"\" {\nquery.Set(%q, %s)\n}\n", field, p.Name, field)
+		default:
+			g.imports["fmt"] = true
+			g.printf("if %s != nil {\nquery.Set(%q, fmt.Sprint(*%s))\n}\n", field, p.Name, field)
+		}
+	}
+	g.printf("return query\n}\n\n")
+}
+
+func queryGoType(schema *Schema) string {
+	switch schema.Type {
+	case "integer":
+		return "*int"
+	case "number":
+		return "*float64"
+	case "boolean":
+		return "*bool"
+	case "array":
+		return "[]" + strings.TrimPrefix(queryGoType(schema.Items), "*")
+	}
+	return "string"
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// goName turns a JSON or parameter name into an exported Go identifier
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		// Split camelCase path parameters such as invoiceId
+		for _, word := range splitCamel(part) {
+			lower := strings.ToLower(word)
+			if initialisms[lower] {
+				b.WriteString(strings.ToUpper(lower))
+				continue
+			}
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			b.WriteString(string(runes))
+		}
+	}
+	result := b.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		result = "N" + result
+	}
+	return result
+}
+
+// lowerGoName is goName for unexported identifiers such as method arguments
+func lowerGoName(name string) string {
+	exported := goName(name)
+	if initialisms[strings.ToLower(exported)] {
+		return strings.ToLower(exported)
+	}
+	runes := []rune(exported)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func splitCamel(s string) []string {
+	var words []string
+	start := 0
+	runes := []rune(s)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Generated files, relative to the repository root
+const (
+	SpecFile   = "api/openapi.json"
+	ClientFile = "client/client_gen.go"
+)
+
+// Usage describes the openapi admin command
+const Usage = `usage: openapi <command>
+
+commands:
+  generate      write ` + SpecFile + ` and ` + ClientFile + ` from the registered routes
+  check         fail if a route is undocumented or the generated files are out of date`
+
+// RunCommand runs the openapi admin command against the engine's routes. The routes tests
+// run the same check, so a handler or DTO change cannot land without regenerating the spec
+// and client.
+func RunCommand(engine *gin.Engine, handlers []Handlers, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", Usage)
+	}
+
+	doc, undocumented := Build(engine.Routes(), handlers...)
+	if len(undocumented) > 0 {
+		return fmt.Errorf("routes without an OpenAPI description (add them to the package's OpenAPI handlers):\n  %s",
+			strings.Join(undocumented, "\n  "))
+	}
+
+	files, err := generatedFiles(doc)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "generate":
+		for _, file := range []string{SpecFile, ClientFile} {
+			if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(file, files[file], 0o644); err != nil {
+				return err
+			}
+			fmt.Printf("wrote %s\n", file)
+		}
+	case "check":
+		stale, err := Stale(doc, ".")
+		if err != nil {
+			return err
+		}
+		if len(stale) > 0 {
+			return fmt.Errorf("%s out of date, run: go run . openapi generate", strings.Join(stale, " and "))
+		}
+		fmt.Println("OpenAPI spec and client are up to date")
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], Usage)
+	}
+	return nil
+}
+
+// Stale returns the generated files under root that differ from what doc generates
+func Stale(doc *Document, root string) ([]string, error) {
+	files, err := generatedFiles(doc)
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, file := range []string{SpecFile, ClientFile} {
+		current, err := os.ReadFile(filepath.Join(root, file))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if !bytes.Equal(current, files[file]) {
+			stale = append(stale, file)
+		}
+	}
+	return stale, nil
+}
+
+func generatedFiles(doc *Document) (map[string][]byte, error) {
+	spec, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	client, err := GenerateClient(doc, filepath.Base(filepath.Dir(ClientFile)))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		SpecFile:   append(spec, '\n'),
+		ClientFile: client,
+	}, nil
+}
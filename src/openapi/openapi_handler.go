@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+// OpenAPIHandler serves the document for the engine's routes. The document is built
+// on first request, after every route has been registered.
+type OpenAPIHandler struct {
+	engine   *gin.Engine
+	handlers []Handlers
+	once     sync.Once
+	doc      *Document
+}
+
+func NewOpenAPIHandler(engine *gin.Engine, handlers ...Handlers) *OpenAPIHandler {
+	return &OpenAPIHandler{engine: engine, handlers: handlers}
+}
+
+func (h *OpenAPIHandler) Document() *Document {
+	h.once.Do(func() {
+		h.doc, _ = Build(h.engine.Routes(), h.handlers...)
+	})
+	return h.doc
+}
+
+// GetSpec handles GET /openapi.json
+func (h *OpenAPIHandler) GetSpec(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Document())
+}
+
+// GetDocs handles GET /docs
+func (h *OpenAPIHandler) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
@@ -0,0 +1,216 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	packagePath    = regexp.MustCompile(`[\w./-]*\.`)
+)
+
+// schemas turns Go types into component schemas. Types are collected first so a name is
+// only qualified by its package when two packages declare a type with the same name.
+type schemas struct {
+	types      map[reflect.Type]bool
+	names      map[reflect.Type]string
+	components map[string]*Schema
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		types:      make(map[reflect.Type]bool),
+		names:      make(map[reflect.Type]string),
+		components: make(map[string]*Schema),
+	}
+}
+
+// collect records every named struct reachable from t
+func (s *schemas) collect(t reflect.Type) {
+	t = deref(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		s.collect(t.Elem())
+	case reflect.Struct:
+		if t == timeType {
+			return
+		}
+		if t.Name() != "" {
+			if s.types[t] {
+				return
+			}
+			s.types[t] = true
+		}
+		for _, field := range fields(t) {
+			s.collect(field.Type)
+		}
+	}
+}
+
+// assignNames gives each collected type its component name
+func (s *schemas) assignNames() {
+	byName := make(map[string][]reflect.Type)
+	for t := range s.types {
+		name := shortName(t)
+		byName[name] = append(byName[name], t)
+	}
+	for name, types := range byName {
+		for _, t := range types {
+			if len(types) > 1 {
+				s.names[t] = exportedName(packageName(t)) + name
+			} else {
+				s.names[t] = name
+			}
+		}
+	}
+}
+
+// schema returns the schema for t, adding named structs to the components
+func (s *schemas) schema(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		nullable = true
+		t = t.Elem()
+	}
+
+	var schema *Schema
+	switch {
+	case t == timeType:
+		schema = &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		schema = &Schema{}
+	default:
+		schema = s.kindSchema(t)
+	}
+	// OpenAPI 3.0 ignores siblings of $ref, so a pointer to a struct is only recorded for the client
+	if nullable && schema.Ref == "" {
+		schema.Nullable = true
+	} else if nullable {
+		schema.pointer = true
+	}
+	return schema
+}
+
+func (s *schemas) kindSchema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.objectSchema(t)
+		}
+		name := s.names[t]
+		if _, ok := s.components[name]; !ok {
+			// Reserve the name first so recursive types terminate
+			s.components[name] = &Schema{}
+			*s.components[name] = *s.objectSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	// interface{} and anything else accepts any JSON value
+	return &Schema{}
+}
+
+func (s *schemas) objectSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range fields(t) {
+		schema.Properties[field.Name] = s.schema(field.Type)
+		schema.order = append(schema.order, field.Name)
+	}
+	return schema
+}
+
+type jsonField struct {
+	Name string
+	Type reflect.Type
+}
+
+// fields lists the JSON fields of a struct the way encoding/json does, flattening
+// embedded structs and skipping unexported and "-" fields
+func fields(t reflect.Type) []jsonField {
+	var result []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && deref(field.Type).Kind() == reflect.Struct {
+			result = append(result, fields(deref(field.Type))...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		result = append(result, jsonField{Name: name, Type: field.Type})
+	}
+	return result
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// shortName is the type name with generic arguments folded in, e.g. PageInvoiceListItem
+func shortName(t reflect.Type) string {
+	name := packagePath.ReplaceAllString(t.Name(), "")
+	return strings.NewReplacer("[", "", "]", "", ",", "", "*", "").Replace(name)
+}
+
+func packageName(t reflect.Type) string {
+	path := t.PkgPath()
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// exportedName turns snake_case or kebab-case into CamelCase
+func exportedName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' ' || r == '.'
+	})
+	for i, part := range parts {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		parts[i] = string(runes)
+	}
+	return strings.Join(parts, "")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+// Package outbox records domain events in the same database transaction as the change
+// they describe, so an event exists exactly when its change was committed. The webhooks
+// dispatcher reads the outbox and delivers the events.
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Event types, "<aggregate>.<action>"
+const (
+	InvoiceCreated      = "invoice.created"
+	InvoiceUpdated      = "invoice.updated"
+	PaymentCreated      = "payment.created"
+	PaymentUpdated      = "payment.updated"
+	PaymentVoided       = "payment.voided"
+	SalesReceiptCreated = "sales_receipt.created"
+	SalesReceiptUpdated = "sales_receipt.updated"
+	CheckCreated        = "check.created"
+	CheckUpdated        = "check.updated"
+	LeaseCreated        = "lease.created"
+	LeaseUpdated        = "lease.updated"
+	LeaseVoided         = "lease.voided"
+)
+
+// EventTypes lists every event type that is published, in the order they are documented
+var EventTypes = []string{
+	InvoiceCreated, InvoiceUpdated,
+	PaymentCreated, PaymentUpdated, PaymentVoided,
+	SalesReceiptCreated, SalesReceiptUpdated,
+	CheckCreated, CheckUpdated,
+	LeaseCreated, LeaseUpdated, LeaseVoided,
+}
+
+type Event struct {
+	ID            int             `json:"id"`
+	BuildingID    int             `json:"building_id"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     string          `json:"created_at"`
+	DispatchedAt  *string         `json:"dispatched_at"`
+}
+
+// Document is the payload of invoice, payment, sales receipt and check events. It names
+// the document; subscribers fetch its lines from the API when they need them.
+type Document struct {
+	ID            int     `json:"id"`
+	TransactionID int     `json:"transaction_id"`
+	Number        string  `json:"number"`
+	Date          string  `json:"date"`
+	Amount        float64 `json:"amount"`
+	PeopleID      *int    `json:"people_id"`
+	UnitID        *int    `json:"unit_id"`
+	InvoiceID     *int    `json:"invoice_id,omitempty"` // Payments only
+	Version       int     `json:"version"`
+}
+
+// Lease is the payload of lease events
+type Lease struct {
+	ID         int     `json:"id"`
+	PeopleID   int     `json:"people_id"`
+	UnitID     int     `json:"unit_id"`
+	StartDate  string  `json:"start_date"`
+	EndDate    *string `json:"end_date"`
+	RentAmount float64 `json:"rent_amount"`
+	Status     string  `json:"status"`
+	Version    int     `json:"version"`
+}
+
+// Executor is satisfied by *sql.Tx; services pass the transaction of the change
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Write records an event in the caller's transaction. Call it before tx.Commit() so the
+// event is rolled back with the change.
+func Write(tx Executor, buildingID int, eventType string, aggregateID int, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	aggregateType, _, _ := strings.Cut(eventType, ".")
+
+	_, err = tx.Exec(
+		"INSERT INTO outbox_events (building_id, event_type, aggregate_type, aggregate_id, payload, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		buildingID, eventType, aggregateType, aggregateID, string(data), time.Now().UTC().Format(timeLayout),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// IsEventType reports whether name is a published event type
+func IsEventType(name string) bool {
+	for _, eventType := range EventTypes {
+		if eventType == name {
+			return true
+		}
+	}
+	return false
+}
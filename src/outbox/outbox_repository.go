@@ -0,0 +1,109 @@
+package outbox
+
+import (
+	"database/sql"
+
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+type OutboxRepository interface {
+	GetByID(id int) (Event, error)
+	Undispatched(limit int) ([]Event, error)
+	List(buildingID int, params pagination.Params) ([]Event, int, error)
+}
+
+type outboxRepo struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxRepo{db: db}
+}
+
+const eventColumns = "id, building_id, event_type, aggregate_type, aggregate_id, payload, created_at, dispatched_at"
+
+// EventListSpec describes the query string of the event list
+var EventListSpec = pagination.Spec{
+	IDColumn: "id",
+	Sorts: map[string]string{
+		"id":   "id",
+		"date": "created_at",
+	},
+	DefaultSort:   "-id",
+	DateColumn:    "created_at",
+	SearchColumns: []string{"event_type"},
+}
+
+// EventSortKey returns the cursor values of an event for the list's sort
+func EventSortKey(event Event, sort string) (interface{}, int) {
+	if sort == "date" {
+		return event.CreatedAt, event.ID
+	}
+	return event.ID, event.ID
+}
+
+func (r *outboxRepo) GetByID(id int) (Event, error) {
+	return scanEvent(r.db.QueryRow("SELECT "+eventColumns+" FROM outbox_events WHERE id = ?", id))
+}
+
+// Undispatched returns the oldest events that have not been handed to the dispatcher
+func (r *outboxRepo) Undispatched(limit int) ([]Event, error) {
+	rows, err := r.db.Query("SELECT "+eventColumns+" FROM outbox_events WHERE dispatched_at IS NULL ORDER BY id LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (r *outboxRepo) List(buildingID int, params pagination.Params) ([]Event, int, error) {
+	where, args := EventListSpec.Where(params)
+	where = " WHERE building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM outbox_events"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := EventListSpec.After(params)
+	order, orderArgs := EventListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	rows, err := r.db.Query("SELECT "+eventColumns+" FROM outbox_events"+where+after+order, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, event)
+	}
+	return events, total, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEvent(row scanner) (Event, error) {
+	var event Event
+	var payload string
+	err := row.Scan(&event.ID, &event.BuildingID, &event.EventType, &event.AggregateType, &event.AggregateID, &payload, &event.CreatedAt, &event.DispatchedAt)
+	event.Payload = []byte(payload)
+	return event, err
+}
@@ -0,0 +1,338 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Spec describes how a list endpoint can be sorted and filtered.
+// Columns are SQL expressions; the *Filter fields are conditions with a single placeholder.
+// Leaving a field empty means the list does not support that filter.
+type Spec struct {
+	IDColumn      string
+	Sorts         map[string]string
+	DefaultSort   string
+	DateColumn    string
+	AmountColumn  string
+	NumberColumn  string
+	SearchColumns []string
+	StatusFilter  string
+	PeopleFilter  string
+	UnitFilter    string
+	TypeFilter    string
+}
+
+type Filters struct {
+	StartDate string
+	EndDate   string
+	PeopleID  *int
+	UnitID    *int
+	TypeID    *int
+	Status    string
+	MinAmount *float64
+	MaxAmount *float64
+	Number    string
+	Search    string
+}
+
+type Params struct {
+	Limit   int
+	Sort    string
+	Desc    bool
+	After   *Cursor
+	Filters Filters
+}
+
+// Cursor marks the last row of a page: its value for the sort column and its id
+type Cursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v"`
+	ID    int         `json:"id"`
+}
+
+type Meta struct {
+	Limit      int     `json:"limit"`
+	Count      int     `json:"count"`
+	Total      int     `json:"total"`
+	Sort       string  `json:"sort"`
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+type Page[T any] struct {
+	Data       []T  `json:"data"`
+	Pagination Meta `json:"pagination"`
+}
+
+// SortParam returns the sort in its query string form, e.g. "-date"
+func (p Params) SortParam() string {
+	if p.Desc {
+		return "-" + p.Sort
+	}
+	return p.Sort
+}
+
+// Parse reads limit, cursor, sort and the filters from a query string.
+// Errors are keyed by query parameter.
+func (s Spec) Parse(query url.Values) (Params, map[string]string) {
+	errors := make(map[string]string)
+	params := Params{Limit: DefaultLimit}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			errors["limit"] = fmt.Sprintf("Limit must be between 1 and %d", MaxLimit)
+		} else {
+			params.Limit = limit
+		}
+	}
+
+	sortParam := query.Get("sort")
+	if sortParam == "" {
+		sortParam = s.DefaultSort
+	}
+	params.Desc = strings.HasPrefix(sortParam, "-")
+	params.Sort = strings.TrimPrefix(sortParam, "-")
+	if _, ok := s.Sorts[params.Sort]; !ok {
+		errors["sort"] = "Sort must be one of: " + strings.Join(s.sortNames(), ", ")
+	}
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursor, err := decodeCursor(cursorStr)
+		if err != nil {
+			errors["cursor"] = "Invalid cursor"
+		} else if cursor.Sort != params.SortParam() {
+			errors["cursor"] = "Cursor does not match the requested sort"
+		} else {
+			params.After = &cursor
+		}
+	}
+
+	f := &params.Filters
+	f.StartDate = parseDate(query, "start_date", s.DateColumn != "", errors)
+	f.EndDate = parseDate(query, "end_date", s.DateColumn != "", errors)
+	f.PeopleID = parseID(query, "people_id", s.PeopleFilter != "", errors)
+	f.UnitID = parseID(query, "unit_id", s.UnitFilter != "", errors)
+	f.TypeID = parseID(query, "type_id", s.TypeFilter != "", errors)
+	f.MinAmount = parseAmount(query, "min_amount", s.AmountColumn != "", errors)
+	f.MaxAmount = parseAmount(query, "max_amount", s.AmountColumn != "", errors)
+	f.Status = parseText(query, "status", s.StatusFilter != "", errors)
+	f.Number = parseText(query, "number", s.NumberColumn != "", errors)
+	f.Search = parseText(query, "search", len(s.SearchColumns) > 0, errors)
+
+	if f.StartDate != "" && f.EndDate != "" && f.StartDate > f.EndDate {
+		errors["end_date"] = "End date must be on or after start date"
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+		errors["max_amount"] = "Max amount must be greater than or equal to min amount"
+	}
+
+	if len(errors) > 0 {
+		return params, errors
+	}
+	return params, nil
+}
+
+func parseText(query url.Values, key string, supported bool, errors map[string]string) string {
+	value := strings.TrimSpace(query.Get(key))
+	if value != "" && !supported {
+		errors[key] = "This list cannot be filtered by " + key
+		return ""
+	}
+	return value
+}
+
+func parseDate(query url.Values, key string, supported bool, errors map[string]string) string {
+	value := parseText(query, key, supported, errors)
+	if value == "" {
+		return ""
+	}
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		errors[key] = "Date must be in YYYY-MM-DD format"
+		return ""
+	}
+	return value
+}
+
+func parseID(query url.Values, key string, supported bool, errors map[string]string) *int {
+	value := parseText(query, key, supported, errors)
+	if value == "" {
+		return nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		errors[key] = "Must be a positive integer"
+		return nil
+	}
+	return &id
+}
+
+func parseAmount(query url.Values, key string, supported bool, errors map[string]string) *float64 {
+	value := parseText(query, key, supported, errors)
+	if value == "" {
+		return nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		errors[key] = "Must be a number"
+		return nil
+	}
+	return &amount
+}
+
+func (s Spec) sortNames() []string {
+	names := make([]string, 0, len(s.Sorts))
+	for name := range s.Sorts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Where returns the filter conditions, each prefixed with AND, and their arguments
+func (s Spec) Where(p Params) (string, []interface{}) {
+	var clause strings.Builder
+	args := []interface{}{}
+	add := func(condition string, values ...interface{}) {
+		clause.WriteString(" AND " + condition)
+		args = append(args, values...)
+	}
+
+	f := p.Filters
+	if f.StartDate != "" {
+		add(s.DateColumn+" >= ?", f.StartDate)
+	}
+	if f.EndDate != "" {
+		add(s.DateColumn+" <= ?", f.EndDate)
+	}
+	if f.PeopleID != nil {
+		add(s.PeopleFilter, *f.PeopleID)
+	}
+	if f.UnitID != nil {
+		add(s.UnitFilter, *f.UnitID)
+	}
+	if f.TypeID != nil {
+		add(s.TypeFilter, *f.TypeID)
+	}
+	if f.Status != "" {
+		add(s.StatusFilter, f.Status)
+	}
+	if f.MinAmount != nil {
+		add(s.AmountColumn+" >= ?", *f.MinAmount)
+	}
+	if f.MaxAmount != nil {
+		add(s.AmountColumn+" <= ?", *f.MaxAmount)
+	}
+	if f.Number != "" {
+		add("LOWER("+s.NumberColumn+") LIKE ? ESCAPE '!'", likePattern(f.Number))
+	}
+	if f.Search != "" {
+		conditions := make([]string, len(s.SearchColumns))
+		values := make([]interface{}, len(s.SearchColumns))
+		for i, column := range s.SearchColumns {
+			conditions[i] = "LOWER(" + column + ") LIKE ? ESCAPE '!'"
+			values[i] = likePattern(f.Search)
+		}
+		add("("+strings.Join(conditions, " OR ")+")", values...)
+	}
+
+	return clause.String(), args
+}
+
+// After returns the keyset condition, prefixed with AND, that skips rows up to and including the cursor
+func (s Spec) After(p Params) (string, []interface{}) {
+	if p.After == nil {
+		return "", nil
+	}
+
+	op := ">"
+	if p.Desc {
+		op = "<"
+	}
+	column := s.Sorts[p.Sort]
+	if column == s.IDColumn {
+		return fmt.Sprintf(" AND %s %s ?", s.IDColumn, op), []interface{}{p.After.ID}
+	}
+	condition := fmt.Sprintf(" AND (%s %s ? OR (%s = ? AND %s %s ?))", column, op, column, s.IDColumn, op)
+	return condition, []interface{}{p.After.Value, p.After.Value, p.After.ID}
+}
+
+// OrderAndLimit returns the ORDER BY and LIMIT clauses. One row more than the limit is
+// fetched so NewPage can tell whether another page follows.
+func (s Spec) OrderAndLimit(p Params) (string, []interface{}) {
+	direction := "ASC"
+	if p.Desc {
+		direction = "DESC"
+	}
+	column := s.Sorts[p.Sort]
+	order := fmt.Sprintf(" ORDER BY %s %s", column, direction)
+	if column != s.IDColumn {
+		order += fmt.Sprintf(", %s %s", s.IDColumn, direction)
+	}
+	return order + " LIMIT ?", []interface{}{p.Limit + 1}
+}
+
+// NewPage trims rows fetched with OrderAndLimit to the page size and builds the cursor for
+// the next page. key returns a row's value for the sort field and its id.
+func NewPage[T any](rows []T, total int, p Params, key func(row T, sort string) (interface{}, int)) Page[T] {
+	hasMore := len(rows) > p.Limit
+	if hasMore {
+		rows = rows[:p.Limit]
+	}
+	if rows == nil {
+		rows = []T{}
+	}
+
+	meta := Meta{
+		Limit:   p.Limit,
+		Count:   len(rows),
+		Total:   total,
+		Sort:    p.SortParam(),
+		HasMore: hasMore,
+	}
+	if hasMore {
+		value, id := key(rows[len(rows)-1], p.Sort)
+		cursor := encodeCursor(Cursor{Sort: p.SortParam(), Value: value, ID: id})
+		meta.NextCursor = &cursor
+	}
+
+	return Page[T]{Data: rows, Pagination: meta}
+}
+
+func encodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (Cursor, error) {
+	var cursor Cursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, err
+	}
+	if cursor.ID <= 0 {
+		return cursor, fmt.Errorf("cursor has no id")
+	}
+	return cursor, nil
+}
+
+// likePattern builds a case-insensitive substring pattern, escaping wildcards with '!'
+// because backslash escaping differs between databases
+func likePattern(value string) string {
+	escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(strings.ToLower(value))
+	return "%" + escaped + "%"
+}
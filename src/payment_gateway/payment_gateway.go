@@ -0,0 +1,112 @@
+// Package payment_gateway takes invoice payments online through a payment gateway, such as
+// mobile money or a card processor.
+//
+// A payment intent hands (part of) an invoice's balance to the gateway, which returns a
+// checkout for the payer. The gateway reports the outcome to a webhook; events are verified
+// by their signature and recorded once per event id, so an event the gateway delivers again
+// changes nothing. A successful payment is recorded as an invoice payment into the building's
+// clearing account, by the user who set up the gateway for the building. Partial payments
+// each become a payment of their own and a reversal becomes a negative payment, the way
+// refunds are recorded by hand.
+//
+// The fake provider stands in for a real gateway locally: nobody pays its checkouts, and its
+// events are posted with the simulate endpoint, signed like a real webhook.
+package payment_gateway
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Settings statuses
+const (
+	StatusActive   = "active"
+	StatusDisabled = "disabled" // No new intents; events of existing intents are still recorded
+)
+
+// Intent statuses
+const (
+	IntentPending       = "pending"
+	IntentPartiallyPaid = "partially_paid"
+	IntentPaid          = "paid"
+	IntentFailed        = "failed"
+	IntentCancelled     = "cancelled"
+	IntentReversed      = "reversed" // Everything paid was reversed
+)
+
+// Intent sources
+const (
+	SourceStaff  = "staff"
+	SourcePortal = "portal" // Started by the tenant in the tenant portal
+)
+
+// Event types, the same for every provider
+const (
+	EventSucceeded = "succeeded"
+	EventFailed    = "failed"
+	EventReversed  = "reversed"
+)
+
+// Event statuses
+const (
+	EventProcessing = "processing" // Being recorded by a webhook request
+	EventProcessed  = "processed"
+	EventIgnored    = "ignored" // Verified but changed nothing; the note says why
+)
+
+// Settings connect a building to the gateway
+type Settings struct {
+	ID                int    `json:"id"`
+	BuildingID        int    `json:"building_id"`
+	ClearingAccountID int    `json:"clearing_account_id"` // Asset account gateway payments are received into
+	UserID            int    `json:"user_id"`             // Gateway payments are recorded by this user
+	Status            string `json:"status"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+// Intent is a payment of an invoice handed to the gateway
+type Intent struct {
+	ID                int     `json:"id"`
+	BuildingID        int     `json:"building_id"`
+	InvoiceID         int     `json:"invoice_id"`
+	PeopleID          int     `json:"people_id"`
+	Provider          string  `json:"provider"`
+	ProviderReference string  `json:"provider_reference"`
+	CheckoutURL       string  `json:"checkout_url"` // Where the payer completes the payment
+	Amount            float64 `json:"amount"`
+	PaidAmount        float64 `json:"paid_amount"` // Active payments recorded from the intent's events, less reversals
+	Status            string  `json:"status"`
+	Source            string  `json:"source"`
+	UserID            *int    `json:"user_id"` // Null for intents started in the tenant portal
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+	Events            []Event `json:"events,omitempty"`
+}
+
+// Event is a webhook event received from the gateway
+type Event struct {
+	ID        int     `json:"id"`
+	Provider  string  `json:"provider"`
+	EventID   string  `json:"event_id"` // The provider's id of the event
+	IntentID  *int    `json:"intent_id"`
+	Type      string  `json:"type"`
+	Amount    float64 `json:"amount"`
+	Status    string  `json:"status"`
+	Note      *string `json:"note"`
+	PaymentID *int    `json:"payment_id"` // The invoice payment recorded for the event
+	Payload   string  `json:"-"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+// intentStatus returns the status of an intent after its paid amount changed
+func intentStatus(intent Intent, paid float64, reversed bool) string {
+	switch {
+	case paid >= intent.Amount-0.005:
+		return IntentPaid
+	case paid > 0.005:
+		return IntentPartiallyPaid
+	case reversed:
+		return IntentReversed
+	default:
+		return intent.Status
+	}
+}
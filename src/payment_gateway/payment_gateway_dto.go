@@ -0,0 +1,51 @@
+package payment_gateway
+
+type SettingsRequest struct {
+	ClearingAccountID int    `json:"clearing_account_id" binding:"required"` // Asset account of the building
+	Status            string `json:"status"`                                 // active (default) or disabled
+}
+
+type CreateIntentRequest struct {
+	InvoiceID int      `json:"invoice_id" binding:"required"`
+	Amount    *float64 `json:"amount"` // Defaults to the invoice's balance; less pays part of it
+}
+
+// SimulateRequest makes the fake provider report an event of an intent
+type SimulateRequest struct {
+	Type   string   `json:"type" binding:"required"` // succeeded, failed or reversed
+	Amount *float64 `json:"amount"`                  // Defaults to what is left to pay, or for reversals to what was paid
+}
+
+// WebhookResponse tells the gateway the event was accepted
+type WebhookResponse struct {
+	Event     Event `json:"event"`
+	Duplicate bool  `json:"duplicate"` // The event was recorded before and nothing changed
+}
+
+func (r SettingsRequest) Validate() map[string]string {
+	if r.Status != "" && r.Status != StatusActive && r.Status != StatusDisabled {
+		return map[string]string{"status": "Status must be active or disabled"}
+	}
+	return nil
+}
+
+func (r CreateIntentRequest) Validate() map[string]string {
+	if r.Amount != nil && *r.Amount <= 0 {
+		return map[string]string{"amount": "Amount must be greater than zero"}
+	}
+	return nil
+}
+
+func (r SimulateRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if r.Type != EventSucceeded && r.Type != EventFailed && r.Type != EventReversed {
+		errors["type"] = "Type must be succeeded, failed or reversed"
+	}
+	if r.Amount != nil && *r.Amount <= 0 {
+		errors["amount"] = "Amount must be greater than zero"
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package payment_gateway
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+)
+
+// maxWebhookBody limits the size of a webhook request
+const maxWebhookBody = 1 << 20
+
+type PaymentGatewayHandler struct {
+	service *PaymentGatewayService
+}
+
+func NewPaymentGatewayHandler(service *PaymentGatewayService) *PaymentGatewayHandler {
+	return &PaymentGatewayHandler{service: service}
+}
+
+// GET /buildings/:id/payment-gateway
+func (h *PaymentGatewayHandler) GetSettings(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	settings, err := h.service.GetSettings(buildingID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// PUT /buildings/:id/payment-gateway
+func (h *PaymentGatewayHandler) SaveSettings(c *gin.Context) {
+	var req SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	settings, validationErr, err := h.service.WithLogger(logging.FromGin(c)).SaveSettings(buildingID, req, userID)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// GET /buildings/:id/payment-intents
+func (h *PaymentGatewayHandler) GetIntents(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	var invoiceID *int
+	if invoiceIDStr := c.Query("invoice_id"); invoiceIDStr != "" {
+		id, err := strconv.Atoi(invoiceIDStr)
+		if err != nil {
+			apperrors.Respond(c, apperrors.BadRequest("Invalid Invoice ID"))
+			return
+		}
+		invoiceID = &id
+	}
+
+	intents, err := h.service.ListIntents(buildingID, invoiceID, c.Query("status"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, intents)
+}
+
+// POST /buildings/:id/payment-intents
+func (h *PaymentGatewayHandler) CreateIntent(c *gin.Context) {
+	var req CreateIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	intent, validationErr, err := h.service.WithLogger(logging.FromGin(c)).CreateIntent(buildingID, req, SourceStaff, &userID)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, intent)
+}
+
+// GET /buildings/:id/payment-intents/:intentId
+func (h *PaymentGatewayHandler) GetIntent(c *gin.Context) {
+	buildingID, id, ok := intentParams(c)
+	if !ok {
+		return
+	}
+
+	intent, err := h.service.GetIntent(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
+}
+
+// POST /buildings/:id/payment-intents/:intentId/cancel
+func (h *PaymentGatewayHandler) CancelIntent(c *gin.Context) {
+	buildingID, id, ok := intentParams(c)
+	if !ok {
+		return
+	}
+
+	intent, err := h.service.WithLogger(logging.FromGin(c)).CancelIntent(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
+}
+
+// POST /buildings/:id/payment-intents/:intentId/simulate
+func (h *PaymentGatewayHandler) Simulate(c *gin.Context) {
+	var req SimulateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, id, ok := intentParams(c)
+	if !ok {
+		return
+	}
+
+	response, validationErr, err := h.service.WithLogger(logging.FromGin(c)).Simulate(buildingID, id, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /payment-gateways/:provider/webhook
+func (h *PaymentGatewayHandler) ReceiveWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Failed to read the webhook body"))
+		return
+	}
+
+	response, err := h.service.WithLogger(logging.FromGin(c)).HandleWebhook(c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func intentParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("intentId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Payment Intent ID"))
+		return 0, 0, false
+	}
+	return buildingID, id, true
+}
+
+// userParam reads the User-ID header, or the user_id query parameter, responding when it is
+// missing or invalid
+func userParam(c *gin.Context) (int, bool) {
+	userIDStr := c.GetHeader("User-ID")
+	if userIDStr == "" {
+		userIDStr = c.Query("user_id")
+	}
+	if userIDStr == "" {
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
+		return 0, false
+	}
+
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
+		return 0, false
+	}
+	return userID, true
+}
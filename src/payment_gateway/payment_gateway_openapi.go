@@ -0,0 +1,44 @@
+package payment_gateway
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"PaymentGatewayHandler.GetSettings": {Summary: "Get the building's payment gateway settings", Response: Settings{}},
+	"PaymentGatewayHandler.SaveSettings": {
+		Summary:     "Set up online payment for the building",
+		Description: "Gateway payments are recorded into the clearing account, an asset account of the building, by the user making this change.",
+		UserID:      true,
+		Request:     SettingsRequest{},
+		Response:    Settings{},
+	},
+	"PaymentGatewayHandler.GetIntents": {
+		Summary: "List payment intents",
+		Query: []openapi.Param{
+			{Name: "invoice_id", Type: "integer", Format: "int32"},
+			{Name: "status", Enum: []string{IntentPending, IntentPartiallyPaid, IntentPaid, IntentFailed, IntentCancelled, IntentReversed}},
+		},
+		Response: []Intent{},
+	},
+	"PaymentGatewayHandler.CreateIntent": {
+		Summary:     "Start an online payment of an invoice",
+		Description: "Creates a pending payment with the gateway and returns the checkout URL to send the payer to. The amount defaults to the invoice's balance.",
+		UserID:      true,
+		Request:     CreateIntentRequest{},
+		Response:    Intent{},
+	},
+	"PaymentGatewayHandler.GetIntent":    {Summary: "Get a payment intent with its gateway events", Response: Intent{}},
+	"PaymentGatewayHandler.CancelIntent": {Summary: "Cancel a pending payment intent", Response: Intent{}},
+	"PaymentGatewayHandler.Simulate": {
+		Summary:     "Simulate a gateway event of a payment intent",
+		Description: "Fake provider only. Posts a signed succeeded, failed or reversed event through the webhook, as the gateway would.",
+		Request:     SimulateRequest{},
+		Response:    WebhookResponse{},
+	},
+	"PaymentGatewayHandler.ReceiveWebhook": {
+		Summary: "Receive a payment gateway event",
+		Description: "Called by the gateway; the body and signature header are the provider's own. Events are recorded once per event id: " +
+			"a succeeded event records an invoice payment into the clearing account and a reversed event a negative one. " +
+			"Requests with an invalid signature are rejected with 401.",
+		Response: WebhookResponse{},
+	},
+}
@@ -0,0 +1,278 @@
+package payment_gateway
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/dialect"
+)
+
+// processingTimeout is how long an event stays claimed by a webhook request that never
+// finished, e.g. because the process stopped, before the gateway's next delivery may take it
+const processingTimeout = 5 * time.Minute
+
+type PaymentGatewayRepository interface {
+	GetSettings(buildingID int) (Settings, error)
+	SaveSettings(settings Settings, now time.Time) (Settings, error)
+	CreateIntent(intent Intent) (Intent, error)
+	GetIntent(buildingID int, id int) (Intent, error)
+	GetIntentByReference(provider string, reference string) (Intent, error)
+	ListIntents(buildingID int, invoiceID *int, status string) ([]Intent, error)
+	ListPersonIntents(peopleID int) ([]Intent, error)
+	SetIntentStatus(id int, status string, fromStatus string, now time.Time) (bool, error)
+	IntentTotals(id int) (float64, bool, error)
+	UpdateIntentPaid(id int, paid float64, status string, now time.Time) error
+	ClaimEvent(event Event, now time.Time) (bool, error)
+	GetEvent(provider string, eventID string) (Event, error)
+	FinishEvent(event Event, now time.Time) (Event, error)
+	ReleaseEvent(id int) error
+	ListEvents(intentID int) ([]Event, error)
+	InvoiceBalance(invoiceID int) (float64, error)
+	FindPayment(invoiceID int, reference string) (int, error)
+}
+
+type paymentGatewayRepo struct {
+	db *sql.DB
+}
+
+func NewPaymentGatewayRepository(db *sql.DB) PaymentGatewayRepository {
+	return &paymentGatewayRepo{db: db}
+}
+
+const settingsColumns = "id, building_id, clearing_account_id, user_id, status, created_at, updated_at"
+
+const intentColumns = "id, building_id, invoice_id, people_id, provider, provider_reference, checkout_url, amount, paid_amount, status, source, user_id, created_at, updated_at"
+
+const eventColumns = "id, provider, event_id, intent_id, type, amount, status, note, payment_id, payload, created_at, updated_at"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSettings(row scanner) (Settings, error) {
+	var s Settings
+	err := row.Scan(&s.ID, &s.BuildingID, &s.ClearingAccountID, &s.UserID, &s.Status, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func scanIntent(row scanner) (Intent, error) {
+	var i Intent
+	err := row.Scan(&i.ID, &i.BuildingID, &i.InvoiceID, &i.PeopleID, &i.Provider, &i.ProviderReference, &i.CheckoutURL,
+		&i.Amount, &i.PaidAmount, &i.Status, &i.Source, &i.UserID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+func scanEvent(row scanner) (Event, error) {
+	var e Event
+	err := row.Scan(&e.ID, &e.Provider, &e.EventID, &e.IntentID, &e.Type, &e.Amount, &e.Status, &e.Note, &e.PaymentID,
+		&e.Payload, &e.CreatedAt, &e.UpdatedAt)
+	return e, err
+}
+
+func (r *paymentGatewayRepo) GetSettings(buildingID int) (Settings, error) {
+	return scanSettings(r.db.QueryRow("SELECT "+settingsColumns+" FROM payment_gateway_settings WHERE building_id = ?", buildingID))
+}
+
+// SaveSettings creates or replaces the building's settings
+func (r *paymentGatewayRepo) SaveSettings(settings Settings, now time.Time) (Settings, error) {
+	nowStr := now.UTC().Format(timeLayout)
+	query := dialect.Current.Upsert(
+		"payment_gateway_settings",
+		[]string{"building_id", "clearing_account_id", "user_id", "status", "created_at", "updated_at"},
+		[]string{"building_id"},
+		[]string{"clearing_account_id", "user_id", "status", "updated_at"},
+	)
+	if _, err := r.db.Exec(query, settings.BuildingID, settings.ClearingAccountID, settings.UserID, settings.Status, nowStr, nowStr); err != nil {
+		return Settings{}, err
+	}
+	return r.GetSettings(settings.BuildingID)
+}
+
+func (r *paymentGatewayRepo) CreateIntent(intent Intent) (Intent, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO payment_intents (building_id, invoice_id, people_id, provider, provider_reference, checkout_url, amount, paid_amount, status, source, user_id, created_at, updated_at)"+
+			" VALUES (?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?)",
+		intent.BuildingID, intent.InvoiceID, intent.PeopleID, intent.Provider, intent.ProviderReference, intent.CheckoutURL,
+		intent.Amount, intent.Status, intent.Source, intent.UserID, intent.CreatedAt, intent.UpdatedAt,
+	)
+	if err != nil {
+		return Intent{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Intent{}, err
+	}
+	return r.GetIntent(intent.BuildingID, int(id))
+}
+
+func (r *paymentGatewayRepo) GetIntent(buildingID int, id int) (Intent, error) {
+	return scanIntent(r.db.QueryRow("SELECT "+intentColumns+" FROM payment_intents WHERE building_id = ? AND id = ?", buildingID, id))
+}
+
+func (r *paymentGatewayRepo) GetIntentByReference(provider string, reference string) (Intent, error) {
+	return scanIntent(r.db.QueryRow("SELECT "+intentColumns+" FROM payment_intents WHERE provider = ? AND provider_reference = ?", provider, reference))
+}
+
+// ListIntents returns the building's intents, newest first, optionally of one invoice or
+// with one status
+func (r *paymentGatewayRepo) ListIntents(buildingID int, invoiceID *int, status string) ([]Intent, error) {
+	query := "SELECT " + intentColumns + " FROM payment_intents WHERE building_id = ?"
+	args := []interface{}{buildingID}
+	if invoiceID != nil {
+		query += " AND invoice_id = ?"
+		args = append(args, *invoiceID)
+	}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	return r.queryIntents(query+" ORDER BY id DESC", args...)
+}
+
+// ListPersonIntents returns the intents of a person's invoices, newest first
+func (r *paymentGatewayRepo) ListPersonIntents(peopleID int) ([]Intent, error) {
+	return r.queryIntents("SELECT "+intentColumns+" FROM payment_intents WHERE people_id = ? ORDER BY id DESC", peopleID)
+}
+
+func (r *paymentGatewayRepo) queryIntents(query string, args ...interface{}) ([]Intent, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	intents := []Intent{}
+	for rows.Next() {
+		intent, err := scanIntent(rows)
+		if err != nil {
+			return nil, err
+		}
+		intents = append(intents, intent)
+	}
+	return intents, rows.Err()
+}
+
+// SetIntentStatus moves an intent from one status to another, reporting false when the
+// intent no longer had fromStatus
+func (r *paymentGatewayRepo) SetIntentStatus(id int, status string, fromStatus string, now time.Time) (bool, error) {
+	result, err := r.db.Exec("UPDATE payment_intents SET status = ?, updated_at = ? WHERE id = ? AND status = ?",
+		status, now.UTC().Format(timeLayout), id, fromStatus)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// IntentTotals returns what the active payments recorded from an intent's events add up to,
+// and whether any of them is a reversal. Payments voided by hand no longer count.
+func (r *paymentGatewayRepo) IntentTotals(id int) (float64, bool, error) {
+	var paid float64
+	var reversals int
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(ip.amount), 0), COALESCE(SUM(CASE WHEN e.type = ? THEN 1 ELSE 0 END), 0)
+		FROM payment_gateway_events e
+		INNER JOIN invoice_payments ip ON ip.id = e.payment_id
+		WHERE e.intent_id = ? AND e.status = ? AND ip.status = '1'`,
+		EventReversed, id, EventProcessed,
+	).Scan(&paid, &reversals)
+	return paid, reversals > 0, err
+}
+
+func (r *paymentGatewayRepo) UpdateIntentPaid(id int, paid float64, status string, now time.Time) error {
+	_, err := r.db.Exec("UPDATE payment_intents SET paid_amount = ?, status = ?, updated_at = ? WHERE id = ?",
+		paid, status, now.UTC().Format(timeLayout), id)
+	return err
+}
+
+// ClaimEvent records an event as processing, reporting false when the provider's event id
+// was already recorded. An event left processing longer than processingTimeout is taken over.
+func (r *paymentGatewayRepo) ClaimEvent(event Event, now time.Time) (bool, error) {
+	nowStr := now.UTC().Format(timeLayout)
+	_, err := r.db.Exec("DELETE FROM payment_gateway_events WHERE provider = ? AND event_id = ? AND status = ? AND updated_at < ?",
+		event.Provider, event.EventID, EventProcessing, now.Add(-processingTimeout).UTC().Format(timeLayout))
+	if err != nil {
+		return false, err
+	}
+
+	query := dialect.Current.Upsert(
+		"payment_gateway_events",
+		[]string{"provider", "event_id", "type", "amount", "status", "payload", "created_at", "updated_at"},
+		[]string{"provider", "event_id"},
+		nil,
+	)
+	result, err := r.db.Exec(query, event.Provider, event.EventID, event.Type, event.Amount, EventProcessing, event.Payload, nowStr, nowStr)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *paymentGatewayRepo) GetEvent(provider string, eventID string) (Event, error) {
+	return scanEvent(r.db.QueryRow("SELECT "+eventColumns+" FROM payment_gateway_events WHERE provider = ? AND event_id = ?", provider, eventID))
+}
+
+// FinishEvent records the outcome of a claimed event
+func (r *paymentGatewayRepo) FinishEvent(event Event, now time.Time) (Event, error) {
+	_, err := r.db.Exec("UPDATE payment_gateway_events SET intent_id = ?, status = ?, note = ?, payment_id = ?, updated_at = ? WHERE provider = ? AND event_id = ?",
+		event.IntentID, event.Status, event.Note, event.PaymentID, now.UTC().Format(timeLayout), event.Provider, event.EventID)
+	if err != nil {
+		return Event{}, err
+	}
+	return r.GetEvent(event.Provider, event.EventID)
+}
+
+// ReleaseEvent forgets a claimed event that could not be recorded, so the gateway's next
+// delivery of it is processed again
+func (r *paymentGatewayRepo) ReleaseEvent(id int) error {
+	_, err := r.db.Exec("DELETE FROM payment_gateway_events WHERE id = ? AND status = ?", id, EventProcessing)
+	return err
+}
+
+func (r *paymentGatewayRepo) ListEvents(intentID int) ([]Event, error) {
+	rows, err := r.db.Query("SELECT "+eventColumns+" FROM payment_gateway_events WHERE intent_id = ? ORDER BY id", intentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// InvoiceBalance returns what is left to pay of an invoice after payments, applied credits
+// and discounts
+func (r *paymentGatewayRepo) InvoiceBalance(invoiceID int) (float64, error) {
+	var balance float64
+	err := r.db.QueryRow(`
+		SELECT i.amount
+			- COALESCE((SELECT SUM(ip.amount) FROM invoice_payments ip WHERE ip.invoice_id = i.id AND ip.status = '1'), 0)
+			- COALESCE((SELECT SUM(c.amount) FROM invoice_applied_credits c WHERE c.invoice_id = i.id AND c.status = '1'), 0)
+			- COALESCE((SELECT SUM(d.amount) FROM invoice_applied_discounts d WHERE d.invoice_id = i.id AND d.status = '1'), 0)
+		FROM invoices i
+		WHERE i.id = ?`, invoiceID,
+	).Scan(&balance)
+	return balance, err
+}
+
+// FindPayment returns the id of an invoice payment by its reference, used to find the
+// payment of an event whose processing was cut off after the payment was recorded
+func (r *paymentGatewayRepo) FindPayment(invoiceID int, reference string) (int, error) {
+	var id int
+	err := r.db.QueryRow("SELECT id FROM invoice_payments WHERE invoice_id = ? AND reference = ? ORDER BY id DESC LIMIT 1", invoiceID, reference).Scan(&id)
+	return id, err
+}
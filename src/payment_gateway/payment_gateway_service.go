@@ -0,0 +1,410 @@
+package payment_gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/invoice_payments"
+	"github.com/mysecodgit/go_accounting/src/invoices"
+)
+
+// checkoutTimeout bounds asking the gateway for a checkout
+const checkoutTimeout = 30 * time.Second
+
+type PaymentGatewayService struct {
+	repo           PaymentGatewayRepository
+	invoiceRepo    invoices.InvoiceRepository
+	accountRepo    accounts.AccountRepository
+	paymentService *invoice_payments.InvoicePaymentService
+	provider       Provider // Nil when online payment is off
+	logger         *slog.Logger
+}
+
+func NewPaymentGatewayService(repo PaymentGatewayRepository, invoiceRepo invoices.InvoiceRepository, accountRepo accounts.AccountRepository, paymentService *invoice_payments.InvoicePaymentService, provider Provider, logger *slog.Logger) *PaymentGatewayService {
+	return &PaymentGatewayService{
+		repo:           repo,
+		invoiceRepo:    invoiceRepo,
+		accountRepo:    accountRepo,
+		paymentService: paymentService,
+		provider:       provider,
+		logger:         logger,
+	}
+}
+
+// WithLogger returns a copy of the service that logs to logger, e.g. the request's logger
+func (s *PaymentGatewayService) WithLogger(logger *slog.Logger) *PaymentGatewayService {
+	copy := *s
+	copy.logger = logger
+	return &copy
+}
+
+func (s *PaymentGatewayService) GetSettings(buildingID int) (*Settings, error) {
+	settings, err := s.repo.GetSettings(buildingID)
+	if err != nil {
+		return nil, apperrors.Lookup("payment gateway settings", err)
+	}
+	return &settings, nil
+}
+
+// SaveSettings sets the building's clearing account; gateway payments are recorded by userID
+func (s *PaymentGatewayService) SaveSettings(buildingID int, req SettingsRequest, userID int) (*Settings, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	account, accountType, _, err := s.accountRepo.GetByID(req.ClearingAccountID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && account.BuildingID != buildingID) {
+		return nil, map[string]string{"clearing_account_id": "Clearing account not found in this building"}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load clearing account: %w", err)
+	}
+	if !strings.EqualFold(accountType.Type, "asset") {
+		return nil, map[string]string{"clearing_account_id": "Clearing account must be an asset account"}, nil
+	}
+
+	status := req.Status
+	if status == "" {
+		status = StatusActive
+	}
+	settings, err := s.repo.SaveSettings(Settings{BuildingID: buildingID, ClearingAccountID: req.ClearingAccountID, UserID: userID, Status: status}, time.Now())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save payment gateway settings: %w", err)
+	}
+
+	s.logger.Info("payment gateway settings saved", "building_id", buildingID, "clearing_account_id", req.ClearingAccountID, "status", status)
+	return &settings, nil, nil
+}
+
+// CreateIntent hands (part of) an invoice's balance to the gateway. userID is nil for intents
+// started by the tenant in the portal.
+func (s *PaymentGatewayService) CreateIntent(buildingID int, req CreateIntentRequest, source string, userID *int) (*Intent, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	if s.provider == nil {
+		return nil, nil, apperrors.Rule("Online payment is turned off, no payment gateway is configured")
+	}
+	settings, err := s.repo.GetSettings(buildingID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && settings.Status != StatusActive) {
+		return nil, nil, apperrors.Rule("Online payment is not set up for this building")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load payment gateway settings: %w", err)
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(req.InvoiceID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && invoice.BuildingID != buildingID) {
+		return nil, nil, apperrors.NotFound("invoice")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load invoice: %w", err)
+	}
+	if invoice.Status != 1 {
+		return nil, nil, apperrors.Rule("The invoice is cancelled")
+	}
+	if invoice.PeopleID == nil {
+		return nil, nil, apperrors.Rule("The invoice has no customer to pay it")
+	}
+	balance, err := s.repo.InvoiceBalance(invoice.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load invoice balance: %w", err)
+	}
+	balance = math.Round(balance*100) / 100
+	if balance <= 0 {
+		return nil, nil, apperrors.Rule("The invoice has nothing left to pay")
+	}
+	amount := balance
+	if req.Amount != nil {
+		amount = math.Round(*req.Amount*100) / 100
+		if amount > balance {
+			return nil, map[string]string{"amount": fmt.Sprintf("Amount must not exceed the invoice's balance of %.2f", balance)}, nil
+		}
+	}
+
+	now := time.Now()
+	intent := Intent{
+		BuildingID: buildingID,
+		InvoiceID:  invoice.ID,
+		PeopleID:   *invoice.PeopleID,
+		Provider:   s.provider.Name(),
+		Amount:     amount,
+		Status:     IntentPending,
+		Source:     source,
+		UserID:     userID,
+		CreatedAt:  now.UTC().Format(timeLayout),
+		UpdatedAt:  now.UTC().Format(timeLayout),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), checkoutTimeout)
+	defer cancel()
+	checkout, err := s.provider.CreateCheckout(ctx, intent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create checkout with %s: %w", s.provider.Name(), err)
+	}
+	intent.ProviderReference = checkout.Reference
+	intent.CheckoutURL = checkout.URL
+
+	created, err := s.repo.CreateIntent(intent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save payment intent: %w", err)
+	}
+
+	s.logger.Info("payment intent created", "intent_id", created.ID, "invoice_id", invoice.ID, "amount", amount, "source", source)
+	return &created, nil, nil
+}
+
+func (s *PaymentGatewayService) ListIntents(buildingID int, invoiceID *int, status string) ([]Intent, error) {
+	intents, err := s.repo.ListIntents(buildingID, invoiceID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment intents: %w", err)
+	}
+	return intents, nil
+}
+
+// ListPersonIntents returns the intents of a person's invoices, for the tenant portal
+func (s *PaymentGatewayService) ListPersonIntents(peopleID int) ([]Intent, error) {
+	intents, err := s.repo.ListPersonIntents(peopleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment intents: %w", err)
+	}
+	return intents, nil
+}
+
+// GetIntent returns an intent with its events
+func (s *PaymentGatewayService) GetIntent(buildingID int, id int) (*Intent, error) {
+	intent, err := s.repo.GetIntent(buildingID, id)
+	if err != nil {
+		return nil, apperrors.Lookup("payment intent", err)
+	}
+	intent.Events, err = s.repo.ListEvents(intent.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment events: %w", err)
+	}
+	return &intent, nil
+}
+
+// CancelIntent cancels an intent nothing was paid of. Payments the gateway reports for it
+// afterwards are still recorded, since the money was taken.
+func (s *PaymentGatewayService) CancelIntent(buildingID int, id int) (*Intent, error) {
+	intent, err := s.repo.GetIntent(buildingID, id)
+	if err != nil {
+		return nil, apperrors.Lookup("payment intent", err)
+	}
+	cancelled, err := s.repo.SetIntentStatus(intent.ID, IntentCancelled, IntentPending, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel payment intent: %w", err)
+	}
+	if !cancelled {
+		return nil, apperrors.Rulef("Only pending payment intents can be cancelled, this one is %s", intent.Status)
+	}
+
+	s.logger.Info("payment intent cancelled", "intent_id", intent.ID)
+	return s.GetIntent(buildingID, id)
+}
+
+// HandleWebhook verifies and records an event posted by the gateway. An event recorded before
+// is answered as a duplicate; an event that fails to be recorded is released so the gateway's
+// next delivery is processed again.
+func (s *PaymentGatewayService) HandleWebhook(providerName string, header http.Header, body []byte) (*WebhookResponse, error) {
+	if s.provider == nil || providerName != s.provider.Name() {
+		return nil, apperrors.NotFound("payment provider")
+	}
+	now := time.Now()
+	notification, err := s.provider.ParseWebhook(header, body, now)
+	if err != nil {
+		s.logger.Warn("payment webhook rejected", "provider", providerName, "error", err)
+		return nil, err
+	}
+
+	event := Event{
+		Provider: providerName,
+		EventID:  notification.EventID,
+		Type:     notification.Type,
+		Amount:   notification.Amount,
+		Payload:  string(body),
+	}
+	claimed, err := s.repo.ClaimEvent(event, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record payment event: %w", err)
+	}
+	if !claimed {
+		existing, err := s.repo.GetEvent(providerName, notification.EventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load payment event: %w", err)
+		}
+		if existing.Status == EventProcessing {
+			return nil, apperrors.Conflict("The event is being processed, deliver it again later")
+		}
+		s.logger.Info("duplicate payment event ignored", "provider", providerName, "event_id", notification.EventID)
+		return &WebhookResponse{Event: existing, Duplicate: true}, nil
+	}
+
+	recorded, err := s.process(event, notification, now)
+	if err != nil {
+		claimedEvent, getErr := s.repo.GetEvent(providerName, notification.EventID)
+		if getErr == nil {
+			if releaseErr := s.repo.ReleaseEvent(claimedEvent.ID); releaseErr != nil {
+				s.logger.Error("failed to release payment event", "event_id", notification.EventID, "error", releaseErr)
+			}
+		}
+		return nil, err
+	}
+
+	s.logger.Info("payment event recorded", "provider", providerName, "event_id", recorded.EventID, "type", recorded.Type, "status", recorded.Status)
+	return &WebhookResponse{Event: recorded}, nil
+}
+
+// Simulate makes the fake provider post an event of an intent, signed and verified like a
+// real webhook
+func (s *PaymentGatewayService) Simulate(buildingID int, id int, req SimulateRequest) (*WebhookResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	fake, ok := s.provider.(*FakeProvider)
+	if !ok {
+		return nil, nil, apperrors.Rule("Payments can only be simulated with the fake provider")
+	}
+	intent, err := s.repo.GetIntent(buildingID, id)
+	if err != nil {
+		return nil, nil, apperrors.Lookup("payment intent", err)
+	}
+	if intent.Provider != fake.Name() {
+		return nil, nil, apperrors.Rulef("The payment intent belongs to the %s provider", intent.Provider)
+	}
+
+	var amount float64
+	switch {
+	case req.Amount != nil:
+		amount = *req.Amount
+	case req.Type == EventSucceeded:
+		amount = math.Max(intent.Amount-intent.PaidAmount, 0)
+	case req.Type == EventReversed:
+		amount = intent.PaidAmount
+	}
+
+	now := time.Now()
+	body, signature, err := fake.Event(req.Type, intent.ProviderReference, math.Round(amount*100)/100, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build fake event: %w", err)
+	}
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, signature)
+
+	response, err := s.HandleWebhook(fake.Name(), header, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, nil, nil
+}
+
+// process applies a claimed event to its intent and records the outcome on the event
+func (s *PaymentGatewayService) process(event Event, notification Notification, now time.Time) (Event, error) {
+	intent, err := s.repo.GetIntentByReference(event.Provider, notification.Reference)
+	if errors.Is(err, sql.ErrNoRows) {
+		s.logger.Warn("payment event of an unknown intent", "provider", event.Provider, "reference", notification.Reference)
+		return s.finish(event, EventIgnored, "No payment intent has this reference", now)
+	}
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to load payment intent: %w", err)
+	}
+	event.IntentID = &intent.ID
+
+	switch notification.Type {
+	case EventFailed:
+		failed, err := s.repo.SetIntentStatus(intent.ID, IntentFailed, IntentPending, now)
+		if err != nil {
+			return Event{}, fmt.Errorf("failed to update payment intent: %w", err)
+		}
+		if !failed {
+			return s.finish(event, EventIgnored, fmt.Sprintf("The payment intent was already %s", intent.Status), now)
+		}
+		return s.finish(event, EventProcessed, "", now)
+
+	case EventReversed:
+		if notification.Amount > intent.PaidAmount+0.005 {
+			return s.finish(event, EventIgnored, fmt.Sprintf("The reversal of %.2f is more than the %.2f paid", notification.Amount, intent.PaidAmount), now)
+		}
+		return s.recordPayment(event, intent, notification, -notification.Amount, now)
+
+	default:
+		// Money the gateway collected is always recorded, even past the intent's amount or
+		// after it was closed, so that the clearing account agrees with the gateway
+		if intent.Status == IntentCancelled || intent.Status == IntentFailed {
+			s.logger.Warn("payment received for a closed intent", "intent_id", intent.ID, "status", intent.Status)
+		}
+		return s.recordPayment(event, intent, notification, notification.Amount, now)
+	}
+}
+
+// recordPayment records an invoice payment of the event into the building's clearing account;
+// negative amounts are reversals. The payment's reference is the provider's event id, which
+// finds the payment again when an earlier attempt was cut off after recording it.
+func (s *PaymentGatewayService) recordPayment(event Event, intent Intent, notification Notification, amount float64, now time.Time) (Event, error) {
+	settings, err := s.repo.GetSettings(intent.BuildingID)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to load payment gateway settings of building %d: %w", intent.BuildingID, err)
+	}
+
+	paymentID, err := s.repo.FindPayment(intent.InvoiceID, notification.EventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		payment, createErr := s.paymentService.WithLogger(s.logger).CreateInvoicePayment(invoice_payments.CreateInvoicePaymentRequest{
+			Reference:  notification.EventID,
+			Date:       notification.Date,
+			InvoiceID:  intent.InvoiceID,
+			AccountID:  settings.ClearingAccountID,
+			Amount:     amount,
+			BuildingID: intent.BuildingID,
+		}, settings.UserID)
+		if createErr != nil {
+			return Event{}, fmt.Errorf("failed to record invoice payment: %w", createErr)
+		}
+		paymentID, err = payment.Payment.ID, nil
+	}
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to look up invoice payment: %w", err)
+	}
+	event.PaymentID = &paymentID
+
+	recorded, err := s.finish(event, EventProcessed, "", now)
+	if err != nil {
+		return Event{}, err
+	}
+	if err := s.refreshIntent(intent, now); err != nil {
+		return Event{}, err
+	}
+	return recorded, nil
+}
+
+// refreshIntent recomputes what was paid of an intent from its recorded payments
+func (s *PaymentGatewayService) refreshIntent(intent Intent, now time.Time) error {
+	paid, reversed, err := s.repo.IntentTotals(intent.ID)
+	if err != nil {
+		return fmt.Errorf("failed to total payment intent: %w", err)
+	}
+	paid = math.Round(paid*100) / 100
+	if err := s.repo.UpdateIntentPaid(intent.ID, paid, intentStatus(intent, paid, reversed), now); err != nil {
+		return fmt.Errorf("failed to update payment intent: %w", err)
+	}
+	return nil
+}
+
+func (s *PaymentGatewayService) finish(event Event, status string, note string, now time.Time) (Event, error) {
+	event.Status = status
+	if note != "" {
+		event.Note = &note
+	}
+	recorded, err := s.repo.FinishEvent(event, now)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to record payment event: %w", err)
+	}
+	return recorded, nil
+}
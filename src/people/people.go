@@ -1,15 +1,34 @@
 package people
 
-import "strings"
+import (
+	"net/mail"
+	"strings"
+)
+
+// NormalizePhone keeps the digits of a phone number so that "+252 61-555 0101" and
+// "25261 5550101" match; a leading 00 is read as +
+func NormalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	return strings.TrimPrefix(digits.String(), "00")
+}
 
 type Person struct {
 	ID         int    `json:"id"`
 	Name       string `json:"name"`
 	Phone      string `json:"phone"`
+	Email      string `json:"email"` // Optional; notifications by email need it
 	TypeID     int    `json:"type_id"`
 	BuildingID int    `json:"building_id,omitempty"`
 	CreatedAt  string `json:"created_at"`
 	UpdatedAt  string `json:"updated_at"`
+
+	// Digits of Phone, which the portal looks people up by; set by the service
+	PhoneNormalized string `json:"-"`
 }
 
 func (p *Person) Validate() map[string]string {
@@ -23,6 +42,10 @@ func (p *Person) Validate() map[string]string {
 		errors["phone"] = "Phone cannot be empty"
 	}
 
+	if msg := validateEmail(p.Email); msg != "" {
+		errors["email"] = msg
+	}
+
 	if p.TypeID <= 0 {
 		errors["type_id"] = "Type ID must be greater than 0"
 	}
@@ -34,3 +57,15 @@ func (p *Person) Validate() map[string]string {
 	return errors
 }
 
+// validateEmail accepts an empty address or a single plain address such as tenant@example.com
+func validateEmail(email string) string {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ""
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "Email must be a valid email address"
+	}
+	return ""
+}
@@ -10,6 +10,7 @@ type PersonResponse struct {
 	ID        int                        `json:"id"`
 	Name      string                     `json:"name"`
 	Phone     string                     `json:"phone"`
+	Email     string                     `json:"email"`
 	Type      people_types.PeopleType    `json:"type"`
 	Building  building.Building          `json:"building"`
 	CreatedAt string                     `json:"created_at"`
@@ -21,6 +22,7 @@ func (p *Person) ToPersonResponse(pt people_types.PeopleType, b building.Buildin
 		ID:        p.ID,
 		Name:      p.Name,
 		Phone:     p.Phone,
+		Email:     p.Email,
 		Type:      pt,
 		Building:  b,
 		CreatedAt: p.CreatedAt,
@@ -31,6 +33,7 @@ func (p *Person) ToPersonResponse(pt people_types.PeopleType, b building.Buildin
 type UpdatePersonRequest struct {
 	Name   string `json:"name"`
 	Phone  string `json:"phone"`
+	Email  string `json:"email"`
 	TypeID int    `json:"type_id"`
 }
 
@@ -45,6 +48,10 @@ func (u *UpdatePersonRequest) Validate() map[string]string {
 		errors["phone"] = "Phone cannot be empty"
 	}
 
+	if msg := validateEmail(u.Email); msg != "" {
+		errors["email"] = msg
+	}
+
 	if u.TypeID <= 0 {
 		errors["type_id"] = "Type ID must be greater than 0"
 	}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type PersonHandler struct {
@@ -19,19 +20,19 @@ func NewPersonHandler(service *PersonService) *PersonHandler {
 func (h *PersonHandler) CreatePerson(c *gin.Context) {
 	var person Person
 	if err := c.ShouldBindJSON(&person); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.CreatePerson(person)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -42,7 +43,7 @@ func (h *PersonHandler) CreatePerson(c *gin.Context) {
 func (h *PersonHandler) GetPeople(c *gin.Context) {
 	people, err := h.service.GetAllPeople()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -55,13 +56,19 @@ func (h *PersonHandler) GetPeopleByBuilding(c *gin.Context) {
 	buildingID, err := strconv.Atoi(buildingIDStr)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
 		return
 	}
 
-	people, err := h.service.GetPeopleByBuildingID(buildingID)
+	params, validationErrors := personListSpec.Parse(c.Request.URL.Query())
+	if validationErrors != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
+		return
+	}
+
+	people, err := h.service.GetPeopleByBuildingID(buildingID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -78,13 +85,13 @@ func (h *PersonHandler) GetPerson(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	person, err := h.service.GetPersonByID(int(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -101,25 +108,25 @@ func (h *PersonHandler) UpdatePerson(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	var updateReq UpdatePersonRequest
 	if err := c.ShouldBindJSON(&updateReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.UpdatePerson(id, updateReq)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
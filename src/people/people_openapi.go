@@ -0,0 +1,14 @@
+package people
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"PersonHandler.GetPeople":           {Summary: "List people of all buildings", Response: []PersonResponse{}},
+	"PersonHandler.GetPeopleByBuilding": {Summary: "List a building's customers and vendors", Response: pagination.Page[PersonResponse]{}, List: &personListSpec},
+	"PersonHandler.GetPerson":           {Summary: "Get a person", Response: PersonResponse{}},
+	"PersonHandler.CreatePerson":        {Summary: "Create a person", Request: Person{}, Response: Person{}},
+	"PersonHandler.UpdatePerson":        {Summary: "Update a person", Request: UpdatePersonRequest{}, Response: Person{}},
+}
@@ -2,18 +2,21 @@ package people
 
 import (
 	"database/sql"
-	"fmt"
+	"strings"
 
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/building"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 	"github.com/mysecodgit/go_accounting/src/people_types"
 )
 
 type PersonRepository interface {
 	Create(person Person) (Person, error)
-	UpdateNameAndPhone(name string, phone string, typeID int, id int) (Person, error)
+	Update(name string, phone string, phoneNormalized string, email string, typeID int, id int) (Person, error)
 	GetByID(id int) (Person, people_types.PeopleType, building.Building, error)
 	GetAll() ([]Person, []people_types.PeopleType, []building.Building, error)
 	GetByBuildingID(buildingID int) ([]Person, []people_types.PeopleType, []building.Building, error)
+	List(buildingID int, params pagination.Params) ([]Person, []people_types.PeopleType, []building.Building, int, error)
 	TypeIDExists(typeID int) (bool, int, error)
 	BuildingIDExists(buildingID int) (bool, error)
 	PersonNameExists(name string, buildingID int, excludeID int) (bool, error)
@@ -28,8 +31,8 @@ func NewPersonRepository(db *sql.DB) PersonRepository {
 }
 
 func (r *personRepo) Create(person Person) (Person, error) {
-	result, err := r.db.Exec("INSERT INTO people (name, phone, type_id, building_id) VALUES (?, ?, ?, ?)",
-		person.Name, person.Phone, person.TypeID, person.BuildingID)
+	result, err := r.db.Exec("INSERT INTO people (name, phone, phone_normalized, email, type_id, building_id) VALUES (?, ?, ?, ?, ?, ?)",
+		person.Name, person.Phone, person.PhoneNormalized, strings.TrimSpace(person.Email), person.TypeID, person.BuildingID)
 
 	if err != nil {
 		return person, err
@@ -39,24 +42,24 @@ func (r *personRepo) Create(person Person) (Person, error) {
 	person.ID = int(id)
 
 	// Fetch the created record to get created_at and updated_at
-	err = r.db.QueryRow("SELECT id, name, phone, type_id, building_id, created_at, updated_at FROM people WHERE id = ?", person.ID).
-		Scan(&person.ID, &person.Name, &person.Phone, &person.TypeID, &person.BuildingID, &person.CreatedAt, &person.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, name, phone, email, type_id, building_id, created_at, updated_at FROM people WHERE id = ?", person.ID).
+		Scan(&person.ID, &person.Name, &person.Phone, &person.Email, &person.TypeID, &person.BuildingID, &person.CreatedAt, &person.UpdatedAt)
 
 	return person, err
 }
 
-func (r *personRepo) UpdateNameAndPhone(name string, phone string, typeID int, id int) (Person, error) {
+func (r *personRepo) Update(name string, phone string, phoneNormalized string, email string, typeID int, id int) (Person, error) {
 	var person Person
-	_, err := r.db.Exec("UPDATE people SET name=?, phone=?, type_id=?, updated_at=NOW() WHERE id=?",
-		name, phone, typeID, id)
+	_, err := r.db.Exec("UPDATE people SET name=?, phone=?, phone_normalized=?, email=?, type_id=?, updated_at=NOW() WHERE id=?",
+		name, phone, phoneNormalized, strings.TrimSpace(email), typeID, id)
 
 	if err != nil {
 		return person, err
 	}
 
 	// Fetch the updated record to get all fields
-	err = r.db.QueryRow("SELECT id, name, phone, type_id, building_id, created_at, updated_at FROM people WHERE id = ?", id).
-		Scan(&person.ID, &person.Name, &person.Phone, &person.TypeID, &person.BuildingID, &person.CreatedAt, &person.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, name, phone, email, type_id, building_id, created_at, updated_at FROM people WHERE id = ?", id).
+		Scan(&person.ID, &person.Name, &person.Phone, &person.Email, &person.TypeID, &person.BuildingID, &person.CreatedAt, &person.UpdatedAt)
 
 	return person, err
 }
@@ -66,19 +69,19 @@ func (r *personRepo) GetByID(id int) (Person, people_types.PeopleType, building.
 	var pt people_types.PeopleType
 	var b building.Building
 	err := r.db.QueryRow(`
-		SELECT p.id, p.name, p.phone, p.type_id, p.building_id, p.created_at, p.updated_at,
+		SELECT p.id, p.name, p.phone, p.email, p.type_id, p.building_id, p.created_at, p.updated_at,
 		       pt.id, pt.title,
 		       b.id, b.name, b.created_at, b.updated_at
 		FROM people p
 		INNER JOIN people_types pt ON p.type_id = pt.id
 		INNER JOIN buildings b ON p.building_id = b.id
 		WHERE p.id = ?`, id).
-		Scan(&person.ID, &person.Name, &person.Phone, &person.TypeID, &person.BuildingID, &person.CreatedAt, &person.UpdatedAt,
+		Scan(&person.ID, &person.Name, &person.Phone, &person.Email, &person.TypeID, &person.BuildingID, &person.CreatedAt, &person.UpdatedAt,
 			&pt.ID, &pt.Title,
 			&b.ID, &b.Name, &b.CreatedAt, &b.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return person, pt, b, fmt.Errorf("id does not exist")
+		return person, pt, b, apperrors.NotFound("person")
 	}
 
 	return person, pt, b, err
@@ -86,7 +89,7 @@ func (r *personRepo) GetByID(id int) (Person, people_types.PeopleType, building.
 
 func (r *personRepo) GetAll() ([]Person, []people_types.PeopleType, []building.Building, error) {
 	rows, err := r.db.Query(`
-		SELECT p.id, p.name, p.phone, p.type_id, p.building_id, p.created_at, p.updated_at,
+		SELECT p.id, p.name, p.phone, p.email, p.type_id, p.building_id, p.created_at, p.updated_at,
 		       pt.id, pt.title,
 		       b.id, b.name, b.created_at, b.updated_at
 		FROM people p
@@ -104,7 +107,7 @@ func (r *personRepo) GetAll() ([]Person, []people_types.PeopleType, []building.B
 		var p Person
 		var pt people_types.PeopleType
 		var b building.Building
-		err := rows.Scan(&p.ID, &p.Name, &p.Phone, &p.TypeID, &p.BuildingID, &p.CreatedAt, &p.UpdatedAt,
+		err := rows.Scan(&p.ID, &p.Name, &p.Phone, &p.Email, &p.TypeID, &p.BuildingID, &p.CreatedAt, &p.UpdatedAt,
 			&pt.ID, &pt.Title,
 			&b.ID, &b.Name, &b.CreatedAt, &b.UpdatedAt)
 		if err != nil {
@@ -119,7 +122,7 @@ func (r *personRepo) GetAll() ([]Person, []people_types.PeopleType, []building.B
 
 func (r *personRepo) GetByBuildingID(buildingID int) ([]Person, []people_types.PeopleType, []building.Building, error) {
 	rows, err := r.db.Query(`
-		SELECT p.id, p.name, p.phone, p.type_id, p.building_id, p.created_at, p.updated_at,
+		SELECT p.id, p.name, p.phone, p.email, p.type_id, p.building_id, p.created_at, p.updated_at,
 		       pt.id, pt.title,
 		       b.id, b.name, b.created_at, b.updated_at
 		FROM people p
@@ -138,7 +141,7 @@ func (r *personRepo) GetByBuildingID(buildingID int) ([]Person, []people_types.P
 		var p Person
 		var pt people_types.PeopleType
 		var b building.Building
-		err := rows.Scan(&p.ID, &p.Name, &p.Phone, &p.TypeID, &p.BuildingID, &p.CreatedAt, &p.UpdatedAt,
+		err := rows.Scan(&p.ID, &p.Name, &p.Phone, &p.Email, &p.TypeID, &p.BuildingID, &p.CreatedAt, &p.UpdatedAt,
 			&pt.ID, &pt.Title,
 			&b.ID, &b.Name, &b.CreatedAt, &b.UpdatedAt)
 		if err != nil {
@@ -151,6 +154,75 @@ func (r *personRepo) GetByBuildingID(buildingID int) ([]Person, []people_types.P
 	return people, peopleTypes, buildings, nil
 }
 
+var personListSpec = pagination.Spec{
+	IDColumn: "p.id",
+	Sorts: map[string]string{
+		"id":         "p.id",
+		"name":       "p.name",
+		"created_at": "p.created_at",
+	},
+	DefaultSort:   "name",
+	SearchColumns: []string{"p.name", "p.phone", "p.email"},
+	TypeFilter:    "p.type_id = ?",
+}
+
+func personSortKey(person PersonResponse, sort string) (interface{}, int) {
+	switch sort {
+	case "name":
+		return person.Name, person.ID
+	case "created_at":
+		return person.CreatedAt, person.ID
+	}
+	return person.ID, person.ID
+}
+
+func (r *personRepo) List(buildingID int, params pagination.Params) ([]Person, []people_types.PeopleType, []building.Building, int, error) {
+	where, args := personListSpec.Where(params)
+	where = " WHERE p.building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM people p"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	after, afterArgs := personListSpec.After(params)
+	order, orderArgs := personListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, p.phone, p.email, p.type_id, p.building_id, p.created_at, p.updated_at,
+		       pt.id, pt.title,
+		       b.id, b.name, b.created_at, b.updated_at
+		FROM people p
+		INNER JOIN people_types pt ON p.type_id = pt.id
+		INNER JOIN buildings b ON p.building_id = b.id`+where+after+order, args...)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+	defer rows.Close()
+
+	people := []Person{}
+	peopleTypes := []people_types.PeopleType{}
+	buildings := []building.Building{}
+	for rows.Next() {
+		var p Person
+		var pt people_types.PeopleType
+		var b building.Building
+		err := rows.Scan(&p.ID, &p.Name, &p.Phone, &p.Email, &p.TypeID, &p.BuildingID, &p.CreatedAt, &p.UpdatedAt,
+			&pt.ID, &pt.Title,
+			&b.ID, &b.Name, &b.CreatedAt, &b.UpdatedAt)
+		if err != nil {
+			return nil, nil, nil, 0, err
+		}
+		people = append(people, p)
+		peopleTypes = append(peopleTypes, pt)
+		buildings = append(buildings, b)
+	}
+	return people, peopleTypes, buildings, total, rows.Err()
+}
+
 func (r *personRepo) TypeIDExists(typeID int) (bool, int, error) {
 	var id int
 	err := r.db.QueryRow("SELECT id FROM people_types WHERE id = ?", typeID).
@@ -1,5 +1,7 @@
 package people
 
+import "github.com/mysecodgit/go_accounting/src/pagination"
+
 type PersonService struct {
 	repo PersonRepository
 }
@@ -36,7 +38,8 @@ func (s *PersonService) CreatePerson(person Person) (*Person, map[string]string,
 
 	// building_id must be provided in the request (no longer comes from people_types)
 
-	// Save to DB
+	// Save to DB, with the phone digits the portal signs in with
+	person.PhoneNormalized = NormalizePhone(person.Phone)
 	createdPerson, err := s.repo.Create(person)
 	if err != nil {
 		return nil, nil, err // internal/server error
@@ -60,10 +63,10 @@ func (s *PersonService) GetAllPeople() ([]PersonResponse, error) {
 	return responses, nil
 }
 
-func (s *PersonService) GetPeopleByBuildingID(buildingID int) ([]PersonResponse, error) {
-	people, peopleTypes, buildings, err := s.repo.GetByBuildingID(buildingID)
+func (s *PersonService) GetPeopleByBuildingID(buildingID int, params pagination.Params) (pagination.Page[PersonResponse], error) {
+	people, peopleTypes, buildings, total, err := s.repo.List(buildingID, params)
 	if err != nil {
-		return nil, err
+		return pagination.Page[PersonResponse]{}, err
 	}
 
 	responses := []PersonResponse{}
@@ -72,7 +75,7 @@ func (s *PersonService) GetPeopleByBuildingID(buildingID int) ([]PersonResponse,
 		responses = append(responses, response)
 	}
 
-	return responses, nil
+	return pagination.NewPage(responses, total, params, personSortKey), nil
 }
 
 func (s *PersonService) GetPersonByID(id int) (*PersonResponse, error) {
@@ -117,8 +120,8 @@ func (s *PersonService) UpdatePerson(id int, updateReq UpdatePersonRequest) (*Pe
 		return nil, map[string]string{"name": "A person with this name already exists in this building"}, nil
 	}
 
-	// Update name, phone, and type_id in DB
-	updatedPerson, err := s.repo.UpdateNameAndPhone(updateReq.Name, updateReq.Phone, updateReq.TypeID, id)
+	// Update name, phone, email and type_id in DB
+	updatedPerson, err := s.repo.Update(updateReq.Name, updateReq.Phone, NormalizePhone(updateReq.Phone), updateReq.Email, updateReq.TypeID, id)
 	if err != nil {
 		return nil, nil, err // internal/server error
 	}
@@ -0,0 +1,10 @@
+package people_types
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"PeopleTypeHandler.GetPeopleTypes":   {Summary: "List people types", Response: []PeopleTypeResponse{}},
+	"PeopleTypeHandler.GetPeopleType":    {Summary: "Get a people type", Response: PeopleTypeResponse{}},
+	"PeopleTypeHandler.CreatePeopleType": {Summary: "Create a people type", Request: PeopleType{}, Response: PeopleType{}},
+	"PeopleTypeHandler.UpdatePeopleType": {Summary: "Update a people type", Request: UpdatePeopleTypeRequest{}, Response: PeopleType{}},
+}
@@ -2,7 +2,8 @@ package people_types
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type PeopleTypeRepository interface {
@@ -58,7 +59,7 @@ func (r *peopleTypeRepo) GetByID(id int) (PeopleType, error) {
 		Scan(&peopleType.ID, &peopleType.Title)
 	
 	if err == sql.ErrNoRows {
-		return peopleType, fmt.Errorf("id does not exist")
+		return peopleType, apperrors.NotFound("people type")
 	}
 	
 	return peopleType, err
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type PeriodHandler struct {
@@ -19,24 +20,24 @@ func NewPeriodHandler(service *PeriodService) *PeriodHandler {
 func (h *PeriodHandler) CreatePeriod(c *gin.Context) {
 	var period Period
 	if err := c.ShouldBindJSON(&period); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.CreatePeriod(period)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
 		// Check if it's a duplicate period error
 		if otherErrors.Error() == "building cannot have duplicate period with the same start and end date" {
-			c.JSON(http.StatusConflict, gin.H{"error": otherErrors.Error()})
+			apperrors.Respond(c, otherErrors)
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -47,7 +48,7 @@ func (h *PeriodHandler) CreatePeriod(c *gin.Context) {
 func (h *PeriodHandler) GetPeriods(c *gin.Context) {
 	periods, err := h.service.GetAllPeriods()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -60,13 +61,13 @@ func (h *PeriodHandler) GetPeriodsByBuilding(c *gin.Context) {
 	buildingID, err := strconv.Atoi(buildingIDStr)
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
 		return
 	}
 
 	periods, err := h.service.GetPeriodsByBuildingID(buildingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -83,17 +84,17 @@ func (h *PeriodHandler) GetPeriod(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	period, err := h.service.GetPeriodByID(int(id))
 	if err != nil {
 		if err.Error() == "id does not exist" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			apperrors.Respond(c, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -110,30 +111,30 @@ func (h *PeriodHandler) UpdatePeriod(c *gin.Context) {
 	id, err := strconv.Atoi(stringId)
 
 	if err != nil {
-		c.JSON(400, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	var period Period
 	if err := c.ShouldBindJSON(&period); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, validationErr, otherErrors := h.service.UpdatePeriod(id, period)
 
 	if validationErr != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"errors": validationErr})
+		apperrors.Respond(c, apperrors.Validation(validationErr))
 		return
 	}
 
 	if otherErrors != nil {
 		// Check if it's a duplicate period error
 		if otherErrors.Error() == "building cannot have duplicate period with the same start and end date" {
-			c.JSON(http.StatusConflict, gin.H{"error": otherErrors.Error()})
+			apperrors.Respond(c, otherErrors)
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": otherErrors.Error()})
+		apperrors.Respond(c, otherErrors)
 		return
 	}
 
@@ -0,0 +1,11 @@
+package period
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"PeriodHandler.GetPeriods":           {Summary: "List periods of all buildings", Response: []PeriodResponse{}},
+	"PeriodHandler.GetPeriodsByBuilding": {Summary: "List a building's periods", Response: []PeriodResponse{}},
+	"PeriodHandler.GetPeriod":            {Summary: "Get a period", Response: PeriodResponse{}},
+	"PeriodHandler.CreatePeriod":         {Summary: "Create a period", Request: Period{}, Response: Period{}},
+	"PeriodHandler.UpdatePeriod":         {Summary: "Update a period", Request: Period{}, Response: Period{}},
+}
@@ -2,9 +2,9 @@ package period
 
 import (
 	"database/sql"
-	"fmt"
 	"strings"
 
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/building"
 )
 
@@ -33,7 +33,7 @@ func (r *periodRepo) Create(period Period) (Period, error) {
 		return period, err
 	}
 	if exists {
-		return period, fmt.Errorf("building cannot have duplicate period with the same start and end date")
+		return period, apperrors.Conflict("building cannot have duplicate period with the same start and end date")
 	}
 
 	result, err := r.db.Exec("INSERT INTO periods (period_name, `start`, `end`, building_id, is_closed) VALUES (?, ?, ?, ?, ?)",
@@ -42,7 +42,7 @@ func (r *periodRepo) Create(period Period) (Period, error) {
 	if err != nil {
 		// Check if it's a duplicate key error
 		if strings.Contains(err.Error(), "Duplicate entry") || strings.Contains(err.Error(), "UNIQUE constraint") {
-			return period, fmt.Errorf("building cannot have duplicate period with the same start and end date")
+			return period, apperrors.Conflict("building cannot have duplicate period with the same start and end date")
 		}
 		return period, err
 	}
@@ -64,7 +64,7 @@ func (r *periodRepo) Update(period Period, id int) (Period, error) {
 		return period, err
 	}
 	if exists {
-		return period, fmt.Errorf("building cannot have duplicate period with the same start and end date")
+		return period, apperrors.Conflict("building cannot have duplicate period with the same start and end date")
 	}
 
 	_, err = r.db.Exec("UPDATE periods SET period_name=?, `start`=?, `end`=?, building_id=?, is_closed=?, updated_at=NOW() WHERE id=?",
@@ -73,7 +73,7 @@ func (r *periodRepo) Update(period Period, id int) (Period, error) {
 	if err != nil {
 		// Check if it's a duplicate key error
 		if strings.Contains(err.Error(), "Duplicate entry") || strings.Contains(err.Error(), "UNIQUE constraint") {
-			return period, fmt.Errorf("building cannot have duplicate period with the same start and end date")
+			return period, apperrors.Conflict("building cannot have duplicate period with the same start and end date")
 		}
 		return period, err
 	}
@@ -99,7 +99,7 @@ func (r *periodRepo) GetByID(id int) (Period, building.Building, error) {
 			&b.ID, &b.Name, &b.CreatedAt, &b.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return period, b, fmt.Errorf("id does not exist")
+		return period, b, apperrors.NotFound("period")
 	}
 
 	return period, b, err
@@ -0,0 +1,149 @@
+// Package portal is the tenant portal: an API where a tenant sees their own leases, lease
+// files, open invoices, payments, statement and meter readings, and pays invoices online when
+// the building has a payment gateway set up. Nothing else can be changed from the portal.
+//
+// The portal is served on its own address, separate from the staff API, and shares none of
+// its routes. Tenants sign in with a one-time code sent by SMS to the phone number on their
+// people record, which opens a session for that record only; every query is scoped to the
+// session's person and building rather than to ids taken from the request.
+package portal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// codeDigits is the length of a sign-in code
+const codeDigits = 6
+
+// minPhoneDigits rejects numbers too short to identify anyone
+const minPhoneDigits = 7
+
+// Session is a signed-in tenant
+type Session struct {
+	ID         int    `json:"-"`
+	PeopleID   int    `json:"people_id"`
+	BuildingID int    `json:"building_id"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+// Account is a people record a phone number belongs to
+type Account struct {
+	PeopleID     int    `json:"people_id"`
+	Name         string `json:"name"`
+	BuildingID   int    `json:"building_id"`
+	BuildingName string `json:"building_name"`
+	Phone        string `json:"-"`
+}
+
+// Profile is the signed-in tenant's own record
+type Profile struct {
+	PeopleID     int    `json:"people_id"`
+	Name         string `json:"name"`
+	Phone        string `json:"phone"`
+	Email        string `json:"email"`
+	BuildingID   int    `json:"building_id"`
+	BuildingName string `json:"building_name"`
+}
+
+type Lease struct {
+	ID            int     `json:"id"`
+	UnitID        int     `json:"unit_id"`
+	UnitName      string  `json:"unit_name"`
+	StartDate     string  `json:"start_date"`
+	EndDate       *string `json:"end_date"`
+	RentAmount    float64 `json:"rent_amount"`
+	DepositAmount float64 `json:"deposit_amount"`
+	ServiceAmount float64 `json:"service_amount"`
+	LeaseTerms    string  `json:"lease_terms"`
+	Status        string  `json:"status"`
+}
+
+// LeaseFile describes a lease document without where it is stored
+type LeaseFile struct {
+	ID           int    `json:"id"`
+	LeaseID      int    `json:"lease_id"`
+	OriginalName string `json:"original_name"`
+	FileType     string `json:"file_type"`
+	FileSize     int64  `json:"file_size"`
+	CreatedAt    string `json:"created_at"`
+	path         string
+}
+
+type Invoice struct {
+	ID          int     `json:"id"`
+	InvoiceNo   string  `json:"invoice_no"`
+	SalesDate   string  `json:"sales_date"`
+	DueDate     string  `json:"due_date"`
+	UnitName    *string `json:"unit_name"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	Settled     float64 `json:"settled"` // Payments, applied credits and discounts
+	Balance     float64 `json:"balance"`
+}
+
+type Payment struct {
+	ID        int     `json:"id"`
+	Date      string  `json:"date"`
+	Reference string  `json:"reference"`
+	Amount    float64 `json:"amount"`
+	InvoiceID int     `json:"invoice_id"`
+	InvoiceNo string  `json:"invoice_no"`
+}
+
+type Reading struct {
+	ID            int      `json:"id"`
+	LeaseID       *int     `json:"lease_id"`
+	UnitName      string   `json:"unit_name"`
+	ItemName      string   `json:"item_name"`
+	ReadingDate   string   `json:"reading_date"`
+	ReadingMonth  *string  `json:"reading_month"`
+	ReadingYear   *string  `json:"reading_year"`
+	PreviousValue *float64 `json:"previous_value"`
+	CurrentValue  *float64 `json:"current_value"`
+	UnitPrice     *float64 `json:"unit_price"`
+	TotalAmount   *float64 `json:"total_amount"`
+}
+
+// code is a sign-in code sent to a phone number
+type code struct {
+	ID        int
+	Phone     string
+	CodeHash  string
+	Attempts  int
+	ExpiresAt string
+	UsedAt    *string
+	CreatedAt string
+}
+
+// hashSecret returns the hex SHA-256 of a code or token; only hashes are stored
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < codeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n), nil
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,50 @@
+package portal
+
+import (
+	"strings"
+
+	"github.com/mysecodgit/go_accounting/src/people"
+)
+
+type CodeRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+type VerifyRequest struct {
+	Phone    string `json:"phone" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+	PeopleID *int   `json:"people_id"` // Required when the phone number belongs to several people records
+}
+
+// PayRequest starts an online payment of one of the tenant's invoices
+type PayRequest struct {
+	Amount *float64 `json:"amount"` // Defaults to the invoice's balance; less pays part of it
+}
+
+// SessionResponse carries the bearer token to send as "Authorization: Bearer <token>"
+type SessionResponse struct {
+	Token     string  `json:"token"`
+	ExpiresAt string  `json:"expires_at"`
+	Profile   Profile `json:"profile"`
+}
+
+func (r CodeRequest) Validate() map[string]string {
+	if len(people.NormalizePhone(r.Phone)) < minPhoneDigits {
+		return map[string]string{"phone": "Phone must have at least 7 digits"}
+	}
+	return nil
+}
+
+func (r VerifyRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	if len(people.NormalizePhone(r.Phone)) < minPhoneDigits {
+		errors["phone"] = "Phone must have at least 7 digits"
+	}
+	if code := strings.TrimSpace(r.Code); len(code) != codeDigits || strings.Trim(code, "0123456789") != "" {
+		errors["code"] = "Code must be the 6 digits sent to the phone"
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
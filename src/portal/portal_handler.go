@@ -0,0 +1,312 @@
+package portal
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+)
+
+// sessionKey is where RequireSession stores the signed-in tenant's session in the gin context
+const sessionKey = "portal_session"
+
+type PortalHandler struct {
+	service *PortalService
+}
+
+func NewPortalHandler(service *PortalService) *PortalHandler {
+	return &PortalHandler{service: service}
+}
+
+// RequireSession rejects requests without a valid "Authorization: Bearer <token>" header
+// and stores the session for the handlers that follow
+func (h *PortalHandler) RequireSession(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		apperrors.Respond(c, apperrors.New(apperrors.CodeUnauthorized, "Sign in to the portal, the Authorization header is missing"))
+		c.Abort()
+		return
+	}
+
+	session, err := h.service.WithLogger(logging.FromGin(c)).Authenticate(strings.TrimSpace(token))
+	if err != nil {
+		apperrors.Respond(c, err)
+		c.Abort()
+		return
+	}
+
+	c.Set(sessionKey, session)
+	c.Next()
+}
+
+// POST /portal/auth/code
+func (h *PortalHandler) RequestCode(c *gin.Context) {
+	var req CodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	validationErr, err := h.service.WithLogger(logging.FromGin(c)).RequestCode(req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the phone number belongs to a tenant, a sign-in code has been sent to it"})
+}
+
+// POST /portal/auth/verify
+func (h *PortalHandler) Verify(c *gin.Context) {
+	var req VerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	session, validationErr, err := h.service.WithLogger(logging.FromGin(c)).Verify(req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// POST /portal/auth/logout
+func (h *PortalHandler) SignOut(c *gin.Context) {
+	if err := h.service.WithLogger(logging.FromGin(c)).SignOut(currentSession(c)); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Signed out successfully"})
+}
+
+// GET /portal/me
+func (h *PortalHandler) GetProfile(c *gin.Context) {
+	profile, err := h.service.Profile(currentSession(c))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// GET /portal/leases
+func (h *PortalHandler) GetLeases(c *gin.Context) {
+	leases, err := h.service.Leases(currentSession(c))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, leases)
+}
+
+// GET /portal/leases/:leaseId/files
+func (h *PortalHandler) GetLeaseFiles(c *gin.Context) {
+	leaseID, err := strconv.Atoi(c.Param("leaseId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Lease ID"))
+		return
+	}
+
+	files, err := h.service.LeaseFiles(currentSession(c), leaseID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, files)
+}
+
+// GET /portal/leases/:leaseId/files/:fileId
+func (h *PortalHandler) DownloadLeaseFile(c *gin.Context) {
+	leaseID, err := strconv.Atoi(c.Param("leaseId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Lease ID"))
+		return
+	}
+	fileID, err := strconv.Atoi(c.Param("fileId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid file ID"))
+		return
+	}
+
+	file, path, err := h.service.LeaseFile(currentSession(c), leaseID, fileID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		apperrors.Respond(c, apperrors.New(apperrors.CodeNotFound, "File not found on disk"))
+		return
+	}
+
+	c.FileAttachment(path, file.OriginalName)
+}
+
+// GET /portal/invoices
+func (h *PortalHandler) GetInvoices(c *gin.Context) {
+	status := c.DefaultQuery("status", "open")
+	if status != "open" && status != "all" {
+		apperrors.Respond(c, apperrors.BadRequest("Status must be open or all"))
+		return
+	}
+
+	invoices, err := h.service.Invoices(currentSession(c), status == "open")
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
+// GET /portal/invoices/:invoiceId/pdf
+func (h *PortalHandler) DownloadInvoice(c *gin.Context) {
+	invoiceID, err := strconv.Atoi(c.Param("invoiceId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Invoice ID"))
+		return
+	}
+
+	filename, content, err := h.service.WithLogger(logging.FromGin(c)).InvoicePDF(currentSession(c), invoiceID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	sendPDF(c, filename, content)
+}
+
+// GET /portal/payments
+// POST /api/portal/invoices/:invoiceId/pay
+func (h *PortalHandler) PayInvoice(c *gin.Context) {
+	invoiceID, err := strconv.Atoi(c.Param("invoiceId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Invoice ID"))
+		return
+	}
+
+	var req PayRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apperrors.Respond(c, apperrors.FromBinding(err))
+			return
+		}
+	}
+
+	intent, validationErr, err := h.service.WithLogger(logging.FromGin(c)).PayInvoice(currentSession(c), invoiceID, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, intent)
+}
+
+// GET /api/portal/payment-intents
+func (h *PortalHandler) GetPaymentIntents(c *gin.Context) {
+	intents, err := h.service.PaymentIntents(currentSession(c))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, intents)
+}
+
+func (h *PortalHandler) GetPayments(c *gin.Context) {
+	payments, err := h.service.Payments(currentSession(c))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// GET /portal/payments/:paymentId/pdf
+func (h *PortalHandler) DownloadPayment(c *gin.Context) {
+	paymentID, err := strconv.Atoi(c.Param("paymentId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Payment ID"))
+		return
+	}
+
+	filename, content, err := h.service.WithLogger(logging.FromGin(c)).PaymentPDF(currentSession(c), paymentID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	sendPDF(c, filename, content)
+}
+
+// GET /portal/statement
+func (h *PortalHandler) GetStatement(c *gin.Context) {
+	statement, err := h.service.Statement(currentSession(c), c.Query("start_date"), c.Query("end_date"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// GET /portal/statement/pdf
+func (h *PortalHandler) DownloadStatement(c *gin.Context) {
+	filename, content, err := h.service.WithLogger(logging.FromGin(c)).StatementPDF(currentSession(c), c.Query("start_date"), c.Query("end_date"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	sendPDF(c, filename, content)
+}
+
+// GET /portal/readings
+func (h *PortalHandler) GetReadings(c *gin.Context) {
+	readings, err := h.service.Readings(currentSession(c))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, readings)
+}
+
+// currentSession returns the session stored by RequireSession
+func currentSession(c *gin.Context) Session {
+	return c.MustGet(sessionKey).(Session)
+}
+
+// sendPDF sends a rendered document as an attachment, or inline with ?inline=true
+func sendPDF(c *gin.Context, filename string, content []byte) {
+	disposition := "attachment"
+	if c.Query("inline") == "true" {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, filename))
+	c.Data(http.StatusOK, "application/pdf", content)
+}
@@ -0,0 +1,73 @@
+package portal
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/payment_gateway"
+	"github.com/mysecodgit/go_accounting/src/reports"
+)
+
+var (
+	inlineParam    = openapi.Param{Name: "inline", Type: "boolean", Description: "Send the PDF for display in the browser instead of as an attachment"}
+	statementQuery = []openapi.Param{
+		{Name: "start_date", Format: "date", Description: "Defaults to January 1 of the current year"},
+		{Name: "end_date", Format: "date", Description: "Defaults to today"},
+	}
+)
+
+var OpenAPI = openapi.Handlers{
+	"PortalHandler.RequestCode": {
+		Summary:     "Send a sign-in code by SMS",
+		Description: "Texts a 6-digit code to the phone number when it is on a people record. The answer is the same for unknown numbers and for requests within the resend interval.",
+		Request:     CodeRequest{},
+	},
+	"PortalHandler.Verify": {
+		Summary:     "Sign in with a code",
+		Description: "Returns a session token for the Authorization header. When the number belongs to several people records and people_id is missing, the error lists them in details.accounts and the code stays valid.",
+		Request:     VerifyRequest{},
+		Response:    SessionResponse{},
+	},
+	"PortalHandler.SignOut":    {Summary: "End the session", Bearer: true},
+	"PortalHandler.GetProfile": {Summary: "Get the signed-in tenant", Bearer: true, Response: Profile{}},
+	"PortalHandler.GetLeases":  {Summary: "List the tenant's leases", Bearer: true, Response: []Lease{}},
+	"PortalHandler.GetLeaseFiles": {
+		Summary:  "List the files of one of the tenant's leases",
+		Bearer:   true,
+		Response: []LeaseFile{},
+	},
+	"PortalHandler.DownloadLeaseFile": {Summary: "Download a file of one of the tenant's leases", Bearer: true, Download: true},
+	"PortalHandler.GetInvoices": {
+		Summary:  "List the tenant's invoices",
+		Bearer:   true,
+		Query:    []openapi.Param{{Name: "status", Enum: []string{"open", "all"}, Description: "open (default) lists invoices with a balance left"}},
+		Response: []Invoice{},
+	},
+	"PortalHandler.DownloadInvoice": {Summary: "Download one of the tenant's invoices as PDF", Bearer: true, Download: true, Query: []openapi.Param{inlineParam}},
+	"PortalHandler.PayInvoice": {
+		Summary:     "Pay one of the tenant's invoices online",
+		Description: "Starts a payment with the building's payment gateway and returns the checkout URL to send the tenant to. The amount defaults to the invoice's balance.",
+		Bearer:      true,
+		Request:     PayRequest{},
+		Response:    payment_gateway.Intent{},
+	},
+	"PortalHandler.GetPaymentIntents": {Summary: "List the tenant's online payments", Bearer: true, Response: []payment_gateway.Intent{}},
+	"PortalHandler.GetPayments":       {Summary: "List the tenant's payments", Bearer: true, Response: []Payment{}},
+	"PortalHandler.DownloadPayment":   {Summary: "Download a receipt for one of the tenant's payments as PDF", Bearer: true, Download: true, Query: []openapi.Param{inlineParam}},
+	"PortalHandler.GetStatement": {
+		Summary:  "Get the tenant's statement of account",
+		Bearer:   true,
+		Query:    statementQuery,
+		Response: reports.CustomerStatement{},
+	},
+	"PortalHandler.DownloadStatement": {
+		Summary:  "Download the tenant's statement of account as PDF",
+		Bearer:   true,
+		Download: true,
+		Query:    append([]openapi.Param{inlineParam}, statementQuery...),
+	},
+	"PortalHandler.GetReadings": {
+		Summary:     "List the tenant's meter readings",
+		Description: "Readings of the tenant's leases, and readings of their units without a lease taken during one of their leases.",
+		Bearer:      true,
+		Response:    []Reading{},
+	},
+}
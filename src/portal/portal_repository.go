@@ -0,0 +1,283 @@
+package portal
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PortalRepository reads only the signed-in person's records: every query takes the people id
+// of the session
+type PortalRepository interface {
+	FindAccounts(phone string) ([]Account, error)
+	LatestCode(phone string) (code, error)
+	CreateCode(c code) error
+	ReserveCodeAttempt(id int, limit int) (bool, error)
+	UseCode(id int, now time.Time) (bool, error)
+	CreateSession(tokenHash string, session Session, now time.Time) error
+	GetSession(tokenHash string, now time.Time) (Session, error)
+	RevokeSession(id int, now time.Time) error
+	GetProfile(peopleID int) (Profile, error)
+	ListLeases(peopleID int) ([]Lease, error)
+	ListLeaseFiles(peopleID int, leaseID int) ([]LeaseFile, error)
+	GetLeaseFile(peopleID int, leaseID int, fileID int) (LeaseFile, error)
+	ListInvoices(peopleID int, openOnly bool) ([]Invoice, error)
+	OwnsInvoice(peopleID int, invoiceID int) (bool, error)
+	ListPayments(peopleID int) ([]Payment, error)
+	OwnsPayment(peopleID int, paymentID int) (bool, error)
+	ListReadings(peopleID int) ([]Reading, error)
+}
+
+type portalRepo struct {
+	db *sql.DB
+}
+
+func NewPortalRepository(db *sql.DB) PortalRepository {
+	return &portalRepo{db: db}
+}
+
+// FindAccounts returns the people records whose phone number has the given digits
+func (r *portalRepo) FindAccounts(phone string) ([]Account, error) {
+	rows, err := r.db.Query("SELECT p.id, p.name, p.building_id, b.name, p.phone FROM people p INNER JOIN buildings b ON b.id = p.building_id WHERE p.phone_normalized = ? ORDER BY p.id", phone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []Account{}
+	for rows.Next() {
+		var account Account
+		if err := rows.Scan(&account.PeopleID, &account.Name, &account.BuildingID, &account.BuildingName, &account.Phone); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *portalRepo) LatestCode(phone string) (code, error) {
+	var c code
+	err := r.db.QueryRow(
+		"SELECT id, phone, code_hash, attempts, expires_at, used_at, created_at FROM portal_codes WHERE phone = ? ORDER BY id DESC LIMIT 1", phone,
+	).Scan(&c.ID, &c.Phone, &c.CodeHash, &c.Attempts, &c.ExpiresAt, &c.UsedAt, &c.CreatedAt)
+	return c, err
+}
+
+func (r *portalRepo) CreateCode(c code) error {
+	_, err := r.db.Exec(
+		"INSERT INTO portal_codes (phone, code_hash, attempts, expires_at, created_at) VALUES (?, ?, 0, ?, ?)",
+		c.Phone, c.CodeHash, c.ExpiresAt, c.CreatedAt,
+	)
+	return err
+}
+
+// ReserveCodeAttempt counts an attempt at an unused code; false when the code has no attempts
+// left. The count is checked and raised in one statement so concurrent attempts can't all
+// pass the limit.
+func (r *portalRepo) ReserveCodeAttempt(id int, limit int) (bool, error) {
+	result, err := r.db.Exec("UPDATE portal_codes SET attempts = attempts + 1 WHERE id = ? AND attempts < ? AND used_at IS NULL", id, limit)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// UseCode marks a code used; false when it was used already, e.g. by a concurrent sign-in
+func (r *portalRepo) UseCode(id int, now time.Time) (bool, error) {
+	result, err := r.db.Exec("UPDATE portal_codes SET used_at = ? WHERE id = ? AND used_at IS NULL", now.UTC().Format(timeLayout), id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (r *portalRepo) CreateSession(tokenHash string, session Session, now time.Time) error {
+	_, err := r.db.Exec(
+		"INSERT INTO portal_sessions (token_hash, people_id, building_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
+		tokenHash, session.PeopleID, session.BuildingID, session.ExpiresAt, now.UTC().Format(timeLayout),
+	)
+	return err
+}
+
+// GetSession returns the unexpired, unrevoked session of a token
+func (r *portalRepo) GetSession(tokenHash string, now time.Time) (Session, error) {
+	var session Session
+	err := r.db.QueryRow(
+		"SELECT id, people_id, building_id, expires_at FROM portal_sessions WHERE token_hash = ? AND revoked_at IS NULL AND expires_at > ?",
+		tokenHash, now.UTC().Format(timeLayout),
+	).Scan(&session.ID, &session.PeopleID, &session.BuildingID, &session.ExpiresAt)
+	return session, err
+}
+
+func (r *portalRepo) RevokeSession(id int, now time.Time) error {
+	_, err := r.db.Exec("UPDATE portal_sessions SET revoked_at = ? WHERE id = ?", now.UTC().Format(timeLayout), id)
+	return err
+}
+
+func (r *portalRepo) GetProfile(peopleID int) (Profile, error) {
+	var profile Profile
+	err := r.db.QueryRow(
+		"SELECT p.id, p.name, p.phone, p.email, p.building_id, b.name FROM people p INNER JOIN buildings b ON b.id = p.building_id WHERE p.id = ?", peopleID,
+	).Scan(&profile.PeopleID, &profile.Name, &profile.Phone, &profile.Email, &profile.BuildingID, &profile.BuildingName)
+	return profile, err
+}
+
+func (r *portalRepo) ListLeases(peopleID int) ([]Lease, error) {
+	rows, err := r.db.Query(
+		"SELECT l.id, l.unit_id, u.name, l.start_date, l.end_date, l.rent_amount, l.deposit_amount, l.service_amount, l.lease_terms, l.status"+
+			" FROM leases l INNER JOIN units u ON u.id = l.unit_id WHERE l.people_id = ? ORDER BY l.start_date DESC, l.id DESC",
+		peopleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leases := []Lease{}
+	for rows.Next() {
+		var l Lease
+		if err := rows.Scan(&l.ID, &l.UnitID, &l.UnitName, &l.StartDate, &l.EndDate, &l.RentAmount, &l.DepositAmount, &l.ServiceAmount, &l.LeaseTerms, &l.Status); err != nil {
+			return nil, err
+		}
+		leases = append(leases, l)
+	}
+	return leases, rows.Err()
+}
+
+const leaseFileQuery = "SELECT f.id, f.lease_id, f.original_name, f.file_type, f.file_size, f.created_at, f.file_path" +
+	" FROM lease_files f INNER JOIN leases l ON l.id = f.lease_id WHERE l.people_id = ? AND f.lease_id = ?"
+
+func (r *portalRepo) ListLeaseFiles(peopleID int, leaseID int) ([]LeaseFile, error) {
+	rows, err := r.db.Query(leaseFileQuery+" ORDER BY f.id", peopleID, leaseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := []LeaseFile{}
+	for rows.Next() {
+		file, err := scanLeaseFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+func (r *portalRepo) GetLeaseFile(peopleID int, leaseID int, fileID int) (LeaseFile, error) {
+	return scanLeaseFile(r.db.QueryRow(leaseFileQuery+" AND f.id = ?", peopleID, leaseID, fileID))
+}
+
+// ListInvoices returns the person's active invoices with what is left to pay, newest first
+func (r *portalRepo) ListInvoices(peopleID int, openOnly bool) ([]Invoice, error) {
+	rows, err := r.db.Query(`
+		SELECT i.id, i.invoice_no, DATE(i.sales_date), DATE(i.due_date), u.name, i.description, i.amount,
+			COALESCE((SELECT SUM(ip.amount) FROM invoice_payments ip WHERE ip.invoice_id = i.id AND ip.status = '1'), 0)
+			+ COALESCE((SELECT SUM(c.amount) FROM invoice_applied_credits c WHERE c.invoice_id = i.id AND c.status = '1'), 0)
+			+ COALESCE((SELECT SUM(d.amount) FROM invoice_applied_discounts d WHERE d.invoice_id = i.id AND d.status = '1'), 0)
+		FROM invoices i
+		LEFT JOIN units u ON u.id = i.unit_id
+		WHERE i.people_id = ? AND i.status = '1'
+		ORDER BY i.sales_date DESC, i.id DESC`, peopleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := []Invoice{}
+	for rows.Next() {
+		var i Invoice
+		if err := rows.Scan(&i.ID, &i.InvoiceNo, &i.SalesDate, &i.DueDate, &i.UnitName, &i.Description, &i.Amount, &i.Settled); err != nil {
+			return nil, err
+		}
+		i.Balance = i.Amount - i.Settled
+		if openOnly && i.Balance < 0.005 {
+			continue
+		}
+		invoices = append(invoices, i)
+	}
+	return invoices, rows.Err()
+}
+
+func (r *portalRepo) OwnsInvoice(peopleID int, invoiceID int) (bool, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM invoices WHERE id = ? AND people_id = ? AND status = '1'", invoiceID, peopleID).Scan(&count)
+	return count > 0, err
+}
+
+func (r *portalRepo) ListPayments(peopleID int) ([]Payment, error) {
+	rows, err := r.db.Query(
+		"SELECT ip.id, DATE(ip.date), ip.reference, ip.amount, i.id, i.invoice_no FROM invoice_payments ip INNER JOIN invoices i ON i.id = ip.invoice_id"+
+			" WHERE i.people_id = ? AND ip.status = '1' ORDER BY ip.date DESC, ip.id DESC",
+		peopleID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []Payment{}
+	for rows.Next() {
+		var p Payment
+		if err := rows.Scan(&p.ID, &p.Date, &p.Reference, &p.Amount, &p.InvoiceID, &p.InvoiceNo); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+func (r *portalRepo) OwnsPayment(peopleID int, paymentID int) (bool, error) {
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM invoice_payments ip INNER JOIN invoices i ON i.id = ip.invoice_id WHERE ip.id = ? AND i.people_id = ? AND ip.status = '1'",
+		paymentID, peopleID,
+	).Scan(&count)
+	return count > 0, err
+}
+
+// ListReadings returns the readings of the person's leases, and readings of their units not
+// tied to a lease that were taken while they held the unit
+func (r *portalRepo) ListReadings(peopleID int) ([]Reading, error) {
+	rows, err := r.db.Query(`
+		SELECT rd.id, rd.lease_id, u.name, it.name, DATE(rd.reading_date), rd.reading_month, rd.reading_year,
+			rd.previous_value, rd.current_value, rd.unit_price, rd.total_amount
+		FROM readings rd
+		INNER JOIN units u ON u.id = rd.unit_id
+		INNER JOIN items it ON it.id = rd.item_id
+		WHERE rd.status = '1' AND (
+			rd.lease_id IN (SELECT l.id FROM leases l WHERE l.people_id = ?)
+			OR (rd.lease_id IS NULL AND EXISTS (
+				SELECT 1 FROM leases l WHERE l.people_id = ? AND l.unit_id = rd.unit_id
+					AND DATE(l.start_date) <= DATE(rd.reading_date) AND (l.end_date IS NULL OR DATE(l.end_date) >= DATE(rd.reading_date))
+			))
+		)
+		ORDER BY rd.reading_date DESC, rd.id DESC`, peopleID, peopleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	readings := []Reading{}
+	for rows.Next() {
+		var rd Reading
+		if err := rows.Scan(&rd.ID, &rd.LeaseID, &rd.UnitName, &rd.ItemName, &rd.ReadingDate, &rd.ReadingMonth, &rd.ReadingYear,
+			&rd.PreviousValue, &rd.CurrentValue, &rd.UnitPrice, &rd.TotalAmount); err != nil {
+			return nil, err
+		}
+		readings = append(readings, rd)
+	}
+	return readings, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLeaseFile(row scanner) (LeaseFile, error) {
+	var f LeaseFile
+	err := row.Scan(&f.ID, &f.LeaseID, &f.OriginalName, &f.FileType, &f.FileSize, &f.CreatedAt, &f.path)
+	return f, err
+}
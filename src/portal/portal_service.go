@@ -0,0 +1,345 @@
+package portal
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/config"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/documents"
+	"github.com/mysecodgit/go_accounting/src/notifications"
+	"github.com/mysecodgit/go_accounting/src/payment_gateway"
+	"github.com/mysecodgit/go_accounting/src/people"
+	"github.com/mysecodgit/go_accounting/src/reports"
+)
+
+// sendTimeout bounds sending a sign-in code
+const sendTimeout = 30 * time.Second
+
+// errInvalidCode is the one answer to every failed sign-in, so that it reveals neither
+// whether the phone number is known nor what was wrong with the code
+var errInvalidCode = apperrors.Rule("The code is invalid or has expired, ask for a new one")
+
+type PortalService struct {
+	repo            PortalRepository
+	reportsService  *reports.ReportsService
+	documentService *documents.DocumentService
+	paymentGateway  *payment_gateway.PaymentGatewayService
+	sms             notifications.Transport
+	cfg             config.PortalConfig
+	logger          *slog.Logger
+}
+
+func NewPortalService(repo PortalRepository, reportsService *reports.ReportsService, documentService *documents.DocumentService, paymentGateway *payment_gateway.PaymentGatewayService, sms notifications.Transport, cfg config.PortalConfig, logger *slog.Logger) *PortalService {
+	return &PortalService{
+		repo:            repo,
+		reportsService:  reportsService,
+		documentService: documentService,
+		paymentGateway:  paymentGateway,
+		sms:             sms,
+		cfg:             cfg,
+		logger:          logger,
+	}
+}
+
+// WithLogger returns a copy of the service that logs to logger, e.g. the request's logger
+func (s *PortalService) WithLogger(logger *slog.Logger) *PortalService {
+	copy := *s
+	copy.logger = logger
+	return &copy
+}
+
+// RequestCode texts a sign-in code to the phone number when it belongs to someone. Unknown
+// numbers and requests within the resend interval get the same answer without a code.
+func (s *PortalService) RequestCode(req CodeRequest) (map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return validationErrors, nil
+	}
+	phone := people.NormalizePhone(req.Phone)
+	now := time.Now()
+
+	accounts, err := s.repo.FindAccounts(phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up phone number: %w", err)
+	}
+	if len(accounts) == 0 {
+		s.logger.Info("portal code requested for unknown phone number")
+		return nil, nil
+	}
+
+	latest, err := s.repo.LatestCode(phone)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to load sign-in code: %w", err)
+	}
+	if err == nil {
+		if created, parseErr := time.Parse(timeLayout, latest.CreatedAt); parseErr == nil && now.UTC().Sub(created) < s.cfg.ResendIntervalDuration() {
+			s.logger.Info("portal code requested again too soon", "people_id", accounts[0].PeopleID)
+			return nil, nil
+		}
+	}
+
+	value, err := newCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate sign-in code: %w", err)
+	}
+	ttl := s.cfg.CodeTTLDuration()
+	err = s.repo.CreateCode(code{
+		Phone:     phone,
+		CodeHash:  hashSecret(value),
+		ExpiresAt: now.Add(ttl).UTC().Format(timeLayout),
+		CreatedAt: now.UTC().Format(timeLayout),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save sign-in code: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	err = s.sms.Send(ctx, notifications.Message{
+		Channel: notifications.ChannelSMS,
+		To:      accounts[0].Phone,
+		Body:    fmt.Sprintf("Your %s tenant portal code is %s. It expires in %s.", accounts[0].BuildingName, value, formatTTL(ttl)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send sign-in code: %w", err)
+	}
+
+	s.logger.Info("portal code sent", "people_id", accounts[0].PeopleID)
+	return nil, nil
+}
+
+// Verify exchanges a sign-in code for a session of one people record. When the number
+// belongs to several records and none was chosen, the code is kept and the records are
+// listed in the error's accounts detail to choose from.
+func (s *PortalService) Verify(req VerifyRequest) (*SessionResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+	phone := people.NormalizePhone(req.Phone)
+	now := time.Now()
+
+	latest, err := s.repo.LatestCode(phone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, errInvalidCode
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load sign-in code: %w", err)
+	}
+	expires, err := time.Parse(timeLayout, latest.ExpiresAt)
+	if err != nil || latest.UsedAt != nil || !now.UTC().Before(expires) {
+		return nil, nil, errInvalidCode
+	}
+	// Every attempt is counted before the code is compared, so parallel guesses share the limit
+	reserved, err := s.repo.ReserveCodeAttempt(latest.ID, s.cfg.CodeAttempts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record sign-in attempt: %w", err)
+	}
+	if !reserved {
+		return nil, nil, errInvalidCode
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(strings.TrimSpace(req.Code))), []byte(latest.CodeHash)) != 1 {
+		s.logger.Warn("portal sign-in with a wrong code", "attempts", latest.Attempts+1)
+		return nil, nil, errInvalidCode
+	}
+
+	accounts, err := s.repo.FindAccounts(phone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to look up phone number: %w", err)
+	}
+	var account *Account
+	for i := range accounts {
+		if (req.PeopleID == nil && len(accounts) == 1) || (req.PeopleID != nil && accounts[i].PeopleID == *req.PeopleID) {
+			account = &accounts[i]
+		}
+	}
+	if account == nil {
+		if req.PeopleID == nil && len(accounts) > 1 {
+			return nil, nil, apperrors.Rule("The phone number belongs to several accounts, choose one with people_id").WithDetail("accounts", accounts)
+		}
+		return nil, nil, errInvalidCode
+	}
+
+	used, err := s.repo.UseCode(latest.ID, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to use sign-in code: %w", err)
+	}
+	if !used {
+		return nil, nil, errInvalidCode
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+	session := Session{
+		PeopleID:   account.PeopleID,
+		BuildingID: account.BuildingID,
+		ExpiresAt:  now.Add(s.cfg.SessionTTLDuration()).UTC().Format(timeLayout),
+	}
+	if err := s.repo.CreateSession(hashSecret(token), session, now); err != nil {
+		return nil, nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	profile, err := s.Profile(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.logger.Info("tenant signed in to the portal", "people_id", account.PeopleID)
+	return &SessionResponse{Token: token, ExpiresAt: session.ExpiresAt, Profile: *profile}, nil, nil
+}
+
+// Authenticate returns the session of a bearer token
+func (s *PortalService) Authenticate(token string) (Session, error) {
+	session, err := s.repo.GetSession(hashSecret(token), time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return Session{}, apperrors.New(apperrors.CodeUnauthorized, "Sign in again, the session is invalid or has expired")
+	}
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to load session: %w", err)
+	}
+	return session, nil
+}
+
+// SignOut ends the session
+func (s *PortalService) SignOut(session Session) error {
+	if err := s.repo.RevokeSession(session.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+	s.logger.Info("tenant signed out of the portal", "people_id", session.PeopleID)
+	return nil
+}
+
+func (s *PortalService) Profile(session Session) (*Profile, error) {
+	profile, err := s.repo.GetProfile(session.PeopleID)
+	if err != nil {
+		return nil, apperrors.Lookup("person", err)
+	}
+	return &profile, nil
+}
+
+func (s *PortalService) Leases(session Session) ([]Lease, error) {
+	leases, err := s.repo.ListLeases(session.PeopleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load leases: %w", err)
+	}
+	return leases, nil
+}
+
+func (s *PortalService) LeaseFiles(session Session, leaseID int) ([]LeaseFile, error) {
+	files, err := s.repo.ListLeaseFiles(session.PeopleID, leaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lease files: %w", err)
+	}
+	return files, nil
+}
+
+// LeaseFile returns a file of one of the person's leases with its path on disk
+func (s *PortalService) LeaseFile(session Session, leaseID int, fileID int) (LeaseFile, string, error) {
+	file, err := s.repo.GetLeaseFile(session.PeopleID, leaseID, fileID)
+	if err != nil {
+		return LeaseFile{}, "", apperrors.Lookup("lease file", err)
+	}
+	return file, file.path, nil
+}
+
+// Invoices returns the person's invoices; only those with a balance left when openOnly
+func (s *PortalService) Invoices(session Session, openOnly bool) ([]Invoice, error) {
+	invoices, err := s.repo.ListInvoices(session.PeopleID, openOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+func (s *PortalService) InvoicePDF(session Session, invoiceID int) (string, []byte, error) {
+	owns, err := s.repo.OwnsInvoice(session.PeopleID, invoiceID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load invoice: %w", err)
+	}
+	if !owns {
+		return "", nil, apperrors.NotFound("invoice")
+	}
+	return s.documentService.WithLogger(s.logger).InvoicePDF(session.BuildingID, invoiceID)
+}
+
+// PayInvoice starts an online payment of one of the person's invoices and returns the
+// checkout to send them to
+func (s *PortalService) PayInvoice(session Session, invoiceID int, req PayRequest) (*payment_gateway.Intent, map[string]string, error) {
+	owns, err := s.repo.OwnsInvoice(session.PeopleID, invoiceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load invoice: %w", err)
+	}
+	if !owns {
+		return nil, nil, apperrors.NotFound("invoice")
+	}
+	return s.paymentGateway.WithLogger(s.logger).CreateIntent(session.BuildingID, payment_gateway.CreateIntentRequest{InvoiceID: invoiceID, Amount: req.Amount}, payment_gateway.SourcePortal, nil)
+}
+
+// PaymentIntents returns the person's online payments, newest first
+func (s *PortalService) PaymentIntents(session Session) ([]payment_gateway.Intent, error) {
+	return s.paymentGateway.ListPersonIntents(session.PeopleID)
+}
+
+func (s *PortalService) Payments(session Session) ([]Payment, error) {
+	payments, err := s.repo.ListPayments(session.PeopleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payments: %w", err)
+	}
+	return payments, nil
+}
+
+func (s *PortalService) PaymentPDF(session Session, paymentID int) (string, []byte, error) {
+	owns, err := s.repo.OwnsPayment(session.PeopleID, paymentID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load payment: %w", err)
+	}
+	if !owns {
+		return "", nil, apperrors.NotFound("payment")
+	}
+	return s.documentService.WithLogger(s.logger).PaymentPDF(session.BuildingID, paymentID)
+}
+
+// Statement returns the person's statement of account; the range defaults to the start of
+// the year until today
+func (s *PortalService) Statement(session Session, startDate string, endDate string) (*reports.CustomerStatement, error) {
+	return s.reportsService.GetCustomerStatement(s.statementRequest(session, startDate, endDate))
+}
+
+func (s *PortalService) StatementPDF(session Session, startDate string, endDate string) (string, []byte, error) {
+	return s.documentService.WithLogger(s.logger).StatementPDF(s.statementRequest(session, startDate, endDate))
+}
+
+func (s *PortalService) Readings(session Session) ([]Reading, error) {
+	readings, err := s.repo.ListReadings(session.PeopleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load readings: %w", err)
+	}
+	return readings, nil
+}
+
+func (s *PortalService) statementRequest(session Session, startDate string, endDate string) reports.CustomerStatementRequest {
+	today := time.Now()
+	if startDate == "" {
+		startDate = fmt.Sprintf("%d-01-01", today.Year())
+	}
+	if endDate == "" {
+		endDate = today.Format("2006-01-02")
+	}
+	peopleID := session.PeopleID
+	return reports.CustomerStatementRequest{BuildingID: session.BuildingID, PeopleID: &peopleID, StartDate: startDate, EndDate: endDate}
+}
+
+// formatTTL writes a code lifetime the way a text message would, e.g. "10 minutes"
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", int(ttl.Hours()))
+	}
+	return fmt.Sprintf("%d minutes", int(math.Ceil(ttl.Minutes())))
+}
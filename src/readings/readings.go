@@ -19,6 +19,7 @@ type Reading struct {
 	TotalAmount   *float64 `json:"total_amount"`
 	Notes         *string `json:"notes"`
 	Status        string  `json:"status"`
+	Version       int     `json:"version"`
 	CreatedAt     string  `json:"created_at"`
 	UpdatedAt     string  `json:"updated_at"`
 }
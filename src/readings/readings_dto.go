@@ -41,6 +41,9 @@ type UpdateReadingRequest struct {
 	TotalAmount   *float64 `json:"total_amount"`
 	Notes         *string  `json:"notes"`
 	Status        string   `json:"status"`
+
+	// Version from the If-Match header; the update fails if the document has moved on
+	ExpectedVersion int `json:"-"`
 }
 
 type ReadingResponse struct {
@@ -81,3 +84,8 @@ type BulkImportReadingsResponse struct {
 	FailedCount  int      `json:"failed_count"`
 	Errors       []string `json:"errors,omitempty"`
 }
+
+// LatestReadingResponse wraps the latest reading, which is null when the unit has none
+type LatestReadingResponse struct {
+	Reading *Reading `json:"reading"`
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type ReadingHandler struct {
@@ -20,20 +22,19 @@ func (h *ReadingHandler) GetReadings(c *gin.Context) {
 	buildingIDStr := c.Param("id")
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid building ID"))
 		return
 	}
 
-	// Get optional status filter
-	status := c.Query("status")
-	var statusPtr *string
-	if status != "" {
-		statusPtr = &status
+	params, validationErrors := readingListSpec.Parse(c.Request.URL.Query())
+	if validationErrors != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
+		return
 	}
 
-	readings, err := h.service.GetReadingsByBuildingID(buildingID, statusPtr)
+	readings, err := h.service.GetReadingsByBuildingID(buildingID, params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -44,13 +45,13 @@ func (h *ReadingHandler) GetReadings(c *gin.Context) {
 func (h *ReadingHandler) CreateReading(c *gin.Context) {
 	var req CreateReadingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, err := h.service.CreateReading(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -62,41 +63,50 @@ func (h *ReadingHandler) GetReadingByID(c *gin.Context) {
 	readingIDStr := c.Param("readingId")
 	readingID, err := strconv.Atoi(readingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid reading ID"))
 		return
 	}
 
 	response, err := h.service.GetReadingByID(readingID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Reading.Version)
 	c.JSON(http.StatusOK, response)
 }
 
 // PUT /buildings/:id/readings/:readingId
 func (h *ReadingHandler) UpdateReading(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req UpdateReadingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	readingIDStr := c.Param("readingId")
 	readingID, err := strconv.Atoi(readingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid reading ID"))
 		return
 	}
 	req.ID = readingID
 
+	req.ExpectedVersion = version
 	response, err := h.service.UpdateReading(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Reading.Version)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -105,13 +115,13 @@ func (h *ReadingHandler) GetReadingsByUnitID(c *gin.Context) {
 	unitIDStr := c.Param("unitId")
 	unitID, err := strconv.Atoi(unitIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unit ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid unit ID"))
 		return
 	}
 
 	readings, err := h.service.GetReadingsByUnitID(unitID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -123,13 +133,13 @@ func (h *ReadingHandler) DeleteReading(c *gin.Context) {
 	readingIDStr := c.Param("readingId")
 	readingID, err := strconv.Atoi(readingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid reading ID"))
 		return
 	}
 
 	err = h.service.DeleteReading(readingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -140,16 +150,17 @@ func (h *ReadingHandler) DeleteReading(c *gin.Context) {
 func (h *ReadingHandler) BulkImportReadings(c *gin.Context) {
 	var req BulkImportReadingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
 	response, err := h.service.BulkImportReadings(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":  err.Error(),
-			"result": response,
-		})
+		// Report which rows failed along with the error
+		if appErr, ok := apperrors.As(err); ok && response != nil {
+			appErr.WithDetail("result", response)
+		}
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -162,32 +173,27 @@ func (h *ReadingHandler) GetLatestReading(c *gin.Context) {
 	unitIDStr := c.Query("unit_id")
 
 	if itemIDStr == "" || unitIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "item_id and unit_id are required"})
+		apperrors.Respond(c, apperrors.BadRequest("item_id and unit_id are required"))
 		return
 	}
 
 	itemID, err := strconv.Atoi(itemIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid item ID"))
 		return
 	}
 
 	unitID, err := strconv.Atoi(unitIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unit ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid unit ID"))
 		return
 	}
 
 	reading, err := h.service.GetLatestReadingByItemAndUnit(itemID, unitID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if reading == nil {
-		c.JSON(http.StatusOK, gin.H{"reading": nil})
+		apperrors.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"reading": reading})
+	c.JSON(http.StatusOK, LatestReadingResponse{Reading: reading})
 }
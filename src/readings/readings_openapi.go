@@ -0,0 +1,24 @@
+package readings
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"ReadingHandler.GetReadings":         {Summary: "List meter readings", Response: pagination.Page[ReadingListItem]{}, List: &readingListSpec},
+	"ReadingHandler.GetReadingsByUnitID": {Summary: "List a unit's meter readings", Response: []ReadingListItem{}},
+	"ReadingHandler.GetLatestReading": {
+		Summary: "Get the latest reading of an item for a unit",
+		Query: []openapi.Param{
+			{Name: "item_id", Type: "integer", Format: "int32", Required: true},
+			{Name: "unit_id", Type: "integer", Format: "int32", Required: true},
+		},
+		Response: LatestReadingResponse{},
+	},
+	"ReadingHandler.GetReadingByID":     {Summary: "Get a meter reading", Response: ReadingResponse{}},
+	"ReadingHandler.CreateReading":      {Summary: "Record a meter reading", Request: CreateReadingRequest{}, Response: ReadingResponse{}},
+	"ReadingHandler.BulkImportReadings": {Summary: "Import meter readings", Request: BulkImportReadingsRequest{}, Response: BulkImportReadingsResponse{}},
+	"ReadingHandler.UpdateReading":      {Summary: "Update a meter reading", IfMatch: true, Request: UpdateReadingRequest{}, Response: ReadingResponse{}},
+	"ReadingHandler.DeleteReading":      {Summary: "Delete a meter reading", Response: openapi.Message{}},
+}
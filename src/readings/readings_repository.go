@@ -2,15 +2,19 @@ package readings
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type ReadingRepository interface {
 	Create(reading Reading) (Reading, error)
 	CreateWithTx(tx *sql.Tx, reading Reading) (Reading, error)
 	Update(reading Reading) (Reading, error)
+	UpdateWithTx(tx *sql.Tx, reading Reading) (Reading, error)
 	GetByID(id int) (Reading, error)
-	GetByBuildingID(buildingID int, status *string) ([]Reading, error)
+	List(buildingID int, params pagination.Params) ([]Reading, int, error)
 	GetByUnitID(unitID int) ([]Reading, error)
 	GetByLeaseID(leaseID int) ([]Reading, error)
 	GetLatestByItemAndUnit(itemID, unitID int) (*Reading, error)
@@ -39,10 +43,10 @@ func (r *readingRepo) Create(reading Reading) (Reading, error) {
 	reading.ID = int(id)
 
 	err = r.db.QueryRow(
-		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at FROM readings WHERE id = ?",
+		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at, version FROM readings WHERE id = ?",
 		reading.ID,
 	).Scan(
-		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt,
+		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt, &reading.Version,
 	)
 
 	return reading, err
@@ -62,17 +66,26 @@ func (r *readingRepo) CreateWithTx(tx *sql.Tx, reading Reading) (Reading, error)
 	reading.ID = int(id)
 
 	err = tx.QueryRow(
-		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at FROM readings WHERE id = ?",
+		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at, version FROM readings WHERE id = ?",
 		reading.ID,
 	).Scan(
-		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt,
+		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt, &reading.Version,
 	)
 
 	return reading, err
 }
 
 func (r *readingRepo) Update(reading Reading) (Reading, error) {
-	_, err := r.db.Exec(
+	return updateReading(r.db, reading)
+}
+
+// UpdateWithTx updates the reading in the caller's transaction
+func (r *readingRepo) UpdateWithTx(tx *sql.Tx, reading Reading) (Reading, error) {
+	return updateReading(tx, reading)
+}
+
+func updateReading(db versioning.Executor, reading Reading) (Reading, error) {
+	_, err := db.Exec(
 		"UPDATE readings SET item_id = ?, unit_id = ?, lease_id = ?, reading_month = ?, reading_year = ?, reading_date = ?, previous_value = ?, current_value = ?, unit_price = ?, total_amount = ?, notes = ?, status = ? WHERE id = ?",
 		reading.ItemID, reading.UnitID, reading.LeaseID, reading.ReadingMonth, reading.ReadingYear, reading.ReadingDate, reading.PreviousValue, reading.CurrentValue, reading.UnitPrice, reading.TotalAmount, reading.Notes, reading.Status, reading.ID,
 	)
@@ -81,11 +94,11 @@ func (r *readingRepo) Update(reading Reading) (Reading, error) {
 		return reading, err
 	}
 
-	err = r.db.QueryRow(
-		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at FROM readings WHERE id = ?",
+	err = db.QueryRow(
+		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at, version FROM readings WHERE id = ?",
 		reading.ID,
 	).Scan(
-		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt,
+		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt, &reading.Version,
 	)
 
 	return reading, err
@@ -94,34 +107,72 @@ func (r *readingRepo) Update(reading Reading) (Reading, error) {
 func (r *readingRepo) GetByID(id int) (Reading, error) {
 	var reading Reading
 	err := r.db.QueryRow(
-		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at FROM readings WHERE id = ?",
+		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at, version FROM readings WHERE id = ?",
 		id,
 	).Scan(
-		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt,
+		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt, &reading.Version,
 	)
 
 	if err == sql.ErrNoRows {
-		return reading, fmt.Errorf("reading not found")
+		return reading, apperrors.NotFound("reading")
 	}
 
 	return reading, err
 }
 
-func (r *readingRepo) GetByBuildingID(buildingID int, status *string) ([]Reading, error) {
-	// Get readings for units in this building
-	query := "SELECT r.id, r.item_id, r.unit_id, r.lease_id, r.reading_month, r.reading_year, r.reading_date, r.previous_value, r.current_value, r.unit_price, r.total_amount, r.notes, r.status, r.created_at, r.updated_at FROM readings r INNER JOIN units u ON r.unit_id = u.id WHERE u.building_id = ?"
-	args := []interface{}{buildingID}
+var readingListSpec = pagination.Spec{
+	IDColumn: "r.id",
+	Sorts: map[string]string{
+		"id":         "r.id",
+		"date":       "r.reading_date",
+		"amount":     "COALESCE(r.total_amount, 0)",
+		"created_at": "r.created_at",
+	},
+	DefaultSort:  "-date",
+	DateColumn:   "r.reading_date",
+	AmountColumn: "COALESCE(r.total_amount, 0)",
+	StatusFilter: "r.status = ?",
+	PeopleFilter: "EXISTS (SELECT 1 FROM leases l WHERE l.id = r.lease_id AND l.people_id = ?)",
+	UnitFilter:   "r.unit_id = ?",
+}
 
-	if status != nil && *status != "" {
-		query += " AND r.status = ?"
-		args = append(args, *status)
+func readingSortKey(item ReadingListItem, sort string) (interface{}, int) {
+	reading := item.Reading
+	switch sort {
+	case "date":
+		return reading.ReadingDate, reading.ID
+	case "amount":
+		if reading.TotalAmount == nil {
+			return 0.0, reading.ID
+		}
+		return *reading.TotalAmount, reading.ID
+	case "created_at":
+		return reading.CreatedAt, reading.ID
 	}
+	return reading.ID, reading.ID
+}
 
-	query += " ORDER BY r.reading_date DESC, r.id DESC"
+// List returns one page of the readings for units in a building
+func (r *readingRepo) List(buildingID int, params pagination.Params) ([]Reading, int, error) {
+	where, args := readingListSpec.Where(params)
+	where = " WHERE u.building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM readings r INNER JOIN units u ON r.unit_id = u.id"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := readingListSpec.After(params)
+	order, orderArgs := readingListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	query := "SELECT r.id, r.item_id, r.unit_id, r.lease_id, r.reading_month, r.reading_year, r.reading_date, r.previous_value, r.current_value, r.unit_price, r.total_amount, r.notes, r.status, r.created_at, r.updated_at, r.version FROM readings r INNER JOIN units u ON r.unit_id = u.id" + where + after + order
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -129,20 +180,20 @@ func (r *readingRepo) GetByBuildingID(buildingID int, status *string) ([]Reading
 	for rows.Next() {
 		var reading Reading
 		err := rows.Scan(
-			&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt,
+			&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt, &reading.Version,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		readings = append(readings, reading)
 	}
 
-	return readings, nil
+	return readings, total, rows.Err()
 }
 
 func (r *readingRepo) GetByUnitID(unitID int) ([]Reading, error) {
 	rows, err := r.db.Query(
-		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at FROM readings WHERE unit_id = ? ORDER BY reading_date DESC, id DESC",
+		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at, version FROM readings WHERE unit_id = ? ORDER BY reading_date DESC, id DESC",
 		unitID,
 	)
 	if err != nil {
@@ -154,7 +205,7 @@ func (r *readingRepo) GetByUnitID(unitID int) ([]Reading, error) {
 	for rows.Next() {
 		var reading Reading
 		err := rows.Scan(
-			&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt,
+			&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt, &reading.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -167,7 +218,7 @@ func (r *readingRepo) GetByUnitID(unitID int) ([]Reading, error) {
 
 func (r *readingRepo) GetByLeaseID(leaseID int) ([]Reading, error) {
 	rows, err := r.db.Query(
-		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at FROM readings WHERE lease_id = ? ORDER BY reading_date DESC, id DESC",
+		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at, version FROM readings WHERE lease_id = ? ORDER BY reading_date DESC, id DESC",
 		leaseID,
 	)
 	if err != nil {
@@ -179,7 +230,7 @@ func (r *readingRepo) GetByLeaseID(leaseID int) ([]Reading, error) {
 	for rows.Next() {
 		var reading Reading
 		err := rows.Scan(
-			&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt,
+			&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt, &reading.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -193,10 +244,10 @@ func (r *readingRepo) GetByLeaseID(leaseID int) ([]Reading, error) {
 func (r *readingRepo) GetLatestByItemAndUnit(itemID, unitID int) (*Reading, error) {
 	var reading Reading
 	err := r.db.QueryRow(
-		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at FROM readings WHERE item_id = ? AND unit_id = ? AND status = '1' ORDER BY reading_date DESC, id DESC LIMIT 1",
+		"SELECT id, item_id, unit_id, lease_id, reading_month, reading_year, reading_date, previous_value, current_value, unit_price, total_amount, notes, status, created_at, updated_at, version FROM readings WHERE item_id = ? AND unit_id = ? AND status = '1' ORDER BY reading_date DESC, id DESC LIMIT 1",
 		itemID, unitID,
 	).Scan(
-		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt,
+		&reading.ID, &reading.ItemID, &reading.UnitID, &reading.LeaseID, &reading.ReadingMonth, &reading.ReadingYear, &reading.ReadingDate, &reading.PreviousValue, &reading.CurrentValue, &reading.UnitPrice, &reading.TotalAmount, &reading.Notes, &reading.Status, &reading.CreatedAt, &reading.UpdatedAt, &reading.Version,
 	)
 
 	if err == sql.ErrNoRows {
@@ -4,10 +4,13 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/items"
 	"github.com/mysecodgit/go_accounting/src/leases"
+	"github.com/mysecodgit/go_accounting/src/pagination"
 	"github.com/mysecodgit/go_accounting/src/people"
 	"github.com/mysecodgit/go_accounting/src/unit"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type ReadingService struct {
@@ -58,23 +61,23 @@ func (s *ReadingService) CreateReading(req CreateReadingRequest) (*ReadingRespon
 	}
 
 	if errors := reading.Validate(); errors != nil {
-		return nil, fmt.Errorf("validation failed: %v", errors)
+		return nil, apperrors.Validation(errors)
 	}
 
 	createdReading, err := s.readingRepo.Create(reading)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reading: %v", err)
+		return nil, fmt.Errorf("failed to create reading: %w", err)
 	}
 
 	// Fetch related entities
 	item, _, _, _, _, _, err := s.itemRepo.GetByID(createdReading.ItemID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch item: %v", err)
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
 	}
 
 	unitData, _, err := s.unitRepo.GetByID(createdReading.UnitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch unit: %v", err)
+		return nil, fmt.Errorf("failed to fetch unit: %w", err)
 	}
 
 	var leaseData *leases.Lease
@@ -111,23 +114,37 @@ func (s *ReadingService) UpdateReading(req UpdateReadingRequest) (*ReadingRespon
 	}
 
 	if errors := reading.Validate(); errors != nil {
-		return nil, fmt.Errorf("validation failed: %v", errors)
+		return nil, apperrors.Validation(errors)
 	}
 
-	updatedReading, err := s.readingRepo.Update(reading)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := versioning.Bump(tx, "readings", req.ID, req.ExpectedVersion, "reading"); err != nil {
+		return nil, err
+	}
+
+	updatedReading, err := s.readingRepo.UpdateWithTx(tx, reading)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update reading: %v", err)
+		return nil, fmt.Errorf("failed to update reading: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	// Fetch related entities
 	item, _, _, _, _, _, err := s.itemRepo.GetByID(updatedReading.ItemID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch item: %v", err)
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
 	}
 
 	unitData, _, err := s.unitRepo.GetByID(updatedReading.UnitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch unit: %v", err)
+		return nil, fmt.Errorf("failed to fetch unit: %w", err)
 	}
 
 	var leaseData *leases.Lease
@@ -155,12 +172,12 @@ func (s *ReadingService) GetReadingByID(id int) (*ReadingResponse, error) {
 	// Fetch related entities
 	item, _, _, _, _, _, err := s.itemRepo.GetByID(reading.ItemID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch item: %v", err)
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
 	}
 
 	unitData, _, err := s.unitRepo.GetByID(reading.UnitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch unit: %v", err)
+		return nil, fmt.Errorf("failed to fetch unit: %w", err)
 	}
 
 	var leaseData *leases.Lease
@@ -179,10 +196,10 @@ func (s *ReadingService) GetReadingByID(id int) (*ReadingResponse, error) {
 	}, nil
 }
 
-func (s *ReadingService) GetReadingsByBuildingID(buildingID int, status *string) ([]ReadingListItem, error) {
-	readings, err := s.readingRepo.GetByBuildingID(buildingID, status)
+func (s *ReadingService) GetReadingsByBuildingID(buildingID int, params pagination.Params) (pagination.Page[ReadingListItem], error) {
+	readings, total, err := s.readingRepo.List(buildingID, params)
 	if err != nil {
-		return nil, err
+		return pagination.Page[ReadingListItem]{}, fmt.Errorf("failed to list readings: %w", err)
 	}
 
 	result := []ReadingListItem{}
@@ -224,7 +241,7 @@ func (s *ReadingService) GetReadingsByBuildingID(buildingID int, status *string)
 		})
 	}
 
-	return result, nil
+	return pagination.NewPage(result, total, params, readingSortKey), nil
 }
 
 func (s *ReadingService) GetReadingsByUnitID(unitID int) ([]ReadingListItem, error) {
@@ -242,12 +259,12 @@ func (s *ReadingService) GetReadingsByUnitID(unitID int) ([]ReadingListItem, err
 
 		item, _, _, _, _, _, err := s.itemRepo.GetByID(reading.ItemID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch item for reading %d: %v", reading.ID, err)
+			return nil, fmt.Errorf("failed to fetch item for reading %d: %w", reading.ID, err)
 		}
 
 		unitData, _, err := s.unitRepo.GetByID(reading.UnitID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch unit for reading %d: %v", reading.ID, err)
+			return nil, fmt.Errorf("failed to fetch unit for reading %d: %w", reading.ID, err)
 		}
 
 		var leaseData *LeaseWithPeople
@@ -287,7 +304,7 @@ func (s *ReadingService) BulkImportReadings(req BulkImportReadingsRequest) (*Bul
 	// Start transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	committed := false
@@ -347,13 +364,13 @@ func (s *ReadingService) BulkImportReadings(req BulkImportReadingsRequest) (*Bul
 			SuccessCount: 0,
 			FailedCount:  failedCount,
 			Errors:       errors,
-		}, fmt.Errorf("import failed: %d rows failed", failedCount)
+		}, apperrors.Rulef("import failed: %d rows failed", failedCount)
 	}
 
 	// Commit transaction if all rows succeeded
 	err = tx.Commit()
 	if err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	committed = true
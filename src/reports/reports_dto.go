@@ -1,9 +1,16 @@
 package reports
 
+// Report bases for the balance sheet, trial balance and profit and loss
+const (
+	ReportBasisAccrual = "accrual"
+	ReportBasisCash    = "cash"
+)
+
 // Balance Sheet DTOs
 type BalanceSheetRequest struct {
 	BuildingID int    `json:"building_id"`
 	AsOfDate   string `json:"as_of_date"` // Date to calculate balance sheet as of
+	Basis      string `json:"basis"`      // accrual (default) or cash
 }
 
 type AccountBalance struct {
@@ -23,6 +30,7 @@ type BalanceSheetSection struct {
 type BalanceSheetResponse struct {
 	BuildingID                int                 `json:"building_id"`
 	AsOfDate                  string              `json:"as_of_date"`
+	Basis                     string              `json:"basis"`
 	Assets                    BalanceSheetSection `json:"assets"`
 	Liabilities               BalanceSheetSection `json:"liabilities"`
 	Equity                    BalanceSheetSection `json:"equity"`
@@ -35,6 +43,7 @@ type BalanceSheetResponse struct {
 type TrialBalanceRequest struct {
 	BuildingID int    `json:"building_id"`
 	AsOfDate   string `json:"as_of_date"` // Date to calculate trial balance as of
+	Basis      string `json:"basis"`      // accrual (default) or cash
 }
 
 type TrialBalanceAccount struct {
@@ -50,6 +59,7 @@ type TrialBalanceAccount struct {
 type TrialBalanceResponse struct {
 	BuildingID  int                   `json:"building_id"`
 	AsOfDate    string                `json:"as_of_date"`
+	Basis       string                `json:"basis"`
 	Accounts    []TrialBalanceAccount `json:"accounts"`
 	TotalDebit  float64               `json:"total_debit"`
 	TotalCredit float64               `json:"total_credit"`
@@ -173,11 +183,84 @@ type CustomerBalanceDetailsResponse struct {
 	GrandTotalBalance float64                `json:"grand_total_balance"`
 }
 
+// Customer Statement DTOs
+type CustomerStatementRequest struct {
+	BuildingID int    `json:"building_id"`
+	PeopleID   *int   `json:"people_id"` // Required for a single statement; all customers in batch mode
+	UnitID     *int   `json:"unit_id"`   // Optional: only activity of this unit
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+}
+
+// Statement line types
+const (
+	StatementLineInvoice       = "invoice"
+	StatementLinePayment       = "payment"
+	StatementLineDiscount      = "discount"
+	StatementLineCreditMemo    = "credit_memo"
+	StatementLineAppliedCredit = "applied_credit"
+	StatementLineSalesReceipt  = "sales_receipt"
+
+	StatementLineCustomerPayment    = "customer_payment"
+	StatementLineCustomerCreditUsed = "customer_payment_application" // Unapplied payment credit applied to invoices
+
+	StatementLinePrepayment        = "customer_prepayment"
+	StatementLinePrepaymentApplied = "customer_prepayment_application" // Moves a prepayment to invoices without changing the balance
+)
+
+type CustomerStatementLine struct {
+	Date          string  `json:"date"`
+	Type          string  `json:"type"` // invoice, payment, customer_payment, customer_prepayment, discount, credit_memo, applied_credit, sales_receipt or another transaction type
+	Reference     string  `json:"reference"`
+	Description   string  `json:"description"`
+	TransactionID *int    `json:"transaction_id,omitempty"`
+	Amount        float64 `json:"amount"`   // The document's amount; applied credits move it without changing the balance
+	Charges       float64 `json:"charges"`  // Increases the balance
+	Payments      float64 `json:"payments"` // Payments and credits, decrease the balance
+	Balance       float64 `json:"balance"`  // Running balance after this line
+}
+
+// CustomerStatementAging splits what is owed at the end date by how long the invoices are past due
+type CustomerStatementAging struct {
+	Current         float64 `json:"current"`
+	Days1To30       float64 `json:"days_1_30"`
+	Days31To60      float64 `json:"days_31_60"`
+	Days61To90      float64 `json:"days_61_90"`
+	Over90          float64 `json:"over_90"`
+	UnappliedCredit float64 `json:"unapplied_credit"` // Credit memo and customer payment amounts not applied to an invoice yet
+	Total           float64 `json:"total"`
+}
+
+type CustomerStatement struct {
+	BuildingID     int                     `json:"building_id"`
+	PeopleID       int                     `json:"people_id"`
+	PeopleName     string                  `json:"people_name"`
+	Phone          string                  `json:"phone"`
+	UnitID         *int                    `json:"unit_id"`
+	UnitName       *string                 `json:"unit_name"`
+	StartDate      string                  `json:"start_date"`
+	EndDate        string                  `json:"end_date"`
+	OpeningBalance float64                 `json:"opening_balance"`
+	Lines          []CustomerStatementLine `json:"lines"`
+	TotalCharges   float64                 `json:"total_charges"`
+	TotalPayments  float64                 `json:"total_payments"`
+	ClosingBalance float64                 `json:"closing_balance"`
+	Aging          CustomerStatementAging  `json:"aging"`
+}
+
+type CustomerStatementsResponse struct {
+	BuildingID int                 `json:"building_id"`
+	StartDate  string              `json:"start_date"`
+	EndDate    string              `json:"end_date"`
+	Statements []CustomerStatement `json:"statements"`
+}
+
 // Profit and Loss Standard DTOs
 type ProfitAndLossStandardRequest struct {
 	BuildingID int    `json:"building_id"`
 	StartDate  string `json:"start_date"`
 	EndDate    string `json:"end_date"`
+	Basis      string `json:"basis"` // accrual (default) or cash
 }
 
 type ProfitAndLossAccount struct {
@@ -197,6 +280,7 @@ type ProfitAndLossStandardResponse struct {
 	BuildingID    int                   `json:"building_id"`
 	StartDate     string                `json:"start_date"`
 	EndDate       string                `json:"end_date"`
+	Basis         string                `json:"basis"`
 	Income        ProfitAndLossSection  `json:"income"`
 	Expenses      ProfitAndLossSection  `json:"expenses"`
 	NetProfitLoss float64               `json:"net_profit_loss"` // Income - Expenses
@@ -207,6 +291,7 @@ type ProfitAndLossByUnitRequest struct {
 	BuildingID int    `json:"building_id"`
 	StartDate  string `json:"start_date"`
 	EndDate    string `json:"end_date"`
+	Basis      string `json:"basis"` // accrual (default) or cash
 }
 
 type UnitColumn struct {
@@ -227,6 +312,7 @@ type ProfitAndLossByUnitResponse struct {
 	BuildingID        int                `json:"building_id"`
 	StartDate         string             `json:"start_date"`
 	EndDate           string             `json:"end_date"`
+	Basis             string             `json:"basis"`
 	Units             []UnitColumn       `json:"units"`              // Column headers
 	IncomeAccounts    []AccountRow       `json:"income_accounts"`    // Income account rows
 	ExpenseAccounts   []AccountRow       `json:"expense_accounts"`   // Expense account rows
@@ -237,3 +323,202 @@ type ProfitAndLossByUnitResponse struct {
 	GrandTotalExpenses float64           `json:"grand_total_expenses"`
 	GrandTotalNetProfitLoss float64      `json:"grand_total_net_profit_loss"`
 }
+
+// General Ledger DTOs
+type GeneralLedgerRequest struct {
+	BuildingID int    `json:"building_id"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+}
+
+type CounterpartAccount struct {
+	AccountID     int    `json:"account_id"`
+	AccountNumber int    `json:"account_number"`
+	AccountName   string `json:"account_name"`
+}
+
+type GeneralLedgerLine struct {
+	SplitID             int                  `json:"split_id"`
+	TransactionID       int                  `json:"transaction_id"`
+	TransactionNumber   string               `json:"transaction_number"`
+	TransactionDate     string               `json:"transaction_date"`
+	TransactionType     string               `json:"transaction_type"`
+	TransactionMemo     string               `json:"transaction_memo"`
+	PeopleID            *int                 `json:"people_id"`
+	PeopleName          *string              `json:"people_name,omitempty"`
+	UnitID              *int                 `json:"unit_id"`
+	CounterpartAccounts []CounterpartAccount `json:"counterpart_accounts"` // Other accounts hit by the same transaction
+	Debit               *float64             `json:"debit"`
+	Credit              *float64             `json:"credit"`
+	Balance             float64              `json:"balance"` // Running balance for this account
+}
+
+type GeneralLedgerAccount struct {
+	AccountID      int                 `json:"account_id"`
+	AccountNumber  int                 `json:"account_number"`
+	AccountName    string              `json:"account_name"`
+	AccountType    string              `json:"account_type"`
+	OpeningBalance float64             `json:"opening_balance"` // Balance before start date
+	Lines          []GeneralLedgerLine `json:"lines"`
+	TotalDebit     float64             `json:"total_debit"`
+	TotalCredit    float64             `json:"total_credit"`
+	ClosingBalance float64             `json:"closing_balance"` // Balance at end date
+}
+
+type GeneralLedgerResponse struct {
+	BuildingID       int                    `json:"building_id"`
+	StartDate        string                 `json:"start_date"`
+	EndDate          string                 `json:"end_date"`
+	Accounts         []GeneralLedgerAccount `json:"accounts"`
+	GrandTotalDebit  float64                `json:"grand_total_debit"`
+	GrandTotalCredit float64                `json:"grand_total_credit"`
+}
+
+// Journal Report DTOs
+type JournalReportRequest struct {
+	BuildingID int    `json:"building_id"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+}
+
+type JournalReportSplit struct {
+	SplitID       int      `json:"split_id"`
+	AccountID     int      `json:"account_id"`
+	AccountNumber int      `json:"account_number"`
+	AccountName   string   `json:"account_name"`
+	PeopleID      *int     `json:"people_id"`
+	PeopleName    *string  `json:"people_name,omitempty"`
+	UnitID        *int     `json:"unit_id"`
+	Debit         *float64 `json:"debit"`
+	Credit        *float64 `json:"credit"`
+}
+
+type JournalReportTransaction struct {
+	TransactionID     int                  `json:"transaction_id"`
+	TransactionNumber string               `json:"transaction_number"`
+	TransactionDate   string               `json:"transaction_date"`
+	TransactionType   string               `json:"transaction_type"`
+	TransactionMemo   string               `json:"transaction_memo"`
+	Splits            []JournalReportSplit `json:"splits"`
+	TotalDebit        float64              `json:"total_debit"`
+	TotalCredit       float64              `json:"total_credit"`
+}
+
+type JournalReportResponse struct {
+	BuildingID       int                        `json:"building_id"`
+	StartDate        string                     `json:"start_date"`
+	EndDate          string                     `json:"end_date"`
+	Transactions     []JournalReportTransaction `json:"transactions"`
+	GrandTotalDebit  float64                    `json:"grand_total_debit"`
+	GrandTotalCredit float64                    `json:"grand_total_credit"`
+}
+
+// Budget vs Actual DTOs
+type BudgetVsActualRequest struct {
+	BuildingID   int  `json:"building_id"`
+	Year         int  `json:"year"`
+	UnitID       *int `json:"unit_id"`       // Optional: compare a single unit's budget and actuals
+	ThroughMonth int  `json:"through_month"` // Last month included in year to date (1-12)
+}
+
+type BudgetVsActualAmounts struct {
+	Budget          float64  `json:"budget"`
+	Actual          float64  `json:"actual"`
+	Variance        float64  `json:"variance"`         // Positive is favorable (income above / expenses below budget)
+	VariancePercent *float64 `json:"variance_percent"` // Variance as % of budget, nil when budget is 0
+}
+
+type BudgetVsActualRow struct {
+	AccountID     int                       `json:"account_id"`
+	AccountNumber int                       `json:"account_number"`
+	AccountName   string                    `json:"account_name"`
+	AccountType   string                    `json:"account_type"` // "income" or "expense"
+	Months        [12]BudgetVsActualAmounts `json:"months"`
+	YearToDate    BudgetVsActualAmounts     `json:"year_to_date"`
+}
+
+type BudgetVsActualTotals struct {
+	Months     [12]BudgetVsActualAmounts `json:"months"`
+	YearToDate BudgetVsActualAmounts     `json:"year_to_date"`
+}
+
+type BudgetVsActualResponse struct {
+	BuildingID      int                  `json:"building_id"`
+	Year            int                  `json:"year"`
+	UnitID          *int                 `json:"unit_id"`
+	ThroughMonth    int                  `json:"through_month"`
+	IncomeAccounts  []BudgetVsActualRow  `json:"income_accounts"`
+	ExpenseAccounts []BudgetVsActualRow  `json:"expense_accounts"`
+	TotalIncome     BudgetVsActualTotals `json:"total_income"`
+	TotalExpenses   BudgetVsActualTotals `json:"total_expenses"`
+	NetProfitLoss   BudgetVsActualTotals `json:"net_profit_loss"`
+}
+
+// Comparative (multi-column) report DTOs
+type ReportColumn struct {
+	Label     string `json:"label"`
+	StartDate string `json:"start_date,omitempty"` // Empty for balance sheet columns
+	EndDate   string `json:"end_date"`             // Period end, or as of date for balance sheet columns
+}
+
+type ColumnChange struct {
+	Amount  float64  `json:"amount"`  // Column value minus the previous column value
+	Percent *float64 `json:"percent"` // Change as % of the previous column, nil when it is 0
+}
+
+type ComparativeRow struct {
+	AccountID     int            `json:"account_id"`
+	AccountNumber int            `json:"account_number"`
+	AccountName   string         `json:"account_name"`
+	AccountType   string         `json:"account_type"`
+	Values        []float64      `json:"values"`          // One value per column
+	Total         *float64       `json:"total,omitempty"` // Sum across columns (profit and loss only)
+	Changes       []ColumnChange `json:"changes"`         // changes[i] compares column i+1 with column i
+}
+
+type ComparativeSection struct {
+	SectionName string           `json:"section_name"`
+	Accounts    []ComparativeRow `json:"accounts"`
+	Totals      ComparativeRow   `json:"totals"`
+}
+
+type ComparativeProfitAndLossRequest struct {
+	BuildingID int    `json:"building_id"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	Columns    string `json:"columns"` // months, quarters, years or prior_year
+	Basis      string `json:"basis"`   // accrual (default) or cash
+}
+
+type ComparativeProfitAndLossResponse struct {
+	BuildingID    int                `json:"building_id"`
+	StartDate     string             `json:"start_date"`
+	EndDate       string             `json:"end_date"`
+	ColumnSpec    string             `json:"column_spec"`
+	Basis         string             `json:"basis"`
+	Columns       []ReportColumn     `json:"columns"`
+	Income        ComparativeSection `json:"income"`
+	Expenses      ComparativeSection `json:"expenses"`
+	NetProfitLoss ComparativeRow     `json:"net_profit_loss"`
+}
+
+type ComparativeBalanceSheetRequest struct {
+	BuildingID int      `json:"building_id"`
+	StartDate  string   `json:"start_date"`
+	EndDate    string   `json:"end_date"`
+	Columns    string   `json:"columns"`     // months, quarters, years, prior_year or dates
+	AsOfDates  []string `json:"as_of_dates"` // Explicit column dates when columns is "dates"
+	Basis      string   `json:"basis"`       // accrual (default) or cash
+}
+
+type ComparativeBalanceSheetResponse struct {
+	BuildingID                int                `json:"building_id"`
+	ColumnSpec                string             `json:"column_spec"`
+	Basis                     string             `json:"basis"`
+	Columns                   []ReportColumn     `json:"columns"`
+	Assets                    ComparativeSection `json:"assets"`
+	Liabilities               ComparativeSection `json:"liabilities"`
+	Equity                    ComparativeSection `json:"equity"`
+	TotalLiabilitiesAndEquity ComparativeRow     `json:"total_liabilities_and_equity"`
+	IsBalanced                []bool             `json:"is_balanced"` // One flag per column
+}
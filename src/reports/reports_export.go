@@ -0,0 +1,433 @@
+package reports
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mysecodgit/go_accounting/src/export"
+)
+
+func dateRangeLabel(startDate string, endDate string) string {
+	return startDate + " to " + endDate
+}
+
+// basisLabel marks cash basis reports in the period line; accrual is the default and left unmarked
+func basisLabel(period string, basis string) string {
+	if basis == ReportBasisCash {
+		return period + " (cash basis)"
+	}
+	return period
+}
+
+func optionalString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func optionalPercent(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f%%", *v)
+}
+
+func (r *BalanceSheetResponse) ToDocument(buildingName string) export.Document {
+	columns := []string{"Account", "Type", "Balance"}
+	section := func(s BalanceSheetSection) export.Table {
+		rows := []export.Row{}
+		for _, account := range s.Accounts {
+			rows = append(rows, export.Row{Cells: []string{
+				accountLabel(account.AccountNumber, account.AccountName), account.AccountType, export.Amount(account.Balance),
+			}})
+		}
+		rows = append(rows, export.Row{Cells: []string{"Total " + s.SectionName, "", export.Amount(s.Total)}, Kind: export.RowTotal})
+		return export.Table{Title: s.SectionName, Columns: columns, Rows: rows}
+	}
+
+	summary := export.Table{
+		Columns: []string{"Summary", "", "Amount"},
+		Rows: []export.Row{
+			{Cells: []string{"Total Assets", "", export.Amount(r.TotalAssets)}, Kind: export.RowTotal},
+			{Cells: []string{"Total Liabilities and Equity", "", export.Amount(r.TotalLiabilitiesAndEquity)}, Kind: export.RowTotal},
+		},
+	}
+
+	return export.Document{
+		Title:        "Balance Sheet",
+		BuildingName: buildingName,
+		Period:       basisLabel("As of "+r.AsOfDate, r.Basis),
+		Tables:       []export.Table{section(r.Assets), section(r.Liabilities), section(r.Equity), summary},
+	}
+}
+
+func (r *TrialBalanceResponse) ToDocument(buildingName string) export.Document {
+	rows := []export.Row{}
+	for _, account := range r.Accounts {
+		if account.IsTotalRow {
+			rows = append(rows, export.Row{Cells: []string{"TOTAL", "", export.Amount(account.DebitBalance), export.Amount(account.CreditBalance)}, Kind: export.RowTotal})
+			continue
+		}
+		rows = append(rows, export.Row{Cells: []string{
+			accountLabel(strconv.Itoa(account.AccountNumber), account.AccountName), account.AccountType,
+			export.Amount(account.DebitBalance), export.Amount(account.CreditBalance),
+		}})
+	}
+
+	return export.Document{
+		Title:        "Trial Balance",
+		BuildingName: buildingName,
+		Period:       basisLabel("As of "+r.AsOfDate, r.Basis),
+		Tables:       []export.Table{{Columns: []string{"Account", "Type", "Debit", "Credit"}, Rows: rows}},
+	}
+}
+
+func (r *TransactionDetailsByAccountResponse) ToDocument(buildingName string) export.Document {
+	columns := []string{"Date", "Type", "Number", "Name", "Memo", "Debit", "Credit", "Balance"}
+	tables := []export.Table{}
+
+	for _, account := range r.Accounts {
+		if account.IsTotalRow {
+			continue
+		}
+		rows := []export.Row{}
+		for _, split := range account.Splits {
+			rows = append(rows, export.Row{Cells: []string{
+				split.TransactionDate, split.TransactionType, split.TransactionNumber, optionalString(split.PeopleName), split.TransactionMemo,
+				export.OptionalAmount(split.Debit), export.OptionalAmount(split.Credit), export.Amount(split.Balance),
+			}})
+		}
+		rows = append(rows, export.Row{Cells: []string{
+			"Total " + account.AccountName, "", "", "", "", export.Amount(account.TotalDebit), export.Amount(account.TotalCredit), export.Amount(account.TotalBalance),
+		}, Kind: export.RowTotal})
+
+		tables = append(tables, export.Table{
+			Title:   accountLabel(strconv.Itoa(account.AccountNumber), account.AccountName),
+			Columns: columns,
+			Rows:    rows,
+		})
+	}
+
+	tables = append(tables, export.Table{
+		Columns: []string{"Grand Total", "Debit", "Credit"},
+		Rows:    []export.Row{{Cells: []string{"", export.Amount(r.GrandTotalDebit), export.Amount(r.GrandTotalCredit)}, Kind: export.RowTotal}},
+	})
+
+	return export.Document{
+		Title:        "Transaction Details by Account",
+		BuildingName: buildingName,
+		Period:       dateRangeLabel(r.StartDate, r.EndDate),
+		Tables:       tables,
+	}
+}
+
+func (r *CustomerBalanceSummaryResponse) ToDocument(buildingName string) export.Document {
+	rows := []export.Row{}
+	for _, customer := range r.Customers {
+		rows = append(rows, export.Row{Cells: []string{customer.PeopleName, export.Amount(customer.Balance)}})
+	}
+	rows = append(rows, export.Row{Cells: []string{"TOTAL", export.Amount(r.TotalBalance)}, Kind: export.RowTotal})
+
+	return export.Document{
+		Title:        "Customer Balance Summary",
+		BuildingName: buildingName,
+		Period:       "As of " + r.AsOfDate,
+		Tables:       []export.Table{{Columns: []string{"Customer", "Balance"}, Rows: rows}},
+	}
+}
+
+func (r *CustomerBalanceDetailsResponse) ToDocument(buildingName string) export.Document {
+	columns := []string{"Date", "Type", "Number", "Account", "Memo", "Debit", "Credit", "Balance"}
+	tables := []export.Table{}
+
+	for _, customer := range r.Customers {
+		if !customer.IsHeader {
+			continue
+		}
+		rows := []export.Row{}
+		for _, account := range customer.Accounts {
+			for _, split := range account.Splits {
+				rows = append(rows, export.Row{Cells: []string{
+					split.TransactionDate, split.TransactionType, split.TransactionNumber, split.AccountName, split.TransactionMemo,
+					export.OptionalAmount(split.Debit), export.OptionalAmount(split.Credit), export.Amount(split.Balance),
+				}})
+			}
+		}
+		rows = append(rows, export.Row{Cells: []string{
+			"Total " + customer.PeopleName, "", "", "", "", export.Amount(customer.TotalDebit), export.Amount(customer.TotalCredit), export.Amount(customer.TotalBalance),
+		}, Kind: export.RowTotal})
+
+		tables = append(tables, export.Table{Title: customer.PeopleName, Columns: columns, Rows: rows})
+	}
+
+	tables = append(tables, export.Table{
+		Columns: []string{"Grand Total", "Debit", "Credit", "Balance"},
+		Rows: []export.Row{{Cells: []string{
+			"", export.Amount(r.GrandTotalDebit), export.Amount(r.GrandTotalCredit), export.Amount(r.GrandTotalBalance),
+		}, Kind: export.RowTotal}},
+	})
+
+	return export.Document{
+		Title:        "Customer Balance Details",
+		BuildingName: buildingName,
+		Period:       "As of " + r.AsOfDate,
+		Tables:       tables,
+	}
+}
+
+func (r *ProfitAndLossStandardResponse) ToDocument(buildingName string) export.Document {
+	columns := []string{"Account", "Amount"}
+	section := func(s ProfitAndLossSection) export.Table {
+		rows := []export.Row{}
+		for _, account := range s.Accounts {
+			rows = append(rows, export.Row{Cells: []string{
+				accountLabel(strconv.Itoa(account.AccountNumber), account.AccountName), export.Amount(account.Balance),
+			}})
+		}
+		rows = append(rows, export.Row{Cells: []string{"Total " + s.SectionName, export.Amount(s.Total)}, Kind: export.RowTotal})
+		return export.Table{Title: s.SectionName, Columns: columns, Rows: rows}
+	}
+
+	net := export.Table{
+		Columns: columns,
+		Rows:    []export.Row{{Cells: []string{"Net Profit/Loss", export.Amount(r.NetProfitLoss)}, Kind: export.RowTotal}},
+	}
+
+	return export.Document{
+		Title:        "Profit and Loss",
+		BuildingName: buildingName,
+		Period:       basisLabel(dateRangeLabel(r.StartDate, r.EndDate), r.Basis),
+		Tables:       []export.Table{section(r.Income), section(r.Expenses), net},
+	}
+}
+
+func (r *ProfitAndLossByUnitResponse) ToDocument(buildingName string) export.Document {
+	columns := []string{"Account"}
+	for _, unit := range r.Units {
+		columns = append(columns, unit.UnitName)
+	}
+	columns = append(columns, "Total")
+
+	accountRows := func(accounts []AccountRow) []export.Row {
+		rows := []export.Row{}
+		for _, account := range accounts {
+			cells := []string{accountLabel(strconv.Itoa(account.AccountNumber), account.AccountName)}
+			for _, unit := range r.Units {
+				cells = append(cells, export.Amount(account.Balances[unit.UnitID]))
+			}
+			cells = append(cells, export.Amount(account.Total))
+			rows = append(rows, export.Row{Cells: cells})
+		}
+		return rows
+	}
+	totalRow := func(label string, byUnit map[int]float64, total float64) export.Row {
+		cells := []string{label}
+		for _, unit := range r.Units {
+			cells = append(cells, export.Amount(byUnit[unit.UnitID]))
+		}
+		cells = append(cells, export.Amount(total))
+		return export.Row{Cells: cells, Kind: export.RowTotal}
+	}
+
+	income := export.Table{Title: "Income", Columns: columns, Rows: accountRows(r.IncomeAccounts)}
+	income.Rows = append(income.Rows, totalRow("Total Income", r.TotalIncome, r.GrandTotalIncome))
+
+	expenses := export.Table{Title: "Expenses", Columns: columns, Rows: accountRows(r.ExpenseAccounts)}
+	expenses.Rows = append(expenses.Rows, totalRow("Total Expenses", r.TotalExpenses, r.GrandTotalExpenses))
+
+	net := export.Table{Columns: columns, Rows: []export.Row{totalRow("Net Profit/Loss", r.NetProfitLoss, r.GrandTotalNetProfitLoss)}}
+
+	return export.Document{
+		Title:        "Profit and Loss by Unit",
+		BuildingName: buildingName,
+		Period:       basisLabel(dateRangeLabel(r.StartDate, r.EndDate), r.Basis),
+		Tables:       []export.Table{income, expenses, net},
+	}
+}
+
+func (r *GeneralLedgerResponse) ToDocument(buildingName string) export.Document {
+	columns := []string{"Date", "Type", "Number", "Name", "Counterpart", "Debit", "Credit", "Balance"}
+	tables := []export.Table{}
+
+	for _, account := range r.Accounts {
+		rows := []export.Row{{Cells: []string{"Opening Balance", "", "", "", "", "", "", export.Amount(account.OpeningBalance)}, Kind: export.RowHeader}}
+		for _, line := range account.Lines {
+			counterpart := ""
+			for i, other := range line.CounterpartAccounts {
+				if i > 0 {
+					counterpart += ", "
+				}
+				counterpart += other.AccountName
+			}
+			rows = append(rows, export.Row{Cells: []string{
+				line.TransactionDate, line.TransactionType, line.TransactionNumber, optionalString(line.PeopleName), counterpart,
+				export.OptionalAmount(line.Debit), export.OptionalAmount(line.Credit), export.Amount(line.Balance),
+			}})
+		}
+		rows = append(rows, export.Row{Cells: []string{
+			"Closing Balance", "", "", "", "", export.Amount(account.TotalDebit), export.Amount(account.TotalCredit), export.Amount(account.ClosingBalance),
+		}, Kind: export.RowTotal})
+
+		tables = append(tables, export.Table{
+			Title:   accountLabel(strconv.Itoa(account.AccountNumber), account.AccountName),
+			Columns: columns,
+			Rows:    rows,
+		})
+	}
+
+	tables = append(tables, export.Table{
+		Columns: []string{"Grand Total", "Debit", "Credit"},
+		Rows:    []export.Row{{Cells: []string{"", export.Amount(r.GrandTotalDebit), export.Amount(r.GrandTotalCredit)}, Kind: export.RowTotal}},
+	})
+
+	return export.Document{
+		Title:        "General Ledger",
+		BuildingName: buildingName,
+		Period:       dateRangeLabel(r.StartDate, r.EndDate),
+		Tables:       tables,
+	}
+}
+
+func (r *JournalReportResponse) ToDocument(buildingName string) export.Document {
+	rows := []export.Row{}
+	for _, transaction := range r.Transactions {
+		rows = append(rows, export.Row{Cells: []string{
+			transaction.TransactionDate, transaction.TransactionType, transaction.TransactionNumber, transaction.TransactionMemo, "", "", "",
+		}, Kind: export.RowHeader})
+		for _, split := range transaction.Splits {
+			rows = append(rows, export.Row{Cells: []string{
+				"", "", "", "", accountLabel(strconv.Itoa(split.AccountNumber), split.AccountName),
+				export.OptionalAmount(split.Debit), export.OptionalAmount(split.Credit),
+			}})
+		}
+	}
+	rows = append(rows, export.Row{Cells: []string{
+		"TOTAL", "", "", "", "", export.Amount(r.GrandTotalDebit), export.Amount(r.GrandTotalCredit),
+	}, Kind: export.RowTotal})
+
+	return export.Document{
+		Title:        "Journal",
+		BuildingName: buildingName,
+		Period:       dateRangeLabel(r.StartDate, r.EndDate),
+		Tables:       []export.Table{{Columns: []string{"Date", "Type", "Number", "Memo", "Account", "Debit", "Credit"}, Rows: rows}},
+	}
+}
+
+func (r *BudgetVsActualResponse) ToDocument(buildingName string) export.Document {
+	columns := []string{"Account", "Budget", "Actual", "Variance", "Variance %"}
+	amountCells := func(label string, a BudgetVsActualAmounts) []string {
+		return []string{label, export.Amount(a.Budget), export.Amount(a.Actual), export.Amount(a.Variance), optionalPercent(a.VariancePercent)}
+	}
+
+	section := func(title string, accounts []BudgetVsActualRow, totals BudgetVsActualTotals) export.Table {
+		rows := []export.Row{}
+		for _, account := range accounts {
+			rows = append(rows, export.Row{Cells: amountCells(accountLabel(strconv.Itoa(account.AccountNumber), account.AccountName), account.YearToDate)})
+		}
+		rows = append(rows, export.Row{Cells: amountCells("Total "+title, totals.YearToDate), Kind: export.RowTotal})
+		return export.Table{Title: title + " (Year to Date)", Columns: columns, Rows: rows}
+	}
+
+	monthly := export.Table{Title: "Net Profit/Loss by Month", Columns: []string{"Month", "Budget", "Actual", "Variance", "Variance %"}}
+	for m, amounts := range r.NetProfitLoss.Months {
+		monthly.Rows = append(monthly.Rows, export.Row{Cells: amountCells(fmt.Sprintf("%d-%02d", r.Year, m+1), amounts)})
+	}
+	monthly.Rows = append(monthly.Rows, export.Row{Cells: amountCells("Year to Date", r.NetProfitLoss.YearToDate), Kind: export.RowTotal})
+
+	return export.Document{
+		Title:        "Budget vs Actual",
+		BuildingName: buildingName,
+		Period:       fmt.Sprintf("%d through month %d", r.Year, r.ThroughMonth),
+		Tables: []export.Table{
+			section("Income", r.IncomeAccounts, r.TotalIncome),
+			section("Expenses", r.ExpenseAccounts, r.TotalExpenses),
+			monthly,
+		},
+	}
+}
+
+// comparativeTables renders comparative sections with one column per report column plus totals
+func comparativeTables(columns []ReportColumn, withTotal bool, sections []ComparativeSection, summary []ComparativeRow) []export.Table {
+	header := []string{"Account"}
+	for _, column := range columns {
+		header = append(header, column.Label)
+	}
+	if withTotal {
+		header = append(header, "Total")
+	}
+	if len(columns) > 1 {
+		header = append(header, "Change")
+	}
+
+	rowCells := func(label string, row ComparativeRow) []string {
+		cells := []string{label}
+		for _, v := range row.Values {
+			cells = append(cells, export.Amount(v))
+		}
+		if withTotal && row.Total != nil {
+			cells = append(cells, export.Amount(*row.Total))
+		}
+		// Change column compares the last column with the first
+		if len(row.Values) > 1 {
+			cells = append(cells, export.Amount(row.Values[len(row.Values)-1]-row.Values[0]))
+		}
+		return cells
+	}
+
+	tables := []export.Table{}
+	for _, section := range sections {
+		rows := []export.Row{}
+		for _, account := range section.Accounts {
+			label := account.AccountName
+			if account.AccountNumber > 0 {
+				label = accountLabel(strconv.Itoa(account.AccountNumber), account.AccountName)
+			}
+			rows = append(rows, export.Row{Cells: rowCells(label, account)})
+		}
+		rows = append(rows, export.Row{Cells: rowCells(section.Totals.AccountName, section.Totals), Kind: export.RowTotal})
+		tables = append(tables, export.Table{Title: section.SectionName, Columns: header, Rows: rows})
+	}
+
+	if len(summary) > 0 {
+		rows := []export.Row{}
+		for _, row := range summary {
+			rows = append(rows, export.Row{Cells: rowCells(row.AccountName, row), Kind: export.RowTotal})
+		}
+		tables = append(tables, export.Table{Columns: header, Rows: rows})
+	}
+
+	return tables
+}
+
+func (r *ComparativeProfitAndLossResponse) ToDocument(buildingName string) export.Document {
+	return export.Document{
+		Title:        "Profit and Loss (Comparative)",
+		BuildingName: buildingName,
+		Period:       basisLabel(dateRangeLabel(r.StartDate, r.EndDate), r.Basis),
+		Tables:       comparativeTables(r.Columns, true, []ComparativeSection{r.Income, r.Expenses}, []ComparativeRow{r.NetProfitLoss}),
+	}
+}
+
+func (r *ComparativeBalanceSheetResponse) ToDocument(buildingName string) export.Document {
+	period := ""
+	if len(r.Columns) > 0 {
+		period = "As of " + r.Columns[0].EndDate + " to " + r.Columns[len(r.Columns)-1].EndDate
+	}
+
+	return export.Document{
+		Title:        "Balance Sheet (Comparative)",
+		BuildingName: buildingName,
+		Period:       basisLabel(period, r.Basis),
+		Tables: comparativeTables(r.Columns, false, []ComparativeSection{r.Assets, r.Liabilities, r.Equity},
+			[]ComparativeRow{r.Assets.Totals, r.TotalLiabilitiesAndEquity}),
+	}
+}
+
+// accountLabel formats an account as "number - name", or just the name when there is no number
+func accountLabel(accountNumber string, accountName string) string {
+	if accountNumber == "" || accountNumber == "0" {
+		return accountName
+	}
+	return accountNumber + " - " + accountName
+}
@@ -1,10 +1,15 @@
 package reports
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/export"
 )
 
 type ReportsHandler struct {
@@ -15,6 +20,41 @@ func NewReportsHandler(service *ReportsService) *ReportsHandler {
 	return &ReportsHandler{service: service}
 }
 
+// exportableReport is a report response that can be rendered to CSV, XLSX or PDF
+type exportableReport interface {
+	ToDocument(buildingName string) export.Document
+}
+
+// respond writes the report as JSON, or as a downloadable file when a format
+// is selected by the format query parameter or the Accept header
+func (h *ReportsHandler) respond(c *gin.Context, fileName string, buildingID int, report exportableReport) {
+	format, err := export.NegotiateFormat(c.Query("format"), c.GetHeader("Accept"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	if format == export.FormatJSON {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	buildingName, err := h.service.GetBuildingName(buildingID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.Render(&buf, format, report.ToDocument(buildingName)); err != nil {
+		apperrors.Respond(c, fmt.Errorf("failed to export report: %w", err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", export.FileName(fileName, format)))
+	c.Data(http.StatusOK, export.ContentType(format), buf.Bytes())
+}
+
 // GET /reports/balance-sheet
 func (h *ReportsHandler) GetBalanceSheet(c *gin.Context) {
 	var req BalanceSheetRequest
@@ -36,17 +76,48 @@ func (h *ReportsHandler) GetBalanceSheet(c *gin.Context) {
 	req.AsOfDate = c.Query("as_of_date")
 
 	if req.BuildingID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
+		return
+	}
+
+	// accrual (default) or cash
+	basis, err := parseReportBasis(c.Query("basis"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	req.Basis = basis
+
+	// A column spec returns balance sheets at several dates side by side
+	if columns := c.Query("columns"); columns != "" {
+		comparativeReq := ComparativeBalanceSheetRequest{
+			BuildingID: req.BuildingID,
+			StartDate:  c.Query("start_date"),
+			EndDate:    c.Query("end_date"),
+			Columns:    columns,
+			Basis:      req.Basis,
+		}
+		if asOfDates := c.Query("as_of_dates"); asOfDates != "" {
+			comparativeReq.AsOfDates = strings.Split(asOfDates, ",")
+		}
+
+		report, err := h.service.GetComparativeBalanceSheet(comparativeReq)
+		if err != nil {
+			apperrors.Respond(c, err)
+			return
+		}
+
+		h.respond(c, "balance-sheet-comparative", req.BuildingID, report)
 		return
 	}
 
 	report, err := h.service.GetBalanceSheet(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, report)
+	h.respond(c, "balance-sheet", req.BuildingID, report)
 }
 
 // GET /reports/trial-balance
@@ -70,17 +141,25 @@ func (h *ReportsHandler) GetTrialBalance(c *gin.Context) {
 	req.AsOfDate = c.Query("as_of_date")
 
 	if req.BuildingID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
+	// accrual (default) or cash
+	basis, err := parseReportBasis(c.Query("basis"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	req.Basis = basis
+
 	report, err := h.service.GetTrialBalance(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, report)
+	h.respond(c, "trial-balance", req.BuildingID, report)
 }
 
 // GET /reports/transaction-details-by-account
@@ -123,22 +202,22 @@ func (h *ReportsHandler) GetTransactionDetailsByAccount(c *gin.Context) {
 	req.EndDate = c.Query("end_date")
 
 	if req.BuildingID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
 	if req.StartDate == "" || req.EndDate == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Start date and end date are required"})
+		apperrors.Respond(c, apperrors.BadRequest("Start date and end date are required"))
 		return
 	}
 
 	report, err := h.service.GetTransactionDetailsByAccount(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, report)
+	h.respond(c, "transaction-details-by-account", req.BuildingID, report)
 }
 
 // GET /reports/customer-balance-summary
@@ -162,17 +241,17 @@ func (h *ReportsHandler) GetCustomerBalanceSummary(c *gin.Context) {
 	req.AsOfDate = c.Query("as_of_date")
 
 	if req.BuildingID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
 	report, err := h.service.GetCustomerBalanceSummary(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, report)
+	h.respond(c, "customer-balance-summary", req.BuildingID, report)
 }
 
 // GET /reports/customer-balance-details
@@ -204,17 +283,17 @@ func (h *ReportsHandler) GetCustomerBalanceDetails(c *gin.Context) {
 	}
 
 	if req.BuildingID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
 	report, err := h.service.GetCustomerBalanceDetails(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, report)
+	h.respond(c, "customer-balance-details", req.BuildingID, report)
 }
 
 // GET /reports/profit-and-loss-standard
@@ -237,22 +316,48 @@ func (h *ReportsHandler) GetProfitAndLossStandard(c *gin.Context) {
 	req.EndDate = c.Query("end_date")
 
 	if req.BuildingID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
 	if req.StartDate == "" || req.EndDate == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Start date and end date are required"})
+		apperrors.Respond(c, apperrors.BadRequest("Start date and end date are required"))
+		return
+	}
+
+	// accrual (default) or cash
+	basis, err := parseReportBasis(c.Query("basis"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	req.Basis = basis
+
+	// A column spec returns a matrix of account by column (months, quarters, years, prior_year)
+	if columns := c.Query("columns"); columns != "" {
+		report, err := h.service.GetComparativeProfitAndLoss(ComparativeProfitAndLossRequest{
+			BuildingID: req.BuildingID,
+			StartDate:  req.StartDate,
+			EndDate:    req.EndDate,
+			Columns:    columns,
+			Basis:      req.Basis,
+		})
+		if err != nil {
+			apperrors.Respond(c, err)
+			return
+		}
+
+		h.respond(c, "profit-and-loss-comparative", req.BuildingID, report)
 		return
 	}
 
 	report, err := h.service.GetProfitAndLossStandard(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, report)
+	h.respond(c, "profit-and-loss-standard", req.BuildingID, report)
 }
 
 // GET /reports/profit-and-loss-by-unit
@@ -275,20 +380,226 @@ func (h *ReportsHandler) GetProfitAndLossByUnit(c *gin.Context) {
 	req.EndDate = c.Query("end_date")
 
 	if req.BuildingID <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Building ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
 		return
 	}
 
 	if req.StartDate == "" || req.EndDate == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Start date and end date are required"})
+		apperrors.Respond(c, apperrors.BadRequest("Start date and end date are required"))
 		return
 	}
 
+	// accrual (default) or cash
+	basis, err := parseReportBasis(c.Query("basis"))
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+	req.Basis = basis
+
 	report, err := h.service.GetProfitAndLossByUnit(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
+		return
+	}
+
+	h.respond(c, "profit-and-loss-by-unit", req.BuildingID, report)
+}
+
+// GET /reports/general-ledger
+func (h *ReportsHandler) GetGeneralLedger(c *gin.Context) {
+	var req GeneralLedgerRequest
+
+	// Get building ID from route parameter
+	buildingIDStr := c.Param("id")
+	if buildingIDStr == "" {
+		buildingIDStr = c.Query("building_id")
+	}
+	if buildingIDStr != "" {
+		buildingID, err := strconv.Atoi(buildingIDStr)
+		if err == nil {
+			req.BuildingID = buildingID
+		}
+	}
+
+	req.StartDate = c.Query("start_date")
+	req.EndDate = c.Query("end_date")
+
+	if req.BuildingID <= 0 {
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
+		return
+	}
+
+	if req.StartDate == "" || req.EndDate == "" {
+		apperrors.Respond(c, apperrors.BadRequest("Start date and end date are required"))
+		return
+	}
+
+	report, err := h.service.GetGeneralLedger(req)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	h.respond(c, "general-ledger", req.BuildingID, report)
+}
+
+// GET /reports/journal
+func (h *ReportsHandler) GetJournalReport(c *gin.Context) {
+	var req JournalReportRequest
+
+	// Get building ID from route parameter
+	buildingIDStr := c.Param("id")
+	if buildingIDStr == "" {
+		buildingIDStr = c.Query("building_id")
+	}
+	if buildingIDStr != "" {
+		buildingID, err := strconv.Atoi(buildingIDStr)
+		if err == nil {
+			req.BuildingID = buildingID
+		}
+	}
+
+	req.StartDate = c.Query("start_date")
+	req.EndDate = c.Query("end_date")
+
+	if req.BuildingID <= 0 {
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
+		return
+	}
+
+	if req.StartDate == "" || req.EndDate == "" {
+		apperrors.Respond(c, apperrors.BadRequest("Start date and end date are required"))
+		return
+	}
+
+	report, err := h.service.GetJournalReport(req)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	h.respond(c, "journal", req.BuildingID, report)
+}
+
+// GET /reports/budget-vs-actual
+func (h *ReportsHandler) GetBudgetVsActual(c *gin.Context) {
+	var req BudgetVsActualRequest
+
+	// Get building ID from route parameter
+	buildingIDStr := c.Param("id")
+	if buildingIDStr == "" {
+		buildingIDStr = c.Query("building_id")
+	}
+	if buildingIDStr != "" {
+		buildingID, err := strconv.Atoi(buildingIDStr)
+		if err == nil {
+			req.BuildingID = buildingID
+		}
+	}
+
+	if yearStr := c.Query("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err == nil {
+			req.Year = year
+		}
+	}
+
+	if throughMonthStr := c.Query("through_month"); throughMonthStr != "" {
+		throughMonth, err := strconv.Atoi(throughMonthStr)
+		if err == nil {
+			req.ThroughMonth = throughMonth
+		}
+	}
+
+	if unitIDStr := c.Query("unit_id"); unitIDStr != "" {
+		unitID, err := strconv.Atoi(unitIDStr)
+		if err == nil {
+			req.UnitID = &unitID
+		}
+	}
+
+	if req.BuildingID <= 0 {
+		apperrors.Respond(c, apperrors.BadRequest("Building ID is required"))
+		return
+	}
+
+	if req.Year <= 0 {
+		apperrors.Respond(c, apperrors.BadRequest("Year is required"))
+		return
+	}
+
+	report, err := h.service.GetBudgetVsActual(req)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	h.respond(c, "budget-vs-actual", req.BuildingID, report)
+}
+
+// ParseCustomerStatementRequest reads a statement request from the building route parameter
+// and the people_id, unit_id, start_date and end_date query parameters
+func ParseCustomerStatementRequest(c *gin.Context) (CustomerStatementRequest, error) {
+	var req CustomerStatementRequest
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return req, apperrors.BadRequest("Invalid Building ID")
+	}
+	req.BuildingID = buildingID
+
+	if peopleIDStr := c.Query("people_id"); peopleIDStr != "" {
+		peopleID, err := strconv.Atoi(peopleIDStr)
+		if err != nil {
+			return req, apperrors.BadRequest("Invalid People ID")
+		}
+		req.PeopleID = &peopleID
+	}
+
+	if unitIDStr := c.Query("unit_id"); unitIDStr != "" {
+		unitID, err := strconv.Atoi(unitIDStr)
+		if err != nil {
+			return req, apperrors.BadRequest("Invalid Unit ID")
+		}
+		req.UnitID = &unitID
+	}
+
+	req.StartDate = c.Query("start_date")
+	req.EndDate = c.Query("end_date")
+	return req, nil
+}
+
+// GET /reports/customer-statement
+func (h *ReportsHandler) GetCustomerStatement(c *gin.Context) {
+	req, err := ParseCustomerStatementRequest(c)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	statement, err := h.service.GetCustomerStatement(req)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// GET /reports/customer-statements
+func (h *ReportsHandler) GetCustomerStatements(c *gin.Context) {
+	req, err := ParseCustomerStatementRequest(c)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	statements, err := h.service.GetCustomerStatements(req)
+	if err != nil {
+		apperrors.Respond(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, report)
+	c.JSON(http.StatusOK, statements)
 }
@@ -0,0 +1,111 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/jobs"
+)
+
+// SnapshotJobParams are the params of a report_snapshot job
+type SnapshotJobParams struct {
+	Report string `json:"report"` // balance_sheet, trial_balance, profit_and_loss or customer_balances
+	Basis  string `json:"basis"`  // accrual (default) or cash; ignored by customer_balances
+	Period string `json:"period"` // month_to_date (default), previous_month, year_to_date or previous_year
+}
+
+var snapshotReports = map[string]bool{"balance_sheet": true, "trial_balance": true, "profit_and_loss": true, "customer_balances": true}
+
+// SnapshotJob is a job type that runs a report for the job's building on a schedule, e.g.
+// a month-end trial balance. The report is stored as the run's output.
+func SnapshotJob(service *ReportsService) jobs.Type {
+	return jobs.Type{
+		Name:        "report_snapshot",
+		Description: "Runs a balance sheet, trial balance, profit and loss or customer balance report and keeps it as the run's output",
+		Validate: func(raw json.RawMessage) map[string]string {
+			_, validationErrors := parseSnapshotParams(raw)
+			return validationErrors
+		},
+		Run: func(ctx context.Context, job jobs.Job) (string, error) {
+			params, validationErrors := parseSnapshotParams(job.Params)
+			if validationErrors != nil {
+				return "", fmt.Errorf("invalid params: %v", validationErrors)
+			}
+			loc, err := time.LoadLocation(job.Timezone)
+			if err != nil {
+				return "", err
+			}
+			start, end := snapshotPeriod(params.Period, time.Now().In(loc))
+
+			var report interface{}
+			switch params.Report {
+			case "balance_sheet":
+				report, err = service.GetBalanceSheet(BalanceSheetRequest{BuildingID: job.BuildingID, AsOfDate: end, Basis: params.Basis})
+			case "trial_balance":
+				report, err = service.GetTrialBalance(TrialBalanceRequest{BuildingID: job.BuildingID, AsOfDate: end, Basis: params.Basis})
+			case "profit_and_loss":
+				report, err = service.GetProfitAndLossStandard(ProfitAndLossStandardRequest{BuildingID: job.BuildingID, StartDate: start, EndDate: end, Basis: params.Basis})
+			case "customer_balances":
+				report, err = service.GetCustomerBalanceSummary(CustomerBalanceSummaryRequest{BuildingID: job.BuildingID, AsOfDate: end})
+			}
+			if err != nil {
+				return "", err
+			}
+
+			output, err := json.Marshal(report)
+			if err != nil {
+				return "", err
+			}
+			return string(output), nil
+		},
+	}
+}
+
+func parseSnapshotParams(raw json.RawMessage) (SnapshotJobParams, map[string]string) {
+	var params SnapshotJobParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return params, map[string]string{"report": "Params must be an object with report, basis and period"}
+	}
+
+	validationErrors := map[string]string{}
+	if !snapshotReports[params.Report] {
+		validationErrors["report"] = "Report must be balance_sheet, trial_balance, profit_and_loss or customer_balances"
+	}
+	switch params.Basis {
+	case "", ReportBasisAccrual, ReportBasisCash:
+	default:
+		validationErrors["basis"] = "Basis must be accrual or cash"
+	}
+	switch params.Period {
+	case "":
+		params.Period = "month_to_date"
+	case "month_to_date", "previous_month", "year_to_date", "previous_year":
+	default:
+		validationErrors["period"] = "Period must be month_to_date, previous_month, year_to_date or previous_year"
+	}
+	if len(validationErrors) > 0 {
+		return params, validationErrors
+	}
+	return params, nil
+}
+
+// snapshotPeriod returns the first and last dates of period relative to today
+func snapshotPeriod(period string, today time.Time) (string, string) {
+	const layout = "2006-01-02"
+	year, month, _ := today.Date()
+	loc := today.Location()
+
+	switch period {
+	case "previous_month":
+		first := time.Date(year, month-1, 1, 0, 0, 0, 0, loc)
+		return first.Format(layout), first.AddDate(0, 1, -1).Format(layout)
+	case "year_to_date":
+		return time.Date(year, 1, 1, 0, 0, 0, 0, loc).Format(layout), today.Format(layout)
+	case "previous_year":
+		return time.Date(year-1, 1, 1, 0, 0, 0, 0, loc).Format(layout), time.Date(year-1, 12, 31, 0, 0, 0, 0, loc).Format(layout)
+	default:
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc).Format(layout), today.Format(layout)
+	}
+}
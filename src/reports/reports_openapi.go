@@ -0,0 +1,102 @@
+package reports
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var (
+	asOfDateParam  = openapi.Param{Name: "as_of_date", Format: "date"}
+	startDateParam = openapi.Param{Name: "start_date", Format: "date", Required: true}
+	endDateParam   = openapi.Param{Name: "end_date", Format: "date", Required: true}
+	basisParam     = openapi.Param{Name: "basis", Enum: []string{ReportBasisAccrual, ReportBasisCash}, Description: "Accounting basis (default accrual)"}
+	unitIDParam    = openapi.Param{Name: "unit_id", Type: "integer", Format: "int32"}
+)
+
+// StatementQuery are the query parameters of customer statements, shared with their PDF downloads
+var StatementQuery = []openapi.Param{
+	startDateParam, endDateParam,
+	{Name: "unit_id", Type: "integer", Format: "int32", Description: "Only include activity of this unit"},
+}
+
+var OpenAPI = openapi.Handlers{
+	"ReportsHandler.GetBalanceSheet": {
+		Summary:     "Balance sheet",
+		Description: "With columns set, returns balance sheets at several dates side by side.",
+		Export:      true,
+		Query: []openapi.Param{
+			asOfDateParam, basisParam,
+			{Name: "columns", Enum: []string{"months", "quarters", "years", "prior_year", "dates"}, Description: "Compare several dates"},
+			{Name: "start_date", Format: "date", Description: "Start of the comparison range"},
+			{Name: "end_date", Format: "date", Description: "End of the comparison range"},
+			{Name: "as_of_dates", Description: "Comma-separated dates when columns is dates"},
+		},
+		Response: openapi.OneOf{BalanceSheetResponse{}, ComparativeBalanceSheetResponse{}},
+	},
+	"ReportsHandler.GetTrialBalance": {
+		Summary: "Trial balance", Export: true,
+		Query:    []openapi.Param{asOfDateParam, basisParam},
+		Response: TrialBalanceResponse{},
+	},
+	"ReportsHandler.GetTransactionDetailsByAccount": {
+		Summary: "Transaction details by account", Export: true,
+		Query: []openapi.Param{
+			startDateParam, endDateParam, unitIDParam,
+			{Name: "account_id", Type: "integer", Format: "int32", Array: true, Description: "Limit to these accounts"},
+		},
+		Response: TransactionDetailsByAccountResponse{},
+	},
+	"ReportsHandler.GetCustomerBalanceSummary": {
+		Summary: "Customer balance summary", Export: true,
+		Query:    []openapi.Param{asOfDateParam},
+		Response: CustomerBalanceSummaryResponse{},
+	},
+	"ReportsHandler.GetCustomerBalanceDetails": {
+		Summary: "Customer balance details", Export: true,
+		Query:    []openapi.Param{asOfDateParam, {Name: "people_id", Type: "integer", Format: "int32"}},
+		Response: CustomerBalanceDetailsResponse{},
+	},
+	"ReportsHandler.GetCustomerStatement": {
+		Summary:     "Customer statement of account",
+		Description: "Opening balance, every invoice, payment, discount, credit memo, applied credit and sales receipt in the range with a running balance, and the aging of the open invoices at the end date. Sales receipts and applied credits do not change the balance.",
+		Query:       append([]openapi.Param{{Name: "people_id", Type: "integer", Format: "int32", Required: true}}, StatementQuery...),
+		Response:    CustomerStatement{},
+	},
+	"ReportsHandler.GetCustomerStatements": {
+		Summary:     "Statements of all customers",
+		Description: "One statement per customer with an opening balance, activity in the range or a closing balance.",
+		Query:       StatementQuery,
+		Response:    CustomerStatementsResponse{},
+	},
+	"ReportsHandler.GetProfitAndLossStandard": {
+		Summary:     "Profit and loss",
+		Description: "With columns set, returns a matrix of accounts by period.",
+		Export:      true,
+		Query: []openapi.Param{
+			startDateParam, endDateParam, basisParam,
+			{Name: "columns", Enum: []string{"months", "quarters", "years", "prior_year"}, Description: "Break the range into columns"},
+		},
+		Response: openapi.OneOf{ProfitAndLossStandardResponse{}, ComparativeProfitAndLossResponse{}},
+	},
+	"ReportsHandler.GetProfitAndLossByUnit": {
+		Summary: "Profit and loss by unit", Export: true,
+		Query:    []openapi.Param{startDateParam, endDateParam, basisParam},
+		Response: ProfitAndLossByUnitResponse{},
+	},
+	"ReportsHandler.GetGeneralLedger": {
+		Summary: "General ledger", Export: true,
+		Query:    []openapi.Param{startDateParam, endDateParam},
+		Response: GeneralLedgerResponse{},
+	},
+	"ReportsHandler.GetJournalReport": {
+		Summary: "Journal", Export: true,
+		Query:    []openapi.Param{startDateParam, endDateParam},
+		Response: JournalReportResponse{},
+	},
+	"ReportsHandler.GetBudgetVsActual": {
+		Summary: "Budget vs. actual", Export: true,
+		Query: []openapi.Param{
+			{Name: "year", Type: "integer", Format: "int32", Required: true},
+			{Name: "through_month", Type: "integer", Format: "int32", Description: "Last month to include, 1-12"},
+			unitIDParam,
+		},
+		Response: BudgetVsActualResponse{},
+	},
+}
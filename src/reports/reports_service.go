@@ -4,11 +4,15 @@ import (
 	"database/sql"
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mysecodgit/go_accounting/src/account_types"
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/budgets"
 	"github.com/mysecodgit/go_accounting/src/invoice_payments"
 	"github.com/mysecodgit/go_accounting/src/invoices"
 	"github.com/mysecodgit/go_accounting/src/people"
@@ -25,6 +29,7 @@ type ReportsService struct {
 	paymentRepo     invoice_payments.InvoicePaymentRepository
 	peopleRepo      people.PersonRepository
 	peopleTypeRepo  people_types.PeopleTypeRepository
+	budgetRepo      budgets.BudgetRepository
 	db              *sql.DB
 }
 
@@ -36,6 +41,7 @@ func NewReportsService(
 	paymentRepo invoice_payments.InvoicePaymentRepository,
 	peopleRepo people.PersonRepository,
 	peopleTypeRepo people_types.PeopleTypeRepository,
+	budgetRepo budgets.BudgetRepository,
 	db *sql.DB,
 ) *ReportsService {
 	return &ReportsService{
@@ -46,6 +52,7 @@ func NewReportsService(
 		paymentRepo:     paymentRepo,
 		peopleRepo:      peopleRepo,
 		peopleTypeRepo:  peopleTypeRepo,
+		budgetRepo:      budgetRepo,
 		db:              db,
 	}
 }
@@ -58,20 +65,33 @@ func (s *ReportsService) GetBalanceSheet(req BalanceSheetRequest) (*BalanceSheet
 		asOfDate = time.Now().Format("2006-01-02")
 	}
 
+	basis, err := parseReportBasis(req.Basis)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all accounts for the building
 	accountsList, accountTypes, _, err := s.accountRepo.GetByBuildingID(req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get accounts: %v", err)
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	var adjustments *cashBasisAdjustments
+	if basis == ReportBasisCash {
+		adjustments, err = s.getCashBasisAdjustments(req.BuildingID, "", asOfDate)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Calculate balances for each account up to asOfDate
 	accountBalances := make(map[int]float64)
-	for _, account := range accountsList {
+	for i, account := range accountsList {
 		balance, err := s.calculateAccountBalance(account.ID, asOfDate)
 		if err != nil {
-			return nil, fmt.Errorf("failed to calculate balance for account %d: %v", account.ID, err)
+			return nil, fmt.Errorf("failed to calculate balance for account %d: %w", account.ID, err)
 		}
-		accountBalances[account.ID] = balance
+		accountBalances[account.ID] = adjustBalanceForBasis(balance, accountTypes[i], adjustments.netDebit(account.ID, nil))
 	}
 
 	// Categorize accounts into Assets, Liabilities, Equity, Income, and Expense
@@ -179,6 +199,7 @@ func (s *ReportsService) GetBalanceSheet(req BalanceSheetRequest) (*BalanceSheet
 	return &BalanceSheetResponse{
 		BuildingID:                req.BuildingID,
 		AsOfDate:                  asOfDate,
+		Basis:                     basis,
 		Assets:                    BalanceSheetSection{SectionName: "Assets", Accounts: assets, Total: totalAssets},
 		Liabilities:               BalanceSheetSection{SectionName: "Liabilities", Accounts: liabilities, Total: totalLiabilities},
 		Equity:                    BalanceSheetSection{SectionName: "Equity", Accounts: equity, Total: totalEquity},
@@ -300,7 +321,7 @@ func (s *ReportsService) FindPeopleTypeByTitle(title string) (int, error) {
 		}
 	}
 
-	return 0, fmt.Errorf("people type '%s' not found", title)
+	return 0, apperrors.NotFound(fmt.Sprintf("people type '%s'", title))
 }
 
 // findAccountTypeByName finds an account type by typeName (case-insensitive)
@@ -314,7 +335,7 @@ func (s *ReportsService) findAccountTypeByName(typeName string) (int, error) {
 	var accountTypeID int
 	err := s.db.QueryRow(query, "%"+typeName+"%").Scan(&accountTypeID)
 	if err != nil {
-		return 0, fmt.Errorf("account type '%s' not found: %v", typeName, err)
+		return 0, apperrors.Lookup(fmt.Sprintf("account type '%s'", typeName), err)
 	}
 
 	return accountTypeID, nil
@@ -322,10 +343,27 @@ func (s *ReportsService) findAccountTypeByName(typeName string) (int, error) {
 
 // GetTrialBalance generates a trial balance report
 func (s *ReportsService) GetTrialBalance(req TrialBalanceRequest) (*TrialBalanceResponse, error) {
+	basis, err := parseReportBasis(req.Basis)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all accounts for the building
 	accountsList, accountTypesList, _, err := s.accountRepo.GetByBuildingID(req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get accounts: %v", err)
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	var adjustments *cashBasisAdjustments
+	if basis == ReportBasisCash {
+		asOfDate := req.AsOfDate
+		if asOfDate == "" {
+			asOfDate = time.Now().Format("2006-01-02")
+		}
+		adjustments, err = s.getCashBasisAdjustments(req.BuildingID, "", asOfDate)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	trialBalanceAccounts := []TrialBalanceAccount{}
@@ -343,6 +381,7 @@ func (s *ReportsService) GetTrialBalance(req TrialBalanceRequest) (*TrialBalance
 		if err != nil {
 			continue
 		}
+		balance = adjustBalanceForBasis(balance, accountType, adjustments.netDebit(account.ID, nil))
 
 		// Determine debit and credit balances based on account type
 		var debitBalance, creditBalance float64
@@ -401,6 +440,7 @@ func (s *ReportsService) GetTrialBalance(req TrialBalanceRequest) (*TrialBalance
 	return &TrialBalanceResponse{
 		BuildingID:  req.BuildingID,
 		AsOfDate:    req.AsOfDate,
+		Basis:       basis,
 		Accounts:    trialBalanceAccounts,
 		TotalDebit:  totalDebit,
 		TotalCredit: totalCredit,
@@ -483,7 +523,7 @@ func (s *ReportsService) calculatePersonBalanceFromSplits(peopleID int, accountI
 // GetTransactionDetailsByAccount generates a transaction details report grouped by account
 func (s *ReportsService) GetTransactionDetailsByAccount(req TransactionDetailsByAccountRequest) (*TransactionDetailsByAccountResponse, error) {
 	if req.StartDate == "" || req.EndDate == "" {
-		return nil, fmt.Errorf("start date and end date are required")
+		return nil, apperrors.BadRequest("start date and end date are required")
 	}
 
 	// Get accounts to process
@@ -498,7 +538,7 @@ func (s *ReportsService) GetTransactionDetailsByAccount(req TransactionDetailsBy
 		for _, accountID := range req.AccountIDs {
 			account, accountType, _, err := s.accountRepo.GetByID(accountID)
 			if err != nil {
-				return nil, fmt.Errorf("account not found (ID: %d): %v", accountID, err)
+				return nil, apperrors.Lookup(fmt.Sprintf("account %d", accountID), err)
 			}
 			accountsList = append(accountsList, account)
 			accountTypesList = append(accountTypesList, accountType)
@@ -507,7 +547,7 @@ func (s *ReportsService) GetTransactionDetailsByAccount(req TransactionDetailsBy
 		// Get all accounts for the building
 		accountsList, accountTypesList, _, err = s.accountRepo.GetByBuildingID(req.BuildingID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get accounts: %v", err)
+			return nil, fmt.Errorf("failed to get accounts: %w", err)
 		}
 	}
 
@@ -522,7 +562,7 @@ func (s *ReportsService) GetTransactionDetailsByAccount(req TransactionDetailsBy
 		// Get splits for this account within date range (with optional unit filter)
 		splits, err := s.splitRepo.GetByAccountIDAndDateRangeWithUnit(account.ID, req.BuildingID, req.StartDate, req.EndDate, req.UnitID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get splits for account %d: %v", account.ID, err)
+			return nil, fmt.Errorf("failed to get splits for account %d: %w", account.ID, err)
 		}
 
 		if len(splits) == 0 {
@@ -532,7 +572,7 @@ func (s *ReportsService) GetTransactionDetailsByAccount(req TransactionDetailsBy
 		// Get initial balance before start date (using typeStatus)
 		initialBalance, err := s.calculateAccountBalanceBeforeDate(account.ID, req.StartDate)
 		if err != nil {
-			return nil, fmt.Errorf("failed to calculate initial balance for account %d: %v", account.ID, err)
+			return nil, fmt.Errorf("failed to calculate initial balance for account %d: %w", account.ID, err)
 		}
 
 		// Get account type status to determine how balance changes
@@ -548,7 +588,7 @@ func (s *ReportsService) GetTransactionDetailsByAccount(req TransactionDetailsBy
 			// Get transaction details
 			transaction, err := s.transactionRepo.GetByID(split.TransactionID)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get transaction %d: %v", split.TransactionID, err)
+				return nil, fmt.Errorf("failed to get transaction %d: %w", split.TransactionID, err)
 			}
 
 			// Get people name if people_id exists
@@ -654,7 +694,7 @@ func (s *ReportsService) GetCustomerBalanceSummary(req CustomerBalanceSummaryReq
 	// Get all people types to find customer type
 	peopleTypes, err := s.peopleTypeRepo.GetAll()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get people types: %v", err)
+		return nil, fmt.Errorf("failed to get people types: %w", err)
 	}
 
 	// Find the "customer" people type
@@ -667,13 +707,13 @@ func (s *ReportsService) GetCustomerBalanceSummary(req CustomerBalanceSummaryReq
 	}
 
 	if customerTypeID == nil {
-		return nil, fmt.Errorf("customer people type not found")
+		return nil, apperrors.NotFound("customer people type")
 	}
 
 	// Get all people for the building
 	allPeople, peopleTypesList, _, err := s.peopleRepo.GetByBuildingID(req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get people: %v", err)
+		return nil, fmt.Errorf("failed to get people: %w", err)
 	}
 
 	// Filter to only customers
@@ -690,7 +730,7 @@ func (s *ReportsService) GetCustomerBalanceSummary(req CustomerBalanceSummaryReq
 	// Find Account Receivable account type
 	arAccountTypeID, err := s.findAccountTypeByName("Account Receivable")
 	if err != nil {
-		return nil, fmt.Errorf("failed to find Account Receivable account type: %v", err)
+		return nil, fmt.Errorf("failed to find Account Receivable account type: %w", err)
 	}
 
 	// Calculate balance for each customer from splits on Account Receivable accounts only
@@ -713,7 +753,7 @@ func (s *ReportsService) GetCustomerBalanceSummary(req CustomerBalanceSummaryReq
 		var totalDebit, totalCredit sql.NullFloat64
 		err := s.db.QueryRow(query, customer.ID, arAccountTypeID, asOfDate).Scan(&totalDebit, &totalCredit)
 		if err != nil && err != sql.ErrNoRows {
-			return nil, fmt.Errorf("failed to calculate balance for customer %d: %v", customer.ID, err)
+			return nil, fmt.Errorf("failed to calculate balance for customer %d: %w", customer.ID, err)
 		}
 
 		debitAmount := 0.0
@@ -754,40 +794,9 @@ func (s *ReportsService) GetCustomerBalanceDetails(req CustomerBalanceDetailsReq
 		asOfDate = time.Now().Format("2006-01-02")
 	}
 
-	// Get all people types to find customer type
-	peopleTypes, err := s.peopleTypeRepo.GetAll()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get people types: %v", err)
-	}
-
-	// Find the "customer" people type
-	var customerTypeID *int
-	for _, pt := range peopleTypes {
-		if strings.ToLower(pt.Title) == "customer" {
-			customerTypeID = &pt.ID
-			break
-		}
-	}
-
-	if customerTypeID == nil {
-		return nil, fmt.Errorf("customer people type not found")
-	}
-
-	// Get all people for the building
-	allPeople, peopleTypesList, _, err := s.peopleRepo.GetByBuildingID(req.BuildingID)
+	customers, err := s.findCustomers(req.BuildingID, req.PeopleID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get people: %v", err)
-	}
-
-	// Filter to only customers
-	customers := []people.Person{}
-	for i, person := range allPeople {
-		if i < len(peopleTypesList) && peopleTypesList[i].ID == *customerTypeID {
-			// If PeopleID filter is specified, only include that customer
-			if req.PeopleID == nil || *req.PeopleID == person.ID {
-				customers = append(customers, person)
-			}
-		}
+		return nil, err
 	}
 
 	customerDetails := []CustomerBalanceDetails{}
@@ -798,41 +807,15 @@ func (s *ReportsService) GetCustomerBalanceDetails(req CustomerBalanceDetailsReq
 	// Find Account Receivable account type
 	arAccountTypeID, err := s.findAccountTypeByName("Account Receivable")
 	if err != nil {
-		return nil, fmt.Errorf("failed to find Account Receivable account type: %v", err)
+		return nil, fmt.Errorf("failed to find Account Receivable account type: %w", err)
 	}
 
 	// Process each customer
 	for _, customer := range customers {
-		// Get all splits for this customer up to asOfDate, only for Account Receivable accounts, ordered by transaction date
-		query := `
-			SELECT 
-				s.id as split_id,
-				s.transaction_id,
-				s.account_id,
-				s.debit,
-				s.credit,
-				t.transaction_date,
-				t.type as transaction_type,
-				t.transaction_number,
-				t.memo as transaction_memo,
-				a.account_name,
-				a.account_number
-			FROM splits s
-			INNER JOIN transactions t ON s.transaction_id = t.id
-			INNER JOIN accounts a ON s.account_id = a.id
-			WHERE s.people_id = ?
-				AND a.account_type = ?
-				AND s.status = '1'
-				AND t.status = '1'
-				AND DATE(t.transaction_date) <= ?
-			ORDER BY t.transaction_date, t.id, s.id
-		`
-
-		rows, err := s.db.Query(query, customer.ID, arAccountTypeID, asOfDate)
+		customerSplits, err := s.customerReceivableSplits(customer.ID, arAccountTypeID, asOfDate, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get splits for customer %d: %v", customer.ID, err)
+			return nil, err
 		}
-		defer rows.Close()
 
 		// Group splits by account
 		accountMap := make(map[int]*CustomerBalanceAccount)
@@ -842,32 +825,7 @@ func (s *ReportsService) GetCustomerBalanceDetails(req CustomerBalanceDetailsReq
 		customerTotalCredit := 0.0
 		customerRunningBalance := 0.0
 
-		for rows.Next() {
-			var split CustomerBalanceDetailSplit
-			var accountNumber sql.NullInt64
-			var debit, credit sql.NullFloat64
-
-			err := rows.Scan(
-				&split.SplitID,
-				&split.TransactionID,
-				&split.AccountID,
-				&debit,
-				&credit,
-				&split.TransactionDate,
-				&split.TransactionType,
-				&split.TransactionNumber,
-				&split.TransactionMemo,
-				&split.AccountName,
-				&accountNumber,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("failed to scan split: %v", err)
-			}
-
-			if accountNumber.Valid {
-				split.AccountNumber = int(accountNumber.Int64)
-			}
-
+		for _, split := range customerSplits {
 			// Initialize account if not exists
 			if _, exists := accountMap[split.AccountID]; !exists {
 				accountMap[split.AccountID] = &CustomerBalanceAccount{
@@ -885,20 +843,16 @@ func (s *ReportsService) GetCustomerBalanceDetails(req CustomerBalanceDetailsReq
 
 			account := accountMap[split.AccountID]
 
-			if debit.Valid {
-				debitValue := debit.Float64
-				split.Debit = &debitValue
-				account.TotalDebit += debit.Float64
-				customerTotalDebit += debit.Float64
-				customerRunningBalance += debit.Float64
+			if split.Debit != nil {
+				account.TotalDebit += *split.Debit
+				customerTotalDebit += *split.Debit
+				customerRunningBalance += *split.Debit
 			}
 
-			if credit.Valid {
-				creditValue := credit.Float64
-				split.Credit = &creditValue
-				account.TotalCredit += credit.Float64
-				customerTotalCredit += credit.Float64
-				customerRunningBalance -= credit.Float64
+			if split.Credit != nil {
+				account.TotalCredit += *split.Credit
+				customerTotalCredit += *split.Credit
+				customerRunningBalance -= *split.Credit
 			}
 
 			// Running balance is calculated at customer level, not account level
@@ -959,6 +913,123 @@ func (s *ReportsService) GetCustomerBalanceDetails(req CustomerBalanceDetailsReq
 	}, nil
 }
 
+// findCustomers returns the building's people of the customer type, or only peopleID when set
+func (s *ReportsService) findCustomers(buildingID int, peopleID *int) ([]people.Person, error) {
+	// Get all people types to find customer type
+	peopleTypes, err := s.peopleTypeRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get people types: %w", err)
+	}
+
+	// Find the "customer" people type
+	var customerTypeID *int
+	for _, pt := range peopleTypes {
+		if strings.ToLower(pt.Title) == "customer" {
+			customerTypeID = &pt.ID
+			break
+		}
+	}
+
+	if customerTypeID == nil {
+		return nil, apperrors.NotFound("customer people type")
+	}
+
+	// Get all people for the building
+	allPeople, peopleTypesList, _, err := s.peopleRepo.GetByBuildingID(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get people: %w", err)
+	}
+
+	// Filter to only customers
+	customers := []people.Person{}
+	for i, person := range allPeople {
+		if i < len(peopleTypesList) && peopleTypesList[i].ID == *customerTypeID {
+			// If PeopleID filter is specified, only include that customer
+			if peopleID == nil || *peopleID == person.ID {
+				customers = append(customers, person)
+			}
+		}
+	}
+	return customers, nil
+}
+
+// customerReceivableSplits returns a customer's splits on Account Receivable accounts up to
+// asOfDate in posting order, optionally limited to one unit. Balance is left at zero.
+func (s *ReportsService) customerReceivableSplits(peopleID int, arAccountTypeID int, asOfDate string, unitID *int) ([]CustomerBalanceDetailSplit, error) {
+	query := `
+		SELECT 
+			s.id as split_id,
+			s.transaction_id,
+			s.account_id,
+			s.debit,
+			s.credit,
+			t.transaction_date,
+			t.type as transaction_type,
+			t.transaction_number,
+			t.memo as transaction_memo,
+			a.account_name,
+			a.account_number
+		FROM splits s
+		INNER JOIN transactions t ON s.transaction_id = t.id
+		INNER JOIN accounts a ON s.account_id = a.id
+		WHERE s.people_id = ?
+			AND a.account_type = ?
+			AND s.status = '1'
+			AND t.status = '1'
+			AND DATE(t.transaction_date) <= ?
+	`
+	args := []interface{}{peopleID, arAccountTypeID, asOfDate}
+	if unitID != nil {
+		query += " AND s.unit_id = ?"
+		args = append(args, *unitID)
+	}
+	query += " ORDER BY t.transaction_date, t.id, s.id"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get splits for customer %d: %w", peopleID, err)
+	}
+	defer rows.Close()
+
+	result := []CustomerBalanceDetailSplit{}
+	for rows.Next() {
+		var split CustomerBalanceDetailSplit
+		var accountNumber sql.NullInt64
+		var debit, credit sql.NullFloat64
+
+		err := rows.Scan(
+			&split.SplitID,
+			&split.TransactionID,
+			&split.AccountID,
+			&debit,
+			&credit,
+			&split.TransactionDate,
+			&split.TransactionType,
+			&split.TransactionNumber,
+			&split.TransactionMemo,
+			&split.AccountName,
+			&accountNumber,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan split: %w", err)
+		}
+
+		if accountNumber.Valid {
+			split.AccountNumber = int(accountNumber.Int64)
+		}
+		if debit.Valid {
+			debitValue := debit.Float64
+			split.Debit = &debitValue
+		}
+		if credit.Valid {
+			creditValue := credit.Float64
+			split.Credit = &creditValue
+		}
+		result = append(result, split)
+	}
+	return result, rows.Err()
+}
+
 // calculateAccountBalanceForDateRange calculates the balance of an account for a specific date range
 // unitID can be:
 //   - nil: all splits (no unit filter)
@@ -1027,16 +1098,194 @@ func (s *ReportsService) calculateAccountBalanceForDateRange(accountID int, star
 	}
 }
 
+// parseReportBasis validates a report basis, defaulting to accrual when empty
+func parseReportBasis(basis string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(basis)) {
+	case "", ReportBasisAccrual:
+		return ReportBasisAccrual, nil
+	case ReportBasisCash:
+		return ReportBasisCash, nil
+	default:
+		return "", apperrors.BadRequestf("basis must be %s or %s", ReportBasisAccrual, ReportBasisCash)
+	}
+}
+
+// cashBasisAdjustments holds the net debit (debit - credit) to add to accrual balances so that
+// invoice postings are only recognized as the invoice is settled
+type cashBasisAdjustments struct {
+	byAccount     map[int]float64
+	byAccountUnit map[int]map[int]float64 // unit 0 collects splits without a unit
+}
+
+// netDebit returns the adjustment for an account, using the same unit filter semantics as
+// calculateAccountBalanceForDateRange (nil = all units, 0 = no unit)
+func (a *cashBasisAdjustments) netDebit(accountID int, unitID *int) float64 {
+	if a == nil {
+		return 0
+	}
+	if unitID == nil {
+		return a.byAccount[accountID]
+	}
+	return a.byAccountUnit[accountID][*unitID]
+}
+
+// adjustBalanceForBasis applies a net debit adjustment to a balance signed by the account's typeStatus
+func adjustBalanceForBasis(balance float64, accountType account_types.AccountType, netDebit float64) float64 {
+	if strings.ToLower(accountType.TypeStatus) == "debit" {
+		return balance + netDebit
+	}
+	return balance - netDebit
+}
+
+// getCashBasisAdjustments calculates cash basis adjustments for a building.
+// Every split of an invoice transaction is recognized in proportion to how much of the invoice
+// has been settled by payments, applied credits and applied discounts, so income splits are
+// recognized when paid and the matching A/R debit is deferred by the same amount.
+// An empty startDate means "everything up to endDate" (balance sheet and trial balance).
+func (s *ReportsService) getCashBasisAdjustments(buildingID int, startDate string, endDate string) (*cashBasisAdjustments, error) {
+	type settlement struct {
+		Date   string
+		Amount float64
+	}
+	settlements := make(map[int][]settlement)
+
+	settlementQuery := `
+		SELECT p.invoice_id, DATE(p.date), p.amount
+		FROM invoice_payments p
+		INNER JOIN invoices i ON p.invoice_id = i.id
+		WHERE i.building_id = ? AND p.status = '1'
+		UNION ALL
+		SELECT c.invoice_id, DATE(c.date), c.amount
+		FROM invoice_applied_credits c
+		INNER JOIN invoices i ON c.invoice_id = i.id
+		WHERE i.building_id = ? AND c.status = '1'
+		UNION ALL
+		SELECT d.invoice_id, DATE(d.date), d.amount
+		FROM invoice_applied_discounts d
+		INNER JOIN invoices i ON d.invoice_id = i.id
+		WHERE i.building_id = ? AND d.status = '1'
+	`
+	rows, err := s.db.Query(settlementQuery, buildingID, buildingID, buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice settlements: %w", err)
+	}
+	for rows.Next() {
+		var invoiceID int
+		var item settlement
+		if err := rows.Scan(&invoiceID, &item.Date, &item.Amount); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan invoice settlement: %w", err)
+		}
+		settlements[invoiceID] = append(settlements[invoiceID], item)
+	}
+	rows.Close()
+
+	// settledRatio returns the share of an invoice settled up to (inclusive) or before (exclusive) a date
+	settledRatio := func(invoiceID int, invoiceAmount float64, date string, inclusive bool) float64 {
+		settled := 0.0
+		for _, item := range settlements[invoiceID] {
+			if item.Date < date || (inclusive && item.Date == date) {
+				settled += item.Amount
+			}
+		}
+		ratio := settled / invoiceAmount
+		if ratio < 0 {
+			return 0
+		}
+		if ratio > 1 {
+			return 1
+		}
+		return ratio
+	}
+
+	splitQuery := `
+		SELECT i.id, i.amount, DATE(t.transaction_date), s.account_id, s.unit_id,
+			COALESCE(s.debit, 0), COALESCE(s.credit, 0)
+		FROM invoices i
+		INNER JOIN transactions t ON i.transaction_id = t.id
+		INNER JOIN splits s ON s.transaction_id = t.id
+		WHERE i.building_id = ?
+			AND i.status = '1'
+			AND t.status = '1'
+			AND s.status = '1'
+	`
+	rows, err = s.db.Query(splitQuery, buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice splits: %w", err)
+	}
+	defer rows.Close()
+
+	adjustments := &cashBasisAdjustments{
+		byAccount:     make(map[int]float64),
+		byAccountUnit: make(map[int]map[int]float64),
+	}
+	for rows.Next() {
+		var invoiceID, accountID int
+		var invoiceAmount, debit, credit float64
+		var invoiceDate string
+		var unitID sql.NullInt64
+		if err := rows.Scan(&invoiceID, &invoiceAmount, &invoiceDate, &accountID, &unitID, &debit, &credit); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice split: %w", err)
+		}
+		// Credit notes and zero invoices have nothing to wait for
+		if invoiceAmount <= 0 {
+			continue
+		}
+
+		net := debit - credit
+
+		accrual := 0.0
+		if invoiceDate <= endDate && (startDate == "" || invoiceDate >= startDate) {
+			accrual = net
+		}
+
+		recognizedRatio := settledRatio(invoiceID, invoiceAmount, endDate, true)
+		if startDate != "" {
+			recognizedRatio -= settledRatio(invoiceID, invoiceAmount, startDate, false)
+		}
+
+		delta := net*recognizedRatio - accrual
+		if delta == 0 {
+			continue
+		}
+
+		unitKey := 0
+		if unitID.Valid {
+			unitKey = int(unitID.Int64)
+		}
+		adjustments.byAccount[accountID] += delta
+		if adjustments.byAccountUnit[accountID] == nil {
+			adjustments.byAccountUnit[accountID] = make(map[int]float64)
+		}
+		adjustments.byAccountUnit[accountID][unitKey] += delta
+	}
+
+	return adjustments, nil
+}
+
 // GetProfitAndLossStandard generates a standard profit and loss report
 func (s *ReportsService) GetProfitAndLossStandard(req ProfitAndLossStandardRequest) (*ProfitAndLossStandardResponse, error) {
 	if req.StartDate == "" || req.EndDate == "" {
-		return nil, fmt.Errorf("start date and end date are required")
+		return nil, apperrors.BadRequest("start date and end date are required")
+	}
+
+	basis, err := parseReportBasis(req.Basis)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get all accounts for the building
 	accountsList, accountTypes, _, err := s.accountRepo.GetByBuildingID(req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get accounts: %v", err)
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	var adjustments *cashBasisAdjustments
+	if basis == ReportBasisCash {
+		adjustments, err = s.getCashBasisAdjustments(req.BuildingID, req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	incomeAccounts := []ProfitAndLossAccount{}
@@ -1058,6 +1307,7 @@ func (s *ReportsService) GetProfitAndLossStandard(req ProfitAndLossStandardReque
 		if err != nil {
 			continue // Skip accounts with errors
 		}
+		balance = adjustBalanceForBasis(balance, accountType, adjustments.netDebit(account.ID, nil))
 
 		// Skip accounts with 0 balance
 		if balance == 0 {
@@ -1086,6 +1336,7 @@ func (s *ReportsService) GetProfitAndLossStandard(req ProfitAndLossStandardReque
 		BuildingID:    req.BuildingID,
 		StartDate:     req.StartDate,
 		EndDate:       req.EndDate,
+		Basis:         basis,
 		Income:        ProfitAndLossSection{SectionName: "Income", Accounts: incomeAccounts, Total: totalIncome},
 		Expenses:      ProfitAndLossSection{SectionName: "Expenses", Accounts: expenseAccounts, Total: totalExpenses},
 		NetProfitLoss: netProfitLoss,
@@ -1095,14 +1346,19 @@ func (s *ReportsService) GetProfitAndLossStandard(req ProfitAndLossStandardReque
 // GetProfitAndLossByUnit generates a profit and loss report grouped by unit (pivot table style)
 func (s *ReportsService) GetProfitAndLossByUnit(req ProfitAndLossByUnitRequest) (*ProfitAndLossByUnitResponse, error) {
 	if req.StartDate == "" || req.EndDate == "" {
-		return nil, fmt.Errorf("start date and end date are required")
+		return nil, apperrors.BadRequest("start date and end date are required")
+	}
+
+	basis, err := parseReportBasis(req.Basis)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get all units for the building
 	query := `SELECT id, name FROM units WHERE building_id = ? ORDER BY name`
 	rows, err := s.db.Query(query, req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get units: %v", err)
+		return nil, fmt.Errorf("failed to get units: %w", err)
 	}
 	defer rows.Close()
 
@@ -1122,7 +1378,15 @@ func (s *ReportsService) GetProfitAndLossByUnit(req ProfitAndLossByUnitRequest)
 	// Get all accounts for the building
 	accountsList, accountTypes, _, err := s.accountRepo.GetByBuildingID(req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get accounts: %v", err)
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	var adjustments *cashBasisAdjustments
+	if basis == ReportBasisCash {
+		adjustments, err = s.getCashBasisAdjustments(req.BuildingID, req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Build unit columns (only include units that have transactions)
@@ -1142,6 +1406,9 @@ func (s *ReportsService) GetProfitAndLossByUnit(req ProfitAndLossByUnitRequest)
 			}
 
 			balance, err := s.calculateAccountBalanceForDateRange(account.ID, req.StartDate, req.EndDate, unitID)
+			if err == nil {
+				balance = adjustBalanceForBasis(balance, accountType, adjustments.netDebit(account.ID, unitID))
+			}
 			if err == nil && balance != 0 {
 				hasData = true
 				break
@@ -1168,6 +1435,9 @@ func (s *ReportsService) GetProfitAndLossByUnit(req ProfitAndLossByUnitRequest)
 		}
 
 		balance, err := s.calculateAccountBalanceForDateRange(account.ID, req.StartDate, req.EndDate, &noUnitID)
+		if err == nil {
+			balance = adjustBalanceForBasis(balance, accountType, adjustments.netDebit(account.ID, &noUnitID))
+		}
 		if err == nil && balance != 0 {
 			hasNoUnitData = true
 			break
@@ -1206,6 +1476,9 @@ func (s *ReportsService) GetProfitAndLossByUnit(req ProfitAndLossByUnitRequest)
 				unitID = &unit.UnitID
 			}
 			balance, err := s.calculateAccountBalanceForDateRange(account.ID, req.StartDate, req.EndDate, unitID)
+			if err == nil {
+				balance = adjustBalanceForBasis(balance, accountType, adjustments.netDebit(account.ID, unitID))
+			}
 			if err == nil && balance != 0 {
 				balances[unit.UnitID] = balance
 				total += balance
@@ -1250,6 +1523,9 @@ func (s *ReportsService) GetProfitAndLossByUnit(req ProfitAndLossByUnitRequest)
 				unitID = &unit.UnitID
 			}
 			balance, err := s.calculateAccountBalanceForDateRange(account.ID, req.StartDate, req.EndDate, unitID)
+			if err == nil {
+				balance = adjustBalanceForBasis(balance, accountType, adjustments.netDebit(account.ID, unitID))
+			}
 			if err == nil && balance != 0 {
 				balances[unit.UnitID] = balance
 				total += balance
@@ -1312,6 +1588,7 @@ func (s *ReportsService) GetProfitAndLossByUnit(req ProfitAndLossByUnitRequest)
 		BuildingID:             req.BuildingID,
 		StartDate:              req.StartDate,
 		EndDate:                req.EndDate,
+		Basis:                  basis,
 		Units:                  unitColumns,
 		IncomeAccounts:         incomeAccounts,
 		ExpenseAccounts:        expenseAccounts,
@@ -1323,3 +1600,802 @@ func (s *ReportsService) GetProfitAndLossByUnit(req ProfitAndLossByUnitRequest)
 		GrandTotalNetProfitLoss: grandTotalNetProfitLoss,
 	}, nil
 }
+
+// ledgerSplitRow is a single active split joined with its transaction, account and person
+type ledgerSplitRow struct {
+	SplitID           int
+	TransactionID     int
+	TransactionNumber string
+	TransactionDate   string
+	TransactionType   string
+	TransactionMemo   string
+	AccountID         int
+	AccountNumber     int
+	AccountName       string
+	PeopleID          *int
+	PeopleName        *string
+	UnitID            *int
+	Debit             *float64
+	Credit            *float64
+}
+
+// getSplitLinesForDateRange returns all active splits of a building within a date range,
+// ordered chronologically by transaction and split
+func (s *ReportsService) getSplitLinesForDateRange(buildingID int, startDate string, endDate string) ([]ledgerSplitRow, error) {
+	query := `
+		SELECT 
+			s.id,
+			s.transaction_id,
+			t.transaction_number,
+			t.transaction_date,
+			t.type,
+			t.memo,
+			s.account_id,
+			a.account_number,
+			a.account_name,
+			s.people_id,
+			p.name,
+			s.unit_id,
+			s.debit,
+			s.credit
+		FROM splits s
+		INNER JOIN transactions t ON s.transaction_id = t.id
+		INNER JOIN accounts a ON s.account_id = a.id
+		LEFT JOIN people p ON s.people_id = p.id
+		WHERE t.building_id = ?
+			AND s.status = '1'
+			AND t.status = '1'
+			AND DATE(t.transaction_date) >= ?
+			AND DATE(t.transaction_date) <= ?
+		ORDER BY t.transaction_date, t.id, s.id
+	`
+
+	rows, err := s.db.Query(query, buildingID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []ledgerSplitRow{}
+	for rows.Next() {
+		var line ledgerSplitRow
+		var peopleName sql.NullString
+		err := rows.Scan(
+			&line.SplitID,
+			&line.TransactionID,
+			&line.TransactionNumber,
+			&line.TransactionDate,
+			&line.TransactionType,
+			&line.TransactionMemo,
+			&line.AccountID,
+			&line.AccountNumber,
+			&line.AccountName,
+			&line.PeopleID,
+			&peopleName,
+			&line.UnitID,
+			&line.Debit,
+			&line.Credit,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan split: %w", err)
+		}
+		if peopleName.Valid {
+			name := peopleName.String
+			line.PeopleName = &name
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+// GetGeneralLedger generates a general ledger covering every account of the building,
+// with opening balance, each split line with its counterpart accounts and closing balance
+func (s *ReportsService) GetGeneralLedger(req GeneralLedgerRequest) (*GeneralLedgerResponse, error) {
+	if req.StartDate == "" || req.EndDate == "" {
+		return nil, apperrors.BadRequest("start date and end date are required")
+	}
+
+	accountsList, accountTypesList, _, err := s.accountRepo.GetByBuildingID(req.BuildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	splitLines, err := s.getSplitLinesForDateRange(req.BuildingID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get splits: %w", err)
+	}
+
+	// Group split lines by account and by transaction (for counterpart lookup)
+	linesByAccount := make(map[int][]ledgerSplitRow)
+	linesByTransaction := make(map[int][]ledgerSplitRow)
+	for _, line := range splitLines {
+		linesByAccount[line.AccountID] = append(linesByAccount[line.AccountID], line)
+		linesByTransaction[line.TransactionID] = append(linesByTransaction[line.TransactionID], line)
+	}
+
+	ledgerAccounts := []GeneralLedgerAccount{}
+	grandTotalDebit := 0.0
+	grandTotalCredit := 0.0
+
+	for i, account := range accountsList {
+		accountType := accountTypesList[i]
+
+		openingBalance, err := s.calculateAccountBalanceBeforeDate(account.ID, req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate opening balance for account %d: %w", account.ID, err)
+		}
+
+		accountLines := linesByAccount[account.ID]
+
+		// Skip accounts with no opening balance and no activity in the range
+		if openingBalance == 0 && len(accountLines) == 0 {
+			continue
+		}
+
+		typeStatusLower := strings.ToLower(accountType.TypeStatus)
+		runningBalance := openingBalance
+		accountTotalDebit := 0.0
+		accountTotalCredit := 0.0
+		ledgerLines := []GeneralLedgerLine{}
+
+		for _, line := range accountLines {
+			debitAmount := 0.0
+			if line.Debit != nil {
+				debitAmount = *line.Debit
+				accountTotalDebit += debitAmount
+			}
+
+			creditAmount := 0.0
+			if line.Credit != nil {
+				creditAmount = *line.Credit
+				accountTotalCredit += creditAmount
+			}
+
+			// Debit accounts increase with debits, credit accounts increase with credits
+			if typeStatusLower == "debit" {
+				runningBalance += debitAmount - creditAmount
+			} else {
+				runningBalance += creditAmount - debitAmount
+			}
+
+			// Counterpart accounts are the other accounts in the same transaction
+			counterparts := []CounterpartAccount{}
+			seen := make(map[int]bool)
+			for _, other := range linesByTransaction[line.TransactionID] {
+				if other.AccountID == account.ID || seen[other.AccountID] {
+					continue
+				}
+				seen[other.AccountID] = true
+				counterparts = append(counterparts, CounterpartAccount{
+					AccountID:     other.AccountID,
+					AccountNumber: other.AccountNumber,
+					AccountName:   other.AccountName,
+				})
+			}
+
+			ledgerLines = append(ledgerLines, GeneralLedgerLine{
+				SplitID:             line.SplitID,
+				TransactionID:       line.TransactionID,
+				TransactionNumber:   line.TransactionNumber,
+				TransactionDate:     line.TransactionDate,
+				TransactionType:     line.TransactionType,
+				TransactionMemo:     line.TransactionMemo,
+				PeopleID:            line.PeopleID,
+				PeopleName:          line.PeopleName,
+				UnitID:              line.UnitID,
+				CounterpartAccounts: counterparts,
+				Debit:               line.Debit,
+				Credit:              line.Credit,
+				Balance:             runningBalance,
+			})
+		}
+
+		ledgerAccounts = append(ledgerAccounts, GeneralLedgerAccount{
+			AccountID:      account.ID,
+			AccountNumber:  account.AccountNumber,
+			AccountName:    account.AccountName,
+			AccountType:    accountType.TypeName,
+			OpeningBalance: openingBalance,
+			Lines:          ledgerLines,
+			TotalDebit:     accountTotalDebit,
+			TotalCredit:    accountTotalCredit,
+			ClosingBalance: runningBalance,
+		})
+
+		grandTotalDebit += accountTotalDebit
+		grandTotalCredit += accountTotalCredit
+	}
+
+	return &GeneralLedgerResponse{
+		BuildingID:       req.BuildingID,
+		StartDate:        req.StartDate,
+		EndDate:          req.EndDate,
+		Accounts:         ledgerAccounts,
+		GrandTotalDebit:  grandTotalDebit,
+		GrandTotalCredit: grandTotalCredit,
+	}, nil
+}
+
+// GetJournalReport generates a chronological listing of every transaction with all its splits
+func (s *ReportsService) GetJournalReport(req JournalReportRequest) (*JournalReportResponse, error) {
+	if req.StartDate == "" || req.EndDate == "" {
+		return nil, apperrors.BadRequest("start date and end date are required")
+	}
+
+	splitLines, err := s.getSplitLinesForDateRange(req.BuildingID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get splits: %w", err)
+	}
+
+	// Split lines are ordered by transaction, so consecutive lines share a transaction
+	journalTransactions := []JournalReportTransaction{}
+	grandTotalDebit := 0.0
+	grandTotalCredit := 0.0
+
+	for _, line := range splitLines {
+		if len(journalTransactions) == 0 || journalTransactions[len(journalTransactions)-1].TransactionID != line.TransactionID {
+			journalTransactions = append(journalTransactions, JournalReportTransaction{
+				TransactionID:     line.TransactionID,
+				TransactionNumber: line.TransactionNumber,
+				TransactionDate:   line.TransactionDate,
+				TransactionType:   line.TransactionType,
+				TransactionMemo:   line.TransactionMemo,
+				Splits:            []JournalReportSplit{},
+			})
+		}
+
+		current := &journalTransactions[len(journalTransactions)-1]
+		current.Splits = append(current.Splits, JournalReportSplit{
+			SplitID:       line.SplitID,
+			AccountID:     line.AccountID,
+			AccountNumber: line.AccountNumber,
+			AccountName:   line.AccountName,
+			PeopleID:      line.PeopleID,
+			PeopleName:    line.PeopleName,
+			UnitID:        line.UnitID,
+			Debit:         line.Debit,
+			Credit:        line.Credit,
+		})
+
+		if line.Debit != nil {
+			current.TotalDebit += *line.Debit
+			grandTotalDebit += *line.Debit
+		}
+		if line.Credit != nil {
+			current.TotalCredit += *line.Credit
+			grandTotalCredit += *line.Credit
+		}
+	}
+
+	return &JournalReportResponse{
+		BuildingID:       req.BuildingID,
+		StartDate:        req.StartDate,
+		EndDate:          req.EndDate,
+		Transactions:     journalTransactions,
+		GrandTotalDebit:  grandTotalDebit,
+		GrandTotalCredit: grandTotalCredit,
+	}, nil
+}
+
+// budgetVsActualAmounts builds a budget/actual pair with variance
+// Income variance is actual - budget, expense variance is budget - actual, so positive is always favorable
+func budgetVsActualAmounts(budget float64, actual float64, isIncome bool) BudgetVsActualAmounts {
+	budget = math.Round(budget*100) / 100
+	actual = math.Round(actual*100) / 100
+
+	variance := budget - actual
+	if isIncome {
+		variance = actual - budget
+	}
+	variance = math.Round(variance*100) / 100
+
+	var variancePercent *float64
+	if budget != 0 {
+		percent := math.Round(variance/math.Abs(budget)*10000) / 100
+		variancePercent = &percent
+	}
+
+	return BudgetVsActualAmounts{
+		Budget:          budget,
+		Actual:          actual,
+		Variance:        variance,
+		VariancePercent: variancePercent,
+	}
+}
+
+// GetBudgetVsActual compares monthly budgets with actual income and expense balances for a year
+// Actuals use the same account balance logic as the profit and loss report
+func (s *ReportsService) GetBudgetVsActual(req BudgetVsActualRequest) (*BudgetVsActualResponse, error) {
+	if req.Year < 1900 || req.Year > 9999 {
+		return nil, apperrors.BadRequest("a valid year is required")
+	}
+
+	throughMonth := req.ThroughMonth
+	if throughMonth == 0 {
+		now := time.Now()
+		if now.Year() == req.Year {
+			throughMonth = int(now.Month())
+		} else {
+			throughMonth = 12
+		}
+	}
+	if throughMonth < 1 || throughMonth > 12 {
+		return nil, apperrors.BadRequest("through month must be between 1 and 12")
+	}
+
+	// Budget lines for a single unit, or every line of the building
+	var budgetRows []budgets.Budget
+	var err error
+	if req.UnitID != nil && *req.UnitID > 0 {
+		budgetRows, err = s.budgetRepo.GetByBuildingYearAndUnit(req.BuildingID, req.Year, *req.UnitID)
+	} else {
+		budgetRows, err = s.budgetRepo.GetByBuildingAndYear(req.BuildingID, req.Year)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	// account_id -> monthly budget amounts
+	budgetByAccount := make(map[int]*[12]float64)
+	for _, b := range budgetRows {
+		if _, exists := budgetByAccount[b.AccountID]; !exists {
+			budgetByAccount[b.AccountID] = &[12]float64{}
+		}
+		budgetByAccount[b.AccountID][b.Month-1] += b.Amount
+	}
+
+	accountsList, accountTypes, _, err := s.accountRepo.GetByBuildingID(req.BuildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	incomeAccounts := []BudgetVsActualRow{}
+	expenseAccounts := []BudgetVsActualRow{}
+	var incomeBudget, incomeActual, expenseBudget, expenseActual [12]float64
+
+	for i, account := range accountsList {
+		typeLower := strings.ToLower(accountTypes[i].Type)
+
+		// Only process Income and Expense accounts
+		if typeLower != "income" && typeLower != "expense" {
+			continue
+		}
+		isIncome := typeLower == "income"
+
+		var monthBudget [12]float64
+		if amounts, ok := budgetByAccount[account.ID]; ok {
+			monthBudget = *amounts
+		}
+
+		var monthActual [12]float64
+		hasData := budgetByAccount[account.ID] != nil
+		for m := 0; m < 12; m++ {
+			startDate := time.Date(req.Year, time.Month(m+1), 1, 0, 0, 0, 0, time.UTC)
+			endDate := startDate.AddDate(0, 1, -1)
+
+			balance, err := s.calculateAccountBalanceForDateRange(account.ID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"), req.UnitID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate balance for account %d: %w", account.ID, err)
+			}
+			monthActual[m] = balance
+			if balance != 0 {
+				hasData = true
+			}
+		}
+
+		// Skip accounts with neither budget nor activity
+		if !hasData {
+			continue
+		}
+
+		row := BudgetVsActualRow{
+			AccountID:     account.ID,
+			AccountNumber: account.AccountNumber,
+			AccountName:   account.AccountName,
+			AccountType:   typeLower,
+		}
+
+		ytdBudget := 0.0
+		ytdActual := 0.0
+		for m := 0; m < 12; m++ {
+			row.Months[m] = budgetVsActualAmounts(monthBudget[m], monthActual[m], isIncome)
+			if m < throughMonth {
+				ytdBudget += monthBudget[m]
+				ytdActual += monthActual[m]
+			}
+
+			if isIncome {
+				incomeBudget[m] += monthBudget[m]
+				incomeActual[m] += monthActual[m]
+			} else {
+				expenseBudget[m] += monthBudget[m]
+				expenseActual[m] += monthActual[m]
+			}
+		}
+		row.YearToDate = budgetVsActualAmounts(ytdBudget, ytdActual, isIncome)
+
+		if isIncome {
+			incomeAccounts = append(incomeAccounts, row)
+		} else {
+			expenseAccounts = append(expenseAccounts, row)
+		}
+	}
+
+	// Build section totals and net profit/loss (net is treated like income: higher is favorable)
+	var totalIncome, totalExpenses, netProfitLoss BudgetVsActualTotals
+	ytdIncomeBudget, ytdIncomeActual, ytdExpenseBudget, ytdExpenseActual := 0.0, 0.0, 0.0, 0.0
+	for m := 0; m < 12; m++ {
+		totalIncome.Months[m] = budgetVsActualAmounts(incomeBudget[m], incomeActual[m], true)
+		totalExpenses.Months[m] = budgetVsActualAmounts(expenseBudget[m], expenseActual[m], false)
+		netProfitLoss.Months[m] = budgetVsActualAmounts(incomeBudget[m]-expenseBudget[m], incomeActual[m]-expenseActual[m], true)
+		if m < throughMonth {
+			ytdIncomeBudget += incomeBudget[m]
+			ytdIncomeActual += incomeActual[m]
+			ytdExpenseBudget += expenseBudget[m]
+			ytdExpenseActual += expenseActual[m]
+		}
+	}
+	totalIncome.YearToDate = budgetVsActualAmounts(ytdIncomeBudget, ytdIncomeActual, true)
+	totalExpenses.YearToDate = budgetVsActualAmounts(ytdExpenseBudget, ytdExpenseActual, false)
+	netProfitLoss.YearToDate = budgetVsActualAmounts(ytdIncomeBudget-ytdExpenseBudget, ytdIncomeActual-ytdExpenseActual, true)
+
+	return &BudgetVsActualResponse{
+		BuildingID:      req.BuildingID,
+		Year:            req.Year,
+		UnitID:          req.UnitID,
+		ThroughMonth:    throughMonth,
+		IncomeAccounts:  incomeAccounts,
+		ExpenseAccounts: expenseAccounts,
+		TotalIncome:     totalIncome,
+		TotalExpenses:   totalExpenses,
+		NetProfitLoss:   netProfitLoss,
+	}, nil
+}
+
+// maxReportColumns limits the size of comparative reports
+const maxReportColumns = 60
+
+// buildReportColumns splits a date range into report columns according to a column spec:
+//   - months, quarters, years: calendar periods clipped to the range
+//   - prior_year: the same range one year earlier, followed by the range itself
+func buildReportColumns(spec string, startDate string, endDate string) ([]ReportColumn, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, apperrors.BadRequest("start date must be in YYYY-MM-DD format")
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, apperrors.BadRequest("end date must be in YYYY-MM-DD format")
+	}
+	if end.Before(start) {
+		return nil, apperrors.BadRequest("end date must be after or equal to start date")
+	}
+
+	columns := []ReportColumn{}
+
+	switch strings.ToLower(spec) {
+	case "prior_year":
+		priorStart := start.AddDate(-1, 0, 0)
+		priorEnd := end.AddDate(-1, 0, 0)
+		columns = append(columns,
+			ReportColumn{
+				Label:     priorStart.Format("2006-01-02") + " to " + priorEnd.Format("2006-01-02"),
+				StartDate: priorStart.Format("2006-01-02"),
+				EndDate:   priorEnd.Format("2006-01-02"),
+			},
+			ReportColumn{
+				Label:     startDate + " to " + endDate,
+				StartDate: startDate,
+				EndDate:   endDate,
+			},
+		)
+		return columns, nil
+	case "months", "quarters", "years":
+	default:
+		return nil, apperrors.BadRequestf("invalid columns value '%s': use months, quarters, years or prior_year", spec)
+	}
+
+	// Align the cursor to the first day of the period containing the start date
+	var cursor time.Time
+	var step int
+	switch strings.ToLower(spec) {
+	case "months":
+		cursor = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+		step = 1
+	case "quarters":
+		quarterMonth := ((int(start.Month())-1)/3)*3 + 1
+		cursor = time.Date(start.Year(), time.Month(quarterMonth), 1, 0, 0, 0, 0, time.UTC)
+		step = 3
+	case "years":
+		cursor = time.Date(start.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		step = 12
+	}
+
+	for !cursor.After(end) {
+		periodEnd := cursor.AddDate(0, step, -1)
+
+		colStart := cursor
+		if colStart.Before(start) {
+			colStart = start
+		}
+		colEnd := periodEnd
+		if colEnd.After(end) {
+			colEnd = end
+		}
+
+		var label string
+		switch step {
+		case 1:
+			label = cursor.Format("Jan 2006")
+		case 3:
+			label = fmt.Sprintf("Q%d %d", (int(cursor.Month())-1)/3+1, cursor.Year())
+		default:
+			label = cursor.Format("2006")
+		}
+
+		columns = append(columns, ReportColumn{
+			Label:     label,
+			StartDate: colStart.Format("2006-01-02"),
+			EndDate:   colEnd.Format("2006-01-02"),
+		})
+
+		if len(columns) > maxReportColumns {
+			return nil, apperrors.BadRequestf("too many columns: at most %d are allowed", maxReportColumns)
+		}
+
+		cursor = cursor.AddDate(0, step, 0)
+	}
+
+	return columns, nil
+}
+
+// comparativeValue is one account value within a single report column
+type comparativeValue struct {
+	AccountID     int
+	AccountNumber int
+	AccountName   string
+	AccountType   string
+	Value         float64
+}
+
+// fillComparativeRow rounds values and computes the total and change columns of a row
+func fillComparativeRow(row *ComparativeRow, withTotal bool) {
+	total := 0.0
+	for i := range row.Values {
+		row.Values[i] = math.Round(row.Values[i]*100) / 100
+		total += row.Values[i]
+	}
+
+	if withTotal {
+		total = math.Round(total*100) / 100
+		row.Total = &total
+	}
+
+	row.Changes = []ColumnChange{}
+	for i := 1; i < len(row.Values); i++ {
+		previous := row.Values[i-1]
+		change := ColumnChange{Amount: math.Round((row.Values[i]-previous)*100) / 100}
+		if previous != 0 {
+			percent := math.Round(change.Amount/math.Abs(previous)*10000) / 100
+			change.Percent = &percent
+		}
+		row.Changes = append(row.Changes, change)
+	}
+}
+
+// buildComparativeSection pivots per-column account values into a matrix of account by column
+// Accounts are ordered by account number; calculated rows (account ID 0) go last
+func buildComparativeSection(sectionName string, valuesByColumn [][]comparativeValue, withTotal bool) ComparativeSection {
+	columnCount := len(valuesByColumn)
+	rowsByAccount := make(map[int]*ComparativeRow)
+	order := []int{}
+
+	for col, values := range valuesByColumn {
+		for _, v := range values {
+			row, exists := rowsByAccount[v.AccountID]
+			if !exists {
+				row = &ComparativeRow{
+					AccountID:     v.AccountID,
+					AccountNumber: v.AccountNumber,
+					AccountName:   v.AccountName,
+					AccountType:   v.AccountType,
+					Values:        make([]float64, columnCount),
+				}
+				rowsByAccount[v.AccountID] = row
+				order = append(order, v.AccountID)
+			}
+			row.Values[col] += v.Value
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := rowsByAccount[order[i]], rowsByAccount[order[j]]
+		if (a.AccountID == 0) != (b.AccountID == 0) {
+			return b.AccountID == 0
+		}
+		return a.AccountNumber < b.AccountNumber
+	})
+
+	totals := ComparativeRow{AccountName: "Total " + sectionName, Values: make([]float64, columnCount)}
+	accountRows := []ComparativeRow{}
+	for _, accountID := range order {
+		row := rowsByAccount[accountID]
+		for col := range row.Values {
+			totals.Values[col] += row.Values[col]
+		}
+		fillComparativeRow(row, withTotal)
+		accountRows = append(accountRows, *row)
+	}
+	fillComparativeRow(&totals, withTotal)
+
+	return ComparativeSection{
+		SectionName: sectionName,
+		Accounts:    accountRows,
+		Totals:      totals,
+	}
+}
+
+// GetComparativeProfitAndLoss generates a profit and loss matrix of account by column
+// Each column is computed with the standard profit and loss logic for its own date range
+func (s *ReportsService) GetComparativeProfitAndLoss(req ComparativeProfitAndLossRequest) (*ComparativeProfitAndLossResponse, error) {
+	if req.StartDate == "" || req.EndDate == "" {
+		return nil, apperrors.BadRequest("start date and end date are required")
+	}
+
+	basis, err := parseReportBasis(req.Basis)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := buildReportColumns(req.Columns, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	incomeByColumn := [][]comparativeValue{}
+	expensesByColumn := [][]comparativeValue{}
+	for _, column := range columns {
+		report, err := s.GetProfitAndLossStandard(ProfitAndLossStandardRequest{
+			BuildingID: req.BuildingID,
+			StartDate:  column.StartDate,
+			EndDate:    column.EndDate,
+			Basis:      basis,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build column %s: %w", column.Label, err)
+		}
+
+		income := []comparativeValue{}
+		for _, account := range report.Income.Accounts {
+			income = append(income, comparativeValue{account.AccountID, account.AccountNumber, account.AccountName, "income", account.Balance})
+		}
+		expenses := []comparativeValue{}
+		for _, account := range report.Expenses.Accounts {
+			expenses = append(expenses, comparativeValue{account.AccountID, account.AccountNumber, account.AccountName, "expense", account.Balance})
+		}
+		incomeByColumn = append(incomeByColumn, income)
+		expensesByColumn = append(expensesByColumn, expenses)
+	}
+
+	incomeSection := buildComparativeSection("Income", incomeByColumn, true)
+	expenseSection := buildComparativeSection("Expenses", expensesByColumn, true)
+
+	netProfitLoss := ComparativeRow{AccountName: "Net Profit/Loss", Values: make([]float64, len(columns))}
+	for col := range columns {
+		netProfitLoss.Values[col] = incomeSection.Totals.Values[col] - expenseSection.Totals.Values[col]
+	}
+	fillComparativeRow(&netProfitLoss, true)
+
+	return &ComparativeProfitAndLossResponse{
+		BuildingID:    req.BuildingID,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		ColumnSpec:    req.Columns,
+		Basis:         basis,
+		Columns:       columns,
+		Income:        incomeSection,
+		Expenses:      expenseSection,
+		NetProfitLoss: netProfitLoss,
+	}, nil
+}
+
+// GetComparativeBalanceSheet generates balance sheets at several dates side by side
+// Column dates are either explicit (columns=dates) or the period ends of a column spec
+func (s *ReportsService) GetComparativeBalanceSheet(req ComparativeBalanceSheetRequest) (*ComparativeBalanceSheetResponse, error) {
+	basis, err := parseReportBasis(req.Basis)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := []ReportColumn{}
+
+	if strings.ToLower(req.Columns) == "dates" {
+		if len(req.AsOfDates) == 0 {
+			return nil, apperrors.BadRequest("at least one as of date is required")
+		}
+		if len(req.AsOfDates) > maxReportColumns {
+			return nil, apperrors.BadRequestf("too many columns: at most %d are allowed", maxReportColumns)
+		}
+		for _, asOfDate := range req.AsOfDates {
+			if _, err := time.Parse("2006-01-02", asOfDate); err != nil {
+				return nil, apperrors.BadRequestf("as of date '%s' must be in YYYY-MM-DD format", asOfDate)
+			}
+			columns = append(columns, ReportColumn{Label: asOfDate, EndDate: asOfDate})
+		}
+	} else {
+		if req.StartDate == "" || req.EndDate == "" {
+			return nil, apperrors.BadRequest("start date and end date are required")
+		}
+		periods, err := buildReportColumns(req.Columns, req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		// Balance sheet columns are as of the end of each period
+		for _, period := range periods {
+			columns = append(columns, ReportColumn{Label: period.Label, EndDate: period.EndDate})
+		}
+	}
+
+	assetsByColumn := [][]comparativeValue{}
+	liabilitiesByColumn := [][]comparativeValue{}
+	equityByColumn := [][]comparativeValue{}
+	isBalanced := []bool{}
+
+	toValues := func(accounts []AccountBalance) []comparativeValue {
+		values := []comparativeValue{}
+		for _, account := range accounts {
+			accountNumber, _ := strconv.Atoi(account.AccountNumber)
+			values = append(values, comparativeValue{account.AccountID, accountNumber, account.AccountName, account.AccountType, account.Balance})
+		}
+		return values
+	}
+
+	for _, column := range columns {
+		report, err := s.GetBalanceSheet(BalanceSheetRequest{
+			BuildingID: req.BuildingID,
+			AsOfDate:   column.EndDate,
+			Basis:      basis,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build column %s: %w", column.Label, err)
+		}
+
+		assetsByColumn = append(assetsByColumn, toValues(report.Assets.Accounts))
+		liabilitiesByColumn = append(liabilitiesByColumn, toValues(report.Liabilities.Accounts))
+		equityByColumn = append(equityByColumn, toValues(report.Equity.Accounts))
+		isBalanced = append(isBalanced, report.IsBalanced)
+	}
+
+	assetsSection := buildComparativeSection("Assets", assetsByColumn, false)
+	liabilitiesSection := buildComparativeSection("Liabilities", liabilitiesByColumn, false)
+	equitySection := buildComparativeSection("Equity", equityByColumn, false)
+
+	totalLiabilitiesAndEquity := ComparativeRow{AccountName: "Total Liabilities and Equity", Values: make([]float64, len(columns))}
+	for col := range columns {
+		totalLiabilitiesAndEquity.Values[col] = liabilitiesSection.Totals.Values[col] + equitySection.Totals.Values[col]
+	}
+	fillComparativeRow(&totalLiabilitiesAndEquity, false)
+
+	return &ComparativeBalanceSheetResponse{
+		BuildingID:                req.BuildingID,
+		ColumnSpec:                req.Columns,
+		Basis:                     basis,
+		Columns:                   columns,
+		Assets:                    assetsSection,
+		Liabilities:               liabilitiesSection,
+		Equity:                    equitySection,
+		TotalLiabilitiesAndEquity: totalLiabilitiesAndEquity,
+		IsBalanced:                isBalanced,
+	}, nil
+}
+
+// GetBuildingName returns the name of a building for report headers
+func (s *ReportsService) GetBuildingName(buildingID int) (string, error) {
+	var name string
+	err := s.db.QueryRow("SELECT name FROM buildings WHERE id = ?", buildingID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", apperrors.NotFound("building")
+	}
+	return name, err
+}
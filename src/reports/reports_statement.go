@@ -0,0 +1,471 @@
+package reports
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/people"
+)
+
+// GetCustomerStatement builds a customer's statement of account for a date range: the opening
+// balance, every invoice, payment, prepayment, discount, credit memo, applied credit and sales
+// receipt with a running balance, and the aging of what is owed at the end date
+func (s *ReportsService) GetCustomerStatement(req CustomerStatementRequest) (*CustomerStatement, error) {
+	if req.PeopleID == nil {
+		return nil, apperrors.BadRequest("people_id is required")
+	}
+	if err := validateStatementDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	customers, err := s.findCustomers(req.BuildingID, req.PeopleID)
+	if err != nil {
+		return nil, err
+	}
+	if len(customers) == 0 {
+		return nil, apperrors.NotFound("customer")
+	}
+
+	unitName, err := s.statementUnitName(req.BuildingID, req.UnitID)
+	if err != nil {
+		return nil, err
+	}
+
+	arAccountTypeID, err := s.findAccountTypeByName("Account Receivable")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Account Receivable account type: %w", err)
+	}
+
+	return s.buildCustomerStatement(customers[0], arAccountTypeID, req, unitName)
+}
+
+// GetCustomerStatements builds the statement of every customer of the building that has an
+// opening balance, activity in the range or a closing balance
+func (s *ReportsService) GetCustomerStatements(req CustomerStatementRequest) (*CustomerStatementsResponse, error) {
+	if err := validateStatementDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	customers, err := s.findCustomers(req.BuildingID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	unitName, err := s.statementUnitName(req.BuildingID, req.UnitID)
+	if err != nil {
+		return nil, err
+	}
+
+	arAccountTypeID, err := s.findAccountTypeByName("Account Receivable")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Account Receivable account type: %w", err)
+	}
+
+	statements := []CustomerStatement{}
+	for _, customer := range customers {
+		statement, err := s.buildCustomerStatement(customer, arAccountTypeID, req, unitName)
+		if err != nil {
+			return nil, err
+		}
+		if len(statement.Lines) == 0 && isZeroAmount(statement.OpeningBalance) && isZeroAmount(statement.ClosingBalance) {
+			continue
+		}
+		statements = append(statements, *statement)
+	}
+
+	return &CustomerStatementsResponse{
+		BuildingID: req.BuildingID,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		Statements: statements,
+	}, nil
+}
+
+func validateStatementDates(startDate string, endDate string) error {
+	if startDate == "" || endDate == "" {
+		return apperrors.BadRequest("start date and end date are required")
+	}
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return apperrors.BadRequest("start date must be formatted as YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return apperrors.BadRequest("end date must be formatted as YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return apperrors.BadRequest("end date must not be before start date")
+	}
+	return nil
+}
+
+// statementUnitName checks that the unit belongs to the building and returns its name
+func (s *ReportsService) statementUnitName(buildingID int, unitID *int) (*string, error) {
+	if unitID == nil {
+		return nil, nil
+	}
+	var name string
+	err := s.db.QueryRow("SELECT name FROM units WHERE id = ? AND building_id = ?", *unitID, buildingID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("unit")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unit: %w", err)
+	}
+	return &name, nil
+}
+
+// statementEntry is a statement line before it is placed in the date range; the balance
+// changes by charges - payments
+type statementEntry struct {
+	line  CustomerStatementLine
+	order int // Keeps entries of the same day in the order they were collected
+}
+
+func (s *ReportsService) buildCustomerStatement(customer people.Person, arAccountTypeID int, req CustomerStatementRequest, unitName *string) (*CustomerStatement, error) {
+	entries := []statementEntry{}
+	add := func(line CustomerStatementLine) {
+		entries = append(entries, statementEntry{line: line, order: len(entries)})
+	}
+
+	// Invoices, payments and discounts are the customer's Account Receivable postings, the
+	// same data as the customer balance details report
+	customerSplits, err := s.customerReceivableSplits(customer.ID, arAccountTypeID, req.EndDate, req.UnitID)
+	if err != nil {
+		return nil, err
+	}
+	discountReferences, err := s.discountReferences(customer.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(customerSplits); {
+		first := customerSplits[i]
+		transactionID := first.TransactionID
+		line := CustomerStatementLine{
+			Date:          dateOnly(first.TransactionDate),
+			Type:          statementLineType(first.TransactionType),
+			Reference:     first.TransactionNumber,
+			Description:   first.TransactionMemo,
+			TransactionID: &transactionID,
+		}
+		// A transaction can post several splits to receivables; it is one line on the statement
+		for ; i < len(customerSplits) && customerSplits[i].TransactionID == transactionID; i++ {
+			if customerSplits[i].Debit != nil {
+				line.Charges += *customerSplits[i].Debit
+			}
+			if customerSplits[i].Credit != nil {
+				line.Payments += *customerSplits[i].Credit
+			}
+		}
+		// The prepayment lowered the balance when it was received
+		if line.Type == StatementLinePrepaymentApplied {
+			line.Charges = line.Payments
+		}
+		if reference, ok := discountReferences[transactionID]; ok {
+			line.Type = StatementLineDiscount
+			if line.Reference == "" {
+				line.Reference = reference
+			}
+		}
+		line.Amount = math.Max(line.Charges, line.Payments)
+		add(line)
+	}
+
+	// Sales receipts are paid at the time of sale and never reach receivables: they are
+	// listed as charged and paid so the balance does not move
+	receiptQuery := `
+		SELECT transaction_id, receipt_no, DATE(receipt_date), amount, COALESCE(description, '')
+		FROM sales_receipt
+		WHERE people_id = ? AND building_id = ? AND status = '1' AND DATE(receipt_date) <= ?`
+	receiptArgs := []interface{}{customer.ID, req.BuildingID, req.EndDate}
+	if req.UnitID != nil {
+		receiptQuery += " AND unit_id = ?"
+		receiptArgs = append(receiptArgs, *req.UnitID)
+	}
+	err = s.queryStatementEntries(receiptQuery+" ORDER BY receipt_date, id", receiptArgs, func(rows *sql.Rows) error {
+		var transactionID, receiptNo int
+		var line CustomerStatementLine
+		if err := rows.Scan(&transactionID, &receiptNo, &line.Date, &line.Amount, &line.Description); err != nil {
+			return err
+		}
+		line.Type = StatementLineSalesReceipt
+		line.Reference = strconv.Itoa(receiptNo)
+		line.TransactionID = &transactionID
+		line.Charges = line.Amount
+		line.Payments = line.Amount
+		add(line)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales receipts for customer %d: %w", customer.ID, err)
+	}
+
+	// Credit memos hold money for the customer on a liability account until they are applied
+	creditMemoTotal := 0.0
+	creditMemoQuery := `
+		SELECT transaction_id, reference, DATE(date), amount, description
+		FROM credit_memo
+		WHERE people_id = ? AND building_id = ? AND status = '1' AND DATE(date) <= ?`
+	creditMemoArgs := []interface{}{customer.ID, req.BuildingID, req.EndDate}
+	if req.UnitID != nil {
+		creditMemoQuery += " AND unit_id = ?"
+		creditMemoArgs = append(creditMemoArgs, *req.UnitID)
+	}
+	err = s.queryStatementEntries(creditMemoQuery+" ORDER BY date, id", creditMemoArgs, func(rows *sql.Rows) error {
+		var transactionID int
+		var line CustomerStatementLine
+		if err := rows.Scan(&transactionID, &line.Reference, &line.Date, &line.Amount, &line.Description); err != nil {
+			return err
+		}
+		line.Type = StatementLineCreditMemo
+		line.TransactionID = &transactionID
+		line.Payments = line.Amount
+		creditMemoTotal += line.Amount
+		add(line)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credit memos for customer %d: %w", customer.ID, err)
+	}
+
+	// Prepayments are held on a liability account until they are applied to invoices
+	prepaymentQuery := `
+		SELECT transaction_id, reference, DATE(date), amount, memo
+		FROM customer_payments
+		WHERE people_id = ? AND building_id = ? AND liability_account_id IS NOT NULL AND status = '1' AND DATE(date) <= ?`
+	prepaymentArgs := []interface{}{customer.ID, req.BuildingID, req.EndDate}
+	if req.UnitID != nil {
+		prepaymentQuery += " AND unit_id = ?"
+		prepaymentArgs = append(prepaymentArgs, *req.UnitID)
+	}
+	err = s.queryStatementEntries(prepaymentQuery+" ORDER BY date, id", prepaymentArgs, func(rows *sql.Rows) error {
+		var transactionID int
+		var line CustomerStatementLine
+		if err := rows.Scan(&transactionID, &line.Reference, &line.Date, &line.Amount, &line.Description); err != nil {
+			return err
+		}
+		line.Type = StatementLinePrepayment
+		line.TransactionID = &transactionID
+		line.Payments = line.Amount
+		add(line)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prepayments for customer %d: %w", customer.ID, err)
+	}
+
+	// Applying a credit moves it from the credit memo to an invoice without posting, so the
+	// balance does not move either
+	appliedCreditTotal := 0.0
+	appliedCreditQuery := `
+		SELECT DATE(c.date), c.amount, c.description, i.invoice_no, m.reference
+		FROM invoice_applied_credits c
+		INNER JOIN invoices i ON c.invoice_id = i.id
+		INNER JOIN credit_memo m ON c.credit_memo_id = m.id
+		WHERE i.people_id = ? AND i.building_id = ? AND c.status = '1' AND DATE(c.date) <= ?`
+	appliedCreditArgs := []interface{}{customer.ID, req.BuildingID, req.EndDate}
+	if req.UnitID != nil {
+		appliedCreditQuery += " AND i.unit_id = ?"
+		appliedCreditArgs = append(appliedCreditArgs, *req.UnitID)
+	}
+	err = s.queryStatementEntries(appliedCreditQuery+" ORDER BY c.date, c.id", appliedCreditArgs, func(rows *sql.Rows) error {
+		var line CustomerStatementLine
+		var description, invoiceNo, creditMemoReference string
+		if err := rows.Scan(&line.Date, &line.Amount, &description, &invoiceNo, &creditMemoReference); err != nil {
+			return err
+		}
+		line.Type = StatementLineAppliedCredit
+		line.Reference = creditMemoReference
+		line.Description = fmt.Sprintf("Credit %s applied to invoice %s", creditMemoReference, invoiceNo)
+		if strings.TrimSpace(description) != "" {
+			line.Description += ": " + description
+		}
+		appliedCreditTotal += line.Amount
+		add(line)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied credits for customer %d: %w", customer.ID, err)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].line.Date != entries[j].line.Date {
+			return entries[i].line.Date < entries[j].line.Date
+		}
+		return entries[i].order < entries[j].order
+	})
+
+	statement := &CustomerStatement{
+		BuildingID: req.BuildingID,
+		PeopleID:   customer.ID,
+		PeopleName: customer.Name,
+		Phone:      customer.Phone,
+		UnitID:     req.UnitID,
+		UnitName:   unitName,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		Lines:      []CustomerStatementLine{},
+	}
+	balance := 0.0
+	for _, entry := range entries {
+		line := entry.line
+		balance += line.Charges - line.Payments
+		if line.Date < req.StartDate {
+			statement.OpeningBalance = balance
+			continue
+		}
+		line.Balance = balance
+		statement.Lines = append(statement.Lines, line)
+		statement.TotalCharges += line.Charges
+		statement.TotalPayments += line.Payments
+	}
+	statement.ClosingBalance = balance
+
+	statement.Aging, err = s.customerAging(customer.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	unappliedPayments, err := s.unappliedPaymentCredit(customer.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	statement.Aging.UnappliedCredit = math.Max(creditMemoTotal-appliedCreditTotal, 0) + unappliedPayments
+	statement.Aging.Total -= statement.Aging.UnappliedCredit
+
+	return statement, nil
+}
+
+// customerAging buckets the open balance of the customer's invoices at the end date by the
+// number of days they are past due
+func (s *ReportsService) customerAging(peopleID int, req CustomerStatementRequest) (CustomerStatementAging, error) {
+	aging := CustomerStatementAging{}
+	endDate, _ := time.Parse("2006-01-02", req.EndDate)
+
+	query := `
+		SELECT DATE(i.due_date), i.amount
+			- COALESCE((SELECT SUM(p.amount) FROM invoice_payments p WHERE p.invoice_id = i.id AND p.status = '1' AND DATE(p.date) <= ?), 0)
+			- COALESCE((SELECT SUM(c.amount) FROM invoice_applied_credits c WHERE c.invoice_id = i.id AND c.status = '1' AND DATE(c.date) <= ?), 0)
+			- COALESCE((SELECT SUM(d.amount) FROM invoice_applied_discounts d WHERE d.invoice_id = i.id AND d.status = '1' AND DATE(d.date) <= ?), 0)
+		FROM invoices i
+		WHERE i.people_id = ? AND i.building_id = ? AND i.status = '1' AND DATE(i.sales_date) <= ?`
+	args := []interface{}{req.EndDate, req.EndDate, req.EndDate, peopleID, req.BuildingID, req.EndDate}
+	if req.UnitID != nil {
+		query += " AND i.unit_id = ?"
+		args = append(args, *req.UnitID)
+	}
+
+	err := s.queryStatementEntries(query, args, func(rows *sql.Rows) error {
+		var dueDate string
+		var open float64
+		if err := rows.Scan(&dueDate, &open); err != nil {
+			return err
+		}
+		if isZeroAmount(open) {
+			return nil
+		}
+		due, err := time.Parse("2006-01-02", dateOnly(dueDate))
+		if err != nil {
+			return fmt.Errorf("invalid due date %q: %w", dueDate, err)
+		}
+		switch daysPastDue := int(endDate.Sub(due).Hours() / 24); {
+		case daysPastDue <= 0:
+			aging.Current += open
+		case daysPastDue <= 30:
+			aging.Days1To30 += open
+		case daysPastDue <= 60:
+			aging.Days31To60 += open
+		case daysPastDue <= 90:
+			aging.Days61To90 += open
+		default:
+			aging.Over90 += open
+		}
+		aging.Total += open
+		return nil
+	})
+	if err != nil {
+		return aging, fmt.Errorf("failed to age invoices for customer %d: %w", peopleID, err)
+	}
+	return aging, nil
+}
+
+// unappliedPaymentCredit totals what the customer's payments and prepayments had not allocated
+// to invoices at the end date. It is already in the balance, credited to receivables or listed
+// as prepayments, so only the aging needs it.
+func (s *ReportsService) unappliedPaymentCredit(peopleID int, req CustomerStatementRequest) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(cp.amount
+			- COALESCE((SELECT SUM(ip.amount) FROM invoice_payments ip WHERE ip.customer_payment_id = cp.id AND ip.status = '1' AND DATE(ip.date) <= ?), 0)), 0)
+		FROM customer_payments cp
+		WHERE cp.people_id = ? AND cp.building_id = ? AND cp.status = '1' AND DATE(cp.date) <= ?`
+	args := []interface{}{req.EndDate, peopleID, req.BuildingID, req.EndDate}
+	if req.UnitID != nil {
+		query += " AND cp.unit_id = ?"
+		args = append(args, *req.UnitID)
+	}
+	var unapplied float64
+	if err := s.db.QueryRow(query, args...).Scan(&unapplied); err != nil {
+		return 0, fmt.Errorf("failed to get unapplied payments for customer %d: %w", peopleID, err)
+	}
+	return math.Max(unapplied, 0), nil
+}
+
+// discountReferences returns the references of the customer's applied discounts by the
+// transaction they posted
+func (s *ReportsService) discountReferences(peopleID int) (map[int]string, error) {
+	references := make(map[int]string)
+	query := `
+		SELECT d.transaction_id, d.reference
+		FROM invoice_applied_discounts d
+		INNER JOIN invoices i ON d.invoice_id = i.id
+		WHERE i.people_id = ? AND d.status = '1'`
+	err := s.queryStatementEntries(query, []interface{}{peopleID}, func(rows *sql.Rows) error {
+		var transactionID int
+		var reference string
+		if err := rows.Scan(&transactionID, &reference); err != nil {
+			return err
+		}
+		references[transactionID] = reference
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied discounts for customer %d: %w", peopleID, err)
+	}
+	return references, nil
+}
+
+func (s *ReportsService) queryStatementEntries(query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// statementLineType names a line after the transaction type, e.g. "sales receipt" becomes sales_receipt
+func statementLineType(transactionType string) string {
+	return strings.ReplaceAll(strings.ToLower(transactionType), " ", "_")
+}
+
+// dateOnly drops the time some drivers return with DATE columns
+func dateOnly(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
+
+func isZeroAmount(amount float64) bool {
+	return math.Abs(amount) < 0.005
+}
@@ -19,6 +19,7 @@ type SalesReceipt struct {
 	CancelReason  *string `json:"cancel_reason"`
 	Status        int     `json:"status"`
 	BuildingID    int     `json:"building_id"`
+	Version       int     `json:"version"`
 	CreatedAt     string  `json:"created_at"`
 	UpdatedAt     string  `json:"updated_at"`
 }
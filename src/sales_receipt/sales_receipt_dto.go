@@ -58,6 +58,9 @@ type UpdateSalesReceiptRequest struct {
 	Status      *int               `json:"status"`
 	BuildingID  int                `json:"building_id"`
 	Items       []ReceiptItemInput `json:"items"`
+
+	// Version from the If-Match header; the update fails if the document has moved on
+	ExpectedVersion int `json:"-"`
 }
 
 type SalesReceiptResponse struct {
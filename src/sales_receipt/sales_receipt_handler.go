@@ -5,6 +5,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type SalesReceiptHandler struct {
@@ -19,7 +22,7 @@ func NewSalesReceiptHandler(service *SalesReceiptService) *SalesReceiptHandler {
 func (h *SalesReceiptHandler) PreviewSalesReceipt(c *gin.Context) {
 	var req CreateSalesReceiptRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -36,19 +39,19 @@ func (h *SalesReceiptHandler) PreviewSalesReceipt(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
 	preview, err := h.service.PreviewSalesReceipt(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -59,7 +62,7 @@ func (h *SalesReceiptHandler) PreviewSalesReceipt(c *gin.Context) {
 func (h *SalesReceiptHandler) CreateSalesReceipt(c *gin.Context) {
 	var req CreateSalesReceiptRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -76,19 +79,19 @@ func (h *SalesReceiptHandler) CreateSalesReceipt(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.CreateSalesReceipt(req, userID)
+	response, err := h.service.WithLogger(logging.FromGin(c)).CreateSalesReceipt(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -104,13 +107,13 @@ func (h *SalesReceiptHandler) GetSalesReceipts(c *gin.Context) {
 
 	buildingID, err := strconv.Atoi(buildingIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Building ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
 		return
 	}
 
 	receipts, err := h.service.GetReceiptRepo().GetByBuildingID(buildingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -127,13 +130,13 @@ func (h *SalesReceiptHandler) GetSalesReceipt(c *gin.Context) {
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid ID"))
 		return
 	}
 
 	receipt, err := h.service.GetReceiptRepo().GetByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
@@ -142,6 +145,7 @@ func (h *SalesReceiptHandler) GetSalesReceipt(c *gin.Context) {
 	splits, _ := h.service.GetSplitRepo().GetByTransactionID(receipt.TransactionID)
 	transaction, _ := h.service.GetTransactionRepo().GetByID(receipt.TransactionID)
 
+	versioning.SetETag(c, receipt.Version)
 	c.JSON(http.StatusOK, SalesReceiptResponse{
 		Receipt:     receipt,
 		Items:       receiptItems,
@@ -152,6 +156,11 @@ func (h *SalesReceiptHandler) GetSalesReceipt(c *gin.Context) {
 
 // PUT /sales-receipts/:id or /buildings/:id/sales-receipts/:receiptId
 func (h *SalesReceiptHandler) UpdateSalesReceipt(c *gin.Context) {
+	version, ok := versioning.IfMatch(c)
+	if !ok {
+		return
+	}
+
 	// Try receiptId first (for building-scoped routes), then id (for legacy routes)
 	idStr := c.Param("receiptId")
 	if idStr == "" {
@@ -160,13 +169,13 @@ func (h *SalesReceiptHandler) UpdateSalesReceipt(c *gin.Context) {
 
 	receiptID, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Receipt ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Receipt ID"))
 		return
 	}
 
 	var req UpdateSalesReceiptRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		apperrors.Respond(c, apperrors.FromBinding(err))
 		return
 	}
 
@@ -187,21 +196,24 @@ func (h *SalesReceiptHandler) UpdateSalesReceipt(c *gin.Context) {
 		userIDStr = c.Query("user_id")
 	}
 	if userIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		apperrors.Respond(c, apperrors.BadRequest("User ID is required"))
 		return
 	}
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID"})
+		apperrors.Respond(c, apperrors.BadRequest("Invalid User ID"))
 		return
 	}
 
-	response, err := h.service.UpdateSalesReceipt(req, userID)
+	req.ExpectedVersion = version
+	response, err := h.service.WithLogger(logging.FromGin(c)).UpdateSalesReceipt(req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err)
 		return
 	}
 
+	versioning.SetETag(c, response.Receipt.Version)
+
 	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,15 @@
+package sales_receipt
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"SalesReceiptHandler.PreviewSalesReceipt": {
+		Summary:     "Preview a sales receipt's postings",
+		Description: "Validates the receipt and returns the splits it would post without saving anything.",
+		UserID:      true, Request: CreateSalesReceiptRequest{}, Response: SalesReceiptPreviewResponse{},
+	},
+	"SalesReceiptHandler.CreateSalesReceipt": {Summary: "Create and post a sales receipt", UserID: true, Idempotent: true, Request: CreateSalesReceiptRequest{}, Response: SalesReceiptResponse{}},
+	"SalesReceiptHandler.GetSalesReceipts":   {Summary: "List sales receipts", Response: []SalesReceipt{}},
+	"SalesReceiptHandler.GetSalesReceipt":    {Summary: "Get a sales receipt with its items and postings", Response: SalesReceiptResponse{}},
+	"SalesReceiptHandler.UpdateSalesReceipt": {Summary: "Update a sales receipt and repost it", UserID: true, IfMatch: true, Request: UpdateSalesReceiptRequest{}, Response: SalesReceiptResponse{}},
+}
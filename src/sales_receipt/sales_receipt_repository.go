@@ -3,6 +3,8 @@ package sales_receipt
 import (
 	"database/sql"
 	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type SalesReceiptRepository interface {
@@ -54,26 +56,26 @@ func (r *salesReceiptRepo) Create(receipt SalesReceipt) (SalesReceipt, error) {
 	id, _ := result.LastInsertId()
 	receipt.ID = int(id)
 
-	err = r.db.QueryRow("SELECT id, receipt_no, transaction_id, receipt_date, unit_id, people_id, user_id, account_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt FROM sales_receipt WHERE id = ?", receipt.ID).
-		Scan(&receipt.ID, &receipt.ReceiptNo, &receipt.TransactionID, &receipt.ReceiptDate, &receipt.UnitID, &receipt.PeopleID, &receipt.UserID, &receipt.AccountID, &receipt.Amount, &receipt.Description, &receipt.CancelReason, &receipt.Status, &receipt.BuildingID, &receipt.CreatedAt, &receipt.UpdatedAt)
+	err = r.db.QueryRow("SELECT id, receipt_no, transaction_id, receipt_date, unit_id, people_id, user_id, account_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt, version FROM sales_receipt WHERE id = ?", receipt.ID).
+		Scan(&receipt.ID, &receipt.ReceiptNo, &receipt.TransactionID, &receipt.ReceiptDate, &receipt.UnitID, &receipt.PeopleID, &receipt.UserID, &receipt.AccountID, &receipt.Amount, &receipt.Description, &receipt.CancelReason, &receipt.Status, &receipt.BuildingID, &receipt.CreatedAt, &receipt.UpdatedAt, &receipt.Version)
 
 	return receipt, err
 }
 
 func (r *salesReceiptRepo) GetByID(id int) (SalesReceipt, error) {
 	var receipt SalesReceipt
-	err := r.db.QueryRow("SELECT id, receipt_no, transaction_id, receipt_date, unit_id, people_id, user_id, account_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt FROM sales_receipt WHERE id = ?", id).
-		Scan(&receipt.ID, &receipt.ReceiptNo, &receipt.TransactionID, &receipt.ReceiptDate, &receipt.UnitID, &receipt.PeopleID, &receipt.UserID, &receipt.AccountID, &receipt.Amount, &receipt.Description, &receipt.CancelReason, &receipt.Status, &receipt.BuildingID, &receipt.CreatedAt, &receipt.UpdatedAt)
+	err := r.db.QueryRow("SELECT id, receipt_no, transaction_id, receipt_date, unit_id, people_id, user_id, account_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt, version FROM sales_receipt WHERE id = ?", id).
+		Scan(&receipt.ID, &receipt.ReceiptNo, &receipt.TransactionID, &receipt.ReceiptDate, &receipt.UnitID, &receipt.PeopleID, &receipt.UserID, &receipt.AccountID, &receipt.Amount, &receipt.Description, &receipt.CancelReason, &receipt.Status, &receipt.BuildingID, &receipt.CreatedAt, &receipt.UpdatedAt, &receipt.Version)
 
 	if err == sql.ErrNoRows {
-		return receipt, fmt.Errorf("sales receipt not found")
+		return receipt, apperrors.NotFound("sales receipt")
 	}
 
 	return receipt, err
 }
 
 func (r *salesReceiptRepo) GetByBuildingID(buildingID int) ([]SalesReceipt, error) {
-	rows, err := r.db.Query("SELECT id, receipt_no, transaction_id, receipt_date, unit_id, people_id, user_id, account_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt FROM sales_receipt WHERE building_id = ? ORDER BY createdAt DESC", buildingID)
+	rows, err := r.db.Query("SELECT id, receipt_no, transaction_id, receipt_date, unit_id, people_id, user_id, account_id, amount, description, cancel_reason, status, building_id, createdAt, updatedAt, version FROM sales_receipt WHERE building_id = ? ORDER BY createdAt DESC", buildingID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +84,7 @@ func (r *salesReceiptRepo) GetByBuildingID(buildingID int) ([]SalesReceipt, erro
 	receipts := []SalesReceipt{}
 	for rows.Next() {
 		var receipt SalesReceipt
-		err := rows.Scan(&receipt.ID, &receipt.ReceiptNo, &receipt.TransactionID, &receipt.ReceiptDate, &receipt.UnitID, &receipt.PeopleID, &receipt.UserID, &receipt.AccountID, &receipt.Amount, &receipt.Description, &receipt.CancelReason, &receipt.Status, &receipt.BuildingID, &receipt.CreatedAt, &receipt.UpdatedAt)
+		err := rows.Scan(&receipt.ID, &receipt.ReceiptNo, &receipt.TransactionID, &receipt.ReceiptDate, &receipt.UnitID, &receipt.PeopleID, &receipt.UserID, &receipt.AccountID, &receipt.Amount, &receipt.Description, &receipt.CancelReason, &receipt.Status, &receipt.BuildingID, &receipt.CreatedAt, &receipt.UpdatedAt, &receipt.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -3,13 +3,18 @@ package sales_receipt
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"math"
 
 	"github.com/mysecodgit/go_accounting/src/accounts"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/items"
+	"github.com/mysecodgit/go_accounting/src/metrics"
+	"github.com/mysecodgit/go_accounting/src/outbox"
 	"github.com/mysecodgit/go_accounting/src/receipt_items"
 	"github.com/mysecodgit/go_accounting/src/splits"
 	"github.com/mysecodgit/go_accounting/src/transactions"
+	"github.com/mysecodgit/go_accounting/src/versioning"
 )
 
 type SalesReceiptService struct {
@@ -20,6 +25,7 @@ type SalesReceiptService struct {
 	itemRepo        items.ItemRepository
 	accountRepo     accounts.AccountRepository
 	db              *sql.DB
+	logger          *slog.Logger
 }
 
 func (s *SalesReceiptService) GetReceiptRepo() SalesReceiptRepository {
@@ -34,6 +40,7 @@ func NewSalesReceiptService(
 	itemRepo items.ItemRepository,
 	accountRepo accounts.AccountRepository,
 	db *sql.DB,
+	logger *slog.Logger,
 ) *SalesReceiptService {
 	return &SalesReceiptService{
 		receiptRepo:     receiptRepo,
@@ -43,9 +50,17 @@ func NewSalesReceiptService(
 		itemRepo:        itemRepo,
 		accountRepo:     accountRepo,
 		db:              db,
+		logger:          logger,
 	}
 }
 
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *SalesReceiptService) WithLogger(logger *slog.Logger) *SalesReceiptService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
+}
+
 // CalculateSplitsForSalesReceipt calculates the double-entry accounting splits for a sales receipt
 // For sales receipt:
 // 1. Debit: Asset Account (cash/bank account where payment is received)
@@ -60,7 +75,7 @@ func (s *SalesReceiptService) CalculateSplitsForSalesReceipt(req CreateSalesRece
 	for _, itemInput := range req.Items {
 		item, _, assetAccount, incomeAccount, _, _, err := s.itemRepo.GetByID(itemInput.ItemID)
 		if err != nil {
-			return nil, fmt.Errorf("item %d not found: %v", itemInput.ItemID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("item %d", itemInput.ItemID), err)
 		}
 		itemMap[itemInput.ItemID] = &item
 		if incomeAccount != nil {
@@ -74,7 +89,7 @@ func (s *SalesReceiptService) CalculateSplitsForSalesReceipt(req CreateSalesRece
 	// Get Asset Account from request (cash/bank account)
 	assetAccount, _, _, err := s.accountRepo.GetByID(req.AccountID)
 	if err != nil {
-		return nil, fmt.Errorf("asset account not found: %v", err)
+		return nil, apperrors.Lookup("asset account", err)
 	}
 
 	// Calculate totals by item type
@@ -140,7 +155,7 @@ func (s *SalesReceiptService) CalculateSplitsForSalesReceipt(req CreateSalesRece
 					serviceDebitByAccount[incomeAccount.ID] += math.Abs(itemTotal)
 				}
 			} else {
-				return nil, fmt.Errorf("service item '%s' (ID: %d) must have an income account configured", item.Name, item.ID)
+				return nil, apperrors.Rulef("service item '%s' (ID: %d) must have an income account configured", item.Name, item.ID)
 			}
 		}
 	}
@@ -205,13 +220,13 @@ func (s *SalesReceiptService) CalculateSplitsForSalesReceipt(req CreateSalesRece
 
 	// 4. Credit: Service Income Accounts (positive rates)
 	if len(serviceIncomeByAccount) == 0 && len(serviceDebitByAccount) == 0 && serviceTotalAmount > 0 {
-		return nil, fmt.Errorf("no income account found for service items - service items must have an income account configured")
+		return nil, apperrors.Rule("no income account found for service items - service items must have an income account configured")
 	}
 
 	for accountID, amount := range serviceIncomeByAccount {
 		account, _, _, err := s.accountRepo.GetByID(accountID)
 		if err != nil {
-			return nil, fmt.Errorf("income account %d not found: %v", accountID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("income account %d", accountID), err)
 		}
 		creditAmount := amount
 		splits = append(splits, SplitPreview{
@@ -229,7 +244,7 @@ func (s *SalesReceiptService) CalculateSplitsForSalesReceipt(req CreateSalesRece
 	for accountID, amount := range serviceDebitByAccount {
 		account, _, _, err := s.accountRepo.GetByID(accountID)
 		if err != nil {
-			return nil, fmt.Errorf("income account %d not found: %v", accountID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("income account %d", accountID), err)
 		}
 		debitAmount := amount
 		splits = append(splits, SplitPreview{
@@ -272,11 +287,11 @@ func (s *SalesReceiptService) CalculateSplitsForSalesReceipt(req CreateSalesRece
 
 	// Validate: Must have at least 2 splits and be balanced
 	if len(splits) < 2 {
-		return nil, fmt.Errorf("sales receipt must have at least 2 splits for double-entry accounting, got %d", len(splits))
+		return nil, apperrors.Rulef("sales receipt must have at least 2 splits for double-entry accounting, got %d", len(splits))
 	}
 
 	if totalDebit != totalCredit {
-		return nil, fmt.Errorf("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
+		return nil, apperrors.Rulef("splits are not balanced: total debit %.2f != total credit %.2f", totalDebit, totalCredit)
 	}
 
 	return splits, nil
@@ -285,11 +300,11 @@ func (s *SalesReceiptService) CalculateSplitsForSalesReceipt(req CreateSalesRece
 // PreviewSalesReceipt calculates and returns the splits that will be created
 func (s *SalesReceiptService) PreviewSalesReceipt(req CreateSalesReceiptRequest, userID int) (*SalesReceiptPreviewResponse, error) {
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	if len(req.Items) == 0 {
-		return nil, fmt.Errorf("sales receipt must have at least one item")
+		return nil, apperrors.Rule("sales receipt must have at least one item")
 	}
 
 	splitPreviews, err := s.CalculateSplitsForSalesReceipt(req, userID)
@@ -328,13 +343,13 @@ func (s *SalesReceiptService) CreateSalesReceipt(req CreateSalesReceiptRequest,
 		return nil, err
 	}
 	if exists {
-		return nil, fmt.Errorf("receipt number already exists for this building")
+		return nil, apperrors.Conflict("receipt number already exists for this building")
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -357,12 +372,12 @@ func (s *SalesReceiptService) CreateSalesReceipt(req CreateSalesReceiptRequest,
 	result, err := tx.Exec("INSERT INTO transactions (type, transaction_date, transaction_number, memo, status, building_id, user_id, unit_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
 		"sales receipt", req.ReceiptDate, req.ReceiptNo, req.Description, transactionStatus, req.BuildingID, userID, unitID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %v", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
 	transactionID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction ID: %v", err)
+		return nil, fmt.Errorf("failed to get transaction ID: %w", err)
 	}
 
 	// Create sales receipt - always use status "1" (active)
@@ -377,19 +392,19 @@ func (s *SalesReceiptService) CreateSalesReceipt(req CreateSalesReceiptRequest,
 	result, err = tx.Exec("INSERT INTO sales_receipt (receipt_no, transaction_id, receipt_date, unit_id, people_id, user_id, account_id, amount, description, cancel_reason, status, building_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		req.ReceiptNo, transactionID, req.ReceiptDate, unitID, peopleID, userID, req.AccountID, req.Amount, req.Description, nil, receiptStatus, req.BuildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create sales receipt: %v", err)
+		return nil, fmt.Errorf("failed to create sales receipt: %w", err)
 	}
 
 	receiptID, err := result.LastInsertId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get receipt ID: %v", err)
+		return nil, fmt.Errorf("failed to get receipt ID: %w", err)
 	}
 
 	// Create receipt items
 	for _, itemInput := range req.Items {
 		item, _, _, _, _, _, err := s.itemRepo.GetByID(itemInput.ItemID)
 		if err != nil {
-			return nil, fmt.Errorf("item %d not found: %v", itemInput.ItemID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("item %d", itemInput.ItemID), err)
 		}
 
 		var total float64
@@ -452,14 +467,14 @@ func (s *SalesReceiptService) CreateSalesReceipt(req CreateSalesReceiptRequest,
 		_, err = tx.Exec("INSERT INTO receipt_items (receipt_id, item_id, item_name, previous_value, current_value, qty, rate, total, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
 			receiptID, itemInput.ItemID, item.Name, previousValue, currentValue, qty, rateStr, total, itemStatus)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create receipt item: %v", err)
+			return nil, fmt.Errorf("failed to create receipt item: %w", err)
 		}
 	}
 
 	// Calculate and create splits
 	splitPreviews, err := s.CalculateSplitsForSalesReceipt(req, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Create splits within transaction
@@ -495,35 +510,52 @@ func (s *SalesReceiptService) CreateSalesReceipt(req CreateSalesReceiptRequest,
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			transactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
+	// Record the event in the same transaction so it exists exactly when the receipt does
+	err = outbox.Write(tx, req.BuildingID, outbox.SalesReceiptCreated, int(receiptID), outbox.Document{
+		ID:            int(receiptID),
+		TransactionID: int(transactionID),
+		Number:        req.ReceiptNo,
+		Date:          req.ReceiptDate,
+		Amount:        req.Amount,
+		PeopleID:      req.PeopleID,
+		UnitID:        req.UnitID,
+		Version:       1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("sales receipt", metrics.PostingCreated)
+	s.logger.Info("sales receipt created", "receipt_id", receiptID, "transaction_id", transactionID)
 
 	// Fetch created records after successful commit
 	createdTransaction, err := s.transactionRepo.GetByID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	createdReceipt, err := s.receiptRepo.GetByID(int(receiptID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sales receipt: %v", err)
+		return nil, fmt.Errorf("failed to fetch sales receipt: %w", err)
 	}
 
 	createdSplits, err := s.splitRepo.GetByTransactionID(int(transactionID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 
 	createdReceiptItems, err := s.receiptItemRepo.GetByReceiptID(int(receiptID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch receipt items: %v", err)
+		return nil, fmt.Errorf("failed to fetch receipt items: %w", err)
 	}
 
 	return &SalesReceiptResponse{
@@ -541,12 +573,12 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 	// Validate receipt exists
 	existingReceipt, err := s.receiptRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("sales receipt not found: %v", err)
+		return nil, apperrors.Lookup("sales receipt", err)
 	}
 
 	// Validate receipt belongs to the building
 	if existingReceipt.BuildingID != req.BuildingID {
-		return nil, fmt.Errorf("sales receipt does not belong to the specified building")
+		return nil, apperrors.Rule("sales receipt does not belong to the specified building")
 	}
 
 	// Check for duplicate receipt number (excluding current receipt)
@@ -555,22 +587,22 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 		return nil, err
 	}
 	if exists {
-		return nil, fmt.Errorf("receipt number already exists for this building")
+		return nil, apperrors.Conflict("receipt number already exists for this building")
 	}
 
 	// Validate request
 	if req.Amount <= 0 {
-		return nil, fmt.Errorf("amount must be greater than 0")
+		return nil, apperrors.Rule("amount must be greater than 0")
 	}
 
 	if len(req.Items) == 0 {
-		return nil, fmt.Errorf("sales receipt must have at least one item")
+		return nil, apperrors.Rule("sales receipt must have at least one item")
 	}
 
 	// Start database transaction
 	tx, err := s.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to start transaction: %v", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 
 	// Track if transaction was committed to avoid unnecessary rollback
@@ -581,6 +613,11 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 		}
 	}()
 
+	// Claim the next version first so a concurrent update fails instead of overwriting
+	if err := versioning.Bump(tx, "sales_receipt", req.ID, req.ExpectedVersion, "sales receipt"); err != nil {
+		return nil, err
+	}
+
 	// Update transaction record
 	var unitID interface{}
 	if req.UnitID != nil {
@@ -592,7 +629,7 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 	_, err = tx.Exec("UPDATE transactions SET transaction_date = ?, transaction_number = ?, memo = ?, unit_id = ? WHERE id = ?",
 		req.ReceiptDate, req.ReceiptNo, req.Description, unitID, existingReceipt.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update transaction: %v", err)
+		return nil, fmt.Errorf("failed to update transaction: %w", err)
 	}
 
 	// Update sales receipt
@@ -606,26 +643,26 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 	_, err = tx.Exec("UPDATE sales_receipt SET receipt_no = ?, receipt_date = ?, unit_id = ?, people_id = ?, account_id = ?, amount = ?, description = ? WHERE id = ?",
 		req.ReceiptNo, req.ReceiptDate, unitID, peopleID, req.AccountID, req.Amount, req.Description, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update sales receipt: %v", err)
+		return nil, fmt.Errorf("failed to update sales receipt: %w", err)
 	}
 
 	// Soft delete existing receipt_items (set status='0')
 	_, err = tx.Exec("UPDATE receipt_items SET status = '0' WHERE receipt_id = ?", req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to soft delete receipt items: %v", err)
+		return nil, fmt.Errorf("failed to soft delete receipt items: %w", err)
 	}
 
 	// Soft delete existing splits (set status='0')
 	_, err = tx.Exec("UPDATE splits SET status = '0' WHERE transaction_id = ?", existingReceipt.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to soft delete splits: %v", err)
+		return nil, fmt.Errorf("failed to soft delete splits: %w", err)
 	}
 
 	// Recreate receipt items
 	for _, itemInput := range req.Items {
 		item, _, _, _, _, _, err := s.itemRepo.GetByID(itemInput.ItemID)
 		if err != nil {
-			return nil, fmt.Errorf("item %d not found: %v", itemInput.ItemID, err)
+			return nil, apperrors.Lookup(fmt.Sprintf("item %d", itemInput.ItemID), err)
 		}
 
 		// Calculate total using rate from input
@@ -690,7 +727,7 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 		_, err = tx.Exec("INSERT INTO receipt_items (receipt_id, item_id, item_name, previous_value, current_value, qty, rate, total, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
 			req.ID, itemInput.ItemID, item.Name, previousValue, currentValue, qty, rateStr, total, itemStatus)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create receipt item: %v", err)
+			return nil, fmt.Errorf("failed to create receipt item: %w", err)
 		}
 	}
 
@@ -710,7 +747,7 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 
 	splitPreviews, err := s.CalculateSplitsForSalesReceipt(createReq, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate splits: %v", err)
+		return nil, fmt.Errorf("failed to calculate splits: %w", err)
 	}
 
 	// Recreate splits within transaction
@@ -747,31 +784,47 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 		_, err = tx.Exec("INSERT INTO splits (transaction_id, account_id, people_id, unit_id, debit, credit, status) VALUES (?, ?, ?, ?, ?, ?, ?)",
 			existingReceipt.TransactionID, preview.AccountID, peopleIDSplit, unitIDSplit, debit, credit, "1")
 		if err != nil {
-			return nil, fmt.Errorf("failed to create split: %v", err)
+			return nil, fmt.Errorf("failed to create split: %w", err)
 		}
 	}
 
+	err = outbox.Write(tx, existingReceipt.BuildingID, outbox.SalesReceiptUpdated, existingReceipt.ID, outbox.Document{
+		ID:            existingReceipt.ID,
+		TransactionID: existingReceipt.TransactionID,
+		Number:        req.ReceiptNo,
+		Date:          req.ReceiptDate,
+		Amount:        req.Amount,
+		PeopleID:      req.PeopleID,
+		UnitID:        req.UnitID,
+		Version:       req.ExpectedVersion + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	committed = true
+	metrics.RecordPosting("sales receipt", metrics.PostingUpdated)
+	s.logger.Info("sales receipt updated", "receipt_id", existingReceipt.ID, "transaction_id", existingReceipt.TransactionID)
 
 	// Fetch updated records after successful commit
 	updatedTransaction, err := s.transactionRepo.GetByID(existingReceipt.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch transaction: %v", err)
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
 	updatedReceipt, err := s.receiptRepo.GetByID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sales receipt: %v", err)
+		return nil, fmt.Errorf("failed to fetch sales receipt: %w", err)
 	}
 
 	// Get only active splits (status='1')
 	updatedSplits, err := s.splitRepo.GetByTransactionID(existingReceipt.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch splits: %v", err)
+		return nil, fmt.Errorf("failed to fetch splits: %w", err)
 	}
 	// Filter to only active splits
 	activeSplits := []splits.Split{}
@@ -784,7 +837,7 @@ func (s *SalesReceiptService) UpdateSalesReceipt(req UpdateSalesReceiptRequest,
 	// Get only active receipt items (status='1')
 	updatedReceiptItems, err := s.receiptItemRepo.GetByReceiptID(req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch receipt items: %v", err)
+		return nil, fmt.Errorf("failed to fetch receipt items: %w", err)
 	}
 	// Filter to only active items
 	activeItems := []receipt_items.ReceiptItem{}
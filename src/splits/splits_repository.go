@@ -2,7 +2,8 @@ package splits
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type SplitRepository interface {
@@ -137,7 +138,7 @@ func (r *splitRepo) GetByID(id int) (Split, error) {
 		Scan(&split.ID, &split.TransactionID, &split.AccountID, &split.PeopleID, &split.UnitID, &split.Debit, &split.Credit, &split.Status, &split.CreatedAt, &split.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return split, fmt.Errorf("split not found")
+		return split, apperrors.NotFound("split")
 	}
 
 	return split, err
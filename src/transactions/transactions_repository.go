@@ -2,7 +2,8 @@ package transactions
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type TransactionRepository interface {
@@ -64,7 +65,7 @@ func (r *transactionRepo) GetByID(id int) (Transaction, error) {
 		Scan(&transaction.ID, &transaction.Type, &transaction.TransactionDate, &transaction.TransactionNumber, &transaction.Memo, &transaction.Status, &transaction.BuildingID, &transaction.UserID, &transaction.UnitID, &transaction.CreatedAt, &transaction.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return transaction, fmt.Errorf("transaction not found")
+		return transaction, apperrors.NotFound("transaction")
 	}
 
 	return transaction, err
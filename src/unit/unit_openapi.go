@@ -0,0 +1,11 @@
+package unit
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"UnitHandler.GetUnits":           {Summary: "List units of all buildings", Response: []UnitResponse{}},
+	"UnitHandler.GetUnitsByBuilding": {Summary: "List a building's units", Response: []UnitResponse{}},
+	"UnitHandler.GetUnit":            {Summary: "Get a unit", Response: UnitResponse{}},
+	"UnitHandler.CreateUnit":         {Summary: "Create a unit", Request: Unit{}, Response: Unit{}},
+	"UnitHandler.UpdateUnit":         {Summary: "Update a unit", Request: Unit{}, Response: Unit{}},
+}
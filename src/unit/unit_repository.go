@@ -2,8 +2,8 @@ package unit
 
 import (
 	"database/sql"
-	"fmt"
 
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 	"github.com/mysecodgit/go_accounting/src/building"
 )
 
@@ -103,7 +103,7 @@ func (r *unitRepo) GetByID(id int) (Unit, building.Building, error) {
 			&b.ID, &b.Name, &b.CreatedAt, &b.UpdatedAt)
 
 	if err == sql.ErrNoRows {
-		return unit, b, fmt.Errorf("id does not exist")
+		return unit, b, apperrors.NotFound("unit")
 	}
 
 	return unit, b, err
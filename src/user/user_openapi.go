@@ -0,0 +1,10 @@
+package user
+
+import "github.com/mysecodgit/go_accounting/src/openapi"
+
+var OpenAPI = openapi.Handlers{
+	"UserHandler.GetUsers":   {Summary: "List users", Response: []UserResponse{}},
+	"UserHandler.GetUser":    {Summary: "Get a user", Response: UserResponse{}},
+	"UserHandler.CreateUser": {Summary: "Register a user", Request: RegisterUserRequest{}, Response: UserResponse{}},
+	"UserHandler.UpdateUser": {Summary: "Update a user", Description: "Not implemented yet; returns the list of users.", Response: []UserResponse{}},
+}
@@ -3,7 +3,8 @@ package user
 
 import (
 	"database/sql"
-	"fmt"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
 )
 
 type UserRepository interface {
@@ -36,7 +37,7 @@ func (r *userRepo) GetByID(id int) (User, error) {
 		Scan(&user.ID, &user.Name, &user.Username, &user.Phone)
 	
 	if err == sql.ErrNoRows {
-		return user, fmt.Errorf("id does not exist")
+		return user, apperrors.NotFound("user")
 	}
 	
 	return user, err
@@ -1,13 +1,21 @@
 package user
 
-import "fmt"
+import "log/slog"
 
 type UserService struct {
-	repo UserRepository
+	repo   UserRepository
+	logger *slog.Logger
 }
 
-func NewUserService(repo UserRepository) *UserService {
-	return &UserService{repo: repo}
+func NewUserService(repo UserRepository, logger *slog.Logger) *UserService {
+	return &UserService{repo: repo, logger: logger}
+}
+
+// WithLogger returns a copy of the service that logs with logger, e.g. a request's logger
+func (s *UserService) WithLogger(logger *slog.Logger) *UserService {
+	scoped := *s
+	scoped.logger = logger
+	return &scoped
 }
 
 func (s *UserService) Register(user RegisterUserRequest) (*UserResponse, map[string]string, error) {
@@ -25,6 +33,7 @@ func (s *UserService) Register(user RegisterUserRequest) (*UserResponse, map[str
 	if err != nil {
 		return nil, nil, err // internal/server error
 	}
+	s.logger.Info("user registered", "new_user_id", createdUser.ID)
 
 	// Model -> DTO response
 	response := createdUser.ToUserResponse()
@@ -51,10 +60,10 @@ func (s *UserService) GetAllUsers() ([]UserResponse, error) {
 
 func (s *UserService) GetUserByID(id int) (*UserResponse, error) {
 	user, err := s.repo.GetByID(id)
-	fmt.Println("The user is ", user)
 	if err != nil {
 		return nil, err
 	}
+	s.logger.Debug("user loaded", "user_id", user.ID)
 
 	response := user.ToUserResponse()
 	return &response, nil
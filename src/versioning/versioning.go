@@ -0,0 +1,75 @@
+package versioning
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+)
+
+// Executor is satisfied by both *sql.DB and *sql.Tx
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Bump moves a row from the expected version to the next one. Run it in the update's
+// transaction before changing anything else: it fails with a version_conflict error when
+// another update got there first, and otherwise holds the row until the transaction ends.
+func Bump(db Executor, table string, id int, expected int, resource string) error {
+	result, err := db.Exec("UPDATE "+table+" SET version = version + 1 WHERE id = ? AND version = ?", id, expected)
+	if err != nil {
+		return fmt.Errorf("failed to update %s version: %w", resource, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update %s version: %w", resource, err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var current int
+	err = db.QueryRow("SELECT version FROM "+table+" WHERE id = ?", id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return apperrors.NotFound(resource)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s version: %w", resource, err)
+	}
+	return apperrors.Newf(apperrors.CodeVersionConflict, "%s has been modified since it was read (current version %d)", resource, current).
+		WithDetail("current_version", current)
+}
+
+// ETag formats a version as an entity tag
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+func SetETag(c *gin.Context, version int) {
+	c.Header("ETag", ETag(version))
+}
+
+// IfMatch reads the version the client is updating from the If-Match header. When the
+// header is missing or malformed it writes the error response and returns false.
+func IfMatch(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		apperrors.Respond(c, apperrors.New(apperrors.CodePreconditionRequired, "If-Match header with the document version is required"))
+		return 0, false
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	if unquoted, err := strconv.Unquote(tag); err == nil {
+		tag = unquoted
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		apperrors.Respond(c, apperrors.BadRequest("If-Match must be the document version, e.g. \"3\""))
+		return 0, false
+	}
+	return version, true
+}
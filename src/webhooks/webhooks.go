@@ -0,0 +1,101 @@
+// Package webhooks delivers outbox events to per-building HTTP subscriptions.
+//
+// Each delivery is a POST of a Message with these headers:
+//
+//	X-Webhook-Event      event type, e.g. "invoice.created"
+//	X-Webhook-Delivery   delivery id, stable across retries
+//	X-Webhook-Timestamp  unix seconds of the attempt
+//	X-Webhook-Signature  "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the subscription secret
+//
+// A 2xx response marks the delivery delivered. Anything else is retried with exponential
+// backoff until the configured number of attempts, after which the delivery is dead and
+// stays in the dead-letter list until it is replayed.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// Subscription statuses
+const (
+	StatusActive   = "active"
+	StatusDisabled = "disabled"
+)
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+type Subscription struct {
+	ID         int      `json:"id"`
+	BuildingID int      `json:"building_id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"-"`
+	EventTypes []string `json:"event_types"` // Empty means every event type
+	Status     string   `json:"status"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+// Wants reports whether the subscription receives events of the given type
+func (s Subscription) Wants(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, wanted := range s.EventTypes {
+		if wanted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type Delivery struct {
+	ID             int     `json:"id"`
+	BuildingID     int     `json:"building_id"`
+	EventID        int     `json:"event_id"`
+	EventType      string  `json:"event_type"`
+	SubscriptionID int     `json:"subscription_id"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	NextAttemptAt  string  `json:"next_attempt_at"`
+	LastStatusCode *int    `json:"last_status_code"`
+	LastError      *string `json:"last_error"`
+	DeliveredAt    *string `json:"delivered_at"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// Message is the JSON body posted to subscribers. ID is the outbox event id, so
+// receivers can drop duplicates caused by retries and replays.
+type Message struct {
+	ID          int             `json:"id"`
+	Type        string          `json:"type"`
+	BuildingID  int             `json:"building_id"`
+	AggregateID int             `json:"aggregate_id"`
+	CreatedAt   string          `json:"created_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Sign returns the X-Webhook-Signature value for a body sent at timestamp
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
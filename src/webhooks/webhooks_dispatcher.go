@@ -0,0 +1,197 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/outbox"
+)
+
+const (
+	// batchSize bounds the events fanned out and the deliveries sent per poll
+	batchSize = 100
+	// firstRetryDelay doubles after every failed attempt, up to maxRetryDelay
+	firstRetryDelay = 30 * time.Second
+	maxRetryDelay   = 6 * time.Hour
+	// maxErrorLength bounds the response excerpt kept in last_error
+	maxErrorLength = 1000
+)
+
+// Dispatcher moves outbox events into per-subscription deliveries and sends them. Several
+// application instances can run one each: events and deliveries are claimed atomically.
+type Dispatcher struct {
+	repo        WebhookRepository
+	outboxRepo  outbox.OutboxRepository
+	client      *http.Client
+	logger      *slog.Logger
+	interval    time.Duration
+	maxAttempts int
+}
+
+func NewDispatcher(repo WebhookRepository, outboxRepo outbox.OutboxRepository, logger *slog.Logger, interval time.Duration, timeout time.Duration, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		repo:        repo,
+		outboxRepo:  outboxRepo,
+		client:      &http.Client{Timeout: timeout},
+		logger:      logger,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run polls the outbox every interval until ctx is cancelled. A poll in progress
+// finishes its current attempt before Run returns.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.DispatchOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce fans out new events and sends the deliveries that are due
+func (d *Dispatcher) DispatchOnce(ctx context.Context) {
+	if err := d.fanOut(); err != nil {
+		d.logger.Error("webhook fan-out failed", "error", err.Error())
+	}
+
+	// Each delivery is claimed just before it is sent, so its lease only has to outlast its
+	// own attempt, not the ones queued before it
+	for sent := 0; sent < batchSize && ctx.Err() == nil; sent++ {
+		attempts, err := d.repo.ClaimDue(time.Now(), d.client.Timeout+time.Minute, 1)
+		if err != nil {
+			d.logger.Error("failed to load due webhook deliveries", "error", err.Error())
+			return
+		}
+		if len(attempts) == 0 {
+			return
+		}
+		d.send(attempts[0])
+	}
+}
+
+func (d *Dispatcher) fanOut() error {
+	events, err := d.outboxRepo.Undispatched(batchSize)
+	if err != nil {
+		return err
+	}
+
+	subscriptions := map[int][]Subscription{}
+	for _, event := range events {
+		buildingSubscriptions, ok := subscriptions[event.BuildingID]
+		if !ok {
+			buildingSubscriptions, err = d.repo.ListSubscriptions(event.BuildingID)
+			if err != nil {
+				return err
+			}
+			subscriptions[event.BuildingID] = buildingSubscriptions
+		}
+
+		if _, err := d.repo.FanOut(event, matchingSubscriptions(buildingSubscriptions, event.EventType), time.Now()); err != nil {
+			return fmt.Errorf("event %d: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) send(attempt Attempt) {
+	delivery := attempt.Delivery
+	logger := d.logger.With("delivery_id", delivery.ID, "event_id", delivery.EventID, "event_type", delivery.EventType, "subscription_id", delivery.SubscriptionID)
+	attempts := delivery.Attempts + 1
+
+	statusCode, err := d.post(attempt)
+	if err == nil {
+		if err := d.repo.MarkDelivered(delivery.ID, attempts, statusCode, time.Now()); err != nil {
+			logger.Error("failed to record webhook delivery", "error", err.Error())
+		}
+		logger.Debug("webhook delivered", "status", statusCode, "attempts", attempts)
+		return
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	message := err.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+
+	var nextAttemptAt *time.Time
+	if attempts < d.maxAttempts {
+		next := time.Now().Add(retryDelay(attempts))
+		nextAttemptAt = &next
+		logger.Warn("webhook delivery failed", "error", message, "attempts", attempts, "next_attempt_at", next.UTC().Format(timeLayout))
+	} else {
+		logger.Error("webhook delivery dead-lettered", "error", message, "attempts", attempts)
+	}
+
+	if err := d.repo.MarkFailed(delivery.ID, attempts, code, message, nextAttemptAt); err != nil {
+		logger.Error("failed to record webhook delivery", "error", err.Error())
+	}
+}
+
+// post sends one attempt and returns the response status; any non-2xx status is an error
+func (d *Dispatcher) post(attempt Attempt) (int, error) {
+	event := attempt.Event
+	body, err := json.Marshal(Message{
+		ID:          event.ID,
+		Type:        event.EventType,
+		BuildingID:  event.BuildingID,
+		AggregateID: event.AggregateID,
+		CreatedAt:   event.CreatedAt,
+		Data:        event.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, attempt.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go_accounting-webhooks")
+	req.Header.Set(HeaderEvent, event.EventType)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(attempt.Delivery.ID))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(attempt.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, excerpt)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryDelay returns the wait after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,73 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mysecodgit/go_accounting/src/outbox"
+)
+
+type CreateSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types"` // Empty subscribes to every event type
+}
+
+type UpdateSubscriptionRequest struct {
+	URL          string   `json:"url" binding:"required"`
+	EventTypes   []string `json:"event_types"`
+	Status       string   `json:"status" binding:"required"` // active or disabled
+	RotateSecret bool     `json:"rotate_secret"`             // Issue a new signing secret
+}
+
+// SubscriptionResponse carries the signing secret only when it was just issued
+type SubscriptionResponse struct {
+	Subscription Subscription `json:"subscription"`
+	Secret       *string      `json:"secret,omitempty"`
+}
+
+type EventTypesResponse struct {
+	EventTypes []string `json:"event_types"`
+}
+
+func (r CreateSubscriptionRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	validateURL(r.URL, errors)
+	validateEventTypes(r.EventTypes, errors)
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func (r UpdateSubscriptionRequest) Validate() map[string]string {
+	errors := make(map[string]string)
+	validateURL(r.URL, errors)
+	validateEventTypes(r.EventTypes, errors)
+	if r.Status != StatusActive && r.Status != StatusDisabled {
+		errors["status"] = "Status must be active or disabled"
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+func validateURL(value string, errors map[string]string) {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		errors["url"] = "URL must be an absolute http or https URL"
+		return
+	}
+	if len(value) > 2048 {
+		errors["url"] = "URL must be at most 2048 characters"
+	}
+}
+
+func validateEventTypes(eventTypes []string, errors map[string]string) {
+	for i, eventType := range eventTypes {
+		if !outbox.IsEventType(eventType) {
+			errors[fmt.Sprintf("event_types[%d]", i)] = "Event type must be one of: " + strings.Join(outbox.EventTypes, ", ")
+		}
+	}
+}
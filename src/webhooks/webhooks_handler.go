@@ -0,0 +1,247 @@
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/logging"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+)
+
+type WebhookHandler struct {
+	service *WebhookService
+}
+
+func NewWebhookHandler(service *WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// GET /buildings/:id/webhooks/event-types
+func (h *WebhookHandler) GetEventTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, EventTypesResponse{EventTypes: outbox.EventTypes})
+}
+
+// GET /buildings/:id/webhooks
+func (h *WebhookHandler) GetSubscriptions(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	subscriptions, err := h.service.ListSubscriptions(buildingID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// GET /buildings/:id/webhooks/:webhookId
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	buildingID, id, ok := subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.service.GetSubscription(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscription)
+}
+
+// POST /buildings/:id/webhooks
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	response, validationErr, err := h.service.WithLogger(logging.FromGin(c)).CreateSubscription(buildingID, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PUT /buildings/:id/webhooks/:webhookId
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	var req UpdateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apperrors.Respond(c, apperrors.FromBinding(err))
+		return
+	}
+
+	buildingID, id, ok := subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	response, validationErr, err := h.service.WithLogger(logging.FromGin(c)).UpdateSubscription(buildingID, id, req)
+	if validationErr != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErr))
+		return
+	}
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE /buildings/:id/webhooks/:webhookId
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	buildingID, id, ok := subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.WithLogger(logging.FromGin(c)).DeleteSubscription(buildingID, id); err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook subscription deleted successfully"})
+}
+
+// GET /buildings/:id/webhook-deliveries?status=dead&subscription_id=1
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	params, validationErrors := DeliveryListSpec.Parse(c.Request.URL.Query())
+	if validationErrors == nil {
+		validationErrors = map[string]string{}
+	}
+	switch params.Filters.Status {
+	case "", DeliveryPending, DeliveryDelivered, DeliveryDead:
+	default:
+		validationErrors["status"] = "Status must be pending, delivered or dead"
+	}
+	var subscriptionID *int
+	if value := c.Query("subscription_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			validationErrors["subscription_id"] = "Subscription ID must be a positive integer"
+		} else {
+			subscriptionID = &id
+		}
+	}
+	if len(validationErrors) > 0 {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(buildingID, subscriptionID, params)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// POST /buildings/:id/webhook-deliveries/:deliveryId/replay
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	id, err := strconv.Atoi(c.Param("deliveryId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Delivery ID"))
+		return
+	}
+
+	delivery, err := h.service.WithLogger(logging.FromGin(c)).ReplayDelivery(buildingID, id)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// GET /buildings/:id/events
+func (h *WebhookHandler) GetEvents(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+
+	params, validationErrors := outbox.EventListSpec.Parse(c.Request.URL.Query())
+	if validationErrors != nil {
+		apperrors.Respond(c, apperrors.Validation(validationErrors))
+		return
+	}
+
+	events, err := h.service.ListEvents(buildingID, params)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// POST /buildings/:id/events/:eventId/replay
+func (h *WebhookHandler) ReplayEvent(c *gin.Context) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return
+	}
+	eventID, err := strconv.Atoi(c.Param("eventId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Event ID"))
+		return
+	}
+
+	event, err := h.service.WithLogger(logging.FromGin(c)).ReplayEvent(buildingID, eventID)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// subscriptionParams reads the building and subscription ids, responding when either is invalid
+func subscriptionParams(c *gin.Context) (int, int, bool) {
+	buildingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Building ID"))
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("webhookId"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.BadRequest("Invalid Webhook ID"))
+		return 0, 0, false
+	}
+	return buildingID, id, true
+}
@@ -0,0 +1,42 @@
+package webhooks
+
+import (
+	"github.com/mysecodgit/go_accounting/src/openapi"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+var OpenAPI = openapi.Handlers{
+	"WebhookHandler.GetEventTypes":    {Summary: "List the event types a webhook can subscribe to", Response: EventTypesResponse{}},
+	"WebhookHandler.GetSubscriptions": {Summary: "List webhook subscriptions", Response: []Subscription{}},
+	"WebhookHandler.GetSubscription":  {Summary: "Get a webhook subscription", Response: Subscription{}},
+	"WebhookHandler.CreateSubscription": {
+		Summary:     "Create a webhook subscription",
+		Description: "The response includes the signing secret. It is not shown again; rotate it with an update if it is lost.",
+		Request:     CreateSubscriptionRequest{},
+		Response:    SubscriptionResponse{},
+	},
+	"WebhookHandler.UpdateSubscription": {
+		Summary:     "Update, disable or rotate the secret of a webhook subscription",
+		Description: "Deliveries to a disabled subscription wait until it is active again.",
+		Request:     UpdateSubscriptionRequest{},
+		Response:    SubscriptionResponse{},
+	},
+	"WebhookHandler.DeleteSubscription": {Summary: "Delete a webhook subscription and its deliveries", Response: openapi.Message{}},
+	"WebhookHandler.GetDeliveries": {
+		Summary:     "List webhook deliveries",
+		Description: "Filter with status=dead for the dead-letter list.",
+		Response:    pagination.Page[Delivery]{},
+		List:        &DeliveryListSpec,
+		Query: []openapi.Param{
+			{Name: "subscription_id", Type: "integer", Format: "int32", Description: "Only deliveries to this subscription"},
+		},
+	},
+	"WebhookHandler.ReplayDelivery": {Summary: "Send a delivery again from its first attempt", Response: Delivery{}},
+	"WebhookHandler.GetEvents":      {Summary: "List domain events recorded in the outbox", Response: pagination.Page[outbox.Event]{}, List: &outbox.EventListSpec},
+	"WebhookHandler.ReplayEvent": {
+		Summary:     "Deliver an event again to every active subscription that receives it",
+		Description: "Existing deliveries of the event are reset; subscriptions created after the event receive it for the first time.",
+		Response:    outbox.Event{},
+	},
+}
@@ -0,0 +1,385 @@
+package webhooks
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/dialect"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+// Attempt is a claimed delivery with everything needed to send it
+type Attempt struct {
+	Delivery Delivery
+	URL      string
+	Secret   string
+	Event    outbox.Event
+}
+
+type WebhookRepository interface {
+	CreateSubscription(subscription Subscription) (Subscription, error)
+	GetSubscription(buildingID int, id int) (Subscription, error)
+	ListSubscriptions(buildingID int) ([]Subscription, error)
+	UpdateSubscription(subscription Subscription) error
+	DeleteSubscription(buildingID int, id int) error
+	FanOut(event outbox.Event, subscriptionIDs []int, now time.Time) (bool, error)
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]Attempt, error)
+	MarkDelivered(id int, attempts int, statusCode int, now time.Time) error
+	MarkFailed(id int, attempts int, statusCode *int, message string, nextAttemptAt *time.Time) error
+	GetDelivery(buildingID int, id int) (Delivery, error)
+	ListDeliveries(buildingID int, subscriptionID *int, params pagination.Params) ([]Delivery, int, error)
+	ResetDelivery(id int, now time.Time) error
+	ReplayEvent(event outbox.Event, subscriptionIDs []int, now time.Time) error
+}
+
+type webhookRepo struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepo{db: db}
+}
+
+const subscriptionColumns = "id, building_id, url, secret, event_types, status, created_at, updated_at"
+
+const deliveryColumns = "d.id, d.building_id, d.event_id, e.event_type, d.subscription_id, d.status, d.attempts, d.next_attempt_at, d.last_status_code, d.last_error, d.delivered_at, d.created_at"
+
+const deliveryFrom = " FROM webhook_deliveries d JOIN outbox_events e ON e.id = d.event_id"
+
+// DeliveryListSpec describes the query string of the delivery list; status=dead is the
+// dead-letter list
+var DeliveryListSpec = pagination.Spec{
+	IDColumn: "d.id",
+	Sorts: map[string]string{
+		"id":           "d.id",
+		"date":         "d.created_at",
+		"next_attempt": "d.next_attempt_at",
+	},
+	DefaultSort:   "-id",
+	DateColumn:    "d.created_at",
+	SearchColumns: []string{"e.event_type"},
+	StatusFilter:  "d.status = ?",
+}
+
+// DeliverySortKey returns the cursor values of a delivery for the list's sort
+func DeliverySortKey(delivery Delivery, sort string) (interface{}, int) {
+	switch sort {
+	case "date":
+		return delivery.CreatedAt, delivery.ID
+	case "next_attempt":
+		return delivery.NextAttemptAt, delivery.ID
+	default:
+		return delivery.ID, delivery.ID
+	}
+}
+
+func (r *webhookRepo) CreateSubscription(subscription Subscription) (Subscription, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO webhook_subscriptions (building_id, url, secret, event_types, status) VALUES (?, ?, ?, ?, ?)",
+		subscription.BuildingID, subscription.URL, subscription.Secret, strings.Join(subscription.EventTypes, ","), subscription.Status,
+	)
+	if err != nil {
+		return Subscription{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Subscription{}, err
+	}
+	return r.GetSubscription(subscription.BuildingID, int(id))
+}
+
+func (r *webhookRepo) GetSubscription(buildingID int, id int) (Subscription, error) {
+	return scanSubscription(r.db.QueryRow(
+		"SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE id = ? AND building_id = ?", id, buildingID,
+	))
+}
+
+func (r *webhookRepo) ListSubscriptions(buildingID int) ([]Subscription, error) {
+	rows, err := r.db.Query("SELECT "+subscriptionColumns+" FROM webhook_subscriptions WHERE building_id = ? ORDER BY id", buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []Subscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (r *webhookRepo) UpdateSubscription(subscription Subscription) error {
+	_, err := r.db.Exec(
+		"UPDATE webhook_subscriptions SET url = ?, secret = ?, event_types = ?, status = ?, updated_at = ? WHERE id = ? AND building_id = ?",
+		subscription.URL, subscription.Secret, strings.Join(subscription.EventTypes, ","), subscription.Status,
+		time.Now().UTC().Format(timeLayout), subscription.ID, subscription.BuildingID,
+	)
+	return err
+}
+
+// DeleteSubscription removes a subscription and its delivery history
+func (r *webhookRepo) DeleteSubscription(buildingID int, id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := tx.Exec("DELETE FROM webhook_deliveries WHERE subscription_id = ? AND building_id = ?", id, buildingID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM webhook_subscriptions WHERE id = ? AND building_id = ?", id, buildingID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// FanOut marks an event dispatched and queues one delivery per subscription. It returns
+// false when another dispatcher already took the event.
+func (r *webhookRepo) FanOut(event outbox.Event, subscriptionIDs []int, now time.Time) (bool, error) {
+	nowStr := now.UTC().Format(timeLayout)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec("UPDATE outbox_events SET dispatched_at = ? WHERE id = ? AND dispatched_at IS NULL", nowStr, event.ID)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+
+	for _, subscriptionID := range subscriptionIDs {
+		_, err := tx.Exec(
+			"INSERT INTO webhook_deliveries (building_id, event_id, subscription_id, status, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, 0, ?, ?)",
+			event.BuildingID, event.ID, subscriptionID, DeliveryPending, nowStr, nowStr,
+		)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	committed = true
+	return true, nil
+}
+
+// ClaimDue returns pending deliveries of active subscriptions whose next attempt is due,
+// pushing each one's next attempt out by lease so that no other dispatcher sends it
+// meanwhile. A dispatcher that dies mid-attempt leaves the delivery to be retried after
+// the lease.
+func (r *webhookRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]Attempt, error) {
+	nowStr := now.UTC().Format(timeLayout)
+
+	rows, err := r.db.Query(
+		"SELECT "+deliveryColumns+", s.url, s.secret, e.aggregate_type, e.aggregate_id, e.payload, e.created_at"+deliveryFrom+
+			" JOIN webhook_subscriptions s ON s.id = d.subscription_id"+
+			" WHERE d.status = ? AND d.next_attempt_at <= ? AND s.status = ? ORDER BY d.next_attempt_at, d.id LIMIT ?",
+		DeliveryPending, nowStr, StatusActive, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []Attempt{}
+	for rows.Next() {
+		var attempt Attempt
+		var payload string
+		d := &attempt.Delivery
+		err := rows.Scan(&d.ID, &d.BuildingID, &d.EventID, &d.EventType, &d.SubscriptionID, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt,
+			&attempt.URL, &attempt.Secret, &attempt.Event.AggregateType, &attempt.Event.AggregateID, &payload, &attempt.Event.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		attempt.Event.ID = d.EventID
+		attempt.Event.BuildingID = d.BuildingID
+		attempt.Event.EventType = d.EventType
+		attempt.Event.Payload = []byte(payload)
+		candidates = append(candidates, attempt)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	leaseUntil := now.Add(lease).UTC().Format(timeLayout)
+	claimed := []Attempt{}
+	for _, attempt := range candidates {
+		result, err := r.db.Exec(
+			"UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?",
+			leaseUntil, attempt.Delivery.ID, DeliveryPending, nowStr,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			claimed = append(claimed, attempt)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *webhookRepo) MarkDelivered(id int, attempts int, statusCode int, now time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE webhook_deliveries SET status = ?, attempts = ?, last_status_code = ?, last_error = NULL, delivered_at = ? WHERE id = ?",
+		DeliveryDelivered, attempts, statusCode, now.UTC().Format(timeLayout), id,
+	)
+	return err
+}
+
+// MarkFailed records a failed attempt. A nil nextAttemptAt dead-letters the delivery.
+func (r *webhookRepo) MarkFailed(id int, attempts int, statusCode *int, message string, nextAttemptAt *time.Time) error {
+	if nextAttemptAt == nil {
+		_, err := r.db.Exec(
+			"UPDATE webhook_deliveries SET status = ?, attempts = ?, last_status_code = ?, last_error = ? WHERE id = ?",
+			DeliveryDead, attempts, statusCode, message, id,
+		)
+		return err
+	}
+	_, err := r.db.Exec(
+		"UPDATE webhook_deliveries SET attempts = ?, last_status_code = ?, last_error = ?, next_attempt_at = ? WHERE id = ?",
+		attempts, statusCode, message, nextAttemptAt.UTC().Format(timeLayout), id,
+	)
+	return err
+}
+
+func (r *webhookRepo) GetDelivery(buildingID int, id int) (Delivery, error) {
+	return scanDelivery(r.db.QueryRow("SELECT "+deliveryColumns+deliveryFrom+" WHERE d.id = ? AND d.building_id = ?", id, buildingID))
+}
+
+func (r *webhookRepo) ListDeliveries(buildingID int, subscriptionID *int, params pagination.Params) ([]Delivery, int, error) {
+	where, args := DeliveryListSpec.Where(params)
+	where = " WHERE d.building_id = ?" + where
+	args = append([]interface{}{buildingID}, args...)
+	if subscriptionID != nil {
+		where += " AND d.subscription_id = ?"
+		args = append(args, *subscriptionID)
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*)"+deliveryFrom+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	after, afterArgs := DeliveryListSpec.After(params)
+	order, orderArgs := DeliveryListSpec.OrderAndLimit(params)
+	args = append(append(args, afterArgs...), orderArgs...)
+
+	rows, err := r.db.Query("SELECT "+deliveryColumns+deliveryFrom+where+after+order, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, total, rows.Err()
+}
+
+// ResetDelivery queues a delivery to be sent again from its first attempt
+func (r *webhookRepo) ResetDelivery(id int, now time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ?, last_status_code = NULL, last_error = NULL, delivered_at = NULL WHERE id = ?",
+		DeliveryPending, now.UTC().Format(timeLayout), id,
+	)
+	return err
+}
+
+// ReplayEvent queues an event again for the given subscriptions, resetting deliveries
+// that already exist
+func (r *webhookRepo) ReplayEvent(event outbox.Event, subscriptionIDs []int, now time.Time) error {
+	nowStr := now.UTC().Format(timeLayout)
+	query := dialect.Current.Upsert(
+		"webhook_deliveries",
+		[]string{"building_id", "event_id", "subscription_id", "status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at", "created_at"},
+		[]string{"event_id", "subscription_id"},
+		[]string{"status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at"},
+	)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	for _, subscriptionID := range subscriptionIDs {
+		if _, err := tx.Exec(query, event.BuildingID, event.ID, subscriptionID, DeliveryPending, 0, nowStr, nil, nil, nil, nowStr); err != nil {
+			return err
+		}
+	}
+	// The event may not have been fanned out yet; it now has its deliveries
+	if _, err := tx.Exec("UPDATE outbox_events SET dispatched_at = ? WHERE id = ? AND dispatched_at IS NULL", nowStr, event.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubscription(row scanner) (Subscription, error) {
+	var subscription Subscription
+	var eventTypes string
+	err := row.Scan(&subscription.ID, &subscription.BuildingID, &subscription.URL, &subscription.Secret, &eventTypes,
+		&subscription.Status, &subscription.CreatedAt, &subscription.UpdatedAt)
+	subscription.EventTypes = []string{}
+	for _, eventType := range strings.Split(eventTypes, ",") {
+		if eventType != "" {
+			subscription.EventTypes = append(subscription.EventTypes, eventType)
+		}
+	}
+	return subscription, err
+}
+
+func scanDelivery(row scanner) (Delivery, error) {
+	var d Delivery
+	err := row.Scan(&d.ID, &d.BuildingID, &d.EventID, &d.EventType, &d.SubscriptionID, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt)
+	return d, err
+}
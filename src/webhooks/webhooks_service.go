@@ -0,0 +1,203 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mysecodgit/go_accounting/src/apperrors"
+	"github.com/mysecodgit/go_accounting/src/outbox"
+	"github.com/mysecodgit/go_accounting/src/pagination"
+)
+
+type WebhookService struct {
+	repo       WebhookRepository
+	outboxRepo outbox.OutboxRepository
+	logger     *slog.Logger
+}
+
+func NewWebhookService(repo WebhookRepository, outboxRepo outbox.OutboxRepository, logger *slog.Logger) *WebhookService {
+	return &WebhookService{repo: repo, outboxRepo: outboxRepo, logger: logger}
+}
+
+// WithLogger returns a copy of the service that logs to logger, e.g. the request's logger
+func (s *WebhookService) WithLogger(logger *slog.Logger) *WebhookService {
+	copy := *s
+	copy.logger = logger
+	return &copy
+}
+
+func (s *WebhookService) ListSubscriptions(buildingID int) ([]Subscription, error) {
+	subscriptions, err := s.repo.ListSubscriptions(buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (s *WebhookService) GetSubscription(buildingID int, id int) (Subscription, error) {
+	subscription, err := s.repo.GetSubscription(buildingID, id)
+	if err != nil {
+		return Subscription{}, apperrors.Lookup("webhook subscription", err)
+	}
+	return subscription, nil
+}
+
+// CreateSubscription registers a webhook. The signing secret is only returned here and
+// when it is rotated.
+func (s *WebhookService) CreateSubscription(buildingID int, req CreateSubscriptionRequest) (*SubscriptionResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscription, err := s.repo.CreateSubscription(Subscription{
+		BuildingID: buildingID,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Status:     StatusActive,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	s.logger.Info("webhook subscription created", "subscription_id", subscription.ID)
+	return &SubscriptionResponse{Subscription: subscription, Secret: &secret}, nil, nil
+}
+
+func (s *WebhookService) UpdateSubscription(buildingID int, id int, req UpdateSubscriptionRequest) (*SubscriptionResponse, map[string]string, error) {
+	if validationErrors := req.Validate(); validationErrors != nil {
+		return nil, validationErrors, nil
+	}
+
+	subscription, err := s.GetSubscription(buildingID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subscription.URL = req.URL
+	subscription.EventTypes = req.EventTypes
+	subscription.Status = req.Status
+
+	response := &SubscriptionResponse{}
+	if req.RotateSecret {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, nil, err
+		}
+		subscription.Secret = secret
+		response.Secret = &secret
+	}
+
+	if err := s.repo.UpdateSubscription(subscription); err != nil {
+		return nil, nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	response.Subscription, err = s.GetSubscription(buildingID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.logger.Info("webhook subscription updated", "subscription_id", id, "status", subscription.Status, "secret_rotated", req.RotateSecret)
+	return response, nil, nil
+}
+
+func (s *WebhookService) DeleteSubscription(buildingID int, id int) error {
+	if _, err := s.GetSubscription(buildingID, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSubscription(buildingID, id); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	s.logger.Info("webhook subscription deleted", "subscription_id", id)
+	return nil
+}
+
+func (s *WebhookService) ListDeliveries(buildingID int, subscriptionID *int, params pagination.Params) (pagination.Page[Delivery], error) {
+	deliveries, total, err := s.repo.ListDeliveries(buildingID, subscriptionID, params)
+	if err != nil {
+		return pagination.Page[Delivery]{}, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return pagination.NewPage(deliveries, total, params, DeliverySortKey), nil
+}
+
+// ReplayDelivery sends a delivery again from its first attempt, typically one taken
+// from the dead-letter list
+func (s *WebhookService) ReplayDelivery(buildingID int, id int) (Delivery, error) {
+	if _, err := s.repo.GetDelivery(buildingID, id); err != nil {
+		return Delivery{}, apperrors.Lookup("webhook delivery", err)
+	}
+	if err := s.repo.ResetDelivery(id, time.Now()); err != nil {
+		return Delivery{}, fmt.Errorf("failed to replay webhook delivery: %w", err)
+	}
+
+	s.logger.Info("webhook delivery replayed", "delivery_id", id)
+	delivery, err := s.repo.GetDelivery(buildingID, id)
+	if err != nil {
+		return Delivery{}, apperrors.Lookup("webhook delivery", err)
+	}
+	return delivery, nil
+}
+
+func (s *WebhookService) ListEvents(buildingID int, params pagination.Params) (pagination.Page[outbox.Event], error) {
+	events, total, err := s.outboxRepo.List(buildingID, params)
+	if err != nil {
+		return pagination.Page[outbox.Event]{}, fmt.Errorf("failed to list events: %w", err)
+	}
+	return pagination.NewPage(events, total, params, outbox.EventSortKey), nil
+}
+
+// ReplayEvent delivers an event again to every active subscription that wants it now,
+// including subscriptions created after the event
+func (s *WebhookService) ReplayEvent(buildingID int, eventID int) (outbox.Event, error) {
+	event, err := s.outboxRepo.GetByID(eventID)
+	if err != nil {
+		return outbox.Event{}, apperrors.Lookup("event", err)
+	}
+	if event.BuildingID != buildingID {
+		return outbox.Event{}, apperrors.NotFound("event")
+	}
+
+	subscriptions, err := s.repo.ListSubscriptions(buildingID)
+	if err != nil {
+		return outbox.Event{}, fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+	subscriptionIDs := matchingSubscriptions(subscriptions, event.EventType)
+	if len(subscriptionIDs) == 0 {
+		return outbox.Event{}, apperrors.Rulef("No active webhook subscription receives %s events", event.EventType)
+	}
+
+	if err := s.repo.ReplayEvent(event, subscriptionIDs, time.Now()); err != nil {
+		return outbox.Event{}, fmt.Errorf("failed to replay event: %w", err)
+	}
+
+	s.logger.Info("event replayed", "event_id", event.ID, "event_type", event.EventType, "subscriptions", len(subscriptionIDs))
+	return s.outboxRepo.GetByID(eventID)
+}
+
+// matchingSubscriptions returns the ids of active subscriptions that want eventType
+func matchingSubscriptions(subscriptions []Subscription, eventType string) []int {
+	ids := []int{}
+	for _, subscription := range subscriptions {
+		if subscription.Status == StatusActive && subscription.Wants(eventType) {
+			ids = append(ids, subscription.ID)
+		}
+	}
+	return ids
+}
+
+func newSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
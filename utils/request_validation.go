@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -12,15 +14,63 @@ func MsgForTag(tag string) string {
 		return "This field is required"
 	case "email":
 		return "Invalid email"
+	case "numeric", "number":
+		return "Must be a number"
+	case "datetime":
+		return "Invalid date"
 	}
-	return ""
+	return "Invalid value"
+}
+
+// MsgForFieldError is MsgForTag with the tag's parameter, e.g. "Must be at least 1"
+func MsgForFieldError(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "min", "gte":
+		return fmt.Sprintf("Must be at least %s", fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("Must be at most %s", fe.Param())
+	case "gt":
+		return fmt.Sprintf("Must be greater than %s", fe.Param())
+	case "lt":
+		return fmt.Sprintf("Must be less than %s", fe.Param())
+	case "len":
+		return fmt.Sprintf("Must have length %s", fe.Param())
+	case "oneof":
+		return "Must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "datetime":
+		return fmt.Sprintf("Must be a date in the format %s", fe.Param())
+	}
+	return MsgForTag(fe.Tag())
+}
+
+// UseJSONFieldNames makes validation errors report fields by their json tag
+func UseJSONFieldNames(v *validator.Validate) {
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
+
+// FieldPath is the path to the field from the request body root, e.g. "items[0].qty"
+func FieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
 }
 
 func ValidateRequestBody(err error, ve validator.ValidationErrors) map[string]string {
 	
 	out := make(map[string]string)
 	for _, fe := range ve {
-		out[strings.ToLower(fe.Field())] = MsgForTag(fe.Tag())
+		out[FieldPath(fe)] = MsgForFieldError(fe)
 	}
 
 	return out